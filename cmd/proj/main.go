@@ -1,24 +1,56 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/accessible"
+	"github.com/s33g/proj/internal/actions"
 	"github.com/s33g/proj/internal/app"
+	"github.com/s33g/proj/internal/audit"
+	"github.com/s33g/proj/internal/bulk"
 	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/hooks"
+	"github.com/s33g/proj/internal/importer"
 	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/protocol"
+	"github.com/s33g/proj/internal/report"
+	"github.com/s33g/proj/internal/scaffold"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui/views"
+	"github.com/s33g/proj/internal/wsl"
 )
 
 // version is set at build time via -ldflags
 var version = "dev"
 
+// accessibleMode is set by --accessible, which may come anywhere on the
+// command line before a --, and on dumb terminals
+var accessibleMode bool
+
+// isAccessible reports whether proj asks plain sequential questions
+// instead of showing full-screen views
+func isAccessible(cfg *config.Config) bool {
+	return accessibleMode || cfg.Display.Accessible
+}
+
 func main() {
+	os.Args, accessibleMode = takeAccessible(os.Args)
+	if os.Getenv("TERM") == "dumb" {
+		accessibleMode = true
+	}
+
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "--version", "-v":
@@ -45,57 +77,192 @@ func main() {
 
 		case "--set-path":
 			if len(os.Args) < 3 {
-				fmt.Fprintln(os.Stderr, "Error: --set-path requires a path argument")
-				os.Exit(1)
+				exit(0, usageErrorf("--set-path requires a path argument"))
+			}
+			exit(0, setPath(os.Args[2]))
+			return
+
+		case "--import":
+			if len(os.Args) < 3 {
+				exit(0, usageErrorf("--import requires a source (ghq, vscode or projectile)"))
+			}
+			exit(0, importProjects(os.Args[2], os.Args[3:]))
+			return
+
+		case "--clone":
+			if len(os.Args) < 3 {
+				exit(0, usageErrorf("--clone requires a repository URL"))
 			}
-			if err := setPath(os.Args[2]); err != nil {
+			exit(0, cloneProject(os.Args[2]))
+			return
+
+		case "-":
+			if err := jumpBack(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 			return
 
 		case "--list", "-l":
-			if err := listProjects(); err != nil {
+			exit(0, listProjects(os.Args[2:]))
+			return
+
+		case "each":
+			exit(runEach(os.Args[2:]))
+			return
+
+		case "run":
+			exit(runAction(os.Args[2:]))
+			return
+
+		case "--report":
+			exit(0, runReport(os.Args[2:]))
+			return
+
+		case "--audit":
+			exit(0, showAudit(os.Args[2:]))
+			return
+
+		case "--clean-all":
+			exit(0, cleanAll(os.Args[2:]))
+			return
+
+		case "plugin":
+			exit(0, pluginCommand(os.Args[2:]))
+			return
+
+		case "--register-url-handler":
+			if err := registerURLHandler(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 			return
 
 		default:
-			// Check if it's a project name
-			if !strings.HasPrefix(os.Args[1], "-") {
-				if err := jumpToProject(os.Args[1]); err != nil {
+			// Check if it's a proj:// URL (e.g. clicked in a browser or docs)
+			if protocol.IsURL(os.Args[1]) {
+				if err := openURL(os.Args[1]); err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 					os.Exit(1)
 				}
 				return
 			}
+
+			// Check if it's a project name
+			if !strings.HasPrefix(os.Args[1], "-") {
+				exit(openProject(os.Args[1], os.Args[2:]))
+				return
+			}
 			fmt.Fprintf(os.Stderr, "Unknown option: %s\n", os.Args[1])
 			printHelp()
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
 	}
 
 	// Run the TUI
-	if err := runTUI(); err != nil {
+	if err := runTUI("", "", false); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// takeAccessible removes --accessible from args, reporting whether it was
+// there. What follows a -- is a command of the user's (proj each), so it's
+// left as it is.
+func takeAccessible(args []string) ([]string, bool) {
+	kept := make([]string, 0, len(args))
+	found := false
+	for i, arg := range args {
+		if arg == "--" {
+			return append(kept, args[i:]...), found
+		}
+		if arg == "--accessible" {
+			found = true
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return kept, found
+}
+
 func printHelp() {
 	fmt.Println(`proj - TUI Project Navigator
 
 Usage:
   proj                    Launch TUI
   proj <project-name>     Jump directly to project
-  proj --list             List all projects (non-interactive)
+  proj -                  Jump back to the previously opened project
+  proj <path>             Jump to the project containing a directory, e.g.
+                          proj . from inside a project (works with --menu
+                          and --action too)
+  proj <name> [--first | --interactive]
+                          When the name matches several projects, take the
+                          first or pick one; by default a picker opens on a
+                          terminal and the candidates are listed otherwise
+  proj <name> --menu [--action <id>]
+                          Open the TUI in the project's action menu,
+                          running the action there if given
+  proj <name> --action <id> [--dry-run] [--yes] [--json]
+                          Run one of the project's actions without the TUI
+                          (like proj run)
+  proj --accessible       Ask plain questions one at a time instead of
+                          showing the full-screen TUI, for screen readers
+                          and dumb terminals (works with any command)
+  proj --list [--pinned] [--archived] [--json]
+                          List all projects, or only pinned ones; archived
+                          projects are only listed with --archived
+                          (non-interactive)
   proj --init             Initialize/reset configuration
   proj --config           Open config in $EDITOR
   proj --set-path <path>  Set projects directory
+  proj --clone <url>      Clone a repository into the projects directory
+                          (host/owner/repo with "layout": "ghq")
+  proj --import <ghq|vscode|projectile> [files...]
+                          Add the projects another tool knows about; files
+                          are .code-workspace or projectile bookmark files
+                          to read instead of the tool's defaults
+  proj each [--filter <query>] [-j N] [--timeout 5m] [--force] [--json] -- <command>
+                          Run a command in every matching project, e.g.
+                          proj each --filter lang:go -- go vet ./...
+                          Projects unchanged since the command last
+                          succeeded in them are skipped unless --force
+  proj run <project> <action-id> [--dry-run] [--yes] [--json]
+                          Run one of a project's actions, e.g. run-tests;
+                          --dry-run shows the command, directory and
+                          environment without running it; --yes skips the
+                          confirmation for commands that would modify a
+                          protected branch; --json prints the result as JSON
+  proj --report stale [--days N] [-i]
+                          List projects without commits or changes in N days
+                          (default 180); -i opens an interactive archive/tag view
+  proj --report usage [--days N]
+                          Chart the most used projects, actions and editors
+                          in the last N days (default 30)
+  proj --audit [--days N] [--project <name>] [--json]
+                          Show the commands recorded in the audit log
+                          (audit.enabled in the config), optionally only
+                          those of the last N days or of one project
+  proj --clean-all [--filter <query>] [--dry-run]
+                          Show the space build artifacts (node_modules,
+                          target, .venv, ...) take up in each project,
+                          largest first, and pick projects to clean;
+                          --dry-run only prints the report
+  proj plugin scaffold <name> [--dir <plugins-dir>]
+                          Generate a Go plugin skeleton (RPC loop, manifest,
+                          Makefile, tests) in ~/.config/proj/plugins/<name>
+  proj proj://open?name=X Open project from a proj:// URL
+  proj --register-url-handler
+                          Register proj as the proj:// URL handler
   proj --version          Show version
   proj --help             Show help
 
+Exit codes:
+  0  Success
+  1  The action or command failed (run exits with the code of the
+     command its action ran), or another error
+  2  Unknown option or missing argument
+  3  No such project or action
+
 Keyboard shortcuts (in TUI):
   Enter   Select item
   /       Search/filter
@@ -216,7 +383,85 @@ func setPath(path string) error {
 	return nil
 }
 
-func listProjects() error {
+// importProjects adds the roots and projects another tool knows about to the config
+func importProjects(source string, files []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+
+	found, err := importer.Import(source, files)
+	if err != nil {
+		return err
+	}
+
+	added := importer.Merge(cfg, found)
+	if added.Empty() {
+		fmt.Printf("Nothing new to import from %s\n", source)
+		return nil
+	}
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	for _, root := range added.Roots {
+		fmt.Printf("  + root     %s\n", root)
+	}
+	for _, p := range added.Projects {
+		fmt.Printf("  + project  %s\n", p)
+	}
+	fmt.Printf("Imported %d root(s) and %d project(s) from %s\n", len(added.Roots), len(added.Projects), source)
+	if len(added.Roots) > 0 && !strings.EqualFold(cfg.Layout, config.LayoutGHQ) {
+		fmt.Println(`Tip: set "layout": "ghq" in the config to list ghq repositories as owner/repo`)
+	}
+	return nil
+}
+
+// cloneProject clones a repository into the repos path, following the
+// configured layout, and changes to it
+func cloneProject(url string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+
+	remote, err := git.ParseRemote(url)
+	if err != nil {
+		return err
+	}
+	dest := project.ClonePath(cfg, remote)
+
+	if _, err := os.Stat(dest); err == nil {
+		fmt.Fprintf(os.Stderr, "Already cloned: %s\n", dest)
+	} else {
+		fmt.Fprintf(os.Stderr, "Cloning %s into %s...\n", remote.URL, dest)
+		if out, err := git.Clone(remote.URL, dest); err != nil {
+			return fmt.Errorf("git clone failed: %v\n%s", err, out)
+		}
+	}
+
+	if os.Getenv("PROJ_CD_FILE") == "" {
+		fmt.Println(dest)
+		return nil
+	}
+	return finishExit(dest, nil)
+}
+
+func listProjects(args []string) error {
+	jsonOut, pinnedOnly, archived := false, false, false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			jsonOut = true
+		case "--pinned":
+			pinnedOnly = true
+		case "--archived":
+			archived = true
+		default:
+			return usageErrorf("unknown option for --list: %s", arg)
+		}
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
@@ -228,6 +473,27 @@ func listProjects() error {
 		return fmt.Errorf("failed to scan projects: %w", err)
 	}
 
+	// Report skipped entries on stderr so stdout stays scriptable
+	for _, d := range scanner.Diagnostics() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", d.Message)
+	}
+
+	if st, err := state.Load(); err == nil {
+		st.Annotate(projects)
+	}
+	// Archived projects are only listed with --archived, and then alone
+	projects = slices.DeleteFunc(projects, func(p *project.Project) bool {
+		return p.Archived != archived || pinnedOnly && !p.Pinned
+	})
+
+	if jsonOut {
+		list := make([]projectJSON, len(projects))
+		for i, p := range projects {
+			list[i] = newProjectJSON(p)
+		}
+		return printJSON(list)
+	}
+
 	if len(projects) == 0 {
 		fmt.Println("No projects found")
 		return nil
@@ -239,33 +505,223 @@ func listProjects() error {
 	return nil
 }
 
-func jumpToProject(name string) error {
+// Ways to settle a name that matches several projects
+const (
+	pickAuto        = iota // Picker when stdout is a terminal, otherwise list the candidates
+	pickFirst              // Take the first candidate
+	pickInteractive        // Always offer the picker
+)
+
+// resolveProject loads the config and scans for the project name refers to,
+// settling ambiguous names as pick says
+func resolveProject(name string, pick int) (*config.Config, *project.Project, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	scanner := project.NewScanner(cfg)
+	projects, err := scanner.Scan(cfg.ReposPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan projects: %w", err)
+	}
+
+	if isPath(name) {
+		match, err := ownerOf(projects, name)
+		return cfg, match, err
+	}
+
+	// Frecency orders the candidates
+	if st, err := state.Load(); err == nil {
+		st.Annotate(projects)
+	}
+	// Names can contain slashes too (ghq's owner/repo, nested groups), so a
+	// relative path is only tried when no project matches
+	candidates := project.Candidates(projects, name)
+	switch {
+	case len(candidates) == 0 && isDir(name):
+		match, err := ownerOf(projects, name)
+		return cfg, match, err
+	case len(candidates) == 0:
+		return nil, nil, notFoundErrorf("project not found: %s", name)
+	case len(candidates) == 1 || pick == pickFirst:
+		return cfg, candidates[0], nil
+	case pick == pickInteractive || isTerminal(os.Stdout):
+		match, err := pickProject(name, candidates, isAccessible(cfg))
+		return cfg, match, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d projects:\n", name, len(candidates))
+	for _, p := range candidates {
+		fmt.Fprintf(&b, "  %s  %s\n", p.Name, p.Path)
+	}
+	b.WriteString("Use a more specific name or path, --first or --interactive")
+	return nil, nil, fmt.Errorf("%s", b.String())
+}
+
+// isPath reports whether name is written as a path rather than a project
+// name, such as ".", "../api" or "~/code/api/src"
+func isPath(name string) bool {
+	if name == "." || name == ".." || strings.HasPrefix(name, "~") || filepath.IsAbs(name) {
+		return true
+	}
+	for _, prefix := range []string{"./", "../", "." + string(filepath.Separator), ".." + string(filepath.Separator)} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDir reports whether name is an existing directory, relative to the
+// working directory
+func isDir(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
+
+// ownerOf resolves path, relative to the working directory, to the project
+// containing it. The path doesn't have to exist, as long as it falls inside
+// a known project.
+func ownerOf(projects []*project.Project, path string) (*project.Project, error) {
+	abs, err := filepath.Abs(config.ExpandPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	if match := project.Containing(projects, abs); match != nil {
+		return match, nil
+	}
+	return nil, fmt.Errorf("%s is not inside a known project", abs)
+}
+
+// pickProject lets the user choose between candidates. The picker draws on
+// stderr so stdout stays free for the path, as in cd "$(proj api -i)".
+func pickProject(name string, candidates []*project.Project, plain bool) (*project.Project, error) {
+	if plain {
+		options := make([]string, len(candidates))
+		for i, p := range candidates {
+			options[i] = p.Name + ", " + p.Path
+		}
+		i, err := accessible.NewPrompter(os.Stdin, os.Stderr).Choose(fmt.Sprintf("%q matches %d projects. Which one?", name, len(candidates)), options, false)
+		if err != nil {
+			return nil, fmt.Errorf("cancelled")
+		}
+		return candidates[i], nil
+	}
+	final, err := tea.NewProgram(app.NewPickerModel(name, candidates), tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run picker: %w", err)
+	}
+	chosen := final.(app.PickerModel).Chosen()
+	if chosen == nil {
+		return nil, fmt.Errorf("cancelled")
+	}
+	return chosen, nil
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// openProject handles proj <name> [flags]: without flags it jumps to the
+// project, --menu opens the TUI in its action menu and --action runs one of
+// its actions headlessly (or in the menu, with --menu). --first and
+// --interactive control what happens when name matches several projects.
+// It returns a headless action's exit code.
+func openProject(name string, args []string) (int, error) {
+	menu := false
+	actionID := ""
+	pick := pickAuto
+	var runArgs []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--menu", "-m":
+			menu = true
+		case "--action", "-a":
+			if i+1 >= len(args) {
+				return 0, usageErrorf("--action requires an action ID")
+			}
+			actionID = args[i+1]
+			i++
+		case "--first":
+			pick = pickFirst
+		case "--interactive", "-i":
+			pick = pickInteractive
+		case "--dry-run", "-n", "--yes", "-y", "--json":
+			runArgs = append(runArgs, args[i])
+		default:
+			return 0, usageErrorf("unknown option for %s: %s", name, args[i])
+		}
+	}
+	if len(runArgs) > 0 && (actionID == "" || menu) {
+		return 0, usageErrorf("%s only applies to a headless --action", runArgs[0])
+	}
+
+	cfg, match, err := resolveProject(name, pick)
+	if err != nil {
+		return 0, err
+	}
+	// Pass the path on so the same project is found again
+	switch {
+	case menu:
+		return 0, runTUI(match.Path, actionID, false)
+	case actionID != "":
+		return runAction(append([]string{match.Path, actionID}, runArgs...))
+	}
+	return 0, jumpTo(cfg, match)
+}
+
+// jumpBack changes to the most recently opened project other than the one
+// the shell is in, so repeating it switches between two projects
+func jumpBack() error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	st, err := state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
 	scanner := project.NewScanner(cfg)
 	projects, err := scanner.Scan(cfg.ReposPath)
 	if err != nil {
 		return fmt.Errorf("failed to scan projects: %w", err)
 	}
-
-	// Find matching project (case-insensitive partial match)
-	var match *project.Project
-	nameLower := strings.ToLower(name)
+	byPath := make(map[string]*project.Project, len(projects))
 	for _, p := range projects {
-		if strings.ToLower(p.Name) == nameLower {
-			match = p
-			break
+		byPath[wsl.NormalizePath(p.Path)] = p
+	}
+
+	cwd, _ := os.Getwd()
+	cwd = wsl.NormalizePath(cwd)
+	for _, path := range st.RecentlyOpened() {
+		p, ok := byPath[path]
+		if !ok || p.IsGroup {
+			continue
 		}
-		if strings.Contains(strings.ToLower(p.Name), nameLower) && match == nil {
-			match = p
+		if cwd == path || strings.HasPrefix(cwd, path+string(filepath.Separator)) {
+			continue
 		}
+		return jumpTo(cfg, p)
 	}
+	return fmt.Errorf("no previous project to switch to")
+}
 
-	if match == nil {
-		return fmt.Errorf("project not found: %s", name)
+// jumpTo records a jump to a project and changes to it (or prints its path
+// without the shell integration)
+func jumpTo(cfg *config.Config, match *project.Project) error {
+	// Remember the jump so the TUI can offer to reopen it in the terminal
+	if st, err := state.Load(); err == nil {
+		st.RecordOpen(match.Path, state.OpenedInTerminal)
+		_ = st.Save()
+	}
+	if err := hooks.Run(cfg, hooks.PostOpen, match, "PROJ_EDITOR="+state.OpenedInTerminal); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
 
 	// Write path to cd file if set
@@ -282,7 +738,511 @@ func jumpToProject(name string) error {
 	return nil
 }
 
-func runTUI() error {
+func runReport(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("--report requires a report name (available: stale, usage)")
+	}
+
+	switch args[0] {
+	case "stale":
+		return staleReport(args[1:])
+	case "usage":
+		return usageReport(args[1:])
+	default:
+		return usageErrorf("unknown report: %s (available: stale, usage)", args[0])
+	}
+}
+
+func staleReport(args []string) error {
+	days := report.DefaultStaleDays
+	interactive := false
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--days":
+			if i+1 >= len(args) {
+				return usageErrorf("--days requires a number")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return usageErrorf("invalid --days value: %s", args[i+1])
+			}
+			days = n
+			i++
+		case "-i", "--interactive":
+			interactive = true
+		default:
+			return usageErrorf("unknown option for stale report: %s", args[i])
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+
+	scanner := project.NewScanner(cfg)
+	projects, err := scanner.Scan(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan projects: %w", err)
+	}
+
+	st, err := state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	st.Annotate(projects)
+
+	stale := report.Stale(projects, days, time.Now())
+
+	if interactive && isAccessible(cfg) {
+		fmt.Fprintln(os.Stderr, "The interactive report isn't available in accessible mode; listing the projects instead.")
+		interactive = false
+	}
+	if interactive {
+		p := tea.NewProgram(app.NewStaleReportModel(stale, days, st), tea.WithAltScreen(), tea.WithFPS(cfg.Display.FPS()))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("failed to run TUI: %w", err)
+		}
+		return nil
+	}
+
+	if len(stale) == 0 {
+		fmt.Printf("No projects inactive for %d days\n", days)
+		return nil
+	}
+
+	now := time.Now()
+	for _, s := range stale {
+		fmt.Printf("%-30s %5dd  %-8s  %s\n", s.Project.Name, report.DaysSince(s.LastActivity, now), s.Source, s.Project.Path)
+	}
+	return nil
+}
+
+// usageReport prints the most used projects, actions and editors as bar charts
+func usageReport(args []string) error {
+	days := report.DefaultUsageDays
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--days":
+			if i+1 >= len(args) {
+				return usageErrorf("--days requires a number")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return usageErrorf("invalid --days value: %s", args[i+1])
+			}
+			days = n
+			i++
+		default:
+			return usageErrorf("unknown option for usage report: %s", args[i])
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+
+	st, err := state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	// Scanning only names the projects, so a failure falls back to directory names
+	scanner := project.NewScanner(cfg)
+	projects, _ := scanner.Scan(cfg.ReposPath)
+
+	usage := report.Usage(st, projects, days, time.Now())
+	if usage.Empty() {
+		fmt.Printf("Nothing opened or run in the last %d days\n", days)
+		return nil
+	}
+
+	fmt.Printf("Usage in the last %d days\n\n%s\n", days, usage.Render(report.BarWidth))
+	return nil
+}
+
+// showAudit prints the commands recorded in the audit log, oldest first
+func showAudit(args []string) error {
+	days, project, jsonOut := 0, "", false
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--days":
+			if i+1 >= len(args) {
+				return usageErrorf("--days requires a number")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return usageErrorf("invalid --days value: %s", args[i+1])
+			}
+			days = n
+			i++
+		case "--project", "-p":
+			if i+1 >= len(args) {
+				return usageErrorf("--project requires a project name")
+			}
+			project = args[i+1]
+			i++
+		case "--json":
+			jsonOut = true
+		default:
+			return usageErrorf("unknown option for audit: %s", args[i])
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+	path, err := audit.Path(cfg)
+	if err != nil {
+		return err
+	}
+	entries, err := audit.Read(path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+	entries = audit.Filter(entries, since, project)
+
+	if jsonOut {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		return printJSON(entries)
+	}
+	if len(entries) == 0 {
+		if !cfg.Audit.Enabled {
+			fmt.Println("Nothing recorded; the audit log is off (set audit.enabled in the config to turn it on)")
+		} else {
+			fmt.Println("Nothing recorded")
+		}
+		return nil
+	}
+	fmt.Print(audit.Format(entries))
+	return nil
+}
+
+// cleanAll reports reclaimable space across projects and lets the user pick
+// which to clean
+func cleanAll(args []string) error {
+	query := ""
+	dryRun := false
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--filter", "-f":
+			if i+1 >= len(args) {
+				return usageErrorf("--filter requires a query")
+			}
+			query = args[i+1]
+			i++
+		case "--dry-run", "-n":
+			dryRun = true
+		default:
+			return usageErrorf("unknown option for --clean-all: %s", args[i])
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+
+	scanner := project.NewScanner(cfg)
+	projects, err := scanner.Scan(cfg.ReposPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan projects: %w", err)
+	}
+	if st, err := state.Load(); err == nil {
+		st.Annotate(projects)
+	}
+	matched, err := project.Match(projects, query)
+	if err != nil {
+		return err
+	}
+
+	targets := make([]*project.Project, 0, len(matched))
+	for _, p := range matched {
+		if !p.IsGroup {
+			targets = append(targets, p)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Measuring build artifacts in %d project(s)...\n", len(targets))
+	items := actions.NewExecutor(cfg).Reclaimable(targets)
+
+	if !dryRun && isAccessible(cfg) {
+		fmt.Fprintln(os.Stderr, "Picking projects to clean isn't available in accessible mode; listing them instead. Clean a project with proj run <project> clean.")
+		dryRun = true
+	}
+	if !dryRun {
+		p := tea.NewProgram(app.NewCleanAllModel(items, cfg), tea.WithAltScreen(), tea.WithFPS(cfg.Display.FPS()))
+		if _, err := p.Run(); err != nil {
+			return fmt.Errorf("failed to run TUI: %w", err)
+		}
+		return nil
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No build artifacts to clean")
+		return nil
+	}
+	var total int64
+	for _, r := range items {
+		fmt.Printf("%-30s %10s  %s\n", r.Project.Name, actions.FormatBytes(r.Size), strings.Join(r.Paths(), ", "))
+		total += r.Size
+	}
+	fmt.Printf("\n%s reclaimable in %d project(s); run without --dry-run to pick which to clean\n", actions.FormatBytes(total), len(items))
+	return nil
+}
+
+// runEach runs a command across matching projects and returns exitFailed
+// if it failed in any
+func runEach(args []string) (int, error) {
+	query := ""
+	concurrency := 0 // bulk.concurrency, or one per CPU
+	timeout := ""    // bulk.timeout
+	force := false
+	jsonOut := false
+	var command []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--filter", "-f":
+			if i+1 >= len(args) {
+				return 0, usageErrorf("--filter requires a query")
+			}
+			query = args[i+1]
+			i++
+		case "-j", "--jobs":
+			if i+1 >= len(args) {
+				return 0, usageErrorf("-j requires a number")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return 0, usageErrorf("invalid -j value: %s", args[i+1])
+			}
+			concurrency = n
+			i++
+		case "--timeout":
+			if i+1 >= len(args) {
+				return 0, usageErrorf("--timeout requires a duration, e.g. 5m")
+			}
+			timeout = args[i+1]
+			i++
+		case "--force":
+			force = true
+		case "--json":
+			jsonOut = true
+		case "--":
+			command = args[i+1:]
+			i = len(args)
+		default:
+			return 0, usageErrorf("unknown option for each: %s (put the command after --)", args[i])
+		}
+	}
+
+	if len(command) == 0 {
+		return 0, usageErrorf("no command given (usage: proj each [--filter <query>] -- <command>)")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err)
+	}
+	if concurrency == 0 {
+		concurrency = cfg.Bulk.Concurrency
+	}
+	if timeout != "" {
+		cfg.Bulk.Timeout = timeout
+	}
+	perProject, err := cfg.Bulk.TimeoutDuration()
+	if err != nil {
+		return 0, err
+	}
+
+	scanner := project.NewScanner(cfg)
+	projects, err := scanner.Scan(cfg.ReposPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan projects: %w", err)
+	}
+	st, err := state.Load()
+	if err == nil {
+		st.Annotate(projects)
+	}
+
+	matched, err := project.Match(projects, query)
+	if err != nil {
+		return 0, err
+	}
+	targets := make([]*project.Project, 0, len(matched))
+	for _, p := range matched {
+		if !p.IsGroup {
+			targets = append(targets, p)
+		}
+	}
+	if len(targets) == 0 {
+		return 0, notFoundErrorf("no projects match %q", query)
+	}
+
+	fmt.Fprintf(os.Stderr, "Running '%s' in %d project(s)...\n\n", strings.Join(command, " "), len(targets))
+
+	// Skip projects that haven't changed since the command last succeeded there
+	key := actions.QuoteArgs(command)
+	paths := make([]string, len(targets))
+	for i, p := range targets {
+		paths[i] = p.Path
+	}
+	last := st.BulkFingerprints(key, paths)
+	if force {
+		last = nil
+	}
+	skipper := bulk.NewSkipper(last)
+
+	// Print each project's output as soon as it finishes so output isn't interleaved
+	start := time.Now()
+	outcomes := bulk.Schedule(targets, skipper.Wrap(bulk.Command(command, actions.NewExecutor(cfg).CommandContext)), bulk.Options{
+		Concurrency: concurrency,
+		Timeout:     perProject,
+		OnDone: func(o bulk.Outcome) {
+			if err := audit.RecordOutcome(cfg, key, o); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning:", err)
+			}
+			if jsonOut {
+				return
+			}
+			fmt.Println(bulk.Header(o))
+			if output := strings.TrimRight(o.Output, "\n"); output != "" {
+				for _, line := range strings.Split(output, "\n") {
+					fmt.Println("  │ " + line)
+				}
+			}
+			fmt.Println()
+		},
+	})
+	elapsed := time.Since(start)
+
+	st.RecordBulkRuns(key, skipper.Succeeded(outcomes))
+	if err := st.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", err)
+	}
+
+	_, failed := bulk.Summarize(outcomes)
+	code := 0
+	if failed > 0 {
+		code = exitFailed
+	}
+	if jsonOut {
+		return code, printJSON(newEachJSON(strings.Join(command, " "), outcomes, elapsed))
+	}
+
+	if table := bulk.Durations(outcomes); table != "" {
+		fmt.Println(table)
+	}
+	fmt.Printf("%s; %s\n", bulk.Totals(outcomes), bulk.Rate(len(outcomes)-bulk.Skipped(outcomes), elapsed))
+	if bulk.Skipped(outcomes) > 0 {
+		fmt.Println("  (run with --force to include unchanged projects)")
+	}
+	for _, o := range outcomes {
+		if !o.Success {
+			fmt.Printf("  ✗ %s\n", o.Project.Name)
+		}
+	}
+
+	return code, nil
+}
+
+// pluginCommand runs a plugin subcommand; scaffold is the only one so far
+func pluginCommand(args []string) error {
+	if len(args) == 0 || args[0] != "scaffold" {
+		return usageErrorf("usage: proj plugin scaffold <name> [--dir <plugins-dir>]")
+	}
+
+	name, pluginsDir := "", ""
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--dir":
+			if i+1 >= len(args) {
+				return usageErrorf("--dir requires a directory")
+			}
+			i++
+			pluginsDir = config.ExpandPath(args[i])
+		default:
+			if name != "" || strings.HasPrefix(args[i], "-") {
+				return usageErrorf("usage: proj plugin scaffold <name> [--dir <plugins-dir>]")
+			}
+			name = args[i]
+		}
+	}
+	if name == "" {
+		return usageErrorf("usage: proj plugin scaffold <name> [--dir <plugins-dir>]")
+	}
+	if pluginsDir == "" {
+		configDir, err := config.ConfigDir()
+		if err != nil {
+			return err
+		}
+		pluginsDir = filepath.Join(configDir, "plugins")
+	}
+
+	files, err := scaffold.Plugin(name)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join(pluginsDir, name)
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	for _, f := range files {
+		if err := scaffold.Write(dest, f); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Created plugin %s in %s\n\n", name, dest)
+	fmt.Println("Next steps:")
+	fmt.Printf("  cd %s && make build test\n", dest)
+	fmt.Printf("  Add \"%s\" to plugins.enabled in your config (proj --config)\n", name)
+	fmt.Println("  See docs/PLUGINS.md for the methods a plugin can implement")
+	return nil
+}
+
+func registerURLHandler() error {
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate proj binary: %w", err)
+	}
+
+	message, err := protocol.Register(binary)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(message)
+	fmt.Printf("Try it: xdg-open '%s'\n", protocol.URL("my-project", ""))
+	return nil
+}
+
+func openURL(raw string) error {
+	req, err := protocol.Parse(raw)
+	if err != nil {
+		return err
+	}
+	return runTUI(req.Project, req.Action, true)
+}
+
+// runTUI runs the TUI, optionally opening a project's action menu (and
+// running one of its actions) on startup. A start project from a proj://
+// link must match exactly, and its action is confirmed first.
+func runTUI(startProject, startAction string, fromURL bool) error {
 	cfg, err := config.Load()
 	if err != nil {
 		// If no config exists, run init flow
@@ -294,38 +1254,196 @@ func runTUI() error {
 		return nil
 	}
 
+	if isAccessible(cfg) {
+		run := accessible.Run
+		if fromURL {
+			run = accessible.RunURL
+		}
+		exit, err := run(cfg, startProject, startAction, os.Stdin, os.Stdout)
+		if err != nil {
+			return err
+		}
+		return finishExit(exit.CdPath, exit.ExecCmd)
+	}
+
 	model := app.New(cfg)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	if startProject != "" {
+		model = model.WithStartup(startProject, startAction)
+		if fromURL {
+			model = model.FromURL()
+		}
+	} else if cwd, err := os.Getwd(); err == nil {
+		model = model.WithStartDir(cwd)
+	}
+	// Mouse support makes the header statistics clickable
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithFPS(cfg.Display.FPS()))
 
 	finalModel, err := p.Run()
+	// Stop the plugin processes before proj exits or execs another command
+	model.Shutdown()
 	if err != nil {
 		return fmt.Errorf("failed to run TUI: %w", err)
 	}
 
 	// Handle post-exit actions
 	if m, ok := finalModel.(app.Model); ok {
-		// Handle cd path
-		cdPath := m.GetCdPath()
-		if cdPath != "" {
-			cdFile := os.Getenv("PROJ_CD_FILE")
-			if cdFile != "" {
-				if err := os.WriteFile(cdFile, []byte(cdPath), 0644); err != nil {
-					return fmt.Errorf("failed to write cd file: %w", err)
-				}
-			}
-		}
+		return finishExit(m.GetCdPath(), m.GetExecCmd())
+	}
+
+	return nil
+}
 
-		// Handle exec command
-		execCmd := m.GetExecCmd()
-		if len(execCmd) > 0 {
-			binary, err := exec.LookPath(execCmd[0])
-			if err != nil {
-				return fmt.Errorf("command not found: %s", execCmd[0])
+// finishExit writes the cd file for the shell integration and replaces proj
+// with execCmd, either of which may be empty
+func finishExit(cdPath string, execCmd []string) error {
+	if cdPath != "" {
+		cdFile := os.Getenv("PROJ_CD_FILE")
+		if cdFile != "" {
+			if err := os.WriteFile(cdFile, []byte(cdPath), 0644); err != nil {
+				return fmt.Errorf("failed to write cd file: %w", err)
 			}
-			// Replace current process with the command
-			return syscall.Exec(binary, execCmd, os.Environ())
 		}
 	}
 
+	if len(execCmd) > 0 {
+		binary, err := exec.LookPath(execCmd[0])
+		if err != nil {
+			return fmt.Errorf("command not found: %s", execCmd[0])
+		}
+		// Replace current process with the command
+		return syscall.Exec(binary, execCmd, os.Environ())
+	}
 	return nil
 }
+
+// runAction runs a single action of a project without the TUI, or with
+// --dry-run describes what it would do. It returns the action's exit code;
+// with --json the result is printed as JSON, errors included.
+func runAction(args []string) (int, error) {
+	dryRun := false
+	yes := false
+	jsonOut := false
+	var positional []string
+	for _, arg := range args {
+		switch {
+		case arg == "--dry-run" || arg == "-n":
+			dryRun = true
+		case arg == "--yes" || arg == "-y":
+			yes = true
+		case arg == "--json":
+			jsonOut = true
+		case strings.HasPrefix(arg, "-"):
+			return 0, usageErrorf("unknown option for run: %s", arg)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) != 2 {
+		return 0, usageErrorf("usage: proj run <project> <action-id> [--dry-run] [--yes] [--json]")
+	}
+	name, actionID := positional[0], positional[1]
+
+	out := actionJSON{Project: name, Action: actionID}
+	fail := func(err error) (int, error) {
+		if jsonOut {
+			out.ExitCode = exitCode(err)
+			out.Error = err.Error()
+			_ = printJSON(out)
+		}
+		return 0, err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fail(fmt.Errorf("failed to load config: %w (run 'proj --init' first)", err))
+	}
+
+	scanner := project.NewScanner(cfg)
+	projects, err := scanner.Scan(cfg.ReposPath)
+	if err != nil {
+		return fail(fmt.Errorf("failed to scan projects: %w", err))
+	}
+	if st, err := state.Load(); err == nil {
+		st.Annotate(projects)
+	}
+
+	proj := project.Find(projects, name)
+	if proj == nil || proj.IsGroup {
+		return fail(notFoundErrorf("project not found: %s", name))
+	}
+	out.Project, out.Path = proj.Name, proj.Path
+
+	action := views.FindAction(views.DefaultActions(proj, cfg.Actions.EnableGitOperations, cfg.Actions.EnableTestRunner), actionID)
+	if action == nil || action.IsSubmenu {
+		return fail(notFoundErrorf("action not available for %s: %s", proj.Name, actionID))
+	}
+	if cfg.Actions.IsDisabled(proj.Name, action.ID) {
+		return fail(notFoundErrorf("action %s is disabled for %s by actions.disabled in the config", action.ID, proj.Name))
+	}
+
+	executor := actions.NewExecutor(cfg)
+	if dryRun {
+		plan := executor.DryRun(action.ID, action.Command, proj)
+		if jsonOut {
+			out.Success, out.Output = true, plan.String()
+			return 0, printJSON(out)
+		}
+		fmt.Println(plan)
+		return 0, nil
+	}
+
+	// Commands that would modify a protected branch are confirmed first
+	if warning := executor.ProtectedBranchWarning(action.Command, proj); warning != "" && !yes {
+		fmt.Fprintf(os.Stderr, "Warning: %s in %s %s.\nRun anyway? [y/N] ", action.Label, proj.Name, warning)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+			return fail(fmt.Errorf("cancelled"))
+		}
+	}
+
+	if err := hooks.Run(cfg, hooks.PreAction, proj, "PROJ_ACTION="+action.ID); err != nil {
+		return fail(err)
+	}
+
+	start := time.Now()
+	var result actions.Result
+	if action.Command != "" {
+		result = executor.ExecuteCommand(action.Command, proj)
+	} else {
+		result = executor.Execute(action.ID, proj)
+	}
+	if result.Success && result.OpenedWith != "" {
+		if err := hooks.Run(cfg, hooks.PostOpen, proj, "PROJ_EDITOR="+result.OpenedWith); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	code := 0
+	if !result.Success {
+		code = exitFailed
+		if result.ExitCode > 0 {
+			code = result.ExitCode
+		}
+	}
+
+	// A script reading the JSON changes directory or runs the command itself
+	if jsonOut {
+		out.Success = result.Success
+		out.ExitCode = code
+		out.Duration = time.Since(start).Seconds()
+		out.Output = result.Message
+		out.CdPath = result.CdPath
+		out.Exec = result.ExecCmd
+		return code, printJSON(out)
+	}
+
+	if result.Message != "" {
+		fmt.Println(result.Message)
+	}
+	if result.Success {
+		if err := finishExit(result.CdPath, result.ExecCmd); err != nil {
+			return 0, err
+		}
+	}
+	return code, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTakeAccessible(t *testing.T) {
+	tests := []struct {
+		args       []string
+		want       []string
+		accessible bool
+	}{
+		{[]string{"proj", "--accessible"}, []string{"proj"}, true},
+		{[]string{"proj", "api", "--accessible"}, []string{"proj", "api"}, true},
+		{[]string{"proj", "--list"}, []string{"proj", "--list"}, false},
+		// The command after -- is passed on untouched
+		{[]string{"proj", "each", "--", "foo", "--accessible"}, []string{"proj", "each", "--", "foo", "--accessible"}, false},
+		{[]string{"proj", "--accessible", "each", "--", "foo", "--accessible"}, []string{"proj", "each", "--", "foo", "--accessible"}, true},
+	}
+	for _, tt := range tests {
+		got, accessible := takeAccessible(tt.args)
+		if !reflect.DeepEqual(got, tt.want) || accessible != tt.accessible {
+			t.Errorf("takeAccessible(%q) = %q, %v, want %q, %v", tt.args, got, accessible, tt.want, tt.accessible)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/s33g/proj/internal/bulk"
+	"github.com/s33g/proj/internal/project"
+)
+
+// Exit codes of the headless commands, so scripts can tell a failed action
+// from a mistyped one. proj run exits with the code of the command its
+// action ran when that command fails.
+const (
+	exitFailed   = 1 // The action or a command failed, or proj hit an error
+	exitUsage    = 2 // Unknown option or missing argument
+	exitNotFound = 3 // No such project or action
+)
+
+// exitError ends proj with a particular exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// usageErrorf reports a bad command line
+func usageErrorf(format string, a ...any) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, a...)}
+}
+
+// notFoundErrorf reports a project or action that doesn't exist
+func notFoundErrorf(format string, a ...any) error {
+	return &exitError{code: exitNotFound, err: fmt.Errorf(format, a...)}
+}
+
+// exitCode returns the exit code err ends proj with
+func exitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitFailed
+}
+
+// exit ends a headless command: with err's code after printing it, with
+// code when the command failed, or not at all when it succeeded
+func exit(code int, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
+
+// actionJSON is what proj run prints with --json
+type actionJSON struct {
+	Project  string   `json:"project,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Action   string   `json:"action"`
+	Success  bool     `json:"success"`
+	ExitCode int      `json:"exitCode"`
+	Duration float64  `json:"duration"` // Seconds
+	Output   string   `json:"output"`
+	CdPath   string   `json:"cdPath,omitempty"`
+	Exec     []string `json:"exec,omitempty"` // Command the action would hand the terminal to
+	Error    string   `json:"error,omitempty"`
+}
+
+// eachJSON is what proj each prints with --json
+type eachJSON struct {
+	Command  string            `json:"command"`
+	Success  bool              `json:"success"`
+	Duration float64           `json:"duration"` // Seconds
+	Results  []eachProjectJSON `json:"results"`
+}
+
+// eachProjectJSON is the outcome of proj each in one project
+type eachProjectJSON struct {
+	Project  string  `json:"project"`
+	Path     string  `json:"path"`
+	Success  bool    `json:"success"`
+	Skipped  bool    `json:"skipped,omitempty"`
+	TimedOut bool    `json:"timedOut,omitempty"`
+	Duration float64 `json:"duration"` // Seconds
+	Output   string  `json:"output"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// projectJSON is a project as proj --list --json prints it
+type projectJSON struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	Group     string   `json:"group,omitempty"`
+	IsGroup   bool     `json:"isGroup,omitempty"`
+	Language  string   `json:"language,omitempty"`
+	GitBranch string   `json:"gitBranch,omitempty"`
+	GitDirty  bool     `json:"gitDirty,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Pinned    bool     `json:"pinned,omitempty"`
+	Archived  bool     `json:"archived,omitempty"`
+}
+
+// newEachJSON summarizes proj each's outcomes
+func newEachJSON(command string, outcomes []bulk.Outcome, elapsed time.Duration) eachJSON {
+	out := eachJSON{Command: command, Success: true, Duration: elapsed.Seconds(), Results: []eachProjectJSON{}}
+	for _, o := range outcomes {
+		result := eachProjectJSON{
+			Project:  o.Project.Name,
+			Path:     o.Project.Path,
+			Success:  o.Success,
+			Skipped:  o.Skipped,
+			TimedOut: o.TimedOut,
+			Duration: o.Duration.Seconds(),
+			Output:   o.Output,
+		}
+		if o.Err != nil {
+			result.Error = o.Err.Error()
+		}
+		out.Success = out.Success && o.Success
+		out.Results = append(out.Results, result)
+	}
+	return out
+}
+
+// newProjectJSON copies what --list --json shows of p
+func newProjectJSON(p *project.Project) projectJSON {
+	return projectJSON{
+		Name:      p.Name,
+		Path:      p.Path,
+		Group:     p.ParentPath,
+		IsGroup:   p.IsGroup,
+		Language:  p.Language,
+		GitBranch: p.GitBranch,
+		GitDirty:  p.GitDirty,
+		Tags:      p.Tags,
+		Pinned:    p.Pinned,
+		Archived:  p.Archived,
+	}
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,132 @@
+// Package accessible is proj's screen-reader mode. Instead of the
+// full-screen TUI it asks plain questions one at a time, numbering the
+// choices and reading the answer from a line of input, without colours,
+// cursor movement or animation, so it works with screen readers and on
+// dumb terminals.
+package accessible
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Answers that leave a prompt instead of choosing
+var (
+	ErrQuit = errors.New("quit")
+	ErrBack = errors.New("back")
+)
+
+// Prompter asks questions on out and reads the answers from in
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewPrompter returns a Prompter reading in and writing out
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Say writes a line
+func (p *Prompter) Say(format string, a ...any) {
+	fmt.Fprintf(p.out, format+"\n", a...)
+}
+
+// readLine asks question and returns the trimmed answer; the end of the
+// input quits
+func (p *Prompter) readLine(question string) (string, error) {
+	fmt.Fprint(p.out, question)
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.out)
+		return "", ErrQuit
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Choose lists options numbered from 1 and returns the index of the one
+// picked by its number. Other text narrows the list to the options
+// containing it, choosing the option when only one is left. q quits, and
+// b goes back when canGoBack is set.
+func (p *Prompter) Choose(question string, options []string, canGoBack bool) (int, error) {
+	shown := allIndexes(len(options))
+	for {
+		p.Say("%s", question)
+		for _, i := range shown {
+			p.Say("%d. %s", i+1, options[i])
+		}
+		hint := "Type a number, or text to narrow the list, or q to quit"
+		if canGoBack {
+			hint = "Type a number, or text to narrow the list, b to go back or q to quit"
+		}
+		answer, err := p.readLine(hint + ": ")
+		if err != nil {
+			return -1, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			continue
+		case "q", "quit":
+			return -1, ErrQuit
+		case "b", "back":
+			if canGoBack {
+				return -1, ErrBack
+			}
+		}
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n >= 1 && n <= len(options) {
+				return n - 1, nil
+			}
+			p.Say("There is no option %d.", n)
+			continue
+		}
+
+		matches := filter(options, answer)
+		switch len(matches) {
+		case 0:
+			p.Say("Nothing matches %q.", answer)
+			shown = allIndexes(len(options))
+		case 1:
+			p.Say("Chose %s.", options[matches[0]])
+			return matches[0], nil
+		default:
+			p.Say("%d options match %q.", len(matches), answer)
+			shown = matches
+		}
+	}
+}
+
+// Confirm asks a yes or no question, taking anything but yes as no
+func (p *Prompter) Confirm(question string) (bool, error) {
+	answer, err := p.readLine(question + " Type y for yes or n for no: ")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// filter returns the indexes of the options containing text, ignoring case
+func filter(options []string, text string) []int {
+	text = strings.ToLower(text)
+	var matches []int
+	for i, option := range options {
+		if strings.Contains(strings.ToLower(option), text) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
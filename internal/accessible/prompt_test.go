@@ -0,0 +1,57 @@
+package accessible
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChoose(t *testing.T) {
+	options := []string{"api, Go", "web, TypeScript", "worker, Go"}
+	tests := []struct {
+		name      string
+		input     string
+		canGoBack bool
+		want      int
+		err       error
+	}{
+		{"number", "2\n", false, 1, nil},
+		{"out of range then number", "9\n3\n", false, 2, nil},
+		{"text matching one", "web\n", false, 1, nil},
+		{"text matching several then number", "go\n3\n", false, 2, nil},
+		{"no match then number", "rust\n1\n", false, 0, nil},
+		{"quit", "q\n", false, -1, ErrQuit},
+		{"back", "b\n", true, -1, ErrBack},
+		{"b is text without back", "b\n", false, 1, nil},
+		{"end of input", "", false, -1, ErrQuit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			got, err := NewPrompter(strings.NewReader(tt.input), &out).Choose("Which project?", options, tt.canGoBack)
+			if got != tt.want || !errors.Is(err, tt.err) {
+				t.Errorf("Choose() = %d, %v; want %d, %v\n%s", got, err, tt.want, tt.err, out.String())
+			}
+		})
+	}
+}
+
+func TestChooseNarrowsList(t *testing.T) {
+	var out strings.Builder
+	_, _ = NewPrompter(strings.NewReader("go\n1\n"), &out).Choose("Which project?", []string{"api, Go", "web, TypeScript", "worker, Go"}, false)
+
+	narrowed := out.String()[strings.Index(out.String(), `2 options match "go".`):]
+	if strings.Contains(narrowed, "web") || !strings.Contains(narrowed, "3. worker, Go") {
+		t.Errorf("Expected the matches listed with their own numbers, got:\n%s", narrowed)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	for input, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false} {
+		got, err := NewPrompter(strings.NewReader(input), &strings.Builder{}).Confirm("Run anyway?")
+		if err != nil || got != want {
+			t.Errorf("Confirm(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package accessible
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/hooks"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// Exit is what the session leaves for proj to do once it ends, like the
+// TUI's: change to a directory or hand the terminal to a command
+type Exit struct {
+	CdPath  string
+	ExecCmd []string
+}
+
+// session picks projects and runs their actions until the user quits
+type session struct {
+	cfg      *config.Config
+	p        *Prompter
+	executor *actions.Executor
+	projects []*project.Project
+	fromURL  bool // The start project and action come from a proj:// link
+}
+
+// Run scans the projects and asks which to work on and what to do with
+// it, running the chosen actions until one opens the project or the user
+// quits. startProject, a project name or path, skips the first question,
+// and startAction then runs that action straight away.
+func Run(cfg *config.Config, startProject, startAction string, in io.Reader, out io.Writer) (Exit, error) {
+	s := &session{cfg: cfg, p: NewPrompter(in, out), executor: actions.NewExecutor(cfg)}
+	return s.start(startProject, startAction)
+}
+
+// RunURL is Run for a proj:// link, which anything can open: startProject
+// must be a project's exact path, alias or name, and startAction is only run
+// once confirmed
+func RunURL(cfg *config.Config, startProject, startAction string, in io.Reader, out io.Writer) (Exit, error) {
+	s := &session{cfg: cfg, p: NewPrompter(in, out), executor: actions.NewExecutor(cfg), fromURL: true}
+	return s.start(startProject, startAction)
+}
+
+// start runs the session from the optional start project and action
+func (s *session) start(startProject, startAction string) (Exit, error) {
+	cfg := s.cfg
+
+	s.p.Say("Scanning projects in %s...", config.ExpandPath(cfg.ReposPath))
+	projects, err := project.NewScanner(cfg).Scan(cfg.ReposPath)
+	if err != nil {
+		return Exit{}, fmt.Errorf("failed to scan projects: %w", err)
+	}
+	if st, err := state.Load(); err == nil {
+		st.Annotate(projects)
+	}
+	var archived []*project.Project
+	for _, p := range projects {
+		switch {
+		case p.IsGroup:
+		case p.Archived:
+			archived = append(archived, p)
+		default:
+			s.projects = append(s.projects, p)
+		}
+	}
+	if len(s.projects) == 0 {
+		s.p.Say("No projects found.")
+		return Exit{}, nil
+	}
+	if len(s.projects) == 1 {
+		s.p.Say("Found 1 project.")
+	} else {
+		s.p.Say("Found %d projects.", len(s.projects))
+	}
+
+	var start *project.Project
+	if startProject != "" && s.fromURL {
+		start = project.FindExact(slices.Concat(s.projects, archived), startProject)
+		if start == nil {
+			s.p.Say("No project has exactly the path, alias or name %s.", startProject)
+		}
+	} else if startProject != "" {
+		// Archived projects are left out of the list, but can be named
+		start = project.Find(s.projects, startProject)
+		if start == nil {
+			start = project.Find(archived, startProject)
+		}
+		if start == nil {
+			start = project.Owner(s.projects, startProject)
+		}
+		if start == nil {
+			s.p.Say("Project %s not found.", startProject)
+		}
+	}
+
+	for {
+		proj := start
+		start = nil
+		if proj == nil {
+			i, err := s.p.Choose("Which project?", s.projectOptions(), false)
+			if errors.Is(err, ErrQuit) {
+				return Exit{}, nil
+			}
+			proj = s.projects[i]
+		}
+
+		exit, err := s.actionMenu(proj, startAction)
+		startAction = ""
+		switch {
+		case errors.Is(err, ErrQuit):
+			return Exit{}, nil
+		case errors.Is(err, ErrBack):
+			continue
+		case err != nil:
+			return Exit{}, err
+		}
+		return exit, nil
+	}
+}
+
+// projectOptions describes each project in words
+func (s *session) projectOptions() []string {
+	options := make([]string, len(s.projects))
+	for i, p := range s.projects {
+		options[i] = describeProject(p)
+	}
+	return options
+}
+
+// describeProject names a project with its language and git state
+func describeProject(p *project.Project) string {
+	var details []string
+	if p.Language != "" && p.Language != "Unknown" {
+		details = append(details, p.Language)
+	}
+	if p.GitBranch != "" {
+		details = append(details, "branch "+p.GitBranch)
+	}
+	if p.GitDirty {
+		details = append(details, "uncommitted changes")
+	}
+	if len(details) == 0 {
+		return p.Name
+	}
+	return p.Name + ", " + strings.Join(details, ", ")
+}
+
+// confirmURLAction asks before running an action a proj:// link asked for
+func (s *session) confirmURLAction(action views.Action, proj *project.Project) bool {
+	ok, err := s.p.Confirm(fmt.Sprintf("A proj:// link asks to run %s in %s. Run it?", action.Label, proj.Name))
+	return err == nil && ok
+}
+
+// actionMenu asks what to do with proj until an action opens it, going
+// back to the project question with ErrBack
+func (s *session) actionMenu(proj *project.Project, startAction string) (Exit, error) {
+	menu := s.menu(proj)
+	if startAction != "" {
+		action := views.FindAction(menu, startAction)
+		switch {
+		case action == nil || action.IsSubmenu:
+			s.p.Say("Action %s is not available for %s.", startAction, proj.Name)
+		case s.fromURL && !s.confirmURLAction(*action, proj):
+			s.p.Say("Cancelled.")
+		default:
+			if exit, done := s.run(*action, proj); done {
+				return exit, nil
+			}
+			s.refresh(proj)
+			menu = s.menu(proj)
+		}
+	}
+
+	stack := [][]views.Action{menu}
+	for {
+		current := stack[len(stack)-1]
+		i, err := s.p.Choose(fmt.Sprintf("What do you want to do with %s?", proj.Name), actionOptions(current), true)
+		if errors.Is(err, ErrBack) && len(stack) > 1 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if err != nil {
+			return Exit{}, err
+		}
+
+		action := current[i]
+		if action.IsSubmenu {
+			stack = append(stack, action.Children)
+			continue
+		}
+		if exit, done := s.run(action, proj); done {
+			return exit, nil
+		}
+		// The action may have changed what's on offer, e.g. Git Init
+		s.refresh(proj)
+		stack = [][]views.Action{s.menu(proj)}
+	}
+}
+
+// menu returns proj's actions
+func (s *session) menu(proj *project.Project) []views.Action {
+	return s.menuActions(views.DefaultActions(proj, s.cfg.Actions.EnableGitOperations, s.cfg.Actions.EnableTestRunner), proj)
+}
+
+// refresh updates proj's details after an action ran in it
+func (s *session) refresh(proj *project.Project) {
+	proj.Apply(project.NewScanner(s.cfg).Details(proj.Path))
+}
+
+// menuActions leaves out the menu's back entry, which b replaces, and the
+// actions disabled for proj
+func (s *session) menuActions(menu []views.Action, proj *project.Project) []views.Action {
+	var out []views.Action
+	for _, a := range menu {
+		if a.ID == "back" || s.cfg.Actions.IsDisabled(proj.Name, a.ID) {
+			continue
+		}
+		if a.IsSubmenu {
+			if a.Children = s.menuActions(a.Children, proj); len(a.Children) == 0 {
+				continue
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// actionOptions describes each action in words
+func actionOptions(menu []views.Action) []string {
+	options := make([]string, len(menu))
+	for i, a := range menu {
+		options[i] = a.Label
+		if a.Desc != "" {
+			options[i] += ": " + a.Desc
+		}
+		if a.IsSubmenu {
+			options[i] += " (opens a submenu)"
+		}
+	}
+	return options
+}
+
+// run runs action on proj and reports the result. done is set when the
+// action opened the project, so the session ends with exit.
+func (s *session) run(action views.Action, proj *project.Project) (exit Exit, done bool) {
+	if warning := s.executor.ProtectedBranchWarning(action.Command, proj); warning != "" {
+		ok, err := s.p.Confirm(fmt.Sprintf("Warning: %s in %s %s. Run anyway?", action.Label, proj.Name, warning))
+		if err != nil || !ok {
+			s.p.Say("Cancelled.")
+			return Exit{}, false
+		}
+	}
+	if err := hooks.Run(s.cfg, hooks.PreAction, proj, "PROJ_ACTION="+action.ID); err != nil {
+		s.p.Say("Error: %v", err)
+		return Exit{}, false
+	}
+
+	s.p.Say("Running %s in %s...", action.Label, proj.Name)
+	var result actions.Result
+	if action.Command != "" {
+		result = s.executor.ExecuteCommand(action.Command, proj)
+	} else {
+		result = s.executor.Execute(action.ID, proj)
+	}
+	if result.Success && result.OpenedWith != "" {
+		if err := hooks.Run(s.cfg, hooks.PostOpen, proj, "PROJ_EDITOR="+result.OpenedWith); err != nil {
+			s.p.Say("Warning: %v", err)
+		}
+	}
+
+	if message := strings.TrimRight(result.Message, "\n"); message != "" {
+		s.p.Say("%s", message)
+	}
+	if !result.Success {
+		s.p.Say("%s failed.", action.Label)
+		return Exit{}, false
+	}
+	s.p.Say("%s finished.", action.Label)
+	if result.CdPath != "" || len(result.ExecCmd) > 0 {
+		return Exit{CdPath: result.CdPath, ExecCmd: result.ExecCmd}, true
+	}
+	return Exit{}, false
+}
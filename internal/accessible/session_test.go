@@ -0,0 +1,86 @@
+package accessible
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+func newTestConfig(t *testing.T) (*config.Config, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	app := filepath.Join(root, "app")
+	if err := os.MkdirAll(app, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app, "Makefile"), []byte("greet:\n\t@echo hello from make\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.ReposPath = root
+	return cfg, app
+}
+
+func TestRunAction(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+
+	var out strings.Builder
+	exit, err := Run(cfg, "", "", strings.NewReader("app\nmake greet\nq\n"), &out)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if exit.CdPath != "" || exit.ExecCmd != nil {
+		t.Errorf("Expected nothing left to do after quitting, got %+v", exit)
+	}
+	for _, want := range []string{"Found 1 project.", "What do you want to do with app?", "hello from make", "greet finished."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Error("Expected no escape sequences in accessible output")
+	}
+}
+
+func TestRunStartAction(t *testing.T) {
+	cfg, app := newTestConfig(t)
+
+	exit, err := Run(cfg, "app", "cd", strings.NewReader(""), &strings.Builder{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if exit.CdPath != app {
+		t.Errorf("Run() CdPath = %q, want %q", exit.CdPath, app)
+	}
+}
+
+func TestRunURL(t *testing.T) {
+	cfg, app := newTestConfig(t)
+
+	// Links don't guess at names
+	var out strings.Builder
+	if _, err := RunURL(cfg, "ap", "cd", strings.NewReader("q\n"), &out); err != nil {
+		t.Fatalf("RunURL failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "No project has exactly the path, alias or name ap.") {
+		t.Errorf("Expected a partial name to be refused:\n%s", out.String())
+	}
+
+	out.Reset()
+	exit, err := RunURL(cfg, "app", "cd", strings.NewReader("n\nq\n"), &out)
+	if err != nil {
+		t.Fatalf("RunURL failed: %v", err)
+	}
+	if exit.CdPath != "" || !strings.Contains(out.String(), "A proj:// link asks to run") {
+		t.Errorf("Expected the action to be confirmed and declined, got %+v:\n%s", exit, out.String())
+	}
+
+	exit, err = RunURL(cfg, "app", "cd", strings.NewReader("y\n"), &strings.Builder{})
+	if err != nil || exit.CdPath != app {
+		t.Errorf("RunURL() = %+v, %v, want cd to %s once confirmed", exit, err, app)
+	}
+}
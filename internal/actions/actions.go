@@ -2,24 +2,42 @@ package actions
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/s33g/proj/internal/config"
 	"github.com/s33g/proj/internal/docker"
 	"github.com/s33g/proj/internal/git"
 	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/results"
+	"github.com/s33g/proj/internal/scripts"
+	"github.com/s33g/proj/internal/security"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/sys"
+	"github.com/s33g/proj/internal/wsl"
 )
 
 // Result represents the result of an action
 type Result struct {
-	Success bool
-	Message string
-	CdPath  string
-	ExecCmd []string
+	Success    bool
+	Message    string
+	CdPath     string
+	ExecCmd    []string
+	OpenedWith string           // Editor alias or state.OpenedInTerminal when the action opened the project
+	Summary    *results.Summary // Structured test summary, when the output was parsed
+	ExitCode   int              // Exit code of the command the action ran, if it ran one
+	Diverged   bool             // A pull stopped because the branch and its upstream diverged
+	RetryArgs  []string         // Git arguments to retry with credential prompts after an auth failure
 }
 
 // Executor executes actions on projects
@@ -32,59 +50,129 @@ func NewExecutor(cfg *config.Config) *Executor {
 	return &Executor{config: cfg}
 }
 
-// Execute executes an action on a project
+// Execute executes an action on a project, recording it in the audit log
 func (e *Executor) Execute(actionID string, proj *project.Project) Result {
+	if !e.config.Audit.Enabled {
+		return e.execute(actionID, proj)
+	}
+	start, plan := time.Now(), e.DryRun(actionID, "", proj)
+	return e.audit(start, actionID, plan, proj, e.execute(actionID, proj))
+}
+
+// execute executes an action on a project
+func (e *Executor) execute(actionID string, proj *project.Project) Result {
 	// Check if it's a Docker action
 	if strings.HasPrefix(actionID, "docker-") || strings.HasPrefix(actionID, "compose-") {
 		return e.executeDockerAction(actionID, proj)
 	}
+	if strings.HasPrefix(actionID, "scaffold-") {
+		return e.scaffold(actionID, proj)
+	}
+	if port, ok := strings.CutPrefix(actionID, "open-port-"); ok {
+		return e.openPort(port)
+	}
+	if name, ok := strings.CutPrefix(actionID, "env-open-"); ok {
+		return e.envOpen(name, proj)
+	}
+	if strings.HasPrefix(actionID, "gowork-") {
+		return e.goWork(actionID, proj)
+	}
+	if service, ok := strings.CutPrefix(actionID, "db-shell-"); ok {
+		return e.dbShell(service, proj)
+	}
+	if service, ok := strings.CutPrefix(actionID, "db-url-"); ok {
+		return e.dbURL(service, proj)
+	}
+	if service, ok := strings.CutPrefix(actionID, "db-dump-"); ok {
+		return e.dbDump(service, proj)
+	}
+	if service, ok := strings.CutPrefix(actionID, "db-restore-"); ok {
+		return e.dbRestore(service, proj)
+	}
 
 	switch actionID {
 	case "open-editor":
 		return e.openEditor(proj)
 	case "cd":
-		return Result{Success: true, CdPath: proj.Path}
+		return Result{Success: true, CdPath: proj.Path, OpenedWith: state.OpenedInTerminal}
+	case "reopen":
+		return e.reopen(proj)
 	case "git-log":
 		return e.gitLog(proj)
 	case "git-pull":
 		return e.gitPull(proj)
 	case "git-branch":
 		return e.gitBranch(proj)
+	case "git-lfs-pull":
+		return e.gitLFS(proj, "pull")
+	case "git-lfs-prune":
+		return e.gitLFS(proj, "prune")
+	case "open-issue":
+		return e.openIssue(proj)
 	case "git-init":
 		return e.gitInit(proj)
 	case "run-tests":
 		return e.runTests(proj)
 	case "install-deps":
 		return e.installDeps(proj)
+	case "bootstrap":
+		return e.bootstrap(proj)
 	case "clean":
 		return e.clean(proj)
+	case "backup":
+		return e.Backup(proj, nil)
+	case "git-commit":
+		return Result{Success: false, Message: "Commit asks for a message, so it can only be run from the TUI"}
+	case "server-start", "server-stop", "server-restart", "server-logs":
+		return Result{Success: false, Message: "Dev servers are managed by the TUI, which keeps track of them"}
+	case "git-new-branch":
+		return Result{Success: false, Message: "New Branch from Template asks for a description, so it can only be run from the TUI"}
+	case "duplicate":
+		return Result{Success: false, Message: "Duplicate Project asks for a name, so it can only be run from the TUI"}
+	case "archive", "unarchive":
+		return setArchived(proj, actionID == "archive")
+	case "generate-gitignore":
+		return e.generateGitignore(proj)
+	case "env-show":
+		return e.envShow(proj)
+	case "env-create", "env-sync":
+		return e.envSync(proj)
+	case "scan-secrets":
+		return e.scanSecrets(proj)
+	case "audit-deps":
+		return e.auditDeps(proj)
+	case "jvm-refresh-tasks":
+		return e.refreshTasks(proj)
 	default:
 		return Result{Success: false, Message: "Unknown action: " + actionID}
 	}
 }
 
-// ExecuteCommand executes a shell command in the project directory
+// ExecuteCommand executes a shell command in the project directory,
+// recording it in the audit log
 func (e *Executor) ExecuteCommand(command string, proj *project.Project) Result {
-	// Parse command into args
-	args := parseCommand(command)
+	if !e.config.Audit.Enabled {
+		return e.executeCommand(command, proj)
+	}
+	start, plan := time.Now(), e.DryRun("", command, proj)
+	return e.audit(start, "", plan, proj, e.executeCommand(command, proj))
+}
+
+// executeCommand executes a shell command in the project directory
+func (e *Executor) executeCommand(command string, proj *project.Project) Result {
+	args := e.commandArgs(command)
 	if len(args) == 0 {
 		return Result{Success: false, Message: "Empty command"}
 	}
 
-	cmd := exec.Command(args[0], args[1:]...)
-	cmd.Dir = proj.Path
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-
-	err := cmd.Run()
-	output := out.String()
+	cmd := sys.Command(args[0], args[1:]...)
+	output, err := e.run(cmd, proj)
 
 	if err != nil {
 		return Result{
-			Success: false,
-			Message: fmt.Sprintf("Command failed: %v\n\n%s", err, output),
+			Success:  false,
+			Message:  fmt.Sprintf("Command failed: %v\n\n%s", err, output),
+			ExitCode: exitCode(err),
 		}
 	}
 
@@ -98,66 +186,287 @@ func (e *Executor) ExecuteCommand(command string, proj *project.Project) Result
 	}
 }
 
-// parseCommand splits a command string into arguments, handling quotes
-func parseCommand(cmd string) []string {
+// exitCode returns the exit code of a command that failed with err, or -1
+// if it didn't get to exit (e.g. it wasn't found)
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// commandArgs splits a command into arguments, or hands it to the configured
+// shell when actions.useShell is set so pipes, && and env prefixes work
+func (e *Executor) commandArgs(command string) []string {
+	if strings.TrimSpace(command) == "" {
+		return nil
+	}
+	if e.config.Actions.UseShell {
+		shell := e.config.Shell
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		return []string{shell, "-c", command}
+	}
+	return ParseCommand(command)
+}
+
+// run runs cmd in the project directory, inside the project's configured
+// container if there is one, and returns its combined output
+func (e *Executor) run(cmd *exec.Cmd, proj *project.Project) (string, error) {
+	var out bytes.Buffer
+
+	cmd, note := e.prepare(cmd, proj)
+	if note != "" {
+		out.WriteString(note + "\n\n")
+	}
+
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// prepare wraps cmd to run in the project's container or development
+// environment and sets its directory, the project's workdir. note says how
+// it was wrapped.
+func (e *Executor) prepare(cmd *exec.Cmd, proj *project.Project) (*exec.Cmd, string) {
+	wrapped, note := e.wrap(cmd.Args, proj)
+	if wrapped != nil {
+		cmd = sys.Command(wrapped[0], wrapped[1:]...)
+	}
+	cmd.Dir = proj.WorkPath()
+	return cmd, note
+}
+
+// CommandContext returns the command running args in a project the way its
+// actions run, in its container or development environment and from its
+// workdir, killed when ctx is done
+func (e *Executor) CommandContext(ctx context.Context, proj *project.Project, args []string) *exec.Cmd {
+	if wrapped, _ := e.wrap(args, proj); wrapped != nil {
+		args = wrapped
+	}
+	cmd := sys.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = proj.WorkPath()
+	return cmd
+}
+
+// wrap returns args wrapped to run in the project's container or
+// development environment, or nil if they run as they are, and a note
+// saying how
+func (e *Executor) wrap(args []string, proj *project.Project) ([]string, string) {
+	if image := e.containerImage(proj); image != "" {
+		return containerArgs(e.containerRuntime(), image, proj.Path, proj.Workdir, args), fmt.Sprintf("🐳 Running in %s", image)
+	}
+	if proj.DevEnv == "" {
+		return nil, ""
+	}
+	// Run in the environment the project declares (a container brings its own)
+	if wrapped := devEnvArgs(proj.DevEnv, args); wrapped != nil {
+		return wrapped, fmt.Sprintf("❄ Running in %s environment", proj.DevEnv)
+	}
+	return nil, fmt.Sprintf("⚠ %s not installed; running without the project's environment", proj.DevEnv)
+}
+
+// containerImage returns the image actions for a project should run in, if
+// any. A per-project image takes precedence over a per-language one.
+func (e *Executor) containerImage(proj *project.Project) string {
+	c := e.config.Container
+	if image, _ := config.LookupFold(c.Projects, proj.Name); image != "" {
+		return image
+	}
+	image, _ := config.LookupFold(c.Languages, proj.Language)
+	return image
+}
+
+// containerRuntime returns the container CLI to use: the configured one, or
+// else docker, podman or nerdctl, whichever is installed
+func (e *Executor) containerRuntime() string {
+	return docker.Runtime(e.config.Container.Runtime)
+}
+
+// DaemonStartCommand returns the command that starts the container
+// runtime's daemon: container.startCommand, or else the one suggested for
+// this machine
+func (e *Executor) DaemonStartCommand() string {
+	if e.config.Container.StartCommand != "" {
+		return e.config.Container.StartCommand
+	}
+	return docker.StartCommand(e.containerRuntime())
+}
+
+// daemonHint explains a failed container action when it's because the
+// runtime's daemon isn't running, or returns ""
+func (e *Executor) daemonHint() string {
+	runtime := e.containerRuntime()
+	if docker.DaemonRunning(runtime) {
+		return ""
+	}
+	hint := fmt.Sprintf("The %s daemon isn't running.", runtime)
+	if start := e.DaemonStartCommand(); start != "" {
+		hint += " Start it with: " + start
+	}
+	return hint
+}
+
+// devEnvArgs wraps args to run inside a Nix or direnv environment, or
+// returns nil if the tool isn't installed
+func devEnvArgs(devEnv string, args []string) []string {
+	switch devEnv {
+	case project.DevEnvNix:
+		if commandExists("nix") {
+			return append([]string{"nix", "develop", "-c"}, args...)
+		}
+	case project.DevEnvDirenv:
+		if commandExists("direnv") {
+			return append([]string{"direnv", "exec", "."}, args...)
+		}
+	}
+	return nil
+}
+
+// containerArgs wraps args in a throwaway container with the project
+// mounted at /work, running in its workdir there
+func containerArgs(runtime, image, projectPath, workdir string, args []string) []string {
+	runArgs := []string{runtime, "run", "--rm", "-v", projectPath + ":/work", "-w", path.Join("/work", filepath.ToSlash(workdir)), image}
+	return append(runArgs, args...)
+}
+
+// ParseCommand splits a command string into arguments the way a POSIX shell
+// would, without expanding anything: whitespace separates arguments, quotes
+// group them ("" is an empty argument), and a backslash escapes the next
+// character outside single quotes
+func ParseCommand(cmd string) []string {
 	var args []string
 	var current strings.Builder
-	inQuote := false
+	inArg := false // An argument has started, even if it's still empty
 	quoteChar := rune(0)
+	escaped := false
 
 	for _, r := range cmd {
 		switch {
-		case r == '"' || r == '\'':
-			if inQuote && r == quoteChar {
-				inQuote = false
+		case escaped:
+			// Inside double quotes only a few characters can be escaped
+			if quoteChar == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quoteChar != '\'':
+			escaped = true
+			inArg = true
+		case quoteChar != 0:
+			if r == quoteChar {
 				quoteChar = 0
-			} else if !inQuote {
-				inQuote = true
-				quoteChar = r
 			} else {
 				current.WriteRune(r)
 			}
-		case r == ' ' && !inQuote:
-			if current.Len() > 0 {
+		case r == '"' || r == '\'':
+			quoteChar = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
 				args = append(args, current.String())
 				current.Reset()
+				inArg = false
 			}
 		default:
 			current.WriteRune(r)
+			inArg = true
 		}
 	}
 
-	if current.Len() > 0 {
+	if escaped {
+		current.WriteRune('\\')
+	}
+	if inArg {
 		args = append(args, current.String())
 	}
 
 	return args
 }
 
-// openEditor opens the project in the configured editor
+// openEditor opens the project in the configured editor, or changes to it
+// instead depending on onOpen
 func (e *Executor) openEditor(proj *project.Project) Result {
-	editorCmd := e.config.Editor.Default
+	if strings.EqualFold(e.config.OnOpen, config.OnOpenCdOnly) {
+		return Result{Success: true, CdPath: proj.Path, OpenedWith: state.OpenedInTerminal}
+	}
+	return e.openEditorWith(e.editorFor(proj), proj)
+}
 
-	// Get editor command and args from aliases
-	cmdArgs, ok := e.config.Editor.Aliases[editorCmd]
-	if !ok || len(cmdArgs) == 0 {
-		// Fallback to just the editor name
-		cmdArgs = []string{editorCmd}
+// editorFor returns the editor a project opens with: its group's, or the default
+func (e *Executor) editorFor(proj *project.Project) string {
+	if proj.Editor != "" {
+		return proj.Editor
 	}
+	return e.config.Editor.Default
+}
 
-	// Check if editor exists
-	if !commandExists(cmdArgs[0]) {
+// reopen opens the project the same way it was opened last time
+func (e *Executor) reopen(proj *project.Project) Result {
+	switch proj.LastOpenedWith {
+	case "":
+		return Result{Success: false, Message: "Project has not been opened with proj yet"}
+	case state.OpenedInTerminal:
+		return Result{Success: true, CdPath: proj.Path, OpenedWith: state.OpenedInTerminal}
+	default:
+		return e.openEditorWith(proj.LastOpenedWith, proj)
+	}
+}
+
+// openEditorWith opens the project with the given editor alias. With onOpen
+// set to both, proj exits, changes to the project and execs the editor.
+func (e *Executor) openEditorWith(editorCmd string, proj *project.Project) Result {
+	cmdArgs, err := e.resolveEditor(editorCmd)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+
+	// Prepare full command with project path
+	fullArgs := append(cmdArgs[1:], editorPath(cmdArgs[0], proj.Path))
+
+	if strings.EqualFold(e.config.OnOpen, config.OnOpenBoth) {
+		return Result{
+			Success:    true,
+			CdPath:     proj.Path,
+			ExecCmd:    append([]string{cmdArgs[0]}, fullArgs...),
+			OpenedWith: editorCmd,
+		}
+	}
+
+	// Execute in background
+	cmd := sys.Command(cmdArgs[0], fullArgs...)
+	if err := cmd.Start(); err != nil {
 		return Result{
 			Success: false,
-			Message: fmt.Sprintf("Editor '%s' not found in PATH", cmdArgs[0]),
+			Message: fmt.Sprintf("Failed to open editor: %v", err),
 		}
 	}
 
-	// Prepare full command with project path
-	fullArgs := append(cmdArgs[1:], proj.Path)
+	return Result{
+		Success:    true,
+		Message:    fmt.Sprintf("Opened in %s", editorCmd),
+		OpenedWith: editorCmd,
+	}
+}
 
-	// Execute in background
-	cmd := exec.Command(cmdArgs[0], fullArgs...)
+// OpenLocation opens a file at a line and column in the configured editor
+func (e *Executor) OpenLocation(loc results.Location) Result {
+	editorCmd := e.config.Editor.Default
+	cmdArgs, err := e.resolveEditor(editorCmd)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+
+	fullArgs := append(cmdArgs[1:], locationArgs(cmdArgs, editorPath(cmdArgs[0], loc.File), loc.Line, loc.Col)...)
+
+	cmd := sys.Command(cmdArgs[0], fullArgs...)
 	if err := cmd.Start(); err != nil {
 		return Result{
 			Success: false,
@@ -167,7 +476,62 @@ func (e *Executor) openEditor(proj *project.Project) Result {
 
 	return Result{
 		Success: true,
-		Message: fmt.Sprintf("Opened in %s", editorCmd),
+		Message: fmt.Sprintf("Opened %s:%d in %s", filepath.Base(loc.File), loc.Line, editorCmd),
+	}
+}
+
+// resolveEditor returns the command and arguments for an editor alias
+func (e *Executor) resolveEditor(editorCmd string) ([]string, error) {
+	// Get editor command and args from aliases
+	cmdArgs, ok := e.config.Editor.Aliases[editorCmd]
+	if !ok || len(cmdArgs) == 0 {
+		// Fallback to just the editor name
+		cmdArgs = []string{editorCmd}
+	}
+
+	// Check if editor exists
+	if !commandExists(cmdArgs[0]) {
+		return nil, fmt.Errorf("Editor '%s' not found in PATH", cmdArgs[0])
+	}
+	return cmdArgs, nil
+}
+
+// editorPath translates a path for the editor: Windows editors launched from
+// WSL need a Windows path
+func editorPath(editor, path string) string {
+	if wsl.IsWSL() && wsl.IsWindowsExecutable(editor) {
+		return wsl.ToWindowsPath(path)
+	}
+	return path
+}
+
+// locationArgs returns the arguments that open path at line and col in the
+// given editor command. Unknown editors just get the path.
+func locationArgs(cmdArgs []string, path string, line, col int) []string {
+	if col < 1 {
+		col = 1
+	}
+	withPosition := fmt.Sprintf("%s:%d:%d", path, line, col)
+
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(cmdArgs[0])), ".exe")
+	switch name {
+	case "code", "code-insiders", "codium", "cursor", "windsurf":
+		for _, arg := range cmdArgs[1:] {
+			if arg == "--goto" || arg == "-g" {
+				return []string{withPosition}
+			}
+		}
+		return []string{"--goto", withPosition}
+	case "vim", "nvim", "vi", "gvim", "mvim":
+		return []string{fmt.Sprintf("+call cursor(%d,%d)", line, col), path}
+	case "emacs", "emacsclient", "kak":
+		return []string{fmt.Sprintf("+%d:%d", line, col), path}
+	case "zed", "subl", "hx", "helix", "micro":
+		return []string{withPosition}
+	case "idea", "goland", "pycharm", "webstorm", "clion", "rubymine", "phpstorm", "rider", "studio":
+		return []string{"--line", strconv.Itoa(line), "--column", strconv.Itoa(col - 1), path}
+	default:
+		return []string{path}
 	}
 }
 
@@ -197,10 +561,82 @@ func (e *Executor) gitPull(proj *project.Project) Result {
 	}
 
 	output, err := git.Pull(proj.Path)
+	if proj.UsesLFS && !lfsInstalled() {
+		// Without git-lfs the large files are checked out as small pointer files
+		output = lfsMissing + "\n\n" + output
+	}
 	if err != nil {
-		return Result{Success: false, Message: fmt.Sprintf("Pull failed: %v\n%s", err, output)}
+		if git.Diverged(output) {
+			return Result{Success: false, Diverged: true, Message: DivergedMessage(proj)}
+		}
+		return GitFailure("Pull failed", err, output, "pull")
+	}
+
+	return Result{Success: true, Message: output}
+}
+
+// GitFailure reports a failed git command run with args. Authentication
+// failures get guidance on fixing them and can be retried with prompts.
+func GitFailure(prefix string, err error, output string, args ...string) Result {
+	auth := git.DetectAuthFailure(output)
+	if auth == "" {
+		return Result{Success: false, Message: fmt.Sprintf("%s: %v\n%s", prefix, err, output)}
+	}
+	return Result{
+		Success:   false,
+		Message:   fmt.Sprintf("%s\n\n%s: %v\n%s", auth.Guidance(), prefix, err, output),
+		RetryArgs: args,
+	}
+}
+
+// DivergedMessage explains why a pull into proj could not fast-forward and
+// how to reconcile the branches
+func DivergedMessage(proj *project.Project) string {
+	var b strings.Builder
+	upstream, ahead, behind, err := aheadBehind(proj.Path)
+	if err != nil {
+		b.WriteString("Pull stopped: your branch and its upstream have diverged.")
+	} else {
+		fmt.Fprintf(&b, "Pull stopped: %s and %s have diverged.\n", proj.GitBranch, upstream)
+		fmt.Fprintf(&b, "%s here not on %s, %s on %s not here.",
+			pluralCommits(ahead), upstream, pluralCommits(behind), upstream)
+	}
+	b.WriteString("\n\nReconcile them with git pull --rebase (replay your commits on top),\n")
+	b.WriteString("git pull --no-rebase (create a merge commit) or git pull --ff-only\n")
+	b.WriteString("(only succeeds once there are no local commits).")
+	return b.String()
+}
+
+// aheadBehind is swapped out in tests
+var aheadBehind = git.AheadBehind
+
+// pluralCommits formats a commit count, e.g. "1 commit" or "3 commits"
+func pluralCommits(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
+
+// lfsMissing warns that a repository needs git-lfs
+const lfsMissing = "Warning: this repository stores large files with Git LFS, but git-lfs is not installed, so they are left as pointer files. Install it from https://git-lfs.com and run git lfs install."
+
+// lfsInstalled is swapped out in tests
+var lfsInstalled = git.LFSInstalled
+
+// gitLFS runs a git lfs subcommand
+func (e *Executor) gitLFS(proj *project.Project, subcommand string) Result {
+	if !lfsInstalled() {
+		return Result{Success: false, Message: lfsMissing}
 	}
 
+	output, err := git.LFS(proj.Path, subcommand)
+	if err != nil {
+		return GitFailure("git lfs "+subcommand+" failed", err, output, "lfs", subcommand)
+	}
+	if output == "" {
+		output = fmt.Sprintf("git lfs %s completed", subcommand)
+	}
 	return Result{Success: true, Message: output}
 }
 
@@ -244,139 +680,226 @@ func (e *Executor) gitInit(proj *project.Project) Result {
 
 // runTests runs project tests
 func (e *Executor) runTests(proj *project.Project) Result {
-	// Detect test command based on language
+	cmd, err := e.testCommand(proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+
+	output, err := e.run(cmd, proj)
+	return testResult(output, err, testParser(cmd.Args))
+}
+
+// testParser returns the output parser for a test command's output, or ""
+func testParser(args []string) string {
+	switch {
+	case len(args) > 2 && args[0] == "go" && args[1] == "test" && args[2] == "-json":
+		return results.GoTestJSON
+	case len(args) > 0 && args[0] == "ctest":
+		return results.CTest
+	}
+	return ""
+}
+
+// testResult reports a test run, summarized by parser if it's set. go test
+// -json output keeps its plain output as the raw text.
+func testResult(output string, err error, parser string) Result {
+	var summary *results.Summary
+	if parser != "" {
+		summary, _ = results.Parse(parser, output)
+	}
+	if parser == results.GoTestJSON {
+		output = results.GoTestOutput(output)
+	}
+
+	if err != nil {
+		return Result{
+			Success:  false,
+			Message:  fmt.Sprintf("Tests failed:\n%s", output),
+			Summary:  summary,
+			ExitCode: exitCode(err),
+		}
+	}
+
+	return Result{
+		Success: true,
+		Message: fmt.Sprintf("Tests passed:\n%s", output),
+		Summary: summary,
+	}
+}
+
+// testCommand detects the command that runs a project's tests
+func (e *Executor) testCommand(proj *project.Project) (*exec.Cmd, error) {
 	var cmd *exec.Cmd
 
 	switch proj.Language {
 	case "Go":
-		cmd = exec.Command("go", "test", "./...")
+		// A workspace root without a module of its own tests each module
+		patterns := []string{"./..."}
+		if _, err := sys.Stat(filepath.Join(proj.WorkPath(), "go.mod")); err != nil && proj.Workdir == "" {
+			if modules := goWorkTestPatterns(proj.Path); len(modules) > 0 {
+				patterns = modules
+			}
+		}
+		cmd = sys.Command("go", append([]string{"test", "-json"}, patterns...)...)
 	case "Rust":
-		cmd = exec.Command("cargo", "test")
+		cmd = sys.Command("cargo", "test")
 	case "JavaScript", "TypeScript":
 		cmd = e.detectNodeTestCommand(proj)
 	case "Python":
 		cmd = e.detectPythonTestCommand(proj)
-	default:
-		return Result{
-			Success: false,
-			Message: fmt.Sprintf("Don't know how to run tests for %s projects", proj.Language),
+	case "C/C++":
+		if args := scripts.CMakeTestArgs(proj.WorkPath()); args != nil {
+			cmd = sys.Command(args[0], args[1:]...)
 		}
+	case "C#":
+		cmd = sys.Command("dotnet", "test")
+	default:
+		return nil, fmt.Errorf("Don't know how to run tests for %s projects", proj.Language)
 	}
 
 	if cmd == nil {
-		return Result{Success: false, Message: "No test command found"}
+		return nil, fmt.Errorf("No test command found")
 	}
+	return cmd, nil
+}
 
-	cmd.Dir = proj.Path
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
+// installDeps installs project dependencies
+func (e *Executor) installDeps(proj *project.Project) Result {
+	cmd, err := e.installCommand(proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
 
-	err := cmd.Run()
-	output := out.String()
+	output, err := e.run(cmd, proj)
 
 	if err != nil {
 		return Result{
-			Success: false,
-			Message: fmt.Sprintf("Tests failed:\n%s", output),
+			Success:  false,
+			Message:  fmt.Sprintf("Install failed:\n%s", output),
+			ExitCode: exitCode(err),
 		}
 	}
 
 	return Result{
 		Success: true,
-		Message: fmt.Sprintf("Tests passed:\n%s", output),
+		Message: fmt.Sprintf("Dependencies installed:\n%s", output),
 	}
 }
 
-// installDeps installs project dependencies
-func (e *Executor) installDeps(proj *project.Project) Result {
+// installCommand detects the command that installs a project's dependencies
+func (e *Executor) installCommand(proj *project.Project) (*exec.Cmd, error) {
 	var cmd *exec.Cmd
 
 	switch proj.Language {
 	case "Go":
-		cmd = exec.Command("go", "mod", "download")
+		cmd = sys.Command("go", "mod", "download")
 	case "Rust":
-		cmd = exec.Command("cargo", "fetch")
+		cmd = sys.Command("cargo", "fetch")
 	case "JavaScript", "TypeScript":
 		cmd = e.detectNodeInstallCommand(proj)
 	case "Python":
 		cmd = e.detectPythonInstallCommand(proj)
 	case "Ruby":
-		cmd = exec.Command("bundle", "install")
+		cmd = sys.Command("bundle", "install")
 	case "PHP":
-		cmd = exec.Command("composer", "install")
+		cmd = sys.Command("composer", "install")
+	case "C#":
+		cmd = sys.Command("dotnet", "restore")
 	default:
-		return Result{
-			Success: false,
-			Message: fmt.Sprintf("Don't know how to install dependencies for %s projects", proj.Language),
-		}
+		return nil, fmt.Errorf("Don't know how to install dependencies for %s projects", proj.Language)
 	}
 
 	if cmd == nil {
-		return Result{Success: false, Message: "No install command found"}
+		return nil, fmt.Errorf("No install command found")
 	}
+	return cmd, nil
+}
 
-	cmd.Dir = proj.Path
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
+// bootstrap sets up a project's environment: Python projects get a .venv
+// with their requirements installed into it, others install dependencies
+func (e *Executor) bootstrap(proj *project.Project) Result {
+	if proj.Language != "Python" || !project.NeedsSetup(proj.WorkPath(), proj.Language) {
+		return e.installDeps(proj)
+	}
 
-	err := cmd.Run()
-	output := out.String()
+	venv, install := venvCommands(proj)
+	output, err := e.run(venv, proj)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to create virtualenv:\n%s", output), ExitCode: exitCode(err)}
+	}
 
+	installOutput, err := e.run(install, proj)
+	output = strings.TrimSpace(output + "\n" + installOutput)
 	if err != nil {
-		return Result{
-			Success: false,
-			Message: fmt.Sprintf("Install failed:\n%s", output),
-		}
+		return Result{Success: false, Message: fmt.Sprintf("Created .venv, but install failed:\n%s", output), ExitCode: exitCode(err)}
 	}
 
-	return Result{
-		Success: true,
-		Message: fmt.Sprintf("Dependencies installed:\n%s", output),
+	return Result{Success: true, Message: fmt.Sprintf("Created .venv and installed dependencies:\n%s", output)}
+}
+
+// venvCommands returns the commands that create a Python project's .venv
+// and install its dependencies into it
+func venvCommands(proj *project.Project) (venv, install *exec.Cmd) {
+	python := "python3"
+	if !commandExists(python) && commandExists("python") {
+		python = "python"
 	}
+
+	pip := filepath.Join(".venv", "bin", "pip")
+	if runtime.GOOS == "windows" {
+		pip = filepath.Join(".venv", "Scripts", "pip.exe")
+	}
+
+	install = sys.Command(pip, "install", "-e", ".")
+	if _, err := os.Stat(filepath.Join(proj.WorkPath(), "requirements.txt")); err == nil {
+		install = sys.Command(pip, "install", "-r", "requirements.txt")
+	}
+	return sys.Command(python, "-m", "venv", ".venv"), install
 }
 
-// clean removes build artifacts
-func (e *Executor) clean(proj *project.Project) Result {
-	artifacts := e.detectBuildArtifacts(proj)
+// scanSecrets scans the working tree for committed credentials
+func (e *Executor) scanSecrets(proj *project.Project) Result {
+	report, err := security.ScanSecrets(proj.Path, e.config.ExcludePatterns)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Secret scan failed: %v", err)}
+	}
 
-	removed := []string{}
-	for _, artifact := range artifacts {
-		artifactPath := filepath.Join(proj.Path, artifact)
-		if _, err := os.Stat(artifactPath); err == nil {
-			if err := os.RemoveAll(artifactPath); err == nil {
-				removed = append(removed, artifact)
-			}
-		}
+	return Result{
+		Success: len(report.Findings) == 0,
+		Message: report.String(),
 	}
+}
 
-	if len(removed) == 0 {
-		return Result{Success: true, Message: "No build artifacts found to clean"}
+// auditDeps checks dependencies for known vulnerabilities
+func (e *Executor) auditDeps(proj *project.Project) Result {
+	report, err := security.Audit(proj.Path, proj.Language)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Audit failed: %v", err)}
 	}
 
 	return Result{
-		Success: true,
-		Message: fmt.Sprintf("Removed: %s", strings.Join(removed, ", ")),
+		Success: len(report.Vulns) == 0,
+		Message: report.String(),
 	}
 }
 
 // detectNodeTestCommand detects the appropriate test command for Node projects
 func (e *Executor) detectNodeTestCommand(proj *project.Project) *exec.Cmd {
-	packageJSON := filepath.Join(proj.Path, "package.json")
+	dir := proj.WorkPath()
+	packageJSON := filepath.Join(dir, "package.json")
 
 	// Check for package managers
-	if _, err := os.Stat(filepath.Join(proj.Path, "bun.lockb")); err == nil {
-		return exec.Command("bun", "test")
+	if _, err := os.Stat(filepath.Join(dir, "bun.lockb")); err == nil {
+		return sys.Command("bun", "test")
 	}
-	if _, err := os.Stat(filepath.Join(proj.Path, "pnpm-lock.yaml")); err == nil {
-		return exec.Command("pnpm", "test")
+	if _, err := os.Stat(filepath.Join(dir, "pnpm-lock.yaml")); err == nil {
+		return sys.Command("pnpm", "test")
 	}
-	if _, err := os.Stat(filepath.Join(proj.Path, "yarn.lock")); err == nil {
-		return exec.Command("yarn", "test")
+	if _, err := os.Stat(filepath.Join(dir, "yarn.lock")); err == nil {
+		return sys.Command("yarn", "test")
 	}
 	if _, err := os.Stat(packageJSON); err == nil {
-		return exec.Command("npm", "test")
+		return sys.Command("npm", "test")
 	}
 
 	return nil
@@ -386,36 +909,38 @@ func (e *Executor) detectNodeTestCommand(proj *project.Project) *exec.Cmd {
 func (e *Executor) detectPythonTestCommand(proj *project.Project) *exec.Cmd {
 	// Check for pytest
 	if commandExists("pytest") {
-		return exec.Command("pytest")
+		return sys.Command("pytest")
 	}
 	// Fallback to unittest
-	return exec.Command("python", "-m", "unittest", "discover")
+	return sys.Command("python", "-m", "unittest", "discover")
 }
 
 // detectNodeInstallCommand detects the appropriate install command for Node projects
 func (e *Executor) detectNodeInstallCommand(proj *project.Project) *exec.Cmd {
-	if _, err := os.Stat(filepath.Join(proj.Path, "bun.lockb")); err == nil {
-		return exec.Command("bun", "install")
+	dir := proj.WorkPath()
+	if _, err := os.Stat(filepath.Join(dir, "bun.lockb")); err == nil {
+		return sys.Command("bun", "install")
 	}
-	if _, err := os.Stat(filepath.Join(proj.Path, "pnpm-lock.yaml")); err == nil {
-		return exec.Command("pnpm", "install")
+	if _, err := os.Stat(filepath.Join(dir, "pnpm-lock.yaml")); err == nil {
+		return sys.Command("pnpm", "install")
 	}
-	if _, err := os.Stat(filepath.Join(proj.Path, "yarn.lock")); err == nil {
-		return exec.Command("yarn", "install")
+	if _, err := os.Stat(filepath.Join(dir, "yarn.lock")); err == nil {
+		return sys.Command("yarn", "install")
 	}
-	return exec.Command("npm", "install")
+	return sys.Command("npm", "install")
 }
 
 // detectPythonInstallCommand detects the appropriate install command for Python projects
 func (e *Executor) detectPythonInstallCommand(proj *project.Project) *exec.Cmd {
-	if _, err := os.Stat(filepath.Join(proj.Path, "Pipfile")); err == nil {
-		return exec.Command("pipenv", "install")
+	dir := proj.WorkPath()
+	if _, err := os.Stat(filepath.Join(dir, "Pipfile")); err == nil {
+		return sys.Command("pipenv", "install")
 	}
-	if _, err := os.Stat(filepath.Join(proj.Path, "poetry.lock")); err == nil {
-		return exec.Command("poetry", "install")
+	if _, err := os.Stat(filepath.Join(dir, "poetry.lock")); err == nil {
+		return sys.Command("poetry", "install")
 	}
-	if _, err := os.Stat(filepath.Join(proj.Path, "requirements.txt")); err == nil {
-		return exec.Command("pip", "install", "-r", "requirements.txt")
+	if _, err := os.Stat(filepath.Join(dir, "requirements.txt")); err == nil {
+		return sys.Command("pip", "install", "-r", "requirements.txt")
 	}
 	return nil
 }
@@ -448,7 +973,7 @@ func (e *Executor) detectBuildArtifacts(proj *project.Project) []string {
 
 // commandExists checks if a command exists in PATH
 func commandExists(cmd string) bool {
-	_, err := exec.LookPath(cmd)
+	_, err := sys.LookPath(cmd)
 	return err == nil
 }
 
@@ -474,7 +999,13 @@ func (e *Executor) executeDockerAction(actionID string, proj *project.Project) R
 	}
 
 	// Execute the Docker action
-	result := docker.Execute(actionID, proj.Path, proj.Name, dockerInfo)
+	result := docker.Execute(e.containerRuntime(), actionID, proj.Path, proj.Name, dockerInfo)
+	if !result.Success && actionID != "docker-lint" {
+		// Rather than a connection error, say how to start the daemon
+		if hint := e.daemonHint(); hint != "" {
+			return Result{Success: false, Message: hint}
+		}
+	}
 
 	return Result{
 		Success: result.Success,
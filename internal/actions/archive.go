@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+)
+
+// setArchived archives a project, hiding it from the project list, or
+// restores it. Only the state records it; the directory stays where it is.
+func setArchived(proj *project.Project, archived bool) Result {
+	st, err := state.Load()
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to load state: %v", err)}
+	}
+	st.SetArchived(proj.Path, archived)
+	if err := st.Save(); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to save state: %v", err)}
+	}
+	proj.Archived = archived
+
+	if !archived {
+		return Result{Success: true, Message: fmt.Sprintf("Restored %s from the archive", proj.Name)}
+	}
+	return Result{Success: true, Message: fmt.Sprintf("Archived %s; proj --list --archived lists it", proj.Name)}
+}
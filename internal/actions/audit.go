@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/audit"
+	"github.com/s33g/proj/internal/project"
+)
+
+// audit records an action that started at start in the audit log, with the
+// commands plan says it runs. A failure to record is added to the result's
+// message rather than failing the action.
+func (e *Executor) audit(start time.Time, actionID string, plan Plan, proj *project.Project, result Result) Result {
+	commands := make([]string, len(plan.Commands))
+	for i, args := range plan.Commands {
+		commands[i] = QuoteArgs(args)
+	}
+	exitCode := result.ExitCode
+	if !result.Success && exitCode == 0 {
+		exitCode = 1
+	}
+	err := audit.Record(e.config, audit.Entry{
+		Time:       start,
+		Project:    proj.Name,
+		Action:     actionID,
+		Command:    strings.Join(commands, " && "),
+		Dir:        plan.Dir,
+		Success:    result.Success,
+		ExitCode:   exitCode,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	if err != nil {
+		if result.Message != "" {
+			result.Message += "\n\n"
+		}
+		result.Message += "⚠ " + err.Error()
+	}
+	return result
+}
@@ -0,0 +1,243 @@
+package actions
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// ProgressFunc reports backup progress in bytes
+type ProgressFunc func(done, total int64)
+
+// Backup writes a backup of the project to the configured backup directory:
+// a git bundle for repositories with commits, otherwise a tar.gz that skips
+// build artifacts. progress may be nil.
+func (e *Executor) Backup(proj *project.Project, progress ProgressFunc) Result {
+	dir := config.ExpandPath(e.config.Backup.Dir)
+	if dir == "" {
+		return Result{Success: false, Message: "No backup directory configured (set backup.dir)"}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to create backup directory: %v", err)}
+	}
+
+	base := fmt.Sprintf("%s-%s", proj.Name, time.Now().Format("20060102-150405"))
+
+	if proj.IsGitRepo {
+		dest := filepath.Join(dir, base+".bundle")
+		output, err := gitBundle(proj.Path, dest)
+		if err == nil {
+			message := fmt.Sprintf("Created git bundle:\n%s\n\nRestore with: git clone %s", dest, dest)
+			if proj.GitDirty {
+				message += "\n\nNote: uncommitted changes are not included in the bundle"
+			}
+			return Result{Success: true, Message: message}
+		}
+		// Repositories without commits can't be bundled; archive the files instead
+		if !strings.Contains(output, "empty bundle") && !strings.Contains(output, "does not have any commits") {
+			return Result{Success: false, Message: fmt.Sprintf("git bundle failed: %v\n%s", err, output)}
+		}
+	}
+
+	dest := filepath.Join(dir, base+".tar.gz")
+	count, size, err := e.writeArchive(proj, dest, progress)
+	if err != nil {
+		os.Remove(dest)
+		return Result{Success: false, Message: fmt.Sprintf("Backup failed: %v", err)}
+	}
+
+	return Result{
+		Success: true,
+		Message: fmt.Sprintf("Created archive:\n%s\n\n%d files, %s (build artifacts excluded)", dest, count, FormatBytes(size)),
+	}
+}
+
+// gitBundle bundles all refs of the repository into dest
+func gitBundle(projectPath, dest string) (string, error) {
+	cmd := sys.Command("git", "-C", projectPath, "bundle", "create", dest, "--all")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
+
+// writeArchive writes a tar.gz of the project to dest, returning the number of
+// files and bytes archived
+func (e *Executor) writeArchive(proj *project.Project, dest string, progress ProgressFunc) (int, int64, error) {
+	excluded := e.backupExcludes(proj)
+
+	// First pass: collect files so progress has a total to work against
+	var files []string
+	var total int64
+	count := 0
+	err := filepath.WalkDir(proj.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == proj.Path {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(proj.Path, path)
+		if isExcluded(rel, d.Name(), excluded) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+			count++
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	var done int64
+	root := filepath.Dir(proj.Path)
+	for _, path := range files {
+		n, err := addToArchive(tw, root, path)
+		if err != nil {
+			return 0, 0, err
+		}
+		done += n
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, 0, err
+	}
+
+	return count, total, nil
+}
+
+// addToArchive writes a single file, directory or symlink to the archive,
+// returning the number of content bytes written
+func addToArchive(tw *tar.Writer, root, path string) (int64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return 0, err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return 0, err
+	}
+	rel, _ := filepath.Rel(root, path)
+	header.Name = filepath.ToSlash(rel)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return 0, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return 0, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return io.Copy(tw, f)
+}
+
+// backupExcludes returns the patterns skipped when archiving a project
+func (e *Executor) backupExcludes(proj *project.Project) []string {
+	var excludes []string
+	for _, pattern := range append(e.detectBuildArtifacts(proj), e.config.ExcludePatterns...) {
+		if !backedUp(pattern, proj) {
+			excludes = append(excludes, pattern)
+		}
+	}
+	return excludes
+}
+
+// backedUp reports whether a backup archives what a scan exclusion or build
+// artifact pattern names anyway: the .git directory is what makes a backup
+// useful, and vendored sources aren't build artifacts. Composer's vendor
+// directory is reinstalled from composer.lock, so it's left out.
+func backedUp(pattern string, proj *project.Project) bool {
+	switch pattern {
+	case ".git":
+		return true
+	case "vendor":
+		return proj.Language != "PHP"
+	default:
+		return false
+	}
+}
+
+// isExcluded reports whether a path matches an artifact name, glob, or relative path
+func isExcluded(rel, name string, patterns []string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "/") {
+			if rel == pattern {
+				return true
+			}
+			continue
+		}
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatBytes formats a byte count for display (e.g. "12.3 MB")
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestBackup_Archive(t *testing.T) {
+	tmpDir := t.TempDir()
+	projPath := filepath.Join(tmpDir, "webapp")
+	os.MkdirAll(filepath.Join(projPath, "src"), 0o755)
+	os.MkdirAll(filepath.Join(projPath, "node_modules", "left-pad"), 0o755)
+	writeFile(t, filepath.Join(projPath, "package.json"), "{}")
+	writeFile(t, filepath.Join(projPath, "src", "index.js"), "console.log('hi')")
+	writeFile(t, filepath.Join(projPath, "node_modules", "left-pad", "index.js"), "module.exports = 1")
+
+	cfg := config.DefaultConfig()
+	cfg.Backup.Dir = filepath.Join(tmpDir, "backups")
+	executor := NewExecutor(cfg)
+
+	proj := &project.Project{Name: "webapp", Path: projPath, Language: "JavaScript"}
+
+	var lastDone, lastTotal int64
+	result := executor.Backup(proj, func(done, total int64) {
+		lastDone, lastTotal = done, total
+	})
+	if !result.Success {
+		t.Fatalf("Backup failed: %s", result.Message)
+	}
+	if lastTotal == 0 || lastDone != lastTotal {
+		t.Errorf("Expected progress to reach the total, got %d/%d", lastDone, lastTotal)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(cfg.Backup.Dir, "webapp-*.tar.gz"))
+	if len(matches) != 1 {
+		t.Fatalf("Expected one archive, found %v", matches)
+	}
+
+	names := archiveNames(t, matches[0])
+	expected := []string{"webapp/package.json", "webapp/src", "webapp/src/index.js"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Archive contents = %v, want %v", names, expected)
+	}
+}
+
+func TestBackup_KeepsVendor(t *testing.T) {
+	projPath := filepath.Join(t.TempDir(), "api")
+	os.MkdirAll(filepath.Join(projPath, "vendor", "example.com", "lib"), 0o755)
+	os.MkdirAll(filepath.Join(projPath, "bin"), 0o755)
+	writeFile(t, filepath.Join(projPath, "go.mod"), "module api")
+	writeFile(t, filepath.Join(projPath, "vendor", "example.com", "lib", "lib.go"), "package lib")
+	writeFile(t, filepath.Join(projPath, "bin", "api"), "binary")
+
+	cfg := config.DefaultConfig()
+	cfg.Backup.Dir = filepath.Join(t.TempDir(), "backups")
+	result := NewExecutor(cfg).Backup(&project.Project{Name: "api", Path: projPath, Language: "Go"}, nil)
+	if !result.Success {
+		t.Fatalf("Backup failed: %s", result.Message)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(cfg.Backup.Dir, "api-*.tar.gz"))
+	if len(matches) != 1 {
+		t.Fatalf("Expected one archive, found %v", matches)
+	}
+	names := strings.Join(archiveNames(t, matches[0]), ",")
+	if !strings.Contains(names, "api/vendor/example.com/lib/lib.go") || strings.Contains(names, "api/bin") {
+		t.Errorf("Archive contents = %s, want the vendored sources without bin", names)
+	}
+}
+
+func TestBackup_GitBundle(t *testing.T) {
+	if !git.IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+	projPath := filepath.Join(tmpDir, "repo")
+	os.Mkdir(projPath, 0o755)
+	exec.Command("git", "init", projPath).Run()
+	exec.Command("git", "-C", projPath, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", projPath, "config", "user.name", "Test User").Run()
+	writeFile(t, filepath.Join(projPath, "README.md"), "# repo")
+	exec.Command("git", "-C", projPath, "add", ".").Run()
+	if err := exec.Command("git", "-C", projPath, "commit", "-m", "initial").Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Backup.Dir = filepath.Join(tmpDir, "backups")
+
+	proj := &project.Project{Name: "repo", Path: projPath, IsGitRepo: true}
+	result := NewExecutor(cfg).Backup(proj, nil)
+	if !result.Success {
+		t.Fatalf("Backup failed: %s", result.Message)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(cfg.Backup.Dir, "repo-*.bundle"))
+	if len(matches) != 1 {
+		t.Fatalf("Expected one bundle, found %v", matches)
+	}
+	if err := exec.Command("git", "bundle", "verify", matches[0]).Run(); err != nil {
+		t.Errorf("Bundle did not verify: %v", err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		2048:            "2.0 KB",
+		5 * 1024 * 1024: "5.0 MB",
+	}
+	for n, expected := range tests {
+		if got := FormatBytes(n); got != expected {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, expected)
+		}
+	}
+}
+
+func archiveNames(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,244 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/trash"
+)
+
+// Artifact is a build artifact or dependency directory clean can remove
+type Artifact struct {
+	Path    string // Relative to the project
+	Size    int64
+	Confirm bool // Only removed when explicitly selected, e.g. a Go vendor directory
+}
+
+// confirmArtifacts may hold source the project depends on, so they're only
+// removed when explicitly selected
+var confirmArtifacts = map[string][]string{
+	"Go": {"vendor"},
+}
+
+// Artifacts returns the build artifacts present in the project, with their
+// sizes, largest first. Patterns are globs relative to the project.
+func (e *Executor) Artifacts(proj *project.Project) []Artifact {
+	patterns := append(e.detectBuildArtifacts(proj), e.config.Clean.PatternsFor(proj.Name)...)
+	confirm := make(map[string]bool)
+	for _, path := range confirmArtifacts[proj.Language] {
+		confirm[path] = true
+	}
+
+	seen := make(map[string]bool)
+	var artifacts []Artifact
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(proj.Path, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(proj.Path, match)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") || seen[rel] {
+				continue
+			}
+			seen[rel] = true
+			artifacts = append(artifacts, Artifact{
+				Path:    filepath.ToSlash(rel),
+				Size:    project.DirSize(match),
+				Confirm: confirm[filepath.ToSlash(rel)],
+			})
+		}
+	}
+
+	sort.SliceStable(artifacts, func(i, j int) bool { return artifacts[i].Size > artifacts[j].Size })
+	return artifacts
+}
+
+// Clean removes the given artifacts (paths relative to the project). They're
+// moved to the OS trash unless deletePermanently is set or there's no trash,
+// e.g. on Windows or when the project is on another filesystem.
+func (e *Executor) Clean(proj *project.Project, paths []string) Result {
+	if len(paths) == 0 {
+		return Result{Success: true, Message: "Nothing selected to clean"}
+	}
+
+	var removed, deleted, failed []string
+	var freed int64
+	for _, path := range paths {
+		full := filepath.Join(proj.Path, filepath.FromSlash(path))
+		rel, err := filepath.Rel(proj.Path, full)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			failed = append(failed, fmt.Sprintf("%s: outside the project", path))
+			continue
+		}
+		size := project.DirSize(full)
+		trashed, err := e.remove(full)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		if trashed {
+			removed = append(removed, path)
+		} else {
+			deleted = append(deleted, path)
+		}
+		freed += size
+	}
+
+	var parts []string
+	if len(removed) > 0 {
+		parts = append(parts, "Moved to trash: "+strings.Join(removed, ", "))
+	}
+	if len(deleted) > 0 {
+		parts = append(parts, "Removed "+strings.Join(deleted, ", "))
+	}
+	message := fmt.Sprintf("%s, freeing %s", strings.Join(parts, "; "), FormatBytes(freed))
+	if len(parts) == 0 {
+		message = "Nothing was removed"
+	}
+	if len(deleted) > 0 && !e.config.DeletePermanently {
+		message += "\n\nNo trash was available, so these were deleted permanently"
+	}
+	if len(failed) > 0 {
+		message += "\n\nFailed:\n  " + strings.Join(failed, "\n  ")
+	}
+	return Result{Success: len(failed) == 0, Message: message}
+}
+
+// remove moves path to the trash, or deletes it if deletePermanently is set
+// or there's no trash to move it to. It reports whether it was trashed.
+func (e *Executor) remove(path string) (bool, error) {
+	if !e.config.DeletePermanently {
+		_, err := trash.Move(path)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, trash.ErrUnavailable) {
+			return false, err
+		}
+	}
+	return false, os.RemoveAll(path)
+}
+
+// clean removes the project's build artifacts, except those that need
+// confirming, which the TUI's preview lets the user select
+func (e *Executor) clean(proj *project.Project) Result {
+	var paths, kept []string
+	for _, a := range e.Artifacts(proj) {
+		if a.Confirm {
+			kept = append(kept, a.Path)
+			continue
+		}
+		paths = append(paths, a.Path)
+	}
+
+	if len(paths) == 0 {
+		message := "No build artifacts found to clean"
+		if len(kept) > 0 {
+			message += fmt.Sprintf(" (kept %s; select it in the clean preview to remove it)", strings.Join(kept, ", "))
+		}
+		return Result{Success: true, Message: message}
+	}
+
+	result := e.Clean(proj, paths)
+	if len(kept) > 0 {
+		result.Message += fmt.Sprintf("\n\nKept %s; select it in the clean preview to remove it", strings.Join(kept, ", "))
+	}
+	return result
+}
+
+// Reclaimable is the space cleaning a project would free
+type Reclaimable struct {
+	Project   *project.Project
+	Artifacts []Artifact // Without the ones that need confirming
+	Size      int64
+}
+
+// Paths returns the artifact paths, as passed to Clean
+func (r Reclaimable) Paths() []string {
+	paths := make([]string, len(r.Artifacts))
+	for i, a := range r.Artifacts {
+		paths[i] = a.Path
+	}
+	return paths
+}
+
+// Reclaimable measures the build artifacts in every project, leaving out
+// groups, projects with nothing to clean and artifacts that need confirming.
+// The result is sorted by size, largest first.
+func (e *Executor) Reclaimable(projects []*project.Project) []Reclaimable {
+	results := make([]Reclaimable, len(projects))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := Reclaimable{Project: projects[i]}
+				for _, a := range e.Artifacts(projects[i]) {
+					if !a.Confirm {
+						r.Artifacts = append(r.Artifacts, a)
+						r.Size += a.Size
+					}
+				}
+				results[i] = r
+			}
+		}()
+	}
+	for i, p := range projects {
+		if !p.IsGroup {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	reclaimable := make([]Reclaimable, 0, len(results))
+	for _, r := range results {
+		if len(r.Artifacts) > 0 {
+			reclaimable = append(reclaimable, r)
+		}
+	}
+	sort.SliceStable(reclaimable, func(i, j int) bool { return reclaimable[i].Size > reclaimable[j].Size })
+	return reclaimable
+}
+
+// planClean describes what clean would remove
+func (e *Executor) planClean(plan *Plan, proj *project.Project) {
+	artifacts := e.Artifacts(proj)
+	if len(artifacts) == 0 {
+		plan.Notes = append(plan.Notes, "No build artifacts to remove")
+		return
+	}
+
+	var remove, kept []string
+	var total int64
+	for _, a := range artifacts {
+		entry := fmt.Sprintf("%s (%s)", a.Path, FormatBytes(a.Size))
+		if a.Confirm {
+			kept = append(kept, entry)
+			continue
+		}
+		remove = append(remove, entry)
+		total += a.Size
+	}
+	if len(remove) > 0 {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Would remove: %s; %s in total", strings.Join(remove, ", "), FormatBytes(total)))
+		if e.config.DeletePermanently {
+			plan.Notes = append(plan.Notes, "Files would be deleted permanently (deletePermanently is set)")
+		} else {
+			plan.Notes = append(plan.Notes, "Files would be moved to the trash")
+		}
+	}
+	if len(kept) > 0 {
+		plan.Notes = append(plan.Notes, "Would keep unless selected: "+strings.Join(kept, ", "))
+	}
+}
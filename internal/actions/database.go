@@ -0,0 +1,168 @@
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// Database returns the database service of a project's compose files
+func Database(service string, proj *project.Project) (docker.Database, error) {
+	info, err := docker.Detect(proj.Path)
+	if err != nil {
+		return docker.Database{}, fmt.Errorf("failed to detect Docker files: %w", err)
+	}
+	for _, db := range docker.Databases(proj.Path, info.ComposeFiles) {
+		if db.Service == service {
+			return db, nil
+		}
+	}
+	return docker.Database{}, fmt.Errorf("no database service %q in the compose files of %s", service, proj.Name)
+}
+
+// DatabaseShell returns the command that opens a database's client in its
+// container, to run attached to the terminal
+func (e *Executor) DatabaseShell(service string, proj *project.Project) (*exec.Cmd, error) {
+	db, err := Database(service, proj)
+	if err != nil {
+		return nil, err
+	}
+	cmd := sys.Command(e.containerRuntime(), db.ShellArgs()...)
+	cmd.Dir = proj.Path
+	return cmd, nil
+}
+
+// LatestDump returns the newest dump of a database service in the backup
+// directory, or ""
+func (e *Executor) LatestDump(db docker.Database, proj *project.Project) string {
+	pattern := filepath.Join(config.ExpandPath(e.config.Backup.Dir), dumpBase(db, proj)+"-[0-9]*"+db.DumpExt())
+	matches, _ := filepath.Glob(pattern)
+	if len(matches) == 0 {
+		return ""
+	}
+	// Timestamps sort in the order they were taken
+	sort.Strings(matches)
+	return matches[len(matches)-1]
+}
+
+// dumpBase returns the start of the names of a database's dumps
+func dumpBase(db docker.Database, proj *project.Project) string {
+	return proj.Name + "-" + db.Service
+}
+
+// dbShell tells CLI users how to open a database's client, which needs the
+// terminal proj runs in
+func (e *Executor) dbShell(service string, proj *project.Project) Result {
+	db, err := Database(service, proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	return Result{
+		Success: true,
+		Message: fmt.Sprintf("Run this command in your terminal:\ncd %s && %s", proj.Path, QuoteArgs(append([]string{e.containerRuntime()}, db.ShellArgs()...))),
+	}
+}
+
+// dbURL shows how to connect to a database service
+func (e *Executor) dbURL(service string, proj *project.Project) Result {
+	db, err := Database(service, proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	message := db.ConnectionString()
+	if db.Port == 0 {
+		_, port, _ := strings.Cut(db.Address(), ":")
+		message += fmt.Sprintf("\n\n%s doesn't publish a port, so only the other services of the compose project can connect.\n"+
+			"Publish one (e.g. ports: [\"%s:%s\"]) to connect from this machine.", db.Service, port, port)
+	}
+	return Result{Success: true, Message: message}
+}
+
+// dbDump writes a dump of a database service to the backup directory
+func (e *Executor) dbDump(service string, proj *project.Project) Result {
+	db, err := Database(service, proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	dir := config.ExpandPath(e.config.Backup.Dir)
+	if dir == "" {
+		return Result{Success: false, Message: "No backup directory configured (set backup.dir)"}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to create backup directory: %v", err)}
+	}
+
+	dest := filepath.Join(dir, fmt.Sprintf("%s-%s%s", dumpBase(db, proj), time.Now().Format("20060102-150405"), db.DumpExt()))
+	// Dumps hold the data, so only the user can read them
+	file, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to create dump: %v", err)}
+	}
+
+	var stderr bytes.Buffer
+	cmd := sys.Command(e.containerRuntime(), db.DumpArgs()...)
+	cmd.Dir = proj.Path
+	cmd.Stdout = file
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dest)
+		return Result{Success: false, Message: fmt.Sprintf("Dump of %s failed: %v\n%s\n\nIs the service running? Start it with Compose Up.", db.Service, err, strings.TrimSpace(stderr.String()))}
+	}
+
+	size := int64(0)
+	if info, err := os.Stat(dest); err == nil {
+		size = info.Size()
+	}
+	message := fmt.Sprintf("Dumped %s to:\n%s\n\n%s", db.Service, dest, FormatBytes(size))
+	if db.RestoreArgs() != nil {
+		message += ", restore it with Restore Latest Dump"
+	}
+	return Result{Success: true, Message: message}
+}
+
+// dbRestore loads the newest dump of a database service into it
+func (e *Executor) dbRestore(service string, proj *project.Project) Result {
+	db, err := Database(service, proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	args := db.RestoreArgs()
+	if args == nil {
+		return Result{Success: false, Message: fmt.Sprintf("%s dumps can't be restored into a running server; copy the .rdb into its data directory and restart it", db.Kind)}
+	}
+	dump := e.LatestDump(db, proj)
+	if dump == "" {
+		return Result{Success: false, Message: fmt.Sprintf("No dumps of %s in %s yet", db.Service, config.ExpandPath(e.config.Backup.Dir))}
+	}
+
+	file, err := os.Open(dump)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to read dump: %v", err)}
+	}
+	defer func() { _ = file.Close() }()
+
+	var out bytes.Buffer
+	cmd := sys.Command(e.containerRuntime(), args...)
+	cmd.Dir = proj.Path
+	cmd.Stdin = file
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Restore of %s failed: %v\n%s", db.Service, err, strings.TrimSpace(out.String()))}
+	}
+	return Result{Success: true, Message: fmt.Sprintf("Restored %s from:\n%s", db.Service, dump)}
+}
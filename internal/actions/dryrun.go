@@ -0,0 +1,389 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/envfile"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// Plan describes what an action would do, without doing it
+type Plan struct {
+	Dir      string
+	Commands [][]string // Commands in the order they would run
+	Env      []string   // Variables set on top of proj's own environment
+	Notes    []string   // How commands are wrapped, and effects that aren't commands
+}
+
+// String renders the plan for the result view and the CLI
+func (p Plan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Directory: %s\n", p.Dir)
+
+	if len(p.Commands) > 0 {
+		b.WriteString("\nWould run:\n")
+		for _, args := range p.Commands {
+			b.WriteString("  $ " + QuoteArgs(args) + "\n")
+		}
+	}
+
+	b.WriteString("\nEnvironment: inherited from proj")
+	if len(p.Env) > 0 {
+		b.WriteString(", plus:\n")
+		for _, v := range p.Env {
+			b.WriteString("  " + v + "\n")
+		}
+	} else {
+		b.WriteString("\n")
+	}
+
+	if len(p.Notes) > 0 {
+		b.WriteString("\n")
+		for _, note := range p.Notes {
+			b.WriteString(note + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// DryRun describes what running an action would do. command is the action's
+// shell command, if it has one (scripts, Makefile targets).
+func (e *Executor) DryRun(actionID, command string, proj *project.Project) Plan {
+	plan := Plan{Dir: proj.Path}
+
+	if command != "" {
+		args := e.commandArgs(command)
+		if len(args) == 0 {
+			plan.Notes = append(plan.Notes, "Empty command")
+			return plan
+		}
+		e.planCommand(&plan, sys.Command(args[0], args[1:]...), proj)
+		return plan
+	}
+
+	if strings.HasPrefix(actionID, "docker-") || strings.HasPrefix(actionID, "compose-") {
+		info, err := docker.Detect(proj.Path)
+		if err != nil {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("Failed to detect Docker files: %v", err))
+			return plan
+		}
+		if actionID == "docker-lint" {
+			plan.Commands = append(plan.Commands, docker.LintArgs(info))
+			return plan
+		}
+		if args := docker.Args(actionID, proj.Name, info); args != nil {
+			plan.Commands = append(plan.Commands, append([]string{e.containerRuntime()}, args...))
+		}
+		if actionID == "docker-stats" {
+			plan.Notes = append(plan.Notes, "Passes the project's running containers, found with docker ps")
+		}
+		return plan
+	}
+	if strings.HasPrefix(actionID, "scaffold-") {
+		planScaffold(&plan, actionID, proj)
+		return plan
+	}
+	if name, ok := strings.CutPrefix(actionID, "env-open-"); ok {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Opens %s in %s", name, e.config.Editor.Default))
+		return plan
+	}
+	if strings.HasPrefix(actionID, "gowork-") {
+		e.planGoWork(&plan, actionID, proj)
+		return plan
+	}
+	if strings.HasPrefix(actionID, "db-") {
+		e.planDatabase(&plan, actionID, proj)
+		return plan
+	}
+	if port, ok := strings.CutPrefix(actionID, "open-port-"); ok {
+		if u, err := LocalhostURL(port); err != nil {
+			plan.Notes = append(plan.Notes, err.Error())
+		} else {
+			plan.Commands = append(plan.Commands, browserCommand(u).Args)
+		}
+		return plan
+	}
+
+	switch actionID {
+	case "open-editor", "reopen":
+		e.planOpen(&plan, actionID, proj)
+	case "cd":
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Exits and changes the shell to %s", proj.Path))
+	case "git-log":
+		plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "log", "--oneline", "--graph", "--decorate", "-n", "20"})
+	case "git-pull":
+		plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "pull"})
+		plan.Notes = append(plan.Notes, "If the branches have diverged, asks whether to rebase, fast-forward only, merge or abort")
+		if proj.UsesLFS && !lfsInstalled() {
+			plan.Notes = append(plan.Notes, lfsMissing)
+		}
+	case "git-lfs-pull", "git-lfs-prune":
+		plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "lfs", strings.TrimPrefix(actionID, "git-lfs-")})
+	case "git-branch":
+		plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "branch", "--format=%(refname:short)"})
+		plan.Notes = append(plan.Notes, "Then asks which branch to switch to")
+	case "open-issue":
+		issueURL, err := e.IssueURL(proj)
+		if err != nil {
+			plan.Notes = append(plan.Notes, "Can't open the issue: "+err.Error())
+			break
+		}
+		plan.Commands = append(plan.Commands, browserCommand(issueURL).Args)
+	case "git-init":
+		plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "init"})
+	case "git-commit":
+		args := []string{"git", "-C", proj.Path, "commit", "-F", "-"}
+		note := "First asks for the message; commits the staged changes"
+		if !git.HasStaged(proj.Path) {
+			args = append(args, "-a")
+			note = "First asks for the message; nothing is staged, so commits every change to tracked files"
+		}
+		plan.Commands = append(plan.Commands, args)
+		plan.Notes = append(plan.Notes, note)
+	case "git-new-branch":
+		plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "checkout", "-b", e.config.Branches.Pattern})
+		plan.Notes = append(plan.Notes, "First asks for the type and description that fill in the branch name")
+	case "server-start", "server-restart":
+		command := e.DevServerCommand(proj)
+		args := e.commandArgs(command)
+		if len(args) == 0 {
+			plan.Notes = append(plan.Notes, "No dev server script found; set one in devServer.projects")
+			break
+		}
+		if actionID == "server-restart" {
+			plan.Notes = append(plan.Notes, "Stops the running dev server first")
+		}
+		e.planCommand(&plan, sys.Command(args[0], args[1:]...), proj)
+		plan.Notes = append(plan.Notes, "Runs in the background, with its output written to a log, until stopped")
+	case "server-stop":
+		plan.Notes = append(plan.Notes, "Stops the dev server and the processes it started, killing them if they haven't exited after 5 seconds")
+	case "server-logs":
+		plan.Notes = append(plan.Notes, "Shows the end of the dev server's log. Nothing is changed.")
+	case "jvm-refresh-tasks":
+		cmd, err := gradleTasksCommand(proj)
+		if err != nil {
+			plan.Notes = append(plan.Notes, err.Error())
+			break
+		}
+		e.planCommand(&plan, cmd, proj)
+		plan.Notes = append(plan.Notes, "Remembers the tasks for the menu until the build files change")
+	case "run-tests":
+		cmd, err := e.testCommand(proj)
+		if err != nil {
+			plan.Notes = append(plan.Notes, err.Error())
+			break
+		}
+		e.planCommand(&plan, cmd, proj)
+	case "install-deps":
+		e.planInstall(&plan, proj)
+	case "bootstrap":
+		if proj.Language != "Python" || !project.NeedsSetup(proj.Path, proj.Language) {
+			e.planInstall(&plan, proj)
+			break
+		}
+		venv, install := venvCommands(proj)
+		e.planCommand(&plan, venv, proj)
+		e.planCommand(&plan, install, proj)
+	case "clean":
+		e.planClean(&plan, proj)
+	case "backup":
+		dir := config.ExpandPath(e.config.Backup.Dir)
+		if proj.IsGitRepo {
+			plan.Commands = append(plan.Commands, []string{"git", "-C", proj.Path, "bundle", "create", filepath.Join(dir, proj.Name+"-<timestamp>.bundle"), "--all"})
+		} else {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("Would write a tar.gz of the project (build artifacts excluded) to %s", dir))
+		}
+	case "duplicate":
+		plan.Notes = append(plan.Notes, "Asks for a name, then copies the project without .git and build artifacts")
+		renames := DuplicateRenames(proj, "<name>")
+		olds := make([]string, 0, len(renames))
+		for old := range renames {
+			olds = append(olds, old)
+		}
+		sort.Strings(olds)
+		for _, old := range olds {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("Would rename %s to %s", old, renames[old]))
+		}
+	case "archive":
+		plan.Notes = append(plan.Notes, "Hides the project from the project list; the directory isn't moved or changed. Archived projects are listed with a, or proj --list --archived")
+	case "unarchive":
+		plan.Notes = append(plan.Notes, "Shows the project in the project list again")
+	case "generate-gitignore":
+		planGitignore(&plan, proj)
+	case "env-show":
+		plan.Notes = append(plan.Notes, "Lists the keys of the .env files, with their values masked. Nothing is changed.")
+	case "env-create", "env-sync":
+		planEnvSync(&plan, proj)
+	case "scan-secrets":
+		plan.Notes = append(plan.Notes, "Scans the working tree with gitleaks or trufflehog if installed, otherwise with built-in rules. Nothing is changed.")
+	case "audit-deps":
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Audits %s dependencies with the ecosystem's audit tool. Nothing is changed.", proj.Language))
+	default:
+		plan.Notes = append(plan.Notes, "Unknown action: "+actionID)
+	}
+	return plan
+}
+
+// planGoWork describes an action on a Go workspace
+func (e *Executor) planGoWork(plan *Plan, actionID string, proj *project.Project) {
+	if actionID == "gowork-list" {
+		plan.Notes = append(plan.Notes, "Lists the modules go.work uses. Nothing is changed.")
+		return
+	}
+	cmd, mod, err := goWorkCommand(actionID, proj)
+	if err != nil {
+		plan.Notes = append(plan.Notes, err.Error())
+		return
+	}
+	e.planCommand(plan, cmd, mod)
+}
+
+// planDatabase describes a quick action on a compose database service
+func (e *Executor) planDatabase(plan *Plan, actionID string, proj *project.Project) {
+	kind, service, _ := strings.Cut(strings.TrimPrefix(actionID, "db-"), "-")
+	db, err := Database(service, proj)
+	if err != nil {
+		plan.Notes = append(plan.Notes, err.Error())
+		return
+	}
+
+	switch kind {
+	case "shell":
+		plan.Commands = append(plan.Commands, append([]string{e.containerRuntime()}, db.ShellArgs()...))
+		plan.Notes = append(plan.Notes, "Runs attached to the terminal until the client exits")
+	case "url":
+		plan.Notes = append(plan.Notes, "Shows "+db.ConnectionString()+". Nothing is changed.")
+	case "dump":
+		dest := filepath.Join(config.ExpandPath(e.config.Backup.Dir), dumpBase(db, proj)+"-<timestamp>"+db.DumpExt())
+		plan.Commands = append(plan.Commands, append([]string{e.containerRuntime()}, db.DumpArgs()...))
+		plan.Notes = append(plan.Notes, "Writes the output to "+dest)
+	case "restore":
+		args := db.RestoreArgs()
+		if args == nil {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("%s dumps can't be restored into a running server", db.Kind))
+			return
+		}
+		plan.Commands = append(plan.Commands, append([]string{e.containerRuntime()}, args...))
+		if dump := e.LatestDump(db, proj); dump != "" {
+			plan.Notes = append(plan.Notes, "Reads the input from "+dump+", replacing the tables it holds")
+		} else {
+			plan.Notes = append(plan.Notes, "No dumps to restore yet")
+		}
+	default:
+		plan.Notes = append(plan.Notes, "Unknown action: "+actionID)
+	}
+}
+
+// planCommand adds cmd to the plan the way run would run it
+func (e *Executor) planCommand(plan *Plan, cmd *exec.Cmd, proj *project.Project) {
+	cmd, note := e.prepare(cmd, proj)
+	plan.Dir = cmd.Dir
+	plan.Commands = append(plan.Commands, cmd.Args)
+	plan.Env = append(plan.Env, addedEnv(cmd.Env)...)
+	if note != "" {
+		plan.Notes = append(plan.Notes, note)
+	}
+}
+
+// planInstall adds the dependency install command to the plan
+func (e *Executor) planInstall(plan *Plan, proj *project.Project) {
+	cmd, err := e.installCommand(proj)
+	if err != nil {
+		plan.Notes = append(plan.Notes, err.Error())
+		return
+	}
+	e.planCommand(plan, cmd, proj)
+}
+
+// planOpen describes opening the project, which depends on onOpen
+func (e *Executor) planOpen(plan *Plan, actionID string, proj *project.Project) {
+	editor := e.editorFor(proj)
+	if actionID == "reopen" {
+		editor = proj.LastOpenedWith
+	} else if strings.EqualFold(e.config.OnOpen, config.OnOpenCdOnly) {
+		editor = state.OpenedInTerminal
+	}
+
+	switch editor {
+	case "":
+		plan.Notes = append(plan.Notes, "Project has not been opened with proj yet")
+		return
+	case state.OpenedInTerminal:
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Exits and changes the shell to %s", proj.Path))
+		return
+	}
+
+	cmdArgs, err := e.resolveEditor(editor)
+	if err != nil {
+		plan.Notes = append(plan.Notes, err.Error())
+		return
+	}
+	plan.Commands = append(plan.Commands, append(append([]string{}, cmdArgs...), editorPath(cmdArgs[0], proj.Path)))
+	if strings.EqualFold(e.config.OnOpen, config.OnOpenBoth) {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Exits, changes the shell to %s and runs the editor in place of proj", proj.Path))
+	}
+}
+
+// addedEnv returns the variables in env that differ from proj's environment.
+// A nil env inherits everything.
+func addedEnv(env []string) []string {
+	if env == nil {
+		return nil
+	}
+	inherited := make(map[string]bool)
+	for _, v := range os.Environ() {
+		inherited[v] = true
+	}
+	var added []string
+	for _, v := range env {
+		if !inherited[v] {
+			added = append(added, v)
+		}
+	}
+	return added
+}
+
+// QuoteArgs joins arguments into a command line a POSIX shell would split
+// back into the same arguments
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\$`|&;<>()*?[]{}~#!") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// planEnvSync describes creating .env from its example or adding the keys
+// it's missing
+func planEnvSync(plan *Plan, proj *project.Project) {
+	example := envfile.Example(proj.Path)
+	if example == "" {
+		plan.Notes = append(plan.Notes, "No .env.example to create .env from")
+		return
+	}
+	exampleVars, _ := envfile.Parse(filepath.Join(proj.Path, example))
+	vars, err := envfile.Parse(filepath.Join(proj.Path, envfile.Name))
+	if os.IsNotExist(err) {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Would copy %s to %s", example, envfile.Name))
+		return
+	}
+	if missing := envfile.Missing(exampleVars, vars); len(missing) > 0 {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Would add %s from %s to %s", strings.Join(envfile.Keys(missing), ", "), example, envfile.Name))
+	} else {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("%s already sets every key in %s", envfile.Name, example))
+	}
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "yarn.lock"), "")
+	writeFile(t, filepath.Join(dir, "compose.yaml"), "services:\n  db:\n    image: postgres:16\n    ports: [\"5433:5432\"]\n")
+	os.Mkdir(filepath.Join(dir, "node_modules"), 0755)
+	proj := &project.Project{Name: "web", Path: dir, Language: "TypeScript"}
+
+	cfg := config.DefaultConfig()
+	cfg.Container.Runtime = "docker"
+	executor := NewExecutor(cfg)
+
+	tests := []struct {
+		actionID string
+		command  string
+		contains string
+	}{
+		{"run-tests", "", "$ yarn test"},
+		{"install-deps", "", "$ yarn install"},
+		{"npm-lint", "npm run lint -- --fix", "$ npm run lint -- --fix"},
+		{"clean", "", "Would remove: node_modules"},
+		{"cd", "", "changes the shell to " + dir},
+		{"generate-gitignore", "", "Would create .gitignore"},
+		{"open-port-5173", "", "http://localhost:5173"},
+		{"open-port-0", "", `invalid port "0"`},
+		{"db-url-db", "", "Shows postgres://postgres@localhost:5433/postgres"},
+		{"db-dump-db", "", "$ docker compose -f compose.yaml exec -T db pg_dump"},
+		{"db-shell-missing", "", `no database service "missing"`},
+	}
+
+	for _, tt := range tests {
+		plan := executor.DryRun(tt.actionID, tt.command, proj).String()
+		if !strings.Contains(plan, tt.contains) || !strings.Contains(plan, "Directory: "+dir) {
+			t.Errorf("DryRun(%q) = %q, want it to contain %q", tt.actionID, plan, tt.contains)
+		}
+	}
+
+	// Nothing was actually run
+	if _, err := os.Stat(filepath.Join(dir, "node_modules")); err != nil {
+		t.Error("DryRun(clean) removed node_modules")
+	}
+
+	// Containers wrap the command
+	cfg.Container.Languages = map[string]string{"typescript": "node:20"}
+	plan := NewExecutor(cfg).DryRun("run-tests", "", proj)
+	if len(plan.Commands) != 1 || plan.Commands[0][0] != "docker" || !strings.Contains(plan.String(), "node:20 yarn test") {
+		t.Errorf("DryRun in a container = %+v", plan)
+	}
+}
+
+func TestDryRun_LFS(t *testing.T) {
+	orig := lfsInstalled
+	defer func() { lfsInstalled = orig }()
+	lfsInstalled = func() bool { return false }
+
+	proj := &project.Project{Name: "assets", Path: t.TempDir(), IsGitRepo: true, UsesLFS: true}
+	executor := NewExecutor(config.DefaultConfig())
+
+	if plan := executor.DryRun("git-lfs-prune", "", proj); len(plan.Commands) != 1 || !strings.Contains(plan.String(), "lfs prune") {
+		t.Errorf("DryRun(git-lfs-prune) = %+v", plan)
+	}
+	if plan := executor.DryRun("git-pull", "", proj).String(); !strings.Contains(plan, "git-lfs is not installed") {
+		t.Errorf("DryRun(git-pull) should warn that git-lfs is missing, got %q", plan)
+	}
+	if result := executor.Execute("git-lfs-pull", proj); result.Success || result.Message != lfsMissing {
+		t.Errorf("Execute(git-lfs-pull) without git-lfs = %+v", result)
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	got := QuoteArgs([]string{"git", "commit", "-m", "it's done", "", "--format=%(refname)"})
+	want := `git commit -m 'it'\''s done' '' '--format=%(refname)'`
+	if got != want {
+		t.Errorf("QuoteArgs() = %s, want %s", got, want)
+	}
+}
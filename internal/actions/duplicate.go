@@ -0,0 +1,268 @@
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// DuplicateOptions says where a project is copied to and what changes in the copy
+type DuplicateOptions struct {
+	Dest    string            // Directory to create, which must not exist yet
+	InitGit bool              // Start a new git repository in the copy
+	Rename  map[string]string // Old -> new module and package names, applied to manifests
+}
+
+// Manifest names rewritten by Duplicate
+var (
+	goModule    = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+	packageName = regexp.MustCompile(`"name"\s*:\s*"([^"]*)"`)
+	crateName   = regexp.MustCompile(`(?m)^name\s*=\s*"([^"]*)"`)
+	majorSuffix = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// Duplicate copies a project to opts.Dest, leaving out .git and build
+// artifacts, and renames it in go.mod, package.json and Cargo.toml as
+// opts.Rename says. Go imports of a renamed module are rewritten too.
+func (e *Executor) Duplicate(proj *project.Project, opts DuplicateOptions) Result {
+	if _, err := os.Lstat(opts.Dest); err == nil {
+		return Result{Success: false, Message: fmt.Sprintf("%s already exists", opts.Dest)}
+	}
+	if rel, err := filepath.Rel(proj.Path, opts.Dest); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return Result{Success: false, Message: "Can't duplicate a project into itself"}
+	}
+
+	count, err := e.copyProject(proj, opts.Dest)
+	if err != nil {
+		os.RemoveAll(opts.Dest)
+		return Result{Success: false, Message: fmt.Sprintf("Failed to copy %s: %v", proj.Name, err)}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Copied %s to %s\n%d files (.git and build artifacts excluded)", proj.Name, opts.Dest, count)
+
+	renamed, err := renameManifests(opts.Dest, opts.Rename)
+	if err != nil {
+		fmt.Fprintf(&b, "\n\nFailed to rename: %v", err)
+	}
+	if len(renamed) > 0 {
+		b.WriteString("\n\nRenamed:\n  " + strings.Join(renamed, "\n  "))
+	}
+
+	if opts.InitGit {
+		cmd := sys.Command("git", "-C", opts.Dest, "init")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(&b, "\n\ngit init failed: %v\n%s", err, strings.TrimSpace(string(output)))
+			return Result{Success: false, Message: b.String()}
+		}
+		b.WriteString("\n\nInitialized a new git repository")
+	}
+	return Result{Success: true, Message: b.String()}
+}
+
+// copyProject copies the project's files to dest, returning how many were
+// copied. Symlinks are copied as links.
+func (e *Executor) copyProject(proj *project.Project, dest string) (int, error) {
+	excluded := append(e.detectBuildArtifacts(proj), e.config.ExcludePatterns...)
+	excluded = append(excluded, ".git")
+
+	count := 0
+	err := filepath.WalkDir(proj.Path, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(proj.Path, src)
+		if src != proj.Path && isExcluded(rel, d.Name(), excluded) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dest, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&os.ModeSymlink != 0:
+			link, err := os.Readlink(src)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			count++
+			return copyFile(src, target, info.Mode().Perm())
+		}
+		// Sockets, devices and the like aren't worth copying
+		return nil
+	})
+	return count, err
+}
+
+// copyFile copies a regular file's contents and permissions
+func copyFile(src, dest string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// DuplicateRenames returns the renames that give a copy of proj called name
+// its own identity: the last element of the go.mod module path (dropping a
+// major version suffix), the package.json name (keeping its scope) and the
+// Cargo.toml package name
+func DuplicateRenames(proj *project.Project, name string) map[string]string {
+	renames := make(map[string]string)
+	if old := manifestName(filepath.Join(proj.Path, "go.mod"), goModule); old != "" {
+		dir, base := path.Split(old)
+		if majorSuffix.MatchString(base) && dir != "" {
+			dir, _ = path.Split(strings.TrimSuffix(dir, "/"))
+		}
+		renames[old] = dir + name
+	}
+	if old := manifestName(filepath.Join(proj.Path, "package.json"), packageName); old != "" {
+		scope, _, found := strings.Cut(old, "/")
+		if found && strings.HasPrefix(scope, "@") {
+			renames[old] = scope + "/" + name
+		} else {
+			renames[old] = name
+		}
+	}
+	if old := manifestName(filepath.Join(proj.Path, "Cargo.toml"), crateName); old != "" {
+		renames[old] = name
+	}
+	for old, name := range renames {
+		if old == name {
+			delete(renames, old)
+		}
+	}
+	return renames
+}
+
+// manifestName returns the first name re finds in a manifest file
+func manifestName(file string, re *regexp.Regexp) string {
+	data, err := sys.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	if m := re.FindSubmatch(data); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// renameManifests applies renames to the manifests in dir, returning what
+// was renamed
+func renameManifests(dir string, renames map[string]string) ([]string, error) {
+	if len(renames) == 0 {
+		return nil, nil
+	}
+
+	var renamed []string
+	for _, manifest := range []struct {
+		file string
+		re   *regexp.Regexp
+	}{
+		{"go.mod", goModule},
+		{"package.json", packageName},
+		{"Cargo.toml", crateName},
+	} {
+		old, updated, err := renameFirst(filepath.Join(dir, manifest.file), manifest.re, renames)
+		if err != nil {
+			return renamed, err
+		}
+		if updated == "" {
+			continue
+		}
+		renamed = append(renamed, fmt.Sprintf("%s → %s in %s", old, updated, manifest.file))
+
+		// The module's own imports have to follow it
+		if manifest.file == "go.mod" {
+			n, err := rewriteImports(dir, old, updated)
+			if err != nil {
+				return renamed, err
+			}
+			if n > 0 {
+				renamed = append(renamed, fmt.Sprintf("imports of %s in %d Go files", old, n))
+			}
+		}
+	}
+	return renamed, nil
+}
+
+// renameFirst replaces the first name re matches in file if renames has a new
+// name for it, returning the old and new names
+func renameFirst(file string, re *regexp.Regexp, renames map[string]string) (string, string, error) {
+	data, err := sys.ReadFile(file)
+	if os.IsNotExist(err) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+
+	loc := re.FindSubmatchIndex(data)
+	if loc == nil {
+		return "", "", nil
+	}
+	old := string(data[loc[2]:loc[3]])
+	updated, ok := renames[old]
+	if !ok {
+		return "", "", nil
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:loc[2]])
+	out.WriteString(updated)
+	out.Write(data[loc[3]:])
+	return old, updated, os.WriteFile(file, out.Bytes(), 0644)
+}
+
+// rewriteImports points imports of module old and its packages at module
+// updated in the Go files below dir, returning how many files changed
+func rewriteImports(dir, old, updated string) (int, error) {
+	replacer := strings.NewReplacer(`"`+old+`"`, `"`+updated+`"`, `"`+old+`/`, `"`+updated+`/`)
+	changed := 0
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(file, ".go") || d.Type()&os.ModeSymlink != 0 {
+			return err
+		}
+		data, err := sys.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if rewritten := replacer.Replace(string(data)); rewritten != string(data) {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			changed++
+			return os.WriteFile(file, []byte(rewritten), info.Mode().Perm())
+		}
+		return nil
+	})
+	return changed, err
+}
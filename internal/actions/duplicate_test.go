@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestDuplicate(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "api")
+	for _, dir := range []string{".git", "bin", "internal/store"} {
+		if err := os.MkdirAll(filepath.Join(src, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(src, "go.mod"), "module github.com/acme/api/v2\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(src, "main.go"), "package main\n\nimport \"github.com/acme/api/v2/internal/store\"\n\nvar _ = store.New\n")
+	writeFile(t, filepath.Join(src, "internal/store/store.go"), "package store\n\nfunc New() {}\n")
+	writeFile(t, filepath.Join(src, "package.json"), "{\n  \"name\": \"@acme/api\",\n  \"dependencies\": {\"left-pad\": {\"name\": \"left-pad\"}}\n}\n")
+	writeFile(t, filepath.Join(src, ".git/HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(src, "bin/api"), "binary")
+	if err := os.Symlink("main.go", filepath.Join(src, "link.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	proj := &project.Project{Name: "api", Path: src, Language: "Go"}
+	renames := DuplicateRenames(proj, "billing")
+	want := map[string]string{"github.com/acme/api/v2": "github.com/acme/billing", "@acme/api": "@acme/billing"}
+	if !reflect.DeepEqual(renames, want) {
+		t.Fatalf("DuplicateRenames() = %v, want %v", renames, want)
+	}
+
+	dest := filepath.Join(root, "billing")
+	result := NewExecutor(config.DefaultConfig()).Duplicate(proj, DuplicateOptions{Dest: dest, Rename: renames})
+	if !result.Success {
+		t.Fatalf("Duplicate() failed: %s", result.Message)
+	}
+
+	for _, path := range []string{".git", "bin"} {
+		if _, err := os.Stat(filepath.Join(dest, path)); !os.IsNotExist(err) {
+			t.Errorf("%s should not have been copied", path)
+		}
+	}
+	if link, err := os.Readlink(filepath.Join(dest, "link.go")); err != nil || link != "main.go" {
+		t.Errorf("link.go = %q, %v; want a link to main.go", link, err)
+	}
+
+	contains := map[string]string{
+		"go.mod":       "module github.com/acme/billing\n",
+		"main.go":      `"github.com/acme/billing/internal/store"`,
+		"package.json": `"name": "@acme/billing"`,
+	}
+	for file, text := range contains {
+		data, err := os.ReadFile(filepath.Join(dest, file))
+		if err != nil || !strings.Contains(string(data), text) {
+			t.Errorf("%s = %q, want it to contain %q", file, data, text)
+		}
+	}
+	// Only the package's own name changes
+	if data, _ := os.ReadFile(filepath.Join(dest, "package.json")); !strings.Contains(string(data), `"name": "left-pad"`) {
+		t.Errorf("package.json dependency was renamed: %s", data)
+	}
+	// The original is untouched
+	if data, _ := os.ReadFile(filepath.Join(src, "go.mod")); !strings.Contains(string(data), "github.com/acme/api/v2") {
+		t.Errorf("original go.mod changed: %s", data)
+	}
+
+	if result := NewExecutor(config.DefaultConfig()).Duplicate(proj, DuplicateOptions{Dest: dest}); result.Success {
+		t.Error("Duplicate() onto an existing directory should fail")
+	}
+	if result := NewExecutor(config.DefaultConfig()).Duplicate(proj, DuplicateOptions{Dest: filepath.Join(src, "copy")}); result.Success {
+		t.Error("Duplicate() into the project itself should fail")
+	}
+}
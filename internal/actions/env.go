@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/s33g/proj/internal/envfile"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/results"
+)
+
+// envShow lists the variables of a project's .env files with their values
+// masked, and how .env differs from its example
+func (e *Executor) envShow(proj *project.Project) Result {
+	files := envfile.Files(proj.Path)
+	if len(files) == 0 {
+		return Result{Success: false, Message: "No .env files in " + proj.Name}
+	}
+
+	var b strings.Builder
+	parsed := make(map[string][]envfile.Var)
+	for _, name := range files {
+		vars, err := envfile.Parse(filepath.Join(proj.Path, name))
+		if err != nil {
+			fmt.Fprintf(&b, "%s: %v\n\n", name, err)
+			continue
+		}
+		parsed[name] = vars
+		fmt.Fprintf(&b, "%s (%d variables)\n", name, len(vars))
+		for _, v := range vars {
+			fmt.Fprintf(&b, "  %s=%s\n", v.Key, envfile.Mask(v.Value))
+		}
+		b.WriteString("\n")
+	}
+
+	if example := envfile.Example(proj.Path); example != "" {
+		env, hasEnv := parsed[envfile.Name]
+		switch missing := envfile.Missing(parsed[example], env); {
+		case !hasEnv:
+			fmt.Fprintf(&b, "No %s yet: Create .env from %s copies it\n", envfile.Name, example)
+		case len(missing) > 0:
+			fmt.Fprintf(&b, "Missing from %s (in %s): %s\n", envfile.Name, example, strings.Join(envfile.Keys(missing), ", "))
+		default:
+			fmt.Fprintf(&b, "%s sets every key in %s\n", envfile.Name, example)
+		}
+		if extra := envfile.Missing(env, parsed[example]); hasEnv && len(extra) > 0 {
+			fmt.Fprintf(&b, "Not in %s: %s\n", example, strings.Join(envfile.Keys(extra), ", "))
+		}
+	}
+	return Result{Success: true, Message: strings.TrimRight(b.String(), "\n")}
+}
+
+// envSync creates .env from the project's example, or adds the keys it's
+// missing
+func (e *Executor) envSync(proj *project.Project) Result {
+	example := envfile.Example(proj.Path)
+	if example == "" {
+		return Result{Success: false, Message: "No .env.example to create .env from"}
+	}
+	added, created, err := envfile.Sync(proj.Path)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to update %s: %v", envfile.Name, err)}
+	}
+	switch {
+	case created:
+		return Result{Success: true, Message: fmt.Sprintf("Created %s from %s with %d keys: %s\n\nFill in their values with Edit .env.",
+			envfile.Name, example, len(added), strings.Join(envfile.Keys(added), ", "))}
+	case len(added) == 0:
+		return Result{Success: true, Message: fmt.Sprintf("%s already sets every key in %s", envfile.Name, example)}
+	default:
+		return Result{Success: true, Message: fmt.Sprintf("Added %d keys from %s to %s: %s\n\nFill in their values with Edit .env.",
+			len(added), example, envfile.Name, strings.Join(envfile.Keys(added), ", "))}
+	}
+}
+
+// envOpen opens one of the project's .env files in the editor
+func (e *Executor) envOpen(name string, proj *project.Project) Result {
+	if !slices.Contains(envfile.Files(proj.Path), name) {
+		return Result{Success: false, Message: fmt.Sprintf("No %s in %s", name, proj.Name)}
+	}
+	return e.OpenLocation(results.Location{File: filepath.Join(proj.Path, name), Line: 1})
+}
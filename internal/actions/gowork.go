@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/results"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// goWork runs an action on a Go workspace: listing its modules, syncing it,
+// or building or testing one of its modules
+func (e *Executor) goWork(actionID string, proj *project.Project) Result {
+	if actionID == "gowork-list" {
+		return goWorkList(proj)
+	}
+
+	cmd, mod, err := goWorkCommand(actionID, proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	output, err := e.run(cmd, mod)
+
+	if strings.HasPrefix(actionID, "gowork-test-") {
+		return testResult(output, err, results.GoTestJSON)
+	}
+	if err != nil {
+		return Result{
+			Success:  false,
+			Message:  fmt.Sprintf("Command failed: %v\n\n%s", err, output),
+			ExitCode: exitCode(err),
+		}
+	}
+	if output == "" {
+		output = "Command completed successfully"
+	}
+	return Result{Success: true, Message: output}
+}
+
+// goWorkCommand returns the command of a Go workspace action, with a copy of
+// the project whose workdir is the directory it runs in
+func goWorkCommand(actionID string, proj *project.Project) (*exec.Cmd, *project.Project, error) {
+	mod := *proj
+	if actionID == "gowork-sync" {
+		// go work sync updates the workspace from its root
+		mod.Workdir = ""
+		return sys.Command("go", "work", "sync"), &mod, nil
+	}
+
+	var args []string
+	dir, ok := strings.CutPrefix(actionID, "gowork-build-")
+	if ok {
+		args = []string{"build", "./..."}
+	} else if dir, ok = strings.CutPrefix(actionID, "gowork-test-"); ok {
+		args = []string{"test", "-json", "./..."}
+	} else {
+		return nil, nil, fmt.Errorf("Unknown action: %s", actionID)
+	}
+	if !slices.Contains(project.GoWorkModules(proj.Path), dir) {
+		return nil, nil, fmt.Errorf("no module %q in the go.work of %s", dir, proj.Name)
+	}
+	mod.Workdir = filepath.FromSlash(dir)
+	return sys.Command("go", args...), &mod, nil
+}
+
+// goWorkList lists the modules of a Go workspace with their module paths
+func goWorkList(proj *project.Project) Result {
+	modules := project.GoWorkModules(proj.Path)
+	if len(modules) == 0 {
+		return Result{Success: false, Message: "go.work doesn't use any modules"}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d modules in go.work:\n", len(modules))
+	for _, dir := range modules {
+		modPath := project.GoModulePath(filepath.Join(proj.Path, filepath.FromSlash(dir)))
+		if modPath == "" {
+			modPath = "(no go.mod)"
+		}
+		fmt.Fprintf(&b, "  %-20s %s\n", dir, modPath)
+	}
+	return Result{Success: true, Message: strings.TrimRight(b.String(), "\n")}
+}
+
+// goWorkTestPatterns returns the package patterns that test every module of
+// the Go workspace at path, or nil if it isn't one
+func goWorkTestPatterns(path string) []string {
+	var patterns []string
+	for _, dir := range project.GoWorkModules(path) {
+		if dir = filepath.ToSlash(filepath.Clean(dir)); dir == "." {
+			patterns = append(patterns, "./...")
+		} else {
+			patterns = append(patterns, "./"+dir+"/...")
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+	"github.com/s33g/proj/internal/wsl"
+)
+
+// IssueURL returns the tracker page of the issue the project's branch names,
+// from issues.url. #123 issues without a URL configured link to the issues
+// of the origin remote, as on GitHub, GitLab and Gitea.
+func (e *Executor) IssueURL(proj *project.Project) (string, error) {
+	key := git.IssueKey(proj.GitBranch)
+	if key == "" {
+		return "", fmt.Errorf("branch %s doesn't name an issue", proj.GitBranch)
+	}
+
+	if tmpl := e.config.Issues.URLFor(proj.Name); tmpl != "" {
+		return strings.ReplaceAll(tmpl, "{key}", url.PathEscape(strings.TrimPrefix(key, "#"))), nil
+	}
+	if number, ok := strings.CutPrefix(key, "#"); ok {
+		remote, err := git.Origin(proj.Path)
+		if err != nil {
+			return "", fmt.Errorf("can't link %s: %w", key, err)
+		}
+		return remote.WebURL() + "/issues/" + number, nil
+	}
+	return "", fmt.Errorf("set issues.url in the config to open %s, e.g. https://acme.atlassian.net/browse/{key}", key)
+}
+
+// openIssue opens the issue the project's branch names in the browser
+func (e *Executor) openIssue(proj *project.Project) Result {
+	issueURL, err := e.IssueURL(proj)
+	if err != nil {
+		return Result{Success: false, Message: "Couldn't open the issue: " + err.Error()}
+	}
+	if err := browserCommand(issueURL).Start(); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to open %s: %v", issueURL, err)}
+	}
+	return Result{Success: true, Message: "Opened " + issueURL}
+}
+
+// browserCommand returns the command that opens a URL in the default browser
+func browserCommand(u string) *exec.Cmd {
+	switch {
+	case runtime.GOOS == "darwin":
+		return sys.Command("open", u)
+	case runtime.GOOS == "windows":
+		return sys.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	case wsl.IsWSL():
+		if _, err := sys.LookPath("wslview"); err == nil {
+			return sys.Command("wslview", u)
+		}
+		return sys.Command("cmd.exe", "/c", "start", "", strings.ReplaceAll(u, "&", "^&"))
+	default:
+		return sys.Command("xdg-open", u)
+	}
+}
@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/s33g/proj/internal/jvm"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// gradleTasksCommand returns the command that lists a Gradle build's tasks
+func gradleTasksCommand(proj *project.Project) (*exec.Cmd, error) {
+	if jvm.BuildTool(proj.WorkPath()) != jvm.Gradle {
+		return nil, fmt.Errorf("%s isn't built with Gradle", proj.Name)
+	}
+	return sys.Command(jvm.Command(jvm.Gradle, proj.WorkPath()), jvm.GradleTasksArgs...), nil
+}
+
+// refreshTasks lists a Gradle build's tasks and caches them for the menu
+func (e *Executor) refreshTasks(proj *project.Project) Result {
+	cmd, err := gradleTasksCommand(proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+
+	output, err := e.run(cmd, proj)
+	if err != nil {
+		return Result{
+			Success:  false,
+			Message:  fmt.Sprintf("Listing tasks failed: %v\n\n%s", err, output),
+			ExitCode: exitCode(err),
+		}
+	}
+
+	tasks := jvm.ParseGradleTasks(output)
+	if len(tasks) == 0 {
+		return Result{Success: false, Message: "No tasks found in the output of gradle tasks:\n\n" + output}
+	}
+	if err := jvm.SaveTasks(proj.WorkPath(), tasks); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to cache the tasks: %v", err)}
+	}
+	return Result{Success: true, Message: fmt.Sprintf("Found %d Gradle tasks; they're in the Gradle Tasks menu until the build files change", len(tasks))}
+}
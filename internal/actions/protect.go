@@ -0,0 +1,133 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+// commandSeparators split a shell command line into separate commands
+var commandSeparators = map[string]bool{"&&": true, "||": true, ";": true, "|": true}
+
+// ProtectedBranchWarning describes how command would modify one of the
+// protected branches (actions.protectedBranches): committing to one,
+// force-pushing one or deleting one. It returns "" for anything else.
+func (e *Executor) ProtectedBranchWarning(command string, proj *project.Project) string {
+	if !proj.IsGitRepo || len(e.config.Actions.ProtectedBranches) == 0 {
+		return ""
+	}
+
+	for _, args := range splitCommands(ParseCommand(command)) {
+		if warning := e.gitWarning(args, proj.GitBranch); warning != "" {
+			return warning
+		}
+	}
+	return ""
+}
+
+// splitCommands splits parsed arguments at &&, ||, ; and |
+func splitCommands(args []string) [][]string {
+	var commands [][]string
+	var current []string
+	for _, arg := range args {
+		trimmed := strings.TrimSuffix(arg, ";")
+		if commandSeparators[arg] {
+			commands = append(commands, current)
+			current = nil
+			continue
+		}
+		if trimmed != "" {
+			current = append(current, trimmed)
+		}
+		if trimmed != arg {
+			commands = append(commands, current)
+			current = nil
+		}
+	}
+	return append(commands, current)
+}
+
+// gitWarning checks a single git command run on currentBranch
+func (e *Executor) gitWarning(args []string, currentBranch string) string {
+	// Skip environment assignments like GIT_AUTHOR_NAME=x
+	for len(args) > 0 && strings.Contains(args[0], "=") {
+		args = args[1:]
+	}
+	if len(args) == 0 || filepath.Base(args[0]) != "git" {
+		return ""
+	}
+
+	// Skip git's own options (-C dir, -c key=value, --no-pager, ...)
+	args = args[1:]
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if args[0] == "-C" || args[0] == "-c" {
+			args = args[1:]
+		}
+		if len(args) > 0 {
+			args = args[1:]
+		}
+	}
+	if len(args) == 0 {
+		return ""
+	}
+
+	subcommand, options, positional := args[0], map[string]bool{}, []string{}
+	for _, arg := range args[1:] {
+		if strings.HasPrefix(arg, "-") {
+			name, _, _ := strings.Cut(arg, "=")
+			options[name] = true
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	protected := e.config.Actions.IsProtected
+
+	switch subcommand {
+	case "commit":
+		if protected(currentBranch) {
+			return fmt.Sprintf("commits to the protected branch %s", currentBranch)
+		}
+
+	case "push":
+		deleting := options["-d"] || options["--delete"]
+		forcing := options["-f"] || options["--force"] || options["--force-with-lease"]
+
+		// git push [remote] [refspec...]; without refspecs the current branch is pushed
+		refspecs := positional
+		if len(refspecs) > 0 {
+			refspecs = refspecs[1:]
+		}
+		if len(refspecs) == 0 && !deleting {
+			refspecs = []string{currentBranch}
+		}
+		for _, refspec := range refspecs {
+			force := forcing || strings.HasPrefix(refspec, "+")
+			src, dst, hasDst := strings.Cut(strings.TrimPrefix(refspec, "+"), ":")
+			if !hasDst {
+				dst = src
+			}
+			dst = strings.TrimPrefix(dst, "refs/heads/")
+			if !protected(dst) {
+				continue
+			}
+			if deleting || (hasDst && src == "") {
+				return fmt.Sprintf("deletes the protected branch %s on the remote", dst)
+			}
+			if force {
+				return fmt.Sprintf("force-pushes the protected branch %s", dst)
+			}
+		}
+
+	case "branch":
+		if options["-d"] || options["-D"] || options["--delete"] {
+			for _, name := range positional {
+				if protected(name) {
+					return fmt.Sprintf("deletes the protected branch %s", name)
+				}
+			}
+		}
+	}
+	return ""
+}
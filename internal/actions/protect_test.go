@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestProtectedBranchWarning(t *testing.T) {
+	executor := NewExecutor(config.DefaultConfig())
+	onMain := &project.Project{Name: "api", IsGitRepo: true, GitBranch: "main"}
+	onFeature := &project.Project{Name: "api", IsGitRepo: true, GitBranch: "feature/login"}
+
+	tests := []struct {
+		command  string
+		proj     *project.Project
+		contains string
+	}{
+		{`git commit -am "wip"`, onMain, "commits to the protected branch main"},
+		{`git add . && git -C . commit -m wip`, onMain, "commits to the protected branch main"},
+		{`git commit -m wip`, onFeature, ""},
+		{`git push --force`, onMain, "force-pushes the protected branch main"},
+		{`git push -f origin release/1.2`, onFeature, "force-pushes the protected branch release/1.2"},
+		{`git push origin +HEAD:master`, onFeature, "force-pushes the protected branch master"},
+		{`git push --force`, onFeature, ""},
+		{`git push origin main`, onFeature, ""},
+		{`git push origin --delete main`, onFeature, "deletes the protected branch main on the remote"},
+		{`git push origin :main`, onFeature, "deletes the protected branch main on the remote"},
+		{`git branch -D master`, onFeature, "deletes the protected branch master"},
+		{`git branch -d feature/old`, onMain, ""},
+		{`make release; git status`, onMain, ""},
+	}
+
+	for _, tt := range tests {
+		got := executor.ProtectedBranchWarning(tt.command, tt.proj)
+		if (tt.contains == "") != (got == "") || !strings.Contains(got, tt.contains) {
+			t.Errorf("ProtectedBranchWarning(%q on %s) = %q, want %q", tt.command, tt.proj.GitBranch, got, tt.contains)
+		}
+	}
+
+	// An empty list turns the guardrails off
+	cfg := config.DefaultConfig()
+	cfg.Actions.ProtectedBranches = nil
+	if got := NewExecutor(cfg).ProtectedBranchWarning("git commit -m wip", onMain); got != "" {
+		t.Errorf("Expected no warning without protected branches, got %q", got)
+	}
+}
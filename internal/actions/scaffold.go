@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/language"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/scaffold"
+)
+
+// scaffoldData is what templates know about a project
+func scaffoldData(proj *project.Project) scaffold.Data {
+	return scaffold.Data{
+		Project: filepath.Base(proj.Path),
+		Author:  git.UserName(proj.Path),
+		Year:    time.Now().Year(),
+	}
+}
+
+// scaffoldFile renders the file a scaffold-* action adds to the project
+func scaffoldFile(actionID string, proj *project.Project) (scaffold.File, error) {
+	data := scaffoldData(proj)
+
+	switch {
+	case actionID == "scaffold-editorconfig":
+		return scaffold.EditorConfig(data)
+	case actionID == "scaffold-ci":
+		return scaffold.CI(proj.Language, data)
+	case strings.HasPrefix(actionID, "scaffold-license-"):
+		return scaffold.License(strings.TrimPrefix(actionID, "scaffold-license-"), data)
+	default:
+		return scaffold.File{}, fmt.Errorf("unknown action: %s", actionID)
+	}
+}
+
+// scaffold writes a .gitignore, LICENSE, .editorconfig or CI workflow into
+// the project, leaving existing files alone
+func (e *Executor) scaffold(actionID string, proj *project.Project) Result {
+	f, err := scaffoldFile(actionID, proj)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	if err := scaffold.Write(proj.Path, f); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to write %s: %v", f.Path, err)}
+	}
+	return Result{Success: true, Message: "Created " + f.Path}
+}
+
+// planScaffold describes what a scaffold-* action would write
+func planScaffold(plan *Plan, actionID string, proj *project.Project) {
+	f, err := scaffoldFile(actionID, proj)
+	switch {
+	case err != nil:
+		plan.Notes = append(plan.Notes, err.Error())
+	case scaffold.Exists(proj.Path, f.Path):
+		plan.Notes = append(plan.Notes, f.Path+" already exists; nothing would be written")
+	default:
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Would write %s (%d bytes)", f.Path, len(f.Content)))
+	}
+}
+
+// GitignorePlan works out the .gitignore for the languages detected in the
+// project and what it would add to the existing one
+func GitignorePlan(proj *project.Project) (scaffold.GitignorePlan, error) {
+	languages, err := language.DetectAll(proj.Path)
+	if err != nil {
+		return scaffold.GitignorePlan{}, err
+	}
+	return scaffold.PlanGitignore(proj.Path, languages, proj.HasDockerfile || proj.HasCompose, scaffoldData(proj))
+}
+
+// generateGitignore writes a .gitignore, or merges the missing entries into
+// the existing one
+func (e *Executor) generateGitignore(proj *project.Project) Result {
+	plan, err := GitignorePlan(proj)
+	if err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to generate .gitignore: %v", err)}
+	}
+	if plan.Exists && len(plan.Added) == 0 {
+		return Result{Success: true, Message: ".gitignore already has every suggested entry"}
+	}
+	if err := scaffold.WriteGitignore(proj.Path, plan); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to write .gitignore: %v", err)}
+	}
+	if plan.Exists {
+		return Result{Success: true, Message: fmt.Sprintf("Added %d entries to .gitignore:\n\n%s", len(plan.Added), strings.Join(plan.Added, "\n"))}
+	}
+	return Result{Success: true, Message: fmt.Sprintf("Created .gitignore with %d entries", len(plan.Added))}
+}
+
+// planGitignore describes what generating the .gitignore would do
+func planGitignore(plan *Plan, proj *project.Project) {
+	gi, err := GitignorePlan(proj)
+	switch {
+	case err != nil:
+		plan.Notes = append(plan.Notes, err.Error())
+	case gi.Exists && len(gi.Added) == 0:
+		plan.Notes = append(plan.Notes, ".gitignore already has every suggested entry; nothing would be written")
+	case gi.Exists:
+		plan.Notes = append(plan.Notes, "Would add to .gitignore: "+strings.Join(gi.Added, ", "))
+	default:
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Would create .gitignore with %d entries", len(gi.Added)))
+	}
+}
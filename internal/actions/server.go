@@ -0,0 +1,61 @@
+package actions
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/s33g/proj/internal/procs"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/scripts"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// DevServerCommand returns the command that runs a project's dev server: the
+// one set in devServer.projects, or the first detected script named in
+// devServer.scripts. It returns "" if the project has neither.
+func (e *Executor) DevServerCommand(proj *project.Project) string {
+	if command := e.config.DevServer.CommandFor(proj.Name); command != "" {
+		return command
+	}
+	detected := scripts.Detect(proj.WorkPath(), proj.Language)
+	for _, name := range e.config.DevServer.Scripts {
+		for _, s := range detected {
+			if s.Name == name {
+				return s.Command
+			}
+		}
+	}
+	return ""
+}
+
+// StartServer starts command in the background in the project, inside its
+// container or development environment like other actions, and returns its
+// PID. Its output goes to logPath.
+func (e *Executor) StartServer(command string, proj *project.Project, logPath string) (int, error) {
+	args := e.commandArgs(command)
+	if len(args) == 0 {
+		return 0, fmt.Errorf("empty command")
+	}
+	cmd, _ := e.prepare(sys.Command(args[0], args[1:]...), proj)
+	return procs.Start(cmd, logPath)
+}
+
+// openPort opens http://localhost:port in the browser
+func (e *Executor) openPort(port string) Result {
+	u, err := LocalhostURL(port)
+	if err != nil {
+		return Result{Success: false, Message: err.Error()}
+	}
+	if err := browserCommand(u).Start(); err != nil {
+		return Result{Success: false, Message: fmt.Sprintf("Failed to open %s: %v", u, err)}
+	}
+	return Result{Success: true, Message: "Opened " + u}
+}
+
+// LocalhostURL returns the URL of a port on this machine
+func LocalhostURL(port string) (string, error) {
+	if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+		return "", fmt.Errorf("invalid port %q", port)
+	}
+	return "http://localhost:" + port, nil
+}
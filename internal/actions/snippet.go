@@ -0,0 +1,36 @@
+package actions
+
+import (
+	"strings"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+// SnippetActionID returns the action ID of a snippet. IDs are derived from
+// the name so history entries keep pointing at the same snippet.
+func SnippetActionID(s config.Snippet) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s.Name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return "snippet-" + strings.TrimSuffix(b.String(), "-")
+}
+
+// ExpandSnippet replaces the placeholders in a snippet's command with the
+// project's values, quoted so they stay single arguments
+func ExpandSnippet(command string, proj *project.Project) string {
+	return strings.NewReplacer(
+		"{name}", QuoteArgs([]string{proj.Name}),
+		"{path}", QuoteArgs([]string{proj.Path}),
+		"{language}", QuoteArgs([]string{proj.Language}),
+		"{branch}", QuoteArgs([]string{proj.GitBranch}),
+	).Replace(command)
+}
@@ -1,20 +1,38 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/audit"
+	"github.com/s33g/proj/internal/bulk"
 	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/docker"
 	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/hooks"
 	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/results"
+	"github.com/s33g/proj/internal/scaffold"
+	"github.com/s33g/proj/internal/scripts"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/toolchain"
 	"github.com/s33g/proj/internal/tui"
 	"github.com/s33g/proj/internal/tui/views"
 	"github.com/s33g/proj/pkg/plugin"
@@ -33,36 +51,120 @@ const (
 	ViewResult
 	ViewBranches
 	ViewConfirmStash
+	ViewScanIssues
+	ViewEachPrompt
+	ViewHistory
+	ViewGitignore
+	ViewClean
+	ViewStats
+	ViewConfirmProtected
+	ViewPullStrategy
+	ViewPlugins
+	ViewPluginSettings
+	ViewWorkspace
+	ViewNewBranch
+	ViewCommit
+	ViewDockerBuild
+	ViewConfirmDaemon
+	ViewError
+	ViewEditCommand
+	ViewConfirmURL
 )
 
 // Model is the main application model
 type Model struct {
-	config          *config.Config
-	pluginRegistry  *plugin.Registry
-	view            View
-	projects        []*project.Project
-	selectedProject *project.Project
-	selectedGroup   *project.Project   // Current group being viewed
-	groupProjects   []*project.Project // Projects within the selected group
-	projectList     views.ProjectListModel
-	groupList       views.ProjectListModel
-	actionMenu      views.ActionMenuModel
-	submenuStack    []views.ActionMenuModel // Stack for nested submenus
-	newProject      views.NewProjectModel
-	resultViewport  viewport.Model
-	resultTitle     string
-	resultSuccess   bool
-	branchList      list.Model
-	targetBranch    string
-	keys            tui.KeyMap
-	currentSortBy   project.SortBy // Current sort order
-	width           int
-	height          int
-	err             error
-	message         string
-	ready           bool
-	cdPath          string   // Path to change to on exit
-	execCmd         []string // Command to exec on exit
+	config            *config.Config
+	state             *state.State
+	pluginRegistry    *plugin.Registry
+	view              View
+	navStack          []View // Views back returns to, the last one first
+	projects          []*project.Project
+	diagnostics       []project.Diagnostic // Entries skipped during the last scan
+	selectedProject   *project.Project
+	projectInfo       views.ProjectInfo  // Toolchains, activity and contributors of the selected project
+	selectedGroup     *project.Project   // Current group being viewed
+	groupProjects     []*project.Project // Projects within the selected group
+	projectList       views.ProjectListModel
+	groupList         views.ProjectListModel
+	actionMenu        views.ActionMenuModel
+	submenuStack      []views.ActionMenuModel // Stack for nested submenus
+	pluginActions     []views.Action          // Plugin actions of the project at pluginActionsPath
+	pluginActionsPath string
+	pluginsLoading    bool // pluginActions are still being asked for
+	newProject        views.NewProjectModel
+	newBranch         views.NewBranchModel   // Prompt for New Branch from Template
+	commit            views.CommitModel      // Prompt for the Commit action's message
+	dockerBuild       views.DockerBuildModel // Prompt for Build Image's args and target
+	dockerBuildAction *views.Action          // Build Image action the prompt runs
+	duplicateSource   *project.Project       // Project the new project prompt copies, if any
+	resultViewport    viewport.Model
+	issuesViewport    viewport.Model
+	eachInput         views.Input        // Command prompt for running in marked projects
+	eachTargets       []*project.Project // Projects the prompted command will run in
+	editInput         views.Input        // Command of editAction, edited before running
+	editAction        *views.Action      // Script action run once with the edited command
+	resultTitle       string
+	resultSuccess     bool
+	resultRaw         string             // Unparsed action output
+	resultSummary     *results.Summary   // Parsed summary, if the action declares a parser
+	resultShowRaw     bool               // Show raw output instead of the summary
+	resultRetry       []string           // Git arguments the failed action can retry with prompts
+	resultPostInit    string             // Command a new project's template asks to run
+	resultPostInitDir string             // The new project resultPostInit runs in
+	problems          []results.Location // File locations found in the result output
+	problemIndex      int                // Selected problem
+	branchList        list.Model
+	targetBranch      string
+	keys              tui.KeyMap
+	currentSortBy     project.SortBy // Current sort order
+	columns           []views.Column // Project list columns from display.columns
+	detailed          bool           // Two-line project list items (toggled with z)
+	enrichPending     int            // Projects whose details are still loading
+	enrichGen         int            // Bumped by each scan, tagging the details it loads
+	stats             project.Stats  // Header statistics, computed once details are loaded
+	statFilter        string         // Filter applied by clicking a header statistic
+	showArchived      bool           // The project list shows the archived projects (toggled with a)
+	runningAction     *views.Action  // Action whose result is awaited, for the history
+	historyList       list.Model     // Runs of the selected project
+	bulkRun           *bulkRun       // Progress of the running "each" command
+	cleanAction       views.Action   // Clean action awaiting the artifact selection
+	cleanItems        []cleanItem    // Artifacts offered by the clean preview
+	cleanCursor       int
+	gitignoreAction   views.Action // Generate .gitignore action awaiting confirmation
+	gitignoreTitle    string
+	gitignoreViewport viewport.Model // Preview of the .gitignore to write
+	usageViewport     viewport.Model // Usage statistics charts
+	usageDays         int            // Period of the stats view, 0 until first opened
+	protectedAction   *views.Action  // Action awaiting confirmation to modify a protected branch
+	protectedWarning  string         // How protectedAction would modify the branch
+	protectedOK       bool           // protectedAction was confirmed
+	urlAction         *views.Action  // Action from a proj:// link awaiting confirmation
+	daemonAction      views.Action   // Container action awaiting the daemon
+	daemonStart       string         // Command offered to start the daemon
+	daemonOK          bool           // The daemon answered for daemonAction
+	loadError         loadFailedMsg  // Failed load shown in the error view
+	lastPath          string         // Project whose menu was opened last
+	previousPath      string         // Project whose menu was opened before lastPath
+	pullExplanation   string         // Why the last pull stopped, shown with the strategies
+	pullCursor        int
+	pluginCursor      int           // Selected plugin in the plugin manager
+	settingsPlugin    plugin.Status // Plugin whose settings form is open
+	settingsFields    []plugin.ConfigField
+	settingsInputs    []views.Input // One input per field of settingsFields
+	settingsFocus     int
+	settingsError     string                // Why the form couldn't be saved
+	workspaceMenu     views.ActionMenuModel // Actions not tied to a project
+	width             int
+	height            int
+	toasts            tui.Toasts // Transient messages shown in the corner
+	message           string
+	ready             bool
+	cdPath            string   // Path to change to on exit
+	execCmd           []string // Command to exec on exit
+	startProject      string   // Project to open on startup (e.g. from a proj:// URL)
+	startAction       string   // Action to run on startup for startProject
+	startFromURL      bool     // startProject and startAction come from a proj:// link
+	startDir          string   // Directory proj was launched from, to select the project containing it
 }
 
 // New creates a new application model
@@ -84,46 +186,116 @@ func New(cfg *config.Config) Model {
 	// Load plugins (ignore errors for now)
 	_ = registry.LoadAll()
 
+	// Load remembered state (a corrupt file just starts fresh)
+	st, _ := state.Load()
+
+	tui.ReducedMotion = cfg.Display.ReducedMotion
+
+	// An invalid column layout falls back to the default one
+	var toasts tui.Toasts
+	columns, err := views.ParseColumns(cfg.Display.Columns)
+	if err != nil {
+		toasts.Error(err)
+		columns = views.DefaultColumns
+	}
+	for _, s := range registry.Statuses() {
+		if s.State == plugin.StateFailed {
+			toasts.Error(fmt.Errorf("plugin %s failed to load: %s", s.Name, s.LastError))
+		}
+	}
+
 	return Model{
 		config:         cfg,
+		state:          st,
 		pluginRegistry: registry,
 		view:           ViewLoading,
 		keys:           tui.DefaultKeyMap(),
 		currentSortBy:  project.SortBy(cfg.Display.SortBy),
+		columns:        columns,
+		detailed:       cfg.Display.Density == config.DensityDetailed,
+		toasts:         toasts,
 	}
 }
 
-type errMsg error
-type projectsLoadedMsg []*project.Project
+type projectsLoadedMsg struct {
+	projects    []*project.Project
+	diagnostics []project.Diagnostic
+	hookErr     error // From the postScan hook
+}
 type actionCompleteMsg struct {
-	success      bool
-	message      string
-	actionLabel  string
-	cdPath       string
-	execCmd      []string
-	shouldReload bool // Whether to reload projects after this action
+	success       bool
+	exitCode      int
+	message       string
+	actionLabel   string
+	cdPath        string
+	execCmd       []string
+	openedWith    string            // Editor alias or state.OpenedInTerminal if the action opened the project
+	shouldReload  bool              // Whether to reload projects after this action
+	summary       *results.Summary  // Structured summary parsed from the output
+	bulkCommand   string            // Bulk command whose successful projects are in bulkRuns
+	bulkRuns      map[string]string // Project path -> fingerprint to remember for bulkCommand
+	diverged      bool              // A pull stopped because the branches diverged
+	retryArgs     []string          // Git arguments to retry with credential prompts
+	postInit      string            // Command the template of a new project asks to run
+	postInitDir   string            // The new project postInit runs in
+	created       string            // Path of a project the action created, added without a rescan
+	gitChanged    bool              // The action changed the selected project's git status
+	server        *state.Server     // Dev server the action started, to remember in the state
+	serverChanged bool              // The action stopped the selected project's dev server
+}
+type backupProgressMsg struct {
+	done    int64
+	total   int64
+	updates <-chan tea.Msg // Channel delivering further progress and the final result
+}
+type projectEnrichedMsg struct {
+	gen     int // enrichGen of the scan that found the project
+	path    string
+	details project.Details
+	updates <-chan tea.Msg
+}
+type enrichmentDoneMsg struct {
+	gen int
 }
 type branchesLoadedMsg []string
 type branchSwitchedMsg struct {
 	success bool
 	message string
 	branch  string
+	created bool // A new branch, which keeps uncommitted changes
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		loadProjects(m.config),
+		loadProjects(m.config, m.pluginRegistry),
 		tea.EnterAltScreen,
 	)
 }
 
-// Update handles messages and updates the model
+// Update handles messages and updates the model, then starts the timers of
+// the toasts shown while doing so
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if updated, ok := model.(Model); ok {
+		if expire := updated.toasts.Schedule(); expire != nil {
+			return updated, tea.Batch(cmd, expire)
+		}
+		return updated, cmd
+	}
+	return model, cmd
+}
+
+// update handles a message
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
 
+	case tui.ToastExpiredMsg:
+		m.toasts.Dismiss(msg)
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -132,32 +304,160 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case projectsLoadedMsg:
-		m.projects = []*project.Project(msg)
+		rescan := len(m.projects) > 0
+		m.projects = msg.projects
+		m.diagnostics = msg.diagnostics
+		if msg.hookErr != nil {
+			m.toasts.Error(msg.hookErr)
+		}
+		m.state.Annotate(m.projects)
+		// Pins are only known once annotated
+		m.projects = project.Sort(m.projects, m.currentSortBy)
+		m.enrichPending = 0
+		m.enrichGen++
+		for _, p := range m.projects {
+			if p.Enriching {
+				m.enrichPending++
+			}
+		}
+		enrich := enrichProjects(m.config, m.projects, m.enrichGen)
+		m.stats = project.ComputeStats(m.projects)
+		var refilter tea.Cmd
+		switch {
+		case len(m.projects) == 0:
+			m.setView(ViewProjects)
+		case rescan:
+			// Keep the position and filter of the lists, and the view the
+			// rescan was started from
+			m.message = ""
+			refilter = m.projectList.SetProjects(m.projects)
+			m.refreshSelection()
+			if m.view == ViewLoading {
+				m.setView(ViewProjects)
+			}
+		default:
+			m.projectList = m.newProjectList(m.projects)
+			m.setView(ViewProjects)
+		}
+		m.updateSizes() // Call after setting view so size is applied
+		if m.startProject != "" {
+			model, cmd := m.applyStartup()
+			return model, tea.Batch(cmd, enrich)
+		}
+		if m.startDir != "" {
+			model, cmd := m.applyStartDir()
+			return model, tea.Batch(cmd, enrich)
+		}
+		return m, tea.Batch(enrich, refilter)
+
+	case projectEnrichedMsg:
+		// Details from before a rescan would overwrite the fresh scan's
+		// projects at the same paths, so they're dropped; the old scan's
+		// channel is still drained so its workers can finish
+		if msg.gen != m.enrichGen {
+			return m, waitForUpdate(msg.updates)
+		}
+		// Rows hold pointers into m.projects, so they pick up the change on
+		// the next render
+		for _, p := range m.projects {
+			if p.Path == msg.path && p.Enriching {
+				p.Apply(msg.details)
+				m.enrichPending--
+				break
+			}
+		}
+		return m, waitForUpdate(msg.updates)
+
+	case enrichmentDoneMsg:
+		if msg.gen != m.enrichGen {
+			return m, nil
+		}
+		m.stats = project.ComputeStats(m.projects)
+		// Languages were unknown when the list was first sorted, and the
+		// filter could only match names
 		if len(m.projects) > 0 {
-			m.projectList = views.NewProjectListModel(m.projects)
-			m.view = ViewProjects
-			m.updateSizes() // Call after setting view so size is applied
-		} else {
-			m.message = "No projects found"
-			m.view = ViewProjects
+			if m.currentSortBy == project.SortByLanguage {
+				m.projects = project.Sort(m.projects, m.currentSortBy)
+			}
+			m.projectList.SetProjects(m.projects)
 		}
 		return m, nil
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
+	case daemonCheckedMsg:
+		return m.handleDaemonChecked(msg)
+
+	case daemonStartedMsg:
+		return m.handleDaemonStarted(msg)
+
 	case actionCompleteMsg:
-		if msg.cdPath != "" {
-			m.cdPath = msg.cdPath
-			return m, tea.Quit
+		menuChanged := msg.gitChanged
+		if m.runningAction != nil && m.selectedProject != nil {
+			m.recordRun(m.selectedProject, *m.runningAction, msg)
+			menuChanged = menuChanged || menuChangingActions[m.runningAction.ID]
+		}
+		m.runningAction = nil
+		m.bulkRun = nil
+		if len(msg.bulkRuns) > 0 {
+			m.state.RecordBulkRuns(msg.bulkCommand, msg.bulkRuns)
+			if err := m.state.Save(); err != nil {
+				m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+			}
 		}
-		if len(msg.execCmd) > 0 {
+		if msg.success && msg.openedWith != "" && m.selectedProject != nil {
+			m.recordOpen(m.selectedProject, msg.openedWith)
+		}
+		if msg.gitChanged && m.selectedProject != nil {
+			// The menu offers Commit only while there are changes
+			m.selectedProject.GitDirty, _ = git.IsDirty(m.selectedProject.Path)
+		}
+		if msg.server != nil && m.selectedProject != nil {
+			m.state.RecordServer(m.selectedProject.Path, msg.server)
+			if err := m.state.Save(); err != nil {
+				m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+			}
+		}
+		if (msg.server != nil || msg.serverChanged) && m.selectedProject != nil {
+			// The menu offers Start or Stop depending on whether it's running
+			m.refreshServer(m.selectedProject)
+			menuChanged = true
+		}
+		if menuChanged && m.selectedProject != nil {
+			m.actionMenu = views.NewActionMenuModel(m.selectedProject, m.projectActions())
+		}
+		if msg.success && m.selectedProject != nil && m.selectedProject.NeedsSetup {
+			m.selectedProject.NeedsSetup = project.NeedsSetup(m.selectedProject.Path, m.selectedProject.Language)
+		}
+		if msg.cdPath != "" || len(msg.execCmd) > 0 {
+			// A result may ask for both: the cd file is written before exec
+			m.cdPath = msg.cdPath
 			m.execCmd = msg.execCmd
 			return m, tea.Quit
 		}
+		if msg.diverged && m.selectedProject != nil {
+			return m.showPullStrategies(msg.message)
+		}
+		if msg.success && msg.created != "" {
+			return m.afterCreate(msg)
+		}
 		// Show result in a dedicated view
 		m.resultTitle = msg.actionLabel
 		m.resultSuccess = msg.success
-		m.resultViewport = viewport.New(m.width-4, m.height-10)
-		m.resultViewport.SetContent(msg.message)
-		m.view = ViewResult
+		m.resultRaw = msg.message
+		m.resultSummary = msg.summary
+		m.resultShowRaw = false
+		m.resultRetry = msg.retryArgs
+		m.resultPostInit, m.resultPostInitDir = msg.postInit, msg.postInitDir
+		m.problems = nil
+		m.problemIndex = 0
+		if m.selectedProject != nil {
+			m.problems = results.FindLocations(msg.message, m.selectedProject.Path)
+		}
+		m.resultViewport = viewport.New(m.width-4, m.resultViewportHeight())
+		m.resultViewport.SetContent(m.resultContent())
+		m.setView(ViewResult)
 		// If this action should reload projects (like project creation), do it
 		var cmd tea.Cmd
 		if msg.shouldReload {
@@ -165,6 +465,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 
+	case backupProgressMsg:
+		m.message = fmt.Sprintf("Backing up %s\n\n%s  %s / %s",
+			m.selectedProject.Name,
+			views.ProgressBar(msg.done, msg.total, 30),
+			actions.FormatBytes(msg.done), actions.FormatBytes(msg.total))
+		return m, waitForUpdate(msg.updates)
+
+	case bulkProgressMsg:
+		if m.bulkRun != nil {
+			m.bulkRun.update(msg)
+			m.message = m.bulkRun.status(time.Now())
+		}
+		return m, waitForUpdate(msg.updates)
+
+	case cleanPreviewMsg:
+		return m.showCleanPreview(msg)
+
+	case configEditedMsg:
+		return m.reloadConfig(msg)
+
+	case pluginTickMsg:
+		// Re-render while the plugin manager is open to show state changes
+		if m.view != ViewPlugins {
+			return m, nil
+		}
+		return m, pluginTick()
+
+	case bulkTickMsg:
+		if m.bulkRun == nil {
+			return m, nil
+		}
+		m.message = m.bulkRun.status(time.Now())
+		return m, m.bulkTick()
+
 	case branchesLoadedMsg:
 		// Create branch list
 		items := make([]list.Item, len(msg))
@@ -176,32 +510,97 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.branchList.SetShowStatusBar(false)
 		m.branchList.SetFilteringEnabled(true)
 		m.branchList.Styles.Title = tui.TitleStyle
-		m.view = ViewBranches
+		m.setView(ViewBranches)
 		return m, nil
 
 	case branchSwitchedMsg:
+		m.resultTitle = "Switch Branch"
+		if msg.created {
+			m.resultTitle = "New Branch"
+		}
 		if msg.success {
 			// Update project's branch info
 			m.selectedProject.GitBranch = msg.branch
-			m.selectedProject.GitDirty = false
+			if !msg.created {
+				m.selectedProject.GitDirty = false
+			}
+			// The header and issue actions follow the branch
+			m.actionMenu = views.NewActionMenuModel(m.selectedProject, m.projectActions())
 		}
-		m.resultTitle = "Switch Branch"
 		m.resultSuccess = msg.success
+		m.resultSummary = nil
+		m.problems = nil
 		m.resultViewport = viewport.New(m.width-4, m.height-10)
 		m.resultViewport.SetContent(msg.message)
-		m.view = ViewResult
+		m.setView(ViewResult)
 		return m, nil
 
-	case errMsg:
-		m.err = msg
-		m.view = ViewProjects
-		return m, nil
+	case loadFailedMsg:
+		return m.handleLoadFailed(msg)
+
+	case pluginActionsLoadedMsg:
+		return m.handlePluginActionsLoaded(msg)
+
+	case projectInfoLoadedMsg:
+		return m.handleProjectInfoLoaded(msg)
 	}
 
 	// Delegate to sub-views
 	return m.updateView(msg)
 }
 
+// Position of the header inside tui.ContainerStyle's padding
+const (
+	headerOffsetX = 2
+	headerOffsetY = 1
+)
+
+// handleMouse handles clicks on the header statistics and wheel scrolling
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch m.view {
+	case ViewProjects:
+		switch {
+		case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+			// The header sits inside the container's padding
+			if msg.Y != headerOffsetY+views.HeaderStatsLine {
+				return m, nil
+			}
+			if stat, ok := views.HeaderStatAt(m.stats, msg.X-headerOffsetX); ok {
+				if stat.Query == m.statFilter {
+					// Clicking the active statistic again clears it
+					stat.Query = ""
+				}
+				m.applyStatFilter(stat.Query)
+			}
+		case msg.Button == tea.MouseButtonWheelUp:
+			m.projectList.CursorUp()
+		case msg.Button == tea.MouseButtonWheelDown:
+			m.projectList.CursorDown()
+		}
+		return m, nil
+	case ViewResult:
+		var cmd tea.Cmd
+		m.resultViewport, cmd = m.resultViewport.Update(msg)
+		return m, cmd
+	case ViewScanIssues:
+		var cmd tea.Cmd
+		m.issuesViewport, cmd = m.issuesViewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// applyStatFilter limits the project list to a header statistic's query
+func (m *Model) applyStatFilter(query string) {
+	if len(m.projects) == 0 {
+		return
+	}
+	m.statFilter = query
+	if err := m.projectList.SetQuery(query); err != nil {
+		m.toasts.Error(err)
+	}
+}
+
 // handleKeyPress handles keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch m.view {
@@ -211,25 +610,104 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case key.Matches(msg, m.keys.Refresh):
 			// Rescan projects
-			m.view = ViewLoading
+			m.setView(ViewLoading)
 			m.message = "Rescanning projects..."
 			return m, m.loadProjects()
+		case key.Matches(msg, m.keys.Density) && !m.projectList.IsFiltering():
+			m.toggleDensity()
+			return m, nil
+		case key.Matches(msg, m.keys.Back) && m.statFilter != "" && !m.projectList.IsFiltering():
+			m.applyStatFilter("")
+			return m, nil
 		case key.Matches(msg, m.keys.Sort):
 			// Cycle through sort options
 			m.currentSortBy = m.nextSortBy()
 			// Re-sort projects
 			m.projects = project.Sort(m.projects, m.currentSortBy)
 			// Update project list with sorted projects
-			m.projectList = views.NewProjectListModel(m.projects)
+			m.projectList = m.newProjectList(m.projects)
 			// Rebuild to apply expanded/collapsed state
 			m.projectList.RebuildList()
 			m.updateSizes()
 			return m, nil
 		case key.Matches(msg, m.keys.New):
-			m.newProject = views.NewNewProjectModel()
-			m.view = ViewNewProject
+			m.newProject = m.newProjectModel()
+			m.duplicateSource = nil
+			m.setView(ViewNewProject)
 			m.updateSizes()
 			return m, m.newProject.Init()
+		case key.Matches(msg, m.keys.Issues) && !m.projectList.IsFiltering():
+			if len(m.diagnostics) > 0 {
+				m.issuesViewport = viewport.New(m.width-4, m.height-10)
+				m.issuesViewport.SetContent(views.ScanIssues(m.diagnostics))
+				m.setView(ViewScanIssues)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Mark) && !m.projectList.IsFiltering():
+			m.projectList.ToggleMarked()
+			return m, nil
+		case key.Matches(msg, m.keys.Each) && !m.projectList.IsFiltering():
+			targets := m.projectList.MarkedProjects()
+			if len(targets) == 0 {
+				if p := m.projectList.SelectedProject(); p != nil && !p.IsGroup {
+					targets = []*project.Project{p}
+				}
+			}
+			if len(targets) == 0 {
+				return m, nil
+			}
+			m.eachTargets = targets
+			m.eachInput = views.NewInput(m.state.InputHistory(inputEach))
+			m.eachInput.Placeholder = "go vet ./..."
+			m.eachInput.Width = m.width - 10
+			m.eachInput.Focus()
+			m.setView(ViewEachPrompt)
+			return m, textinput.Blink
+		case key.Matches(msg, m.keys.Reopen) && !m.projectList.IsFiltering():
+			if p := m.projectList.SelectedProject(); p != nil && p.LastOpenedWith != "" {
+				m.selectedProject = p
+				return m.runAction(views.ReopenAction(p))
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Bootstrap) && !m.projectList.IsFiltering():
+			if p := m.projectList.SelectedProject(); p != nil && p.NeedsSetup {
+				m.selectedProject = p
+				return m.runAction(views.BootstrapAction(p))
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Rerun) && !m.projectList.IsFiltering():
+			if p := m.projectList.SelectedProject(); p != nil && !p.IsGroup && len(m.state.History(p.Path)) > 0 {
+				// Results return to the project's menu, as if run from there
+				m.selectedProject = p
+				load := m.openActionMenu()
+				model, cmd := m.rerunLast()
+				return model, tea.Batch(load, cmd)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.History) && !m.projectList.IsFiltering():
+			if p := m.projectList.SelectedProject(); p != nil && !p.IsGroup {
+				m.selectedProject = p
+				load := m.openActionMenu()
+				m.openHistory()
+				return m, load
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Switch) && !m.projectList.IsFiltering():
+			return m.switchProject()
+		case key.Matches(msg, m.keys.Stats) && !m.projectList.IsFiltering():
+			m.openStats()
+			return m, nil
+		case key.Matches(msg, m.keys.Plugins) && !m.projectList.IsFiltering():
+			return m.openPlugins()
+		case key.Matches(msg, m.keys.Workspace) && !m.projectList.IsFiltering():
+			return m.openWorkspace()
+		case key.Matches(msg, m.keys.Pin) && !m.projectList.IsFiltering():
+			if p := m.projectList.SelectedProject(); p != nil {
+				return m, m.togglePin(p)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Archived) && !m.projectList.IsFiltering():
+			return m, m.toggleArchivedView()
 		case key.Matches(msg, m.keys.Enter):
 			if m.selectedProject = m.projectList.SelectedProject(); m.selectedProject != nil {
 				// If this is a pure group (not a project), navigate into it
@@ -237,54 +715,14 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.selectedGroup = m.selectedProject
 					// Find child projects for this group
 					m.groupProjects = m.getChildProjects(m.selectedProject.Path)
-					m.groupList = views.NewGroupListModel(m.groupProjects)
-					m.view = ViewGroup
+					m.groupList = m.newGroupList(m.groupProjects)
+					m.setView(ViewGroup)
 					m.updateSizes()
 					return m, nil
 				}
-				
-				// Get built-in actions
-				actions := views.DefaultActions(m.selectedProject, m.config.Actions.EnableGitOperations, m.config.Actions.EnableTestRunner)
-
-				// If this is a monorepo (project with sub-projects), add "Show child projects" action
-				if m.selectedProject.SubProjectCount > 0 {
-					childAction := views.Action{
-						ID:    "show_children",
-						Label: fmt.Sprintf("📂 Show child projects (%d)", m.selectedProject.SubProjectCount),
-						Desc:  "View projects within this monorepo",
-					}
-					// Insert after "Open in editor" (usually index 1)
-					insertIdx := 1
-					if len(actions) > insertIdx {
-						actions = append(actions[:insertIdx+1], append([]views.Action{childAction}, actions[insertIdx+1:]...)...)
-					} else {
-						actions = append([]views.Action{childAction}, actions...)
-					}
-				}
-
-				// Get plugin actions
-				if m.pluginRegistry != nil {
-					pluginActions := m.getPluginActions(m.selectedProject)
-					// Insert plugin actions before the "back" action
-					if len(actions) > 0 {
-						// Find the back action
-						backIdx := len(actions) - 1
-						for i, a := range actions {
-							if a.ID == "back" {
-								backIdx = i
-								break
-							}
-						}
-						// Insert plugin actions before back
-						actions = append(actions[:backIdx], append(pluginActions, actions[backIdx:]...)...)
-					} else {
-						actions = append(actions, pluginActions...)
-					}
-				}
 
-				m.actionMenu = views.NewActionMenuModel(m.selectedProject, actions)
-				m.view = ViewActions
-				m.updateSizes()
+				m.recordSelect(m.selectedProject)
+				return m, m.openActionMenu()
 			}
 			return m, nil
 		default:
@@ -297,48 +735,48 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ViewGroup:
 		switch {
 		case key.Matches(msg, m.keys.Back):
-			// Back to main project list
-			m.view = ViewProjects
+			// Back to the project list, or the menu of the project whose children these are
+			m.back(ViewProjects)
+			if m.view == ViewActions && m.selectedProject != m.selectedGroup {
+				// The menu was reused for a child since
+				m.selectedProject = m.selectedGroup
+				m.actionMenu = views.NewActionMenuModel(m.selectedProject, m.projectActions())
+				m.updateSizes()
+			}
 			m.selectedGroup = nil
 			m.groupProjects = nil
 			return m, nil
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
+		case key.Matches(msg, m.keys.Density) && !m.groupList.IsFiltering():
+			m.toggleDensity()
+			return m, nil
 		case key.Matches(msg, m.keys.Refresh):
 			// Rescan projects and refresh group view
-			m.message = "Rescanning projects..."
+			m.toasts.Info("Rescanning projects...")
 			return m, m.loadProjectsAndRefreshGroup()
 		case key.Matches(msg, m.keys.New):
 			// Create new project inside the group
-			m.newProject = views.NewNewProjectModel()
-			m.view = ViewNewProject
+			m.newProject = m.newProjectModel()
+			m.duplicateSource = nil
+			m.setView(ViewNewProject)
 			m.updateSizes()
 			return m, m.newProject.Init()
+		case key.Matches(msg, m.keys.Switch) && !m.groupList.IsFiltering():
+			return m.switchProject()
+		case key.Matches(msg, m.keys.Pin) && !m.groupList.IsFiltering():
+			if p := m.groupList.SelectedProject(); p != nil {
+				return m, m.togglePin(p)
+			}
+			return m, nil
 		case key.Matches(msg, m.keys.Enter):
+			// Nested groups collapse and expand in place
+			if m.groupList.ToggleExpanded() {
+				return m, nil
+			}
 			if m.selectedProject = m.groupList.SelectedProject(); m.selectedProject != nil {
-				// Get built-in actions
-				actions := views.DefaultActions(m.selectedProject, m.config.Actions.EnableGitOperations, m.config.Actions.EnableTestRunner)
-
-				// Get plugin actions
-				if m.pluginRegistry != nil {
-					pluginActions := m.getPluginActions(m.selectedProject)
-					if len(actions) > 0 {
-						backIdx := len(actions) - 1
-						for i, a := range actions {
-							if a.ID == "back" {
-								backIdx = i
-								break
-							}
-						}
-						actions = append(actions[:backIdx], append(pluginActions, actions[backIdx:]...)...)
-					} else {
-						actions = append(actions, pluginActions...)
-					}
-				}
-
-				m.actionMenu = views.NewActionMenuModel(m.selectedProject, actions)
-				m.view = ViewActions
-				m.updateSizes()
+				m.recordSelect(m.selectedProject)
+				return m, m.openActionMenu()
 			}
 			return m, nil
 		default:
@@ -358,19 +796,43 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.submenuStack = m.submenuStack[:len(m.submenuStack)-1]
 				m.updateSizes()
 			} else {
-				// Back to project list (or group list if we came from there)
-				if m.selectedGroup != nil {
-					m.view = ViewGroup
-				} else {
-					m.view = ViewProjects
-				}
-				m.selectedProject = nil
+				m.leaveActions()
 			}
 			return m, nil
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
+		case key.Matches(msg, m.keys.Reopen):
+			if reopen := views.ReopenAction(m.selectedProject); reopen != nil {
+				return m.runAction(reopen)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Bootstrap):
+			if bootstrap := views.BootstrapAction(m.selectedProject); bootstrap != nil {
+				return m.runAction(bootstrap)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Rerun):
+			return m.rerunLast()
+		case key.Matches(msg, m.keys.History):
+			m.openHistory()
+			return m, nil
+		case key.Matches(msg, m.keys.Switch):
+			return m.switchProject()
+		case key.Matches(msg, m.keys.Edit):
+			if action := m.actionMenu.SelectedAction(); editable(action) {
+				return m.promptEditCommand(action)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.DryRun):
+			if action := m.actionMenu.SelectedAction(); action != nil && !action.IsSubmenu && action.ID != "back" && action.ID != "show_children" && action.ID != views.PluginsLoadingID {
+				return m.dryRun(action)
+			}
+			return m, nil
 		case key.Matches(msg, m.keys.Enter):
 			if action := m.actionMenu.SelectedAction(); action != nil {
+				if action.ID == views.PluginsLoadingID {
+					return m, nil
+				}
 				if action.ID == "back" {
 					// Check if we're in a submenu
 					if len(m.submenuStack) > 0 {
@@ -379,13 +841,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						m.submenuStack = m.submenuStack[:len(m.submenuStack)-1]
 						m.updateSizes()
 					} else {
-						// Back to project list (or group list if we came from there)
-						if m.selectedGroup != nil {
-							m.view = ViewGroup
-						} else {
-							m.view = ViewProjects
-						}
-						m.selectedProject = nil
+						m.leaveActions()
 					}
 					return m, nil
 				}
@@ -394,8 +850,8 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if action.ID == "show_children" {
 					m.selectedGroup = m.selectedProject
 					m.groupProjects = m.getChildProjects(m.selectedProject.Path)
-					m.groupList = views.NewGroupListModel(m.groupProjects)
-					m.view = ViewGroup
+					m.groupList = m.newGroupList(m.groupProjects)
+					m.setView(ViewGroup)
 					m.updateSizes()
 					return m, nil
 				}
@@ -417,15 +873,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				// Special handling for git-branch - show interactive picker
-				if action.ID == "git-branch" {
-					m.view = ViewExecuting
-					m.message = "Loading branches..."
-					return m, loadBranches(m.selectedProject.Path)
-				}
-				m.view = ViewExecuting
-				m.message = fmt.Sprintf("Executing: %s...", action.Label)
-				return m, executeAction(action.ID, action.Label, action.Command, m.selectedProject, m.config, m.pluginRegistry)
+				return m.runAction(action)
 			}
 			return m, nil
 		default:
@@ -438,26 +886,44 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ViewNewProject:
 		switch {
 		case key.Matches(msg, m.keys.Back) && msg.String() != "backspace":
-			// Go back to the appropriate view (but not on backspace - let text input handle that)
-			if m.selectedGroup != nil {
-				m.view = ViewGroup
-			} else {
-				m.view = ViewProjects
-			}
+			// Go back to the previous view (but not on backspace - let text input handle that)
+			m.back(ViewProjects)
 			return m, nil
 		case key.Matches(msg, m.keys.Enter):
-			projectName := m.newProject.Value()
-			if projectName != "" {
-				m.view = ViewExecuting
-				m.message = fmt.Sprintf("Creating project: %s...", projectName)
-				// Create in group path if we're inside a group, otherwise use repos path
-				basePath := m.config.ReposPath
-				if m.selectedGroup != nil {
-					basePath = m.selectedGroup.Path
+			if m.newProject.Value() == "" {
+				return m, nil
+			}
+			projectName, location, err := m.newProject.Target()
+			if err != nil {
+				m.newProject.SetError(err)
+				return m, nil
+			}
+			m.recordInput(inputNewProject, m.newProject.Value())
+			m.setView(ViewExecuting)
+			if m.duplicateSource != nil {
+				m.message = fmt.Sprintf("Copying %s to %s...", m.duplicateSource.Name, projectName)
+				return m, duplicateProject(m.duplicateSource, filepath.Join(location.Path, projectName), m.newProject.InitGit(), m.config)
+			}
+			if tmpl := m.newProject.Template(); tmpl != "" {
+				if generator, template := scaffold.ParseTemplate(m.config.Templates[tmpl]); generator != "" {
+					cmd, err := scaffold.GeneratorCommand(generator, template, config.ExpandPath(location.Path), projectName)
+					if err != nil {
+						m.setView(ViewNewProject)
+						m.newProject.SetError(err)
+						return m, nil
+					}
+					m.message = fmt.Sprintf("Running %s...", generator)
+					dest := ""
+					if generator == scaffold.GeneratorCopier {
+						dest = filepath.Join(config.ExpandPath(location.Path), projectName)
+					}
+					return m, runGenerator(generator, template, dest, cmd)
 				}
-				return m, createProject(projectName, basePath)
+				m.message = fmt.Sprintf("Creating %s from template %s...", projectName, tmpl)
+				return m, createFromTemplate(projectName, location.Path, tmpl, m.config)
 			}
-			return m, nil
+			m.message = fmt.Sprintf("Creating project: %s...", projectName)
+			return m, createProject(projectName, location.Path)
 		default:
 			// Pass other keys to the text input
 			var cmd tea.Cmd
@@ -468,13 +934,40 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ViewResult:
 		switch {
 		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
-			// Go back to the appropriate view
+			// Go back to the view the action ran from, such as the branch list
+			fallback := ViewProjects
 			if m.selectedProject != nil {
-				m.view = ViewActions
+				fallback = ViewActions
+			}
+			m.back(fallback)
+			return m, nil
+		case (msg.String() == "n" || msg.String() == "tab") && len(m.problems) > 0:
+			m.problemIndex = (m.problemIndex + 1) % len(m.problems)
+			return m, nil
+		case (msg.String() == "p" || msg.String() == "shift+tab") && len(m.problems) > 0:
+			m.problemIndex = (m.problemIndex - 1 + len(m.problems)) % len(m.problems)
+			return m, nil
+		case key.Matches(msg, m.keys.Enter) && len(m.problems) > 0:
+			// Open the selected problem in the editor
+			result := actions.NewExecutor(m.config).OpenLocation(m.problems[m.problemIndex])
+			if result.Success {
+				m.toasts.Success(result.Message)
 			} else {
-				m.view = ViewProjects
+				m.toasts.Push(tui.ToastError, result.Message)
 			}
 			return m, nil
+		case msg.String() == "i" && m.resultPostInit != "":
+			m.setView(ViewExecuting)
+			m.message = fmt.Sprintf("Running %s...", m.resultPostInit)
+			return m, runPostInit(m.resultPostInit, m.resultPostInitDir, m.config)
+		case msg.String() == "r" && len(m.resultRetry) > 0 && m.selectedProject != nil:
+			return m, retryWithPrompts(m.resultTitle, m.resultRetry, m.selectedProject)
+		case msg.String() == "r" && m.resultSummary != nil:
+			// Toggle between the summary and the raw output
+			m.resultShowRaw = !m.resultShowRaw
+			m.resultViewport.SetContent(m.resultContent())
+			m.resultViewport.GotoTop()
+			return m, nil
 		default:
 			// Pass keys to viewport for scrolling
 			var cmd tea.Cmd
@@ -482,10 +975,53 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case ViewEachPrompt:
+		switch msg.String() {
+		case "esc":
+			m.setView(ViewProjects)
+			return m, nil
+		case "ctrl+c":
+			return m, tea.Quit
+		case "enter", "ctrl+f":
+			command := actions.ParseCommand(m.eachInput.Value())
+			if len(command) == 0 {
+				return m, nil
+			}
+			m.recordInput(inputEach, m.eachInput.Value())
+			// Skip projects unchanged since the command last succeeded, unless forced
+			key := actions.QuoteArgs(command)
+			var last map[string]string
+			if msg.String() == "enter" {
+				paths := make([]string, len(m.eachTargets))
+				for i, p := range m.eachTargets {
+					paths[i] = p.Path
+				}
+				last = m.state.BulkFingerprints(key, paths)
+			}
+			m.selectedProject = nil
+			m.projectList.ClearMarked()
+			return m.startEach(command, last)
+		default:
+			var cmd tea.Cmd
+			m.eachInput, cmd = m.eachInput.Update(msg)
+			return m, cmd
+		}
+
+	case ViewScanIssues:
+		switch {
+		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+			m.setView(ViewProjects)
+			return m, nil
+		default:
+			var cmd tea.Cmd
+			m.issuesViewport, cmd = m.issuesViewport.Update(msg)
+			return m, cmd
+		}
+
 	case ViewBranches:
 		switch {
 		case key.Matches(msg, m.keys.Back):
-			m.view = ViewActions
+			m.back(ViewActions)
 			return m, nil
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
@@ -495,11 +1031,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Check if repo is dirty
 				dirty, _ := git.IsDirty(m.selectedProject.Path)
 				if dirty {
-					m.view = ViewConfirmStash
+					m.setView(ViewConfirmStash)
 					return m, nil
 				}
 				// Not dirty, switch directly
-				m.view = ViewExecuting
+				m.setView(ViewExecuting)
 				m.message = fmt.Sprintf("Switching to %s...", m.targetBranch)
 				return m, switchBranch(m.selectedProject.Path, m.targetBranch, false)
 			}
@@ -510,16 +1046,77 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+	case ViewHistory:
+		return m.handleHistoryKey(msg)
+
+	case ViewGitignore:
+		return m.handleGitignoreKey(msg)
+
+	case ViewNewBranch:
+		return m.handleNewBranchKey(msg)
+
+	case ViewCommit:
+		return m.handleCommitKey(msg)
+
+	case ViewDockerBuild:
+		return m.handleDockerBuildKey(msg)
+
+	case ViewEditCommand:
+		return m.handleEditCommandKey(msg)
+
+	case ViewClean:
+		return m.handleCleanKey(msg)
+
+	case ViewStats:
+		return m.handleStatsKey(msg)
+
+	case ViewPullStrategy:
+		return m.handlePullStrategyKey(msg)
+
+	case ViewPlugins:
+		return m.handlePluginsKey(msg)
+
+	case ViewPluginSettings:
+		return m.handlePluginSettingsKey(msg)
+
+	case ViewWorkspace:
+		return m.handleWorkspaceKey(msg)
+
+	case ViewConfirmDaemon:
+		return m.handleConfirmDaemonKey(msg)
+
+	case ViewError:
+		return m.handleLoadErrorKey(msg)
+
+	case ViewConfirmProtected:
+		switch msg.String() {
+		case "y", "Y":
+			m.protectedOK = true
+			return m.runAction(m.protectedAction)
+		case "n", "N", "esc", "q":
+			m.setView(ViewActions)
+			return m, nil
+		}
+
+	case ViewConfirmURL:
+		switch msg.String() {
+		case "y", "Y":
+			return m.runAction(m.urlAction)
+		case "n", "N", "esc", "q":
+			m.setView(ViewActions)
+			return m, nil
+		}
+
 	case ViewConfirmStash:
 		switch msg.String() {
 		case "y", "Y":
 			// Stash and switch
-			m.view = ViewExecuting
+			m.setView(ViewExecuting)
 			m.message = fmt.Sprintf("Stashing changes and switching to %s...", m.targetBranch)
 			return m, switchBranch(m.selectedProject.Path, m.targetBranch, true)
 		case "n", "N", "esc", "q":
 			// Cancel, go back to branches
-			m.view = ViewBranches
+			m.setView(ViewBranches)
 			return m, nil
 		}
 	}
@@ -544,6 +1141,18 @@ func (m Model) updateView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.actionMenu, cmd = m.actionMenu.Update(msg)
 	case ViewNewProject:
 		m.newProject, cmd = m.newProject.Update(msg)
+	case ViewEachPrompt:
+		m.eachInput, cmd = m.eachInput.Update(msg)
+	case ViewNewBranch:
+		m.newBranch, cmd = m.newBranch.Update(msg)
+	case ViewCommit:
+		m.commit, cmd = m.commit.Update(msg)
+	case ViewDockerBuild:
+		m.dockerBuild, cmd = m.dockerBuild.Update(msg)
+	case ViewEditCommand:
+		m.editInput, cmd = m.editInput.Update(msg)
+	case ViewPluginSettings:
+		m.settingsInputs[m.settingsFocus], cmd = m.settingsInputs[m.settingsFocus].Update(msg)
 	}
 
 	return m, cmd
@@ -562,7 +1171,8 @@ func (m *Model) updateSizes() {
 	}
 
 	if len(m.projects) > 0 {
-		m.projectList.SetSize(m.width-4, contentHeight)
+		// The header has an extra line of statistics
+		m.projectList.SetSize(m.width-4, contentHeight-1)
 	}
 	if len(m.groupProjects) > 0 {
 		m.groupList.SetSize(m.width-4, contentHeight)
@@ -570,6 +1180,9 @@ func (m *Model) updateSizes() {
 	if m.view == ViewActions {
 		m.actionMenu.SetSize(m.width-4, contentHeight)
 	}
+	if m.view == ViewWorkspace {
+		m.workspaceMenu.SetSize(m.width-4, contentHeight)
+	}
 	if m.view == ViewNewProject {
 		m.newProject.SetSize(m.width-4, contentHeight)
 	}
@@ -580,6 +1193,11 @@ func (m Model) View() string {
 	if !m.ready {
 		return ""
 	}
+	return m.toasts.Overlay(m.renderView(), m.width)
+}
+
+// renderView renders the current view
+func (m Model) renderView() string {
 
 	switch m.view {
 	case ViewLoading:
@@ -597,6 +1215,18 @@ func (m Model) View() string {
 	case ViewNewProject:
 		return tui.ContainerStyle.Render(m.newProject.View())
 
+	case ViewNewBranch:
+		return tui.ContainerStyle.Render(m.newBranch.View())
+
+	case ViewCommit:
+		return tui.ContainerStyle.Render(m.commit.View())
+
+	case ViewDockerBuild:
+		return tui.ContainerStyle.Render(m.dockerBuild.View())
+
+	case ViewEditCommand:
+		return m.renderEditCommandView()
+
 	case ViewExecuting:
 		return tui.ContainerStyle.Render(
 			lipgloss.JoinVertical(
@@ -615,6 +1245,48 @@ func (m Model) View() string {
 
 	case ViewConfirmStash:
 		return m.renderConfirmStashView()
+
+	case ViewConfirmProtected:
+		return m.renderConfirmProtectedView()
+
+	case ViewConfirmURL:
+		return m.renderConfirmURLView()
+
+	case ViewConfirmDaemon:
+		return m.renderConfirmDaemonView()
+
+	case ViewError:
+		return m.renderLoadErrorView()
+
+	case ViewScanIssues:
+		return m.renderScanIssuesView()
+
+	case ViewEachPrompt:
+		return m.renderEachPromptView()
+
+	case ViewHistory:
+		return m.renderHistoryView()
+
+	case ViewGitignore:
+		return m.renderGitignoreView()
+
+	case ViewClean:
+		return m.renderCleanView()
+
+	case ViewStats:
+		return m.renderStatsView()
+
+	case ViewPullStrategy:
+		return m.renderPullStrategyView()
+
+	case ViewPlugins:
+		return m.renderPluginsView()
+
+	case ViewPluginSettings:
+		return m.renderPluginSettingsView()
+
+	case ViewWorkspace:
+		return m.renderWorkspaceView()
 	}
 
 	return ""
@@ -622,7 +1294,7 @@ func (m Model) View() string {
 
 // renderProjectsView renders the projects list view
 func (m Model) renderProjectsView() string {
-	header := views.Header(m.config.ReposPath, len(m.projects))
+	header := views.Header(m.config.ReposPath, m.stats, m.statFilter)
 
 	content := ""
 	if len(m.projects) == 0 {
@@ -633,17 +1305,22 @@ func (m Model) renderProjectsView() string {
 
 	// Show current sort mode
 	sortLabel := m.getSortLabel()
-	sortInfo := tui.SubtitleStyle.Render(fmt.Sprintf("Sort: %s", sortLabel))
-
-	help := tui.HelpStyle.Render("↑/↓: navigate  •  enter: select  •  s: sort  •  n: new  •  r/F5: refresh  •  q: quit")
-
-	errorMsg := ""
-	if m.err != nil {
-		errorMsg = "\n" + tui.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	sortInfo := fmt.Sprintf("Sort: %s", sortLabel)
+	if m.showArchived {
+		sortInfo = "Archived projects (a: back to the others)  •  " + sortInfo
+	}
+	if m.statFilter != "" {
+		sortInfo += fmt.Sprintf("  •  Filter: %s (esc: clear)", m.statFilter)
 	}
-	if m.message != "" {
-		errorMsg = "\n" + tui.SuccessStyle.Render(m.message)
+	if m.enrichPending > 0 {
+		sortInfo += fmt.Sprintf("  •  Loading details (%d left)", m.enrichPending)
 	}
+	if hint := views.ScanIssuesHint(len(m.diagnostics)); hint != "" {
+		sortInfo += "  •  " + hint
+	}
+	sortInfo = tui.SubtitleStyle.Render(sortInfo)
+
+	help := tui.HelpStyle.Render("↑/↓: navigate  •  enter: select  •  o: reopen  •  .: re-run last  •  h: history  •  -: switch  •  S: stats  •  space: mark  •  e: run in marked  •  f: pin  •  a: archived  •  s: sort  •  z: density  •  n: new  •  r/F5: refresh  •  q: quit")
 
 	return tui.ContainerStyle.Render(
 		lipgloss.JoinVertical(
@@ -652,7 +1329,6 @@ func (m Model) renderProjectsView() string {
 			sortInfo,
 			"",
 			content,
-			errorMsg,
 			"",
 			help,
 		),
@@ -676,7 +1352,7 @@ func (m Model) renderGroupView() string {
 
 	projectCount := tui.SubtitleStyle.Render(fmt.Sprintf("%d projects", len(m.groupProjects)))
 
-	help := tui.HelpStyle.Render("↑/↓: navigate  •  enter: select  •  n: new  •  r/F5: refresh  •  esc: back  •  q: quit")
+	help := tui.HelpStyle.Render("↑/↓: navigate  •  enter: select (or collapse/expand a group)  •  f: pin  •  z: density  •  n: new  •  r/F5: refresh  •  esc: back  •  q: quit")
 
 	return tui.ContainerStyle.Render(
 		lipgloss.JoinVertical(
@@ -691,36 +1367,652 @@ func (m Model) renderGroupView() string {
 	)
 }
 
-// getChildProjects returns child projects for a given parent path
-func (m Model) getChildProjects(parentPath string) []*project.Project {
-	children := make([]*project.Project, 0)
-	for _, p := range m.projects {
-		if p.ParentPath == parentPath && p.Depth > 0 {
-			children = append(children, p)
-		}
+// toggleDensity switches the project lists between compact and detailed items
+func (m *Model) toggleDensity() {
+	m.detailed = !m.detailed
+	m.projectList.SetDetailed(m.detailed)
+	if len(m.groupProjects) > 0 {
+		m.groupList.SetDetailed(m.detailed)
 	}
-	return children
 }
 
-// renderActionsView renders the actions menu view
-func (m Model) renderActionsView() string {
-	header := views.ActionHeader(
-		m.selectedProject.Name,
-		m.selectedProject.Language,
-		m.selectedProject.GitBranch,
-		m.selectedProject.GitDirty,
-	)
+// newProjectList creates the top-level project list with the configured columns
+func (m Model) newProjectList(projects []*project.Project) views.ProjectListModel {
+	l := views.NewProjectListModel(projects)
+	l.SetColumns(m.columns)
+	l.SetDetailed(m.detailed)
+	l.SetTheme(m.config.Theme)
+	l.SetArchived(m.showArchived)
+	_ = l.SetQuery(m.statFilter) // Only set from header statistics, which are valid
+	return l
+}
 
-	content := m.actionMenu.View()
+// newGroupList creates a group's project list with the configured columns
+func (m Model) newGroupList(projects []*project.Project) views.ProjectListModel {
+	l := views.NewGroupListModel(projects)
+	l.SetColumns(m.columns)
+	l.SetDetailed(m.detailed)
+	l.SetTheme(m.config.Theme)
+	return l
+}
 
-	// Update help text based on whether we're in a submenu
-	helpText := "↑/↓: navigate  •  enter: execute  •  esc: back  •  q: quit"
-	if len(m.submenuStack) > 0 {
-		helpText = "↑/↓: navigate  •  enter: select  •  esc: back to menu  •  q: quit"
-	}
-	help := tui.HelpStyle.Render(helpText)
+// getChildProjects returns the projects inside a group or monorepo,
+// including those in its nested groups
+func (m Model) getChildProjects(parentPath string) []*project.Project {
+	return project.Descendants(m.projects, parentPath)
+}
 
-	return tui.ContainerStyle.Render(
+// menuChangingActions change what the action menu offers, so it's rebuilt
+// after they run
+var menuChangingActions = map[string]bool{
+	"env-create":        true,
+	"env-sync":          true,
+	"jvm-refresh-tasks": true,
+}
+
+// activityWeeks is how many weeks of commits the action menu's sparkline shows
+const activityWeeks = 12
+
+// openActionMenu builds the action menu for the selected project and switches
+// to it. Plugins are asked for their actions in the background, as they may
+// be slow to answer; the returned command delivers them to the menu.
+func (m *Model) openActionMenu() tea.Cmd {
+	m.refreshServer(m.selectedProject)
+
+	var load tea.Cmd
+	var pluginActions []views.Action
+	if m.pluginRegistry != nil && len(m.pluginRegistry.GetAllPlugins()) > 0 {
+		pluginActions = []views.Action{views.PluginsLoadingAction()}
+		if m.pluginActionsPath == m.selectedProject.Path && !m.pluginsLoading {
+			// Show the actions from the last opening until the plugins answer
+			pluginActions = m.pluginActions
+		}
+		m.pluginsLoading = true
+		load = loadPluginActions(m.pluginRegistry, m.selectedProject)
+	} else {
+		m.pluginActions, m.pluginsLoading = nil, false
+	}
+	m.pluginActionsPath = m.selectedProject.Path
+	actions := m.menuActions(pluginActions)
+
+	m.rememberSelection(m.selectedProject)
+	m.projectInfo = views.ProjectInfo{}
+	info := loadProjectInfo(m.selectedProject, m.state.CachedContributors(m.selectedProject.Path))
+	m.actionMenu = views.NewActionMenuModel(m.selectedProject, actions)
+	m.pushView(ViewActions)
+	m.updateSizes()
+	return tea.Batch(load, info)
+}
+
+// projectInfoLoadedMsg delivers the details shown under a project's action
+// menu header
+type projectInfoLoadedMsg struct {
+	path    string
+	info    views.ProjectInfo
+	counted *state.Contributors // Contributors counted afresh, to be cached
+}
+
+// loadProjectInfo finds a project's toolchains, compose services and git
+// activity in the background; git log and shortlog are slow in large
+// repositories. Contributors are only counted again when HEAD has moved
+// since cached.
+func loadProjectInfo(p *project.Project, cached state.Contributors) tea.Cmd {
+	path, hasCompose, isGitRepo := p.Path, p.HasCompose, p.IsGitRepo
+	return func() tea.Msg {
+		msg := projectInfoLoadedMsg{path: path}
+		msg.info.Toolchains = toolchain.Check(path)
+		if hasCompose {
+			if info, err := docker.Detect(path); err == nil {
+				msg.info.Services = info.Services
+			}
+		}
+		if !isGitRepo {
+			return msg
+		}
+		msg.info.Activity, _ = git.WeeklyCommits(path, activityWeeks, time.Now())
+		msg.info.Me = git.UserName(path)
+		head, err := git.HeadCommit(path)
+		switch {
+		case err != nil:
+		case head == cached.Head:
+			msg.info.Contributors = cached.Authors
+		default:
+			if authors, err := git.Contributors(path); err == nil {
+				msg.info.Contributors = authors
+				msg.counted = &state.Contributors{Head: head, Authors: authors}
+			}
+		}
+		return msg
+	}
+}
+
+// handleProjectInfoLoaded caches freshly counted contributors and shows the
+// details, unless another project's menu was opened meanwhile
+func (m Model) handleProjectInfoLoaded(msg projectInfoLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.counted != nil {
+		m.state.RecordContributors(msg.path, msg.counted.Head, msg.counted.Authors)
+		if err := m.state.Save(); err != nil {
+			m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+		}
+	}
+	if m.selectedProject != nil && m.selectedProject.Path == msg.path {
+		m.projectInfo = msg.info
+		m.updateSizes()
+	}
+	return m, nil
+}
+
+// pluginActionsLoadedMsg delivers the plugin actions of a project
+type pluginActionsLoadedMsg struct {
+	path    string
+	actions []views.Action
+}
+
+// loadPluginActions asks the plugins for a project's actions
+func loadPluginActions(registry *plugin.Registry, proj *project.Project) tea.Cmd {
+	return func() tea.Msg {
+		return pluginActionsLoadedMsg{path: proj.Path, actions: pluginViewActions(registry, proj)}
+	}
+}
+
+// handlePluginActionsLoaded merges the plugin actions into the menu, unless
+// the menu of another project was opened since they were asked for
+func (m Model) handlePluginActionsLoaded(msg pluginActionsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.path != m.pluginActionsPath || !m.pluginsLoading {
+		return m, nil
+	}
+	m.pluginActions, m.pluginsLoading = msg.actions, false
+	if m.selectedProject != nil && m.selectedProject.Path == msg.path {
+		m.mergePluginActions()
+	}
+	return m, nil
+}
+
+// mergePluginActions puts the loaded plugin actions in the selected
+// project's menu in place of the placeholder
+func (m *Model) mergePluginActions() {
+	// The plugin actions belong to the top menu, under any open submenus
+	menu := &m.actionMenu
+	if len(m.submenuStack) > 0 {
+		menu = &m.submenuStack[0]
+	}
+	menu.SetActions(m.menuActions(m.pluginActions))
+}
+
+// refreshSelection points the selected project and group at their rescanned
+// copies, and refreshes the group's list
+func (m *Model) refreshSelection() {
+	for _, p := range m.projects {
+		if m.selectedProject != nil && p.Path == m.selectedProject.Path {
+			m.selectedProject = p
+		}
+		if m.selectedGroup != nil && p.Path == m.selectedGroup.Path {
+			m.selectedGroup = p
+			m.groupProjects = m.getChildProjects(p.Path)
+			m.groupList.SetProjects(m.groupProjects)
+		}
+	}
+}
+
+// leaveActions closes the action menu, returning to the list it was opened
+// from
+func (m *Model) leaveActions() {
+	fallback := ViewProjects
+	if m.selectedGroup != nil {
+		fallback = ViewGroup
+	}
+	m.back(fallback)
+	if m.view == ViewProjects || m.view == ViewGroup {
+		m.selectedProject = nil
+	}
+}
+
+// projectActions returns the built-in and plugin actions for the selected project
+func (m *Model) projectActions() []views.Action {
+	if m.pluginActionsPath != m.selectedProject.Path || m.pluginsLoading {
+		// Something needs the actions before the plugins answered in the
+		// background, so ask them now
+		waiting := m.pluginsLoading && m.pluginActionsPath == m.selectedProject.Path
+		m.pluginActions = m.getPluginActions(m.selectedProject)
+		m.pluginActionsPath = m.selectedProject.Path
+		m.pluginsLoading = false
+		if waiting {
+			m.mergePluginActions()
+		}
+	}
+	return m.menuActions(m.pluginActions)
+}
+
+// menuActions returns the actions of the selected project's menu, with the
+// given plugin actions
+func (m *Model) menuActions(pluginActions []views.Action) []views.Action {
+	// The menu depends on the language, git and docker details
+	if m.selectedProject.Enriching {
+		m.selectedProject.Apply(project.NewScanner(m.config).Details(m.selectedProject.Path))
+		m.enrichPending--
+	}
+
+	// Projects contributed by plugins aren't on disk, so only their plugin's
+	// actions apply
+	if m.selectedProject.Source != "" {
+		actions := append(append([]views.Action{}, pluginActions...), views.Action{ID: "back", Label: "← Back", Desc: "Return to project list"})
+		return orderMenu(actions, m.config.Actions, m.selectedProject.Name)
+	}
+
+	// Get built-in actions
+	actions := views.DefaultActions(m.selectedProject, m.config.Actions.EnableGitOperations, m.config.Actions.EnableTestRunner)
+
+	// If this is a monorepo (project with sub-projects), add "Show child projects" action
+	if m.selectedProject.SubProjectCount > 0 {
+		childAction := views.Action{
+			ID:    "show_children",
+			Label: fmt.Sprintf("📂 Show child projects (%d)", m.selectedProject.SubProjectCount),
+			Desc:  "View projects within this monorepo",
+		}
+		// Insert after "Open in editor" (usually index 1)
+		insertIdx := 1
+		if len(actions) > insertIdx {
+			actions = append(actions[:insertIdx+1], append([]views.Action{childAction}, actions[insertIdx+1:]...)...)
+		} else {
+			actions = append([]views.Action{childAction}, actions...)
+		}
+	}
+
+	// Start, stop and restart the dev server
+	actions = insertBeforeBack(actions, m.serverActions()...)
+
+	// Snippets from the library
+	if snippets := m.snippetAction(); snippets != nil {
+		actions = insertBeforeBack(actions, *snippets)
+	}
+
+	// Get plugin actions
+	actions = insertBeforeBack(actions, pluginActions...)
+
+	return orderMenu(actions, m.config.Actions, m.selectedProject.Name)
+}
+
+// orderMenu applies the actions.menu overrides: hidden actions, and ones
+// disabled for the project, are left out and positioned ones moved to their
+// place, recursing into submenus. The back action can't be moved or hidden
+// and stays last.
+func orderMenu(actions []views.Action, cfg config.ActionsConfig, projectName string) []views.Action {
+	var kept, moved []views.Action
+	for _, a := range actions {
+		override, ok := cfg.MenuFor(a.ID)
+		if a.ID == "back" {
+			ok = false
+		} else if cfg.IsDisabled(projectName, a.ID) {
+			continue
+		}
+		if ok && override.Hidden {
+			continue
+		}
+		if len(a.Children) > 0 {
+			if a.Children = orderMenu(a.Children, cfg, projectName); len(a.Children) == 0 {
+				continue // Everything in the submenu is hidden
+			}
+		}
+		if ok && override.Position > 0 {
+			moved = append(moved, a)
+		} else {
+			kept = append(kept, a)
+		}
+	}
+
+	position := func(a views.Action) int {
+		override, _ := cfg.MenuFor(a.ID)
+		return override.Position
+	}
+	sort.SliceStable(moved, func(i, j int) bool { return position(moved[i]) < position(moved[j]) })
+	for _, a := range moved {
+		limit := len(kept)
+		if limit > 0 && kept[limit-1].ID == "back" {
+			limit--
+		}
+		kept = slices.Insert(kept, min(position(a)-1, limit), a)
+	}
+	return kept
+}
+
+// insertBeforeBack inserts extra before the "back" action, which stays last
+func insertBeforeBack(actions []views.Action, extra ...views.Action) []views.Action {
+	if len(actions) == 0 {
+		return append(actions, extra...)
+	}
+	// Find the back action
+	backIdx := len(actions) - 1
+	for i, a := range actions {
+		if a.ID == "back" {
+			backIdx = i
+			break
+		}
+	}
+	tail := append(append([]views.Action{}, extra...), actions[backIdx:]...)
+	return append(actions[:backIdx], tail...)
+}
+
+// runAction executes a (non-submenu) action for the selected project
+func (m Model) runAction(action *views.Action) (tea.Model, tea.Cmd) {
+	// Shortcuts like rerun and reopen bypass the menu, so check here too
+	if m.config.Actions.IsDisabled(m.selectedProject.Name, action.ID) {
+		return m.Update(actionCompleteMsg{
+			success:     false,
+			message:     fmt.Sprintf("%s is disabled by actions.disabled in the config", action.Label),
+			actionLabel: action.Label,
+		})
+	}
+	// Special handling for git-branch - show interactive picker
+	if action.ID == "git-branch" {
+		m.setView(ViewExecuting)
+		m.message = "Loading branches..."
+		return m, loadBranches(m.selectedProject.Path)
+	}
+	// Generating a .gitignore is previewed first; confirming the preview runs it
+	if action.ID == "generate-gitignore" && m.view != ViewGitignore {
+		return m.previewGitignore(action)
+	}
+	// Cleaning lists the artifacts to pick from first
+	if action.ID == "clean" {
+		return m.previewClean(action)
+	}
+	// New branches are named from a type and description
+	if action.ID == "git-new-branch" {
+		return m.promptNewBranch()
+	}
+	// Duplicating asks for the name of the copy first
+	if action.ID == "duplicate" {
+		m.newProject = m.newProjectModel().ForDuplicate(m.selectedProject.Name, m.selectedProject.IsGitRepo)
+		m.duplicateSource = m.selectedProject
+		m.setView(ViewNewProject)
+		m.updateSizes()
+		return m, m.newProject.Init()
+	}
+	// Archiving only records the project in the state
+	if action.ID == "archive" || action.ID == "unarchive" {
+		return m.setArchived(m.selectedProject, action.ID == "archive")
+	}
+	// Commands that would modify a protected branch are confirmed first
+	command := action.Command
+	if action.ID == "git-commit" {
+		command = "git commit"
+	}
+	if command != "" && !m.protectedOK {
+		if warning := actions.NewExecutor(m.config).ProtectedBranchWarning(command, m.selectedProject); warning != "" {
+			m.protectedAction = action
+			m.protectedWarning = warning
+			m.setView(ViewConfirmProtected)
+			return m, nil
+		}
+	}
+	m.protectedOK = false
+	// Container actions check the daemon is up first, offering to start it
+	if needsDaemon(action.ID) && !m.daemonOK {
+		m.setView(ViewExecuting)
+		m.message = "Checking the container daemon..."
+		return m, checkDaemon(*action, docker.Runtime(m.config.Container.Runtime))
+	}
+	m.daemonOK = false
+	// Committing asks for the message first
+	if action.ID == "git-commit" {
+		return m.promptCommit()
+	}
+	// Building asks for the build args and target the Dockerfile declares
+	if action.ID == "docker-build" {
+		if model, cmd, ok := m.promptDockerBuild(action); ok {
+			return model, cmd
+		}
+	}
+	running := *action
+	m.runningAction = &running
+	// Dev servers run in the background and are tracked in the state
+	if strings.HasPrefix(action.ID, "server-") {
+		return m.runServerAction(action)
+	}
+	// Database clients run attached to the terminal
+	if service, ok := strings.CutPrefix(action.ID, "db-shell-"); ok {
+		return m, openDatabaseShell(action.Label, service, m.selectedProject, m.config)
+	}
+	// Backups of large projects take a while, so stream progress
+	if action.ID == "backup" {
+		m.setView(ViewExecuting)
+		m.message = fmt.Sprintf("Backing up %s...", m.selectedProject.Name)
+		return m, startBackup(action.Label, m.selectedProject, m.config)
+	}
+	m.setView(ViewExecuting)
+	m.message = fmt.Sprintf("Executing: %s...", action.Label)
+
+	parser := action.Parser
+	if parser == "" {
+		parser = m.config.Actions.ParserFor(action.ID)
+	}
+	return m, withParser(executeAction(action.ID, action.Label, action.Command, m.selectedProject, m.config, m.pluginRegistry), parser)
+}
+
+// dryRun shows what an action would run instead of running it
+func (m Model) dryRun(action *views.Action) (tea.Model, tea.Cmd) {
+	message := ""
+	for _, pa := range m.getPluginActions(m.selectedProject) {
+		if pa.ID == action.ID {
+			message = fmt.Sprintf("Directory: %s\n\n%s is provided by a plugin, which can't describe what it runs", m.selectedProject.Path, action.Label)
+			break
+		}
+	}
+	if message == "" {
+		message = actions.NewExecutor(m.config).DryRun(action.ID, action.Command, m.selectedProject).String()
+	}
+
+	return m.Update(actionCompleteMsg{
+		success:     true,
+		message:     message,
+		actionLabel: "Dry run: " + action.Label,
+	})
+}
+
+// withParser parses the output of an action with its declared output parser.
+// If parsing fails the raw output is shown with a note.
+func withParser(cmd tea.Cmd, parser string) tea.Cmd {
+	if parser == "" {
+		return cmd
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		complete, ok := msg.(actionCompleteMsg)
+		if !ok || complete.summary != nil {
+			return msg
+		}
+		summary, err := results.Parse(parser, complete.message)
+		if err != nil {
+			complete.message = fmt.Sprintf("(%s parser: %v)\n\n%s", parser, err, complete.message)
+			return complete
+		}
+		complete.summary = summary
+		return complete
+	}
+}
+
+// WithStartup makes the TUI open directly into a project's action menu once
+// projects are loaded, optionally running an action straight away
+func (m Model) WithStartup(projectName, actionID string) Model {
+	m.startProject = projectName
+	m.startAction = actionID
+	return m
+}
+
+// FromURL marks the startup target as coming from a proj:// link, which
+// anything can open: it must name a project exactly, not guess as proj <name>
+// does, and its action only runs once confirmed
+func (m Model) FromURL() Model {
+	m.startFromURL = true
+	return m
+}
+
+// applyStartup handles the startup target requested via WithStartup
+func (m Model) applyStartup() (tea.Model, tea.Cmd) {
+	name, actionID := m.startProject, m.startAction
+	m.startProject, m.startAction = "", ""
+
+	var proj *project.Project
+	if m.startFromURL {
+		proj = project.FindExact(m.projects, name)
+	} else {
+		proj = project.Find(m.projects, name)
+	}
+	if proj == nil && m.startFromURL {
+		m.toasts.Error(fmt.Errorf("no project has exactly the path, alias or name %s", name))
+		return m, nil
+	}
+	if proj == nil {
+		m.toasts.Error(fmt.Errorf("project not found: %s", name))
+		return m, nil
+	}
+
+	if proj.IsGroup {
+		m.selectedGroup = proj
+		m.groupProjects = m.getChildProjects(proj.Path)
+		m.groupList = m.newGroupList(m.groupProjects)
+		m.setView(ViewGroup)
+		m.updateSizes()
+		return m, nil
+	}
+
+	m.selectedProject = proj
+	load := m.openActionMenu()
+
+	if actionID == "" {
+		return m, load
+	}
+
+	action := views.FindAction(m.projectActions(), actionID)
+	if action == nil || action.IsSubmenu {
+		m.toasts.Error(fmt.Errorf("action not available for %s: %s", proj.Name, actionID))
+		m.setView(ViewProjects)
+		m.selectedProject = nil
+		return m, nil
+	}
+	if m.startFromURL {
+		m.urlAction = action
+		m.setView(ViewConfirmURL)
+		return m, load
+	}
+	return m.runAction(action)
+}
+
+// WithStartDir selects the project containing dir once projects are loaded,
+// or opens its action menu, as display.currentProject says
+func (m Model) WithStartDir(dir string) Model {
+	m.startDir = dir
+	return m
+}
+
+// applyStartDir handles the launch directory set via WithStartDir. Only the
+// first scan uses it, so a rescan keeps the user's selection.
+func (m Model) applyStartDir() (tea.Model, tea.Cmd) {
+	dir := m.startDir
+	m.startDir = ""
+
+	mode := m.config.Display.CurrentProject
+	if mode == config.CurrentProjectOff {
+		return m, nil
+	}
+	proj := project.Containing(m.projects, dir)
+	if proj == nil {
+		return m, nil
+	}
+
+	// Projects inside a group aren't in the main list; select the group instead
+	shown := proj
+	for shown.Depth > 0 {
+		parent := project.Find(m.projects, shown.ParentPath)
+		if parent == nil {
+			break
+		}
+		shown = parent
+	}
+	m.projectList.SelectProject(shown)
+
+	if mode != config.CurrentProjectMenu {
+		return m, nil
+	}
+	m.startProject = proj.Path
+	return m.applyStartup()
+}
+
+// Names text input history is kept under in state
+const (
+	inputNewProject = "newProject"
+	inputEach       = "each"
+	inputBranch     = "branch"
+	inputCommit     = "commit"
+)
+
+// recordInput adds a submitted value to a text input's history
+func (m *Model) recordInput(name, value string) {
+	m.state.RecordInput(name, value)
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+	}
+}
+
+// recordOpen remembers how a project was opened so it can be reopened the same way
+func (m *Model) recordOpen(p *project.Project, openedWith string) {
+	m.state.RecordOpen(p.Path, openedWith)
+	m.state.Annotate([]*project.Project{p})
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+	}
+}
+
+// recordSelect remembers that a project's menu was opened, for frecency
+func (m *Model) recordSelect(p *project.Project) {
+	m.state.RecordSelect(p.Path)
+	m.state.Annotate([]*project.Project{p})
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+	}
+}
+
+// renderActionsView renders the actions menu view
+func (m Model) renderActionsView() string {
+	header := views.ActionHeader(
+		m.selectedProject.Name,
+		m.selectedProject.Language,
+		m.selectedProject.GitBranch,
+		m.selectedProject.GitDirty,
+	)
+
+	details := views.ProjectDetails(m.selectedProject, m.projectInfo)
+	if details != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, details)
+	}
+
+	content := m.actionMenu.View()
+
+	// Update help text based on whether we're in a submenu
+	shortcuts := ""
+	if m.selectedProject.NeedsSetup {
+		shortcuts += "b: bootstrap  •  "
+	}
+	if m.selectedProject.LastOpenedWith != "" {
+		shortcuts += "o: reopen  •  "
+	}
+	if len(m.state.History(m.selectedProject.Path)) > 0 {
+		shortcuts += ".: re-run last  •  "
+	}
+	if m.previousPath != "" {
+		shortcuts += "-: switch  •  "
+	}
+	edit := ""
+	if editable(m.actionMenu.SelectedAction()) {
+		edit = "e: edit & run  •  "
+	}
+	helpText := "↑/↓: navigate  •  enter: execute  •  " + edit + "d: dry run  •  h: history  •  " + shortcuts + "esc: back  •  q: quit"
+	if len(m.submenuStack) > 0 {
+		helpText = "↑/↓: navigate  •  enter: select  •  " + edit + "esc: back to menu  •  q: quit"
+	}
+	help := tui.HelpStyle.Render(helpText)
+
+	return tui.ContainerStyle.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Left,
 			header,
@@ -732,6 +2024,14 @@ func (m Model) renderActionsView() string {
 	)
 }
 
+// resultContent returns the parsed summary or the raw output, whichever is showing
+func (m Model) resultContent() string {
+	if m.resultSummary != nil && !m.resultShowRaw {
+		return m.resultSummary.String()
+	}
+	return m.resultRaw
+}
+
 // renderResultView renders the action result view
 func (m Model) renderResultView() string {
 	// Status icon and title
@@ -762,13 +2062,65 @@ func (m Model) renderResultView() string {
 		Width(m.width - 6).
 		Render(m.resultViewport.View())
 
+	shortcuts := ""
+	if len(m.problems) > 0 {
+		shortcuts += "n/p: select problem  •  enter: open in editor  •  "
+	}
+	if m.resultPostInit != "" {
+		shortcuts += "i: run post-init command  •  "
+	}
+	if len(m.resultRetry) > 0 {
+		shortcuts += "r: retry with prompts  •  "
+	} else if m.resultSummary != nil {
+		if m.resultShowRaw {
+			shortcuts += "r: summary  •  "
+		} else {
+			shortcuts += "r: raw output  •  "
+		}
+	}
+	help := tui.HelpStyle.Render("↑/↓: scroll  •  " + shortcuts + "esc/q: close")
+
+	sections := []string{header, "", content}
+	if len(m.problems) > 0 {
+		sections = append(sections, views.ProblemList(m.problems, m.selectedProject.Path, m.problemIndex, m.width-6))
+	}
+	sections = append(sections, "", help)
+
+	return tui.ContainerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// resultViewportHeight leaves room below the result output for the problem list
+func (m Model) resultViewportHeight() int {
+	height := m.height - 10
+	if n := len(m.problems); n > 0 {
+		if n > views.ProblemListRows {
+			n = views.ProblemListRows
+		}
+		height -= n + 2
+	}
+	if height < 3 {
+		height = 3
+	}
+	return height
+}
+
+// renderScanIssuesView renders the list of scan issues with suggested fixes
+func (m Model) renderScanIssuesView() string {
+	title := tui.TitleStyle.Render(fmt.Sprintf("⚠ Scan Issues (%d)", len(m.diagnostics)))
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Width(m.width - 6).
+		Render(m.issuesViewport.View())
+
 	help := tui.HelpStyle.Render("↑/↓: scroll  •  esc/q: close")
 
 	return tui.ContainerStyle.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Left,
-			header,
-			"",
+			title,
 			content,
 			"",
 			help,
@@ -776,6 +2128,30 @@ func (m Model) renderResultView() string {
 	)
 }
 
+// renderEachPromptView renders the prompt for a command to run in marked projects
+func (m Model) renderEachPromptView() string {
+	title := tui.TitleStyle.Render(fmt.Sprintf("▶ Run in %d project(s)", len(m.eachTargets)))
+
+	names := make([]string, len(m.eachTargets))
+	for i, p := range m.eachTargets {
+		names[i] = p.Name
+	}
+	targets := tui.SubtitleStyle.Render(strings.Join(names, ", "))
+
+	help := tui.HelpStyle.Render("enter: run (skipping unchanged projects)  •  ctrl+f: run in all  •  ↑/↓: history  •  esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			targets,
+			m.eachInput.View(),
+			"",
+			help,
+		),
+	)
+}
+
 // renderBranchesView renders the branch selection view
 func (m Model) renderBranchesView() string {
 	header := views.ActionHeader(
@@ -833,6 +2209,76 @@ func (m Model) renderConfirmStashView() string {
 	)
 }
 
+// renderConfirmProtectedView asks before running a command that would
+// modify a protected branch
+func (m Model) renderConfirmProtectedView() string {
+	header := tui.TitleStyle.Render("⚠️  Protected Branch")
+
+	message := fmt.Sprintf(
+		"%s in %s %s.\n\n"+
+			"  %s\n\n"+
+			"  [Y] Yes, run it anyway\n"+
+			"  [N] No, cancel",
+		m.protectedAction.Label,
+		m.selectedProject.Name,
+		m.protectedWarning,
+		m.protectedAction.Command,
+	)
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(1, 2).
+		Render(message)
+
+	help := tui.HelpStyle.Render("y: run anyway  •  n/esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			help,
+		),
+	)
+}
+
+// renderConfirmURLView asks before running an action a proj:// link asked for
+func (m Model) renderConfirmURLView() string {
+	header := tui.TitleStyle.Render("🔗 Run Action from Link")
+
+	message := fmt.Sprintf(
+		"A proj:// link asks to run %s in %s.\n\n"+
+			"  %s\n\n"+
+			"  [Y] Yes, run it\n"+
+			"  [N] No, just show the project's actions",
+		m.urlAction.Label,
+		m.selectedProject.Name,
+		m.selectedProject.Path,
+	)
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(1, 2).
+		Render(message)
+
+	help := tui.HelpStyle.Render("y: run  •  n/esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			help,
+		),
+	)
+}
+
 // GetCdPath returns the path to change to on exit
 func (m Model) GetCdPath() string {
 	return m.cdPath
@@ -843,26 +2289,74 @@ func (m Model) GetExecCmd() []string {
 	return m.execCmd
 }
 
-// loadProjects loads projects from the repos path
-func loadProjects(cfg *config.Config) tea.Cmd {
+// loadProjects loads projects from the repos path and those contributed by plugins
+func loadProjects(cfg *config.Config, registry *plugin.Registry) tea.Cmd {
 	return func() tea.Msg {
 		scanner := project.NewScanner(cfg)
-		projects, err := scanner.Scan(cfg.ReposPath)
+		projects, err := scanner.Discover(cfg.ReposPath)
 		if err != nil {
-			return errMsg(err)
+			return loadFailed(loadingProjects, cfg.ReposPath, err)
+		}
+		if registry != nil {
+			for _, p := range registry.GetProjects() {
+				projects = append(projects, projectFromPlugin(p))
+			}
 		}
 
 		// Sort projects
 		sortBy := project.SortBy(cfg.Display.SortBy)
 		projects = project.Sort(projects, sortBy)
 
-		return projectsLoadedMsg(projects)
+		hookErr := hooks.Run(cfg, hooks.PostScan, nil,
+			"PROJ_REPOS_PATH="+config.ExpandPath(cfg.ReposPath),
+			fmt.Sprintf("PROJ_PROJECT_COUNT=%d", len(projects)))
+
+		return projectsLoadedMsg{projects: projects, diagnostics: scanner.Diagnostics(), hookErr: hookErr}
+	}
+}
+
+// enrichProjects fills in the details of projects found by Discover in the
+// background, sending a projectEnrichedMsg as each one finishes. Messages
+// are tagged with gen so those of an earlier scan can be told apart.
+func enrichProjects(cfg *config.Config, projects []*project.Project, gen int) tea.Cmd {
+	paths := make([]string, 0, len(projects))
+	for _, p := range projects {
+		if p.Enriching {
+			paths = append(paths, p.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
 	}
+
+	updates := make(chan tea.Msg)
+	go func() {
+		scanner := project.NewScanner(cfg)
+		jobs := make(chan string)
+		var wg sync.WaitGroup
+		for i := 0; i < bulk.DefaultConcurrency(); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for path := range jobs {
+					updates <- projectEnrichedMsg{gen: gen, path: path, details: scanner.Details(path), updates: updates}
+				}
+			}()
+		}
+		for _, path := range paths {
+			jobs <- path
+		}
+		close(jobs)
+		wg.Wait()
+		updates <- enrichmentDoneMsg{gen: gen}
+	}()
+
+	return waitForUpdate(updates)
 }
 
 // loadProjects is a method wrapper for loading projects
 func (m Model) loadProjects() tea.Cmd {
-	return loadProjects(m.config)
+	return loadProjects(m.config, m.pluginRegistry)
 }
 
 // loadProjectsAndRefreshGroup loads projects and lets Update refresh the view
@@ -870,18 +2364,40 @@ func (m *Model) loadProjectsAndRefreshGroup() tea.Cmd {
 	// Delegate to the existing project loading command. The model and any
 	// relevant views will be updated in the Update method when the
 	// corresponding message (e.g. projectsLoadedMsg) is received.
-	return loadProjects(m.config)
+	return loadProjects(m.config, m.pluginRegistry)
+}
+
+// recordPluginAction logs an action a plugin ran in the audit log. What the
+// plugin ran is its own business, so only the action is recorded.
+func recordPluginAction(cfg *config.Config, start time.Time, actionID string, proj *project.Project, success bool) error {
+	exitCode := 0
+	if !success {
+		exitCode = 1
+	}
+	return audit.Record(cfg, audit.Entry{
+		Time:     start,
+		Project:  proj.Name,
+		Action:   actionID,
+		Dir:      proj.Path,
+		Success:  success,
+		ExitCode: exitCode,
+	})
 }
 
 // executeAction executes an action
 func executeAction(actionID string, actionLabel string, actionCommand string, proj *project.Project, cfg *config.Config, registry *plugin.Registry) tea.Cmd {
 	return func() tea.Msg {
+		if err := hooks.Run(cfg, hooks.PreAction, proj, "PROJ_ACTION="+actionID); err != nil {
+			return actionCompleteMsg{success: false, message: err.Error(), actionLabel: actionLabel}
+		}
+
 		// If action has a command, execute it directly
 		if actionCommand != "" {
 			executor := actions.NewExecutor(cfg)
 			result := executor.ExecuteCommand(actionCommand, proj)
 			return actionCompleteMsg{
 				success:     result.Success,
+				exitCode:    result.ExitCode,
 				message:     result.Message,
 				actionLabel: actionLabel,
 				cdPath:      result.CdPath,
@@ -892,8 +2408,12 @@ func executeAction(actionID string, actionLabel string, actionCommand string, pr
 		// Try plugin actions first
 		if registry != nil {
 			pluginProj := projectToPlugin(proj)
+			start := time.Now()
 			result, err := registry.ExecuteAction(actionID, pluginProj)
 			if err == nil && result != nil {
+				if auditErr := recordPluginAction(cfg, start, actionID, proj, result.Success); auditErr != nil {
+					result.Message += "\n\n⚠ " + auditErr.Error()
+				}
 				return actionCompleteMsg{
 					success:     result.Success,
 					message:     result.Message,
@@ -902,30 +2422,107 @@ func executeAction(actionID string, actionLabel string, actionCommand string, pr
 					execCmd:     result.ExecCmd,
 				}
 			}
+			// The plugin owning the action failed, rather than no plugin having it
+			if err != nil && !errors.Is(err, plugin.ErrUnknownAction) {
+				_ = recordPluginAction(cfg, start, actionID, proj, false)
+				return actionCompleteMsg{success: false, message: err.Error(), actionLabel: actionLabel}
+			}
+		}
+
+		// Projects contributed by plugins have no built-in actions
+		if proj.Source != "" {
+			return actionCompleteMsg{
+				success:     false,
+				message:     fmt.Sprintf("Plugin %s did not handle %s", proj.Source, actionLabel),
+				actionLabel: actionLabel,
+			}
 		}
 
 		// Fall back to built-in actions
 		executor := actions.NewExecutor(cfg)
 		result := executor.Execute(actionID, proj)
+		if result.Success && result.OpenedWith != "" {
+			if err := hooks.Run(cfg, hooks.PostOpen, proj, "PROJ_EDITOR="+result.OpenedWith); err != nil {
+				result.Message += "\n\n⚠ " + err.Error()
+			}
+		}
 
 		return actionCompleteMsg{
 			success:     result.Success,
+			exitCode:    result.ExitCode,
 			message:     result.Message,
 			actionLabel: actionLabel,
 			cdPath:      result.CdPath,
 			execCmd:     result.ExecCmd,
+			openedWith:  result.OpenedWith,
+			summary:     result.Summary,
+			diverged:    result.Diverged,
+			retryArgs:   result.RetryArgs,
 		}
 	}
 }
 
+// startBackup runs the backup action in the background, streaming progress
+// messages followed by the final actionCompleteMsg
+func startBackup(actionLabel string, proj *project.Project, cfg *config.Config) tea.Cmd {
+	updates := make(chan tea.Msg, 1)
+
+	go func() {
+		defer close(updates)
+
+		if err := hooks.Run(cfg, hooks.PreAction, proj, "PROJ_ACTION=backup"); err != nil {
+			updates <- actionCompleteMsg{success: false, message: err.Error(), actionLabel: actionLabel}
+			return
+		}
+
+		lastPercent := int64(-1)
+		result := actions.NewExecutor(cfg).Backup(proj, func(done, total int64) {
+			if total == 0 {
+				return
+			}
+			percent := done * 100 / total
+			if percent == lastPercent {
+				return
+			}
+			lastPercent = percent
+			// Drop updates the UI hasn't caught up with
+			select {
+			case updates <- backupProgressMsg{done: done, total: total, updates: updates}:
+			default:
+			}
+		})
+
+		updates <- actionCompleteMsg{
+			success:     result.Success,
+			message:     result.Message,
+			actionLabel: actionLabel,
+		}
+	}()
+
+	return waitForUpdate(updates)
+}
+
+// waitForUpdate waits for the next message on a background task's channel
+func waitForUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
 // getPluginActions gets actions from plugins for a project
 func (m Model) getPluginActions(proj *project.Project) []views.Action {
-	if m.pluginRegistry == nil {
+	return pluginViewActions(m.pluginRegistry, proj)
+}
+
+// pluginViewActions returns the actions the plugins of registry offer for a
+// project, as menu actions
+func pluginViewActions(registry *plugin.Registry, proj *project.Project) []views.Action {
+	if registry == nil {
 		return nil
 	}
 
 	pluginProj := projectToPlugin(proj)
-	pluginActions := m.pluginRegistry.GetActions(pluginProj)
+	pluginActions := registry.GetActions(pluginProj)
 
 	// Convert plugin actions to view actions
 	viewActions := make([]views.Action, len(pluginActions))
@@ -941,16 +2538,107 @@ func (m Model) getPluginActions(proj *project.Project) []views.Action {
 	return viewActions
 }
 
-// projectToPlugin converts a project.Project to a plugin.Project
+// favoriteTag marks a project as a favorite for plugins
+const favoriteTag = "favorite"
+
+// projectToPlugin converts a project.Project to a plugin.Project, sharing
+// what proj knows about it so plugins needn't scan it themselves
 func projectToPlugin(proj *project.Project) plugin.Project {
-	return plugin.Project{
-		Name:      proj.Name,
-		Path:      proj.Path,
-		Language:  proj.Language,
-		GitBranch: proj.GitBranch,
-		GitDirty:  proj.GitDirty,
-		IsGitRepo: proj.IsGitRepo,
+	p := plugin.Project{
+		Name:          proj.Name,
+		Path:          proj.Path,
+		Language:      proj.Language,
+		GitBranch:     proj.GitBranch,
+		GitDirty:      proj.GitDirty,
+		IsGitRepo:     proj.IsGitRepo,
+		Source:        proj.Source,
+		Tags:          proj.Tags,
+		IsFavorite:    slices.Contains(proj.Tags, favoriteTag),
+		HasDockerfile: proj.HasDockerfile,
+	}
+
+	// Projects contributed by plugins aren't on disk to detect scripts in
+	if proj.Source == "" {
+		for _, s := range scripts.Detect(proj.WorkPath(), proj.Language) {
+			p.DetectedScripts = append(p.DetectedScripts, plugin.Script{Name: s.Name, Command: s.Command, Source: s.Source})
+		}
+	}
+
+	metadata := make(map[string]string)
+	for k, v := range map[string]string{
+		"devEnv":         proj.DevEnv,
+		"gitOperation":   proj.GitOperation,
+		"lastOpenedWith": proj.LastOpenedWith,
+		"symlinkTarget":  proj.SymlinkTarget,
+		"editor":         proj.Editor,
+		"hasCompose":     strconv.FormatBool(proj.HasCompose),
+		"usesLFS":        strconv.FormatBool(proj.UsesLFS),
+		"gitDetached":    strconv.FormatBool(proj.GitDetached),
+		"archived":       strconv.FormatBool(proj.Archived),
+		"needsSetup":     strconv.FormatBool(proj.NeedsSetup),
+	} {
+		// Leave out what doesn't apply to keep the message small
+		if v != "" && v != "false" {
+			metadata[k] = v
+		}
+	}
+	if len(metadata) > 0 {
+		p.Metadata = metadata
+	}
+	return p
+}
+
+// projectFromPlugin converts a project contributed by a plugin
+func projectFromPlugin(p plugin.Project) *project.Project {
+	return &project.Project{
+		Name:          p.Name,
+		Path:          p.Path,
+		Language:      p.Language,
+		GitBranch:     p.GitBranch,
+		GitDirty:      p.GitDirty,
+		IsGitRepo:     p.IsGitRepo,
+		Source:        p.Source,
+		Tags:          p.Tags,
+		HasDockerfile: p.HasDockerfile,
+	}
+}
+
+// newProjectModel starts the new project flow. Projects can be created at the
+// top level or in a group, starting with the group being viewed.
+func (m Model) newProjectModel() views.NewProjectModel {
+	locations := []views.ProjectLocation{{Path: config.ExpandPath(m.config.ReposPath)}}
+	selected := 0
+	groupNames := make(map[string]string) // Path -> name including the groups it's in
+	for _, p := range m.projects {
+		if !p.IsGroup && (p.Depth != 0 || p.SubProjectCount == 0) {
+			continue
+		}
+		name := p.Name
+		if parent, ok := groupNames[p.ParentPath]; ok && p.Depth > 0 {
+			name = parent + "/" + p.Name
+		}
+		groupNames[p.Path] = name
+		if m.selectedGroup != nil && p.Path == m.selectedGroup.Path {
+			selected = len(locations)
+		}
+		locations = append(locations, views.ProjectLocation{Name: name, Path: p.Path})
+	}
+	names := make([]string, 0, len(m.config.Templates))
+	for name := range m.config.Templates {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	templates := make([]views.TemplateOption, len(names))
+	for i, name := range names {
+		templates[i] = views.TemplateOption{Name: name}
+		if generator, _ := scaffold.ParseTemplate(m.config.Templates[name]); generator != "" {
+			templates[i].Note = generator
+			if !scaffold.GeneratorInstalled(generator) {
+				templates[i].Note += " (not installed)"
+			}
+		}
+	}
+	return views.NewNewProjectModel(locations, selected, m.state.InputHistory(inputNewProject)).WithTemplates(templates)
 }
 
 // createProject creates a new project directory
@@ -961,17 +2649,103 @@ func createProject(name string, reposPath string) tea.Cmd {
 
 		if err := os.MkdirAll(projectPath, 0755); err != nil {
 			return actionCompleteMsg{
-				success: false,
-				message: fmt.Sprintf("Failed to create project directory %s: %v", projectPath, err),
+				success:     false,
+				message:     fmt.Sprintf("Failed to create project directory %s: %v", projectPath, err),
 				actionLabel: "Create Project",
 			}
 		}
 
 		return actionCompleteMsg{
-			success: true,
-			message: fmt.Sprintf("Successfully created project: %s\nLocation: %s", name, projectPath),
+			success:     true,
+			message:     fmt.Sprintf("Successfully created project: %s\nLocation: %s", name, projectPath),
+			actionLabel: "Create Project",
+			created:     projectPath,
+		}
+	}
+}
+
+// createFromTemplate creates a project from one of the configured templates.
+// A post-init command the template declares is offered, not run, since the
+// template may come from anyone.
+func createFromTemplate(name, dir, tmpl string, cfg *config.Config) tea.Cmd {
+	source := cfg.Templates[tmpl]
+	return func() tea.Msg {
+		dest := filepath.Join(config.ExpandPath(dir), name)
+		if _, err := os.Lstat(dest); err == nil {
+			return actionCompleteMsg{success: false, message: dest + " already exists", actionLabel: "Create Project"}
+		}
+		remote, err := scaffold.FromTemplate(source, dest)
+		if err != nil {
+			return actionCompleteMsg{
+				success:     false,
+				message:     fmt.Sprintf("Failed to create %s from template %s (%s): %v", name, tmpl, source, err),
+				actionLabel: "Create Project",
+			}
+		}
+
+		message := fmt.Sprintf("Created project %s from template %s (%s)\nLocation: %s", name, tmpl, source, dest)
+		if remote.PostInit != "" {
+			message += fmt.Sprintf("\n\nThe template asks to run:\n  %s\n\nPress i to run it in the new project.", remote.PostInit)
+		}
+		return actionCompleteMsg{
+			success:     true,
+			message:     message,
 			actionLabel: "Create Project",
-			shouldReload: true, // Reload projects to show the new project
+			created:     dest,
+			postInit:    remote.PostInit,
+			postInitDir: dest,
+		}
+	}
+}
+
+// runGenerator runs cookiecutter or copier attached to the terminal, so it
+// can ask its questions, and reports when it exits. dest is the directory it
+// creates, or "" if the generator names it (cookiecutter), which rescans.
+func runGenerator(generator, template, dest string, cmd *exec.Cmd) tea.Cmd {
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return actionCompleteMsg{
+				success:     false,
+				message:     fmt.Sprintf("%s %s failed: %v", generator, template, err),
+				actionLabel: "Create Project",
+			}
+		}
+		return actionCompleteMsg{
+			success:      true,
+			message:      fmt.Sprintf("Created a project with %s from %s\nLocation: %s", generator, template, cmd.Dir),
+			actionLabel:  "Create Project",
+			shouldReload: dest == "",
+			created:      dest,
+		}
+	})
+}
+
+// runPostInit runs the post-init command of a new project's template in it
+func runPostInit(command, dir string, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		result := actions.NewExecutor(cfg).ExecuteCommand(command, &project.Project{Name: filepath.Base(dir), Path: dir})
+		return actionCompleteMsg{
+			success:     result.Success,
+			exitCode:    result.ExitCode,
+			message:     result.Message,
+			actionLabel: "Post-init: " + command,
+		}
+	}
+}
+
+// duplicateProject copies proj to dest, renaming it in its manifests
+func duplicateProject(proj *project.Project, dest string, initGit bool, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		result := actions.NewExecutor(cfg).Duplicate(proj, actions.DuplicateOptions{
+			Dest:    dest,
+			InitGit: initGit,
+			Rename:  actions.DuplicateRenames(proj, filepath.Base(dest)),
+		})
+		return actionCompleteMsg{
+			success:     result.Success,
+			message:     result.Message,
+			actionLabel: "Duplicate Project",
+			created:     dest,
 		}
 	}
 }
@@ -981,7 +2755,7 @@ func loadBranches(projectPath string) tea.Cmd {
 	return func() tea.Msg {
 		branches, err := git.GetBranches(projectPath)
 		if err != nil {
-			return errMsg(err)
+			return loadFailed(loadingBranches, projectPath, err)
 		}
 		return branchesLoadedMsg(branches)
 	}
@@ -1085,6 +2859,8 @@ func (m Model) nextSortBy() project.SortBy {
 	case project.SortByLastModified:
 		return project.SortByLanguage
 	case project.SortByLanguage:
+		return project.SortByFrecency
+	case project.SortByFrecency:
 		return project.SortByName
 	default:
 		return project.SortByName
@@ -1100,6 +2876,8 @@ func (m Model) getSortLabel() string {
 		return "Last Modified"
 	case project.SortByLanguage:
 		return "Language"
+	case project.SortByFrecency:
+		return "Frecency (most used)"
 	default:
 		return "Unknown"
 	}
@@ -1,9 +1,25 @@
 package app
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/bulk"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/procs"
 	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
 	"github.com/s33g/proj/pkg/plugin"
 )
 
@@ -20,8 +36,13 @@ func TestNextSortByCyclesThroughOptions(t *testing.T) {
 	}
 
 	m.currentSortBy = project.SortByLanguage
+	if next := m.nextSortBy(); next != project.SortByFrecency {
+		t.Fatalf("expected SortByFrecency after SortByLanguage, got %v", next)
+	}
+
+	m.currentSortBy = project.SortByFrecency
 	if next := m.nextSortBy(); next != project.SortByName {
-		t.Fatalf("expected SortByName after SortByLanguage, got %v", next)
+		t.Fatalf("expected SortByName after SortByFrecency, got %v", next)
 	}
 }
 
@@ -33,6 +54,7 @@ func TestGetSortLabel(t *testing.T) {
 		{project.SortByName, "Alphabetical (A-Z)"},
 		{project.SortByLastModified, "Last Modified"},
 		{project.SortByLanguage, "Language"},
+		{project.SortByFrecency, "Frecency (most used)"},
 		{"unknown", "Unknown"},
 	}
 
@@ -45,34 +67,876 @@ func TestGetSortLabel(t *testing.T) {
 }
 
 func TestProjectToPlugin(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build:\n\tgo build ./...\n"), 0644)
+
 	proj := &project.Project{
-		Name:      "proj",
-		Path:      "/tmp/proj",
-		Language:  "Go",
-		GitBranch: "main",
-		GitDirty:  true,
-		IsGitRepo: true,
+		Name:          "proj",
+		Path:          dir,
+		Language:      "Go",
+		GitBranch:     "main",
+		GitDirty:      true,
+		IsGitRepo:     true,
+		Tags:          []string{"work", "favorite"},
+		HasDockerfile: true,
+		UsesLFS:       true,
+		DevEnv:        project.DevEnvNix,
 	}
 
 	converted := projectToPlugin(proj)
 
 	expected := plugin.Project{
-		Name:      "proj",
-		Path:      "/tmp/proj",
-		Language:  "Go",
-		GitBranch: "main",
-		GitDirty:  true,
-		IsGitRepo: true,
+		Name:          "proj",
+		Path:          dir,
+		Language:      "Go",
+		GitBranch:     "main",
+		GitDirty:      true,
+		IsGitRepo:     true,
+		Tags:          []string{"work", "favorite"},
+		IsFavorite:    true,
+		HasDockerfile: true,
+		Metadata:      map[string]string{"devEnv": "nix", "usesLFS": "true"},
 	}
 
-	if converted != expected {
+	if !slices.ContainsFunc(converted.DetectedScripts, func(s plugin.Script) bool { return s.Source == "Makefile" }) {
+		t.Errorf("Expected the Makefile targets in DetectedScripts, got %+v", converted.DetectedScripts)
+	}
+	converted.DetectedScripts = nil
+	if !reflect.DeepEqual(converted, expected) {
 		t.Fatalf("projectToPlugin mismatch: got %+v, want %+v", converted, expected)
 	}
 }
 
+func TestPluginProjectRoundTrip(t *testing.T) {
+	remote := plugin.Project{Name: "api", Path: "devbox:/srv/api", Language: "Go", Source: "devbox"}
+
+	proj := projectFromPlugin(remote)
+	if proj.Source != "devbox" || proj.Enriching {
+		t.Errorf("projectFromPlugin(%+v) = %+v", remote, proj)
+	}
+	if back := projectToPlugin(proj); !reflect.DeepEqual(back, remote) {
+		t.Errorf("projectToPlugin(projectFromPlugin(p)) = %+v, want %+v", back, remote)
+	}
+
+	// Only the plugin's actions apply to projects that aren't on disk
+	m := Model{config: config.DefaultConfig(), selectedProject: proj}
+	if actions := m.projectActions(); len(actions) != 1 || actions[0].ID != "back" {
+		t.Errorf("projectActions() for a plugin project = %+v, want only back", actions)
+	}
+}
+
+func TestOrderMenu(t *testing.T) {
+	actions := []views.Action{
+		{ID: "open-editor"},
+		{ID: "cd"},
+		{ID: "submenu-git", IsSubmenu: true, Children: []views.Action{{ID: "git-log"}, {ID: "git-pull"}}},
+		{ID: "backup"},
+		{ID: "example-hello"},
+		{ID: "back"},
+	}
+	cfg := config.ActionsConfig{Menu: map[string]config.MenuOverride{
+		"example-hello": {Position: 1},
+		"git-pull":      {Position: 1},
+		"backup":        {Position: 99},
+		"cd":            {Hidden: true},
+		"back":          {Hidden: true},
+	}}
+
+	ordered := orderMenu(actions, cfg, "web")
+	var ids []string
+	for _, a := range ordered {
+		ids = append(ids, a.ID)
+	}
+	if got := strings.Join(ids, ","); got != "example-hello,open-editor,submenu-git,backup,back" {
+		t.Errorf("orderMenu() = %s", got)
+	}
+	if children := ordered[2].Children; children[0].ID != "git-pull" || actions[2].Children[0].ID != "git-log" {
+		t.Errorf("Expected git-pull first in a reordered copy of the submenu, got %+v", children)
+	}
+
+	// Submenus whose actions are all hidden are left out
+	cfg.Menu = map[string]config.MenuOverride{"git-log": {Hidden: true}, "git-pull": {Hidden: true}}
+	if ordered := orderMenu(actions, cfg, "web"); len(ordered) != len(actions)-1 {
+		t.Errorf("Expected the empty git submenu to be left out, got %+v", ordered)
+	}
+}
+
+func TestGitProjects(t *testing.T) {
+	loading := t.TempDir()
+	os.Mkdir(filepath.Join(loading, ".git"), 0755)
+
+	projects := []*project.Project{
+		{Name: "repo", IsGitRepo: true},
+		{Name: "plain"},
+		{Name: "group", IsGroup: true, IsGitRepo: true},
+		{Name: "remote", IsGitRepo: true, Source: "devbox"},
+		{Name: "loading", Path: loading, Enriching: true},
+		{Name: "loading-plain", Path: t.TempDir(), Enriching: true},
+	}
+	var names []string
+	for _, p := range gitProjects(projects) {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "repo,loading" {
+		t.Errorf("gitProjects() = %s, want repo,loading", got)
+	}
+}
+
+func TestOpenWorkspaceLeavesOutDisabled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Actions.Disabled = []string{"workspace-pull-all"}
+	m := Model{config: cfg, keys: tui.DefaultKeyMap()}
+
+	model, _ := m.openWorkspace()
+	menu := model.(Model).workspaceMenu.Actions()
+	if views.FindAction(menu, "workspace-pull-all") != nil || views.FindAction(menu, "workspace-sync-remotes") == nil {
+		t.Errorf("Expected only pull-all to be left out, got %+v", menu)
+	}
+}
+
+func TestApplyStartDir(t *testing.T) {
+	projects := []*project.Project{
+		{Name: "api", Path: "/code/api"},
+		{Name: "clients", Path: "/code/clients", IsGroup: true},
+		{Name: "acme", Path: "/code/clients/acme", ParentPath: "/code/clients", Depth: 1},
+		{Name: "web", Path: "/code/web"},
+	}
+
+	tests := []struct {
+		mode string
+		dir  string
+		want string
+	}{
+		{config.CurrentProjectSelect, "/code/web/src", "web"},
+		// A project inside a group selects the group
+		{config.CurrentProjectSelect, "/code/clients/acme", "clients"},
+		{config.CurrentProjectSelect, "/elsewhere", "api"},
+		{config.CurrentProjectOff, "/code/web", "api"},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.Display.CurrentProject = tt.mode
+		m := Model{config: cfg, view: ViewProjects, projects: projects, projectList: views.NewProjectListModel(projects)}.WithStartDir(tt.dir)
+
+		model, _ := m.applyStartDir()
+		got := model.(Model)
+		if p := got.projectList.SelectedProject(); p == nil || p.Name != tt.want {
+			t.Errorf("%s %s: selected %v, want %s", tt.mode, tt.dir, p, tt.want)
+		}
+		if got.startDir != "" || got.view != ViewProjects {
+			t.Errorf("%s %s: startDir %q, view %v", tt.mode, tt.dir, got.startDir, got.view)
+		}
+	}
+}
+
 func TestJoinMessages(t *testing.T) {
 	msg := joinMessages([]string{"first line", "second line", "third"})
 	if msg != "first line\nsecond line\nthird" {
 		t.Fatalf("unexpected joinMessages result: %q", msg)
 	}
 }
+
+func TestWithParser(t *testing.T) {
+	complete := func(message string) tea.Cmd {
+		return func() tea.Msg {
+			return actionCompleteMsg{success: false, message: message}
+		}
+	}
+
+	jest := `{"numPassedTests":2,"numFailedTests":1,"testResults":[{"assertionResults":[{"fullName":"adds","status":"failed"}]}]}`
+	msg := withParser(complete("Command failed: exit status 1\n\n"+jest), "jest-json")().(actionCompleteMsg)
+	if msg.summary == nil || msg.summary.Passed != 2 || msg.summary.Failed != 1 {
+		t.Fatalf("Expected parsed summary, got %+v", msg.summary)
+	}
+	if !strings.Contains(msg.message, jest) {
+		t.Errorf("Expected raw output to be kept, got %q", msg.message)
+	}
+
+	msg = withParser(complete("not json"), "jest-json")().(actionCompleteMsg)
+	if msg.summary != nil || !strings.Contains(msg.message, "jest-json parser") {
+		t.Errorf("Expected raw output with a parser note, got %+v", msg)
+	}
+}
+
+func TestRecordRun(t *testing.T) {
+	s, err := state.LoadFrom(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Model{state: s}
+	proj := &project.Project{Name: "proj", Path: "/tmp/proj"}
+
+	m.recordRun(proj, views.Action{ID: "run-tests", Label: "Run tests"}, actionCompleteMsg{success: false})
+	m.recordRun(proj, views.Action{ID: "script-build", Label: "build", Command: "make build"}, actionCompleteMsg{success: false, exitCode: 2})
+	m.recordRun(proj, views.Action{ID: "open-editor", Label: "Open in editor"}, actionCompleteMsg{success: true})
+
+	history := s.History(proj.Path)
+	if len(history) != 2 {
+		t.Fatalf("expected 2 runs (open-editor is not recorded), got %d", len(history))
+	}
+	if history[0].ActionID != "script-build" || history[0].Command != "make build" || history[0].ExitCode != 2 {
+		t.Errorf("unexpected latest run: %+v", history[0])
+	}
+	if history[1].ExitCode != 1 {
+		t.Errorf("failed built-in action should record exit code 1, got %d", history[1].ExitCode)
+	}
+}
+
+func TestSnippetAction(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Snippets = []config.Snippet{
+		{Name: "Tidy modules", Command: "go mod tidy", Languages: []string{"go"}},
+		{Name: "Open PR", Command: "gh pr create --head {branch}"},
+		{Name: "Lint", Command: "npm run lint", Languages: []string{"TypeScript"}},
+	}
+	m := Model{config: cfg, selectedProject: &project.Project{Name: "api", Language: "Go", GitBranch: "feature/x y"}}
+
+	action := m.snippetAction()
+	if action == nil || len(action.Children) != 2 {
+		t.Fatalf("expected the Go and language-independent snippets, got %+v", action)
+	}
+	if got := action.Children[0]; got.ID != "snippet-tidy-modules" || got.Command != "go mod tidy" {
+		t.Errorf("first snippet = %+v", got)
+	}
+	if got := action.Children[1].Command; got != "gh pr create --head 'feature/x y'" {
+		t.Errorf("placeholder expansion = %q", got)
+	}
+
+	m.selectedProject.Language = "Rust"
+	if action := m.snippetAction(); action == nil || len(action.Children) != 1 {
+		t.Errorf("expected only the language-independent snippet for Rust, got %+v", action)
+	}
+}
+
+func TestBulkRunStatus(t *testing.T) {
+	start := time.Now()
+	api := &project.Project{Name: "api"}
+	web := &project.Project{Name: "web"}
+	run := &bulkRun{command: "go test ./...", total: 3, timeout: 10 * time.Second, started: start, startAt: map[*project.Project]time.Time{}}
+
+	run.update(bulkProgressMsg{started: api})
+	run.update(bulkProgressMsg{started: web})
+	run.update(bulkProgressMsg{outcome: &bulk.Outcome{Project: api, Success: true, Duration: time.Second}})
+
+	status := run.status(start.Add(2 * time.Second))
+	for _, want := range []string{"1/3", "1 in 2s (0.5/s)", "~4s left", "⏳ web", "Last finished: ✓ api"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status is missing %q:\n%s", want, status)
+		}
+	}
+	if strings.Contains(status, "⏳ api") {
+		t.Errorf("finished project still listed as running:\n%s", status)
+	}
+}
+
+func TestSwitchTarget(t *testing.T) {
+	api := &project.Project{Name: "api", Path: "/code/api"}
+	web := &project.Project{Name: "web", Path: "/code/web"}
+	old := &project.Project{Name: "old", Path: "/code/old"}
+	st := state.New("")
+	st.Project(old.Path).LastOpenedAt = time.Now()
+	m := Model{state: st, projects: []*project.Project{api, web, old}, view: ViewProjects}
+
+	// Nothing opened this session: fall back to the state
+	if got := m.switchTarget(); got != old {
+		t.Fatalf("switchTarget() before any selection = %v, want old", got)
+	}
+
+	m.rememberSelection(api)
+	m.rememberSelection(web)
+	if got := m.switchTarget(); got != web {
+		t.Errorf("switchTarget() from the list = %v, want web", got)
+	}
+
+	// From web's menu, switch back to api
+	m.view = ViewActions
+	m.selectedProject = web
+	if got := m.switchTarget(); got != api {
+		t.Errorf("switchTarget() from web's menu = %v, want api", got)
+	}
+}
+
+func TestPluginDetail(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		status plugin.Status
+		want   string
+	}{
+		{plugin.Status{State: plugin.StateRunning, PID: 42, StartedAt: now.Add(-90 * time.Second)}, "pid 42, up 1m30s"},
+		{plugin.Status{State: plugin.StateRestarting, Restarts: 2, NextRestart: now.Add(4 * time.Second), LastError: "exited: exit status 3"}, "retrying in 4s • 2/5 restarts • exited: exit status 3"},
+		{plugin.Status{State: plugin.StateFailed, Restarts: 5, LastError: "init failed"}, "5/5 restarts • init failed"},
+		{plugin.Status{State: plugin.StateStopped}, ""},
+		{plugin.Status{State: plugin.StateRunning, PID: 7, StartedAt: now, ConfigProblems: []string{"colour is not a setting of this plugin"}}, "pid 7, up 0s • 1 ignored setting(s)"},
+	}
+	for _, tt := range tests {
+		if got := pluginDetail(tt.status, now); got != tt.want {
+			t.Errorf("pluginDetail(%+v) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestAfterCreate(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "clients/acme", "clients/new", "web"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, mode := range []string{config.AfterCreateMenu, config.AfterCreateResult} {
+		projects := []*project.Project{
+			{Name: "api", Path: filepath.Join(root, "api")},
+			{Name: "clients", Path: filepath.Join(root, "clients"), IsGroup: true, SubProjectCount: 1},
+			{Name: "acme", Path: filepath.Join(root, "clients/acme"), ParentPath: filepath.Join(root, "clients"), Depth: 1},
+		}
+		cfg := config.DefaultConfig()
+		cfg.AfterCreate = mode
+		m := Model{config: cfg, state: state.New(""), view: ViewExecuting, projects: projects, projectList: views.NewProjectListModel(projects)}
+
+		model, _ := m.Update(actionCompleteMsg{success: true, actionLabel: "Create Project", created: filepath.Join(root, "web")})
+		got := model.(Model)
+		if len(got.projects) != 4 || got.projects[3].Name != "web" {
+			t.Fatalf("%s: projects %v, want web added last", mode, got.projects)
+		}
+		if p := got.projectList.SelectedProject(); p == nil || p.Name != "web" {
+			t.Errorf("%s: selected %v in the list, want web", mode, p)
+		}
+		if mode == config.AfterCreateResult {
+			if got.view != ViewResult || got.selectedProject != nil {
+				t.Errorf("%s: view %v, selected project %v", mode, got.view, got.selectedProject)
+			}
+			continue
+		}
+		if got.view != ViewActions || got.selectedProject == nil || got.selectedProject.Name != "web" {
+			t.Errorf("%s: view %v, selected project %v, want web's actions", mode, got.view, got.selectedProject)
+		}
+
+		// A project created in a group goes under it
+		model, _ = got.Update(actionCompleteMsg{success: true, actionLabel: "Create Project", created: filepath.Join(root, "clients/new")})
+		got = model.(Model)
+		p := got.selectedProject
+		if p == nil || p.Name != "new" || p.Depth != 1 || p.ParentPath != filepath.Join(root, "clients") {
+			t.Fatalf("%s: selected project %+v, want new in clients", mode, p)
+		}
+		if projects[1].SubProjectCount != 2 {
+			t.Errorf("%s: clients has %d projects, want 2", mode, projects[1].SubProjectCount)
+		}
+	}
+}
+
+func TestNewBranch(t *testing.T) {
+	if !git.IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main", dir},
+		{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	proj := &project.Project{Name: "api", Path: dir, IsGitRepo: true, GitBranch: "main"}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), selectedProject: proj}
+	model, _ := m.promptNewBranch()
+	for _, msg := range []tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeyRunes, Runes: []rune("ABC-7 Fix the login")}} {
+		model, _ = model.Update(msg)
+	}
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to create the branch")
+	}
+	model, _ = model.Update(cmd())
+	got := model.(Model)
+	if got.view != ViewResult || !got.resultSuccess {
+		t.Fatalf("view %v, success %v: %s", got.view, got.resultSuccess, got.resultViewport.View())
+	}
+	if branch, _ := git.GetCurrentBranch(dir); branch != "fix/ABC-7-fix-the-login" || proj.GitBranch != branch {
+		t.Errorf("Switched to %q (project shows %q), want fix/ABC-7-fix-the-login", branch, proj.GitBranch)
+	}
+	if history := got.state.InputHistory(inputBranch); len(history) != 1 || history[0] != "ABC-7 Fix the login" {
+		t.Errorf("Input history = %v", history)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	if !git.IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main", dir},
+		{"-C", dir, "config", "user.name", "Ann"},
+		{"-C", dir, "config", "user.email", "ann@example.com"},
+		{"-C", dir, "commit", "--allow-empty", "-m", "initial", "--author", "Bob <bob@example.com>"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitmessage"), []byte("# Why?\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exec.Command("git", "-C", dir, "add", ".").Run()
+
+	proj := &project.Project{Name: "api", Path: dir, IsGitRepo: true, GitBranch: "feature", GitDirty: true}
+	cfg := config.DefaultConfig()
+	cfg.Commit.SignOff = true
+	m := Model{config: cfg, state: state.New(""), keys: tui.DefaultKeyMap(), selectedProject: proj}
+	model, _ := m.promptCommit()
+	if got := model.(Model).commit.All(); got {
+		t.Error("Expected the staged .gitmessage to be committed on its own")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Add message template")})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to commit")
+	}
+	model, _ = model.Update(cmd())
+	got := model.(Model)
+	if got.view != ViewResult || !got.resultSuccess {
+		t.Fatalf("view %v, success %v: %s", got.view, got.resultSuccess, got.resultViewport.View())
+	}
+	if proj.GitDirty {
+		t.Error("Expected the project to be clean after committing")
+	}
+
+	out, _ := exec.Command("git", "-C", dir, "log", "--format=%B", "-1").Output()
+	if want := "Add message template\n\nSigned-off-by: Ann <ann@example.com>\n\n"; string(out) != want {
+		t.Errorf("Committed %q, want %q", out, want)
+	}
+	if history := got.state.InputHistory(inputCommit); len(history) != 1 || history[0] != "Add message template" {
+		t.Errorf("Input history = %v", history)
+	}
+}
+
+func TestCoAuthors(t *testing.T) {
+	got := coAuthors(t.TempDir(), "Ann <ann@example.com>", []string{"ann <ANN@example.com>", "Bob <bob@example.com>", "Bob <bob@example.com>"})
+	if want := []string{"Bob <bob@example.com>"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("coAuthors = %v, want %v", got, want)
+	}
+}
+
+func TestDevServer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	t.Setenv("HOME", t.TempDir())
+	defer func(wait time.Duration) { serverStartWait = wait }(serverStartWait)
+	serverStartWait = 10 * time.Millisecond
+
+	proj := &project.Project{Name: "web", Path: t.TempDir()}
+	cfg := config.DefaultConfig()
+	cfg.DevServer.Projects = map[string]string{"web": "sleep 30"}
+	m := Model{config: cfg, state: state.New(""), keys: tui.DefaultKeyMap(), selectedProject: proj}
+	m.openActionMenu()
+
+	run := func(m Model, id string) Model {
+		t.Helper()
+		action := views.FindAction(m.actionMenu.Actions(), id)
+		if action == nil {
+			t.Fatalf("No %s action in the menu", id)
+		}
+		model, cmd := m.runAction(action)
+		if cmd != nil {
+			model, _ = model.Update(cmd())
+		}
+		got := model.(Model)
+		if !got.resultSuccess {
+			t.Fatalf("%s failed: %s", id, got.resultRaw)
+		}
+		return got
+	}
+
+	m = run(m, "server-start")
+	server := m.state.Server(proj.Path)
+	if server == nil || server.Command != "sleep 30" || proj.ServerPID != server.PID {
+		t.Fatalf("Server %+v, project pid %d", server, proj.ServerPID)
+	}
+	if views.FindAction(m.actionMenu.Actions(), "server-start") != nil {
+		t.Error("Expected no Start action while the server runs")
+	}
+
+	// The port is picked up from the server's output
+	log, _ := os.OpenFile(server.Log, os.O_APPEND|os.O_WRONLY, 0644)
+	log.WriteString("ready on http://localhost:4321\n")
+	log.Close()
+	m.openActionMenu()
+	if proj.ServerPort != 4321 || views.FindAction(m.actionMenu.Actions(), "open-port-4321") == nil {
+		t.Errorf("Expected Open localhost:4321 once the log shows the port, project port %d", proj.ServerPort)
+	}
+
+	m = run(m, "server-logs")
+	m = run(m, "server-restart")
+	if restarted := m.state.Server(proj.Path); restarted.PID == server.PID || procs.Running(server.PID) {
+		t.Errorf("Expected pid %d to be replaced after restarting, got %d", server.PID, restarted.PID)
+	}
+
+	m = run(m, "server-stop")
+	if proj.ServerPID != 0 {
+		t.Errorf("Expected the project to show no server after stopping, pid %d", proj.ServerPID)
+	}
+	if views.FindAction(m.actionMenu.Actions(), "server-start") == nil || views.FindAction(m.actionMenu.Actions(), "server-logs") == nil {
+		t.Error("Expected Start and the last run's logs after stopping")
+	}
+}
+
+func TestDaemonCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the runtime")
+	}
+	// A runtime whose daemon never answers
+	fake := filepath.Join(t.TempDir(), "fakedocker")
+	if err := os.WriteFile(fake, []byte("#!/bin/sh\necho 'Cannot connect to the daemon' >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Container.Runtime = fake
+	cfg.Container.StartCommand = "colima start --cpu 4"
+	proj := &project.Project{Name: "api", Path: t.TempDir(), HasCompose: true}
+	m := Model{config: cfg, state: state.New(""), keys: tui.DefaultKeyMap(), selectedProject: proj}
+
+	model, cmd := m.runAction(&views.Action{ID: "compose-ps", Label: "Compose PS"})
+	if cmd == nil {
+		t.Fatal("Expected the daemon to be checked first")
+	}
+	model, _ = model.Update(cmd())
+	got := model.(Model)
+	if got.view != ViewConfirmDaemon || got.daemonStart != "colima start --cpu 4" || got.daemonAction.ID != "compose-ps" {
+		t.Fatalf("view %v, start %q, action %q", got.view, got.daemonStart, got.daemonAction.ID)
+	}
+
+	model, _ = got.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if model.(Model).view != ViewActions {
+		t.Errorf("Expected n to go back to the menu, view %v", model.(Model).view)
+	}
+
+	// Actions that don't talk to the daemon aren't held up
+	if needsDaemon("docker-lint") || needsDaemon("db-url-db") || !needsDaemon("db-dump-db") || !needsDaemon("docker-build") {
+		t.Error("needsDaemon disagrees about which actions use the daemon")
+	}
+}
+
+func TestNavigationBack(t *testing.T) {
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+	proj := &project.Project{Name: "api", Path: t.TempDir()}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewProjects}
+	m.selectedProject = proj
+	m.setView(ViewActions)
+
+	// Switch Branch: the list of branches, then the result of switching
+	m.setView(ViewExecuting)
+	model, _ := m.Update(branchesLoadedMsg{"main", "dev"})
+	got := model.(Model)
+	got.setView(ViewExecuting)
+	model, _ = got.Update(branchSwitchedMsg{success: false, message: "error: local changes", branch: "dev"})
+
+	for _, want := range []View{ViewBranches, ViewActions, ViewProjects} {
+		model, _ = model.Update(esc)
+		if got := model.(Model).view; got != want {
+			t.Fatalf("back went to view %v, want %v", got, want)
+		}
+	}
+	if model.(Model).selectedProject != nil {
+		t.Error("Expected no selected project back in the list")
+	}
+
+	// Going to a view on the stack returns to it
+	m = Model{view: ViewProjects}
+	m.setView(ViewActions)
+	m.setView(ViewHistory)
+	m.setView(ViewActions)
+	if len(m.navStack) != 1 || m.navStack[0] != ViewProjects {
+		t.Errorf("stack %v, want only the project list", m.navStack)
+	}
+}
+
+func TestRescanKeepsView(t *testing.T) {
+	root := t.TempDir()
+	scan := func() []*project.Project {
+		return []*project.Project{
+			{Name: "api", Path: filepath.Join(root, "api")},
+			{Name: "web", Path: filepath.Join(root, "web")},
+		}
+	}
+	projects := scan()
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewResult, projects: projects, projectList: views.NewProjectListModel(projects)}
+	m.projectList.SelectProject(projects[1])
+	m.selectedProject = projects[1]
+
+	// A reload after an action leaves the result shown and the list as it was
+	model, _ := m.Update(projectsLoadedMsg{projects: scan()})
+	got := model.(Model)
+	if got.view != ViewResult {
+		t.Errorf("view %v after the reload, want the result", got.view)
+	}
+	if p := got.projectList.SelectedProject(); p == nil || p.Name != "web" {
+		t.Errorf("selected %v in the list, want web", p)
+	}
+	if got.selectedProject != got.projects[1] {
+		t.Error("Expected the selected project to be the rescanned one")
+	}
+}
+
+func TestRescanDropsStaleDetails(t *testing.T) {
+	root := t.TempDir()
+	scan := func() []*project.Project {
+		return []*project.Project{{Name: "api", Path: filepath.Join(root, "api"), Enriching: true}}
+	}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap()}
+	model, _ := m.Update(projectsLoadedMsg{projects: scan()})
+	model, _ = model.(Model).Update(projectsLoadedMsg{projects: scan()})
+	m = model.(Model)
+
+	// Details loaded for the first scan arrive after the second
+	model, _ = m.Update(projectEnrichedMsg{gen: m.enrichGen - 1, path: filepath.Join(root, "api"), details: project.Details{Language: "Go"}})
+	m = model.(Model)
+	if p := m.projects[0]; !p.Enriching || p.Language != "" || m.enrichPending != 1 {
+		t.Fatalf("Expected stale details dropped, got language %q, enriching %v, %d pending", p.Language, p.Enriching, m.enrichPending)
+	}
+
+	model, _ = m.Update(projectEnrichedMsg{gen: m.enrichGen, path: filepath.Join(root, "api"), details: project.Details{Language: "Rust"}})
+	m = model.(Model)
+	if p := m.projects[0]; p.Enriching || p.Language != "Rust" || m.enrichPending != 0 {
+		t.Errorf("Expected the current scan's details applied, got language %q, enriching %v, %d pending", p.Language, p.Enriching, m.enrichPending)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ReposPath = filepath.Join(t.TempDir(), "missing")
+	m := Model{config: cfg, state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewLoading}
+
+	model, _ := m.Update(loadProjects(cfg, nil)())
+	got := model.(Model)
+	if got.view != ViewError {
+		t.Fatalf("view %v after a failed scan, want the error view", got.view)
+	}
+	if cause := loadErrorCause(got.loadError.what, got.loadError.path, got.loadError.err); !strings.Contains(cause, "doesn't exist") {
+		t.Errorf("cause %q, want the missing path", cause)
+	}
+
+	// r scans again
+	model, cmd := got.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if model.(Model).view != ViewLoading || cmd == nil {
+		t.Fatalf("view %v after retrying, want a rescan", model.(Model).view)
+	}
+	if err := os.Mkdir(cfg.ReposPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	model, _ = model.Update(cmd())
+	if model.(Model).view != ViewProjects {
+		t.Errorf("view %v after a successful retry, want the projects", model.(Model).view)
+	}
+
+	// Branches of a directory that isn't a repository, from the menu
+	dir := t.TempDir()
+	m = Model{config: cfg, state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewActions}
+	m.setView(ViewExecuting)
+	model, _ = m.Update(loadBranches(dir)())
+	got = model.(Model)
+	if got.view != ViewError || !strings.Contains(loadErrorCause(got.loadError.what, dir, got.loadError.err), "isn't a git repository") {
+		t.Fatalf("view %v, load error %+v", got.view, got.loadError)
+	}
+	model, _ = got.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(Model).view != ViewActions {
+		t.Errorf("view %v after esc, want the menu", model.(Model).view)
+	}
+}
+
+func TestPluginActionsLoaded(t *testing.T) {
+	proj := &project.Project{Name: "api", Path: t.TempDir()}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), selectedProject: proj}
+	m.pluginActionsPath, m.pluginsLoading = proj.Path, true
+	m.actionMenu = views.NewActionMenuModel(proj, m.menuActions([]views.Action{views.PluginsLoadingAction()}))
+	if views.FindAction(m.actionMenu.Actions(), views.PluginsLoadingID) == nil {
+		t.Fatal("Expected the placeholder while plugins load")
+	}
+
+	// Actions for a project whose menu was left are dropped
+	model, _ := m.Update(pluginActionsLoadedMsg{path: "/elsewhere", actions: []views.Action{{ID: "other-deploy", Label: "Deploy"}}})
+	if views.FindAction(model.(Model).actionMenu.Actions(), "other-deploy") != nil {
+		t.Error("Expected another project's plugin actions to be dropped")
+	}
+
+	model, _ = model.Update(pluginActionsLoadedMsg{path: proj.Path, actions: []views.Action{{ID: "acme-deploy", Label: "Deploy"}}})
+	actions := model.(Model).actionMenu.Actions()
+	if views.FindAction(actions, "acme-deploy") == nil || views.FindAction(actions, views.PluginsLoadingID) != nil {
+		t.Errorf("Expected the plugin action in place of the placeholder, got %+v", actions)
+	}
+	if actions[len(actions)-1].ID != "back" {
+		t.Error("Expected back to stay last")
+	}
+}
+
+func TestProjectInfoLoaded(t *testing.T) {
+	if !git.IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main", dir},
+		{"-C", dir, "config", "user.name", "Ann"},
+		{"-C", dir, "config", "user.email", "ann@example.com"},
+		{"-C", dir, "commit", "--allow-empty", "-m", "initial"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	proj := &project.Project{Name: "api", Path: dir, IsGitRepo: true}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), selectedProject: proj}
+	m.openActionMenu()
+	if m.projectInfo.Me != "" {
+		t.Fatal("Expected the menu to open before its details are loaded")
+	}
+
+	msg := loadProjectInfo(proj, state.Contributors{})()
+	model, _ := m.Update(msg)
+	m = model.(Model)
+	if m.projectInfo.Me != "Ann" || len(m.projectInfo.Contributors) != 1 {
+		t.Fatalf("projectInfo = %+v, want Ann's commit", m.projectInfo)
+	}
+	cached := m.state.CachedContributors(dir)
+	if cached.Head == "" || len(cached.Authors) != 1 {
+		t.Errorf("Expected the contributors cached, got %+v", cached)
+	}
+
+	// Details of a project whose menu was left aren't shown
+	m.selectedProject = &project.Project{Name: "web", Path: t.TempDir()}
+	m.projectInfo = views.ProjectInfo{}
+	model, _ = m.Update(msg)
+	if model.(Model).projectInfo.Me != "" {
+		t.Error("Expected another project's details to be dropped")
+	}
+}
+
+func TestEditCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs echo")
+	}
+	proj := &project.Project{Name: "api", Path: t.TempDir()}
+	action := views.Action{ID: "npm-greet", Label: "greet", Command: "echo hello"}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewActions, selectedProject: proj}
+	m.actionMenu = views.NewActionMenuModel(proj, []views.Action{action})
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	got := model.(Model)
+	if got.view != ViewEditCommand || got.editInput.Value() != "echo hello" {
+		t.Fatalf("view %v, input %q, want the command to edit", got.view, got.editInput.Value())
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" world")})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to run the edited command")
+	}
+	model, _ = model.Update(cmd())
+	got = model.(Model)
+	if got.view != ViewResult || !strings.Contains(got.resultRaw, "hello world") {
+		t.Fatalf("view %v, output %q, want the edited command's output", got.view, got.resultRaw)
+	}
+	if selected := got.actionMenu.SelectedAction(); selected == nil || selected.Command != "echo hello" {
+		t.Errorf("Expected the action to keep its command, got %+v", selected)
+	}
+
+	// Built-in actions have no command to edit
+	m.actionMenu = views.NewActionMenuModel(proj, []views.Action{{ID: "cd", Label: "Change Directory"}})
+	if model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")}); model.(Model).view != ViewActions {
+		t.Error("Expected e to do nothing on a built-in action")
+	}
+}
+
+func TestTogglePin(t *testing.T) {
+	projects := []*project.Project{
+		{Name: "api", Path: "/code/api"},
+		{Name: "web", Path: "/code/web"},
+	}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewProjects,
+		projects: projects, projectList: views.NewProjectListModel(projects), currentSortBy: project.SortByName}
+	m.projectList.SelectProject(projects[1])
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	got := model.(Model)
+	if !got.state.Project("/code/web").Pinned || got.projects[0].Name != "web" {
+		t.Fatalf("Expected web to be pinned and listed first, got %v", got.projects)
+	}
+	if selected := got.projectList.SelectedProject(); selected == nil || selected.Name != "web" {
+		t.Errorf("Expected the selection to follow web, got %+v", selected)
+	}
+
+	model, _ = got.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	got = model.(Model)
+	if got.state.Project("/code/web").Pinned || got.projects[0].Name != "api" {
+		t.Errorf("Expected web to be unpinned and sorted by name again, got %v", got.projects)
+	}
+}
+
+func TestArchiveProject(t *testing.T) {
+	projects := []*project.Project{
+		{Name: "api", Path: "/code/api"},
+		{Name: "web", Path: "/code/web"},
+	}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewActions,
+		projects: projects, projectList: views.NewProjectListModel(projects), selectedProject: projects[1]}
+
+	action := views.ArchiveAction(projects[1])
+	model, _ := m.runAction(&action)
+	got := model.(Model)
+	if !got.state.Project("/code/web").Archived || !projects[1].Archived {
+		t.Fatal("Expected web to be archived")
+	}
+	if selected := got.projectList.SelectedProject(); got.view != ViewProjects || selected == nil || selected.Name != "api" {
+		t.Fatalf("Expected to be back in the list without web, got view %v with %+v selected", got.view, selected)
+	}
+
+	model, _ = got.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	got = model.(Model)
+	if selected := got.projectList.SelectedProject(); !got.showArchived || selected == nil || selected.Name != "web" {
+		t.Fatalf("Expected a to list only the archived web, got %+v", selected)
+	}
+
+	got.selectedProject = projects[1]
+	if action = views.ArchiveAction(projects[1]); action.ID != "unarchive" {
+		t.Fatalf("Expected archived projects to be restorable, got %s", action.ID)
+	}
+	model, _ = got.runAction(&action)
+	got = model.(Model)
+	if selected := got.projectList.SelectedProject(); got.state.Project("/code/web").Archived || selected != nil {
+		t.Errorf("Expected web to be restored and gone from the archived list, got %+v", selected)
+	}
+}
+
+func TestStartupFromURL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projects := []*project.Project{
+		{Name: "api", Path: t.TempDir()},
+		{Name: "api-gateway", Path: t.TempDir()},
+	}
+	m := Model{config: config.DefaultConfig(), state: state.New(""), keys: tui.DefaultKeyMap(), view: ViewProjects, projects: projects}
+
+	// Links don't guess at names
+	model, _ := m.WithStartup("gateway", "cd").FromURL().applyStartup()
+	if got := model.(Model); got.selectedProject != nil {
+		t.Fatalf("Expected a partial name from a link to be refused, got %s", got.selectedProject.Name)
+	}
+
+	model, _ = m.WithStartup("api", "cd").FromURL().applyStartup()
+	got := model.(Model)
+	if got.view != ViewConfirmURL || got.selectedProject != projects[0] || got.cdPath != "" {
+		t.Fatalf("Expected cd in api to await confirmation, got view %v", got.view)
+	}
+	model, _ = got.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if got := model.(Model); got.view != ViewActions || got.cdPath != "" {
+		t.Errorf("Expected n to leave the action menu open without running cd, got view %v", got.view)
+	}
+}
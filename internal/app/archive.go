@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/project"
+)
+
+// toggleArchivedView switches the project list between the archived
+// projects and the others
+func (m *Model) toggleArchivedView() tea.Cmd {
+	m.showArchived = !m.showArchived
+	return m.projectList.SetArchived(m.showArchived)
+}
+
+// setArchived archives p, hiding it from the project list, or restores it,
+// and returns to the list it was picked from. Its directory isn't touched.
+func (m Model) setArchived(p *project.Project, archived bool) (tea.Model, tea.Cmd) {
+	m.state.SetArchived(p.Path, archived)
+	m.state.Annotate([]*project.Project{p})
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+	}
+
+	cmd := m.projectList.SetProjects(m.projects)
+	if m.selectedGroup != nil {
+		cmd = tea.Batch(cmd, m.groupList.SetProjects(m.groupProjects))
+	}
+
+	if archived {
+		m.toasts.Success(fmt.Sprintf("Archived %s (a: show archived projects)", p.Name))
+	} else {
+		m.toasts.Success("Restored " + p.Name + " from the archive")
+	}
+	m.leaveActions()
+	return m, cmd
+}
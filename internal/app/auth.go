@@ -0,0 +1,37 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+)
+
+// retryWithPrompts suspends the interface and reruns the failed git command
+// on the terminal, so git can ask for credentials or a key passphrase
+func retryWithPrompts(actionLabel string, args []string, proj *project.Project) tea.Cmd {
+	var out bytes.Buffer
+	cmd := git.InteractiveCommand(proj.Path, args...)
+	cmd.Stdout = io.MultiWriter(os.Stdout, &out)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &out)
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		output := strings.TrimSpace(out.String())
+		if err != nil {
+			return actionCompleteMsg{
+				success:     false,
+				message:     fmt.Sprintf("git %s failed: %v\n%s", strings.Join(args, " "), err, output),
+				actionLabel: actionLabel,
+			}
+		}
+		if output == "" {
+			output = fmt.Sprintf("git %s completed", strings.Join(args, " "))
+		}
+		return actionCompleteMsg{success: true, message: output, actionLabel: actionLabel}
+	})
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// promptNewBranch asks for the type and description of a branch to create,
+// named by the branches pattern in the config
+func (m Model) promptNewBranch() (tea.Model, tea.Cmd) {
+	m.newBranch = views.NewNewBranchModel(m.config.Branches.Pattern, m.config.Branches.Types, m.state.InputHistory(inputBranch))
+	m.setView(ViewNewBranch)
+	return m, m.newBranch.Init()
+}
+
+// handleNewBranchKey creates the branch on enter, if git accepts its name
+func (m Model) handleNewBranchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back) && msg.String() != "backspace":
+		m.setView(ViewActions)
+		return m, nil
+	case key.Matches(msg, m.keys.Enter):
+		branch := m.newBranch.Branch()
+		if branch == "" {
+			return m, nil
+		}
+		if err := git.CheckBranchName(branch); err != nil {
+			m.newBranch.SetError(err)
+			return m, nil
+		}
+		m.recordInput(inputBranch, m.newBranch.Value())
+		m.setView(ViewExecuting)
+		m.message = fmt.Sprintf("Creating branch %s...", branch)
+		return m, createBranch(m.selectedProject.Path, branch)
+	}
+
+	var cmd tea.Cmd
+	m.newBranch, cmd = m.newBranch.Update(msg)
+	return m, cmd
+}
+
+// createBranch creates a branch and switches to it, taking uncommitted
+// changes along
+func createBranch(projectPath, branch string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := git.CreateBranch(projectPath, branch)
+		if err != nil {
+			return branchSwitchedMsg{
+				success: false,
+				message: fmt.Sprintf("Failed to create branch: %v\n%s", err, out),
+				branch:  branch,
+				created: true,
+			}
+		}
+		return branchSwitchedMsg{
+			success: true,
+			message: fmt.Sprintf("Created branch '%s'\n%s", branch, out),
+			branch:  branch,
+			created: true,
+		}
+	}
+}
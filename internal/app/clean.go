@@ -0,0 +1,169 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/hooks"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// cleanPreviewMsg carries the artifacts found for the clean preview
+type cleanPreviewMsg struct {
+	artifacts []actions.Artifact
+}
+
+// cleanItem is an artifact in the clean preview and whether it's selected
+type cleanItem struct {
+	actions.Artifact
+	selected bool
+}
+
+// previewClean measures the project's build artifacts so the user can pick
+// which to remove
+func (m Model) previewClean(action *views.Action) (tea.Model, tea.Cmd) {
+	m.cleanAction = *action
+	m.setView(ViewExecuting)
+	m.message = "Measuring build artifacts..."
+
+	proj, cfg := m.selectedProject, m.config
+	return m, func() tea.Msg {
+		return cleanPreviewMsg{artifacts: actions.NewExecutor(cfg).Artifacts(proj)}
+	}
+}
+
+// showCleanPreview shows the measured artifacts, selecting all but those
+// that need confirming
+func (m Model) showCleanPreview(msg cleanPreviewMsg) (tea.Model, tea.Cmd) {
+	if len(msg.artifacts) == 0 {
+		return m.Update(actionCompleteMsg{
+			success:     true,
+			message:     "No build artifacts found to clean",
+			actionLabel: m.cleanAction.Label,
+		})
+	}
+
+	m.cleanItems = make([]cleanItem, len(msg.artifacts))
+	for i, a := range msg.artifacts {
+		m.cleanItems[i] = cleanItem{Artifact: a, selected: !a.Confirm}
+	}
+	m.cleanCursor = 0
+	m.setView(ViewClean)
+	return m, nil
+}
+
+// handleCleanKey moves through and toggles the artifacts, and removes the
+// selected ones on enter
+func (m Model) handleCleanKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cleanCursor > 0 {
+			m.cleanCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cleanCursor < len(m.cleanItems)-1 {
+			m.cleanCursor++
+		}
+	case msg.String() == " " || msg.String() == "x":
+		m.cleanItems[m.cleanCursor].selected = !m.cleanItems[m.cleanCursor].selected
+	case msg.String() == "a":
+		// Select all, or none if all are selected
+		all := true
+		for _, item := range m.cleanItems {
+			all = all && item.selected
+		}
+		for i := range m.cleanItems {
+			m.cleanItems[i].selected = !all
+		}
+	case key.Matches(msg, m.keys.Enter):
+		var paths []string
+		for _, item := range m.cleanItems {
+			if item.selected {
+				paths = append(paths, item.Path)
+			}
+		}
+		if len(paths) == 0 {
+			return m, nil
+		}
+		action := m.cleanAction
+		m.runningAction = &action
+		m.setView(ViewExecuting)
+		m.message = fmt.Sprintf("Removing %s...", strings.Join(paths, ", "))
+		return m, cleanSelected(action.Label, paths, m.selectedProject, m.config)
+	case key.Matches(msg, m.keys.Back):
+		m.setView(ViewActions)
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// cleanSelected removes the selected artifacts once the preAction hook allows it
+func cleanSelected(actionLabel string, paths []string, proj *project.Project, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		if err := hooks.Run(cfg, hooks.PreAction, proj, "PROJ_ACTION=clean"); err != nil {
+			return actionCompleteMsg{success: false, message: err.Error(), actionLabel: actionLabel}
+		}
+		result := actions.NewExecutor(cfg).Clean(proj, paths)
+		return actionCompleteMsg{success: result.Success, message: result.Message, actionLabel: actionLabel}
+	}
+}
+
+// renderCleanView renders the artifact checklist with sizes
+func (m Model) renderCleanView() string {
+	header := tui.TitleStyle.Render("🗑️  Clean " + m.selectedProject.Name)
+
+	width := 0
+	for _, item := range m.cleanItems {
+		width = max(width, len(item.Path))
+	}
+
+	var lines []string
+	var total int64
+	for i, item := range m.cleanItems {
+		check := "[ ]"
+		if item.selected {
+			check = "[x]"
+			total += item.Size
+		}
+		line := fmt.Sprintf("%s %-*s  %10s", check, width, item.Path, actions.FormatBytes(item.Size))
+		if item.Confirm {
+			line += "  " + tui.HelpStyle.Render("may be needed to build; select to remove")
+		}
+		if i == m.cleanCursor {
+			line = tui.SelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+
+	summary := fmt.Sprintf("Selected: %s", actions.FormatBytes(total))
+	help := tui.HelpStyle.Render("space: toggle  •  a: all/none  •  enter: remove selected  •  esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			summary,
+			"",
+			help,
+		),
+	)
+}
@@ -0,0 +1,242 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/hooks"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// cleanAllDoneMsg reports the projects cleaned from the clean-all view
+type cleanAllDoneMsg struct {
+	cleaned map[int]bool
+	freed   int64
+	failed  []string
+}
+
+// CleanAllModel is a standalone TUI for picking which projects to clean
+// from a report of reclaimable space across all of them
+type CleanAllModel struct {
+	items    []actions.Reclaimable
+	config   *config.Config
+	selected map[int]bool
+	cleaned  map[int]bool
+	cursor   int
+	offset   int
+	running  bool
+	keys     tui.KeyMap
+	width    int
+	height   int
+	message  string     // Progress of the running clean
+	toasts   tui.Toasts // Outcome of the last clean
+}
+
+// NewCleanAllModel creates the interactive clean-all view
+func NewCleanAllModel(items []actions.Reclaimable, cfg *config.Config) CleanAllModel {
+	return CleanAllModel{
+		items:    items,
+		config:   cfg,
+		selected: make(map[int]bool),
+		cleaned:  make(map[int]bool),
+		keys:     tui.DefaultKeyMap(),
+	}
+}
+
+// Init initializes the model
+func (m CleanAllModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m CleanAllModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tui.ToastExpiredMsg:
+		m.toasts.Dismiss(msg)
+		return m, nil
+
+	case cleanAllDoneMsg:
+		m.running = false
+		for i := range msg.cleaned {
+			m.cleaned[i] = true
+		}
+		m.selected = make(map[int]bool)
+		m.message = ""
+		m.toasts.Success(fmt.Sprintf("Cleaned %d project(s), freeing %s", len(msg.cleaned), actions.FormatBytes(msg.freed)))
+		if len(msg.failed) > 0 {
+			m.toasts.Error(fmt.Errorf("failed: %s", strings.Join(msg.failed, "; ")))
+		}
+		return m, m.toasts.Schedule()
+
+	case tea.KeyMsg:
+		if m.running {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Back):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+		case msg.String() == " ":
+			if len(m.items) > 0 && !m.cleaned[m.cursor] {
+				m.selected[m.cursor] = !m.selected[m.cursor]
+			}
+		case msg.String() == "A":
+			// Toggle all that are left to clean
+			all := len(m.selectedIndexes()) < len(m.items)-len(m.cleaned)
+			for i := range m.items {
+				m.selected[i] = all && !m.cleaned[i]
+			}
+		case msg.String() == "c", key.Matches(msg, m.keys.Enter):
+			if indexes := m.selectedIndexes(); len(indexes) > 0 {
+				m.running = true
+				m.message = fmt.Sprintf("Cleaning %d project(s)...", len(indexes))
+				return m, m.clean(indexes)
+			}
+		}
+		m.scrollToCursor()
+	}
+
+	return m, nil
+}
+
+// clean removes the artifacts of the given projects in the background, each
+// once the preAction hook allows it
+func (m CleanAllModel) clean(indexes []int) tea.Cmd {
+	items := m.items
+	cfg := m.config
+	return func() tea.Msg {
+		executor := actions.NewExecutor(cfg)
+		done := cleanAllDoneMsg{cleaned: make(map[int]bool)}
+		for _, i := range indexes {
+			proj := items[i].Project
+			if err := hooks.Run(cfg, hooks.PreAction, proj, "PROJ_ACTION=clean"); err != nil {
+				done.failed = append(done.failed, fmt.Sprintf("%s: %v", proj.Name, err))
+				continue
+			}
+			result := executor.Clean(proj, items[i].Paths())
+			if !result.Success {
+				done.failed = append(done.failed, proj.Name)
+				continue
+			}
+			done.cleaned[i] = true
+			done.freed += items[i].Size
+		}
+		return done
+	}
+}
+
+// selectedIndexes returns the indexes of the selected items in order
+func (m CleanAllModel) selectedIndexes() []int {
+	indexes := make([]int, 0, len(m.selected))
+	for i := range m.items {
+		if m.selected[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// visibleRows returns how many items fit on screen
+func (m CleanAllModel) visibleRows() int {
+	rows := m.height - 10
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// scrollToCursor keeps the cursor inside the visible window
+func (m *CleanAllModel) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// View renders the report
+func (m CleanAllModel) View() string {
+	var total, selected int64
+	for i, item := range m.items {
+		if !m.cleaned[i] {
+			total += item.Size
+		}
+		if m.selected[i] {
+			selected += item.Size
+		}
+	}
+	title := tui.TitleStyle.Render(fmt.Sprintf("🗑️  Reclaimable Space — %s in %d project(s)", actions.FormatBytes(total), len(m.items)-len(m.cleaned)))
+
+	if len(m.items) == 0 {
+		return tui.ContainerStyle.Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			tui.SubtitleStyle.Render("No build artifacts to clean."),
+			tui.HelpStyle.Render("q: quit"),
+		))
+	}
+
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+
+	var rows []string
+	end := m.offset + m.visibleRows()
+	if end > len(m.items) {
+		end = len(m.items)
+	}
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+
+		check := "[ ]"
+		if m.selected[i] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %-30s %10s", check, item.Project.Name, actions.FormatBytes(item.Size))
+		detail := "  " + strings.Join(item.Paths(), ", ")
+		if m.cleaned[i] {
+			line = fmt.Sprintf("✓   %-30s %10s", item.Project.Name, "cleaned")
+		}
+
+		if i == m.cursor {
+			rows = append(rows, tui.SelectedStyle.Render("▸ "+line)+muted.Render(detail))
+		} else {
+			rows = append(rows, tui.NormalStyle.Render(line)+muted.Render(detail))
+		}
+	}
+
+	status := ""
+	if m.message != "" {
+		status = muted.Render(m.message)
+	} else if selected > 0 {
+		status = muted.Render(fmt.Sprintf("%d selected, %s", len(m.selectedIndexes()), actions.FormatBytes(selected)))
+	}
+
+	help := tui.HelpStyle.Render("↑/↓: navigate  •  space: select  •  A: select all  •  enter/c: clean selected  •  q: quit")
+
+	return m.toasts.Overlay(tui.ContainerStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		strings.Join(rows, "\n"),
+		"",
+		status,
+		help,
+	)), m.width)
+}
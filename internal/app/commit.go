@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// maxCoAuthors is how many of a repository's authors are offered as co-authors
+const maxCoAuthors = 8
+
+// promptCommit asks for the message of a commit in the selected project
+func (m Model) promptCommit() (tea.Model, tea.Cmd) {
+	proj := m.selectedProject
+	var trailers []views.Trailer
+	me := git.Identity(proj.Path)
+	if me != "" {
+		trailers = append(trailers, views.Trailer{Key: "Signed-off-by", Value: me, On: m.config.Commit.SignOff})
+	}
+	for _, author := range coAuthors(proj.Path, me, m.config.Commit.CoAuthors) {
+		trailers = append(trailers, views.Trailer{Key: "Co-authored-by", Value: author})
+	}
+
+	m.commit = views.NewCommitModel(m.config.Commit.Types, commitTemplate(proj.Path, m.config), trailers,
+		m.state.InputHistory(inputCommit), !git.HasStaged(proj.Path))
+	m.setView(ViewCommit)
+	return m, m.commit.Init()
+}
+
+// commitTemplate returns the message a project's commits start from: its
+// .gitmessage, git's commit.template, or commit.template in the config
+func commitTemplate(projectPath string, cfg *config.Config) string {
+	if data, err := os.ReadFile(filepath.Join(projectPath, ".gitmessage")); err == nil {
+		return string(data)
+	}
+	if template := git.CommitTemplate(projectPath); template != "" {
+		return template
+	}
+	return cfg.Commit.Template
+}
+
+// coAuthors returns who to offer as co-authors: those in the config, then
+// the repository's most frequent authors, leaving out me
+func coAuthors(projectPath, me string, configured []string) []string {
+	authors, _ := git.Authors(projectPath)
+	if len(authors) > maxCoAuthors {
+		authors = authors[:maxCoAuthors]
+	}
+
+	var offered []string
+	seen := map[string]bool{strings.ToLower(me): true}
+	for _, author := range append(append([]string(nil), configured...), authors...) {
+		if key := strings.ToLower(author); !seen[key] {
+			seen[key] = true
+			offered = append(offered, author)
+		}
+	}
+	return offered
+}
+
+// handleCommitKey commits on enter (ctrl+s while editing the body)
+func (m Model) handleCommitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "esc":
+		m.setView(ViewActions)
+		return m, nil
+	case msg.String() == "ctrl+s", msg.String() == "enter" && !m.commit.EditingBody():
+		message, err := m.commit.Message()
+		if err != nil {
+			m.commit.SetError(err)
+			return m, nil
+		}
+		m.recordInput(inputCommit, m.commit.Summary())
+		m.setView(ViewExecuting)
+		m.message = "Committing..."
+		return m, commitChanges(m.selectedProject.Path, message, m.commit.All())
+	}
+
+	var cmd tea.Cmd
+	m.commit, cmd = m.commit.Update(msg)
+	return m, cmd
+}
+
+// commitChanges commits with message, taking every change to tracked files
+// if all is set
+func commitChanges(projectPath, message string, all bool) tea.Cmd {
+	return func() tea.Msg {
+		out, err := git.Commit(projectPath, message, all)
+		if err != nil {
+			return actionCompleteMsg{
+				success:     false,
+				message:     fmt.Sprintf("git commit failed: %v\n\n%s", err, out),
+				actionLabel: "Commit",
+				gitChanged:  true,
+			}
+		}
+		return actionCompleteMsg{
+			success:     true,
+			message:     out,
+			actionLabel: "Commit",
+			gitChanged:  true,
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// afterCreate adds a project that was just created to the list and does what
+// afterCreate in the config says: open its action menu (with Open in Editor
+// and Change Directory), open it straight away, or show the result. A
+// template's post-init command still shows the result first, where i runs it.
+func (m Model) afterCreate(msg actionCompleteMsg) (tea.Model, tea.Cmd) {
+	path := msg.created
+	msg.created = ""
+	p, err := m.addProject(path)
+	if err != nil {
+		msg.shouldReload = true
+		return m.Update(msg)
+	}
+	if m.config.AfterCreate == config.AfterCreateResult {
+		return m.Update(msg)
+	}
+
+	m.duplicateSource = nil
+	m.selectedProject = p
+	load := m.openActionMenu()
+	if msg.postInit != "" {
+		model, cmd := m.Update(msg)
+		return model, tea.Batch(load, cmd)
+	}
+	if m.config.AfterCreate == config.AfterCreateOpen {
+		if action := views.FindAction(m.actionMenu.Actions(), "open-editor"); action != nil {
+			model, cmd := m.runAction(action)
+			return model, tea.Batch(load, cmd)
+		}
+	}
+	return m, load
+}
+
+// addProject scans a new project and inserts it into the list, under its
+// group if it has one, without rescanning everything
+func (m *Model) addProject(path string) (*project.Project, error) {
+	p, err := project.NewScanner(m.config).ScanProject(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, parent := range m.projects {
+		if parent.Path == filepath.Dir(path) && (parent.Depth == 0 || parent.IsGroup) {
+			p.Depth = parent.Depth + 1
+			p.ParentPath = parent.Path
+			parent.SubProjectCount++
+			break
+		}
+	}
+
+	added := []*project.Project{p}
+	project.ApplyAliases(added, m.config.ProjectAliases)
+	m.state.Annotate(added)
+	m.projects = project.Sort(append(m.projects, p), m.currentSortBy)
+	m.stats = project.ComputeStats(m.projects)
+
+	if len(m.projects) == 1 {
+		m.message = ""
+		m.projectList = m.newProjectList(m.projects)
+		m.updateSizes()
+	} else {
+		m.projectList.SetProjects(m.projects)
+	}
+	if m.selectedGroup != nil && m.selectedGroup.Path == p.ParentPath {
+		m.groupProjects = m.getChildProjects(m.selectedGroup.Path)
+		m.groupList.SetProjects(m.groupProjects)
+		m.groupList.SelectProject(p)
+	} else if p.ParentPath == "" {
+		m.projectList.SelectProject(p)
+	}
+	return p, nil
+}
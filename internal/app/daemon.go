@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// daemonStartTimeout is how long to wait for the daemon after starting it;
+// Docker Desktop and VMs take a while to boot
+const daemonStartTimeout = 90 * time.Second
+
+// daemonCheckedMsg reports whether the container daemon answered before
+// running action
+type daemonCheckedMsg struct {
+	action  views.Action
+	running bool
+	started bool // The check followed starting the daemon
+}
+
+// daemonStartedMsg reports that the command starting the daemon exited
+type daemonStartedMsg struct {
+	action views.Action
+	err    error
+}
+
+// needsDaemon reports whether an action talks to the container daemon
+func needsDaemon(actionID string) bool {
+	switch {
+	case actionID == "docker-lint", strings.HasPrefix(actionID, "db-url-"):
+		return false
+	case strings.HasPrefix(actionID, "docker-"), strings.HasPrefix(actionID, "compose-"), strings.HasPrefix(actionID, "db-"):
+		return true
+	}
+	return false
+}
+
+// checkDaemon checks whether the container daemon answers
+func checkDaemon(action views.Action, runtime string) tea.Cmd {
+	return func() tea.Msg {
+		return daemonCheckedMsg{action: action, running: docker.DaemonRunning(runtime)}
+	}
+}
+
+// waitForDaemon waits for the container daemon to answer after starting it
+func waitForDaemon(action views.Action, runtime string) tea.Cmd {
+	return func() tea.Msg {
+		deadline := time.Now().Add(daemonStartTimeout)
+		for !docker.DaemonRunning(runtime) {
+			if time.Now().After(deadline) {
+				return daemonCheckedMsg{action: action, started: true}
+			}
+			time.Sleep(time.Second)
+		}
+		return daemonCheckedMsg{action: action, running: true, started: true}
+	}
+}
+
+// handleDaemonChecked runs the action once the daemon answers, and
+// otherwise offers to start it
+func (m Model) handleDaemonChecked(msg daemonCheckedMsg) (tea.Model, tea.Cmd) {
+	if msg.running {
+		m.daemonOK = true
+		return m.runAction(&msg.action)
+	}
+
+	runtime := docker.Runtime(m.config.Container.Runtime)
+	start := actions.NewExecutor(m.config).DaemonStartCommand()
+	if msg.started || start == "" {
+		message := fmt.Sprintf("The %s daemon isn't running.", runtime)
+		if msg.started {
+			message = fmt.Sprintf("The %s daemon didn't answer within %s of running:\n  %s", runtime, daemonStartTimeout, start)
+		} else {
+			message += " Start it, or set container.startCommand in the config to the command that does."
+		}
+		return m.Update(actionCompleteMsg{success: false, message: message, actionLabel: msg.action.Label})
+	}
+
+	m.daemonAction = msg.action
+	m.daemonStart = start
+	m.setView(ViewConfirmDaemon)
+	return m, nil
+}
+
+// handleConfirmDaemonKey starts the daemon on y, running the start command
+// attached to the terminal in case it asks for a password
+func (m Model) handleConfirmDaemonKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		args := actions.ParseCommand(m.daemonStart)
+		if len(args) == 0 {
+			return m, nil
+		}
+		action := m.daemonAction
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = m.selectedProject.Path
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return daemonStartedMsg{action: action, err: err}
+		})
+	case "n", "N", "esc", "q":
+		m.setView(ViewActions)
+		return m, nil
+	}
+	return m, nil
+}
+
+// handleDaemonStarted waits for the daemon once its start command exits
+func (m Model) handleDaemonStarted(msg daemonStartedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m.Update(actionCompleteMsg{
+			success:     false,
+			message:     fmt.Sprintf("%s failed: %v", m.daemonStart, msg.err),
+			actionLabel: msg.action.Label,
+		})
+	}
+	runtime := docker.Runtime(m.config.Container.Runtime)
+	m.setView(ViewExecuting)
+	m.message = fmt.Sprintf("Waiting for the %s daemon to start...", runtime)
+	return m, waitForDaemon(msg.action, runtime)
+}
+
+// renderConfirmDaemonView offers to start the container daemon before
+// running an action that needs it
+func (m Model) renderConfirmDaemonView() string {
+	runtime := docker.Runtime(m.config.Container.Runtime)
+	header := tui.TitleStyle.Render("🐳 Container Daemon Not Running")
+
+	message := fmt.Sprintf(
+		"%s needs the %s daemon, which isn't answering.\n\n"+
+			"Start it with:\n"+
+			"  %s\n\n"+
+			"  [Y] Yes, start it and run %s\n"+
+			"  [N] No, cancel",
+		m.daemonAction.Label,
+		runtime,
+		m.daemonStart,
+		m.daemonAction.Label,
+	)
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(1, 2).
+		Render(message)
+
+	help := tui.HelpStyle.Render("y: start the daemon  •  n/esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			help,
+		),
+	)
+}
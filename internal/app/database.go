@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+// openDatabaseShell suspends the interface and opens a database's client in
+// its container, reporting when the client exits
+func openDatabaseShell(actionLabel, service string, proj *project.Project, cfg *config.Config) tea.Cmd {
+	cmd, err := actions.NewExecutor(cfg).DatabaseShell(service, proj)
+	if err != nil {
+		return func() tea.Msg {
+			return actionCompleteMsg{success: false, message: err.Error(), actionLabel: actionLabel}
+		}
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return actionCompleteMsg{
+				success:     false,
+				message:     fmt.Sprintf("%s exited: %v\n\nIs the service running? Start it with Compose Up.", service, err),
+				actionLabel: actionLabel,
+			}
+		}
+		return actionCompleteMsg{success: true, message: "Closed the " + service + " shell", actionLabel: actionLabel}
+	})
+}
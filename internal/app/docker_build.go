@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// promptDockerBuild asks for the build args and target of the project's
+// Dockerfile before building it. It reports false when the Dockerfile has
+// nothing to ask about, so the build runs straight away.
+func (m Model) promptDockerBuild(action *views.Action) (tea.Model, tea.Cmd, bool) {
+	info, err := docker.Detect(m.selectedProject.Path)
+	if err != nil {
+		return m, nil, false
+	}
+	dockerfile := docker.GetPrimaryDockerfile(info.Dockerfiles)
+	df, err := docker.ParseDockerfile(filepath.Join(m.selectedProject.Path, dockerfile))
+	if err != nil || !views.NeedsPrompt(df) {
+		return m, nil, false
+	}
+
+	m.dockerBuild = views.NewDockerBuildModel(dockerfile, df)
+	m.dockerBuildAction = action
+	m.setView(ViewDockerBuild)
+	return m, m.dockerBuild.Init(), true
+}
+
+// handleDockerBuildKey builds the image with the chosen options on enter
+func (m Model) handleDockerBuildKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.setView(ViewActions)
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		running := *m.dockerBuildAction
+		m.runningAction = &running
+		m.setView(ViewExecuting)
+		m.message = fmt.Sprintf("Building %s...", m.selectedProject.Name)
+		return m, buildImage(running.Label, m.selectedProject, m.dockerBuild.Options(), docker.Runtime(m.config.Container.Runtime))
+	}
+
+	var cmd tea.Cmd
+	m.dockerBuild, cmd = m.dockerBuild.Update(msg)
+	return m, cmd
+}
+
+// buildImage builds a project's image with the given target and build args
+func buildImage(actionLabel string, proj *project.Project, opts docker.BuildOptions, runtime string) tea.Cmd {
+	return func() tea.Msg {
+		info, err := docker.Detect(proj.Path)
+		if err != nil {
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("Failed to detect Docker files: %v", err), actionLabel: actionLabel}
+		}
+		result := docker.Build(runtime, proj.Path, proj.Name, info, opts)
+		return actionCompleteMsg{success: result.Success, message: result.Message, actionLabel: actionLabel}
+	}
+}
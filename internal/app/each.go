@@ -0,0 +1,170 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/audit"
+	"github.com/s33g/proj/internal/bulk"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// bulkProgressMsg reports a project starting or finishing in a bulk run
+type bulkProgressMsg struct {
+	started *project.Project // Set when a project starts
+	outcome *bulk.Outcome    // Set when a project finishes
+	updates <-chan tea.Msg
+}
+
+// bulkTickMsg refreshes the elapsed times of a bulk run
+type bulkTickMsg struct{}
+
+// bulkRun tracks a running "each" command for the progress view
+type bulkRun struct {
+	command  string
+	total    int
+	timeout  time.Duration
+	started  time.Time
+	running  []*project.Project
+	startAt  map[*project.Project]time.Time
+	finished []bulk.Outcome
+}
+
+// startEach runs a command in the targeted projects in the background,
+// streaming progress followed by the aggregated result. Projects whose
+// fingerprint matches the one in last are skipped.
+func (m Model) startEach(command []string, last map[string]string) (tea.Model, tea.Cmd) {
+	skipper := bulk.NewSkipper(last)
+	return m.startBulk(strings.Join(command, " "), skipper.Wrap(bulk.Command(command, actions.NewExecutor(m.config).CommandContext)), func(outcomes []bulk.Outcome) actionCompleteMsg {
+		return actionCompleteMsg{
+			actionLabel: fmt.Sprintf("each: %s", strings.Join(command, " ")),
+			bulkCommand: actions.QuoteArgs(command),
+			bulkRuns:    skipper.Succeeded(outcomes),
+		}
+	})
+}
+
+// startBulk runs task in m.eachTargets in the background, streaming progress
+// followed by the aggregated result. description names the task in the
+// progress view; complete returns the result message to fill in.
+func (m Model) startBulk(description string, task bulk.Task, complete func([]bulk.Outcome) actionCompleteMsg) (tea.Model, tea.Cmd) {
+	timeout, err := m.config.Bulk.TimeoutDuration()
+	if err != nil {
+		m.toasts.Error(err)
+		m.setView(ViewProjects)
+		return m, nil
+	}
+
+	targets := m.eachTargets
+	m.bulkRun = &bulkRun{
+		command: description,
+		total:   len(targets),
+		timeout: timeout,
+		started: time.Now(),
+		startAt: make(map[*project.Project]time.Time),
+	}
+	m.setView(ViewExecuting)
+	m.message = m.bulkRun.status(time.Now())
+
+	updates := make(chan tea.Msg, 2*len(targets)+1)
+	opts := bulk.Options{
+		Concurrency: m.config.Bulk.Concurrency,
+		Timeout:     timeout,
+		OnStart: func(p *project.Project) {
+			updates <- bulkProgressMsg{started: p, updates: updates}
+		},
+		OnDone: func(o bulk.Outcome) {
+			// Logging is best effort; a bulk run has no single result to
+			// report a failure in
+			_ = audit.RecordOutcome(m.config, description, o)
+			updates <- bulkProgressMsg{outcome: &o, updates: updates}
+		},
+	}
+
+	go func() {
+		defer close(updates)
+
+		start := time.Now()
+		outcomes := bulk.Schedule(targets, task, opts)
+		ran := len(outcomes) - bulk.Skipped(outcomes)
+
+		_, failed := bulk.Summarize(outcomes)
+		msg := complete(outcomes)
+		msg.success = failed == 0
+		msg.message = bulk.Report(outcomes) + "; " + bulk.Rate(ran, time.Since(start))
+		updates <- msg
+	}()
+
+	return m, tea.Batch(waitForUpdate(updates), m.bulkTick())
+}
+
+// bulkTick schedules the next refresh of the progress view's timers. With
+// reduced motion they don't tick; the view changes as projects start and
+// finish.
+func (m Model) bulkTick() tea.Cmd {
+	if m.config.Display.ReducedMotion {
+		return nil
+	}
+	return tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg { return bulkTickMsg{} })
+}
+
+// update applies a progress message
+func (r *bulkRun) update(msg bulkProgressMsg) {
+	if msg.started != nil {
+		r.running = append(r.running, msg.started)
+		r.startAt[msg.started] = time.Now()
+	}
+	if msg.outcome != nil {
+		for i, p := range r.running {
+			if p == msg.outcome.Project {
+				r.running = append(r.running[:i], r.running[i+1:]...)
+				break
+			}
+		}
+		r.finished = append(r.finished, *msg.outcome)
+	}
+}
+
+// status renders the progress of the run: overall progress and rate, then
+// how long each running project has taken (against the timeout, if any)
+func (r *bulkRun) status(now time.Time) string {
+	done := len(r.finished)
+	elapsed := now.Sub(r.started)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Running '%s' in %d project(s)\n\n", r.command, r.total)
+	fmt.Fprintf(&b, "%s  %d/%d", views.ProgressBar(int64(done), int64(r.total), 30), done, r.total)
+	if done > 0 {
+		fmt.Fprintf(&b, "  •  %s", bulk.Rate(done, elapsed))
+		if remaining := r.total - done; remaining > 0 {
+			eta := time.Duration(float64(elapsed) / float64(done) * float64(remaining))
+			fmt.Fprintf(&b, "  •  ~%s left", eta.Round(time.Second))
+		}
+	}
+	b.WriteString("\n")
+
+	if len(r.running) > 0 {
+		width := 0
+		for _, p := range r.running {
+			width = max(width, len(p.Name))
+		}
+		b.WriteString("\nRunning:\n")
+		for _, p := range r.running {
+			took := now.Sub(r.startAt[p])
+			line := fmt.Sprintf("  ⏳ %-*s  %6s", width, p.Name, took.Round(100*time.Millisecond))
+			if r.timeout > 0 {
+				line += "  " + views.ProgressBar(int64(took), int64(r.timeout), 15)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if done > 0 {
+		fmt.Fprintf(&b, "\nLast finished: %s", bulk.Header(r.finished[done-1]))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
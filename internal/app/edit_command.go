@@ -0,0 +1,79 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// editable reports whether an action runs a command that can be edited
+// before running, such as a package.json script or a custom command
+func editable(action *views.Action) bool {
+	return action != nil && action.Command != "" && !action.IsSubmenu
+}
+
+// promptEditCommand shows the command of a script action in an input to
+// tweak before running it once. The action itself is left as it was.
+func (m Model) promptEditCommand(action *views.Action) (tea.Model, tea.Cmd) {
+	m.editAction = action
+	m.editInput = views.NewInput(nil)
+	m.editInput.SetValue(action.Command)
+	m.editInput.CursorEnd()
+	m.editInput.Width = m.width - 10
+	m.editInput.Focus()
+	m.setView(ViewEditCommand)
+	return m, textinput.Blink
+}
+
+// handleEditCommandKey runs the edited command on enter
+func (m Model) handleEditCommandKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.setView(ViewActions)
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		command := strings.TrimSpace(m.editInput.Value())
+		if command == "" {
+			return m, nil
+		}
+		edited := *m.editAction
+		edited.Command = command
+		return m.runAction(&edited)
+	}
+
+	var cmd tea.Cmd
+	m.editInput, cmd = m.editInput.Update(msg)
+	return m, cmd
+}
+
+// renderEditCommandView renders the prompt for the edited command
+func (m Model) renderEditCommandView() string {
+	header := views.ActionHeader(
+		m.selectedProject.Name,
+		m.selectedProject.Language,
+		m.selectedProject.GitBranch,
+		m.selectedProject.GitDirty,
+	)
+	title := tui.TitleStyle.Render("✎ Edit & Run: " + m.editAction.Label)
+	note := tui.SubtitleStyle.Render("Runs once; the action keeps its command")
+	help := tui.HelpStyle.Render("enter: run  •  esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			title,
+			note,
+			m.editInput.View(),
+			"",
+			help,
+		),
+	)
+}
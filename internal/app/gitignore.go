@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// previewGitignore shows the .gitignore that generating one would write, or
+// the entries it would add to the existing one, before writing anything
+func (m Model) previewGitignore(action *views.Action) (tea.Model, tea.Cmd) {
+	plan, err := actions.GitignorePlan(m.selectedProject)
+	if err != nil {
+		m.toasts.Error(fmt.Errorf("failed to generate .gitignore: %w", err))
+		return m, nil
+	}
+
+	var content string
+	switch {
+	case plan.Exists && len(plan.Added) == 0:
+		content = ".gitignore already has every suggested entry."
+	case plan.Exists:
+		content = fmt.Sprintf("Would add %d entries to the existing .gitignore:\n\n%s",
+			len(plan.Added), strings.Join(plan.Added, "\n"))
+	default:
+		content = string(plan.Content)
+	}
+
+	m.gitignoreAction = *action
+	m.gitignoreTitle = "Generate .gitignore"
+	if len(plan.Sections) > 0 {
+		m.gitignoreTitle += " for " + strings.Join(plan.Sections, ", ")
+	}
+	m.gitignoreViewport = viewport.New(m.width-4, m.height-10)
+	m.gitignoreViewport.SetContent(content)
+	m.setView(ViewGitignore)
+	return m, nil
+}
+
+// handleGitignoreKey writes the previewed .gitignore on y/enter
+func (m Model) handleGitignoreKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "y" || msg.String() == "Y" || key.Matches(msg, m.keys.Enter):
+		action := m.gitignoreAction
+		return m.runAction(&action)
+	case msg.String() == "n" || key.Matches(msg, m.keys.Back):
+		m.setView(ViewActions)
+		return m, nil
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.gitignoreViewport, cmd = m.gitignoreViewport.Update(msg)
+		return m, cmd
+	}
+}
+
+// renderGitignoreView renders the .gitignore preview
+func (m Model) renderGitignoreView() string {
+	header := tui.TitleStyle.Render("🙈 " + m.gitignoreTitle)
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Width(m.width - 6).
+		Render(m.gitignoreViewport.View())
+
+	help := tui.HelpStyle.Render("↑/↓: scroll  •  y/enter: write  •  n/esc: cancel")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			help,
+		),
+	)
+}
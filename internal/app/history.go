@@ -0,0 +1,177 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// unrecordedActions are not worth re-running from the history: they leave proj
+var unrecordedActions = map[string]bool{
+	"cd":          true,
+	"open-editor": true,
+	"reopen":      true,
+}
+
+// recordRun adds a finished action to the project's history
+func (m *Model) recordRun(p *project.Project, action views.Action, msg actionCompleteMsg) {
+	if unrecordedActions[action.ID] {
+		return
+	}
+	exitCode := msg.exitCode
+	if !msg.success && exitCode == 0 {
+		// Built-in actions can fail without running anything
+		exitCode = 1
+	}
+	m.state.RecordRun(p.Path, state.Run{
+		ActionID: action.ID,
+		Label:    action.Label,
+		Command:  action.Command,
+		At:       time.Now(),
+		ExitCode: exitCode,
+	})
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+	}
+}
+
+// rerun runs a past action again in the selected project. The action is
+// looked up again so config changes since the run apply.
+func (m Model) rerun(run state.Run) (tea.Model, tea.Cmd) {
+	if action := views.FindAction(m.projectActions(), run.ActionID); action != nil && !action.IsSubmenu {
+		return m.runAction(action)
+	}
+	if run.Command != "" {
+		return m.runAction(&views.Action{ID: run.ActionID, Label: run.Label, Command: run.Command})
+	}
+	m.toasts.Error(fmt.Errorf("action no longer available for %s: %s", m.selectedProject.Name, run.Label))
+	return m, nil
+}
+
+// rerunLast runs the selected project's most recent action again
+func (m Model) rerunLast() (tea.Model, tea.Cmd) {
+	history := m.state.History(m.selectedProject.Path)
+	if len(history) == 0 {
+		return m, nil
+	}
+	return m.rerun(history[0])
+}
+
+// openHistory shows the selected project's history
+func (m *Model) openHistory() {
+	history := m.state.History(m.selectedProject.Path)
+	items := make([]list.Item, len(history))
+	for i, run := range history {
+		items[i] = historyItem(run)
+	}
+
+	m.historyList = list.New(items, historyDelegate{now: time.Now()}, m.width-4, m.height-10)
+	m.historyList.Title = "History"
+	m.historyList.SetShowStatusBar(false)
+	m.historyList.SetFilteringEnabled(true)
+	m.historyList.Styles.Title = tui.TitleStyle
+	m.setView(ViewHistory)
+}
+
+// handleHistoryKey handles keys in the history view
+func (m Model) handleHistoryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.historyList.SettingFilter():
+		var cmd tea.Cmd
+		m.historyList, cmd = m.historyList.Update(msg)
+		return m, cmd
+	case key.Matches(msg, m.keys.Back):
+		m.back(ViewProjects)
+		if m.view != ViewActions {
+			m.selectedProject = nil
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Enter):
+		if item, ok := m.historyList.SelectedItem().(historyItem); ok {
+			return m.rerun(state.Run(item))
+		}
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.historyList, cmd = m.historyList.Update(msg)
+		return m, cmd
+	}
+}
+
+// renderHistoryView renders the history of the selected project
+func (m Model) renderHistoryView() string {
+	header := views.ActionHeader(
+		m.selectedProject.Name,
+		m.selectedProject.Language,
+		m.selectedProject.GitBranch,
+		m.selectedProject.GitDirty,
+	)
+
+	content := m.historyList.View()
+	if len(m.historyList.Items()) == 0 {
+		content = tui.SubtitleStyle.Render("Nothing has been run in this project yet.")
+	}
+	help := tui.HelpStyle.Render("↑/↓: navigate  •  /: filter  •  enter: run again  •  esc: back")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			help,
+		),
+	)
+}
+
+// historyItem is a list item for the history view
+type historyItem state.Run
+
+func (h historyItem) FilterValue() string { return h.Label + " " + h.Command }
+func (h historyItem) Title() string       { return h.Label }
+func (h historyItem) Description() string { return h.Command }
+
+// historyDelegate renders a run with its exit status, time and command
+type historyDelegate struct {
+	now time.Time
+}
+
+func (d historyDelegate) Height() int                             { return 1 }
+func (d historyDelegate) Spacing() int                            { return 0 }
+func (d historyDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d historyDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	run, ok := item.(historyItem)
+	if !ok {
+		return
+	}
+
+	status := tui.SuccessStyle.Render("✓    ")
+	if run.ExitCode != 0 {
+		status = tui.ErrorStyle.Render(fmt.Sprintf("✗ %-3d", run.ExitCode))
+	}
+	when := fmt.Sprintf("%s (%s)", run.At.Format("Jan _2 15:04"), views.RelativeTime(run.At, d.now))
+	str := fmt.Sprintf("%s  %-24s %s", status, when, run.Label)
+	if run.Command != "" {
+		str += "  " + tui.SubtitleStyle.Render(run.Command)
+	}
+
+	if index == m.Index() {
+		str = tui.SelectedStyle.Render("> " + str)
+	} else {
+		str = "  " + str
+	}
+
+	_, _ = fmt.Fprint(w, str)
+}
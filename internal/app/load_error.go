@@ -0,0 +1,145 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// What failed to load
+const (
+	loadingProjects = "Projects"
+	loadingBranches = "Branches"
+)
+
+// loadFailedMsg reports that the projects or a project's branches couldn't
+// be loaded
+type loadFailedMsg struct {
+	what string // loadingProjects or loadingBranches
+	path string // Directory that was read
+	err  error
+}
+
+// loadingViews are the views shown while each load runs. A load that fails
+// after its view was left, such as a rescan in the background, is reported
+// in a toast instead of the error view.
+var loadingViews = map[string]View{
+	loadingProjects: ViewLoading,
+	loadingBranches: ViewExecuting,
+}
+
+// handleLoadFailed shows the error view for a failed load
+func (m Model) handleLoadFailed(msg loadFailedMsg) (tea.Model, tea.Cmd) {
+	if m.view != loadingViews[msg.what] {
+		m.toasts.Error(fmt.Errorf("failed to load %s: %w", strings.ToLower(msg.what), msg.err))
+		return m, nil
+	}
+	m.loadError = msg
+	m.setView(ViewError)
+	return m, nil
+}
+
+// retryLoad runs the failed load again
+func (m Model) retryLoad() (tea.Model, tea.Cmd) {
+	m.back(ViewProjects)
+	if m.loadError.what == loadingBranches {
+		m.setView(ViewExecuting)
+		m.message = "Loading branches..."
+		return m, loadBranches(m.loadError.path)
+	}
+	m.setView(ViewLoading)
+	m.message = "Rescanning projects..."
+	return m, m.loadProjects()
+}
+
+// handleLoadErrorKey retries the load with r, opens the config after a
+// failed scan with e, and goes back with esc
+func (m Model) handleLoadErrorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "r":
+		return m.retryLoad()
+	case msg.String() == "e" && m.loadError.what == loadingProjects:
+		return m.runWorkspaceAction("workspace-open-config")
+	case key.Matches(msg, m.keys.Back):
+		m.back(ViewProjects)
+		return m, nil
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// loadErrorCause guesses why reading path failed, or returns ""
+func loadErrorCause(what, path string, err error) string {
+	info, statErr := os.Stat(path)
+	switch {
+	case errors.Is(statErr, fs.ErrNotExist):
+		if what == loadingProjects {
+			return fmt.Sprintf("%s doesn't exist. Create it, or point reposPath at the folder holding your projects (proj --set-path ~/code).", path)
+		}
+		return fmt.Sprintf("%s no longer exists; it may have been moved or deleted.", path)
+	case errors.Is(statErr, fs.ErrPermission), errors.Is(err, fs.ErrPermission):
+		return fmt.Sprintf("You don't have permission to read %s.", path)
+	case statErr == nil && !info.IsDir():
+		return fmt.Sprintf("%s is a file, not a directory.", path)
+	case errors.Is(err, exec.ErrNotFound):
+		return "git isn't installed, or isn't on your PATH."
+	}
+	if what == loadingBranches {
+		if _, err := os.Stat(filepath.Join(path, ".git")); errors.Is(err, fs.ErrNotExist) {
+			return fmt.Sprintf("%s isn't a git repository.", path)
+		}
+	}
+	return ""
+}
+
+// renderLoadErrorView shows why the projects or branches couldn't be loaded
+func (m Model) renderLoadErrorView() string {
+	header := tui.TitleStyle.Render("⚠️  Couldn't Load " + m.loadError.what)
+
+	message := tui.ErrorStyle.Render(m.loadError.err.Error())
+	if cause := loadErrorCause(m.loadError.what, m.loadError.path, m.loadError.err); cause != "" {
+		message += "\n\n" + lipgloss.NewStyle().Foreground(tui.Muted).Render("Probable cause: ") + cause
+	}
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(tui.Error).
+		Padding(1, 2).
+		Width(max(min(m.width-6, 80), 20)).
+		Render(message)
+
+	help := "r: retry  •  esc: back  •  q: quit"
+	if m.loadError.what == loadingProjects {
+		help = "r: retry  •  e: edit config  •  esc: back  •  q: quit"
+	}
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			content,
+			"",
+			tui.HelpStyle.Render(help),
+		),
+	)
+}
+
+// loadFailed returns the message reporting a failed load of path
+func loadFailed(what, path string, err error) tea.Msg {
+	if what == loadingProjects {
+		path = config.ExpandPath(path)
+	}
+	return loadFailedMsg{what: what, path: path, err: err}
+}
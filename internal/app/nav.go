@@ -0,0 +1,63 @@
+package app
+
+// transientViews are passed through on the way somewhere: progress, results,
+// prompts and confirmations. Back never returns to them.
+var transientViews = map[View]bool{
+	ViewLoading:          true,
+	ViewExecuting:        true,
+	ViewResult:           true,
+	ViewNewProject:       true,
+	ViewNewBranch:        true,
+	ViewCommit:           true,
+	ViewDockerBuild:      true,
+	ViewEditCommand:      true,
+	ViewEachPrompt:       true,
+	ViewGitignore:        true,
+	ViewClean:            true,
+	ViewPullStrategy:     true,
+	ViewPluginSettings:   true,
+	ViewConfirmStash:     true,
+	ViewConfirmProtected: true,
+	ViewConfirmURL:       true,
+	ViewConfirmDaemon:    true,
+	ViewError:            true,
+}
+
+// setView shows v, remembering the current view so back can return to it.
+// Showing a view that is already on the stack returns to it, dropping the
+// views opened since.
+func (m *Model) setView(v View) {
+	if m.view == v {
+		return
+	}
+	for i := len(m.navStack) - 1; i >= 0; i-- {
+		if m.navStack[i] == v {
+			m.navStack = m.navStack[:i]
+			m.view = v
+			return
+		}
+	}
+	m.pushView(v)
+}
+
+// pushView shows v over the current view without returning to an earlier
+// copy of it, for views that open a level deeper, such as the action menu
+// of a group's child opened from the group's own menu
+func (m *Model) pushView(v View) {
+	if m.view != v && !transientViews[m.view] {
+		m.navStack = append(m.navStack, m.view)
+	}
+	m.view = v
+}
+
+// back returns to the view shown before the current one, or to fallback
+// when there is none. Views keep their state while covered, so the list
+// position, filter and submenu are as they were left.
+func (m *Model) back(fallback View) {
+	if len(m.navStack) == 0 {
+		m.view = fallback
+		return
+	}
+	m.view = m.navStack[len(m.navStack)-1]
+	m.navStack = m.navStack[:len(m.navStack)-1]
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// PickerModel is a minimal inline picker for choosing between the projects
+// an ambiguous name matched
+type PickerModel struct {
+	query      string
+	candidates []*project.Project
+	cursor     int
+	chosen     *project.Project
+	keys       tui.KeyMap
+	done       bool
+}
+
+// NewPickerModel creates a picker over the projects query matched
+func NewPickerModel(query string, candidates []*project.Project) PickerModel {
+	return PickerModel{
+		query:      query,
+		candidates: candidates,
+		keys:       tui.DefaultKeyMap(),
+	}
+}
+
+// Init initializes the model
+func (m PickerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, m.keys.Down):
+		if m.cursor < len(m.candidates)-1 {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, m.keys.Enter):
+		m.chosen = m.candidates[m.cursor]
+		m.done = true
+		return m, tea.Quit
+	case key.Matches(keyMsg, m.keys.Back), key.Matches(keyMsg, m.keys.Quit):
+		m.done = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View renders the candidates
+func (m PickerModel) View() string {
+	// Leave nothing behind once a choice is made
+	if m.done {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(tui.SubtitleStyle.Render(fmt.Sprintf("%d projects match %q:", len(m.candidates), m.query)))
+	b.WriteString("\n")
+	for i, p := range m.candidates {
+		line := fmt.Sprintf("%s  %s", p.Name, tui.HelpStyle.Render(p.Path))
+		if i == m.cursor {
+			b.WriteString(tui.SelectedStyle.Render("> ") + line)
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(tui.HelpStyle.Render("↑/↓: select  •  enter: open  •  esc: cancel"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Chosen returns the picked project, or nil if the picker was cancelled
+func (m PickerModel) Chosen() *project.Project {
+	return m.chosen
+}
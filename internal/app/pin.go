@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/project"
+)
+
+// togglePin pins p to the top of the list, or unpins it, and re-sorts the
+// lists so it moves there
+func (m *Model) togglePin(p *project.Project) tea.Cmd {
+	m.state.SetPinned(p.Path, !p.Pinned)
+	m.state.Annotate([]*project.Project{p})
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+	}
+
+	m.projects = project.Sort(m.projects, m.currentSortBy)
+	cmd := m.projectList.SetProjects(m.projects)
+	if m.selectedGroup != nil {
+		m.groupProjects = m.getChildProjects(m.selectedGroup.Path)
+		cmd = tea.Batch(cmd, m.groupList.SetProjects(m.groupProjects))
+	}
+
+	if p.Pinned {
+		m.toasts.Success("Pinned " + p.Name)
+	} else {
+		m.toasts.Info("Unpinned " + p.Name)
+	}
+	return cmd
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+	"github.com/s33g/proj/pkg/plugin"
+)
+
+// openPluginSettings shows the settings form of the selected plugin, if its
+// manifest declares a schema
+func (m Model) openPluginSettings(s plugin.Status) (tea.Model, tea.Cmd) {
+	schema, settings, err := m.pluginRegistry.Settings(s.ID)
+	if err != nil {
+		m.toasts.Error(err)
+		return m, nil
+	}
+	if len(schema) == 0 {
+		m.toasts.Info(s.Name + " has no settings")
+		return m, nil
+	}
+
+	m.settingsPlugin = s
+	m.settingsFields = schema
+	m.settingsInputs = make([]views.Input, len(schema))
+	for i, field := range schema {
+		input := views.NewInput(nil)
+		input.Prompt = ""
+		input.Width = max(m.width-20, 20)
+		input.SetValue(field.Format(settings[field.Key]))
+		m.settingsInputs[i] = input
+	}
+	m.settingsFocus = 0
+	m.settingsError = ""
+	m.settingsInputs[0].Focus()
+	m.setView(ViewPluginSettings)
+	return m, textinput.Blink
+}
+
+// focusSetting moves the settings form's focus by delta, wrapping around
+func (m *Model) focusSetting(delta int) {
+	m.settingsInputs[m.settingsFocus].Blur()
+	m.settingsFocus = (m.settingsFocus + delta + len(m.settingsInputs)) % len(m.settingsInputs)
+	m.settingsInputs[m.settingsFocus].Focus()
+}
+
+// handlePluginSettingsKey edits the settings form, saving it with enter
+func (m Model) handlePluginSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.setView(ViewPlugins)
+		return m, pluginTick()
+	case "ctrl+c":
+		return m, tea.Quit
+	case "tab", "down":
+		m.focusSetting(1)
+		return m, nil
+	case "shift+tab", "up":
+		m.focusSetting(-1)
+		return m, nil
+	case "enter":
+		return m.savePluginSettings()
+	}
+
+	var cmd tea.Cmd
+	m.settingsInputs[m.settingsFocus], cmd = m.settingsInputs[m.settingsFocus].Update(msg)
+	return m, cmd
+}
+
+// savePluginSettings writes the form to plugins.config in config.json and
+// restarts the plugin with it
+func (m Model) savePluginSettings() (tea.Model, tea.Cmd) {
+	id := m.settingsPlugin.ID
+	user, _ := m.config.Plugins.Config[id].(map[string]interface{})
+	updated := make(map[string]interface{}, len(user))
+	for k, v := range user {
+		updated[k] = v
+	}
+
+	for i, field := range m.settingsFields {
+		value, err := field.Parse(m.settingsInputs[i].Value())
+		if err != nil {
+			m.settingsError = err.Error()
+			m.settingsInputs[m.settingsFocus].Blur()
+			m.settingsFocus = i
+			m.settingsInputs[i].Focus()
+			return m, nil
+		}
+		// The config loader lowercases keys; replace them rather than
+		// keeping both spellings
+		for k := range updated {
+			if strings.EqualFold(k, field.Key) {
+				delete(updated, k)
+			}
+		}
+		updated[field.Key] = value
+	}
+
+	if m.config.Plugins.Config == nil {
+		m.config.Plugins.Config = make(map[string]interface{})
+	}
+	m.config.Plugins.Config[id] = updated
+	if err := config.Save(m.config); err != nil {
+		m.settingsError = "Failed to save config: " + err.Error()
+		return m, nil
+	}
+
+	m.setView(ViewPlugins)
+	problems, err := m.pluginRegistry.Reconfigure(id, updated)
+	switch {
+	case err != nil:
+		m.toasts.Error(fmt.Errorf("saved, but %s failed to restart: %w", m.settingsPlugin.Name, err))
+	case len(problems) > 0:
+		m.toasts.Warn("Saved; " + strings.Join(problems, "; "))
+	default:
+		m.toasts.Success("Saved settings and restarted " + m.settingsPlugin.Name)
+	}
+	return m, pluginTick()
+}
+
+// renderPluginSettingsView renders the settings form of a plugin
+func (m Model) renderPluginSettingsView() string {
+	header := tui.TitleStyle.Render("⚙ " + m.settingsPlugin.Name + " settings")
+
+	width := 0
+	for _, field := range m.settingsFields {
+		width = max(width, len(field.Key))
+	}
+
+	var lines []string
+	for i, field := range m.settingsFields {
+		label := fmt.Sprintf("%-*s", width, field.Key)
+		cursor := "  "
+		if i == m.settingsFocus {
+			label = tui.SelectedStyle.Render(label)
+			cursor = tui.SelectedStyle.Render("> ")
+		}
+		lines = append(lines, cursor+label+"  "+m.settingsInputs[i].View())
+
+		description := field.Type
+		if field.Description != "" {
+			description += " • " + field.Description
+		}
+		lines = append(lines, strings.Repeat(" ", width+4)+tui.HelpStyle.Render(description))
+	}
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+
+	sections := []string{header, "", content}
+	if m.settingsError != "" {
+		sections = append(sections, "", tui.ErrorStyle.Render(m.settingsError))
+	}
+	help := tui.HelpStyle.Render("tab/↑/↓: next field  •  enter: save and restart  •  esc: cancel")
+	sections = append(sections, "", help)
+
+	return tui.ContainerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
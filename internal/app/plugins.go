@@ -0,0 +1,147 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/pkg/plugin"
+)
+
+// pluginTickMsg refreshes the plugin manager while it's open
+type pluginTickMsg struct{}
+
+// pluginTick schedules the next refresh of the plugin manager
+func pluginTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return pluginTickMsg{} })
+}
+
+// Shutdown stops the plugin processes; call it once the program has exited
+func (m Model) Shutdown() {
+	if m.pluginRegistry != nil {
+		m.pluginRegistry.Shutdown()
+	}
+}
+
+// pluginStatuses returns the state of the loaded plugins
+func (m Model) pluginStatuses() []plugin.Status {
+	if m.pluginRegistry == nil {
+		return nil
+	}
+	return m.pluginRegistry.Statuses()
+}
+
+// openPlugins shows the plugin manager
+func (m Model) openPlugins() (tea.Model, tea.Cmd) {
+	m.pluginCursor = 0
+	m.setView(ViewPlugins)
+	return m, pluginTick()
+}
+
+// handlePluginsKey moves through the plugins, restarts a failed one with r
+// and opens a plugin's settings with enter
+func (m Model) handlePluginsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	statuses := m.pluginStatuses()
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.pluginCursor > 0 {
+			m.pluginCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.pluginCursor < len(statuses)-1 {
+			m.pluginCursor++
+		}
+	case msg.String() == "r" && m.pluginCursor < len(statuses):
+		s := statuses[m.pluginCursor]
+		if err := m.pluginRegistry.Restart(s.ID); err != nil {
+			m.toasts.Error(err)
+		} else {
+			m.toasts.Success("Restarted " + s.Name)
+		}
+	case key.Matches(msg, m.keys.Enter) && m.pluginCursor < len(statuses):
+		return m.openPluginSettings(statuses[m.pluginCursor])
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+		m.setView(ViewProjects)
+	}
+	return m, nil
+}
+
+// pluginStateStyles color each process state
+var pluginStateStyles = map[string]lipgloss.Style{
+	plugin.StateRunning:    tui.SuccessStyle,
+	plugin.StateRestarting: lipgloss.NewStyle().Foreground(tui.Warning),
+	plugin.StateFailed:     tui.ErrorStyle,
+	plugin.StateStopped:    tui.HelpStyle,
+}
+
+// pluginDetail describes a plugin's process beyond its state
+func pluginDetail(s plugin.Status, now time.Time) string {
+	var parts []string
+	switch s.State {
+	case plugin.StateRunning:
+		parts = append(parts, fmt.Sprintf("pid %d, up %s", s.PID, now.Sub(s.StartedAt).Round(time.Second)))
+	case plugin.StateRestarting:
+		parts = append(parts, fmt.Sprintf("retrying in %s", max(s.NextRestart.Sub(now), 0).Round(time.Second)))
+	}
+	if s.Restarts > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d restarts", s.Restarts, plugin.MaxRestarts))
+	}
+	if s.LastError != "" && s.State != plugin.StateRunning {
+		parts = append(parts, s.LastError)
+	}
+	if n := len(s.ConfigProblems); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d ignored setting(s)", n))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// renderPluginsView renders the loaded plugins and their process state
+func (m Model) renderPluginsView() string {
+	header := tui.TitleStyle.Render("🧩 Plugins")
+
+	statuses := m.pluginStatuses()
+	var body string
+	if len(statuses) == 0 {
+		body = tui.SubtitleStyle.Render("No plugins enabled. Add them to plugins.enabled in the config.")
+	} else {
+		width := 0
+		for _, s := range statuses {
+			width = max(width, len(s.Name+s.Version)+1)
+		}
+
+		now := time.Now()
+		var lines []string
+		for i, s := range statuses {
+			state := pluginStateStyles[s.State].Render(fmt.Sprintf("%-10s", s.State))
+			line := fmt.Sprintf("%-*s  %s  %s", width, s.Name+" "+s.Version, state, tui.HelpStyle.Render(pluginDetail(s, now)))
+			if i == m.pluginCursor {
+				line = tui.SelectedStyle.Render("> ") + line
+			} else {
+				line = "  " + line
+			}
+			lines = append(lines, line)
+			if i == m.pluginCursor {
+				for _, problem := range s.ConfigProblems {
+					lines = append(lines, "    "+lipgloss.NewStyle().Foreground(tui.Warning).Render("⚠ "+problem))
+				}
+			}
+		}
+		body = strings.Join(lines, "\n")
+	}
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Render(body)
+
+	sections := []string{header, "", content}
+	help := tui.HelpStyle.Render("↑/↓: select  •  enter: settings  •  r: restart a failed plugin  •  esc/q: close")
+	sections = append(sections, "", help)
+
+	return tui.ContainerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/hooks"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// pullStrategy is a way of finishing a pull whose branches diverged
+type pullStrategy struct {
+	strategy    string // git.Pull* constant, "" to abort
+	label       string
+	description string
+}
+
+// pullStrategies are offered when a pull stops because the branches diverged
+var pullStrategies = []pullStrategy{
+	{git.PullRebase, "Rebase (--rebase)", "replay your local commits on top of the remote ones"},
+	{git.PullFastForward, "Fast-forward only (--ff-only)", "only update if there are no local commits"},
+	{git.PullMerge, "Merge (--no-rebase)", "create a merge commit joining both histories"},
+	{"", "Abort", "leave the branch as it is"},
+}
+
+// showPullStrategies explains why the pull stopped and offers ways to finish it
+func (m Model) showPullStrategies(explanation string) (tea.Model, tea.Cmd) {
+	// The dialog lists the strategies itself, so drop the advice after the summary
+	m.pullExplanation, _, _ = strings.Cut(explanation, "\n\n")
+	m.pullCursor = 0
+	m.setView(ViewPullStrategy)
+	return m, nil
+}
+
+// handlePullStrategyKey moves through the strategies and pulls with the
+// chosen one on enter
+func (m Model) handlePullStrategyKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.pullCursor > 0 {
+			m.pullCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.pullCursor < len(pullStrategies)-1 {
+			m.pullCursor++
+		}
+	case key.Matches(msg, m.keys.Enter):
+		choice := pullStrategies[m.pullCursor]
+		if choice.strategy == "" {
+			m.setView(ViewActions)
+			return m, nil
+		}
+		m.setView(ViewExecuting)
+		m.message = fmt.Sprintf("Pulling %s with %s...", m.selectedProject.Name, choice.label)
+		return m, pullWith(choice, m.selectedProject, m.config)
+	case key.Matches(msg, m.keys.Back):
+		m.setView(ViewActions)
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// pullWith pulls using the chosen strategy once the preAction hook allows it
+func pullWith(choice pullStrategy, proj *project.Project, cfg *config.Config) tea.Cmd {
+	actionLabel := "Git Pull (" + choice.label + ")"
+	return func() tea.Msg {
+		if err := hooks.Run(cfg, hooks.PreAction, proj, "PROJ_ACTION=git-pull"); err != nil {
+			return actionCompleteMsg{success: false, message: err.Error(), actionLabel: actionLabel}
+		}
+		output, err := git.PullWith(proj.Path, choice.strategy)
+		if err != nil {
+			result := actions.GitFailure("Pull failed", err, output, "pull", git.PullFlag(choice.strategy))
+			return actionCompleteMsg{
+				success:     false,
+				message:     result.Message,
+				actionLabel: actionLabel,
+				retryArgs:   result.RetryArgs,
+			}
+		}
+		return actionCompleteMsg{success: true, message: output, actionLabel: actionLabel}
+	}
+}
+
+// renderPullStrategyView renders the divergence explanation and strategies
+func (m Model) renderPullStrategyView() string {
+	header := tui.TitleStyle.Render("⑂ " + m.selectedProject.Name + " has diverged")
+
+	var lines []string
+	for i, s := range pullStrategies {
+		line := fmt.Sprintf("%-30s %s", s.label, tui.HelpStyle.Render(s.description))
+		if i == m.pullCursor {
+			line = tui.SelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+
+	help := tui.HelpStyle.Render("↑/↓: choose  •  enter: pull  •  esc: abort")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			"",
+			m.pullExplanation,
+			"",
+			content,
+			"",
+			help,
+		),
+	)
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/procs"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/scripts"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// serverLogLines is how much of a dev server's log View Logs shows
+const serverLogLines = 200
+
+// serverStartWait is how long a dev server that was just started is watched
+// for exiting straight away, e.g. because its port is taken. A variable so
+// tests can shorten it.
+var serverStartWait = 500 * time.Millisecond
+
+// serverActions returns the actions for the selected project's dev server:
+// Start while it isn't running, Stop, Restart and View Logs while it is, and
+// View Logs of the last run after it has exited
+func (m Model) serverActions() []views.Action {
+	p := m.selectedProject
+	server := m.state.Server(p.Path)
+	if p.ServerPID > 0 && server != nil {
+		where := fmt.Sprintf("pid %d", p.ServerPID)
+		if p.ServerPort > 0 {
+			where = fmt.Sprintf(":%d, %s", p.ServerPort, where)
+		}
+		var serverActions []views.Action
+		if p.ServerPort > 0 {
+			serverActions = append(serverActions, views.OpenPortAction(p.ServerPort, "Open the dev server in the browser"))
+		}
+		return append(serverActions, []views.Action{
+			{ID: "server-stop", Label: "Stop Dev Server", Desc: fmt.Sprintf("Stop %s (%s)", server.Command, where), Icon: "⏹"},
+			{ID: "server-restart", Label: "Restart Dev Server", Desc: "Stop it and start " + actions.NewExecutor(m.config).DevServerCommand(p), Icon: "🔁"},
+			{ID: "server-logs", Label: "View Dev Server Logs", Desc: "Output of " + server.Command, Icon: "📜"},
+		}...)
+	}
+
+	var serverActions []views.Action
+	if command := actions.NewExecutor(m.config).DevServerCommand(p); command != "" {
+		serverActions = append(serverActions, views.Action{
+			ID:    "server-start",
+			Label: "Start Dev Server",
+			Desc:  fmt.Sprintf("Run %s in the background", command),
+			Icon:  "▶",
+		})
+	}
+	if server != nil && serverLogExists(server) {
+		serverActions = append(serverActions, views.Action{
+			ID:    "server-logs",
+			Label: "View Dev Server Logs",
+			Desc:  fmt.Sprintf("Output of %s, which has exited", server.Command),
+			Icon:  "📜",
+		})
+	}
+	return serverActions
+}
+
+// runServerAction starts, stops or restarts the selected project's dev
+// server, or shows its log
+func (m Model) runServerAction(action *views.Action) (tea.Model, tea.Cmd) {
+	p := m.selectedProject
+	server := m.state.Server(p.Path)
+	m.setView(ViewExecuting)
+
+	switch action.ID {
+	case "server-start", "server-restart":
+		command := actions.NewExecutor(m.config).DevServerCommand(p)
+		if command == "" {
+			return m.Update(actionCompleteMsg{
+				success:     false,
+				message:     "No dev server script found; set a command in devServer.projects in the config",
+				actionLabel: action.Label,
+			})
+		}
+		stop := 0
+		if action.ID == "server-restart" && server != nil {
+			stop = server.PID
+		}
+		m.message = fmt.Sprintf("Starting %s...", command)
+		return m, startServer(action.Label, command, stop, p, m.config)
+	case "server-stop":
+		if server == nil {
+			return m.Update(actionCompleteMsg{success: false, message: "No dev server is running", actionLabel: action.Label})
+		}
+		m.message = fmt.Sprintf("Stopping %s...", server.Command)
+		return m, stopServer(action.Label, *server)
+	}
+
+	if server == nil {
+		return m.Update(actionCompleteMsg{success: false, message: "The dev server hasn't been started", actionLabel: action.Label})
+	}
+	return m, serverLogs(action.Label, *server)
+}
+
+// startServer starts a dev server, after stopping the one running as pid
+// stop if there is one, and reports it straight away if it exits
+func startServer(label, command string, stop int, p *project.Project, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		if err := procs.Stop(stop); err != nil {
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("Failed to stop the dev server: %v", err), actionLabel: label, serverChanged: true}
+		}
+		log, err := procs.LogPath(p.Path)
+		if err != nil {
+			return actionCompleteMsg{success: false, message: err.Error(), actionLabel: label, serverChanged: true}
+		}
+		pid, err := actions.NewExecutor(cfg).StartServer(command, p, log)
+		if err != nil {
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("Failed to start %s: %v", command, err), actionLabel: label, serverChanged: true}
+		}
+
+		server := &state.Server{Command: command, PID: pid, Log: log, StartedAt: time.Now()}
+		time.Sleep(serverStartWait)
+		if !procs.Running(pid) {
+			output, _ := procs.Tail(log, serverLogLines)
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("%s exited straight away:\n\n%s", command, output), actionLabel: label, server: server}
+		}
+		return actionCompleteMsg{
+			success: true,
+			message: fmt.Sprintf("Started %s in the background (pid %d).\n\nIt keeps running after proj exits, until it's stopped from the menu. Its output is written to %s.",
+				command, pid, log),
+			actionLabel: label,
+			server:      server,
+		}
+	}
+}
+
+// stopServer stops a dev server and the processes it started
+func stopServer(label string, server state.Server) tea.Cmd {
+	return func() tea.Msg {
+		if err := procs.Stop(server.PID); err != nil {
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("Failed to stop %s (pid %d): %v", server.Command, server.PID, err), actionLabel: label, serverChanged: true}
+		}
+		return actionCompleteMsg{success: true, message: fmt.Sprintf("Stopped %s", server.Command), actionLabel: label, serverChanged: true}
+	}
+}
+
+// serverLogs shows the end of a dev server's log
+func serverLogs(label string, server state.Server) tea.Cmd {
+	return func() tea.Msg {
+		output, err := procs.Tail(server.Log, serverLogLines)
+		if err != nil {
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("Failed to read %s: %v", server.Log, err), actionLabel: label}
+		}
+		status := "exited"
+		if procs.Running(server.PID) {
+			status = fmt.Sprintf("running as pid %d", server.PID)
+		}
+		return actionCompleteMsg{
+			success:     true,
+			message:     fmt.Sprintf("%s, started %s, %s. Last %d lines of %s:\n\n%s", server.Command, server.StartedAt.Format("Jan 2 15:04"), status, serverLogLines, server.Log, output),
+			actionLabel: label,
+		}
+	}
+}
+
+// refreshServer updates whether a project's dev server is still running, and
+// the port it listens on. The port is remembered once the server's log or
+// lsof shows it; until then it's guessed from the command.
+func (m *Model) refreshServer(p *project.Project) {
+	p.ServerPID, p.ServerPort = 0, 0
+	server := m.state.Server(p.Path)
+	if server == nil || !procs.Running(server.PID) {
+		return
+	}
+	if server.Port == 0 {
+		server.Port = procs.Port(server.Log)
+		if ports := procs.ListeningPorts(server.PID); server.Port == 0 && len(ports) > 0 {
+			server.Port = ports[0]
+		}
+		if server.Port > 0 {
+			if err := m.state.Save(); err != nil {
+				m.toasts.Error(fmt.Errorf("failed to save state: %w", err))
+			}
+		}
+	}
+	p.ServerPID, p.ServerPort = server.PID, server.Port
+	if p.ServerPort == 0 {
+		p.ServerPort = scripts.Port(p.WorkPath(), server.Command)
+	}
+}
+
+// serverLogExists reports whether a dev server's log is still on disk
+func serverLogExists(server *state.Server) bool {
+	_, err := os.Stat(server.Log)
+	return err == nil
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// snippetAction returns the "Run Snippet" submenu with the snippets that
+// apply to the selected project's language, or nil if there are none
+func (m Model) snippetAction() *views.Action {
+	var children []views.Action
+	for _, s := range m.config.Snippets {
+		if s.Name == "" || s.Command == "" || !s.AppliesTo(m.selectedProject.Language) {
+			continue
+		}
+		command := actions.ExpandSnippet(s.Command, m.selectedProject)
+		desc := s.Desc
+		if desc == "" {
+			desc = command
+		}
+		children = append(children, views.Action{
+			ID:      actions.SnippetActionID(s),
+			Label:   s.Name,
+			Desc:    desc,
+			Icon:    "▸",
+			Command: command,
+			Source:  "snippet",
+		})
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	return &views.Action{
+		ID:        "submenu-snippets",
+		Label:     "Run Snippet",
+		Desc:      fmt.Sprintf("%d commands from your snippet library", len(children)),
+		Icon:      "📎",
+		IsSubmenu: true,
+		Children:  children,
+	}
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/report"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// StaleTag is the tag applied to projects from the stale report
+const StaleTag = "stale"
+
+// StaleReportModel is a standalone TUI for reviewing stale projects and
+// bulk-archiving or tagging them
+type StaleReportModel struct {
+	items    []report.StaleProject
+	selected map[int]bool
+	cursor   int
+	offset   int
+	days     int
+	state    *state.State
+	keys     tui.KeyMap
+	width    int
+	height   int
+	toasts   tui.Toasts // Outcome of archiving or tagging
+}
+
+// NewStaleReportModel creates the interactive stale report
+func NewStaleReportModel(items []report.StaleProject, days int, st *state.State) StaleReportModel {
+	return StaleReportModel{
+		items:    items,
+		selected: make(map[int]bool),
+		days:     days,
+		state:    st,
+		keys:     tui.DefaultKeyMap(),
+	}
+}
+
+// Init initializes the model
+func (m StaleReportModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m StaleReportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tui.ToastExpiredMsg:
+		m.toasts.Dismiss(msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Back):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+		case msg.String() == " ":
+			if len(m.items) > 0 {
+				m.selected[m.cursor] = !m.selected[m.cursor]
+			}
+		case msg.String() == "A":
+			// Toggle all
+			all := len(m.selectedIndexes()) < len(m.items)
+			for i := range m.items {
+				m.selected[i] = all
+			}
+		case msg.String() == "a":
+			m.apply("Archived", func(path string) { m.state.SetArchived(path, true) })
+		case msg.String() == "t":
+			m.apply(fmt.Sprintf("Tagged %q", StaleTag), func(path string) { m.state.AddTag(path, StaleTag) })
+		}
+		m.scrollToCursor()
+	}
+
+	return m, m.toasts.Schedule()
+}
+
+// apply runs fn for each selected project (or the one under the cursor) and saves the state
+func (m *StaleReportModel) apply(verb string, fn func(path string)) {
+	indexes := m.selectedIndexes()
+	if len(indexes) == 0 && len(m.items) > 0 {
+		indexes = []int{m.cursor}
+	}
+	if len(indexes) == 0 {
+		return
+	}
+
+	for _, i := range indexes {
+		p := m.items[i].Project
+		fn(p.Path)
+		m.state.Annotate([]*project.Project{p})
+	}
+
+	if err := m.state.Save(); err != nil {
+		m.toasts.Error(err)
+		return
+	}
+
+	m.toasts.Success(fmt.Sprintf("%s %d project(s)", verb, len(indexes)))
+	m.selected = make(map[int]bool)
+}
+
+// selectedIndexes returns the indexes of the selected items in order
+func (m StaleReportModel) selectedIndexes() []int {
+	indexes := make([]int, 0, len(m.selected))
+	for i := range m.items {
+		if m.selected[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// visibleRows returns how many items fit on screen
+func (m StaleReportModel) visibleRows() int {
+	rows := m.height - 10
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// scrollToCursor keeps the cursor inside the visible window
+func (m *StaleReportModel) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+// View renders the report
+func (m StaleReportModel) View() string {
+	title := tui.TitleStyle.Render(fmt.Sprintf("🕸  Stale Projects (%d) — no activity in %d days", len(m.items), m.days))
+
+	if len(m.items) == 0 {
+		return tui.ContainerStyle.Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			tui.SubtitleStyle.Render("Nothing stale. Your code directory is tidy!"),
+			tui.HelpStyle.Render("q: quit"),
+		))
+	}
+
+	now := time.Now()
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+
+	var rows []string
+	end := m.offset + m.visibleRows()
+	if end > len(m.items) {
+		end = len(m.items)
+	}
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+		p := item.Project
+
+		check := "[ ]"
+		if m.selected[i] {
+			check = "[x]"
+		}
+
+		badges := ""
+		if p.Archived {
+			badges += "  archived"
+		}
+		if len(p.Tags) > 0 {
+			badges += "  #" + strings.Join(p.Tags, " #")
+		}
+
+		line := fmt.Sprintf("%s %-30s %5dd  (last %s)", check, p.Name, report.DaysSince(item.LastActivity, now), item.Source)
+		if i == m.cursor {
+			rows = append(rows, tui.SelectedStyle.Render("▸ "+line)+muted.Render(badges))
+		} else {
+			rows = append(rows, tui.NormalStyle.Render(line)+muted.Render(badges))
+		}
+	}
+
+	help := tui.HelpStyle.Render("↑/↓: navigate  •  space: select  •  A: select all  •  a: archive  •  t: tag stale  •  q: quit")
+
+	return m.toasts.Overlay(tui.ContainerStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		strings.Join(rows, "\n"),
+		"",
+		help,
+	)), m.width)
+}
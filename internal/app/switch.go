@@ -0,0 +1,63 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/wsl"
+)
+
+// rememberSelection tracks the last two projects whose menu was opened, for
+// switching between them
+func (m *Model) rememberSelection(p *project.Project) {
+	if p.Path == m.lastPath {
+		return
+	}
+	m.previousPath = m.lastPath
+	m.lastPath = p.Path
+}
+
+// switchTarget returns the project to switch to: from the list the last
+// project opened, from a project's menu the one before it. Before anything
+// is opened in this session, projects opened in earlier runs are used.
+func (m Model) switchTarget() *project.Project {
+	current := ""
+	if m.view == ViewActions && m.selectedProject != nil {
+		current = m.selectedProject.Path
+	}
+
+	candidates := []string{m.lastPath, m.previousPath}
+	candidates = append(candidates, m.state.RecentlyOpened()...)
+	for _, path := range candidates {
+		if path == "" || wsl.NormalizePath(path) == wsl.NormalizePath(current) {
+			continue
+		}
+		if p := m.findProjectByPath(path); p != nil && !p.IsGroup {
+			return p
+		}
+	}
+	return nil
+}
+
+// findProjectByPath returns the scanned project at path, if any
+func (m Model) findProjectByPath(path string) *project.Project {
+	key := wsl.NormalizePath(path)
+	for _, p := range m.projects {
+		if wsl.NormalizePath(p.Path) == key {
+			return p
+		}
+	}
+	return nil
+}
+
+// switchProject opens the menu of the other recently selected project
+func (m Model) switchProject() (tea.Model, tea.Cmd) {
+	target := m.switchTarget()
+	if target == nil {
+		return m, nil
+	}
+
+	m.selectedProject = target
+	m.selectedGroup = nil
+	m.submenuStack = nil
+	return m, m.openActionMenu()
+}
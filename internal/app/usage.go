@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/report"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// usagePeriods are the periods, in days, the stats view cycles through with tab
+var usagePeriods = []int{7, report.DefaultUsageDays, 90, 365}
+
+// openStats shows the usage statistics for the last usageDays
+func (m *Model) openStats() {
+	if m.usageDays == 0 {
+		m.usageDays = report.DefaultUsageDays
+	}
+	days := m.usageDays
+	usage := report.Usage(m.state, m.projects, days, time.Now())
+
+	content := usage.Render(report.BarWidth)
+	if usage.Empty() {
+		content = tui.SubtitleStyle.Render(fmt.Sprintf("Nothing opened or run in the last %d days.", days))
+	}
+
+	m.usageViewport = viewport.New(m.width-4, m.height-10)
+	m.usageViewport.SetContent(content)
+	m.setView(ViewStats)
+}
+
+// handleStatsKey handles keys in the stats view
+func (m Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+		m.setView(ViewProjects)
+		return m, nil
+	case msg.String() == "tab":
+		m.usageDays = nextUsagePeriod(m.usageDays)
+		m.openStats()
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.usageViewport, cmd = m.usageViewport.Update(msg)
+		return m, cmd
+	}
+}
+
+// nextUsagePeriod returns the period after days in usagePeriods, wrapping around
+func nextUsagePeriod(days int) int {
+	for i, d := range usagePeriods {
+		if d == days {
+			return usagePeriods[(i+1)%len(usagePeriods)]
+		}
+	}
+	return usagePeriods[0]
+}
+
+// renderStatsView renders the most used projects, actions and editors
+func (m Model) renderStatsView() string {
+	title := tui.TitleStyle.Render(fmt.Sprintf("📊 Stats — last %d days", m.usageDays))
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Width(m.width - 6).
+		Render(m.usageViewport.View())
+
+	help := tui.HelpStyle.Render("↑/↓: scroll  •  tab: change period  •  esc/q: close")
+
+	return tui.ContainerStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			content,
+			"",
+			help,
+		),
+	)
+}
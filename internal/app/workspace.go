@@ -0,0 +1,201 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/bulk"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/tui"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// configEditedMsg reports that the config editor opened from the workspace
+// menu has exited
+type configEditedMsg struct {
+	err error
+}
+
+// openWorkspace shows the actions that aren't tied to a project
+func (m Model) openWorkspace() (tea.Model, tea.Cmd) {
+	m.selectedProject = nil
+	m.workspaceMenu = views.NewActionMenuModel(nil, orderMenu(views.WorkspaceActions(), m.config.Actions, ""))
+	m.setView(ViewWorkspace)
+	m.updateSizes()
+	return m, nil
+}
+
+// handleWorkspaceKey runs the selected workspace action
+func (m Model) handleWorkspaceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+		m.setView(ViewProjects)
+		return m, nil
+	case key.Matches(msg, m.keys.Enter):
+		if action := m.workspaceMenu.SelectedAction(); action != nil {
+			return m.runWorkspaceAction(action.ID)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.workspaceMenu, cmd = m.workspaceMenu.Update(msg)
+	return m, cmd
+}
+
+// runWorkspaceAction runs a workspace action by ID
+func (m Model) runWorkspaceAction(id string) (tea.Model, tea.Cmd) {
+	switch id {
+	case "back":
+		m.setView(ViewProjects)
+		return m, nil
+
+	case "workspace-rescan":
+		m.setView(ViewLoading)
+		m.message = "Rescanning projects..."
+		return m, m.loadProjects()
+
+	case "workspace-pull-all", "workspace-sync-remotes":
+		m.eachTargets = gitProjects(m.projects)
+		if len(m.eachTargets) == 0 {
+			m.toasts.Error(fmt.Errorf("no git projects to update"))
+			m.setView(ViewProjects)
+			return m, nil
+		}
+		label, args := "Pull all", []string{"pull", "--ff-only"}
+		if id == "workspace-sync-remotes" {
+			label, args = "Sync remotes", []string{"fetch", "--all", "--prune"}
+		}
+		return m.startBulk("git "+actions.QuoteArgs(args), gitTask(args...), func([]bulk.Outcome) actionCompleteMsg {
+			// Branches and dirty states may have changed
+			return actionCompleteMsg{actionLabel: label, shouldReload: true}
+		})
+
+	case "workspace-clean-all":
+		return m, runProj("Clean all", "--clean-all")
+
+	case "workspace-open-config":
+		self, err := os.Executable()
+		if err != nil {
+			m.toasts.Error(err)
+			return m, nil
+		}
+		return m, tea.ExecProcess(exec.Command(self, "--config"), func(err error) tea.Msg {
+			return configEditedMsg{err: err}
+		})
+
+	case "workspace-open-plugins":
+		dir := m.pluginRegistry.Dir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			m.toasts.Error(fmt.Errorf("failed to create plugins directory: %w", err))
+			return m, nil
+		}
+		cfg := m.config
+		return m, func() tea.Msg {
+			result := actions.NewExecutor(cfg).Execute("open-editor", &project.Project{Name: filepath.Base(dir), Path: dir})
+			return actionCompleteMsg{
+				success:     result.Success,
+				message:     result.Message,
+				actionLabel: "Open plugins directory",
+				execCmd:     result.ExecCmd,
+			}
+		}
+	}
+	return m, nil
+}
+
+// reloadConfig reads the config again after it was edited and rescans, since
+// roots and filters may have changed. Plugins keep their settings until proj
+// is restarted.
+func (m Model) reloadConfig(msg configEditedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.toasts.Error(fmt.Errorf("failed to edit config: %w", msg.err))
+		m.setView(ViewProjects)
+		return m, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		m.toasts.Error(fmt.Errorf("failed to reload config: %w", err))
+		m.setView(ViewProjects)
+		return m, nil
+	}
+	*m.config = *cfg
+	tui.ReducedMotion = cfg.Display.ReducedMotion
+	m.toasts.Success("Config reloaded")
+	m.setView(ViewLoading)
+	m.message = "Reloading config..."
+	return m, m.loadProjects()
+}
+
+// gitProjects returns the projects on disk that are git repositories.
+// Projects whose details are still loading are checked for a .git directory.
+func gitProjects(projects []*project.Project) []*project.Project {
+	var repos []*project.Project
+	for _, p := range projects {
+		if p.IsGroup || p.Source != "" {
+			continue
+		}
+		if p.Enriching {
+			if info, err := os.Stat(filepath.Join(p.Path, ".git")); err != nil || !info.IsDir() {
+				continue
+			}
+		} else if !p.IsGitRepo {
+			continue
+		}
+		repos = append(repos, p)
+	}
+	return repos
+}
+
+// gitTask returns a bulk task running git with args in each project, failing
+// rather than prompting for credentials
+func gitTask(args ...string) bulk.Task {
+	return func(ctx context.Context, p *project.Project) (string, error) {
+		cmd := git.BackgroundCommand(ctx, p.Path, args...)
+		// Don't wait on ssh holding the output open after a timeout
+		cmd.WaitDelay = time.Second
+
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		err := cmd.Run()
+		return out.String(), err
+	}
+}
+
+// runProj runs proj itself with args attached to the terminal, such as the
+// interactive clean-all view, and reports when it exits
+func runProj(label string, args ...string) tea.Cmd {
+	self, err := os.Executable()
+	if err != nil {
+		return func() tea.Msg {
+			return actionCompleteMsg{success: false, message: err.Error(), actionLabel: label}
+		}
+	}
+	return tea.ExecProcess(exec.Command(self, args...), func(err error) tea.Msg {
+		if err != nil {
+			return actionCompleteMsg{success: false, message: fmt.Sprintf("proj %s failed: %v", args[0], err), actionLabel: label}
+		}
+		return actionCompleteMsg{success: true, message: label + " finished", actionLabel: label, shouldReload: true}
+	})
+}
+
+// renderWorkspaceView renders the workspace actions menu
+func (m Model) renderWorkspaceView() string {
+	header := tui.TitleStyle.Render("🗂  Workspace")
+	subtitle := tui.SubtitleStyle.Render(fmt.Sprintf("Actions across %s", m.config.ReposPath))
+	help := tui.HelpStyle.Render("↑/↓: select  •  enter: run  •  esc/q: back")
+
+	return tui.ContainerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, header, subtitle, "", m.workspaceMenu.View(), "", help))
+}
@@ -0,0 +1,192 @@
+// Package audit keeps an append-only log of the commands proj runs for the
+// user: actions, scripts and commands run across projects, with where and
+// when they ran, how long they took and how they exited. It is off unless
+// audit.enabled is set. Commands proj runs on its own to read state, such
+// as git status for the project list, aren't logged.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s33g/proj/internal/bulk"
+	"github.com/s33g/proj/internal/config"
+)
+
+// FileName is the log's name in the config directory
+const FileName = "audit.log"
+
+// Entry is a command proj ran
+type Entry struct {
+	Time       time.Time `json:"time"` // When it started
+	Project    string    `json:"project,omitempty"`
+	Action     string    `json:"action,omitempty"`  // Action ID, or "each" for proj each
+	Command    string    `json:"command,omitempty"` // What ran, as a shell would write it
+	Dir        string    `json:"dir"`
+	Success    bool      `json:"success"`
+	ExitCode   int       `json:"exitCode"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// Duration returns how long the command took
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// mu keeps lines written at the same time, e.g. by proj each, whole
+var mu sync.Mutex
+
+// Path returns the log file cfg writes to
+func Path(cfg *config.Config) (string, error) {
+	if cfg.Audit.Path != "" {
+		return config.ExpandPath(cfg.Audit.Path), nil
+	}
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Record appends e to the log when auditing is enabled, stamping its
+// duration from its start time when it has none
+func Record(cfg *config.Config, e Entry) error {
+	if !cfg.Audit.Enabled {
+		return nil
+	}
+	if e.DurationMs == 0 && !e.Time.IsZero() {
+		e.DurationMs = time.Since(e.Time).Milliseconds()
+	}
+	path, err := Path(cfg)
+	if err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	if err := Append(path, e); err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	return nil
+}
+
+// RecordOutcome logs a project's run of command by proj each. Projects it
+// skipped as unchanged didn't run and aren't logged.
+func RecordOutcome(cfg *config.Config, command string, o bulk.Outcome) error {
+	if o.Skipped {
+		return nil
+	}
+	return Record(cfg, Entry{
+		Time:       time.Now().Add(-o.Duration),
+		Project:    o.Project.Name,
+		Action:     "each",
+		Command:    command,
+		Dir:        o.Project.Path,
+		Success:    o.Success,
+		ExitCode:   ExitCode(o.Err),
+		DurationMs: o.Duration.Milliseconds(),
+	})
+}
+
+// Append writes e to the end of the log at path as a line of JSON. The log
+// is only ever appended to, and readable by the user alone.
+func Append(path string, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries of the log at path, oldest first. A missing log
+// has none; lines that aren't entries, such as one cut short by a crash,
+// are skipped.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Filter returns the entries since the given time (any, when zero) for the
+// named project (any, when empty)
+func Filter(entries []Entry, since time.Time, project string) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.Time.Before(since) || (project != "" && !strings.EqualFold(e.Project, project)) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Format renders entries one per line: when, project, outcome, duration,
+// directory and command
+func Format(entries []Entry) string {
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Project))
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		status := "✓"
+		if !e.Success {
+			status = "✗"
+		}
+		command := e.Command
+		if command == "" {
+			command = e.Action
+		}
+		fmt.Fprintf(&b, "%s  %-*s  %s %3d  %7s  %s  %s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"), width, e.Project, status, e.ExitCode,
+			e.Duration().Round(10*time.Millisecond), e.Dir, command)
+	}
+	return b.String()
+}
+
+// ExitCode returns the exit code of a command that failed with err: its
+// own when it ran, otherwise 1. It's 0 without an error.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/bulk"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", FileName)
+
+	entries, err := Read(path)
+	if err != nil || entries != nil {
+		t.Fatalf("Read() of a missing log = %v, %v, want nothing", entries, err)
+	}
+
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{Time: start, Project: "api", Command: "go test ./...", Dir: "/code/api", Success: true, DurationMs: 1420},
+		{Time: start.Add(time.Hour), Project: "web", Command: "npm run build", Dir: "/code/web", ExitCode: 2},
+	} {
+		if err := Append(path, e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"time": "2026-10`)
+	f.Close()
+
+	entries, err = Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Project != "api" || entries[1].ExitCode != 2 {
+		t.Fatalf("Read() = %+v, want the two entries without the torn line", entries)
+	}
+	if entries[0].Duration() != 1420*time.Millisecond {
+		t.Errorf("Duration() = %v", entries[0].Duration())
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the log to be readable by the user alone, got %v", info.Mode().Perm())
+	}
+
+	if got := Filter(entries, start.Add(time.Minute), ""); len(got) != 1 || got[0].Project != "web" {
+		t.Errorf("Filter(since) = %+v, want web", got)
+	}
+	if got := Filter(entries, time.Time{}, "API"); len(got) != 1 || got[0].Project != "api" {
+		t.Errorf("Filter(project) = %+v, want api", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(Format(entries)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "✓") || !strings.HasSuffix(lines[1], "/code/web  npm run build") {
+		t.Errorf("Format() = %q", lines)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Audit.Path = filepath.Join(t.TempDir(), FileName)
+
+	if err := Record(cfg, Entry{Time: time.Now(), Project: "api"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cfg.Audit.Path); !os.IsNotExist(err) {
+		t.Fatal("Expected nothing recorded while auditing is off")
+	}
+
+	cfg.Audit.Enabled = true
+	proj := &project.Project{Name: "api", Path: "/code/api"}
+	outcomes := []bulk.Outcome{
+		{Project: proj, Success: true, Duration: 2 * time.Second},
+		{Project: proj, Skipped: true},
+		{Project: proj, Err: errors.New("timed out"), TimedOut: true},
+	}
+	for _, o := range outcomes {
+		if err := RecordOutcome(cfg, "go vet ./...", o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, _ := Read(cfg.Audit.Path)
+	if len(entries) != 2 {
+		t.Fatalf("Expected the skipped project not to be recorded, got %+v", entries)
+	}
+	if e := entries[0]; e.Action != "each" || e.Command != "go vet ./..." || e.Dir != "/code/api" || e.DurationMs != 2000 || !e.Success {
+		t.Errorf("RecordOutcome() recorded %+v", e)
+	}
+	if e := entries[1]; e.Success || e.ExitCode != 1 {
+		t.Errorf("Expected the failure recorded with exit code 1, got %+v", e)
+	}
+}
@@ -0,0 +1,270 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// Outcome is the result of running a task in one project
+type Outcome struct {
+	Project  *project.Project
+	Success  bool
+	Output   string
+	Err      error
+	Duration time.Duration
+	Skipped  bool // The task returned ErrUnchanged and didn't run
+	TimedOut bool // The task was stopped after Options.Timeout
+}
+
+// Task does the work for a single project and returns its output. It should
+// stop when ctx is done.
+type Task func(ctx context.Context, p *project.Project) (string, error)
+
+// Options control how Schedule runs a task across projects
+type Options struct {
+	Concurrency int                    // Tasks running at once; DefaultConcurrency if <= 0
+	Timeout     time.Duration          // Per project; 0 means no limit
+	OnStart     func(*project.Project) // Called as each project starts, if set
+	OnDone      func(Outcome)          // Called as each project finishes, if set
+}
+
+// DefaultConcurrency is used when concurrency is not set
+func DefaultConcurrency() int {
+	return runtime.NumCPU()
+}
+
+// Run runs task in every project with at most concurrency tasks at a time.
+// onDone, if set, is called (one at a time) as each project finishes.
+// Outcomes are returned in the order of projects.
+func Run(projects []*project.Project, concurrency int, task Task, onDone func(Outcome)) []Outcome {
+	return Schedule(projects, task, Options{Concurrency: concurrency, OnDone: onDone})
+}
+
+// Schedule runs task in every project as opts allow. OnStart and OnDone are
+// called one at a time. Outcomes are returned in the order of projects.
+func Schedule(projects []*project.Project, task Task, opts Options) []Outcome {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency()
+	}
+
+	outcomes := make([]Outcome, len(projects))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i, p := range projects {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, p *project.Project) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if opts.OnStart != nil {
+				mu.Lock()
+				opts.OnStart(p)
+				mu.Unlock()
+			}
+
+			outcome := runOne(p, task, opts.Timeout)
+			outcomes[i] = outcome
+
+			if opts.OnDone != nil {
+				mu.Lock()
+				opts.OnDone(outcome)
+				mu.Unlock()
+			}
+		}(i, p)
+	}
+
+	wg.Wait()
+	return outcomes
+}
+
+// runOne runs task in a project, stopping it after timeout if there is one
+func runOne(p *project.Project, task Task, timeout time.Duration) Outcome {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	output, err := task(ctx, p)
+	outcome := Outcome{
+		Project:  p,
+		Success:  err == nil,
+		Output:   output,
+		Err:      err,
+		Duration: time.Since(start),
+	}
+	if errors.Is(err, ErrUnchanged) {
+		return Outcome{Project: p, Success: true, Skipped: true}
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		outcome.TimedOut = true
+		outcome.Err = fmt.Errorf("timed out after %s", timeout)
+	}
+	return outcome
+}
+
+// CommandFunc returns the command running args in a project, killed when
+// ctx is done
+type CommandFunc func(ctx context.Context, p *project.Project, args []string) *exec.Cmd
+
+// Command returns a task that runs args in each project, as the command
+// made by build so it runs like the project's actions do. A nil build runs
+// args in the project's workdir as they are.
+func Command(args []string, build CommandFunc) Task {
+	if build == nil {
+		build = func(ctx context.Context, p *project.Project, args []string) *exec.Cmd {
+			cmd := sys.CommandContext(ctx, args[0], args[1:]...)
+			cmd.Dir = p.WorkPath()
+			return cmd
+		}
+	}
+	return func(ctx context.Context, p *project.Project) (string, error) {
+		if len(args) == 0 {
+			return "", fmt.Errorf("empty command")
+		}
+
+		cmd := build(ctx, p, args)
+		// Don't wait on grandchildren holding the output open after a timeout
+		cmd.WaitDelay = time.Second
+
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+
+		err := cmd.Run()
+		return out.String(), err
+	}
+}
+
+// Summarize counts passed and failed outcomes. Skipped projects count as
+// passed; see Skipped.
+func Summarize(outcomes []Outcome) (passed, failed int) {
+	for _, o := range outcomes {
+		if o.Success {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// Skipped counts the outcomes of projects that were skipped as unchanged
+func Skipped(outcomes []Outcome) int {
+	skipped := 0
+	for _, o := range outcomes {
+		if o.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// Totals renders the "3 passed, 1 failed" summary line, with the number of
+// skipped projects if any were
+func Totals(outcomes []Outcome) string {
+	passed, failed := Summarize(outcomes)
+	skipped := Skipped(outcomes)
+	if skipped == 0 {
+		return fmt.Sprintf("%d passed, %d failed", passed, failed)
+	}
+	return fmt.Sprintf("%d passed, %d failed, %d skipped as unchanged", passed-skipped, failed, skipped)
+}
+
+// Header returns the "✓ name (1.2s)" line shown above a project's output
+func Header(o Outcome) string {
+	if o.Skipped {
+		return fmt.Sprintf("↷ %s: %v", o.Project.Name, ErrUnchanged)
+	}
+	icon := "✓"
+	if !o.Success {
+		icon = "✗"
+	}
+	header := fmt.Sprintf("%s %s (%s)", icon, o.Project.Name, o.Duration.Round(10*time.Millisecond))
+	if o.Err != nil {
+		header += ": " + o.Err.Error()
+	}
+	return header
+}
+
+// Report renders the aggregated output of every project followed by a summary
+func Report(outcomes []Outcome) string {
+	var b strings.Builder
+
+	for _, o := range outcomes {
+		b.WriteString(Header(o))
+		b.WriteString("\n")
+		if output := strings.TrimRight(o.Output, "\n"); output != "" {
+			for _, line := range strings.Split(output, "\n") {
+				b.WriteString("  │ " + line + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(Durations(outcomes))
+	b.WriteString("\n")
+	b.WriteString(Totals(outcomes))
+	return b.String()
+}
+
+// Durations renders a table of how long each project took, slowest first.
+// Skipped projects are left out.
+func Durations(outcomes []Outcome) string {
+	ran := make([]Outcome, 0, len(outcomes))
+	width := len("Project")
+	for _, o := range outcomes {
+		if o.Skipped {
+			continue
+		}
+		ran = append(ran, o)
+		if len(o.Project.Name) > width {
+			width = len(o.Project.Name)
+		}
+	}
+	if len(ran) == 0 {
+		return ""
+	}
+	sort.SliceStable(ran, func(i, j int) bool { return ran[i].Duration > ran[j].Duration })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-*s  %9s\n", width, "Project", "Duration")
+	for _, o := range ran {
+		status := "✓"
+		switch {
+		case o.TimedOut:
+			status = "⏱"
+		case !o.Success:
+			status = "✗"
+		}
+		fmt.Fprintf(&b, "%s %-*s  %9s\n", status, width, o.Project.Name, o.Duration.Round(10*time.Millisecond))
+	}
+	return b.String()
+}
+
+// Rate renders how many projects finished in elapsed, e.g. "12 in 8.2s (1.5/s)"
+func Rate(finished int, elapsed time.Duration) string {
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(finished) / elapsed.Seconds()
+	}
+	return fmt.Sprintf("%d in %s (%.1f/s)", finished, elapsed.Round(100*time.Millisecond), rate)
+}
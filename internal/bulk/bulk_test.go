@@ -0,0 +1,140 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestRun(t *testing.T) {
+	projects := []*project.Project{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+
+	var running, maxRunning int32
+	task := func(ctx context.Context, p *project.Project) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		if p.Name == "c" {
+			return "boom", errors.New("exit status 1")
+		}
+		return "ok " + p.Name, nil
+	}
+
+	done := 0
+	outcomes := Run(projects, 2, task, func(Outcome) { done++ })
+
+	if done != 4 {
+		t.Errorf("Expected onDone for every project, got %d", done)
+	}
+	if maxRunning > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, got %d", maxRunning)
+	}
+
+	// Outcomes keep the input order
+	for i, o := range outcomes {
+		if o.Project != projects[i] {
+			t.Errorf("Outcome %d is for %s, want %s", i, o.Project.Name, projects[i].Name)
+		}
+	}
+
+	passed, failed := Summarize(outcomes)
+	if passed != 3 || failed != 1 {
+		t.Errorf("Summarize = %d passed, %d failed; want 3, 1", passed, failed)
+	}
+
+	report := Report(outcomes)
+	if !strings.Contains(report, "✗ c") || !strings.Contains(report, "│ boom") {
+		t.Errorf("Expected failure details in report, got:\n%s", report)
+	}
+	if !strings.HasSuffix(report, "3 passed, 1 failed") {
+		t.Errorf("Expected summary at the end, got:\n%s", report)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	p := &project.Project{Name: "a", Path: dir}
+
+	output, err := Command([]string{"pwd"}, nil)(context.Background(), p)
+	if err != nil {
+		t.Skipf("pwd not available: %v", err)
+	}
+	if !strings.Contains(output, filepath.Base(dir)) {
+		t.Errorf("Expected command to run in %s, got %q", dir, output)
+	}
+
+	// A project's workdir is where its commands run
+	os.Mkdir(filepath.Join(dir, "backend"), 0755)
+	p.Workdir = "backend"
+	if output, _ := Command([]string{"pwd"}, nil)(context.Background(), p); !strings.HasSuffix(strings.TrimSpace(output), "backend") {
+		t.Errorf("Expected command to run in the workdir, got %q", output)
+	}
+
+	if _, err := Command(nil, nil)(context.Background(), p); err == nil {
+		t.Error("Expected error for empty command")
+	}
+}
+
+func TestSchedule_Timeout(t *testing.T) {
+	projects := []*project.Project{{Name: "fast", Path: t.TempDir()}, {Name: "slow", Path: t.TempDir()}}
+
+	var started []string
+	outcomes := Schedule(projects, func(ctx context.Context, p *project.Project) (string, error) {
+		if p.Name == "fast" {
+			return "ok", nil
+		}
+		<-ctx.Done()
+		return "", ctx.Err()
+	}, Options{
+		Concurrency: 1,
+		Timeout:     20 * time.Millisecond,
+		OnStart:     func(p *project.Project) { started = append(started, p.Name) },
+	})
+
+	if len(started) != 2 || started[0] != "fast" {
+		t.Errorf("OnStart called for %v, want fast then slow", started)
+	}
+	if outcomes[0].TimedOut || !outcomes[0].Success {
+		t.Errorf("fast outcome = %+v", outcomes[0])
+	}
+	if !outcomes[1].TimedOut || outcomes[1].Success || !strings.Contains(outcomes[1].Err.Error(), "timed out after 20ms") {
+		t.Errorf("slow outcome = %+v, want a timeout", outcomes[1])
+	}
+}
+
+func TestDurations(t *testing.T) {
+	outcomes := []Outcome{
+		{Project: &project.Project{Name: "api"}, Success: true, Duration: 1200 * time.Millisecond},
+		{Project: &project.Project{Name: "web-frontend"}, Success: false, Duration: 3 * time.Second},
+		{Project: &project.Project{Name: "docs"}, Success: true, Skipped: true},
+	}
+
+	lines := strings.Split(strings.TrimRight(Durations(outcomes), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Durations() = %q, want a header and two rows", lines)
+	}
+	if !strings.HasPrefix(lines[1], "✗ web-frontend") || !strings.HasSuffix(lines[1], "3s") {
+		t.Errorf("slowest row = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "✓ api         ") || !strings.HasSuffix(lines[2], "1.2s") {
+		t.Errorf("second row = %q", lines[2])
+	}
+
+	if got := Rate(12, 8*time.Second); got != "12 in 8s (1.5/s)" {
+		t.Errorf("Rate() = %q", got)
+	}
+}
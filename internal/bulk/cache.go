@@ -0,0 +1,149 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+// ErrUnchanged is returned by tasks that skipped a project because it hasn't
+// changed since the task last succeeded there
+var ErrUnchanged = errors.New("unchanged since the last successful run")
+
+// skippedDirs are not part of a fingerprint outside git: they hold
+// dependencies and build output, not the project's own files
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"target":       true,
+	"__pycache__":  true,
+}
+
+// Fingerprint summarises the state of a project's files. For git
+// repositories it covers HEAD, changes to tracked files and untracked files;
+// otherwise every file's path, size and modification time.
+func Fingerprint(p *project.Project) (string, error) {
+	h := sha256.New()
+	var err error
+	if _, statErr := os.Stat(filepath.Join(p.Path, ".git")); statErr == nil {
+		err = gitFingerprint(h, p.Path)
+	} else {
+		err = treeFingerprint(h, p.Path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// gitFingerprint hashes the commit, the diff against it and untracked files
+func gitFingerprint(w io.Writer, dir string) error {
+	for _, args := range [][]string{
+		{"rev-parse", "HEAD"},
+		{"diff", "HEAD", "--binary"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+		if err != nil {
+			return fmt.Errorf("git %s: %w", args[0], err)
+		}
+		w.Write(out)
+	}
+
+	out, err := exec.Command("git", "-C", dir, "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return fmt.Errorf("git ls-files: %w", err)
+	}
+	for _, name := range bytes.Split(out, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		if info, err := os.Lstat(filepath.Join(dir, string(name))); err == nil {
+			fmt.Fprintf(w, "%s\x00%d\x00%d\n", name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return nil
+}
+
+// treeFingerprint hashes the path, size and modification time of every file
+func treeFingerprint(w io.Writer, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()]) {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if d.IsDir() {
+			fmt.Fprintf(w, "%s/\n", rel)
+			return nil
+		}
+		fmt.Fprintf(w, "%s\x00%d\x00%d\n", rel, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+}
+
+// Skipper skips projects that haven't changed since a command last succeeded
+// in them, and collects the fingerprints to remember for the next run
+type Skipper struct {
+	last map[string]string // Project path -> fingerprint when the command last succeeded
+
+	mu      sync.Mutex
+	current map[string]string // Project path -> fingerprint before this run
+}
+
+// NewSkipper creates a skipper from the fingerprints recorded after the last
+// successful runs, keyed by project path
+func NewSkipper(last map[string]string) *Skipper {
+	return &Skipper{last: last, current: make(map[string]string)}
+}
+
+// Wrap returns a task that returns ErrUnchanged instead of running task in
+// projects whose fingerprint matches the recorded one. Projects that can't
+// be fingerprinted always run.
+func (s *Skipper) Wrap(task Task) Task {
+	return func(ctx context.Context, p *project.Project) (string, error) {
+		fingerprint, err := Fingerprint(p)
+		if err != nil {
+			return task(ctx, p)
+		}
+		if last, ok := s.last[p.Path]; ok && last == fingerprint {
+			return "", ErrUnchanged
+		}
+
+		s.mu.Lock()
+		s.current[p.Path] = fingerprint
+		s.mu.Unlock()
+		return task(ctx, p)
+	}
+}
+
+// Succeeded returns the fingerprints to record for the projects the task ran
+// and succeeded in, keyed by project path
+func (s *Skipper) Succeeded(outcomes []Outcome) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	succeeded := make(map[string]string)
+	for _, o := range outcomes {
+		if fingerprint, ok := s.current[o.Project.Path]; ok && o.Success && !o.Skipped {
+			succeeded[o.Project.Path] = fingerprint
+		}
+	}
+	return succeeded
+}
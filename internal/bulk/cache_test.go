@@ -0,0 +1,104 @@
+package bulk
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestFingerprint_Git(t *testing.T) {
+	dir := t.TempDir()
+	if err := exec.Command("git", "-C", dir, "init").Run(); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	exec.Command("git", "-C", dir, "config", "user.email", "test@test.com").Run()
+	exec.Command("git", "-C", dir, "config", "user.name", "Test User").Run()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	exec.Command("git", "-C", dir, "add", ".").Run()
+	exec.Command("git", "-C", dir, "commit", "-m", "init").Run()
+	p := &project.Project{Name: "a", Path: dir}
+
+	before, err := Fingerprint(p)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if again, _ := Fingerprint(p); again != before {
+		t.Error("fingerprint changed without any change to the project")
+	}
+
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	edited, _ := Fingerprint(p)
+	if edited == before {
+		t.Error("editing a tracked file should change the fingerprint")
+	}
+
+	os.WriteFile(filepath.Join(dir, "new.go"), []byte("package main\n"), 0644)
+	if untracked, _ := Fingerprint(p); untracked == edited {
+		t.Error("adding an untracked file should change the fingerprint")
+	}
+}
+
+func TestFingerprint_Tree(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	p := &project.Project{Name: "a", Path: dir}
+
+	before, err := Fingerprint(p)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+
+	// Dependencies don't count
+	os.MkdirAll(filepath.Join(dir, "node_modules", "x"), 0755)
+	os.WriteFile(filepath.Join(dir, "node_modules", "x", "index.js"), []byte("x"), 0644)
+	if after, _ := Fingerprint(p); after != before {
+		t.Error("node_modules should not change the fingerprint")
+	}
+
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644)
+	if after, _ := Fingerprint(p); after == before {
+		t.Error("a new file should change the fingerprint")
+	}
+}
+
+func TestSkipper(t *testing.T) {
+	unchanged := &project.Project{Name: "unchanged", Path: t.TempDir()}
+	changed := &project.Project{Name: "changed", Path: t.TempDir()}
+	failing := &project.Project{Name: "failing", Path: t.TempDir()}
+	projects := []*project.Project{unchanged, changed, failing}
+
+	fingerprint, _ := Fingerprint(unchanged)
+	skipper := NewSkipper(map[string]string{
+		unchanged.Path: fingerprint,
+		changed.Path:   "stale",
+	})
+
+	ran := make(map[string]bool)
+	task := func(ctx context.Context, p *project.Project) (string, error) {
+		ran[p.Name] = true
+		if p == failing {
+			return "", os.ErrInvalid
+		}
+		return "ok", nil
+	}
+	outcomes := Run(projects, 1, skipper.Wrap(task), nil)
+
+	if ran["unchanged"] || !ran["changed"] || !ran["failing"] {
+		t.Errorf("ran %v, want only changed and failing", ran)
+	}
+	if !outcomes[0].Skipped || !outcomes[0].Success {
+		t.Errorf("unchanged outcome = %+v, want skipped", outcomes[0])
+	}
+	if Skipped(outcomes) != 1 || Totals(outcomes) != "1 passed, 1 failed, 1 skipped as unchanged" {
+		t.Errorf("Totals() = %q", Totals(outcomes))
+	}
+
+	succeeded := skipper.Succeeded(outcomes)
+	if len(succeeded) != 1 || succeeded[changed.Path] == "" {
+		t.Errorf("Succeeded() = %v, want only the changed project", succeeded)
+	}
+}
@@ -2,24 +2,72 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+// DefaultMaxProjects is the default cap on directories scanned under reposPath
+const DefaultMaxProjects = 2000
+
 // Config represents the application configuration
 type Config struct {
-	ReposPath       string        `json:"reposPath" mapstructure:"reposPath"`
-	Editor          EditorConfig  `json:"editor" mapstructure:"editor"`
-	Shell           string        `json:"shell" mapstructure:"shell"`
-	Theme           ThemeConfig   `json:"theme" mapstructure:"theme"`
-	Display         DisplayConfig `json:"display" mapstructure:"display"`
-	ExcludePatterns []string      `json:"excludePatterns" mapstructure:"excludePatterns"`
-	Actions         ActionsConfig `json:"actions" mapstructure:"actions"`
-	Plugins         PluginsConfig `json:"plugins" mapstructure:"plugins"`
+	ReposPath         string            `json:"reposPath" mapstructure:"reposPath"`
+	Editor            EditorConfig      `json:"editor" mapstructure:"editor"`
+	Shell             string            `json:"shell" mapstructure:"shell"`
+	Theme             ThemeConfig       `json:"theme" mapstructure:"theme"`
+	Display           DisplayConfig     `json:"display" mapstructure:"display"`
+	Layout            string            `json:"layout" mapstructure:"layout"`               // How roots are organised: flat or ghq (host/owner/repo)
+	ExtraRoots        []string          `json:"extraRoots" mapstructure:"extraRoots"`       // More directories scanned like reposPath
+	ExtraProjects     []string          `json:"extraProjects" mapstructure:"extraProjects"` // Project directories outside any root
+	ExcludePatterns   []string          `json:"excludePatterns" mapstructure:"excludePatterns"`
+	MaxProjects       int               `json:"maxProjects" mapstructure:"maxProjects"` // Hard cap on directories scanned
+	Actions           ActionsConfig     `json:"actions" mapstructure:"actions"`
+	Plugins           PluginsConfig     `json:"plugins" mapstructure:"plugins"`
+	Backup            BackupConfig      `json:"backup" mapstructure:"backup"`
+	WSL               WSLConfig         `json:"wsl" mapstructure:"wsl"`
+	Container         ContainerConfig   `json:"container" mapstructure:"container"`
+	OnOpen            string            `json:"onOpen" mapstructure:"onOpen"`           // What "Open in editor" does: editorOnly, cdOnly or both
+	AfterCreate       string            `json:"afterCreate" mapstructure:"afterCreate"` // What happens once a project is created: menu, open or result
+	Hooks             HooksConfig       `json:"hooks" mapstructure:"hooks"`
+	Snippets          []Snippet         `json:"snippets" mapstructure:"snippets"` // Reusable commands offered in every project
+	Bulk              BulkConfig        `json:"bulk" mapstructure:"bulk"`
+	Clean             CleanConfig       `json:"clean" mapstructure:"clean"`
+	DeletePermanently bool              `json:"deletePermanently" mapstructure:"deletePermanently"` // Delete cleaned files instead of moving them to the OS trash
+	ProjectAliases    map[string]string `json:"projectAliases" mapstructure:"projectAliases"`       // Short name -> project name or path
+	Templates         map[string]string `json:"templates" mapstructure:"templates"`                 // Template name -> repository to create new projects from
+	Issues            IssuesConfig      `json:"issues" mapstructure:"issues"`
+	Branches          BranchesConfig    `json:"branches" mapstructure:"branches"`
+	Commit            CommitConfig      `json:"commit" mapstructure:"commit"`
+	DevServer         DevServerConfig   `json:"devServer" mapstructure:"devServer"`
+	Audit             AuditConfig       `json:"audit" mapstructure:"audit"`
 }
 
+// Open behaviors for OnOpen
+const (
+	OnOpenEditorOnly = "editorOnly"
+	OnOpenCdOnly     = "cdOnly"
+	OnOpenBoth       = "both"
+)
+
+// What happens once a project is created, for AfterCreate
+const (
+	AfterCreateMenu   = "menu"   // Open the new project's action menu
+	AfterCreateOpen   = "open"   // Open it like "Open in Editor" does
+	AfterCreateResult = "result" // Show what was created
+)
+
+// Directory layouts for Layout
+const (
+	LayoutFlat = "flat" // Projects and groups of projects
+	LayoutGHQ  = "ghq"  // host/owner/repo, as created by ghq
+)
+
 // EditorConfig holds editor settings
 type EditorConfig struct {
 	Default string              `json:"default" mapstructure:"default"`
@@ -28,21 +76,357 @@ type EditorConfig struct {
 
 // ThemeConfig holds color theme settings
 type ThemeConfig struct {
-	PrimaryColor string `json:"primaryColor" mapstructure:"primaryColor"`
-	AccentColor  string `json:"accentColor" mapstructure:"accentColor"`
-	ErrorColor   string `json:"errorColor" mapstructure:"errorColor"`
+	PrimaryColor string        `json:"primaryColor" mapstructure:"primaryColor"`
+	AccentColor  string        `json:"accentColor" mapstructure:"accentColor"`
+	ErrorColor   string        `json:"errorColor" mapstructure:"errorColor"`
+	BranchColors []BranchColor `json:"branchColors,omitempty" mapstructure:"branchColors"` // Empty uses DefaultBranchColors
+}
+
+// BranchColor colors the branches matching a glob pattern such as "feature/*"
+type BranchColor struct {
+	Pattern string `json:"pattern" mapstructure:"pattern"`
+	Color   string `json:"color" mapstructure:"color"`
+}
+
+// DetachedBranch is the branch git reports for a detached HEAD
+const DetachedBranch = "HEAD"
+
+// DefaultBranchColors color branches by the usual naming conventions
+var DefaultBranchColors = []BranchColor{
+	{Pattern: "main", Color: "#32CD32"},
+	{Pattern: "master", Color: "#32CD32"},
+	{Pattern: "feature/*", Color: "#5F87FF"},
+	{Pattern: "feat/*", Color: "#5F87FF"},
+	{Pattern: "fix/*", Color: "#FF8C00"},
+	{Pattern: "bugfix/*", Color: "#FF8C00"},
+	{Pattern: "hotfix/*", Color: "#FF8C00"},
+	{Pattern: DetachedBranch, Color: "#FF6347"},
+}
+
+// BranchColor returns the color of the first branch pattern matching branch
+// (see MatchBranch), or "" if none does. Patterns are checked in order.
+func (t ThemeConfig) BranchColor(branch string) string {
+	colors := t.BranchColors
+	if len(colors) == 0 {
+		colors = DefaultBranchColors
+	}
+	for _, c := range colors {
+		if MatchBranch(c.Pattern, branch) {
+			return c.Color
+		}
+	}
+	return ""
+}
+
+// MatchBranch reports whether branch matches a glob pattern. A pattern ending
+// in "/*" also matches nested branches like feature/api/login.
+func MatchBranch(pattern, branch string) bool {
+	if ok, _ := path.Match(pattern, branch); ok {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "*")
+	return ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(branch, prefix)
 }
 
 // DisplayConfig holds display preferences
 type DisplayConfig struct {
-	ShowHiddenDirs bool   `json:"showHiddenDirs" mapstructure:"showHiddenDirs"`
-	SortBy         string `json:"sortBy" mapstructure:"sortBy"` // "name" or "lastModified"
+	ShowHiddenDirs bool     `json:"showHiddenDirs" mapstructure:"showHiddenDirs"`
+	SortBy         string   `json:"sortBy" mapstructure:"sortBy"`                 // "name", "lastModified", "language" or "frecency"
+	Columns        []string `json:"columns" mapstructure:"columns"`               // Project list columns, e.g. "name:30"; empty uses the default layout
+	Density        string   `json:"density" mapstructure:"density"`               // "compact" (one line per project) or "detailed"
+	CurrentProject string   `json:"currentProject" mapstructure:"currentProject"` // What launching inside a project does: select, menu or off
+	Accessible     bool     `json:"accessible" mapstructure:"accessible"`         // Plain sequential prompts instead of the full-screen TUI
+	ReducedMotion  bool     `json:"reducedMotion" mapstructure:"reducedMotion"`   // No blinking cursors or ticking timers, and fewer redraws
+	MaxFPS         int      `json:"maxFPS" mapstructure:"maxFPS"`                 // Most redraws a second; 0 for the default
+	MaxDepth       int      `json:"maxDepth" mapstructure:"maxDepth"`             // Levels of folders scanned for projects and groups; 0 for the default
+}
+
+// DefaultMaxDepth scans the top level and one level of group children
+const DefaultMaxDepth = 2
+
+// Depth returns how many levels below each root are scanned for projects
+func (d DisplayConfig) Depth() int {
+	if d.MaxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return d.MaxDepth
+}
+
+// Redraw rates: bubbletea's default, and the cap under reducedMotion
+const (
+	DefaultFPS       = 60
+	ReducedMotionFPS = 10
+)
+
+// FPS returns how many times a second the TUI may redraw at most
+func (d DisplayConfig) FPS() int {
+	switch {
+	case d.MaxFPS > 0:
+		return d.MaxFPS
+	case d.ReducedMotion:
+		return ReducedMotionFPS
+	}
+	return DefaultFPS
+}
+
+// List densities
+const (
+	DensityCompact  = "compact"
+	DensityDetailed = "detailed"
+)
+
+// What launching the TUI from inside a project does, for CurrentProject
+const (
+	CurrentProjectSelect = "select" // Select the project in the list
+	CurrentProjectMenu   = "menu"   // Open the project's action menu
+	CurrentProjectOff    = "off"    // Start at the top of the list
+)
+
+// HasColumn reports whether the project list shows the named column
+func (d DisplayConfig) HasColumn(name string) bool {
+	for _, col := range d.Columns {
+		colName, _, _ := strings.Cut(col, ":")
+		if strings.EqualFold(strings.TrimSpace(colName), name) {
+			return true
+		}
+	}
+	return false
 }
 
 // ActionsConfig holds action-related settings
 type ActionsConfig struct {
-	EnableGitOperations bool `json:"enableGitOperations" mapstructure:"enableGitOperations"`
-	EnableTestRunner    bool `json:"enableTestRunner" mapstructure:"enableTestRunner"`
+	EnableGitOperations bool                      `json:"enableGitOperations" mapstructure:"enableGitOperations"`
+	EnableTestRunner    bool                      `json:"enableTestRunner" mapstructure:"enableTestRunner"`
+	Parsers             map[string]string         `json:"parsers" mapstructure:"parsers"`                     // Action ID -> output parser
+	UseShell            bool                      `json:"useShell" mapstructure:"useShell"`                   // Run command actions with shell -c
+	ProtectedBranches   []string                  `json:"protectedBranches" mapstructure:"protectedBranches"` // Branch patterns commands must confirm before modifying
+	Menu                map[string]MenuOverride   `json:"menu" mapstructure:"menu"`                           // Action ID -> place in the action menu
+	Disabled            []string                  `json:"disabled" mapstructure:"disabled"`                   // Action IDs removed from the menu and refused by proj run
+	Projects            map[string]ProjectActions `json:"projects" mapstructure:"projects"`                   // Project name -> overrides of Disabled
+}
+
+// ProjectActions adjusts the disabled actions for a single project
+type ProjectActions struct {
+	Disabled []string `json:"disabled" mapstructure:"disabled"` // Disabled in this project too
+	Enabled  []string `json:"enabled" mapstructure:"enabled"`   // Allowed in this project despite Disabled
+}
+
+// MenuOverride moves or hides an action in the action menu
+type MenuOverride struct {
+	Position int  `json:"position" mapstructure:"position"` // 1-based place within its menu; 0 keeps the default
+	Hidden   bool `json:"hidden" mapstructure:"hidden"`
+}
+
+// DefaultProtectedBranches are the branches guarded unless actions.protectedBranches is set
+var DefaultProtectedBranches = []string{"main", "master", "release/*"}
+
+// IsProtected reports whether branch matches one of the protected branch patterns
+func (c ActionsConfig) IsProtected(branch string) bool {
+	for _, pattern := range c.ProtectedBranches {
+		if MatchBranch(pattern, branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParserFor returns the output parser declared for an action, or "".
+// Action IDs are matched case-insensitively.
+func (c ActionsConfig) ParserFor(actionID string) string {
+	parser, _ := LookupFold(c.Parsers, actionID)
+	return parser
+}
+
+// MenuFor returns the menu override declared for an action. Action IDs are
+// matched case-insensitively.
+func (c ActionsConfig) MenuFor(actionID string) (MenuOverride, bool) {
+	return LookupFold(c.Menu, actionID)
+}
+
+// IsDisabled reports whether an action is disabled for a project, either
+// globally or by the project's overrides, which take precedence. Project
+// names are matched case-insensitively.
+func (c ActionsConfig) IsDisabled(projectName, actionID string) bool {
+	disabled := containsFold(c.Disabled, actionID)
+	if overrides, ok := LookupFold(c.Projects, projectName); ok {
+		if containsFold(overrides.Enabled, actionID) {
+			return false
+		}
+		disabled = disabled || containsFold(overrides.Disabled, actionID)
+	}
+	return disabled
+}
+
+// LookupFold returns the value of key in m, falling back to a key that
+// differs only in case. Config maps need this because viper lowercases map
+// keys, so "MyProject" is read back as "myproject".
+func LookupFold[V any](m map[string]V, key string) (V, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// HooksConfig holds shell commands run at points in proj's lifecycle
+type HooksConfig struct {
+	PreAction string                  `json:"preAction" mapstructure:"preAction"` // Before an action runs; failing cancels it
+	PostOpen  string                  `json:"postOpen" mapstructure:"postOpen"`   // After a project is opened
+	PostScan  string                  `json:"postScan" mapstructure:"postScan"`   // After projects are scanned
+	Projects  map[string]ProjectHooks `json:"projects" mapstructure:"projects"`   // Project name -> hooks run after the global ones
+}
+
+// ProjectHooks holds the hooks of a single project
+type ProjectHooks struct {
+	PreAction string `json:"preAction" mapstructure:"preAction"`
+	PostOpen  string `json:"postOpen" mapstructure:"postOpen"`
+}
+
+// Snippet is a reusable command from the snippet library. {name}, {path},
+// {language} and {branch} in Command are replaced with the project's values.
+type Snippet struct {
+	Name      string   `json:"name" mapstructure:"name"`
+	Command   string   `json:"command" mapstructure:"command"`
+	Desc      string   `json:"desc" mapstructure:"desc"`
+	Languages []string `json:"languages" mapstructure:"languages"` // Only offered for these languages; empty means all
+}
+
+// AppliesTo reports whether the snippet is offered for a language
+func (s Snippet) AppliesTo(language string) bool {
+	if len(s.Languages) == 0 {
+		return true
+	}
+	for _, l := range s.Languages {
+		if strings.EqualFold(l, language) {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkConfig holds settings for commands run across many projects (proj each)
+type BulkConfig struct {
+	Concurrency int    `json:"concurrency" mapstructure:"concurrency"` // Projects at a time; 0 means one per CPU
+	Timeout     string `json:"timeout" mapstructure:"timeout"`         // Per project, e.g. "5m"; empty means no limit
+}
+
+// TimeoutDuration parses Timeout, which is empty or a Go duration
+func (b BulkConfig) TimeoutDuration() (time.Duration, error) {
+	if b.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(b.Timeout)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid bulk.timeout %q (use e.g. 90s or 5m)", b.Timeout)
+	}
+	return d, nil
+}
+
+// CleanConfig adds to the build artifacts the clean action removes
+type CleanConfig struct {
+	Patterns []string            `json:"patterns" mapstructure:"patterns"` // Glob patterns relative to every project
+	Projects map[string][]string `json:"projects" mapstructure:"projects"` // Project name -> patterns for that project only
+}
+
+// PatternsFor returns the artifact patterns configured for a project.
+// Project names are matched case-insensitively.
+func (c CleanConfig) PatternsFor(projectName string) []string {
+	patterns := append([]string(nil), c.Patterns...)
+	extra, _ := LookupFold(c.Projects, projectName)
+	return append(patterns, extra...)
+}
+
+// IssuesConfig says where the issues named in branch names are tracked
+type IssuesConfig struct {
+	URL      string            `json:"url" mapstructure:"url"`           // Issue page with {key} for the key, e.g. https://acme.atlassian.net/browse/{key}
+	Projects map[string]string `json:"projects" mapstructure:"projects"` // Project name -> URL for that project only
+}
+
+// URLFor returns the issue URL template configured for a project.
+// Project names are matched case-insensitively.
+func (c IssuesConfig) URLFor(projectName string) string {
+	if url, ok := LookupFold(c.Projects, projectName); ok {
+		return url
+	}
+	return c.URL
+}
+
+// BranchesConfig holds how New Branch from Template names branches
+type BranchesConfig struct {
+	Pattern string   `json:"pattern" mapstructure:"pattern"` // Branch name with {type}, {ticket} and {slug}
+	Types   []string `json:"types" mapstructure:"types"`     // Kinds of change to pick from, the first by default
+}
+
+// Defaults for BranchesConfig
+const DefaultBranchPattern = "{type}/{ticket}-{slug}"
+
+var DefaultBranchTypes = []string{"feature", "fix", "chore"}
+
+// CommitConfig holds settings for the Commit action
+type CommitConfig struct {
+	Template  string   `json:"template" mapstructure:"template"`   // Message body to start from, unless the project has a .gitmessage
+	Types     []string `json:"types" mapstructure:"types"`         // Conventional commit types to choose from
+	CoAuthors []string `json:"coAuthors" mapstructure:"coAuthors"` // "Name <email>" offered as co-authors besides the repository's authors
+	SignOff   bool     `json:"signOff" mapstructure:"signOff"`     // Add a Signed-off-by trailer by default
+}
+
+// DefaultCommitTypes are the Conventional Commits types offered unless commit.types is set
+var DefaultCommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore"}
+
+// DevServerConfig says which command runs a project's dev server
+type DevServerConfig struct {
+	Scripts  []string          `json:"scripts" mapstructure:"scripts"`   // Names of detected scripts that run a dev server, in order of preference
+	Projects map[string]string `json:"projects" mapstructure:"projects"` // Project name -> command for that project only
+}
+
+// DefaultDevServerScripts are the script names looked for unless devServer.scripts is set
+var DefaultDevServerScripts = []string{"dev", "serve", "start", "server"}
+
+// CommandFor returns the dev server command configured for a project, or ""
+// to use the first of its scripts named in Scripts.
+// Project names are matched case-insensitively.
+func (c DevServerConfig) CommandFor(projectName string) string {
+	command, _ := LookupFold(c.Projects, projectName)
+	return command
+}
+
+// BackupConfig holds settings for the backup action
+type BackupConfig struct {
+	Dir string `json:"dir" mapstructure:"dir"` // Where bundles and archives are written
+}
+
+// AuditConfig holds settings for the log of the commands proj runs
+type AuditConfig struct {
+	Enabled bool   `json:"enabled" mapstructure:"enabled"`
+	Path    string `json:"path" mapstructure:"path"` // Log file; empty for audit.log in the config directory
+}
+
+// ContainerConfig holds settings for running actions inside containers
+type ContainerConfig struct {
+	Runtime      string            `json:"runtime" mapstructure:"runtime"`           // docker, podman or nerdctl; "" to use whichever is installed
+	StartCommand string            `json:"startCommand" mapstructure:"startCommand"` // Starts the daemon when it isn't running; "" to suggest one
+	Languages    map[string]string `json:"languages" mapstructure:"languages"`       // Language -> image
+	Projects     map[string]string `json:"projects" mapstructure:"projects"`         // Project name -> image
+}
+
+// WSLConfig holds settings used when running inside WSL
+type WSLConfig struct {
+	ScanWindowsMounts bool `json:"scanWindowsMounts" mapstructure:"scanWindowsMounts"` // Allow scanning /mnt/<drive> paths
 }
 
 // PluginsConfig holds plugin settings
@@ -57,6 +441,7 @@ func DefaultConfig() *Config {
 
 	return &Config{
 		ReposPath: filepath.Join(home, "code"),
+		Layout:    LayoutFlat,
 		Editor: EditorConfig{
 			Default: "code",
 			Aliases: map[string][]string{
@@ -86,16 +471,41 @@ func DefaultConfig() *Config {
 		Display: DisplayConfig{
 			ShowHiddenDirs: false,
 			SortBy:         "lastModified",
+			Density:        DensityCompact,
+			CurrentProject: CurrentProjectSelect,
+			MaxDepth:       DefaultMaxDepth,
 		},
 		ExcludePatterns: []string{".git", "node_modules", ".DS_Store", "__pycache__", "vendor"},
+		MaxProjects:     DefaultMaxProjects,
 		Actions: ActionsConfig{
 			EnableGitOperations: true,
 			EnableTestRunner:    true,
+			ProtectedBranches:   append([]string(nil), DefaultProtectedBranches...),
 		},
 		Plugins: PluginsConfig{
 			Enabled: []string{},
 			Config:  make(map[string]interface{}),
 		},
+		Backup: BackupConfig{
+			Dir: "~/Backups/proj",
+		},
+		Container: ContainerConfig{
+			Runtime:   "",
+			Languages: map[string]string{},
+			Projects:  map[string]string{},
+		},
+		Branches: BranchesConfig{
+			Pattern: DefaultBranchPattern,
+			Types:   append([]string(nil), DefaultBranchTypes...),
+		},
+		Commit: CommitConfig{
+			Types: append([]string(nil), DefaultCommitTypes...),
+		},
+		DevServer: DevServerConfig{
+			Scripts: append([]string(nil), DefaultDevServerScripts...),
+		},
+		OnOpen:      OnOpenEditorOnly,
+		AfterCreate: AfterCreateMenu,
 	}
 }
 
@@ -183,6 +593,7 @@ func setDefaults() {
 	home, _ := os.UserHomeDir()
 
 	viper.SetDefault("reposPath", filepath.Join(home, "code"))
+	viper.SetDefault("layout", LayoutFlat)
 	viper.SetDefault("editor.default", "code")
 	viper.SetDefault("shell", "/bin/bash")
 	viper.SetDefault("theme.primaryColor", "#00CED1")
@@ -190,9 +601,28 @@ func setDefaults() {
 	viper.SetDefault("theme.errorColor", "#FF6347")
 	viper.SetDefault("display.showHiddenDirs", false)
 	viper.SetDefault("display.sortBy", "lastModified")
+	viper.SetDefault("display.density", DensityCompact)
+	viper.SetDefault("display.currentProject", CurrentProjectSelect)
+	viper.SetDefault("display.accessible", false)
+	viper.SetDefault("display.reducedMotion", false)
+	viper.SetDefault("display.maxFPS", 0)
+	viper.SetDefault("display.maxDepth", DefaultMaxDepth)
 	viper.SetDefault("excludePatterns", []string{".git", "node_modules", ".DS_Store", "__pycache__", "vendor"})
+	viper.SetDefault("maxProjects", DefaultMaxProjects)
 	viper.SetDefault("actions.enableGitOperations", true)
 	viper.SetDefault("actions.enableTestRunner", true)
+	viper.SetDefault("actions.protectedBranches", DefaultProtectedBranches)
+	viper.SetDefault("backup.dir", "~/Backups/proj")
+	viper.SetDefault("audit.enabled", false)
+	viper.SetDefault("audit.path", "")
+	viper.SetDefault("wsl.scanWindowsMounts", false)
+	viper.SetDefault("container.runtime", "")
+	viper.SetDefault("branches.pattern", DefaultBranchPattern)
+	viper.SetDefault("branches.types", DefaultBranchTypes)
+	viper.SetDefault("commit.types", DefaultCommitTypes)
+	viper.SetDefault("devServer.scripts", DefaultDevServerScripts)
+	viper.SetDefault("onOpen", OnOpenEditorOnly)
+	viper.SetDefault("afterCreate", AfterCreateMenu)
 }
 
 // ExpandPath expands ~ to the user's home directory
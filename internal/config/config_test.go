@@ -162,3 +162,140 @@ func TestConfigPath(t *testing.T) {
 		t.Error("ConfigPath should have .json extension")
 	}
 }
+
+func TestLookupFold(t *testing.T) {
+	m := map[string]int{"myproject": 1, "Exact": 2, "exact": 3}
+
+	if got, ok := LookupFold(m, "MyProject"); !ok || got != 1 {
+		t.Errorf("LookupFold(MyProject) = %d, %v, want 1", got, ok)
+	}
+	if got, _ := LookupFold(m, "Exact"); got != 2 {
+		t.Errorf("LookupFold(Exact) = %d, want the exact key's 2", got)
+	}
+	if got, ok := LookupFold(m, "other"); ok || got != 0 {
+		t.Errorf("LookupFold(other) = %d, %v, want nothing", got, ok)
+	}
+}
+
+func TestParserFor(t *testing.T) {
+	cfg := ActionsConfig{Parsers: map[string]string{"npm-test": "jest-json", "make-test": "junit-xml"}}
+
+	if got := cfg.ParserFor("npm-test"); got != "jest-json" {
+		t.Errorf("ParserFor(npm-test) = %q, want jest-json", got)
+	}
+	if got := cfg.ParserFor("Make-Test"); got != "junit-xml" {
+		t.Errorf("ParserFor(Make-Test) = %q, want junit-xml", got)
+	}
+	if got := cfg.ParserFor("clean"); got != "" {
+		t.Errorf("ParserFor(clean) = %q, want empty", got)
+	}
+}
+
+func TestMenuFor(t *testing.T) {
+	cfg := ActionsConfig{Menu: map[string]MenuOverride{"git-pull": {Position: 1}, "backup": {Hidden: true}}}
+
+	if got, ok := cfg.MenuFor("Git-Pull"); !ok || got.Position != 1 {
+		t.Errorf("MenuFor(Git-Pull) = %+v, %v", got, ok)
+	}
+	if _, ok := cfg.MenuFor("clean"); ok {
+		t.Error("Expected no override for clean")
+	}
+}
+
+func TestIsDisabled(t *testing.T) {
+	cfg := ActionsConfig{
+		Disabled: []string{"clean", "git-pull"},
+		Projects: map[string]ProjectActions{
+			"scratch": {Enabled: []string{"clean"}},
+			"prod":    {Disabled: []string{"backup"}},
+		},
+	}
+
+	tests := []struct {
+		project, action string
+		want            bool
+	}{
+		{"web", "clean", true},
+		{"web", "git-pull", true},
+		{"web", "backup", false},
+		{"Scratch", "clean", false},
+		{"scratch", "git-pull", true},
+		{"prod", "backup", true},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsDisabled(tt.project, tt.action); got != tt.want {
+			t.Errorf("IsDisabled(%s, %s) = %v, want %v", tt.project, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestHasColumn(t *testing.T) {
+	d := DisplayConfig{Columns: []string{"name:30", "lastcommit", " size "}}
+
+	for _, name := range []string{"name", "lastCommit", "size"} {
+		if !d.HasColumn(name) {
+			t.Errorf("Expected HasColumn(%q) to be true", name)
+		}
+	}
+	if d.HasColumn("branch") {
+		t.Error("Expected HasColumn(branch) to be false")
+	}
+}
+
+func TestFPS(t *testing.T) {
+	tests := []struct {
+		display DisplayConfig
+		want    int
+	}{
+		{DisplayConfig{}, DefaultFPS},
+		{DisplayConfig{ReducedMotion: true}, ReducedMotionFPS},
+		{DisplayConfig{ReducedMotion: true, MaxFPS: 4}, 4},
+		{DisplayConfig{MaxFPS: 30}, 30},
+	}
+	for _, tt := range tests {
+		if got := tt.display.FPS(); got != tt.want {
+			t.Errorf("FPS() of %+v = %d, want %d", tt.display, got, tt.want)
+		}
+	}
+}
+
+func TestBranchColor(t *testing.T) {
+	theme := ThemeConfig{}
+	tests := map[string]string{
+		"main":              "#32CD32",
+		"feature/login":     "#5F87FF",
+		"feature/api/login": "#5F87FF",
+		"fix/crash":         "#FF8C00",
+		DetachedBranch:      "#FF6347",
+		"develop":           "",
+	}
+	for branch, want := range tests {
+		if got := theme.BranchColor(branch); got != want {
+			t.Errorf("BranchColor(%q) = %q, want %q", branch, got, want)
+		}
+	}
+
+	// Configured patterns replace the defaults
+	theme.BranchColors = []BranchColor{{Pattern: "release-*", Color: "#FF00FF"}}
+	if got := theme.BranchColor("release-1.2"); got != "#FF00FF" {
+		t.Errorf("BranchColor(release-1.2) = %q, want #FF00FF", got)
+	}
+	if got := theme.BranchColor("main"); got != "" {
+		t.Errorf("BranchColor(main) with custom patterns = %q, want empty", got)
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	actions := DefaultConfig().Actions
+
+	for _, branch := range []string{"main", "master", "release/1.2", "release/2026/q1"} {
+		if !actions.IsProtected(branch) {
+			t.Errorf("Expected %s to be protected", branch)
+		}
+	}
+	for _, branch := range []string{"feature/login", "mainline", ""} {
+		if actions.IsProtected(branch) {
+			t.Errorf("Expected %s not to be protected", branch)
+		}
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
+
+	"github.com/s33g/proj/internal/sys"
 )
 
 // Action represents a Docker action that can be executed
@@ -24,6 +27,12 @@ var AvailableActions = []Action{
 		Description: "Build Docker image from Dockerfile",
 		RequireFile: true,
 	},
+	{
+		ID:          "docker-lint",
+		Name:        "🔍 Lint Dockerfile",
+		Description: "Check the Dockerfiles with hadolint",
+		RequireFile: true,
+	},
 	{
 		ID:          "docker-run",
 		Name:        "▶️  Run Container",
@@ -48,6 +57,12 @@ var AvailableActions = []Action{
 		Description: "Stream container logs",
 		RequireFile: false,
 	},
+	{
+		ID:          "docker-stats",
+		Name:        "📊 Docker Stats",
+		Description: "CPU and memory of the project's running containers",
+		RequireFile: false,
+	},
 	{
 		ID:          "compose-up",
 		Name:        "🚀 Compose Up",
@@ -93,55 +108,130 @@ type ExecuteResult struct {
 	Command string
 }
 
-// Execute executes a Docker action for the given project
-func Execute(actionID, projectPath, projectName string, info *DockerInfo) ExecuteResult {
+// Execute executes a Docker action for the given project with a container
+// runtime that accepts docker's commands, such as docker or podman
+func Execute(runtime, actionID, projectPath, projectName string, info *DockerInfo) ExecuteResult {
 	switch actionID {
 	case "docker-build":
-		return dockerBuild(projectPath, projectName, info)
+		return Build(runtime, projectPath, projectName, info, BuildOptions{})
+	case "docker-lint":
+		return dockerLint(projectPath, info)
 	case "docker-run":
-		return dockerRun(projectPath, projectName, false)
+		return dockerRun(runtime, projectPath, projectName, false)
 	case "docker-run-detached":
-		return dockerRun(projectPath, projectName, true)
+		return dockerRun(runtime, projectPath, projectName, true)
 	case "docker-ps":
-		return dockerPS(projectPath)
+		return dockerPS(runtime, projectPath)
 	case "docker-logs":
-		return dockerLogs(projectPath, projectName)
+		return dockerLogs(runtime, projectPath, projectName)
+	case "docker-stats":
+		return dockerStats(runtime, projectPath, projectName)
 	case "compose-up":
-		return composeUp(projectPath, info, false)
+		return composeUp(runtime, projectPath, info, false)
 	case "compose-up-detached":
-		return composeUp(projectPath, info, true)
+		return composeUp(runtime, projectPath, info, true)
 	case "compose-down":
-		return composeDown(projectPath, info)
+		return composeDown(runtime, projectPath, info)
 	case "compose-build":
-		return composeBuild(projectPath, info)
+		return composeBuild(runtime, projectPath, info)
 	case "compose-logs":
-		return composeLogs(projectPath, info)
+		return composeLogs(runtime, projectPath, info)
 	case "compose-ps":
-		return composePS(projectPath, info)
-	default:
-		return ExecuteResult{
-			Success: false,
-			Message: "Unknown Docker action: " + actionID,
-		}
+		return composePS(runtime, projectPath, info)
+	}
+
+	// Actions on a profile or a single service
+	if args := Args(actionID, projectName, info); args != nil {
+		return composeRun(runtime, projectPath, args)
+	}
+	return ExecuteResult{
+		Success: false,
+		Message: "Unknown Docker action: " + actionID,
 	}
 }
 
-// dockerBuild builds a Docker image
-func dockerBuild(projectPath, projectName string, info *DockerInfo) ExecuteResult {
-	dockerfile := GetPrimaryDockerfile(info.Dockerfiles)
+// Args returns the docker arguments an action runs, or nil for unknown actions
+func Args(actionID, projectName string, info *DockerInfo) []string {
 	imageName := sanitizeImageName(projectName)
 
-	args := []string{"build", "-t", imageName}
-	if dockerfile != "Dockerfile" {
-		args = append(args, "-f", dockerfile)
+	switch actionID {
+	case "docker-build":
+		return BuildArgs(projectName, info, BuildOptions{})
+	case "docker-run":
+		return []string{"run", "-it", "--rm", "--name", imageName + "-container", imageName}
+	case "docker-run-detached":
+		return []string{"run", "-d", "--name", imageName + "-container", imageName}
+	case "docker-ps":
+		return []string{"ps", "--format", "table {{.ID}}\t{{.Image}}\t{{.Status}}\t{{.Names}}"}
+	case "docker-logs":
+		return []string{"logs", "--tail", "50", imageName + "-container"}
+	case "docker-stats":
+		return []string{"stats", "--no-stream", "--format", statsFormat}
 	}
-	args = append(args, ".")
 
-	cmd := exec.Command("docker", args...)
+	if info == nil {
+		return nil
+	}
+	composeFile := GetPrimaryComposeFile(info.ComposeFiles)
+	switch actionID {
+	case "compose-up":
+		return []string{"compose", "-f", composeFile, "up"}
+	case "compose-up-detached":
+		return []string{"compose", "-f", composeFile, "up", "-d"}
+	case "compose-down":
+		return []string{"compose", "-f", composeFile, "down"}
+	case "compose-build":
+		return []string{"compose", "-f", composeFile, "build"}
+	case "compose-logs":
+		return []string{"compose", "-f", composeFile, "logs", "--tail", "50"}
+	case "compose-ps":
+		return []string{"compose", "-f", composeFile, "ps"}
+	}
+	if profile, ok := strings.CutPrefix(actionID, "compose-up-profile-"); ok {
+		return []string{"compose", "-f", composeFile, "--profile", profile, "up", "-d"}
+	}
+	if service, ok := strings.CutPrefix(actionID, "compose-restart-"); ok {
+		return []string{"compose", "-f", composeFile, "restart", service}
+	}
+	if service, ok := strings.CutPrefix(actionID, "compose-logs-"); ok {
+		return []string{"compose", "-f", composeFile, "logs", "--tail", "50", service}
+	}
+	return nil
+}
+
+// BuildArgs returns the docker arguments that build a project's image with
+// the given target and build args
+func BuildArgs(projectName string, info *DockerInfo, opts BuildOptions) []string {
+	args := []string{"build", "-t", sanitizeImageName(projectName)}
+	if info != nil {
+		if dockerfile := GetPrimaryDockerfile(info.Dockerfiles); dockerfile != "Dockerfile" {
+			args = append(args, "-f", dockerfile)
+		}
+	}
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+	names := make([]string, 0, len(opts.Args))
+	for name := range opts.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--build-arg", name+"="+opts.Args[name])
+	}
+	return append(args, ".")
+}
+
+// Build builds a project's image
+func Build(runtime, projectPath, projectName string, info *DockerInfo, opts BuildOptions) ExecuteResult {
+	imageName := sanitizeImageName(projectName)
+	args := BuildArgs(projectName, info, opts)
+
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if err != nil {
 		return ExecuteResult{
@@ -158,22 +248,54 @@ func dockerBuild(projectPath, projectName string, info *DockerInfo) ExecuteResul
 	}
 }
 
-// dockerRun runs a Docker container
-func dockerRun(projectPath, projectName string, detached bool) ExecuteResult {
-	imageName := sanitizeImageName(projectName)
+// LintArgs returns the hadolint arguments that check a project's Dockerfiles
+func LintArgs(info *DockerInfo) []string {
+	return append([]string{"hadolint"}, info.Dockerfiles...)
+}
 
-	args := []string{"run"}
+// dockerLint checks the Dockerfiles with hadolint, if it's installed
+func dockerLint(projectPath string, info *DockerInfo) ExecuteResult {
+	if _, err := sys.LookPath("hadolint"); err != nil {
+		return ExecuteResult{
+			Success: false,
+			Message: "hadolint is not installed. Install it from https://github.com/hadolint/hadolint (e.g. brew install hadolint).",
+		}
+	}
+	args := LintArgs(info)
+
+	cmd := sys.Command(args[0], args[1:]...)
+	cmd.Dir = projectPath
+
+	output, err := runCommand(cmd)
+	cmdStr := strings.Join(args, " ")
+
+	if err != nil {
+		return ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("hadolint found problems:\n%s", output),
+			Command: cmdStr,
+		}
+	}
+
+	return ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("No problems found in %s", strings.Join(info.Dockerfiles, ", ")),
+		Command: cmdStr,
+	}
+}
+
+// dockerRun runs a Docker container
+func dockerRun(runtime, projectPath, projectName string, detached bool) ExecuteResult {
+	actionID := "docker-run"
 	if detached {
-		args = append(args, "-d")
-	} else {
-		args = append(args, "-it", "--rm")
+		actionID = "docker-run-detached"
 	}
-	args = append(args, "--name", imageName+"-container", imageName)
+	args := Args(actionID, projectName, nil)
 
-	cmd := exec.Command("docker", args...)
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if detached {
 		output, err := runCommand(cmd)
@@ -200,12 +322,12 @@ func dockerRun(projectPath, projectName string, detached bool) ExecuteResult {
 }
 
 // dockerPS lists running containers
-func dockerPS(projectPath string) ExecuteResult {
-	cmd := exec.Command("docker", "ps", "--format", "table {{.ID}}\t{{.Image}}\t{{.Status}}\t{{.Names}}")
+func dockerPS(runtime, projectPath string) ExecuteResult {
+	cmd := sys.Command(runtime, Args("docker-ps", "", nil)...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := "docker ps"
+	cmdStr := runtime + " ps"
 
 	if err != nil {
 		return ExecuteResult{
@@ -231,14 +353,14 @@ func dockerPS(projectPath string) ExecuteResult {
 }
 
 // dockerLogs shows container logs
-func dockerLogs(projectPath, projectName string) ExecuteResult {
+func dockerLogs(runtime, projectPath, projectName string) ExecuteResult {
 	containerName := sanitizeImageName(projectName) + "-container"
 
-	cmd := exec.Command("docker", "logs", "--tail", "50", containerName)
+	cmd := sys.Command(runtime, Args("docker-logs", projectName, nil)...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker logs %s", containerName)
+	cmdStr := fmt.Sprintf("%s logs %s", runtime, containerName)
 
 	if err != nil {
 		return ExecuteResult{
@@ -256,19 +378,18 @@ func dockerLogs(projectPath, projectName string) ExecuteResult {
 }
 
 // composeUp starts services with docker compose
-func composeUp(projectPath string, info *DockerInfo, detached bool) ExecuteResult {
-	composeFile := GetPrimaryComposeFile(info.ComposeFiles)
-
-	args := []string{"compose", "-f", composeFile, "up"}
+func composeUp(runtime, projectPath string, info *DockerInfo, detached bool) ExecuteResult {
+	actionID := "compose-up"
 	if detached {
-		args = append(args, "-d")
+		actionID = "compose-up-detached"
 	}
+	args := Args(actionID, "", info)
 
-	cmd := exec.Command("docker", args...)
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if err != nil {
 		return ExecuteResult{
@@ -286,16 +407,14 @@ func composeUp(projectPath string, info *DockerInfo, detached bool) ExecuteResul
 }
 
 // composeDown stops and removes services
-func composeDown(projectPath string, info *DockerInfo) ExecuteResult {
-	composeFile := GetPrimaryComposeFile(info.ComposeFiles)
+func composeDown(runtime, projectPath string, info *DockerInfo) ExecuteResult {
+	args := Args("compose-down", "", info)
 
-	args := []string{"compose", "-f", composeFile, "down"}
-
-	cmd := exec.Command("docker", args...)
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if err != nil {
 		return ExecuteResult{
@@ -313,16 +432,14 @@ func composeDown(projectPath string, info *DockerInfo) ExecuteResult {
 }
 
 // composeBuild builds all images
-func composeBuild(projectPath string, info *DockerInfo) ExecuteResult {
-	composeFile := GetPrimaryComposeFile(info.ComposeFiles)
-
-	args := []string{"compose", "-f", composeFile, "build"}
+func composeBuild(runtime, projectPath string, info *DockerInfo) ExecuteResult {
+	args := Args("compose-build", "", info)
 
-	cmd := exec.Command("docker", args...)
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if err != nil {
 		return ExecuteResult{
@@ -340,16 +457,14 @@ func composeBuild(projectPath string, info *DockerInfo) ExecuteResult {
 }
 
 // composeLogs shows logs for all services
-func composeLogs(projectPath string, info *DockerInfo) ExecuteResult {
-	composeFile := GetPrimaryComposeFile(info.ComposeFiles)
-
-	args := []string{"compose", "-f", composeFile, "logs", "--tail", "50"}
+func composeLogs(runtime, projectPath string, info *DockerInfo) ExecuteResult {
+	args := Args("compose-logs", "", info)
 
-	cmd := exec.Command("docker", args...)
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if err != nil {
 		return ExecuteResult{
@@ -367,16 +482,14 @@ func composeLogs(projectPath string, info *DockerInfo) ExecuteResult {
 }
 
 // composePS lists services
-func composePS(projectPath string, info *DockerInfo) ExecuteResult {
-	composeFile := GetPrimaryComposeFile(info.ComposeFiles)
+func composePS(runtime, projectPath string, info *DockerInfo) ExecuteResult {
+	args := Args("compose-ps", "", info)
 
-	args := []string{"compose", "-f", composeFile, "ps"}
-
-	cmd := exec.Command("docker", args...)
+	cmd := sys.Command(runtime, args...)
 	cmd.Dir = projectPath
 
 	output, err := runCommand(cmd)
-	cmdStr := fmt.Sprintf("docker %s", strings.Join(args, " "))
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
 
 	if err != nil {
 		return ExecuteResult{
@@ -393,6 +506,33 @@ func composePS(projectPath string, info *DockerInfo) ExecuteResult {
 	}
 }
 
+// composeRun runs the compose command of an action on a profile or a
+// single service
+func composeRun(runtime, projectPath string, args []string) ExecuteResult {
+	cmd := sys.Command(runtime, args...)
+	cmd.Dir = projectPath
+
+	output, err := runCommand(cmd)
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
+
+	if err != nil {
+		return ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("%s failed:\n%s", cmdStr, output),
+			Command: cmdStr,
+		}
+	}
+
+	if strings.TrimSpace(output) == "" {
+		output = cmdStr + " completed"
+	}
+	return ExecuteResult{
+		Success: true,
+		Message: output,
+		Command: cmdStr,
+	}
+}
+
 // runCommand executes a command and returns combined stdout/stderr
 func runCommand(cmd *exec.Cmd) (string, error) {
 	var out bytes.Buffer
@@ -431,8 +571,50 @@ func GetActionsForProject(info *DockerInfo) []Action {
 		if action.RequireComp && !info.HasCompose {
 			continue
 		}
+		// Nothing to build when every service pulls its image
+		if action.ID == "compose-build" && len(info.Services) > 0 && !composeBuilds(info.Services) {
+			continue
+		}
 		available = append(available, action)
 	}
 
+	if !info.HasCompose {
+		return available
+	}
+	for _, profile := range info.Profiles {
+		available = append(available, Action{
+			ID:          "compose-up-profile-" + profile,
+			Name:        fmt.Sprintf("🧩 Compose Up (%s profile)", profile),
+			Description: fmt.Sprintf("Start the services, with those in the %s profile, in background", profile),
+			RequireComp: true,
+		})
+	}
+	for _, svc := range info.Services {
+		available = append(available,
+			Action{
+				ID:          "compose-restart-" + svc.Name,
+				Name:        "🔁 Restart " + svc.Name,
+				Description: "Restart the " + svc.Name + " service",
+				RequireComp: true,
+			},
+			Action{
+				ID:          "compose-logs-" + svc.Name,
+				Name:        "📜 Logs: " + svc.Name,
+				Description: "Show the end of the " + svc.Name + " service's logs",
+				RequireComp: true,
+			},
+		)
+	}
+
 	return available
 }
+
+// composeBuilds reports whether any of the services builds its image
+func composeBuilds(services []ComposeService) bool {
+	for _, svc := range services {
+		if svc.Build {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,10 @@
 package docker
 
 import (
-	"os"
-	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/s33g/proj/internal/sys"
 )
 
 func TestSanitizeImageName(t *testing.T) {
@@ -28,6 +28,31 @@ func TestSanitizeImageName(t *testing.T) {
 	}
 }
 
+func TestArgs(t *testing.T) {
+	info := &DockerInfo{Dockerfiles: []string{"Dockerfile.dev"}, ComposeFiles: []string{"compose.yaml"}}
+
+	tests := []struct {
+		actionID string
+		expected string
+	}{
+		{"docker-build", "build -t my-app -f Dockerfile.dev ."},
+		{"docker-run", "run -it --rm --name my-app-container my-app"},
+		{"docker-run-detached", "run -d --name my-app-container my-app"},
+		{"compose-up-detached", "compose -f compose.yaml up -d"},
+		{"compose-logs", "compose -f compose.yaml logs --tail 50"},
+		{"compose-logs-web", "compose -f compose.yaml logs --tail 50 web"},
+		{"compose-restart-db", "compose -f compose.yaml restart db"},
+		{"compose-up-profile-debug", "compose -f compose.yaml --profile debug up -d"},
+		{"unknown", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(Args(tt.actionID, "My App", info), " "); got != tt.expected {
+			t.Errorf("Args(%q) = %q, want %q", tt.actionID, got, tt.expected)
+		}
+	}
+}
+
 func TestGetActionsForProjectFiltersByCapabilities(t *testing.T) {
 	info := &DockerInfo{HasDockerfile: true, HasCompose: true}
 	actions := GetActionsForProject(info)
@@ -45,76 +70,50 @@ func TestGetActionsForProjectFiltersByCapabilities(t *testing.T) {
 	}
 }
 
+func TestGetActionsForProjectServices(t *testing.T) {
+	info := &DockerInfo{
+		HasCompose: true,
+		Services:   []ComposeService{{Name: "db", Image: "postgres"}},
+		Profiles:   []string{"debug"},
+	}
+	ids := make(map[string]bool)
+	for _, action := range GetActionsForProject(info) {
+		ids[action.ID] = true
+	}
+
+	for _, id := range []string{"compose-up-profile-debug", "compose-restart-db", "compose-logs-db"} {
+		if !ids[id] {
+			t.Errorf("expected action %s", id)
+		}
+	}
+	if ids["compose-build"] {
+		t.Error("expected no Compose Build when no service builds")
+	}
+}
+
 func TestDockerPSOutputHandling(t *testing.T) {
-	t.Run("reports running containers", func(t *testing.T) {
-		withFakeDocker(t, `#!/bin/sh
-if [ "$1" = "ps" ]; then
-  printf "CONTAINER ID\tIMAGE\tSTATUS\tNAMES\n123\timg\tUp\tname\n"
-  exit 0
-fi
-echo "unexpected" >&2
-exit 1
-`, func() {
-			result := dockerPS(t.TempDir())
-			if !result.Success {
-				t.Fatalf("expected success, got failure: %s", result.Message)
-			}
-			if !strings.Contains(result.Message, "123") {
-				t.Fatalf("expected container output, got %q", result.Message)
-			}
-		})
-	})
+	tests := []struct {
+		name    string
+		result  sys.Result
+		success bool
+		message string
+	}{
+		{"reports running containers", sys.Result{Stdout: "CONTAINER ID\tIMAGE\tSTATUS\tNAMES\n123\timg\tUp\tname\n"}, true, "123"},
+		{"handles empty list", sys.Result{Stdout: "CONTAINER ID\tIMAGE\tSTATUS\tNAMES\n"}, true, "No running containers"},
+		{"propagates failures", sys.Result{Stderr: "boom", ExitCode: 1}, false, "boom"},
+	}
 
-	t.Run("handles empty list", func(t *testing.T) {
-		withFakeDocker(t, `#!/bin/sh
-if [ "$1" = "ps" ]; then
-  printf "CONTAINER ID\tIMAGE\tSTATUS\tNAMES\n"
-  exit 0
-fi
-exit 1
-`, func() {
-			result := dockerPS(t.TempDir())
-			if !result.Success {
-				t.Fatalf("expected success for empty ps output, got failure: %s", result.Message)
-			}
-			if result.Message != "No running containers" {
-				t.Fatalf("expected empty ps message, got %q", result.Message)
-			}
-		})
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(sys.UseRunner(sys.NewFake().On("docker ps", tt.result)))
 
-	t.Run("propagates failures", func(t *testing.T) {
-		withFakeDocker(t, `#!/bin/sh
-echo "boom" >&2
-exit 1
-`, func() {
-			result := dockerPS(t.TempDir())
-			if result.Success {
-				t.Fatalf("expected failure, got success: %s", result.Message)
+			result := dockerPS("docker", t.TempDir())
+			if result.Success != tt.success {
+				t.Fatalf("Success = %v, want %v: %s", result.Success, tt.success, result.Message)
 			}
-			if !strings.Contains(result.Message, "boom") {
-				t.Fatalf("expected error output in message, got %q", result.Message)
+			if !strings.Contains(result.Message, tt.message) {
+				t.Fatalf("expected %q in message, got %q", tt.message, result.Message)
 			}
 		})
-	})
-}
-
-func withFakeDocker(t *testing.T, script string, fn func()) {
-	t.Helper()
-
-	dir := t.TempDir()
-	path := filepath.Join(dir, "docker")
-	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
-		t.Fatalf("failed to write fake docker: %v", err)
-	}
-
-	origPath := os.Getenv("PATH")
-	if err := os.Setenv("PATH", dir+string(os.PathListSeparator)+origPath); err != nil {
-		t.Fatalf("failed to set PATH: %v", err)
 	}
-	t.Cleanup(func() {
-		_ = os.Setenv("PATH", origPath)
-	})
-
-	fn()
 }
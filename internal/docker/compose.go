@@ -0,0 +1,278 @@
+package docker
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ComposeService is what proj reads of a service in the compose files
+type ComposeService struct {
+	Name        string
+	Image       string
+	Build       bool              // Whether the service builds its image
+	Environment map[string]string // Values as written, before interpolation
+	Ports       []PortMapping
+	Volumes     []VolumeMount
+	Profiles    []string // Profiles the service is only started in
+	File        string   // Compose file that defines the service (the last to give it an image)
+}
+
+// PortMapping is an entry of a service's ports: list
+type PortMapping struct {
+	Published int // Host port; 0 when Docker picks one
+	Target    int // Container port
+}
+
+// VolumeMount is an entry of a service's volumes: list
+type VolumeMount struct {
+	Source string // Named volume or host path; "" for an anonymous volume
+	Target string // Path in the container
+}
+
+// Bind reports whether the mount is of a host path rather than a volume
+func (v VolumeMount) Bind() bool {
+	return strings.HasPrefix(v.Source, ".") || strings.HasPrefix(v.Source, "/") || strings.HasPrefix(v.Source, "~")
+}
+
+// ComposeServices returns the services of a project's compose files, in the
+// order they're defined. A service defined in several files (such as an
+// override) has their settings merged.
+func ComposeServices(projectPath string, composeFiles []string) []ComposeService {
+	var services []ComposeService
+	index := make(map[string]int)
+	for _, name := range composeFiles {
+		for _, svc := range parseComposeFile(filepath.Join(projectPath, name)) {
+			i, ok := index[svc.Name]
+			if !ok {
+				index[svc.Name] = len(services)
+				svc.File = name
+				services = append(services, svc)
+				continue
+			}
+			merged := &services[i]
+			if svc.Image != "" {
+				merged.Image = svc.Image
+				merged.File = name
+			}
+			merged.Build = merged.Build || svc.Build
+			for k, v := range svc.Environment {
+				merged.Environment[k] = v
+			}
+			merged.Ports = append(merged.Ports, svc.Ports...)
+			merged.Volumes = append(merged.Volumes, svc.Volumes...)
+			if len(svc.Profiles) > 0 {
+				merged.Profiles = svc.Profiles
+			}
+		}
+	}
+	return services
+}
+
+// composeProfiles returns the profiles the services use, sorted
+func composeProfiles(services []ComposeService) []string {
+	seen := make(map[string]bool)
+	var profiles []string
+	for _, svc := range services {
+		for _, p := range svc.Profiles {
+			if !seen[p] {
+				seen[p] = true
+				profiles = append(profiles, p)
+			}
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// parseComposeFile reads the services of a compose file: their image,
+// whether they build, their environment (as a list of KEY=value or a map),
+// their ports and volumes (in the short "8080:80" and long target:/
+// published: syntax) and their profiles. It reads the YAML a line at a
+// time, as compose files are written, rather than in full.
+func parseComposeFile(path string) []ComposeService {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var services []ComposeService
+	inServices := false
+	serviceIndent, keyIndent := -1, -1 // Indentation of service names and of their keys
+	block := ""                        // Key of the current service whose list or map is being read
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if indent == 0 {
+			inServices = trimmed == "services:"
+			serviceIndent, keyIndent, block = -1, -1, ""
+			continue
+		}
+		if !inServices {
+			continue
+		}
+
+		if serviceIndent < 0 {
+			serviceIndent = indent
+		}
+		if indent <= serviceIndent {
+			name := strings.Trim(strings.TrimSuffix(trimmed, ":"), `"'`)
+			services = append(services, ComposeService{Name: name, Environment: make(map[string]string)})
+			keyIndent, block = -1, ""
+			continue
+		}
+		if len(services) == 0 {
+			continue
+		}
+		svc := &services[len(services)-1]
+
+		if keyIndent < 0 {
+			keyIndent = indent
+		}
+		if indent <= keyIndent && !(block != "" && strings.HasPrefix(trimmed, "-")) {
+			key, value, _ := strings.Cut(trimmed, ":")
+			value = strings.TrimSpace(value)
+			block = ""
+			switch key {
+			case "image":
+				svc.Image = unquote(value)
+			case "build":
+				svc.Build = true
+			case "environment", "ports", "volumes", "profiles":
+				if value == "" {
+					block = key
+				}
+				for _, item := range flowItems(value) {
+					svc.addItem(key, item)
+				}
+			}
+			continue
+		}
+
+		item, isItem := strings.CutPrefix(trimmed, "- ")
+		switch {
+		case block == "environment":
+			svc.addItem(block, item)
+		case (block == "ports" || block == "volumes") && isItem && strings.Contains(item, ": "):
+			// First key of an entry in the long syntax
+			if block == "ports" {
+				svc.Ports = append(svc.Ports, PortMapping{})
+			} else {
+				svc.Volumes = append(svc.Volumes, VolumeMount{})
+			}
+			svc.setLongKey(block, item)
+		case isItem:
+			svc.addItem(block, item)
+		case block == "ports" || block == "volumes":
+			svc.setLongKey(block, trimmed)
+		}
+	}
+	return services
+}
+
+// addItem adds an entry of the service's environment, ports, volumes or
+// profiles
+func (s *ComposeService) addItem(block, item string) {
+	switch block {
+	case "environment":
+		// - KEY=value in a list, KEY: value in a map
+		key, value, ok := strings.Cut(unquote(item), "=")
+		if k, v, isMap := strings.Cut(item, ":"); isMap && (!ok || len(k) < len(key)) {
+			key, value = k, unquote(strings.TrimSpace(v))
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			s.Environment[key] = value
+		}
+	case "ports":
+		// [ip:]host:container[/protocol], where either port may be a range
+		parts := strings.Split(unquote(item), ":")
+		port := PortMapping{Target: portNumber(parts[len(parts)-1])}
+		if len(parts) >= 2 {
+			port.Published = portNumber(parts[len(parts)-2])
+		}
+		s.Ports = append(s.Ports, port)
+	case "volumes":
+		// [source:]target[:mode]
+		parts := strings.Split(unquote(item), ":")
+		volume := VolumeMount{Target: parts[0]}
+		if len(parts) >= 2 {
+			volume = VolumeMount{Source: parts[0], Target: parts[1]}
+		}
+		s.Volumes = append(s.Volumes, volume)
+	case "profiles":
+		if profile := unquote(item); profile != "" {
+			s.Profiles = append(s.Profiles, profile)
+		}
+	}
+}
+
+// setLongKey sets a key of the last port or volume, given in the long syntax
+func (s *ComposeService) setLongKey(block, item string) {
+	key, value, _ := strings.Cut(item, ":")
+	key = strings.TrimSpace(key)
+	if block == "volumes" {
+		volume := &s.Volumes[len(s.Volumes)-1]
+		switch key {
+		case "source":
+			volume.Source = unquote(value)
+		case "target":
+			volume.Target = unquote(value)
+		}
+		return
+	}
+
+	port := &s.Ports[len(s.Ports)-1]
+	switch key {
+	case "target":
+		port.Target = portNumber(value)
+	case "published":
+		port.Published = portNumber(value)
+	}
+}
+
+// portNumber reads a port, taking the first of a range and dropping the
+// protocol; it returns 0 for anything else
+func portNumber(s string) int {
+	s, _, _ = strings.Cut(unquote(s), "/")
+	s, _, _ = strings.Cut(s, "-")
+	if port, err := strconv.Atoi(s); err == nil && port > 0 && port < 65536 {
+		return port
+	}
+	return 0
+}
+
+// flowItems returns the items of a one-line [a, b] list, or nil
+func flowItems(value string) []string {
+	inner, ok := strings.CutPrefix(value, "[")
+	if !ok {
+		return nil
+	}
+	inner, _, _ = strings.Cut(inner, "]")
+	var items []string
+	for _, item := range strings.Split(inner, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// unquote trims the quotes around a YAML scalar
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestComposeServices(t *testing.T) {
+	dir := t.TempDir()
+	compose := `name: shop
+services:
+  web:
+    build:
+      context: .
+    ports:
+      - "8080:80"
+    volumes:
+      - ./src:/app/src:ro
+      - /app/node_modules
+    depends_on:
+      - db
+  db:
+    image: postgres:16
+    volumes:
+      - type: volume
+        source: pgdata
+        target: /var/lib/postgresql/data
+  debug:
+    image: busybox
+    profiles: [debug, tools]
+volumes:
+  pgdata:
+`
+	override := `services:
+  db:
+    image: postgres:17
+    profiles:
+      - db
+`
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "compose.override.yaml"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ComposeServices(dir, []string{"compose.yaml", "compose.override.yaml"})
+	want := []ComposeService{
+		{
+			Name:        "web",
+			Build:       true,
+			Environment: map[string]string{},
+			Ports:       []PortMapping{{Published: 8080, Target: 80}},
+			Volumes:     []VolumeMount{{Source: "./src", Target: "/app/src"}, {Target: "/app/node_modules"}},
+			File:        "compose.yaml",
+		},
+		{
+			Name:        "db",
+			Image:       "postgres:17",
+			Environment: map[string]string{},
+			Volumes:     []VolumeMount{{Source: "pgdata", Target: "/var/lib/postgresql/data"}},
+			Profiles:    []string{"db"},
+			File:        "compose.override.yaml",
+		},
+		{
+			Name:        "debug",
+			Image:       "busybox",
+			Environment: map[string]string{},
+			Profiles:    []string{"debug", "tools"},
+			File:        "compose.yaml",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ComposeServices() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if profiles := composeProfiles(got); !reflect.DeepEqual(profiles, []string{"db", "debug", "tools"}) {
+		t.Errorf("composeProfiles() = %v", profiles)
+	}
+	if !got[0].Volumes[0].Bind() || got[1].Volumes[0].Bind() {
+		t.Error("Expected ./src to be a bind mount and pgdata a volume")
+	}
+}
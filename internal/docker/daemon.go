@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// daemonTimeout is how long to wait for the daemon to answer
+const daemonTimeout = 5 * time.Second
+
+// DaemonRunning reports whether a container runtime's daemon answers. A
+// runtime that isn't installed counts as running, so that its actions fail
+// with the error saying so.
+func DaemonRunning(name string) bool {
+	if _, err := sys.LookPath(name); err != nil {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+	// version asks the daemon for its version, failing if it can't
+	return sys.CommandContext(ctx, name, "version").Run() == nil
+}
+
+// StartCommand returns the command that starts a runtime's daemon on this
+// machine, or "" if there's none to suggest: the Podman machine, Colima when
+// it's installed, Docker Desktop on macOS and the system service on Linux
+func StartCommand(name string) string {
+	if name == "podman" {
+		return "podman machine start"
+	}
+	if _, err := sys.LookPath("colima"); err == nil {
+		return "colima start"
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return "open -a Docker"
+	case "linux":
+		if name == "docker" {
+			return "sudo systemctl start docker"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,309 @@
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/envfile"
+)
+
+// DatabaseKind is a database proj has quick actions for
+type DatabaseKind string
+
+const (
+	Postgres DatabaseKind = "postgres"
+	MySQL    DatabaseKind = "mysql"
+	MariaDB  DatabaseKind = "mariadb"
+	Redis    DatabaseKind = "redis"
+)
+
+// databaseImages maps the start of an image's name, without its registry
+// and tag, to the database it runs
+var databaseImages = []struct {
+	prefix string
+	kind   DatabaseKind
+}{
+	{"postgres", Postgres}, // Also bitnami/postgresql
+	{"postgis", Postgres},
+	{"timescaledb", Postgres},
+	{"mysql", MySQL},
+	{"percona", MySQL},
+	{"mariadb", MariaDB},
+	{"redis", Redis},
+	{"valkey", Redis},
+}
+
+// Database is a database service of a compose project, with the credentials
+// its environment gives it
+type Database struct {
+	Service     string
+	Kind        DatabaseKind
+	User        string
+	Password    string
+	Name        string // Database to connect to; "" for all of them, and for Redis
+	Port        int    // Host port the service publishes; 0 if it isn't published
+	ComposeFile string // Compose file that defines the service
+}
+
+// Databases returns the database services in a project's compose files, in
+// the order they're defined. Variables in their environment are
+// interpolated the way compose does, from proj's environment and then the
+// project's .env.
+func Databases(projectPath string, composeFiles []string) []Database {
+	services := ComposeServices(projectPath, composeFiles)
+
+	lookup := envLookup(projectPath)
+	var databases []Database
+	for _, svc := range services {
+		kind := databaseKind(interpolate(svc.Image, lookup))
+		if kind == "" {
+			continue
+		}
+		env := make(map[string]string, len(svc.Environment))
+		for k, v := range svc.Environment {
+			env[k] = interpolate(v, lookup)
+		}
+
+		db := Database{Service: svc.Name, Kind: kind, ComposeFile: svc.File}
+		db.setCredentials(env)
+		for _, port := range svc.Ports {
+			if port.Target == db.defaultPort() && port.Published > 0 {
+				db.Port = port.Published
+				break
+			}
+		}
+		databases = append(databases, db)
+	}
+	return databases
+}
+
+// databaseKind returns the database an image runs, or ""
+func databaseKind(image string) DatabaseKind {
+	image, _, _ = strings.Cut(image, "@")
+	base := image[strings.LastIndex(image, "/")+1:]
+	base, _, _ = strings.Cut(base, ":")
+	for _, d := range databaseImages {
+		if strings.HasPrefix(strings.ToLower(base), d.prefix) {
+			return d.kind
+		}
+	}
+	return ""
+}
+
+// setCredentials reads the user, password and database the official (and
+// bitnami) images create from their environment, falling back to the ones
+// the images create by default
+func (d *Database) setCredentials(env map[string]string) {
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if v := env[key]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	switch d.Kind {
+	case Postgres:
+		d.User = first("POSTGRES_USER", "POSTGRESQL_USERNAME")
+		if d.User == "" {
+			d.User = "postgres"
+		}
+		d.Password = first("POSTGRES_PASSWORD", "POSTGRESQL_PASSWORD")
+		if d.Name = first("POSTGRES_DB", "POSTGRESQL_DATABASE"); d.Name == "" {
+			d.Name = d.User
+		}
+	case MySQL, MariaDB:
+		d.Name = first("MARIADB_DATABASE", "MYSQL_DATABASE")
+		if user := first("MARIADB_USER", "MYSQL_USER"); user != "" {
+			d.User, d.Password = user, first("MARIADB_PASSWORD", "MYSQL_PASSWORD")
+		} else {
+			d.User, d.Password = "root", first("MARIADB_ROOT_PASSWORD", "MYSQL_ROOT_PASSWORD")
+		}
+	case Redis:
+		d.Password = first("REDIS_PASSWORD")
+	}
+}
+
+// defaultPort returns the port the database listens on in its container
+func (d Database) defaultPort() int {
+	switch d.Kind {
+	case Postgres:
+		return 5432
+	case MySQL, MariaDB:
+		return 3306
+	default:
+		return 6379
+	}
+}
+
+// Address returns where to connect to the database: its published port on
+// localhost, or else the service on the compose network, which only other
+// containers can reach
+func (d Database) Address() string {
+	if d.Port > 0 {
+		return fmt.Sprintf("localhost:%d", d.Port)
+	}
+	return fmt.Sprintf("%s:%d", d.Service, d.defaultPort())
+}
+
+// ConnectionString returns a URL for connecting to the database
+func (d Database) ConnectionString() string {
+	u := url.URL{Scheme: string(d.Kind), Host: d.Address()}
+	switch d.Kind {
+	case MySQL, MariaDB:
+		u.Scheme = "mysql"
+	case Redis:
+		if d.Password != "" {
+			u.User = url.UserPassword("", d.Password)
+		}
+		return u.String()
+	}
+	if d.Password != "" {
+		u.User = url.UserPassword(d.User, d.Password)
+	} else {
+		u.User = url.User(d.User)
+	}
+	if d.Name != "" {
+		u.Path = "/" + d.Name
+	}
+	return u.String()
+}
+
+// ShellArgs returns the docker arguments that open the database's client in
+// its container
+func (d Database) ShellArgs() []string {
+	args := d.exec(true)
+	switch d.Kind {
+	case Postgres:
+		return append(args, "psql", "-U", d.User, "-d", d.Name)
+	case MySQL, MariaDB:
+		return d.mysqlArgs(args, d.client())
+	default:
+		return append(args, "redis-cli")
+	}
+}
+
+// DumpArgs returns the docker arguments that write a dump of the database
+// to stdout: SQL that replaces the tables it holds, or Redis's RDB snapshot
+func (d Database) DumpArgs() []string {
+	args := d.exec(false)
+	switch d.Kind {
+	case Postgres:
+		return append(args, "pg_dump", "-U", d.User, "--clean", "--if-exists", d.Name)
+	case MySQL, MariaDB:
+		dump := "mysqldump"
+		if d.Kind == MariaDB {
+			dump = "mariadb-dump"
+		}
+		if d.Name == "" {
+			return append(args, dump, "-u", d.User, "--all-databases")
+		}
+		return d.mysqlArgs(args, dump)
+	default:
+		return append(args, "redis-cli", "--rdb", "-")
+	}
+}
+
+// RestoreArgs returns the docker arguments that load a dump from stdin, or
+// nil for Redis, whose snapshots are only loaded when it starts
+func (d Database) RestoreArgs() []string {
+	switch d.Kind {
+	case Postgres:
+		return append(d.exec(false), "psql", "-U", d.User, "-d", d.Name)
+	case MySQL, MariaDB:
+		return d.mysqlArgs(d.exec(false), d.client())
+	}
+	return nil
+}
+
+// DumpExt returns the extension of the database's dumps
+func (d Database) DumpExt() string {
+	if d.Kind == Redis {
+		return ".rdb"
+	}
+	return ".sql"
+}
+
+// exec starts the docker arguments that run a command in the service, with
+// its password in the variable the client reads. Without tty, the command
+// reads and writes plain streams, as dumps need.
+func (d Database) exec(tty bool) []string {
+	args := []string{"compose", "-f", d.ComposeFile, "exec"}
+	if !tty {
+		args = append(args, "-T")
+	}
+	if d.Password != "" {
+		switch d.Kind {
+		case Postgres:
+			args = append(args, "-e", "PGPASSWORD="+d.Password)
+		case MySQL, MariaDB:
+			args = append(args, "-e", "MYSQL_PWD="+d.Password)
+		case Redis:
+			args = append(args, "-e", "REDISCLI_AUTH="+d.Password)
+		}
+	}
+	return append(args, d.Service)
+}
+
+// mysqlArgs adds a MySQL or MariaDB command for the database's user and
+// database to args
+func (d Database) mysqlArgs(args []string, command string) []string {
+	args = append(args, command, "-u", d.User)
+	if d.Name != "" {
+		args = append(args, d.Name)
+	}
+	return args
+}
+
+// client returns the command line client of a MySQL or MariaDB database;
+// newer MariaDB images no longer have the mysql names
+func (d Database) client() string {
+	if d.Kind == MariaDB {
+		return "mariadb"
+	}
+	return "mysql"
+}
+
+// envLookup looks variables up the way compose interpolates them: in the
+// environment, then in the project's .env
+func envLookup(projectPath string) func(string) (string, bool) {
+	dotenv := make(map[string]string)
+	if vars, err := envfile.Parse(filepath.Join(projectPath, envfile.Name)); err == nil {
+		for _, v := range vars {
+			dotenv[v.Key] = v.Value
+		}
+	}
+	return func(key string) (string, bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			return v, true
+		}
+		v, ok := dotenv[key]
+		return v, ok
+	}
+}
+
+// interpolate replaces $VAR, ${VAR}, ${VAR:-default} and ${VAR-default} in
+// a compose value, and $$ with $
+func interpolate(value string, lookup func(string) (string, bool)) string {
+	return os.Expand(value, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		key, rest := name, ""
+		if i := strings.IndexAny(name, ":-?"); i >= 0 {
+			key, rest = name[:i], name[i:]
+		}
+		v, set := lookup(key)
+		switch {
+		case strings.HasPrefix(rest, ":-") && v == "":
+			return rest[2:]
+		case strings.HasPrefix(rest, "-") && !set:
+			return rest[1:]
+		}
+		return v
+	})
+}
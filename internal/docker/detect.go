@@ -1,17 +1,20 @@
 package docker
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/s33g/proj/internal/sys"
 )
 
 // DockerInfo contains information about Docker files in a project
 type DockerInfo struct {
 	HasDockerfile bool
 	HasCompose    bool
-	Dockerfiles   []string // All Dockerfile variants found
-	ComposeFiles  []string // All compose files found
+	Dockerfiles   []string         // All Dockerfile variants found
+	ComposeFiles  []string         // All compose files found
+	Services      []ComposeService // Services of the compose files, merged
+	Profiles      []string         // Profiles the services use, sorted
 }
 
 // composeFileNames are exact filenames for Docker Compose files
@@ -24,7 +27,7 @@ var composeFileNames = []string{
 
 // Detect scans a project directory for Docker-related files
 func Detect(projectPath string) (*DockerInfo, error) {
-	entries, err := os.ReadDir(projectPath)
+	entries, err := sys.ReadDir(projectPath)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +57,11 @@ func Detect(projectPath string) (*DockerInfo, error) {
 		}
 	}
 
+	if info.HasCompose {
+		info.Services = ComposeServices(projectPath, info.ComposeFiles)
+		info.Profiles = composeProfiles(info.Services)
+	}
+
 	return info, nil
 }
 
@@ -82,11 +90,10 @@ func isComposeFile(filename string) bool {
 	return false
 }
 
-// GetComposeCommand returns the preferred compose command
-// Checks for docker compose v2 plugin first, falls back to docker-compose v1
-func GetComposeCommand() string {
-	// Modern Docker CLI with compose plugin
-	return "docker compose"
+// GetComposeCommand returns the compose command of a container runtime:
+// docker compose, podman compose or nerdctl compose
+func GetComposeCommand(runtime string) string {
+	return runtime + " compose"
 }
 
 // GetPrimaryDockerfile returns the most likely primary Dockerfile
@@ -130,6 +137,6 @@ func GetPrimaryComposeFile(composeFiles []string) string {
 // HasDevContainer checks if a project has a dev container configuration
 func HasDevContainer(projectPath string) bool {
 	devcontainerPath := filepath.Join(projectPath, ".devcontainer", "devcontainer.json")
-	_, err := os.Stat(devcontainerPath)
+	_, err := sys.Stat(devcontainerPath)
 	return err == nil
 }
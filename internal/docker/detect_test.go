@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/s33g/proj/internal/sys"
 )
 
 func TestIsDockerfile(t *testing.T) {
@@ -271,3 +273,37 @@ func TestHasDevContainer(t *testing.T) {
 		}
 	})
 }
+
+func TestRuntime(t *testing.T) {
+	fake := sys.NewFake().On("podman", sys.Result{}).On("nerdctl", sys.Result{})
+	t.Cleanup(sys.UseRunner(fake))
+
+	if got := Runtime(""); got != "podman" {
+		t.Errorf("Runtime(\"\") without docker = %q, want podman", got)
+	}
+	if got := Runtime("nerdctl"); got != "nerdctl" {
+		t.Errorf("Runtime(nerdctl) = %q", got)
+	}
+	sys.UseRunner(sys.NewFake())
+	if got := Runtime("auto"); got != "docker" {
+		t.Errorf("Runtime(auto) with nothing installed = %q, want docker", got)
+	}
+	if got := GetComposeCommand("podman"); got != "podman compose" {
+		t.Errorf("GetComposeCommand(podman) = %q", got)
+	}
+}
+
+func TestDaemonRunning(t *testing.T) {
+	fake := sys.NewFake().On("docker version", sys.Result{Stderr: "Cannot connect to the Docker daemon", ExitCode: 1})
+	t.Cleanup(sys.UseRunner(fake))
+	if DaemonRunning("docker") {
+		t.Error("Expected the daemon to be down when docker version fails")
+	}
+	fake.On("docker version", sys.Result{})
+	if !DaemonRunning("docker") {
+		t.Error("Expected the daemon to be running when docker version succeeds")
+	}
+	if !DaemonRunning("no-such-runtime") {
+		t.Error("Expected a runtime that isn't installed to count as running")
+	}
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Dockerfile is what proj reads of a Dockerfile to ask about before a build
+type Dockerfile struct {
+	Args   []BuildArg // ARG declarations, in order, each name once
+	Stages []string   // Named build stages (FROM ... AS name), in order
+}
+
+// BuildArg is an ARG declaration of a Dockerfile
+type BuildArg struct {
+	Name    string
+	Default string // Value after =, "" without one
+}
+
+// BuildOptions are the choices made before building an image
+type BuildOptions struct {
+	Target string            // Stage to stop at; "" for the last
+	Args   map[string]string // --build-arg values
+}
+
+// ParseDockerfile reads the build args and stages of a Dockerfile. Lines
+// continued with a backslash are joined, and instructions are matched in
+// any case, as docker does.
+func ParseDockerfile(path string) (Dockerfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Dockerfile{}, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var df Dockerfile
+	seen := make(map[string]bool)
+	instruction := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if instruction == "" && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		if continued, ok := strings.CutSuffix(line, "\\"); ok {
+			instruction += continued + " "
+			continue
+		}
+		instruction += line
+		df.add(instruction, seen)
+		instruction = ""
+	}
+	if instruction != "" {
+		df.add(instruction, seen)
+	}
+	return df, scanner.Err()
+}
+
+// add records the ARG or FROM instruction of a Dockerfile
+func (d *Dockerfile) add(instruction string, seen map[string]bool) {
+	fields := strings.Fields(instruction)
+	if len(fields) < 2 {
+		return
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "ARG":
+		// ARG may declare several: ARG A=1 B
+		for _, decl := range fields[1:] {
+			name, value, _ := strings.Cut(decl, "=")
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			d.Args = append(d.Args, BuildArg{Name: name, Default: unquote(value)})
+		}
+	case "FROM":
+		if len(fields) >= 4 && strings.EqualFold(fields[len(fields)-2], "AS") {
+			d.Stages = append(d.Stages, fields[len(fields)-1])
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	content := `# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.24
+FROM golang:${GO_VERSION} AS build
+ARG VERSION \
+    COMMIT="unknown"
+RUN go build -ldflags "-X main.version=$VERSION" ./...
+
+from alpine:3.20 as runtime
+arg GO_VERSION
+COPY --from=build /app /app
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseDockerfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Dockerfile{
+		Args:   []BuildArg{{Name: "GO_VERSION", Default: "1.24"}, {Name: "VERSION"}, {Name: "COMMIT", Default: "unknown"}},
+		Stages: []string{"build", "runtime"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDockerfile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildArgs(t *testing.T) {
+	info := &DockerInfo{Dockerfiles: []string{"Dockerfile"}}
+	got := BuildArgs("My App", info, BuildOptions{Target: "runtime", Args: map[string]string{"VERSION": "1.2", "COMMIT": "abc"}})
+	want := []string{"build", "-t", "my-app", "--target", "runtime", "--build-arg", "COMMIT=abc", "--build-arg", "VERSION=1.2", "."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildArgs() = %q, want %q", got, want)
+	}
+}
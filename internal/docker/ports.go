@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"sort"
+)
+
+// ComposePorts returns the host ports the services in a project's compose
+// files publish, in order. Ports published without a host port get a random
+// one, so they're left out.
+func ComposePorts(projectPath string, composeFiles []string) []int {
+	seen := make(map[int]bool)
+	for _, svc := range ComposeServices(projectPath, composeFiles) {
+		for _, port := range svc.Ports {
+			if port.Published > 0 {
+				seen[port.Published] = true
+			}
+		}
+	}
+
+	ports := make([]int, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestComposePorts(t *testing.T) {
+	dir := t.TempDir()
+	compose := `services:
+  web:
+    image: nginx
+    ports:
+      - "8080:80"
+      - 127.0.0.1:8443:443/tcp
+      - "9229"
+    environment:
+      - "NOT_A_PORT=1:2"
+  db:
+    image: postgres
+    ports:
+      - target: 5432
+        published: "15432"
+  cache:
+    image: redis
+    ports: ["6379"]
+`
+	override := `services:
+  web:
+    ports:
+      - "3000-3001:3000-3001"
+      - "8080:80"
+`
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "compose.override.yaml"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ComposePorts(dir, []string{"compose.yaml", "compose.override.yaml", "missing.yml"})
+	if want := []int{3000, 8080, 8443, 15432}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ComposePorts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,22 @@
+package docker
+
+import "github.com/s33g/proj/internal/sys"
+
+// Runtimes are the container CLIs that accept docker's commands, including
+// compose, in the order they're looked for
+var Runtimes = []string{"docker", "podman", "nerdctl"}
+
+// Runtime returns the container CLI to run: the configured one, or else the
+// first of Runtimes that's installed. Without any it returns docker, so the
+// error names what's missing.
+func Runtime(configured string) string {
+	if configured != "" && configured != "auto" {
+		return configured
+	}
+	for _, runtime := range Runtimes {
+		if _, err := sys.LookPath(runtime); err == nil {
+			return runtime
+		}
+	}
+	return "docker"
+}
@@ -0,0 +1,172 @@
+package docker
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// statsFormat is the docker stats --format that parseStats reads
+const statsFormat = "{{.Name}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.MemPerc}}\t{{.NetIO}}\t{{.BlockIO}}\t{{.PIDs}}"
+
+// ContainerStats is a container's resource usage at one point in time
+type ContainerStats struct {
+	Name       string
+	CPU        string // e.g. 0.52%
+	MemUsage   string // e.g. 41.2MiB / 7.66GiB
+	MemPercent string
+	NetIO      string
+	BlockIO    string
+	PIDs       string
+	memory     int64 // Bytes in use, for sorting
+}
+
+// projectContainers returns the names of the running containers of a
+// project: those compose started from its directory, and the one Run
+// Detached names after it
+func projectContainers(runtime, projectPath, projectName string) ([]string, error) {
+	dir, err := filepath.Abs(projectPath)
+	if err != nil {
+		dir = projectPath
+	}
+	filters := []string{
+		"label=com.docker.compose.project.working_dir=" + dir,
+		"name=^" + sanitizeImageName(projectName) + "-container$",
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, filter := range filters {
+		output, err := sys.Command(runtime, "ps", "--filter", filter, "--format", "{{.Names}}").Output()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range strings.Fields(string(output)) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// dockerStats shows the resource usage of the project's running containers
+func dockerStats(runtime, projectPath, projectName string) ExecuteResult {
+	names, err := projectContainers(runtime, projectPath, projectName)
+	if err != nil {
+		return ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to list containers: %v", err),
+		}
+	}
+	if len(names) == 0 {
+		return ExecuteResult{
+			Success: true,
+			Message: "No running containers for " + projectName,
+		}
+	}
+
+	args := append(Args("docker-stats", projectName, nil), names...)
+	cmd := sys.Command(runtime, args...)
+	cmd.Dir = projectPath
+
+	output, err := runCommand(cmd)
+	cmdStr := fmt.Sprintf("%s %s", runtime, strings.Join(args, " "))
+
+	if err != nil {
+		return ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to get stats:\n%s", output),
+			Command: cmdStr,
+		}
+	}
+
+	return ExecuteResult{
+		Success: true,
+		Message: FormatStats(parseStats(output)),
+		Command: cmdStr,
+	}
+}
+
+// parseStats reads the output of docker stats in statsFormat
+func parseStats(output string) []ContainerStats {
+	var stats []ContainerStats
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 7 {
+			continue
+		}
+		used, _, _ := strings.Cut(fields[2], "/")
+		stats = append(stats, ContainerStats{
+			Name:       fields[0],
+			CPU:        fields[1],
+			MemUsage:   fields[2],
+			MemPercent: fields[3],
+			NetIO:      fields[4],
+			BlockIO:    fields[5],
+			PIDs:       fields[6],
+			memory:     parseSize(used),
+		})
+	}
+	return stats
+}
+
+// FormatStats renders container stats as a table aligned in columns, the
+// containers using the most memory first
+func FormatStats(stats []ContainerStats) string {
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].memory > stats[j].memory })
+
+	rows := [][]string{{"CONTAINER", "CPU", "MEMORY", "MEM %", "NET I/O", "BLOCK I/O", "PIDS"}}
+	for _, s := range stats {
+		rows = append(rows, []string{s.Name, s.CPU, s.MemUsage, s.MemPercent, s.NetIO, s.BlockIO, s.PIDs})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		lines[r] = strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sizeUnits are the multipliers of the units docker stats prints sizes in
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"kB":  1e3,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+}
+
+// parseSize reads a size such as 41.2MiB in bytes, or 0
+func parseSize(s string) int64 {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i <= 0 {
+		return 0
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0
+	}
+	return int64(n * sizeUnits[s[i:]])
+}
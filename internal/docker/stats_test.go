@@ -0,0 +1,57 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+func TestDockerStats(t *testing.T) {
+	dir := t.TempDir()
+	fake := sys.NewFake().
+		On("docker ps --filter label=com.docker.compose.project.working_dir="+dir, sys.Result{Stdout: "shop-db-1\nshop-web-1\n"}).
+		On("docker ps --filter name=^shop-container$", sys.Result{Stdout: "shop-container\n"}).
+		On("docker stats", sys.Result{Stdout: "shop-db-1\t0.02%\t41.2MiB / 7.66GiB\t0.53%\t1.2kB / 0B\t0B / 0B\t6\n" +
+			"shop-web-1\t12.50%\t1.1GiB / 7.66GiB\t14.36%\t5MB / 2MB\t0B / 0B\t23\n" +
+			"shop-container\t0.00%\t512KiB / 7.66GiB\t0.01%\t0B / 0B\t0B / 0B\t1\n"})
+	t.Cleanup(sys.UseRunner(fake))
+
+	result := dockerStats("docker", dir, "Shop")
+	if !result.Success {
+		t.Fatalf("expected success, got failure: %s", result.Message)
+	}
+	if calls := fake.Calls(); !strings.HasSuffix(calls[len(calls)-1], " shop-db-1 shop-web-1 shop-container") {
+		t.Errorf("expected stats of the project's containers, ran %q", calls[len(calls)-1])
+	}
+
+	lines := strings.Split(result.Message, "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "CONTAINER") {
+		t.Fatalf("unexpected table:\n%s", result.Message)
+	}
+	// Most memory first, in aligned columns
+	for i, name := range []string{"shop-web-1", "shop-db-1", "shop-container"} {
+		if !strings.HasPrefix(lines[i+1], name) {
+			t.Errorf("row %d = %q, want %s", i+1, lines[i+1], name)
+		}
+	}
+	col := strings.Index(lines[0], "CPU")
+	if strings.Index(lines[1], "12.50%") != col || strings.Index(lines[3], "0.00%") != col {
+		t.Errorf("CPU column not aligned:\n%s", result.Message)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"41MiB":  41 << 20,
+		"1.5GiB": 3 << 29,
+		"2kB":    2000,
+		"0B":     0,
+		"--":     0,
+	}
+	for in, want := range tests {
+		if got := parseSize(in); got != want {
+			t.Errorf("parseSize(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
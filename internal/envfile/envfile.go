@@ -0,0 +1,182 @@
+// Package envfile reads a project's .env files, compares them with the
+// example committed alongside them, and fills in the keys they're missing
+package envfile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Name is the file applications load their environment from
+const Name = ".env"
+
+// ExampleNames are the committed templates of .env, in order of preference
+var ExampleNames = []string{".env.example", ".env.sample", ".env.template", ".env.dist"}
+
+// Var is a variable set in an env file
+type Var struct {
+	Key   string
+	Value string // Unquoted
+	Line  int    // 1-based
+	Raw   string // The line as written
+}
+
+// Files returns the names of the .env files in a project: .env, then the
+// others (.env.local, .env.example, ...) in order
+func Files(projectPath string) []string {
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && (name == Name || strings.HasPrefix(name, Name+".")) {
+			files = append(files, name)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if (files[i] == Name) != (files[j] == Name) {
+			return files[i] == Name
+		}
+		return files[i] < files[j]
+	})
+	return files
+}
+
+// Example returns the name of the project's .env template, or ""
+func Example(projectPath string) string {
+	for _, name := range ExampleNames {
+		if _, err := os.Stat(filepath.Join(projectPath, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// Parse reads the variables of an env file: KEY=value lines, optionally
+// prefixed with export, skipping blank lines and # comments
+func Parse(path string) ([]Var, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var vars []Var
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(trimmed, "export "), "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			continue
+		}
+		vars = append(vars, Var{Key: key, Value: unquote(strings.TrimSpace(value)), Line: n, Raw: line})
+	}
+	return vars, scanner.Err()
+}
+
+// unquote strips the quotes around a value, or a trailing comment from an
+// unquoted one
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+// Mask hides a value for display, keeping only whether it's set
+func Mask(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	return strings.Repeat("•", min(len([]rune(value)), 8))
+}
+
+// Missing returns the variables of example whose keys vars doesn't set
+func Missing(example, vars []Var) []Var {
+	set := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		set[v.Key] = true
+	}
+	var missing []Var
+	for _, v := range example {
+		if !set[v.Key] {
+			missing = append(missing, v)
+			set[v.Key] = true
+		}
+	}
+	return missing
+}
+
+// Keys returns the keys of vars
+func Keys(vars []Var) []string {
+	keys := make([]string, len(vars))
+	for i, v := range vars {
+		keys[i] = v.Key
+	}
+	return keys
+}
+
+// Sync creates a project's .env from its example, or adds the lines of the
+// keys it's missing to the end of it. It returns the variables added, and
+// whether .env was created.
+func Sync(projectPath string) (added []Var, created bool, err error) {
+	example := Example(projectPath)
+	if example == "" {
+		return nil, false, os.ErrNotExist
+	}
+	exampleVars, err := Parse(filepath.Join(projectPath, example))
+	if err != nil {
+		return nil, false, err
+	}
+
+	path := filepath.Join(projectPath, Name)
+	vars, err := Parse(path)
+	if os.IsNotExist(err) {
+		data, err := os.ReadFile(filepath.Join(projectPath, example))
+		if err != nil {
+			return nil, false, err
+		}
+		// .env holds secrets, so only the user may read it
+		return exampleVars, true, os.WriteFile(path, data, 0600)
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	missing := Missing(exampleVars, vars)
+	if len(missing) == 0 {
+		return nil, false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	var b strings.Builder
+	b.Write(data)
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	b.WriteString("\n# Added from " + example + "\n")
+	for _, v := range missing {
+		b.WriteString(strings.TrimSpace(v.Raw) + "\n")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, err
+	}
+	return missing, false, os.WriteFile(path, []byte(b.String()), info.Mode().Perm())
+}
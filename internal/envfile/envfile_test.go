@@ -0,0 +1,91 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	writeFile(t, path, "# Database\nDATABASE_URL=postgres://localhost/app\n\nexport API_KEY=\"se cret\" \nDEBUG=true # local only\nEMPTY=\nnot a variable\n")
+
+	vars, err := Parse(path)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, v := range vars {
+		got[v.Key] = v.Value
+	}
+	want := map[string]string{"DATABASE_URL": "postgres://localhost/app", "API_KEY": "se cret", "DEBUG": "true", "EMPTY": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+	if vars[1].Line != 4 {
+		t.Errorf("API_KEY on line %d, want 4", vars[1].Line)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env.local", ".env.example", ".env", ".envrc.bak", ".environment"} {
+		writeFile(t, filepath.Join(dir, name), "")
+	}
+	os.Mkdir(filepath.Join(dir, ".env.d"), 0755)
+
+	if got, want := Files(dir), []string{".env", ".env.example", ".env.local"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Files() = %v, want %v", got, want)
+	}
+	if got := Example(dir); got != ".env.example" {
+		t.Errorf("Example() = %q", got)
+	}
+}
+
+func TestMask(t *testing.T) {
+	for value, want := range map[string]string{"": "(empty)", "abc": "•••", "a-very-long-secret": "••••••••"} {
+		if got := Mask(value); got != want {
+			t.Errorf("Mask(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := Sync(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected an error without an example, got %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, ".env.example"), "API_KEY=changeme\nPORT=3000\n")
+	added, created, err := Sync(dir)
+	if err != nil || !created || !reflect.DeepEqual(Keys(added), []string{"API_KEY", "PORT"}) {
+		t.Fatalf("Sync() = %v, %v, %v", Keys(added), created, err)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, ".env")); info.Mode().Perm() != 0600 {
+		t.Errorf("Created .env with mode %v, want 0600", info.Mode().Perm())
+	}
+
+	// Keys added to the example later are appended, leaving values set alone
+	writeFile(t, filepath.Join(dir, ".env"), "API_KEY=real-key")
+	writeFile(t, filepath.Join(dir, ".env.example"), "API_KEY=changeme\nPORT=3000\nexport SENTRY_DSN=\n")
+	added, created, err = Sync(dir)
+	if err != nil || created || !reflect.DeepEqual(Keys(added), []string{"PORT", "SENTRY_DSN"}) {
+		t.Fatalf("Sync() = %v, %v, %v", Keys(added), created, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	if want := "API_KEY=real-key\n\n# Added from .env.example\nPORT=3000\nexport SENTRY_DSN=\n"; string(data) != want {
+		t.Errorf(".env = %q, want %q", data, want)
+	}
+
+	if added, _, err := Sync(dir); err != nil || len(added) != 0 {
+		t.Errorf("Sync() of a complete .env = %v, %v", Keys(added), err)
+	}
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// AuthFailure is a kind of authentication failure reported by git
+type AuthFailure string
+
+// Authentication failures recognized by DetectAuthFailure
+const (
+	AuthSSHKey  AuthFailure = "ssh-key"  // The server rejected the SSH keys offered
+	AuthHostKey AuthFailure = "host-key" // The server's host key is unknown or changed
+	AuthHTTPS   AuthFailure = "https"    // HTTPS credentials are missing or were rejected
+)
+
+// authMarkers map lowercase phrases in git output to the failure they indicate
+var authMarkers = []struct {
+	phrase  string
+	failure AuthFailure
+}{
+	{"permission denied (publickey", AuthSSHKey},
+	{"host key verification failed", AuthHostKey},
+	{"terminal prompts disabled", AuthHTTPS},
+	{"could not read username", AuthHTTPS},
+	{"could not read password", AuthHTTPS},
+	{"authentication failed for", AuthHTTPS},
+	{"invalid username or password", AuthHTTPS},
+	{"the requested url returned error: 403", AuthHTTPS},
+}
+
+// DetectAuthFailure returns the authentication failure git output reports,
+// or "" if it reports none
+func DetectAuthFailure(output string) AuthFailure {
+	lower := strings.ToLower(output)
+	for _, m := range authMarkers {
+		if strings.Contains(lower, m.phrase) {
+			return m.failure
+		}
+	}
+	return ""
+}
+
+// Guidance explains how to fix the failure
+func (f AuthFailure) Guidance() string {
+	switch f {
+	case AuthSSHKey:
+		return "Authentication failed: the server rejected your SSH key.\n" +
+			"Check that your key is loaded with ssh-add -l (start the agent with\n" +
+			"eval \"$(ssh-agent -s)\" and add the key with ssh-add), and that its\n" +
+			"public key is registered with the git host. ssh -T git@<host> tests it."
+	case AuthHostKey:
+		return "Authentication failed: the server's host key could not be verified.\n" +
+			"Connect once with ssh -T git@<host> to review and accept the key, or\n" +
+			"remove an outdated entry with ssh-keygen -R <host> if the key changed."
+	case AuthHTTPS:
+		return "Authentication failed: git needs credentials for this HTTPS remote.\n" +
+			"Retry with prompts to enter them, configure a credential helper\n" +
+			"(git config --global credential.helper), or use a personal access\n" +
+			"token instead of your password."
+	}
+	return ""
+}
+
+// withoutPrompts stops git asking for credentials on the terminal, which
+// proj's own interface occupies; it fails with a detectable message instead
+func withoutPrompts(cmd *exec.Cmd) *exec.Cmd {
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+// InteractiveCommand returns a git command for projectPath that may prompt
+// for credentials, to run attached to the terminal
+func InteractiveCommand(projectPath string, args ...string) *exec.Cmd {
+	cmd := sys.Command("git", append([]string{"-C", projectPath}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=1")
+	return cmd
+}
+
+// BackgroundCommand returns a git command for projectPath that fails instead
+// of prompting for credentials, to run while proj's interface is showing
+func BackgroundCommand(ctx context.Context, projectPath string, args ...string) *exec.Cmd {
+	return withoutPrompts(sys.CommandContext(ctx, "git", append([]string{"-C", projectPath}, args...)...))
+}
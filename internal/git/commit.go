@@ -0,0 +1,80 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// HasStaged reports whether changes are staged for the next commit
+func HasStaged(projectPath string) bool {
+	err := sys.Command("git", "-C", projectPath, "diff", "--cached", "--quiet").Run()
+	exitErr, ok := err.(*exec.ExitError)
+	return ok && exitErr.ExitCode() == 1
+}
+
+// Commit commits the staged changes with message, or with all set every
+// change to tracked files, like git commit -a
+func Commit(projectPath, message string, all bool) (string, error) {
+	args := []string{"-C", projectPath, "commit", "-F", "-"}
+	if all {
+		args = append(args, "-a")
+	}
+	cmd := sys.Command("git", args...)
+	cmd.Stdin = strings.NewReader(message)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
+
+// Identity returns "Name <email>" as git would commit in projectPath, or ""
+// if either isn't set
+func Identity(projectPath string) string {
+	email, err := sys.Command("git", "-C", projectPath, "config", "user.email").Output()
+	name := UserName(projectPath)
+	if err != nil || name == "" {
+		return ""
+	}
+	return name + " <" + strings.TrimSpace(string(email)) + ">"
+}
+
+// Authors returns the authors of the commits on HEAD as "Name <email>", most
+// commits first
+func Authors(projectPath string) ([]string, error) {
+	out, err := sys.Command("git", "-C", projectPath, "shortlog", "-sne", "HEAD").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var authors []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if _, author, ok := strings.Cut(strings.TrimSpace(line), "\t"); ok {
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
+// CommitTemplate returns the message template set by git's commit.template,
+// or "" if there is none
+func CommitTemplate(projectPath string) string {
+	out, err := sys.Command("git", "-C", projectPath, "config", "--path", "commit.template").Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
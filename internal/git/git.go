@@ -4,16 +4,56 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/sys"
 )
 
 // Status represents the git status of a project
 type Status struct {
-	IsRepo  bool
-	Branch  string
-	IsDirty bool
+	IsRepo    bool
+	Branch    string // "HEAD" when detached
+	IsDirty   bool
+	Detached  bool   // HEAD points at a commit rather than a branch
+	Operation string // Operation in progress (OpRebase, OpMerge, ...), if any
+}
+
+// Operations that can be left in progress in a repository
+const (
+	OpRebase     = "rebase"
+	OpMerge      = "merge"
+	OpCherryPick = "cherry-pick"
+	OpRevert     = "revert"
+	OpBisect     = "bisect"
+	OpAm         = "am"
+)
+
+// operationMarkers are the files git keeps in .git while an operation is in
+// progress, checked in order
+var operationMarkers = []struct {
+	file string
+	op   string
+}{
+	{"rebase-merge", OpRebase},
+	{filepath.Join("rebase-apply", "applying"), OpAm},
+	{"rebase-apply", OpRebase},
+	{"MERGE_HEAD", OpMerge},
+	{"CHERRY_PICK_HEAD", OpCherryPick},
+	{"REVERT_HEAD", OpRevert},
+	{"BISECT_LOG", OpBisect},
+}
+
+// InProgress returns the operation left in progress in a .git directory, or ""
+func InProgress(gitDir string) string {
+	for _, m := range operationMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.file)); err == nil {
+			return m.op
+		}
+	}
+	return ""
 }
 
 // GetStatus returns the git status for a project directory
@@ -34,6 +74,8 @@ func GetStatus(projectPath string) (*Status, error) {
 	if err == nil {
 		status.Branch = branch
 	}
+	status.Detached = status.Branch == "HEAD"
+	status.Operation = InProgress(gitDir)
 
 	// Check if dirty
 	dirty, err := isDirty(projectPath)
@@ -46,7 +88,7 @@ func GetStatus(projectPath string) (*Status, error) {
 
 // getCurrentBranch returns the current git branch
 func getCurrentBranch(projectPath string) (string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := sys.Command("git", "-C", projectPath, "rev-parse", "--abbrev-ref", "HEAD")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = nil
@@ -60,7 +102,7 @@ func getCurrentBranch(projectPath string) (string, error) {
 
 // isDirty checks if there are uncommitted changes
 func isDirty(projectPath string) (bool, error) {
-	cmd := exec.Command("git", "-C", projectPath, "status", "--porcelain")
+	cmd := sys.Command("git", "-C", projectPath, "status", "--porcelain")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = nil
@@ -74,7 +116,7 @@ func isDirty(projectPath string) (bool, error) {
 
 // GetBranches returns all git branches
 func GetBranches(projectPath string) ([]string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "branch", "--format=%(refname:short)")
+	cmd := sys.Command("git", "-C", projectPath, "branch", "--format=%(refname:short)")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = nil
@@ -96,7 +138,7 @@ func GetBranches(projectPath string) ([]string, error) {
 
 // Pull performs a git pull
 func Pull(projectPath string) (string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "pull")
+	cmd := withoutPrompts(sys.Command("git", "-C", projectPath, "pull"))
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
@@ -105,9 +147,85 @@ func Pull(projectPath string) (string, error) {
 	return strings.TrimSpace(out.String()), err
 }
 
+// Ways to reconcile a pull whose branches diverged, for PullWith
+const (
+	PullRebase      = "rebase"
+	PullFastForward = "ff-only"
+	PullMerge       = "merge"
+)
+
+// pullFlags are the git pull flags of each strategy
+var pullFlags = map[string]string{
+	PullRebase:      "--rebase",
+	PullFastForward: "--ff-only",
+	PullMerge:       "--no-rebase",
+}
+
+// PullFlag returns the git pull flag of strategy, or "" if it's unknown
+func PullFlag(strategy string) string {
+	return pullFlags[strategy]
+}
+
+// PullWith performs a git pull reconciling diverged branches with strategy
+func PullWith(projectPath, strategy string) (string, error) {
+	flag := PullFlag(strategy)
+	if flag == "" {
+		return "", fmt.Errorf("unknown pull strategy: %s", strategy)
+	}
+	cmd := withoutPrompts(sys.Command("git", "-C", projectPath, "pull", flag))
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
+
+// Diverged reports whether git pull output says it stopped because the
+// local branch and its upstream both have commits the other lacks
+func Diverged(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "divergent branches") ||
+		strings.Contains(lower, "diverging branches") ||
+		strings.Contains(lower, "not possible to fast-forward")
+}
+
+// AheadBehind returns the upstream of the current branch and how many
+// commits the branch is ahead of and behind it
+func AheadBehind(projectPath string) (upstream string, ahead, behind int, err error) {
+	cmd := sys.Command("git", "-C", projectPath, "rev-parse", "--abbrev-ref", "@{upstream}")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("no upstream branch: %w", err)
+	}
+	upstream = strings.TrimSpace(string(out))
+
+	cmd = sys.Command("git", "-C", projectPath, "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	out, err = cmd.Output()
+	if err != nil {
+		return upstream, 0, 0, err
+	}
+	if _, err := fmt.Sscan(string(out), &ahead, &behind); err != nil {
+		return upstream, 0, 0, fmt.Errorf("unexpected rev-list output %q", out)
+	}
+	return upstream, ahead, behind, nil
+}
+
 // Checkout switches to a different branch
 func Checkout(projectPath, branch string) (string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "checkout", branch)
+	cmd := sys.Command("git", "-C", projectPath, "checkout", branch)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
+
+// CreateBranch creates a branch at HEAD and switches to it, keeping any
+// uncommitted changes
+func CreateBranch(projectPath, branch string) (string, error) {
+	cmd := sys.Command("git", "-C", projectPath, "checkout", "-b", branch)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
@@ -116,9 +234,17 @@ func Checkout(projectPath, branch string) (string, error) {
 	return strings.TrimSpace(out.String()), err
 }
 
+// CheckBranchName reports why git won't accept name for a branch, if it won't
+func CheckBranchName(name string) error {
+	if err := sys.Command("git", "check-ref-format", "--branch", name).Run(); err != nil {
+		return fmt.Errorf("%q isn't a valid branch name", name)
+	}
+	return nil
+}
+
 // Log returns recent git log entries
 func Log(projectPath string, limit int) ([]string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "log", "--oneline", "--graph", "--decorate", "-n", fmt.Sprintf("%d", limit))
+	cmd := sys.Command("git", "-C", projectPath, "log", "--oneline", "--graph", "--decorate", "-n", fmt.Sprintf("%d", limit))
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = nil
@@ -138,15 +264,103 @@ func Log(projectPath string, limit int) ([]string, error) {
 	return result, nil
 }
 
+// LastCommitTime returns the time of the most recent commit on HEAD
+func LastCommitTime(projectPath string) (time.Time, error) {
+	cmd := sys.Command("git", "-C", projectPath, "log", "-1", "--format=%ct")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = nil
+
+	if err := cmd.Run(); err != nil {
+		return time.Time{}, err
+	}
+
+	secs, err := strconv.ParseInt(strings.TrimSpace(out.String()), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected git log output: %q", out.String())
+	}
+
+	return time.Unix(secs, 0), nil
+}
+
+// WeeklyCommits returns how many commits HEAD gained in each of the last
+// weeks weeks before now, oldest week first
+func WeeklyCommits(projectPath string, weeks int, now time.Time) ([]int, error) {
+	since := now.Add(-time.Duration(weeks) * 7 * 24 * time.Hour)
+	cmd := sys.Command("git", "-C", projectPath, "log", "--since="+since.Format(time.RFC3339), "--format=%ct")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = nil
+
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	counts := make([]int, weeks)
+	for _, line := range strings.Fields(out.String()) {
+		secs, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log output: %q", line)
+		}
+		age := now.Sub(time.Unix(secs, 0))
+		if week := int(age / (7 * 24 * time.Hour)); age >= 0 && week < weeks {
+			counts[weeks-1-week]++
+		}
+	}
+	return counts, nil
+}
+
+// Contributor is an author and how many of the commits on HEAD are theirs
+type Contributor struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// Contributors returns the authors of the commits on HEAD, most commits
+// first, as git shortlog counts them
+func Contributors(projectPath string) ([]Contributor, error) {
+	cmd := sys.Command("git", "-C", projectPath, "shortlog", "-sn", "HEAD")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = nil
+
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	var contributors []Contributor
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		count, name, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		commits, err := strconv.Atoi(count)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git shortlog output: %q", line)
+		}
+		contributors = append(contributors, Contributor{Name: name, Commits: commits})
+	}
+	return contributors, nil
+}
+
+// HeadCommit returns the hash of the commit HEAD points at
+func HeadCommit(projectPath string) (string, error) {
+	out, err := sys.Command("git", "-C", projectPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // IsInstalled checks if git is installed on the system
 func IsInstalled() bool {
-	cmd := exec.Command("git", "--version")
+	cmd := sys.Command("git", "--version")
 	return cmd.Run() == nil
 }
 
 // Init initializes a new git repository in the given directory
 func Init(projectPath string) (string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "init")
+	cmd := sys.Command("git", "-C", projectPath, "init")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
@@ -157,7 +371,7 @@ func Init(projectPath string) (string, error) {
 
 // Stash stashes the current changes
 func Stash(projectPath string) (string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "stash", "push", "-m", "Auto-stash by proj before branch switch")
+	cmd := sys.Command("git", "-C", projectPath, "stash", "push", "-m", "Auto-stash by proj before branch switch")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
@@ -168,7 +382,7 @@ func Stash(projectPath string) (string, error) {
 
 // StashPop pops the most recent stash
 func StashPop(projectPath string) (string, error) {
-	cmd := exec.Command("git", "-C", projectPath, "stash", "pop")
+	cmd := sys.Command("git", "-C", projectPath, "stash", "pop")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
@@ -186,3 +400,13 @@ func IsDirty(projectPath string) (bool, error) {
 func GetCurrentBranch(projectPath string) (string, error) {
 	return getCurrentBranch(projectPath)
 }
+
+// UserName returns the user.name git would commit with in projectPath, or ""
+// if it isn't set
+func UserName(projectPath string) string {
+	out, err := sys.Command("git", "-C", projectPath, "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
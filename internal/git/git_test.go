@@ -5,7 +5,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestIsInstalled(t *testing.T) {
@@ -88,6 +91,44 @@ func TestGetStatus(t *testing.T) {
 	}
 }
 
+func TestGetStatus_DetachedAndInProgress(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+	exec.Command("git", "-C", tmpDir, "init").Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User").Run()
+	os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("a"), 0644)
+	exec.Command("git", "-C", tmpDir, "add", ".").Run()
+	exec.Command("git", "-C", tmpDir, "commit", "-m", "initial").Run()
+
+	status, _ := GetStatus(tmpDir)
+	if status.Detached || status.Operation != "" {
+		t.Errorf("fresh repo: Detached = %v, Operation = %q", status.Detached, status.Operation)
+	}
+
+	if err := exec.Command("git", "-C", tmpDir, "checkout", "--detach").Run(); err != nil {
+		t.Fatalf("checkout --detach failed: %v", err)
+	}
+	status, _ = GetStatus(tmpDir)
+	if !status.Detached || status.Branch != "HEAD" {
+		t.Errorf("after checkout --detach: Detached = %v, Branch = %q", status.Detached, status.Branch)
+	}
+
+	// Operations are detected from the files git leaves in .git
+	gitDir := filepath.Join(tmpDir, ".git")
+	os.WriteFile(filepath.Join(gitDir, "MERGE_HEAD"), []byte("0000"), 0644)
+	if status, _ = GetStatus(tmpDir); status.Operation != OpMerge {
+		t.Errorf("Operation with MERGE_HEAD = %q, want %q", status.Operation, OpMerge)
+	}
+	os.MkdirAll(filepath.Join(gitDir, "rebase-merge"), 0755)
+	if status, _ = GetStatus(tmpDir); status.Operation != OpRebase {
+		t.Errorf("Operation with rebase-merge = %q, want %q", status.Operation, OpRebase)
+	}
+}
+
 func TestGetStatus_NonGitDir(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -194,3 +235,310 @@ func TestLog(t *testing.T) {
 		t.Errorf("Expected 3 log entries, got %d", len(logs))
 	}
 }
+
+func TestLastCommitTime(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+
+	if _, err := LastCommitTime(tmpDir); err == nil {
+		t.Error("Expected error for non-git directory")
+	}
+
+	exec.Command("git", "init", tmpDir).Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User").Run()
+	os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte("test"), 0644)
+	exec.Command("git", "-C", tmpDir, "add", ".").Run()
+
+	commit := exec.Command("git", "-C", tmpDir, "commit", "-m", "old commit")
+	commit.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2020-01-02T03:04:05Z")
+	if err := commit.Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	when, err := LastCommitTime(tmpDir)
+	if err != nil {
+		t.Fatalf("LastCommitTime failed: %v", err)
+	}
+	if when.UTC().Year() != 2020 {
+		t.Errorf("Expected commit from 2020, got %v", when)
+	}
+}
+
+func TestWeeklyCommits(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+	exec.Command("git", "init", tmpDir).Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com").Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.name", "Test User").Run()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, ago := range []time.Duration{400 * 24 * time.Hour, 20 * 24 * time.Hour, 2 * 24 * time.Hour, time.Hour} {
+		os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte(fmt.Sprint(i)), 0644)
+		exec.Command("git", "-C", tmpDir, "add", ".").Run()
+		commit := exec.Command("git", "-C", tmpDir, "commit", "-m", fmt.Sprint("commit ", i))
+		date := now.Add(-ago).Format(time.RFC3339)
+		commit.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date, "GIT_AUTHOR_DATE="+date)
+		if err := commit.Run(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	counts, err := WeeklyCommits(tmpDir, 4, now)
+	if err != nil {
+		t.Fatalf("WeeklyCommits failed: %v", err)
+	}
+	if want := []int{0, 1, 0, 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("WeeklyCommits = %v, want %v", counts, want)
+	}
+}
+
+func TestContributors(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+	exec.Command("git", "init", tmpDir).Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.email", "test@example.com").Run()
+	if _, err := HeadCommit(tmpDir); err == nil {
+		t.Error("Expected error for a repository without commits")
+	}
+
+	for i, author := range []string{"Ann <ann@example.com>", "Bob <bob@example.com>", "Bob <bob@example.com>"} {
+		os.WriteFile(filepath.Join(tmpDir, "test.txt"), []byte(fmt.Sprint(i)), 0644)
+		exec.Command("git", "-C", tmpDir, "add", ".").Run()
+		if err := exec.Command("git", "-C", tmpDir, "-c", "user.name=Committer", "commit", "-m", "change", "--author", author).Run(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	contributors, err := Contributors(tmpDir)
+	if err != nil {
+		t.Fatalf("Contributors failed: %v", err)
+	}
+	want := []Contributor{{Name: "Bob", Commits: 2}, {Name: "Ann", Commits: 1}}
+	if !reflect.DeepEqual(contributors, want) {
+		t.Errorf("Contributors = %+v, want %+v", contributors, want)
+	}
+	if head, err := HeadCommit(tmpDir); err != nil || head == "" {
+		t.Errorf("HeadCommit = %q, %v", head, err)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+	exec.Command("git", "init", tmpDir).Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.name", "Ann").Run()
+	exec.Command("git", "-C", tmpDir, "config", "user.email", "ann@example.com").Run()
+	if got := Identity(tmpDir); got != "Ann <ann@example.com>" {
+		t.Errorf("Identity = %q", got)
+	}
+
+	file := filepath.Join(tmpDir, "test.txt")
+	os.WriteFile(file, []byte("one"), 0644)
+	exec.Command("git", "-C", tmpDir, "add", ".").Run()
+	if !HasStaged(tmpDir) {
+		t.Error("Expected staged changes after git add")
+	}
+	if out, err := Commit(tmpDir, "feat: first\n\nSigned-off-by: Ann <ann@example.com>\n", false); err != nil {
+		t.Fatalf("Commit failed: %v\n%s", err, out)
+	}
+	if HasStaged(tmpDir) {
+		t.Error("Expected nothing staged after committing")
+	}
+
+	// Without staging, all commits the change to the tracked file
+	os.WriteFile(file, []byte("two"), 0644)
+	if out, err := Commit(tmpDir, "fix: second\n", true); err != nil {
+		t.Fatalf("Commit with all failed: %v\n%s", err, out)
+	}
+	if dirty, _ := IsDirty(tmpDir); dirty {
+		t.Error("Expected a clean tree after committing all")
+	}
+
+	out, _ := exec.Command("git", "-C", tmpDir, "log", "--format=%B", "-2").Output()
+	if want := "fix: second\n\nfeat: first\n\nSigned-off-by: Ann <ann@example.com>\n\n"; string(out) != want {
+		t.Errorf("Log = %q, want %q", out, want)
+	}
+	if authors, err := Authors(tmpDir); err != nil || !reflect.DeepEqual(authors, []string{"Ann <ann@example.com>"}) {
+		t.Errorf("Authors = %v, %v", authors, err)
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   Remote
+		ok     bool
+	}{
+		{"https://github.com/s33g/proj.git", Remote{"https://github.com/s33g/proj.git", "github.com", "s33g/proj"}, true},
+		{"ssh://git@gitlab.example.com:2222/team/sub/app", Remote{"ssh://git@gitlab.example.com:2222/team/sub/app", "gitlab.example.com", "team/sub/app"}, true},
+		{"git@github.com:s33g/proj.git", Remote{"git@github.com:s33g/proj.git", "github.com", "s33g/proj"}, true},
+		{"s33g/proj", Remote{"https://github.com/s33g/proj", "github.com", "s33g/proj"}, true},
+		{"codeberg.org/owner/repo", Remote{"https://codeberg.org/owner/repo", "codeberg.org", "owner/repo"}, true},
+		{"proj", Remote{}, false},
+		{"https://github.com/", Remote{}, false},
+		{"owner/../repo", Remote{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRemote(tt.remote)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseRemote(%q) error = %v, want ok %v", tt.remote, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRemote(%q) = %+v, want %+v", tt.remote, got, tt.want)
+		}
+	}
+
+	if r, _ := ParseRemote("git@github.com:s33g/proj.git"); r.WebURL() != "https://github.com/s33g/proj" {
+		t.Errorf("WebURL() = %q", r.WebURL())
+	}
+}
+
+func TestIssueKey(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"feature/ABC-123-login", "ABC-123"},
+		{"ABC-123", "ABC-123"},
+		{"jdoe/eng-42-fix-tests", "ENG-42"},
+		{"eng-42", "ENG-42"},
+		{"fix/#456-crash", "#456"},
+		{"release-2024", ""},
+		{"hotfix/release-2", ""},
+		{"feature/login-v2", ""},
+		{"main", ""},
+	}
+	for _, tt := range tests {
+		if got := IssueKey(tt.branch); got != tt.want {
+			t.Errorf("IssueKey(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestUsesLFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	if UsesLFS(tmpDir) {
+		t.Error("Expected no LFS without .gitattributes")
+	}
+
+	attrs := filepath.Join(tmpDir, ".gitattributes")
+	os.WriteFile(attrs, []byte("*.sh text eol=lf\n# *.psd filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+	if UsesLFS(tmpDir) {
+		t.Error("Expected commented-out LFS patterns to be ignored")
+	}
+
+	os.WriteFile(attrs, []byte("*.psd filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+	if !UsesLFS(tmpDir) {
+		t.Error("Expected LFS to be detected")
+	}
+}
+
+func TestPullDiverged(t *testing.T) {
+	if !IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	remote, local, other := filepath.Join(root, "remote"), filepath.Join(root, "local"), filepath.Join(root, "other")
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(dir, file string) {
+		os.WriteFile(filepath.Join(dir, file), []byte(file), 0644)
+		run(dir, "add", ".")
+		run(dir, "commit", "-m", file)
+	}
+
+	run(root, "init", "--bare", remote)
+	run(root, "clone", remote, local)
+	run(local, "config", "user.email", "test@example.com")
+	run(local, "config", "user.name", "Test User")
+	commit(local, "base")
+	run(local, "push", "origin", "HEAD")
+	run(root, "clone", remote, other)
+	run(other, "config", "user.email", "test@example.com")
+	run(other, "config", "user.name", "Test User")
+	commit(other, "theirs")
+	run(other, "push")
+	commit(local, "ours")
+	run(local, "fetch")
+
+	upstream, ahead, behind, err := AheadBehind(local)
+	if err != nil {
+		t.Fatalf("AheadBehind failed: %v", err)
+	}
+	if !strings.HasPrefix(upstream, "origin/") || ahead != 1 || behind != 1 {
+		t.Errorf("AheadBehind = %s +%d -%d, want origin/... +1 -1", upstream, ahead, behind)
+	}
+
+	if _, err := PullWith(local, "sideways"); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+	output, err := PullWith(local, PullFastForward)
+	if err == nil || !Diverged(output) {
+		t.Errorf("Expected --ff-only to stop on diverged branches, got %v: %s", err, output)
+	}
+	if output, err := PullWith(local, PullRebase); err != nil {
+		t.Fatalf("PullWith(rebase) failed: %v\n%s", err, output)
+	}
+	if _, ahead, behind, _ := AheadBehind(local); ahead != 1 || behind != 0 {
+		t.Errorf("After rebasing expected +1 -0, got +%d -%d", ahead, behind)
+	}
+}
+
+func TestDiverged(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"hint: You have divergent branches and need to specify how to reconcile them.\nfatal: Need to specify how to reconcile divergent branches.", true},
+		{"fatal: Not possible to fast-forward, aborting.", true},
+		{"CONFLICT (content): Merge conflict in main.go", false},
+		{"Already up to date.", false},
+	}
+	for _, tt := range tests {
+		if got := Diverged(tt.output); got != tt.want {
+			t.Errorf("Diverged(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestDetectAuthFailure(t *testing.T) {
+	tests := []struct {
+		output string
+		want   AuthFailure
+	}{
+		{"git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", AuthSSHKey},
+		{"Host key verification failed.\nfatal: Could not read from remote repository.", AuthHostKey},
+		{"fatal: could not read Username for 'https://github.com': terminal prompts disabled", AuthHTTPS},
+		{"remote: Invalid username or password.\nfatal: Authentication failed for 'https://github.com/a/b.git/'", AuthHTTPS},
+		{"fatal: unable to access 'https://example.com/a.git/': The requested URL returned error: 403", AuthHTTPS},
+		{"fatal: Not possible to fast-forward, aborting.", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectAuthFailure(tt.output); got != tt.want {
+			t.Errorf("DetectAuthFailure(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+		if tt.want != "" && tt.want.Guidance() == "" {
+			t.Errorf("Expected guidance for %q", tt.want)
+		}
+	}
+}
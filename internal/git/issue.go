@@ -0,0 +1,45 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Issue keys in branch names: Jira/Linear style ABC-123 anywhere in upper
+// case, or in lower case at the start of a part of the name (eng-123-login),
+// and GitHub style #456
+var (
+	upperIssueKey = regexp.MustCompile(`(?:^|[^A-Za-z0-9])([A-Z][A-Z0-9]{1,9}-[0-9]+)(?:[^0-9]|$)`)
+	lowerIssueKey = regexp.MustCompile(`^([a-z][a-z0-9]{1,9}-[0-9]+)(?:[^0-9]|$)`)
+	numberedIssue = regexp.MustCompile(`#([0-9]+)`)
+)
+
+// notIssueKeys are lower-case words that look like issue keys in branch
+// names such as release-2024 or hotfix-2
+var notIssueKeys = map[string]bool{
+	"release": true, "hotfix": true, "bugfix": true, "feature": true, "fix": true,
+	"version": true, "rc": true, "sprint": true, "wip": true, "build": true,
+}
+
+// IssueKey returns the issue key named in a branch name, e.g. ABC-123 for
+// feature/ABC-123-login or eng-42-fix-tests, or #456 for fix/#456, and ""
+// if it names none
+func IssueKey(branch string) string {
+	if m := upperIssueKey.FindStringSubmatch(branch); m != nil {
+		return m[1]
+	}
+	for _, part := range strings.Split(branch, "/") {
+		m := lowerIssueKey.FindStringSubmatch(part)
+		if m == nil {
+			continue
+		}
+		prefix, _, _ := strings.Cut(m[1], "-")
+		if !notIssueKeys[prefix] {
+			return strings.ToUpper(m[1])
+		}
+	}
+	if m := numberedIssue.FindStringSubmatch(branch); m != nil {
+		return "#" + m[1]
+	}
+	return ""
+}
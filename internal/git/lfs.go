@@ -0,0 +1,51 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// UsesLFS reports whether a project's .gitattributes routes any files
+// through Git LFS
+func UsesLFS(projectPath string) bool {
+	f, err := os.Open(filepath.Join(projectPath, ".gitattributes"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, attr := range strings.Fields(line) {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LFSInstalled reports whether the git-lfs extension is installed
+func LFSInstalled() bool {
+	return sys.Command("git", "lfs", "version").Run() == nil
+}
+
+// LFS runs a git lfs subcommand, e.g. pull or prune
+func LFS(projectPath string, args ...string) (string, error) {
+	cmd := withoutPrompts(sys.Command("git", append([]string{"-C", projectPath, "lfs"}, args...)...))
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
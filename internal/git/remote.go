@@ -0,0 +1,122 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// DefaultHost is assumed for owner/repo shorthands
+const DefaultHost = "github.com"
+
+// Remote is a parsed repository URL
+type Remote struct {
+	URL  string // What to clone
+	Host string // e.g. github.com
+	Path string // e.g. owner/repo, without .git
+}
+
+// scpLike matches git's user@host:path syntax
+var scpLike = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+\.[\w.-]+|localhost):(.+)$`)
+
+// ParseRemote parses a repository URL (https, ssh, git@host:owner/repo) or a
+// ghq-style host/owner/repo or owner/repo shorthand
+func ParseRemote(remote string) (Remote, error) {
+	remote = strings.TrimSpace(remote)
+	r := Remote{URL: remote}
+
+	switch {
+	case strings.Contains(remote, "://"):
+		u, err := url.Parse(remote)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid repository URL %q: %w", remote, err)
+		}
+		r.Host, r.Path = u.Hostname(), u.Path
+	case scpLike.MatchString(remote):
+		m := scpLike.FindStringSubmatch(remote)
+		r.Host, r.Path = m[1], m[2]
+	default:
+		parts := strings.Split(strings.Trim(remote, "/"), "/")
+		if len(parts) > 2 && strings.Contains(parts[0], ".") {
+			r.Host, r.Path = parts[0], strings.Join(parts[1:], "/")
+		} else {
+			r.Host, r.Path = DefaultHost, strings.Join(parts, "/")
+		}
+		r.URL = "https://" + r.Host + "/" + r.Path
+	}
+
+	r.Path = strings.TrimSuffix(strings.Trim(r.Path, "/"), ".git")
+	if r.Host == "" || r.Path == "" || !strings.Contains(r.Path, "/") {
+		return Remote{}, fmt.Errorf("invalid repository %q (expected a URL or owner/repo)", remote)
+	}
+	for _, part := range strings.Split(r.Path, "/") {
+		if part == "" || part == "." || part == ".." {
+			return Remote{}, fmt.Errorf("invalid repository path %q", r.Path)
+		}
+	}
+	return r, nil
+}
+
+// Name returns the repository name, the last element of its path
+func (r Remote) Name() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
+
+// WebURL returns the repository's page, assuming the host serves it over https
+func (r Remote) WebURL() string {
+	return "https://" + r.Host + "/" + r.Path
+}
+
+// Origin returns the project's origin remote
+func Origin(projectPath string) (Remote, error) {
+	out, err := sys.Command("git", "-C", projectPath, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return Remote{}, fmt.Errorf("no origin remote")
+	}
+	return ParseRemote(strings.TrimSpace(string(out)))
+}
+
+// Clone clones a repository into dest, creating its parent directories
+func Clone(url, dest string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+
+	cmd := sys.Command("git", "clone", url, dest)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return strings.TrimSpace(out.String()), err
+}
+
+// Export copies the files of a repository's default branch, or of ref, into
+// dest without its history, like degit. It fails rather than prompting for
+// credentials.
+func Export(url, ref, dest string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+
+	args := []string{"clone", "--depth", "1", "--single-branch"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	cmd := BackgroundCommand(context.Background(), filepath.Dir(dest), append(args, "--", url, dest)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		return strings.TrimSpace(out.String()), err
+	}
+	return strings.TrimSpace(out.String()), os.RemoveAll(filepath.Join(dest, ".git"))
+}
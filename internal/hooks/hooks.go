@@ -0,0 +1,116 @@
+// Package hooks runs the shell commands users configure to run before actions,
+// after opening a project and after scanning
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+// Hook names, as they appear in the config
+const (
+	PreAction = "preAction"
+	PostOpen  = "postOpen"
+	PostScan  = "postScan"
+)
+
+// Timeout stops hooks that hang, e.g. a VPN check without a network
+const Timeout = 30 * time.Second
+
+// Commands returns the commands configured for a hook: the global one, then
+// the project's own. projectName may be empty for hooks not about a project.
+func Commands(cfg config.HooksConfig, hook, projectName string) []string {
+	var commands []string
+	var projectHooks config.ProjectHooks
+	if projectName != "" {
+		projectHooks, _ = config.LookupFold(cfg.Projects, projectName)
+	}
+
+	switch hook {
+	case PreAction:
+		commands = []string{cfg.PreAction, projectHooks.PreAction}
+	case PostOpen:
+		commands = []string{cfg.PostOpen, projectHooks.PostOpen}
+	case PostScan:
+		commands = []string{cfg.PostScan}
+	}
+
+	configured := commands[:0]
+	for _, c := range commands {
+		if strings.TrimSpace(c) != "" {
+			configured = append(configured, c)
+		}
+	}
+	return configured
+}
+
+// Env returns the variables describing a project to its hooks
+func Env(proj *project.Project) []string {
+	return []string{
+		"PROJ_NAME=" + proj.Name,
+		"PROJ_PATH=" + proj.Path,
+		"PROJ_LANGUAGE=" + proj.Language,
+		"PROJ_BRANCH=" + proj.GitBranch,
+	}
+}
+
+// Run runs a hook's commands in order with the configured shell, stopping at
+// the first that fails. Hooks about a project run in its directory with its
+// details in the environment; proj is nil otherwise. env adds variables.
+func Run(cfg *config.Config, hook string, proj *project.Project, env ...string) error {
+	name, dir := "", config.ExpandPath(cfg.ReposPath)
+	vars := []string{"PROJ_HOOK=" + hook}
+	if proj != nil {
+		name, dir = proj.Name, proj.Path
+		vars = append(vars, Env(proj)...)
+	}
+	vars = append(vars, env...)
+
+	for _, command := range Commands(cfg.Hooks, hook, name) {
+		if err := run(shell(cfg), command, dir, vars); err != nil {
+			return fmt.Errorf("%s hook failed: %w", hook, err)
+		}
+	}
+	return nil
+}
+
+// run runs one hook command, returning its output with the error
+func run(shell, command, dir string, env []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, shell, "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s: timed out after %s", command, Timeout)
+	}
+	if err != nil {
+		if output := strings.TrimSpace(out.String()); output != "" {
+			return fmt.Errorf("%s: %v\n%s", command, err, output)
+		}
+		return fmt.Errorf("%s: %v", command, err)
+	}
+	return nil
+}
+
+// shell returns the shell hooks run with
+func shell(cfg *config.Config) string {
+	if cfg.Shell != "" {
+		return cfg.Shell
+	}
+	return "/bin/sh"
+}
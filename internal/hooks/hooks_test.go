@@ -0,0 +1,74 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestCommands(t *testing.T) {
+	cfg := config.HooksConfig{
+		PreAction: "vpn-check",
+		PostOpen:  "  ",
+		PostScan:  "notify scanned",
+		Projects: map[string]config.ProjectHooks{
+			// viper lower-cases map keys
+			"webapp": {PreAction: "docker info", PostOpen: "notify opened"},
+		},
+	}
+
+	tests := []struct {
+		hook    string
+		project string
+		want    []string
+	}{
+		{PreAction, "WebApp", []string{"vpn-check", "docker info"}},
+		{PreAction, "other", []string{"vpn-check"}},
+		{PostOpen, "webapp", []string{"notify opened"}},
+		{PostOpen, "other", []string{}},
+		{PostScan, "", []string{"notify scanned"}},
+	}
+
+	for _, tt := range tests {
+		got := Commands(cfg, tt.hook, tt.project)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Commands(%s, %q) = %q, want %q", tt.hook, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "hook.log")
+	proj := &project.Project{Name: "webapp", Path: dir, Language: "Go", GitBranch: "main"}
+
+	cfg := config.DefaultConfig()
+	cfg.Shell = "/bin/sh"
+	cfg.Hooks.PreAction = `echo "$PROJ_HOOK $PROJ_NAME $PROJ_LANGUAGE $PROJ_BRANCH $PROJ_ACTION $(pwd)" > ` + out
+
+	if err := Run(cfg, PreAction, proj, "PROJ_ACTION=run-tests"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "preAction webapp Go main run-tests " + dir
+	if got := strings.TrimSpace(string(data)); got != want {
+		t.Errorf("hook saw %q, want %q", got, want)
+	}
+
+	cfg.Hooks.Projects = map[string]config.ProjectHooks{"webapp": {PreAction: "echo not on VPN; exit 3"}}
+	err = Run(cfg, PreAction, proj)
+	if err == nil {
+		t.Fatal("expected the failing project hook to fail the run")
+	}
+	if !strings.Contains(err.Error(), "not on VPN") {
+		t.Errorf("error should include the hook output, got %v", err)
+	}
+}
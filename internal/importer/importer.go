@@ -0,0 +1,381 @@
+// Package importer reads the project lists of other project managers so
+// users can bring them into proj
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+// Sources are the tools projects can be imported from
+var Sources = []string{"ghq", "vscode", "projectile"}
+
+// Result is what an import found
+type Result struct {
+	Roots    []string // Directories holding projects, scanned like reposPath
+	Projects []string // Individual project directories
+}
+
+// Empty reports whether nothing was found
+func (r Result) Empty() bool {
+	return len(r.Roots) == 0 && len(r.Projects) == 0
+}
+
+// Import reads the projects of a tool. files optionally names the tool's
+// files to read instead of the default locations (VS Code .code-workspace
+// files, a projectile bookmarks file).
+func Import(source string, files []string) (Result, error) {
+	switch strings.ToLower(source) {
+	case "ghq":
+		return GHQ()
+	case "vscode", "code":
+		return VSCode(files)
+	case "projectile":
+		return Projectile(files)
+	default:
+		return Result{}, fmt.Errorf("unknown import source %q (use %s)", source, strings.Join(Sources, ", "))
+	}
+}
+
+// GHQ returns ghq's roots, asking ghq itself when installed and otherwise
+// reading ghq.root from the git config
+func GHQ() (Result, error) {
+	var roots []string
+	if out, err := exec.Command("ghq", "root", "--all").Output(); err == nil {
+		roots = lines(out)
+	} else if out, err := exec.Command("git", "config", "--path", "--get-all", "ghq.root").Output(); err == nil {
+		roots = lines(out)
+	}
+	if len(roots) == 0 {
+		roots = []string{"~/ghq"}
+	}
+
+	r := Result{Roots: existingDirs(roots)}
+	if r.Empty() {
+		return r, fmt.Errorf("no ghq root found (is ghq set up?)")
+	}
+	return r, nil
+}
+
+// VSCode returns the folders of workspace files, or when none are given the
+// folders and workspaces VS Code recently opened
+func VSCode(workspaceFiles []string) (Result, error) {
+	var folders []string
+	if len(workspaceFiles) > 0 {
+		for _, file := range workspaceFiles {
+			found, err := workspaceFolders(file)
+			if err != nil {
+				return Result{}, err
+			}
+			folders = append(folders, found...)
+		}
+		return Result{Projects: existingDirs(folders)}, nil
+	}
+
+	data, err := vscodeRecent()
+	if err != nil {
+		return Result{}, err
+	}
+	folders, workspaces, err := parseVSCodeRecent(data)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, file := range workspaces {
+		// Workspaces that have since been deleted are skipped
+		if found, err := workspaceFolders(file); err == nil {
+			folders = append(folders, found...)
+		}
+	}
+	return Result{Projects: existingDirs(folders)}, nil
+}
+
+// vscodeDirs are the user data directories of VS Code and its variants
+var vscodeDirs = []string{"Code", "Code - Insiders", "VSCodium"}
+
+// vscodeRecent returns VS Code's recently opened list. Current versions keep
+// it in a SQLite database, read with the sqlite3 CLI; older ones in storage.json.
+func vscodeRecent() ([]byte, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range vscodeDirs {
+		storage := filepath.Join(configDir, dir, "User", "globalStorage")
+
+		db := filepath.Join(storage, "state.vscdb")
+		if _, err := os.Stat(db); err == nil {
+			if _, err := exec.LookPath("sqlite3"); err != nil {
+				return nil, fmt.Errorf("reading %s needs the sqlite3 CLI; pass .code-workspace files instead", db)
+			}
+			out, err := exec.Command("sqlite3", "-readonly", db,
+				"SELECT value FROM ItemTable WHERE key = 'history.recentlyOpenedPathsList'").Output()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", db, err)
+			}
+			return out, nil
+		}
+
+		data, err := os.ReadFile(filepath.Join(storage, "storage.json"))
+		if err == nil {
+			var storageJSON struct {
+				OpenedPathsList json.RawMessage `json:"openedPathsList"`
+			}
+			if err := json.Unmarshal(data, &storageJSON); err != nil {
+				return nil, fmt.Errorf("failed to parse VS Code storage.json: %w", err)
+			}
+			return storageJSON.OpenedPathsList, nil
+		}
+	}
+	return nil, fmt.Errorf("no VS Code history found in %s", configDir)
+}
+
+// parseVSCodeRecent returns the local folders and workspace files in VS
+// Code's recently opened list. Remote folders are skipped.
+func parseVSCodeRecent(data []byte) (folders, workspaces []string, err error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil, nil
+	}
+	var recent struct {
+		Entries []struct {
+			FolderURI string `json:"folderUri"`
+			Workspace *struct {
+				ConfigPath string `json:"configPath"`
+			} `json:"workspace"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &recent); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse VS Code history: %w", err)
+	}
+
+	for _, e := range recent.Entries {
+		if path, ok := fileURIPath(e.FolderURI); ok {
+			folders = append(folders, path)
+		}
+		if e.Workspace != nil {
+			if path, ok := fileURIPath(e.Workspace.ConfigPath); ok {
+				workspaces = append(workspaces, path)
+			}
+		}
+	}
+	return folders, workspaces, nil
+}
+
+// fileURIPath converts a file:// URI to a local path
+func fileURIPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	path := u.Path
+	// file:///c:/Users/... on Windows
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), true
+}
+
+// workspaceFolders returns the folders of a .code-workspace file. Relative
+// folders are relative to the file.
+func workspaceFolders(file string) ([]string, error) {
+	data, err := os.ReadFile(config.ExpandPath(file))
+	if err != nil {
+		return nil, err
+	}
+	var workspace struct {
+		Folders []struct {
+			Path string `json:"path"`
+			URI  string `json:"uri"`
+		} `json:"folders"`
+	}
+	// Workspace files are JSON with comments and trailing commas
+	if err := json.Unmarshal(stripJSONC(data), &workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	var folders []string
+	for _, f := range workspace.Folders {
+		path := f.Path
+		if path == "" {
+			var ok bool
+			if path, ok = fileURIPath(f.URI); !ok {
+				continue
+			}
+		}
+		path = config.ExpandPath(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(config.ExpandPath(file)), path)
+		}
+		folders = append(folders, path)
+	}
+	return folders, nil
+}
+
+var (
+	jsoncComments = regexp.MustCompile(`(?m)("(?:[^"\\]|\\.)*")|//[^\n]*|/\*[\s\S]*?\*/`)
+	jsoncTrailing = regexp.MustCompile(`,(\s*[}\]])`)
+)
+
+// stripJSONC removes comments and trailing commas, keeping strings intact
+func stripJSONC(data []byte) []byte {
+	data = jsoncComments.ReplaceAll(data, []byte("$1"))
+	return jsoncTrailing.ReplaceAll(data, []byte("$1"))
+}
+
+// projectileBookmarks are where Emacs, Spacemacs and Doom keep projectile's
+// known projects, relative to the home directory
+var projectileBookmarks = []string{
+	".emacs.d/projectile-bookmarks.eld",
+	".config/emacs/projectile-bookmarks.eld",
+	".emacs.d/.cache/projectile-bookmarks.eld",
+	".emacs.d/.local/cache/projectile.projects",
+	".config/emacs/.local/cache/projectile.projects",
+}
+
+// Projectile returns the projects in projectile's bookmarks
+func Projectile(files []string) (Result, error) {
+	if len(files) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Result{}, err
+		}
+		for _, rel := range projectileBookmarks {
+			if path := filepath.Join(home, rel); fileExists(path) {
+				files = append(files, path)
+			}
+		}
+		if len(files) == 0 {
+			return Result{}, fmt.Errorf("no projectile bookmarks found (looked in ~/%s)", strings.Join(projectileBookmarks, ", ~/"))
+		}
+	}
+
+	var projects []string
+	for _, file := range files {
+		data, err := os.ReadFile(config.ExpandPath(file))
+		if err != nil {
+			return Result{}, err
+		}
+		projects = append(projects, parseProjectile(data)...)
+	}
+	return Result{Projects: existingDirs(projects)}, nil
+}
+
+var (
+	elispString = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+	trampPath   = regexp.MustCompile(`^/[a-z]+:`)
+)
+
+// parseProjectile returns the paths in a projectile bookmarks file, an Emacs
+// Lisp list of strings
+func parseProjectile(data []byte) []string {
+	var paths []string
+	for _, m := range elispString.FindAllSubmatch(data, -1) {
+		path := strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(string(m[1]))
+		// Remote (TRAMP) projects can't be scanned
+		if trampPath.MatchString(path) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// Merge adds what an import found to the config and returns what was new.
+// Directories already known, or that a scan of an existing root already
+// finds, are left out.
+func Merge(cfg *config.Config, r Result) Result {
+	var roots []string
+	for _, root := range append([]string{cfg.ReposPath}, cfg.ExtraRoots...) {
+		roots = append(roots, clean(root))
+	}
+	known := make(map[string]bool)
+	for _, root := range roots {
+		known[root] = true
+	}
+	for _, p := range cfg.ExtraProjects {
+		known[clean(p)] = true
+	}
+
+	var added Result
+	for _, root := range r.Roots {
+		root = clean(root)
+		if known[root] {
+			continue
+		}
+		known[root] = true
+		roots = append(roots, root)
+		cfg.ExtraRoots = append(cfg.ExtraRoots, root)
+		added.Roots = append(added.Roots, root)
+	}
+
+	for _, p := range r.Projects {
+		p = clean(p)
+		if known[p] || scannedUnder(p, roots) {
+			continue
+		}
+		known[p] = true
+		cfg.ExtraProjects = append(cfg.ExtraProjects, p)
+		added.Projects = append(added.Projects, p)
+	}
+	return added
+}
+
+// scannedUnder reports whether scanning one of roots finds path: the scanner
+// looks at projects and group children, two levels down
+func scannedUnder(path string, roots []string) bool {
+	parent := filepath.Dir(path)
+	for _, root := range roots {
+		if parent == root || filepath.Dir(parent) == root {
+			return true
+		}
+	}
+	return false
+}
+
+// clean expands ~ and cleans a path
+func clean(path string) string {
+	return filepath.Clean(config.ExpandPath(path))
+}
+
+// existingDirs cleans paths, keeping each existing directory once
+func existingDirs(paths []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, path := range paths {
+		path = clean(strings.TrimSpace(path))
+		if seen[path] {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			seen[path] = true
+			dirs = append(dirs, path)
+		}
+	}
+	return dirs
+}
+
+// lines splits command output into non-empty lines
+func lines(out []byte) []string {
+	var result []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
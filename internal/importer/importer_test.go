@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+func TestParseVSCodeRecent(t *testing.T) {
+	data := []byte(`{"entries": [
+		{"folderUri": "file:///home/me/code/api"},
+		{"folderUri": "vscode-remote://ssh-remote%2Bbox/srv/app"},
+		{"workspace": {"id": "1", "configPath": "file:///home/me/work.code-workspace"}},
+		{"fileUri": "file:///home/me/notes.md"}
+	]}`)
+
+	folders, workspaces, err := parseVSCodeRecent(data)
+	if err != nil {
+		t.Fatalf("parseVSCodeRecent failed: %v", err)
+	}
+	if want := []string{filepath.FromSlash("/home/me/code/api")}; !reflect.DeepEqual(folders, want) {
+		t.Errorf("folders = %q, want %q", folders, want)
+	}
+	if want := []string{filepath.FromSlash("/home/me/work.code-workspace")}; !reflect.DeepEqual(workspaces, want) {
+		t.Errorf("workspaces = %q, want %q", workspaces, want)
+	}
+}
+
+func TestWorkspaceFolders(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "work.code-workspace")
+	content := `{
+	// Folders of the workspace
+	"folders": [
+		{"path": "api"},
+		{"name": "web", "path": "/srv/web"}, /* absolute */
+	],
+	"settings": {"files.exclude": {"**/.git": true}},
+}`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	folders, err := workspaceFolders(file)
+	if err != nil {
+		t.Fatalf("workspaceFolders failed: %v", err)
+	}
+	want := []string{filepath.Join(dir, "api"), "/srv/web"}
+	if !reflect.DeepEqual(folders, want) {
+		t.Errorf("workspaceFolders() = %q, want %q", folders, want)
+	}
+}
+
+func TestParseProjectile(t *testing.T) {
+	data := []byte(`("~/code/api/" "/home/me/dotfiles/" "/ssh:box:/srv/app/" "/home/me/with \"quote\"/")`)
+
+	got := parseProjectile(data)
+	want := []string{"~/code/api/", "/home/me/dotfiles/", `/home/me/with "quote"/`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProjectile() = %q, want %q", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ReposPath = "/code"
+	cfg.ExtraRoots = []string{"/ghq"}
+	cfg.ExtraProjects = []string{"/home/me/dotfiles"}
+
+	added := Merge(cfg, Result{
+		Roots: []string{"/ghq", "/work"},
+		Projects: []string{
+			"/code/api",              // Under reposPath
+			"/code/group/lib",        // A group child of reposPath
+			"/work/tool",             // Under a root added by this import
+			"/home/me/dotfiles/",     // Already imported
+			"/srv/app", "/srv/app/.", // Once
+		},
+	})
+
+	want := Result{Roots: []string{"/work"}, Projects: []string{"/srv/app"}}
+	if !reflect.DeepEqual(added, want) {
+		t.Errorf("Merge() added %+v, want %+v", added, want)
+	}
+	if !reflect.DeepEqual(cfg.ExtraRoots, []string{"/ghq", "/work"}) {
+		t.Errorf("ExtraRoots = %q", cfg.ExtraRoots)
+	}
+	if !reflect.DeepEqual(cfg.ExtraProjects, []string{"/home/me/dotfiles", "/srv/app"}) {
+		t.Errorf("ExtraProjects = %q", cfg.ExtraProjects)
+	}
+}
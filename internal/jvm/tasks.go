@@ -0,0 +1,290 @@
+// Package jvm enumerates the tasks of Gradle and Maven builds. Listing
+// Gradle's tasks starts a JVM and configures the whole build, so they are
+// cached until the build files change or a refresh is asked for; Maven's come
+// straight from pom.xml.
+package jvm
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+// Build tools
+const (
+	Gradle = "gradle"
+	Maven  = "maven"
+)
+
+// Task is a Gradle task or Maven phase or goal
+type Task struct {
+	Name  string `json:"name"`
+	Desc  string `json:"desc,omitempty"`
+	Group string `json:"group,omitempty"` // e.g. "Build", "Verification"
+}
+
+// gradleBuildFiles mark a Gradle build
+var gradleBuildFiles = []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}
+
+// gradleFiles configure a Gradle build; their changes invalidate its tasks
+var gradleFiles = append(gradleBuildFiles, "gradle.properties", filepath.Join("gradle", "libs.versions.toml"))
+
+// BuildTool returns Gradle or Maven for a project built with one, or ""
+func BuildTool(path string) string {
+	for _, name := range gradleBuildFiles {
+		if exists(filepath.Join(path, name)) {
+			return Gradle
+		}
+	}
+	if exists(filepath.Join(path, "pom.xml")) {
+		return Maven
+	}
+	return ""
+}
+
+// Command returns the command that runs a build tool in the project at
+// path: its wrapper if it has one, else the installed tool
+func Command(tool, path string) string {
+	wrapper, installed := "gradlew", "gradle"
+	if tool == Maven {
+		wrapper, installed = "mvnw", "mvn"
+	}
+	if runtime.GOOS == "windows" {
+		if exists(filepath.Join(path, wrapper+".bat")) {
+			return wrapper + ".bat"
+		}
+		if exists(filepath.Join(path, wrapper+".cmd")) {
+			return wrapper + ".cmd"
+		}
+		return installed
+	}
+	if exists(filepath.Join(path, wrapper)) {
+		return "./" + wrapper
+	}
+	return installed
+}
+
+// GradleTasksArgs are the arguments that list every task of a Gradle build
+var GradleTasksArgs = []string{"tasks", "--all", "--console=plain"}
+
+// gradleTask matches a task line of gradle tasks, "name - description"
+var gradleTask = regexp.MustCompile(`^([A-Za-z0-9_.:-]+)(?: - (.*))?$`)
+
+// ParseGradleTasks reads the output of gradle tasks --all. Tasks are listed
+// under "<Group> tasks" headings underlined with dashes; the rules section
+// and the text around the report are skipped.
+func ParseGradleTasks(output string) []Task {
+	var tasks []Task
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+
+	group := ""
+	for i, line := range lines {
+		if i+1 < len(lines) && isUnderline(lines[i+1]) && line != "" {
+			// A heading: tasks follow those ending in " tasks"
+			group = ""
+			if name, ok := strings.CutSuffix(line, " tasks"); ok {
+				group = name
+			}
+			continue
+		}
+		if group == "" || isUnderline(line) {
+			continue
+		}
+		if m := gradleTask.FindStringSubmatch(line); m != nil {
+			tasks = append(tasks, Task{Name: m[1], Desc: m[2], Group: group})
+		}
+	}
+	return tasks
+}
+
+// isUnderline reports whether a line is only dashes
+func isUnderline(line string) bool {
+	return line != "" && strings.Trim(line, "-") == ""
+}
+
+// mavenPhases are the lifecycle phases offered for every Maven build
+var mavenPhases = []Task{
+	{Name: "clean", Desc: "Remove the build output"},
+	{Name: "compile", Desc: "Compile the sources"},
+	{Name: "test", Desc: "Run the unit tests"},
+	{Name: "package", Desc: "Package the compiled code, e.g. as a JAR"},
+	{Name: "verify", Desc: "Run the integration tests and checks"},
+	{Name: "install", Desc: "Install the package into the local repository"},
+}
+
+// mavenRunGoals are the goals that run an application, for plugins that have one
+var mavenRunGoals = map[string]string{
+	"spring-boot-maven-plugin": "run",
+	"quarkus-maven-plugin":     "dev",
+	"jetty-maven-plugin":       "run",
+	"exec-maven-plugin":        "java",
+	"javafx-maven-plugin":      "run",
+}
+
+// pom is the part of a pom.xml tasks are read from
+type pom struct {
+	Build struct {
+		Plugins []struct {
+			ArtifactID string `xml:"artifactId"`
+			Executions []struct {
+				Goals []string `xml:"goals>goal"`
+			} `xml:"executions>execution"`
+		} `xml:"plugins>plugin"`
+	} `xml:"build"`
+	Profiles []struct {
+		ID string `xml:"id"`
+	} `xml:"profiles>profile"`
+}
+
+// MavenTasks returns the lifecycle phases of the Maven build at path, the
+// goals of its plugins and a package phase for each of its profiles
+func MavenTasks(path string) ([]Task, error) {
+	data, err := os.ReadFile(filepath.Join(path, "pom.xml"))
+	if err != nil {
+		return nil, err
+	}
+	var p pom
+	if err := xml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pom.xml: %w", err)
+	}
+
+	var tasks []Task
+	for _, phase := range mavenPhases {
+		phase.Group = "Lifecycle"
+		tasks = append(tasks, phase)
+	}
+
+	seen := map[string]bool{}
+	addGoal := func(prefix, goal string) {
+		name := prefix + ":" + goal
+		if !seen[name] {
+			seen[name] = true
+			tasks = append(tasks, Task{Name: name, Group: "Plugins"})
+		}
+	}
+	for _, plugin := range p.Build.Plugins {
+		prefix := pluginPrefix(plugin.ArtifactID)
+		if prefix == "" {
+			continue
+		}
+		if goal, ok := mavenRunGoals[plugin.ArtifactID]; ok {
+			addGoal(prefix, goal)
+		}
+		for _, execution := range plugin.Executions {
+			for _, goal := range execution.Goals {
+				addGoal(prefix, strings.TrimSpace(goal))
+			}
+		}
+	}
+
+	for _, profile := range p.Profiles {
+		if profile.ID != "" {
+			tasks = append(tasks, Task{Name: "package -P" + profile.ID, Desc: "Package with the " + profile.ID + " profile", Group: "Profiles"})
+		}
+	}
+	return tasks, nil
+}
+
+// pluginPrefix returns the goal prefix of a Maven plugin by the naming
+// convention: foo-maven-plugin and maven-foo-plugin are both "foo"
+func pluginPrefix(artifactID string) string {
+	if prefix, ok := strings.CutSuffix(artifactID, "-maven-plugin"); ok {
+		return prefix
+	}
+	if prefix, ok := strings.CutPrefix(artifactID, "maven-"); ok {
+		return strings.TrimSuffix(prefix, "-plugin")
+	}
+	return ""
+}
+
+// Cache holds the Gradle tasks of a project with the build files they were
+// listed from
+type Cache struct {
+	Fingerprint string    `json:"fingerprint"`
+	ListedAt    time.Time `json:"listedAt"`
+	Tasks       []Task    `json:"tasks"`
+}
+
+// Stale reports whether the build files of the project at path changed
+// since the tasks were listed
+func (c *Cache) Stale(path string) bool {
+	return c.Fingerprint != Fingerprint(path)
+}
+
+// Fingerprint identifies the state of a Gradle build's files by their sizes
+// and modification times
+func Fingerprint(path string) string {
+	h := sha1.New()
+	for _, name := range gradleFiles {
+		if info, err := os.Stat(filepath.Join(path, name)); err == nil {
+			fmt.Fprintf(h, "%s %d %d\n", name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachePath returns the file the Gradle tasks of the project at path are
+// cached in
+func cachePath(path string) (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(path))
+	return filepath.Join(dir, "cache", "gradle-tasks", hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// CachedTasks returns the cached Gradle tasks of the project at path, or nil
+// if they were never listed
+func CachedTasks(path string) *Cache {
+	file, err := cachePath(path)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var c Cache
+	if json.Unmarshal(data, &c) != nil {
+		return nil
+	}
+	return &c
+}
+
+// SaveTasks caches the Gradle tasks of the project at path
+func SaveTasks(path string, tasks []Task) error {
+	file, err := cachePath(path)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(Cache{Fingerprint: Fingerprint(path), ListedAt: time.Now(), Tasks: tasks}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// exists reports whether a file exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
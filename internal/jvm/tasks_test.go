@@ -0,0 +1,114 @@
+package jvm
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const gradleTasksOutput = `
+> Task :tasks
+
+------------------------------------------------------------
+Tasks runnable from root project 'demo'
+------------------------------------------------------------
+
+Application tasks
+-----------------
+run - Runs this project as a JVM application
+
+Build tasks
+-----------
+assemble - Assembles the outputs of this project.
+app:jar
+
+Rules
+-----
+Pattern: clean<TaskName>: Cleans the output files of a task.
+
+To see all tasks and more detail, run gradle tasks --all
+
+BUILD SUCCESSFUL in 2s
+`
+
+func TestParseGradleTasks(t *testing.T) {
+	want := []Task{
+		{Name: "run", Desc: "Runs this project as a JVM application", Group: "Application"},
+		{Name: "assemble", Desc: "Assembles the outputs of this project.", Group: "Build"},
+		{Name: "app:jar", Group: "Build"},
+	}
+	if got := ParseGradleTasks(gradleTasksOutput); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGradleTasks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMavenTasks(t *testing.T) {
+	dir := t.TempDir()
+	pom := `<project>
+  <build>
+    <plugins>
+      <plugin><artifactId>spring-boot-maven-plugin</artifactId></plugin>
+      <plugin>
+        <artifactId>maven-checkstyle-plugin</artifactId>
+        <executions><execution><goals><goal>check</goal></goals></execution></executions>
+      </plugin>
+      <plugin><artifactId>lombok</artifactId></plugin>
+    </plugins>
+  </build>
+  <profiles><profile><id>native</id></profile></profiles>
+</project>`
+	if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(pom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if tool := BuildTool(dir); tool != Maven {
+		t.Fatalf("BuildTool() = %q, want maven", tool)
+	}
+
+	tasks, err := MavenTasks(dir)
+	if err != nil {
+		t.Fatalf("MavenTasks failed: %v", err)
+	}
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	want := []string{"clean", "compile", "test", "package", "verify", "install", "spring-boot:run", "checkstyle:check", "package -Pnative"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("MavenTasks() = %v, want %v", names, want)
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", os.Getenv("HOME"))
+	dir := t.TempDir()
+	build := filepath.Join(dir, "build.gradle")
+	if err := os.WriteFile(build, []byte("plugins { id 'java' }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if BuildTool(dir) != Gradle || Command(Gradle, dir) != "gradle" {
+		t.Fatalf("Expected a Gradle build run with the installed gradle")
+	}
+	if CachedTasks(dir) != nil {
+		t.Fatal("Expected no tasks before they're listed")
+	}
+
+	tasks := []Task{{Name: "build", Group: "Build"}}
+	if err := SaveTasks(dir, tasks); err != nil {
+		t.Fatalf("SaveTasks failed: %v", err)
+	}
+	cache := CachedTasks(dir)
+	if cache == nil || !reflect.DeepEqual(cache.Tasks, tasks) || cache.Stale(dir) {
+		t.Fatalf("CachedTasks() = %+v, want the saved tasks, fresh", cache)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(build, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.Stale(dir) {
+		t.Error("Expected the cache to be stale once build.gradle changed")
+	}
+}
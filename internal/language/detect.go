@@ -1,9 +1,10 @@
 package language
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/s33g/proj/internal/sys"
 )
 
 // Detector defines a language detector
@@ -19,7 +20,7 @@ var detectors = []Detector{
 		Language: "Go",
 		Priority: 1,
 		Check: func(files []string) bool {
-			return contains(files, "go.mod") || contains(files, "go.sum")
+			return contains(files, "go.mod") || contains(files, "go.sum") || contains(files, "go.work")
 		},
 	},
 	{
@@ -63,7 +64,7 @@ var detectors = []Detector{
 		Language: "C#",
 		Priority: 7,
 		Check: func(files []string) bool {
-			return hasExtension(files, ".csproj", ".sln")
+			return hasExtension(files, ".csproj", ".fsproj", ".sln", ".slnx")
 		},
 	},
 	{
@@ -142,16 +143,11 @@ var detectors = []Detector{
 
 // Detect detects the primary language of a project
 func Detect(projectPath string) (string, error) {
-	entries, err := os.ReadDir(projectPath)
+	files, err := listFiles(projectPath)
 	if err != nil {
 		return "Unknown", err
 	}
 
-	files := make([]string, 0, len(entries))
-	for _, entry := range entries {
-		files = append(files, entry.Name())
-	}
-
 	// Try each detector in priority order
 	for _, detector := range detectors {
 		if detector.Check(files) {
@@ -167,6 +163,37 @@ func Detect(projectPath string) (string, error) {
 	return "Unknown", nil
 }
 
+// DetectAll returns every language a project has markers for, primary first
+// (e.g. a Go service with a TypeScript frontend)
+func DetectAll(projectPath string) ([]string, error) {
+	files, err := listFiles(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var languages []string
+	for _, detector := range detectors {
+		if detector.Check(files) {
+			languages = append(languages, detector.Language)
+		}
+	}
+	return languages, nil
+}
+
+// listFiles returns the names of the entries in a project's root
+func listFiles(projectPath string) ([]string, error) {
+	entries, err := sys.ReadDir(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	return files, nil
+}
+
 // GetIcon returns an icon for the language
 func GetIcon(language string) string {
 	icons := map[string]string{
@@ -17,6 +17,11 @@ func TestDetect(t *testing.T) {
 			files:    []string{"go.mod", "main.go"},
 			expected: "Go",
 		},
+		{
+			name:     "Go workspace",
+			files:    []string{"go.work"},
+			expected: "Go",
+		},
 		{
 			name:     "Rust project",
 			files:    []string{"Cargo.toml", "src/main.rs"},
@@ -42,6 +47,11 @@ func TestDetect(t *testing.T) {
 			files:    []string{"pom.xml", "Main.java"},
 			expected: "Java",
 		},
+		{
+			name:     "C# solution",
+			files:    []string{"Shop.slnx"},
+			expected: "C#",
+		},
 		{
 			name:     "Ruby project",
 			files:    []string{"Gemfile", "app.rb"},
@@ -188,3 +198,25 @@ func TestPriorityOrder(t *testing.T) {
 		t.Errorf("Expected TypeScript to be detected with higher priority, got %s", result)
 	}
 }
+
+func TestDetectAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "tsconfig.json"} {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte("{}"), 0644)
+	}
+
+	got, err := DetectAll(tmpDir)
+	if err != nil {
+		t.Fatalf("DetectAll failed: %v", err)
+	}
+	want := []string{"Go", "TypeScript", "JavaScript"}
+	if len(got) != len(want) {
+		t.Fatalf("DetectAll() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("DetectAll() = %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package procs runs long-lived commands, such as a project's dev server, in
+// the background: detached from proj so they outlive it, with their output
+// written to a log file
+package procs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+// tailBytes is how much of the end of a log is read for its last lines and
+// the port the process listens on
+const tailBytes = 64 * 1024
+
+// stopTimeout is how long a process gets to exit after being asked to,
+// before it's killed. A variable so tests can shorten it.
+var stopTimeout = 5 * time.Second
+
+// LogPath returns the log file for a background process of the project at
+// projectPath, in the logs directory next to the config
+func LogPath(projectPath string) (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New32a()
+	h.Write([]byte(projectPath))
+	return filepath.Join(dir, "logs", fmt.Sprintf("%s-%08x.log", filepath.Base(projectPath), h.Sum32())), nil
+}
+
+// Start starts cmd in the background in its own process group, writing its
+// output to logPath (truncated first), and returns its PID
+func Start(cmd *exec.Cmd, logPath string) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return 0, err
+	}
+	log, err := os.Create(logPath)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Fprintf(log, "$ %s\n", strings.Join(cmd.Args, " "))
+
+	cmd.Stdin = nil
+	cmd.Stdout = log
+	cmd.Stderr = log
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		log.Close()
+		return 0, err
+	}
+
+	// Reap the process when it exits, so it isn't left a zombie while proj runs
+	go func() {
+		_ = cmd.Wait()
+		log.Close()
+	}()
+	return cmd.Process.Pid, nil
+}
+
+// Running reports whether the process pid is still running
+func Running(pid int) bool {
+	return pid > 0 && running(pid)
+}
+
+// Stop asks the process pid and its process group to exit, and kills them
+// if they're still running after stopTimeout
+func Stop(pid int) error {
+	if !Running(pid) {
+		return nil
+	}
+	if err := terminate(pid); err != nil {
+		return err
+	}
+	for deadline := time.Now().Add(stopTimeout); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if !Running(pid) {
+			return nil
+		}
+	}
+	return kill(pid)
+}
+
+// Tail returns the last lines of a log
+func Tail(logPath string, lines int) (string, error) {
+	data, err := readEnd(logPath)
+	if err != nil {
+		return "", err
+	}
+	all := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.Join(all, "\n"), nil
+}
+
+var (
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	// Addresses such as http://localhost:5173 or 0.0.0.0:8000, then phrases
+	// such as "listening on port 3000"
+	addressPattern = regexp.MustCompile(`(?i)(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1?\]):(\d{2,5})\b`)
+	portPattern    = regexp.MustCompile(`(?i)\bport[: ]+(\d{2,5})\b`)
+)
+
+// Port returns the port a process says in its log that it listens on, or 0
+func Port(logPath string) int {
+	data, err := readEnd(logPath)
+	if err != nil {
+		return 0
+	}
+	// The first line is the command Start wrote, or cut off by readEnd
+	_, text, _ := strings.Cut(ansiPattern.ReplaceAllString(string(data), ""), "\n")
+	for _, pattern := range []*regexp.Regexp{addressPattern, portPattern} {
+		if match := pattern.FindStringSubmatch(text); match != nil {
+			if port, err := strconv.Atoi(match[1]); err == nil && port > 0 && port < 65536 {
+				return port
+			}
+		}
+	}
+	return 0
+}
+
+// ListeningPorts returns the TCP ports the process group led by pid listens
+// on, in order, as lsof reports them. It returns nil if lsof isn't installed.
+func ListeningPorts(pid int) []int {
+	if _, err := exec.LookPath("lsof"); err != nil || pid <= 0 {
+		return nil
+	}
+	// lsof exits 1 when nothing matches
+	out, _ := exec.Command("lsof", "-Pan", "-g", strconv.Itoa(pid), "-iTCP", "-sTCP:LISTEN", "-Fn").Output()
+	return parseLsofPorts(string(out))
+}
+
+// parseLsofPorts reads the ports from the name fields (n*:5173,
+// n127.0.0.1:5173, n[::1]:5173) of lsof -F output
+func parseLsofPorts(out string) []int {
+	seen := make(map[int]bool)
+	var ports []int
+	for _, line := range strings.Split(out, "\n") {
+		name, ok := strings.CutPrefix(line, "n")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndex(name, ":")
+		if i < 0 {
+			continue
+		}
+		if port, err := strconv.Atoi(name[i+1:]); err == nil && port > 0 && !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// readEnd reads up to tailBytes from the end of a file
+func readEnd(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > tailBytes {
+		if _, err := f.Seek(-tailBytes, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
@@ -0,0 +1,94 @@
+package procs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartAndStop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	log := filepath.Join(t.TempDir(), "logs", "web.log")
+	cmd := exec.Command("sh", "-c", "echo 'ready on http://localhost:4321'; sleep 30")
+	pid, err := Start(cmd, log)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if !Running(pid) {
+		t.Fatal("Expected the process to be running")
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); Port(log) == 0 && time.Now().Before(deadline); {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if port := Port(log); port != 4321 {
+		t.Errorf("Port() = %d, want 4321", port)
+	}
+	if out, err := Tail(log, 1); err != nil || out != "ready on http://localhost:4321" {
+		t.Errorf("Tail() = %q, %v", out, err)
+	}
+
+	if err := Stop(pid); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	// The reaper goroutine may take a moment to collect the exited process
+	for deadline := time.Now().Add(time.Second); Running(pid) && time.Now().Before(deadline); {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if Running(pid) {
+		t.Error("Expected the process to be stopped")
+	}
+}
+
+func TestPort(t *testing.T) {
+	tests := []struct {
+		log  string
+		want int
+	}{
+		{"$ cmd\n  ➜  Local:   http://localhost:\x1b[1m5173\x1b[22m/\n", 5173},
+		{"$ cmd\nStarting development server at http://127.0.0.1:8000/\n", 8000},
+		{"$ cmd\nServer listening on port 3000\n", 3000},
+		{"$ cmd\nINFO:     Uvicorn running on http://0.0.0.0:8080 (Press CTRL+C to quit)\n", 8080},
+		{"$ cmd\ncompiled successfully\n", 0},
+		{"$ vite --port 4000\n", 0}, // The command line isn't output
+	}
+	for _, tt := range tests {
+		log := filepath.Join(t.TempDir(), "server.log")
+		if err := os.WriteFile(log, []byte(tt.log), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := Port(log); got != tt.want {
+			t.Errorf("Port(%q) = %d, want %d", strings.TrimSpace(tt.log), got, tt.want)
+		}
+	}
+	if got := Port(filepath.Join(t.TempDir(), "missing.log")); got != 0 {
+		t.Errorf("Port() of a missing log = %d", got)
+	}
+}
+
+func TestParseLsofPorts(t *testing.T) {
+	out := "p4242\nf21\nn*:5173\nf22\nn[::1]:5173\np4243\nf9\nn127.0.0.1:24678\n"
+	if got, want := parseLsofPorts(out), []int{5173, 24678}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLsofPorts() = %v, want %v", got, want)
+	}
+	if got := parseLsofPorts(""); got != nil {
+		t.Errorf("parseLsofPorts(\"\") = %v", got)
+	}
+}
+
+func TestRunning(t *testing.T) {
+	if !Running(os.Getpid()) {
+		t.Error("Expected this process to be running")
+	}
+	if Running(0) || Running(-1) {
+		t.Error("Expected no process for pid 0 or -1")
+	}
+}
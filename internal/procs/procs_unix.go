@@ -0,0 +1,40 @@
+//go:build !windows
+
+package procs
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts cmd in a process group of its own, so that it can be stopped
+// along with the processes it starts and isn't sent the terminal's signals
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func running(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func terminate(pid int) error {
+	return signalGroup(pid, syscall.SIGTERM)
+}
+
+func kill(pid int) error {
+	return signalGroup(pid, syscall.SIGKILL)
+}
+
+// signalGroup signals the process group led by pid, or just pid if it
+// doesn't lead one
+func signalGroup(pid int, sig syscall.Signal) error {
+	if err := syscall.Kill(-pid, sig); err == nil || !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	if err := syscall.Kill(pid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
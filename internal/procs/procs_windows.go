@@ -0,0 +1,35 @@
+//go:build windows
+
+package procs
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// detach starts cmd in a process group of its own, so that it isn't sent the
+// console's Ctrl+C
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+func running(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
+
+// terminate ends the process and the processes it started; Windows has no
+// signal asking a console process to exit
+func terminate(pid int) error {
+	return kill(pid)
+}
+
+func kill(pid int) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
@@ -0,0 +1,100 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Match filters projects with a query made of space-separated terms, all of
+// which must match:
+//
+//	lang:go      language equals (case-insensitive, dashes for spaces)
+//	name:api     name contains
+//	branch:main  git branch equals
+//	tag:stale    has tag
+//	is:git       is a git repository (also is:dirty, is:docker)
+//	api          plain text, same as Filter
+func Match(projects []*Project, query string) ([]*Project, error) {
+	terms := strings.Fields(query)
+	for _, term := range terms {
+		if err := validateTerm(term); err != nil {
+			return nil, err
+		}
+	}
+
+	matched := make([]*Project, 0)
+	for _, p := range projects {
+		if matchesAll(p, terms) {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// validateTerm rejects unknown keys so typos don't silently match nothing
+func validateTerm(term string) error {
+	key, value, ok := strings.Cut(term, ":")
+	if !ok {
+		return nil
+	}
+
+	switch strings.ToLower(key) {
+	case "lang", "language", "name", "branch", "tag":
+		if value == "" {
+			return fmt.Errorf("filter %q needs a value", term)
+		}
+		return nil
+	case "is":
+		switch strings.ToLower(value) {
+		case "git", "dirty", "docker":
+			return nil
+		}
+		return fmt.Errorf("unknown filter %q (use is:git, is:dirty or is:docker)", term)
+	default:
+		return fmt.Errorf("unknown filter key %q (use lang, name, branch, tag or is)", key)
+	}
+}
+
+func matchesAll(p *Project, terms []string) bool {
+	for _, term := range terms {
+		if !matchesTerm(p, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTerm(p *Project, term string) bool {
+	key, value, ok := strings.Cut(term, ":")
+	if !ok {
+		return len(Filter([]*Project{p}, term)) == 1
+	}
+
+	value = strings.ToLower(value)
+	switch strings.ToLower(key) {
+	case "lang", "language":
+		lang := strings.ToLower(p.Language)
+		return lang == value || strings.ReplaceAll(lang, " ", "-") == value
+	case "name":
+		return strings.Contains(strings.ToLower(p.Name), value)
+	case "branch":
+		return strings.ToLower(p.GitBranch) == value
+	case "tag":
+		for _, tag := range p.Tags {
+			if strings.ToLower(tag) == value {
+				return true
+			}
+		}
+		return false
+	case "is":
+		switch value {
+		case "git":
+			return p.IsGitRepo
+		case "dirty":
+			return p.GitDirty
+		case "docker":
+			return p.HasDockerfile || p.HasCompose
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package project
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	projects := []*Project{
+		{Name: "api", Language: "Go", IsGitRepo: true, GitBranch: "main", GitDirty: true},
+		{Name: "web", Language: "TypeScript", IsGitRepo: true, GitBranch: "main", HasDockerfile: true},
+		{Name: "tool", Language: "Go", Tags: []string{"stale"}},
+		{Name: "notes", Language: "Git Repo", IsGitRepo: true},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"api", "web", "tool", "notes"}},
+		{"lang:go", []string{"api", "tool"}},
+		{"lang:go is:git", []string{"api"}},
+		{"branch:main is:docker", []string{"web"}},
+		{"is:dirty", []string{"api"}},
+		{"tag:stale", []string{"tool"}},
+		{"name:to", []string{"tool"}},
+		{"typescript", []string{"web"}},
+		{"lang:git-repo", []string{"notes"}},
+	}
+
+	for _, tt := range tests {
+		matched, err := Match(projects, tt.query)
+		if err != nil {
+			t.Errorf("Match(%q) returned error: %v", tt.query, err)
+			continue
+		}
+		names := make([]string, len(matched))
+		for i, p := range matched {
+			names[i] = p.Name
+		}
+		if len(names) != len(tt.expected) {
+			t.Errorf("Match(%q) = %v, want %v", tt.query, names, tt.expected)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.expected[i] {
+				t.Errorf("Match(%q) = %v, want %v", tt.query, names, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestMatch_InvalidTerms(t *testing.T) {
+	for _, query := range []string{"lnag:go", "is:fast", "lang:"} {
+		if _, err := Match(nil, query); err == nil {
+			t.Errorf("Expected error for %q", query)
+		}
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	projects := []*Project{
+		{Name: "api", Language: "Go", GitDirty: true},
+		{Name: "web", Language: "TypeScript", HasCompose: true},
+		{Name: "tool", Language: "Go", GitDirty: true},
+		{Name: "lib", Language: "Rust"},
+		{Name: "site", Language: "Python", HasDockerfile: true},
+		{Name: "misc", Language: "Unknown"},
+	}
+
+	stats := ComputeStats(projects)
+	if stats.Total != 6 || stats.Dirty != 2 || stats.Docker != 2 {
+		t.Errorf("ComputeStats() = %d total, %d dirty, %d docker; want 6, 2, 2", stats.Total, stats.Dirty, stats.Docker)
+	}
+
+	top := stats.TopLanguages(3)
+	want := []LanguageCount{{"Go", 2}, {"Python", 1}, {"Rust", 1}}
+	if len(top) != len(want) {
+		t.Fatalf("TopLanguages(3) = %v, want %v", top, want)
+	}
+	for i := range want {
+		if top[i] != want[i] {
+			t.Errorf("TopLanguages(3) = %v, want %v", top, want)
+			break
+		}
+	}
+
+	if got := LanguageQuery("Git Repo"); got != "lang:git-repo" {
+		t.Errorf("LanguageQuery() = %q, want lang:git-repo", got)
+	}
+}
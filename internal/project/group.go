@@ -0,0 +1,99 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// GroupConfigFile holds settings for the projects of a group folder
+const GroupConfigFile = ".proj-group.json"
+
+// GroupConfig are settings a group folder applies to its projects
+type GroupConfig struct {
+	Editor  string   `json:"editor"`  // Editor alias projects open with instead of editor.default
+	Tags    []string `json:"tags"`    // Added to every project in the group
+	Exclude []string `json:"exclude"` // Names or glob patterns of directories to leave out
+	Sort    SortBy   `json:"sort"`    // Order of projects within the group, whatever the list is sorted by
+}
+
+// LoadGroupConfig reads the group config of a directory. It returns nil
+// without an error when the directory has none.
+func LoadGroupConfig(dir string) (*GroupConfig, error) {
+	data, err := sys.ReadFile(filepath.Join(dir, GroupConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg GroupConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	switch cfg.Sort {
+	case "", SortByName, SortByLastModified, SortByLanguage, SortByFrecency:
+	default:
+		return nil, fmt.Errorf("unknown sort %q (use name, lastModified, language or frecency)", cfg.Sort)
+	}
+	return &cfg, nil
+}
+
+// Excludes reports whether the group leaves out a directory
+func (g *GroupConfig) Excludes(name string) bool {
+	for _, pattern := range g.Exclude {
+		if pattern == name {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply applies the group's settings to one of its projects
+func (g *GroupConfig) Apply(p *Project) {
+	if g.Editor != "" {
+		p.Editor = g.Editor
+	}
+	p.Tags = MergeTags(p.Tags, g.Tags)
+}
+
+// MergeTags returns tags with extra appended, leaving out duplicates
+func MergeTags(tags, extra []string) []string {
+	merged := append([]string(nil), tags...)
+	for _, tag := range extra {
+		seen := false
+		for _, t := range merged {
+			if t == tag {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// loadGroupConfig loads a group's config, recording a diagnostic if it's invalid
+func (s *Scanner) loadGroupConfig(dir string) *GroupConfig {
+	cfg, err := LoadGroupConfig(dir)
+	if err != nil {
+		path := filepath.Join(dir, GroupConfigFile)
+		s.diagnostics = append(s.diagnostics, Diagnostic{
+			Kind:       DiagnosticGroupConfig,
+			Path:       path,
+			Message:    fmt.Sprintf("invalid group config %s: %v", path, err),
+			Suggestion: "Fix the file; until then the group's settings are ignored",
+		})
+	}
+	return cfg
+}
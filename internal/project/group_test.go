@@ -0,0 +1,77 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+func TestScanner_GroupConfig(t *testing.T) {
+	root := t.TempDir()
+	group := filepath.Join(root, "work")
+	for _, name := range []string{"api", "web", "old-api", "tmp"} {
+		os.MkdirAll(filepath.Join(group, name), 0755)
+	}
+	// Sorted by name within the group, although the list is sorted by date
+	os.Chtimes(filepath.Join(group, "api"), time.Now(), time.Now().Add(-time.Hour))
+	os.WriteFile(filepath.Join(group, GroupConfigFile), []byte(`{
+		"editor": "goland",
+		"tags": ["work", "team"],
+		"exclude": ["old-*", "tmp"],
+		"sort": "name"
+	}`), 0644)
+
+	found, err := NewScanner(config.DefaultConfig()).Scan(root)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	found = Sort(found, SortByLastModified)
+
+	var names []string
+	for _, p := range found {
+		names = append(names, p.Name)
+	}
+	if want := []string{"work", "api", "web"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Scan() found %v, want %v", names, want)
+	}
+	if found[0].ChildSort != SortByName {
+		t.Errorf("group ChildSort = %q, want name", found[0].ChildSort)
+	}
+	for _, p := range found[1:] {
+		if p.Editor != "goland" || !reflect.DeepEqual(p.Tags, []string{"work", "team"}) {
+			t.Errorf("%s: editor %q, tags %v; want goland and the group's tags", p.Name, p.Editor, p.Tags)
+		}
+	}
+}
+
+func TestScanner_InvalidGroupConfig(t *testing.T) {
+	root := t.TempDir()
+	group := filepath.Join(root, "work")
+	os.MkdirAll(filepath.Join(group, "api"), 0755)
+	os.WriteFile(filepath.Join(group, GroupConfigFile), []byte(`{"sort": "size"}`), 0644)
+
+	scanner := NewScanner(config.DefaultConfig())
+	found, err := scanner.Scan(root)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(found) != 2 || found[0].ChildSort != "" {
+		t.Errorf("an invalid group config should be ignored, got %d entries, sort %q", len(found), found[0].ChildSort)
+	}
+
+	diags := scanner.Diagnostics()
+	if len(diags) != 1 || diags[0].Kind != DiagnosticGroupConfig {
+		t.Errorf("expected a group-config diagnostic, got %+v", diags)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"stale", "work"}, []string{"work", "team"})
+	if want := []string{"stale", "work", "team"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTags() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// scanGHQ scans a root laid out as host/owner/repo. Each owner becomes a
+// "host/owner" group of "owner/repo" projects. Projects found directly under
+// the root or a host are listed as they are.
+func (s *Scanner) scanGHQ(basePath string) ([]*Project, error) {
+	hosts, err := s.readDirs(basePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("cannot read reposPath %s: permission denied", basePath)
+		}
+		return nil, err
+	}
+
+	projects := make([]*Project, 0)
+	for _, host := range hosts {
+		if !s.visit() {
+			return nil, s.limitError(basePath)
+		}
+		if isProjectRoot(host.path) {
+			if proj, err := s.scanProject(host.name, host.path, 0); err == nil {
+				proj.SymlinkTarget = host.symlinkTarget
+				projects = append(projects, proj)
+			} else {
+				s.recordError(host.path, err)
+			}
+			continue
+		}
+
+		owners, err := s.readDirs(host.path)
+		if err != nil {
+			s.recordError(host.path, err)
+			continue
+		}
+		for _, owner := range owners {
+			if !s.visit() {
+				return nil, s.limitError(basePath)
+			}
+			name := host.name + "/" + owner.name
+
+			if isProjectRoot(owner.path) {
+				if proj, err := s.scanProject(name, owner.path, 0); err == nil {
+					proj.SymlinkTarget = owner.symlinkTarget
+					projects = append(projects, proj)
+				} else {
+					s.recordError(owner.path, err)
+				}
+				continue
+			}
+
+			repos, groupCfg := s.findChildProjects(owner.path)
+			if s.scanned > s.maxProjects {
+				return nil, s.limitError(basePath)
+			}
+			if len(repos) == 0 {
+				continue
+			}
+
+			group := &Project{
+				Name:            name,
+				Path:            owner.path,
+				IsGroup:         true,
+				SubProjectCount: len(repos),
+				Expanded:        true,
+				SymlinkTarget:   owner.symlinkTarget,
+			}
+			if groupCfg != nil {
+				group.ChildSort = groupCfg.Sort
+			}
+			if info, err := sys.Stat(owner.path); err == nil {
+				group.LastModified = info.ModTime()
+			}
+			projects = append(projects, group)
+
+			for _, repo := range repos {
+				repo.Name = owner.name + "/" + repo.Name
+				repo.ParentPath = owner.path
+				repo.Depth = 1
+				projects = append(projects, repo)
+			}
+		}
+	}
+	return projects, nil
+}
+
+// dirEntry is a directory the scanner should look at
+type dirEntry struct {
+	name          string
+	path          string
+	symlinkTarget string
+}
+
+// readDirs lists the directories in path the scanner doesn't skip (hidden,
+// excluded, broken symlinks)
+func (s *Scanner) readDirs(path string) ([]dirEntry, error) {
+	entries, err := sys.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]dirEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !s.showHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if s.isExcluded(entry.Name()) {
+			continue
+		}
+		dirPath := filepath.Join(path, entry.Name())
+		symlinkTarget, ok := s.resolveDir(dirPath, entry)
+		if !ok {
+			continue
+		}
+		dirs = append(dirs, dirEntry{name: entry.Name(), path: dirPath, symlinkTarget: symlinkTarget})
+	}
+	return dirs, nil
+}
+
+// ClonePath returns where a repository is cloned: reposPath/host/owner/repo
+// with the ghq layout, reposPath/repo otherwise
+func ClonePath(cfg *config.Config, remote git.Remote) string {
+	root := config.ExpandPath(cfg.ReposPath)
+	if strings.EqualFold(cfg.Layout, config.LayoutGHQ) {
+		return filepath.Join(root, remote.Host, filepath.FromSlash(remote.Path))
+	}
+	return filepath.Join(root, remote.Name())
+}
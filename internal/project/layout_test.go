@@ -0,0 +1,100 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/sys"
+)
+
+func TestScanner_GHQLayout(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"github.com/s33g/proj",
+		"github.com/s33g/tools",
+		"gitlab.com/team/app",
+		"gitlab.com/empty",
+	} {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(root, "github.com/s33g/proj/go.mod"), []byte("module proj"), 0644)
+	// A project that isn't in host/owner/repo form
+	os.MkdirAll(filepath.Join(root, "scratch", ".git"), 0755)
+
+	cfg := config.DefaultConfig()
+	cfg.Layout = config.LayoutGHQ
+	found, err := NewScanner(cfg).Scan(root)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	type entry struct {
+		name    string
+		isGroup bool
+		parent  string
+	}
+	want := []entry{
+		{"github.com/s33g", true, ""},
+		{"s33g/proj", false, filepath.Join(root, "github.com/s33g")},
+		{"s33g/tools", false, filepath.Join(root, "github.com/s33g")},
+		{"gitlab.com/team", true, ""},
+		{"team/app", false, filepath.Join(root, "gitlab.com/team")},
+		{"scratch", false, ""},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("Scan() found %d entries, want %d", len(found), len(want))
+	}
+	for i, w := range want {
+		p := found[i]
+		if p.Name != w.name || p.IsGroup != w.isGroup || p.ParentPath != w.parent {
+			t.Errorf("entry %d = {%s group:%v parent:%s}, want %+v", i, p.Name, p.IsGroup, p.ParentPath, w)
+		}
+	}
+	if found[1].Language != "Go" {
+		t.Errorf("s33g/proj language = %q, want Go", found[1].Language)
+	}
+}
+
+func TestScanner_GHQLayoutFS(t *testing.T) {
+	t.Cleanup(sys.UseFS(sys.FromFS(fstest.MapFS{
+		"code/github.com/s33g/proj/go.mod":      {Data: []byte("module proj")},
+		"code/github.com/s33g/web/package.json": {Data: []byte("{}")},
+		"code/github.com/s33g/web/Dockerfile":   {Data: []byte("FROM node")},
+	})))
+
+	cfg := config.DefaultConfig()
+	cfg.Layout = config.LayoutGHQ
+	found, err := NewScanner(cfg).Scan("/code")
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(found) != 3 || found[0].Name != "github.com/s33g" {
+		t.Fatalf("Scan() = %d entries, want the github.com/s33g group and its two projects", len(found))
+	}
+	proj, web := found[1], found[2]
+	if proj.Language != "Go" || web.Language == "" || !web.NeedsSetup {
+		t.Errorf("expected languages detected from the fake tree, got proj %q, web %q (needs setup %v)", proj.Language, web.Language, web.NeedsSetup)
+	}
+	if !web.HasDockerfile {
+		t.Error("expected web's Dockerfile found in the fake tree")
+	}
+}
+
+func TestClonePath(t *testing.T) {
+	remote := git.Remote{URL: "git@github.com:s33g/proj.git", Host: "github.com", Path: "s33g/proj"}
+	cfg := config.DefaultConfig()
+	cfg.ReposPath = "/code"
+
+	if got := ClonePath(cfg, remote); got != filepath.FromSlash("/code/proj") {
+		t.Errorf("flat ClonePath() = %s", got)
+	}
+
+	cfg.Layout = config.LayoutGHQ
+	if got := ClonePath(cfg, remote); got != filepath.FromSlash("/code/github.com/s33g/proj") {
+		t.Errorf("ghq ClonePath() = %s", got)
+	}
+}
@@ -1,6 +1,9 @@
 package project
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,6 +14,8 @@ import (
 	"github.com/s33g/proj/internal/docker"
 	"github.com/s33g/proj/internal/git"
 	"github.com/s33g/proj/internal/language"
+	"github.com/s33g/proj/internal/sys"
+	"github.com/s33g/proj/internal/wsl"
 )
 
 // Project represents a code project
@@ -21,50 +26,248 @@ type Project struct {
 	Language        string
 	GitBranch       string
 	GitDirty        bool
+	GitDetached     bool   // HEAD is detached
+	GitOperation    string // Rebase, merge, ... left in progress (see git.OpRebase)
+	UsesLFS         bool   // .gitattributes routes files through Git LFS
 	IsGitRepo       bool
 	LastModified    time.Time
 	HasDockerfile   bool
 	HasCompose      bool
 	Depth           int
 	SubProjectCount int
-	IsGroup         bool // True if this is a folder containing projects (not a project itself)
-	Expanded        bool // True if group is expanded to show children
+	IsGroup         bool      // True if this is a folder containing projects (not a project itself)
+	Expanded        bool      // True if group is expanded to show children
+	LastOpenedWith  string    // Editor alias (or state.OpenedInTerminal) the project was last opened with
+	LastOpenedAt    time.Time // When the project was last opened
+	SymlinkTarget   string    // Resolved target if the project directory is a symlink
+	Archived        bool      // Archived projects are kept on disk but set aside
+	Pinned          bool      // Pinned projects are listed first, whatever the sort order
+	Frecency        float64   // How often and recently it was opened (see state.ProjectState.Frecency)
+	Tags            []string  // User-assigned tags (e.g. "stale")
+	DevEnv          string    // DevEnvNix or DevEnvDirenv if the project declares its environment
+	NeedsSetup      bool      // Dependencies look uninstalled (see NeedsSetup)
+	Size            int64     // Bytes on disk, only computed when the size column is shown
+	LastCommit      time.Time // Only looked up when the lastCommit column is shown
+	Enriching       bool      // Found by Discover; Details are still loading
+	Editor          string    // Editor alias set by the group's .proj-group.json
+	ChildSort       SortBy    // Order of the group's children set by its .proj-group.json
+	Source          string    // Plugin that contributed the project; empty for projects on disk
+	Aliases         []string  // Short names from projectAliases
+	ServerPID       int       // PID of the dev server proj started, while it's running
+	ServerPort      int       // Port the running dev server listens on, if its log says
+	Workdir         string    // Subdirectory actions run in (see DetectWorkdir)
+}
+
+// Declared development environments
+const (
+	DevEnvNix    = "nix"    // flake.nix, entered with nix develop
+	DevEnvDirenv = "direnv" // .envrc, loaded with direnv exec
+)
+
+// DetectDevEnv returns the development environment a project declares, if
+// any. A Nix flake takes precedence over an .envrc.
+func DetectDevEnv(path string) string {
+	if _, err := sys.Stat(filepath.Join(path, "flake.nix")); err == nil {
+		return DevEnvNix
+	}
+	if _, err := sys.Stat(filepath.Join(path, ".envrc")); err == nil {
+		return DevEnvDirenv
+	}
+	return ""
+}
+
+// DiagnosticKind classifies issues found while scanning
+type DiagnosticKind string
+
+const (
+	DiagnosticBrokenSymlink    DiagnosticKind = "broken-symlink"
+	DiagnosticDuplicate        DiagnosticKind = "duplicate"
+	DiagnosticPermissionDenied DiagnosticKind = "permission-denied"
+	DiagnosticUnreadable       DiagnosticKind = "unreadable"
+	DiagnosticBroadRoot        DiagnosticKind = "broad-root"
+	DiagnosticWindowsMount     DiagnosticKind = "windows-mount"
+	DiagnosticGroupConfig      DiagnosticKind = "group-config"
+)
+
+// ErrTooManyProjects is returned when a scan exceeds the maxProjects limit
+var ErrTooManyProjects = errors.New("too many directories")
+
+// Diagnostic describes an entry the scanner skipped and why
+type Diagnostic struct {
+	Kind       DiagnosticKind
+	Path       string // Path of the skipped entry
+	Target     string // Symlink target or the path it duplicates
+	Message    string
+	Suggestion string // How the user can resolve the issue
 }
 
 // Scanner scans directories for projects
 type Scanner struct {
 	excludePatterns []string
 	showHidden      bool
+	maxProjects     int
+	maxDepth        int // Levels of folders scanned below each root
+	extraRoots      []string
+	extraProjects   []string
+	aliases         map[string]string // projectAliases
+	ghqLayout       bool              // Roots hold host/owner/repo directories
+	diagnostics     []Diagnostic
+	scanned         int  // Directories visited during the current scan
+	broadRoot       bool // reposPath is / or $HOME, so only the top level is scanned
+
+	skipWindowsMounts bool // Running in WSL without wsl.scanWindowsMounts
+	withSize          bool // Compute Project.Size for the size column
+	withLastCommit    bool // Look up Project.LastCommit for the lastCommit column
+	deferDetails      bool // Discover: leave Details to be filled in later
 }
 
 // NewScanner creates a new project scanner
 func NewScanner(cfg *config.Config) *Scanner {
+	maxProjects := cfg.MaxProjects
+	if maxProjects <= 0 {
+		maxProjects = config.DefaultMaxProjects
+	}
+
 	return &Scanner{
 		excludePatterns: cfg.ExcludePatterns,
 		showHidden:      cfg.Display.ShowHiddenDirs,
+		maxProjects:     maxProjects,
+		maxDepth:        cfg.Display.Depth(),
+		extraRoots:      cfg.ExtraRoots,
+		extraProjects:   cfg.ExtraProjects,
+		aliases:         cfg.ProjectAliases,
+		ghqLayout:       strings.EqualFold(cfg.Layout, config.LayoutGHQ),
+
+		skipWindowsMounts: wsl.IsWSL() && !cfg.WSL.ScanWindowsMounts,
+		withSize:          cfg.Display.HasColumn("size"),
+		withLastCommit:    cfg.Display.HasColumn("lastCommit"),
 	}
 }
 
-// Scan scans a directory for projects (1 level deep for groups)
+// Scan scans a directory for projects, and groups of them down to
+// display.maxDepth levels
 func (s *Scanner) Scan(reposPath string) ([]*Project, error) {
+	s.diagnostics = nil
+	s.scanned = 0
 	expandedPath := config.ExpandPath(reposPath)
-	return s.scanWithGroups(expandedPath)
+
+	if s.skipWindowsMounts && wsl.IsWindowsMount(expandedPath) {
+		return nil, fmt.Errorf("reposPath %s is on a Windows drive, which is slow to scan from WSL; "+
+			"move your projects into the Linux filesystem or set wsl.scanWindowsMounts to true", expandedPath)
+	}
+
+	// Scanning / or $HOME recursively can take forever, so stay at the top level
+	s.broadRoot = IsBroadRoot(expandedPath)
+	if s.broadRoot {
+		s.diagnostics = append(s.diagnostics, Diagnostic{
+			Kind:       DiagnosticBroadRoot,
+			Path:       expandedPath,
+			Message:    fmt.Sprintf("reposPath is %s; only top-level directories are scanned", expandedPath),
+			Suggestion: "Point reposPath at the folder holding your projects (proj --set-path ~/code)",
+		})
+	}
+
+	projects, err := s.scanRoot(expandedPath)
+	if err != nil {
+		return nil, err
+	}
+	projects = append(projects, s.scanExtra(expandedPath)...)
+	projects = s.dedupe(projects)
+	ApplyAliases(projects, s.aliases)
+	return projects, nil
+}
+
+// scanExtra scans the configured extra roots and projects. Unlike reposPath,
+// one that can't be read is recorded as a diagnostic rather than failing.
+func (s *Scanner) scanExtra(reposPath string) []*Project {
+	var projects []*Project
+	for _, root := range s.extraRoots {
+		root = filepath.Clean(config.ExpandPath(root))
+		if root == filepath.Clean(reposPath) {
+			continue
+		}
+		broadRoot := s.broadRoot
+		s.broadRoot = IsBroadRoot(root)
+		found, err := s.scanRoot(root)
+		s.broadRoot = broadRoot
+		if err != nil {
+			s.recordError(root, err)
+			continue
+		}
+		projects = append(projects, found...)
+	}
+
+	for _, path := range s.extraProjects {
+		path = filepath.Clean(config.ExpandPath(path))
+		if info, err := sys.Stat(path); err != nil || !info.IsDir() {
+			if err == nil {
+				err = fmt.Errorf("not a directory")
+			}
+			s.recordError(path, err)
+			continue
+		}
+		proj, err := s.scanProject(filepath.Base(path), path, 0)
+		if err != nil {
+			s.recordError(path, err)
+			continue
+		}
+		projects = append(projects, proj)
+	}
+	return projects
+}
+
+// Discover scans like Scan but only records names, paths and modification
+// times, so the list can be shown right away. Projects still missing their
+// details have Enriching set; fill them in with Details and Apply.
+func (s *Scanner) Discover(reposPath string) ([]*Project, error) {
+	s.deferDetails = true
+	defer func() { s.deferDetails = false }()
+	return s.Scan(reposPath)
+}
+
+// IsBroadRoot reports whether path is the filesystem root or the home directory
+func IsBroadRoot(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	if abs == filepath.Dir(abs) {
+		return true
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	return abs == filepath.Clean(home)
+}
+
+// Diagnostics returns the issues found during the last scan
+func (s *Scanner) Diagnostics() []Diagnostic {
+	return s.diagnostics
+}
+
+// scanRoot scans a root in the configured layout
+func (s *Scanner) scanRoot(root string) ([]*Project, error) {
+	if s.ghqLayout && !s.broadRoot {
+		return s.scanGHQ(root)
+	}
+	return s.scanWithGroups(root)
 }
 
 // scanWithGroups scans top level and detects groups vs projects
 func (s *Scanner) scanWithGroups(basePath string) ([]*Project, error) {
-	entries, err := os.ReadDir(basePath)
+	entries, err := sys.ReadDir(basePath)
 	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("cannot read reposPath %s: permission denied", basePath)
+		}
 		return nil, err
 	}
 
 	projects := make([]*Project, 0)
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
 		if !s.showHidden && strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
@@ -74,27 +277,41 @@ func (s *Scanner) scanWithGroups(basePath string) ([]*Project, error) {
 		}
 
 		dirPath := filepath.Join(basePath, entry.Name())
+		symlinkTarget, ok := s.resolveDir(dirPath, entry)
+		if !ok {
+			continue
+		}
+		if !s.visit() {
+			return nil, s.limitError(basePath)
+		}
+
 		isProject := isProjectRoot(dirPath)
 
 		// Check if this directory contains projects (making it a group)
-		childProjects := s.findChildProjects(dirPath)
+		var childProjects []*Project
+		var groupCfg *GroupConfig
+		if !s.broadRoot && s.maxDepth > 1 {
+			childProjects, groupCfg = s.findNestedProjects(dirPath, 1)
+			if s.scanned > s.maxProjects {
+				return nil, s.limitError(basePath)
+			}
+		}
 
 		if isProject {
 			// It's a project (and possibly also contains sub-projects - monorepo)
 			proj, err := s.scanProject(entry.Name(), dirPath, 0)
 			if err != nil {
+				s.recordError(dirPath, err)
 				continue
 			}
-			proj.SubProjectCount = len(childProjects)
+			proj.SubProjectCount = countChildren(childProjects, dirPath)
 			proj.Expanded = true // Projects with children are expanded by default
-			projects = append(projects, proj)
-
-			// Add child projects
-			for _, child := range childProjects {
-				child.ParentPath = dirPath
-				child.Depth = 1
-				projects = append(projects, child)
+			proj.SymlinkTarget = symlinkTarget
+			if groupCfg != nil {
+				proj.ChildSort = groupCfg.Sort
 			}
+			projects = append(projects, proj)
+			projects = append(projects, childProjects...)
 		} else if len(childProjects) > 0 {
 			// It's a group folder (not a project itself, but contains projects)
 			group := &Project{
@@ -102,29 +319,28 @@ func (s *Scanner) scanWithGroups(basePath string) ([]*Project, error) {
 				Path:            dirPath,
 				Depth:           0,
 				IsGroup:         true,
-				SubProjectCount: len(childProjects),
+				SubProjectCount: countChildren(childProjects, dirPath),
 				Expanded:        true,
+				SymlinkTarget:   symlinkTarget,
+			}
+			if groupCfg != nil {
+				group.ChildSort = groupCfg.Sort
 			}
 
 			// Get last modified from directory
-			info, _ := os.Stat(dirPath)
+			info, _ := sys.Stat(dirPath)
 			if info != nil {
 				group.LastModified = info.ModTime()
 			}
 
 			projects = append(projects, group)
-
-			// Add child projects
-			for _, child := range childProjects {
-				child.ParentPath = dirPath
-				child.Depth = 1
-				projects = append(projects, child)
-			}
+			projects = append(projects, childProjects...)
 		} else {
 			// It's a regular directory (not a project yet, but still show it)
 			// This allows users to see directories they create and potentially initialize as projects
 			proj, err := s.scanProject(entry.Name(), dirPath, 0)
 			if err != nil {
+				s.recordError(dirPath, err)
 				continue
 			}
 			// Mark as not a real project yet (no language, no git status)
@@ -132,6 +348,8 @@ func (s *Scanner) scanWithGroups(basePath string) ([]*Project, error) {
 			proj.IsGitRepo = false
 			proj.GitBranch = ""
 			proj.GitDirty = false
+			proj.Enriching = false
+			proj.SymlinkTarget = symlinkTarget
 			projects = append(projects, proj)
 		}
 		// No longer skip any directories - show all
@@ -140,35 +358,96 @@ func (s *Scanner) scanWithGroups(basePath string) ([]*Project, error) {
 	return projects, nil
 }
 
+// findNestedProjects finds the directories in a group at the given depth,
+// each followed by its own children when it's a group too. A directory that
+// isn't a project becomes a group when it holds projects or groups, down to
+// display.maxDepth levels; projects' own subdirectories (a monorepo's
+// packages) are only listed one level deep.
+func (s *Scanner) findNestedProjects(dirPath string, depth int) ([]*Project, *GroupConfig) {
+	children, groupCfg := s.findChildProjects(dirPath)
+
+	var projects []*Project
+	for _, child := range children {
+		child.ParentPath = dirPath
+		child.Depth = depth
+		projects = append(projects, child)
+		if depth+1 >= s.maxDepth || isProjectRoot(child.Path) || s.scanned > s.maxProjects {
+			continue
+		}
+
+		nested, nestedCfg := s.findNestedProjects(child.Path, depth+1)
+		if !holdsProjects(nested, child.Path) {
+			continue
+		}
+		child.IsGroup = true
+		child.Expanded = true
+		child.SubProjectCount = countChildren(nested, child.Path)
+		if nestedCfg != nil {
+			child.ChildSort = nestedCfg.Sort
+		}
+		projects = append(projects, nested...)
+	}
+	return projects, groupCfg
+}
+
+// holdsProjects reports whether any of the projects found in dir is a
+// project or group of its own, rather than a plain directory
+func holdsProjects(projects []*Project, dir string) bool {
+	for _, p := range projects {
+		if p.ParentPath == dir && (p.IsGroup || isProjectRoot(p.Path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// countChildren counts the projects directly inside dir
+func countChildren(projects []*Project, dir string) int {
+	n := 0
+	for _, p := range projects {
+		if p.ParentPath == dir {
+			n++
+		}
+	}
+	return n
+}
+
 // findChildProjects finds all directories in a directory (1 level only)
 // Shows ALL directories, not just those with project indicators
-func (s *Scanner) findChildProjects(dirPath string) []*Project {
-	entries, err := os.ReadDir(dirPath)
+func (s *Scanner) findChildProjects(dirPath string) ([]*Project, *GroupConfig) {
+	entries, err := sys.ReadDir(dirPath)
 	if err != nil {
-		return nil
+		s.recordError(dirPath, err)
+		return nil, nil
 	}
 
 	projects := make([]*Project, 0)
+	groupCfg := s.loadGroupConfig(dirPath)
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
 		if !s.showHidden && strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
-		if s.isExcluded(entry.Name()) {
+		if s.isExcluded(entry.Name()) || (groupCfg != nil && groupCfg.Excludes(entry.Name())) {
 			continue
 		}
 
 		childPath := filepath.Join(dirPath, entry.Name())
+		symlinkTarget, ok := s.resolveDir(childPath, entry)
+		if !ok {
+			continue
+		}
+		if !s.visit() {
+			return projects, groupCfg
+		}
 
 		proj, err := s.scanProject(entry.Name(), childPath, 1)
 		if err != nil {
+			s.recordError(childPath, err)
 			continue
 		}
+		proj.SymlinkTarget = symlinkTarget
 
 		// If it's not a project root, mark it as an empty directory
 		if !isProjectRoot(childPath) {
@@ -176,12 +455,16 @@ func (s *Scanner) findChildProjects(dirPath string) []*Project {
 			proj.IsGitRepo = false
 			proj.GitBranch = ""
 			proj.GitDirty = false
+			proj.Enriching = false
+		}
+		if groupCfg != nil {
+			groupCfg.Apply(proj)
 		}
 
 		projects = append(projects, proj)
 	}
 
-	return projects
+	return projects, groupCfg
 }
 
 // scanProject scans a single project directory
@@ -193,36 +476,258 @@ func (s *Scanner) scanProject(name, path string, depth int) (*Project, error) {
 	}
 
 	// Get file info for last modified time
-	info, err := os.Stat(path)
+	info, err := sys.Stat(path)
 	if err != nil {
 		return nil, err
 	}
 	project.LastModified = info.ModTime()
 
-	// Detect language
+	if s.deferDetails {
+		project.Enriching = true
+		return project, nil
+	}
+
+	project.Apply(s.Details(path))
+	return project, nil
+}
+
+// ScanProject scans the single project at path with its details, e.g. one
+// that was just created
+func (s *Scanner) ScanProject(path string) (*Project, error) {
+	return s.scanProject(filepath.Base(path), path, 0)
+}
+
+// Details are the project attributes that are slow to compute (language
+// detection, git status, ...). Discover leaves them for Details to fill in.
+type Details struct {
+	Language      string
+	IsGitRepo     bool
+	GitBranch     string
+	GitDirty      bool
+	GitDetached   bool
+	GitOperation  string
+	UsesLFS       bool
+	HasDockerfile bool
+	HasCompose    bool
+	DevEnv        string
+	NeedsSetup    bool
+	Size          int64
+	LastCommit    time.Time
+	Workdir       string
+}
+
+// Details computes the details of the project at path. It only reads the
+// filesystem and runs git, so it's safe to call from background workers.
+func (s *Scanner) Details(path string) Details {
+	var d Details
+
+	d.Workdir = DetectWorkdir(path)
+	workPath := filepath.Join(path, d.Workdir)
+
+	// Detect language, from the workdir when the project root doesn't tell
 	lang, err := language.Detect(path)
+	if (err != nil || lang == "Unknown" || lang == "Git Repo") && d.Workdir != "" {
+		lang, err = language.Detect(workPath)
+	}
 	if err == nil {
-		project.Language = lang
+		d.Language = lang
 	} else {
-		project.Language = "Unknown"
+		d.Language = "Unknown"
 	}
 
 	// Get git status
 	gitStatus, err := git.GetStatus(path)
 	if err == nil {
-		project.IsGitRepo = gitStatus.IsRepo
-		project.GitBranch = gitStatus.Branch
-		project.GitDirty = gitStatus.IsDirty
+		d.IsGitRepo = gitStatus.IsRepo
+		d.GitBranch = gitStatus.Branch
+		d.GitDirty = gitStatus.IsDirty
+		d.GitDetached = gitStatus.Detached
+		d.GitOperation = gitStatus.Operation
+	}
+	if d.IsGitRepo {
+		d.UsesLFS = git.UsesLFS(path)
 	}
 
 	// Detect Docker
 	dockerInfo, err := docker.Detect(path)
 	if err == nil {
-		project.HasDockerfile = dockerInfo.HasDockerfile
-		project.HasCompose = dockerInfo.HasCompose
+		d.HasDockerfile = dockerInfo.HasDockerfile
+		d.HasCompose = dockerInfo.HasCompose
 	}
 
-	return project, nil
+	d.DevEnv = DetectDevEnv(path)
+	d.NeedsSetup = NeedsSetup(workPath, d.Language)
+
+	// Optional columns that are too slow to compute unless they're shown
+	if s.withSize {
+		d.Size = DirSize(path)
+	}
+	if s.withLastCommit && d.IsGitRepo {
+		if t, err := git.LastCommitTime(path); err == nil {
+			d.LastCommit = t
+		}
+	}
+
+	return d
+}
+
+// Apply copies details onto the project
+func (p *Project) Apply(d Details) {
+	p.Language = d.Language
+	p.IsGitRepo = d.IsGitRepo
+	p.GitBranch = d.GitBranch
+	p.GitDirty = d.GitDirty
+	p.GitDetached = d.GitDetached
+	p.GitOperation = d.GitOperation
+	p.UsesLFS = d.UsesLFS
+	p.HasDockerfile = d.HasDockerfile
+	p.HasCompose = d.HasCompose
+	p.DevEnv = d.DevEnv
+	p.NeedsSetup = d.NeedsSetup
+	p.Size = d.Size
+	p.LastCommit = d.LastCommit
+	p.Workdir = d.Workdir
+	p.Enriching = false
+}
+
+// resolveDir decides whether a directory entry should be scanned. Plain
+// directories are accepted as-is; symlinks are resolved and accepted when they
+// point at a directory, returning the resolved target. Broken symlinks are
+// recorded as diagnostics.
+func (s *Scanner) resolveDir(path string, entry os.DirEntry) (string, bool) {
+	if entry.IsDir() {
+		return "", true
+	}
+
+	if entry.Type()&os.ModeSymlink == 0 {
+		return "", false
+	}
+
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		link, _ := sys.Readlink(path)
+		s.diagnostics = append(s.diagnostics, Diagnostic{
+			Kind:       DiagnosticBrokenSymlink,
+			Path:       path,
+			Target:     link,
+			Message:    fmt.Sprintf("broken symlink: %s → %s", filepath.Base(path), link),
+			Suggestion: fmt.Sprintf("Remove the link (rm %s) or restore its target", path),
+		})
+		return "", false
+	}
+
+	info, err := sys.Stat(target)
+	if err != nil || !info.IsDir() {
+		// Symlinks to files are not projects
+		return "", false
+	}
+
+	if s.skipWindowsMounts && wsl.IsWindowsMount(target) {
+		s.diagnostics = append(s.diagnostics, Diagnostic{
+			Kind:       DiagnosticWindowsMount,
+			Path:       path,
+			Target:     target,
+			Message:    fmt.Sprintf("skipped Windows drive link: %s → %s", filepath.Base(path), target),
+			Suggestion: "Scanning Windows drives from WSL is slow; set wsl.scanWindowsMounts to true to include it",
+		})
+		return "", false
+	}
+
+	return target, true
+}
+
+// visit counts a scanned directory and reports whether the scan may continue
+func (s *Scanner) visit() bool {
+	s.scanned++
+	return s.scanned <= s.maxProjects
+}
+
+// limitError explains why the scan stopped early
+func (s *Scanner) limitError(basePath string) error {
+	return fmt.Errorf("%w: stopped after scanning %d directories under %s; "+
+		"point reposPath at your projects folder or raise maxProjects in the config",
+		ErrTooManyProjects, s.maxProjects, basePath)
+}
+
+// recordError records a directory the scanner could not read
+func (s *Scanner) recordError(path string, err error) {
+	name := filepath.Base(path)
+
+	if errors.Is(err, fs.ErrPermission) {
+		s.diagnostics = append(s.diagnostics, Diagnostic{
+			Kind:       DiagnosticPermissionDenied,
+			Path:       path,
+			Message:    fmt.Sprintf("permission denied: %s", path),
+			Suggestion: fmt.Sprintf("Check ownership with 'ls -ld %s' or add %q to excludePatterns", path, name),
+		})
+		return
+	}
+
+	s.diagnostics = append(s.diagnostics, Diagnostic{
+		Kind:       DiagnosticUnreadable,
+		Path:       path,
+		Message:    fmt.Sprintf("unreadable: %s (%v)", path, err),
+		Suggestion: fmt.Sprintf("Add %q to excludePatterns to skip it", name),
+	})
+}
+
+// dedupe removes projects that resolve to the same directory as another
+// project (e.g. a symlink to a project that is also listed directly). The
+// entry reached without symlinks wins; skipped symlinks are recorded as
+// diagnostics.
+func (s *Scanner) dedupe(projects []*Project) []*Project {
+	byPath := make(map[string]*Project, len(projects))
+	for _, p := range projects {
+		byPath[p.Path] = p
+	}
+
+	// viaSymlink reports whether a project was reached through a symlink,
+	// either directly or through its parent group
+	viaSymlink := func(p *Project) bool {
+		if p.SymlinkTarget != "" {
+			return true
+		}
+		parent, ok := byPath[p.ParentPath]
+		return ok && parent.SymlinkTarget != ""
+	}
+
+	realPaths := make(map[*Project]string, len(projects))
+	owners := make(map[string]*Project)
+
+	for _, p := range projects {
+		real, err := filepath.EvalSymlinks(p.Path)
+		if err != nil {
+			real = p.Path
+		}
+		// Windows drives are case-insensitive
+		real = wsl.NormalizePath(real)
+		realPaths[p] = real
+
+		owner, ok := owners[real]
+		if !ok || (viaSymlink(owner) && !viaSymlink(p)) {
+			owners[real] = p
+		}
+	}
+
+	result := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		owner := owners[realPaths[p]]
+		if owner == p {
+			result = append(result, p)
+			continue
+		}
+		if p.SymlinkTarget != "" {
+			s.diagnostics = append(s.diagnostics, Diagnostic{
+				Kind:       DiagnosticDuplicate,
+				Path:       p.Path,
+				Target:     owner.Path,
+				Message:    fmt.Sprintf("duplicate symlink: %s → %s (already listed)", filepath.Base(p.Path), owner.Name),
+				Suggestion: fmt.Sprintf("Remove the symlink or add %q to excludePatterns", filepath.Base(p.Path)),
+			})
+		}
+	}
+
+	return result
 }
 
 // isExcluded checks if a directory name should be excluded
@@ -241,7 +746,9 @@ func isProjectRoot(path string) bool {
 	// Check for common project indicators
 	indicators := []string{
 		".git",           // Git repository
+		ConfigFile,       // Project settings (.proj.json)
 		"go.mod",         // Go project
+		"go.work",        // Go workspace
 		"package.json",   // Node.js/JavaScript project
 		"Cargo.toml",     // Rust project
 		"requirements.txt", // Python project
@@ -260,7 +767,7 @@ func isProjectRoot(path string) bool {
 
 	for _, indicator := range indicators {
 		indicatorPath := filepath.Join(path, indicator)
-		if _, err := os.Stat(indicatorPath); err == nil {
+		if _, err := sys.Stat(indicatorPath); err == nil {
 			return true
 		}
 	}
@@ -275,42 +782,32 @@ const (
 	SortByName         SortBy = "name"
 	SortByLastModified SortBy = "lastModified"
 	SortByLanguage     SortBy = "language"
+	SortByFrecency     SortBy = "frecency" // Most often and recently opened first
 )
 
 // Sort sorts projects by the specified criteria while preserving parent-child relationships.
-// Top-level items (Depth 0) are sorted, and children stay with their parent.
+// Top-level items (Depth 0) are sorted, and children follow their parent,
+// sorted the same way (or in the parent's ChildSort) at every level. Pinned
+// projects come first at each level, sorted among themselves the same way.
 func Sort(projects []*Project, by SortBy) []*Project {
-	// Separate top-level items and their children
-	type groupedProject struct {
-		parent   *Project
-		children []*Project
-	}
-
-	groups := make([]*groupedProject, 0)
-	groupMap := make(map[string]*groupedProject) // path -> group
-
-	// First pass: identify parents and standalone projects
+	// Attach children to their parents
+	children := make(map[string][]*Project) // parent path -> children
+	top := make([]*Project, 0)
 	for _, p := range projects {
 		if p.Depth == 0 {
-			g := &groupedProject{parent: p, children: make([]*Project, 0)}
-			groups = append(groups, g)
-			groupMap[p.Path] = g
+			top = append(top, p)
+		} else if p.ParentPath != "" {
+			children[p.ParentPath] = append(children[p.ParentPath], p)
 		}
 	}
 
-	// Second pass: attach children to their parents
-	for _, p := range projects {
-		if p.Depth > 0 && p.ParentPath != "" {
-			if g, ok := groupMap[p.ParentPath]; ok {
-				g.children = append(g.children, p)
-			}
+	// Sort the top level
+	sort.Slice(top, func(i, j int) bool {
+		pi := top[i]
+		pj := top[j]
+		if pi.Pinned != pj.Pinned {
+			return pi.Pinned
 		}
-	}
-
-	// Sort the groups by their parent
-	sortFunc := func(i, j int) bool {
-		pi := groups[i].parent
-		pj := groups[j].parent
 		switch by {
 		case SortByName:
 			return pi.Name < pj.Name
@@ -327,18 +824,37 @@ func Sort(projects []*Project, by SortBy) []*Project {
 				return pi.Name < pj.Name
 			}
 			return pi.Language < pj.Language
+		case SortByFrecency:
+			if pi.Frecency == pj.Frecency {
+				return pi.Name < pj.Name
+			}
+			return pi.Frecency > pj.Frecency
 		default:
 			return pi.Name < pj.Name
 		}
-	}
-	sort.Slice(groups, sortFunc)
+	})
 
-	// Sort children within each group
-	for _, g := range groups {
-		sort.Slice(g.children, func(i, j int) bool {
-			ci := g.children[i]
-			cj := g.children[j]
-			switch by {
+	// Flatten back into a single list, each parent followed by its children
+	result := make([]*Project, 0, len(projects))
+	var add func(p *Project, by SortBy)
+	add = func(p *Project, by SortBy) {
+		result = append(result, p)
+		kids := children[p.Path]
+		if len(kids) == 0 {
+			return
+		}
+		// Sort children in the parent's own order if it has one
+		childBy := by
+		if p.ChildSort != "" {
+			childBy = p.ChildSort
+		}
+		sort.Slice(kids, func(i, j int) bool {
+			ci := kids[i]
+			cj := kids[j]
+			if ci.Pinned != cj.Pinned {
+				return ci.Pinned
+			}
+			switch childBy {
 			case SortByName:
 				return ci.Name < cj.Name
 			case SortByLastModified:
@@ -348,22 +864,40 @@ func Sort(projects []*Project, by SortBy) []*Project {
 					return ci.Name < cj.Name
 				}
 				return ci.Language < cj.Language
+			case SortByFrecency:
+				if ci.Frecency == cj.Frecency {
+					return ci.Name < cj.Name
+				}
+				return ci.Frecency > cj.Frecency
 			default:
 				return ci.Name < cj.Name
 			}
 		})
+		for _, kid := range kids {
+			add(kid, by)
+		}
 	}
-
-	// Flatten back into a single list
-	result := make([]*Project, 0, len(projects))
-	for _, g := range groups {
-		result = append(result, g.parent)
-		result = append(result, g.children...)
+	for _, p := range top {
+		add(p, by)
 	}
 
 	return result
 }
 
+// Descendants returns the projects inside the group or project at path, at
+// any depth, in the order of projects
+func Descendants(projects []*Project, path string) []*Project {
+	inside := map[string]bool{path: true}
+	result := make([]*Project, 0)
+	for _, p := range projects {
+		if p.Depth > 0 && inside[p.ParentPath] {
+			inside[p.Path] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 // Filter filters projects by a search query
 func Filter(projects []*Project, query string) []*Project {
 	if query == "" {
@@ -376,10 +910,157 @@ func Filter(projects []*Project, query string) []*Project {
 	for _, project := range projects {
 		if strings.Contains(strings.ToLower(project.Name), lowerQuery) ||
 			strings.Contains(strings.ToLower(project.Language), lowerQuery) ||
-			strings.Contains(strings.ToLower(project.GitBranch), lowerQuery) {
+			strings.Contains(strings.ToLower(project.GitBranch), lowerQuery) ||
+			project.HasAlias(query) {
 			filtered = append(filtered, project)
 		}
 	}
 
 	return filtered
 }
+
+// Find finds a project by alias or name. An alias wins, then an exact
+// (case-insensitive) name match, otherwise the most frecent project whose
+// name contains the query is returned.
+func Find(projects []*Project, name string) *Project {
+	if candidates := Candidates(projects, name); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// Candidates returns the projects name could refer to, from the most
+// specific kind of match found: the project at that exact path, projects
+// with that alias, projects with that name (case-insensitive) or, failing
+// those, projects whose name contains it. More than one means name is
+// ambiguous; they're ordered by frecency, so the projects opened most often
+// and recently come first.
+func Candidates(projects []*Project, name string) []*Project {
+	nameLower := strings.ToLower(name)
+	matchers := append(exactMatchers(name),
+		func(p *Project) bool { return strings.Contains(strings.ToLower(p.Name), nameLower) },
+	)
+	for _, matches := range matchers {
+		var found []*Project
+		for _, p := range projects {
+			if matches(p) {
+				found = append(found, p)
+			}
+		}
+		if len(found) > 0 {
+			sort.SliceStable(found, func(i, j int) bool { return found[i].Frecency > found[j].Frecency })
+			return found
+		}
+	}
+	return nil
+}
+
+// FindExact returns the project at the path name, or with that alias or
+// name (case-insensitive), and nil if there's none or several. Unlike Find it
+// never guesses, for names that come from somewhere other than the user.
+func FindExact(projects []*Project, name string) *Project {
+	for _, matches := range exactMatchers(name) {
+		var found []*Project
+		for _, p := range projects {
+			if matches(p) {
+				found = append(found, p)
+			}
+		}
+		if len(found) == 1 {
+			return found[0]
+		}
+		if len(found) > 1 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// exactMatchers match a project at the path name, with that alias or with
+// that name, from the most specific
+func exactMatchers(name string) []func(*Project) bool {
+	return []func(*Project) bool{
+		func(p *Project) bool { return p.Path == name },
+		func(p *Project) bool { return p.HasAlias(name) },
+		func(p *Project) bool { return strings.EqualFold(p.Name, name) },
+	}
+}
+
+// Owner returns the project containing dir, which may be the project
+// directory itself or anything below it. The deepest project wins, so a
+// project inside a group is preferred over the group. Symlinked projects
+// also own paths below their target.
+func Owner(projects []*Project, dir string) *Project {
+	dir = filepath.Clean(dir)
+	within := func(root string) bool {
+		return root != "" && (dir == root || strings.HasPrefix(dir, root+string(filepath.Separator)))
+	}
+
+	var owner *Project
+	depth := -1
+	for _, p := range projects {
+		for _, root := range []string{p.Path, p.SymlinkTarget} {
+			if within(root) && len(root) > depth {
+				owner, depth = p, len(root)
+			}
+		}
+	}
+	return owner
+}
+
+// Containing returns the project containing dir like Owner, also trying
+// dir with symlinks resolved, as when it was reached through a symlinked
+// repos root
+func Containing(projects []*Project, dir string) *Project {
+	if owner := Owner(projects, dir); owner != nil {
+		return owner
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		return Owner(projects, real)
+	}
+	return nil
+}
+
+// ApplyAliases sets Aliases on the projects each alias points at, by name
+// (case-insensitive) or by path
+func ApplyAliases(projects []*Project, aliases map[string]string) {
+	for alias, target := range aliases {
+		path := filepath.Clean(config.ExpandPath(target))
+		for _, p := range projects {
+			if (strings.EqualFold(p.Name, target) || p.Path == path) && !p.HasAlias(alias) {
+				p.Aliases = append(p.Aliases, alias)
+			}
+		}
+	}
+	for _, p := range projects {
+		sort.Strings(p.Aliases)
+	}
+}
+
+// HasAlias reports whether alias is one of the project's aliases, ignoring case
+func (p *Project) HasAlias(alias string) bool {
+	for _, a := range p.Aliases {
+		if strings.EqualFold(a, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// DirSize returns the total size of the regular files under path. Unreadable
+// entries are skipped.
+func DirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -1,13 +1,18 @@
 package project
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/sys"
 )
 
 func TestScanner_Scan(t *testing.T) {
@@ -61,6 +66,108 @@ func TestScanner_Scan(t *testing.T) {
 	}
 }
 
+func TestScanner_Discover(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "app")
+	os.Mkdir(projectPath, 0755)
+	os.WriteFile(filepath.Join(projectPath, "go.mod"), []byte("module test"), 0644)
+	os.WriteFile(filepath.Join(projectPath, "Dockerfile"), []byte("FROM scratch"), 0644)
+
+	scanner := NewScanner(config.DefaultConfig())
+	found, err := scanner.Discover(tmpDir)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("Expected 1 project, found %d", len(found))
+	}
+
+	p := found[0]
+	if p.Name != "app" || !p.Enriching {
+		t.Errorf("Discover() = %q (enriching %v), want app still enriching", p.Name, p.Enriching)
+	}
+	if p.Language != "" || p.HasDockerfile {
+		t.Errorf("Discover() should leave details empty, got language %q, dockerfile %v", p.Language, p.HasDockerfile)
+	}
+
+	p.Apply(scanner.Details(p.Path))
+	if p.Enriching || p.Language != "Go" || !p.HasDockerfile {
+		t.Errorf("after Apply: enriching %v, language %q, dockerfile %v", p.Enriching, p.Language, p.HasDockerfile)
+	}
+
+	// Scan still fills in everything up front
+	found, err = scanner.Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if found[0].Enriching || found[0].Language != "Go" {
+		t.Errorf("Scan() after Discover: enriching %v, language %q", found[0].Enriching, found[0].Language)
+	}
+}
+
+func TestScanner_DiscoverFS(t *testing.T) {
+	t.Cleanup(sys.UseFS(sys.FromFS(fstest.MapFS{
+		"code/shop/go.mod":               {Data: []byte("module shop")},
+		"code/tools/lint/package.json":   {Data: []byte("{}")},
+		"code/tools/fmt/README.md":       {Data: []byte("# fmt")},
+		"code/tools/old/Makefile":        {Data: []byte("all:")},
+		"code/tools/.proj-group.json":    {Data: []byte(`{"exclude": ["old"]}`)},
+		"code/notes.txt":                 {Data: []byte("not a project")},
+		"code/.hidden/go.mod":            {Data: []byte("module hidden")},
+		"elsewhere/unrelated/Cargo.toml": {Data: []byte("[package]")},
+	})))
+
+	found, err := NewScanner(config.DefaultConfig()).Discover("/code")
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	var names []string
+	for _, p := range found {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, " "); got != "shop tools fmt lint" {
+		t.Fatalf("Discover() = %q, want shop, the tools group and its children", got)
+	}
+	if !found[1].IsGroup || found[2].ParentPath != filepath.Join("/code", "tools") {
+		t.Errorf("expected tools to be a group holding fmt, got %+v", found[1])
+	}
+}
+
+func TestScanner_Extra(t *testing.T) {
+	reposPath := t.TempDir()
+	os.Mkdir(filepath.Join(reposPath, "app"), 0755)
+
+	otherRoot := t.TempDir()
+	os.Mkdir(filepath.Join(otherRoot, "lib"), 0755)
+	os.WriteFile(filepath.Join(otherRoot, "lib", "go.mod"), []byte("module lib"), 0644)
+
+	standalone := filepath.Join(t.TempDir(), "dotfiles")
+	os.Mkdir(standalone, 0755)
+
+	cfg := config.DefaultConfig()
+	cfg.ExtraRoots = []string{otherRoot, reposPath}
+	cfg.ExtraProjects = []string{standalone, filepath.Join(reposPath, "app"), filepath.Join(reposPath, "missing")}
+
+	scanner := NewScanner(cfg)
+	found, err := scanner.Scan(reposPath)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	var names []string
+	for _, p := range found {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "app,lib,dotfiles" {
+		t.Errorf("Scan() found %v, want app, lib and dotfiles once each", names)
+	}
+
+	diags := scanner.Diagnostics()
+	if len(diags) != 1 || diags[0].Kind != DiagnosticUnreadable {
+		t.Errorf("expected the missing extra project to be recorded as unreadable, got %+v", diags)
+	}
+}
+
 func TestScanner_ScanWithHidden(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -192,6 +299,21 @@ func TestSort(t *testing.T) {
 	if sorted[0].Name != "alice" || sorted[1].Name != "bob" || sorted[2].Name != "charlie" {
 		t.Error("Sort by last modified failed")
 	}
+
+	// Most frecent first
+	projects[2].Frecency = 3
+	projects[0].Frecency = 1
+	sorted = Sort(projects, SortByFrecency)
+	if sorted[0].Name != "bob" || sorted[1].Name != "charlie" || sorted[2].Name != "alice" {
+		t.Error("Sort by frecency failed")
+	}
+
+	// Pinned projects come first, whatever the order
+	projects[0].Pinned = true
+	sorted = Sort(projects, SortByName)
+	if sorted[0].Name != "charlie" || sorted[1].Name != "alice" || sorted[2].Name != "bob" {
+		t.Error("Sort with a pinned project failed")
+	}
 }
 
 func TestFilter(t *testing.T) {
@@ -242,3 +364,399 @@ func TestScanner_IsExcluded(t *testing.T) {
 		t.Error("myproject should not be excluded")
 	}
 }
+
+func TestFind(t *testing.T) {
+	projects := []*Project{
+		{Name: "webapp-legacy"},
+		{Name: "webapp"},
+		{Name: "api"},
+	}
+
+	// Exact match wins over an earlier partial match
+	if p := Find(projects, "WebApp"); p == nil || p.Name != "webapp" {
+		t.Errorf("Expected exact match 'webapp', got %v", p)
+	}
+
+	// Partial match returns the first candidate
+	if p := Find(projects, "legacy"); p == nil || p.Name != "webapp-legacy" {
+		t.Errorf("Expected partial match 'webapp-legacy', got %v", p)
+	}
+
+	if p := Find(projects, "missing"); p != nil {
+		t.Errorf("Expected no match, got %s", p.Name)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	projects := []*Project{
+		{Name: "api", Path: "/work/api"},
+		{Name: "api", Path: "/personal/api"},
+		{Name: "api-gateway", Path: "/work/api-gateway", Aliases: []string{"gw"}},
+		{Name: "web-api", Path: "/work/web-api"},
+	}
+
+	paths := func(ps []*Project) []string {
+		var out []string
+		for _, p := range ps {
+			out = append(out, p.Path)
+		}
+		return out
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"/personal/api", []string{"/personal/api"}},
+		{"GW", []string{"/work/api-gateway"}},
+		{"API", []string{"/work/api", "/personal/api"}},
+		{"gate", []string{"/work/api-gateway"}},
+		{"ap", []string{"/work/api", "/personal/api", "/work/api-gateway", "/work/web-api"}},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := paths(Candidates(projects, tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Candidates(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	// Frecent matches come first
+	projects[3].Frecency = 8
+	projects[1].Frecency = 2
+	want := []string{"/work/web-api", "/personal/api", "/work/api", "/work/api-gateway"}
+	if got := paths(Candidates(projects, "ap")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Candidates(ap) = %v, want %v", got, want)
+	}
+	if got := Find(projects, "ap"); got.Path != "/work/web-api" {
+		t.Errorf("Find(ap) = %s, want the most frecent match", got.Path)
+	}
+}
+
+func TestFindExact(t *testing.T) {
+	projects := []*Project{
+		{Name: "api", Path: "/work/api"},
+		{Name: "api", Path: "/personal/api"},
+		{Name: "api-gateway", Path: "/work/api-gateway", Aliases: []string{"gw"}},
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"/personal/api", "/personal/api"},
+		{"GW", "/work/api-gateway"},
+		{"API-Gateway", "/work/api-gateway"},
+		{"api", ""},  // Ambiguous
+		{"gate", ""}, // Only a substring
+	}
+	for _, tt := range tests {
+		got := ""
+		if p := FindExact(projects, tt.query); p != nil {
+			got = p.Path
+		}
+		if got != tt.want {
+			t.Errorf("FindExact(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestOwner(t *testing.T) {
+	projects := []*Project{
+		{Name: "clients", Path: "/code/clients", IsGroup: true},
+		{Name: "acme", Path: "/code/clients/acme", ParentPath: "/code/clients"},
+		{Name: "api", Path: "/code/api"},
+		{Name: "linked", Path: "/code/linked", SymlinkTarget: "/mnt/data/linked"},
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/code/api", "api"},
+		{"/code/api/internal/handlers/", "api"},
+		{"/code/clients/acme/src", "acme"},
+		{"/code/clients/other", "clients"},
+		{"/mnt/data/linked/docs", "linked"},
+		{"/code/api-v2", ""},
+		{"/elsewhere", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if p := Owner(projects, tt.dir); p != nil {
+			got = p.Name
+		}
+		if got != tt.want {
+			t.Errorf("Owner(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestApplyAliases(t *testing.T) {
+	projects := []*Project{
+		{Name: "api", Path: "/code/api"},
+		{Name: "company-website", Path: "/code/company-website"},
+		{Name: "www", Path: "/code/www"},
+	}
+	ApplyAliases(projects, map[string]string{"w": "Company-Website", "site": "/code/company-website", "a": "api"})
+
+	if got := projects[1].Aliases; len(got) != 2 || got[0] != "site" || got[1] != "w" {
+		t.Errorf("Aliases = %v, want [site w]", got)
+	}
+
+	// An alias wins over names that match or contain it
+	if p := Find(projects, "W"); p == nil || p.Name != "company-website" {
+		t.Errorf("Find(W) = %v, want company-website", p)
+	}
+	if p := Find(projects, "www"); p == nil || p.Name != "www" {
+		t.Errorf("Find(www) = %v, want www", p)
+	}
+	if filtered := Filter(projects, "site"); len(filtered) != 1 || filtered[0].Name != "company-website" {
+		t.Errorf("Filter(site) = %v", filtered)
+	}
+}
+
+func TestScanner_Symlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	outside := t.TempDir()
+
+	// A real project and a symlink pointing at it
+	realPath := filepath.Join(tmpDir, "real")
+	os.Mkdir(realPath, 0755)
+	os.WriteFile(filepath.Join(realPath, "go.mod"), []byte("module real"), 0644)
+	if err := os.Symlink(realPath, filepath.Join(tmpDir, "a-link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	// A symlink to a project outside the repos path
+	externalPath := filepath.Join(outside, "external")
+	os.Mkdir(externalPath, 0755)
+	os.WriteFile(filepath.Join(externalPath, "go.mod"), []byte("module external"), 0644)
+	os.Symlink(externalPath, filepath.Join(tmpDir, "external"))
+
+	// A broken symlink
+	os.Symlink(filepath.Join(outside, "missing"), filepath.Join(tmpDir, "broken"))
+
+	// A symlink to a file (not a project)
+	os.WriteFile(filepath.Join(outside, "notes.txt"), []byte("notes"), 0644)
+	os.Symlink(filepath.Join(outside, "notes.txt"), filepath.Join(tmpDir, "notes"))
+
+	cfg := config.DefaultConfig()
+	scanner := NewScanner(cfg)
+
+	found, err := scanner.Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	names := map[string]*Project{}
+	for _, p := range found {
+		names[p.Name] = p
+	}
+
+	if len(found) != 2 {
+		t.Fatalf("Expected 2 projects (real, external), got %d: %v", len(found), names)
+	}
+	if p := names["real"]; p == nil || p.SymlinkTarget != "" {
+		t.Errorf("Expected real project without symlink target, got %+v", p)
+	}
+	if p := names["external"]; p == nil || p.SymlinkTarget == "" {
+		t.Errorf("Expected external project to record its symlink target, got %+v", p)
+	}
+
+	kinds := map[DiagnosticKind]int{}
+	for _, d := range scanner.Diagnostics() {
+		kinds[d.Kind]++
+	}
+	if kinds[DiagnosticBrokenSymlink] != 1 {
+		t.Errorf("Expected 1 broken symlink diagnostic, got %d", kinds[DiagnosticBrokenSymlink])
+	}
+	if kinds[DiagnosticDuplicate] != 1 {
+		t.Errorf("Expected 1 duplicate diagnostic, got %d", kinds[DiagnosticDuplicate])
+	}
+}
+
+func TestScanner_PermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+
+	tmpDir := t.TempDir()
+
+	locked := filepath.Join(tmpDir, "locked")
+	os.MkdirAll(filepath.Join(locked, "inner"), 0755)
+	if err := os.Chmod(locked, 0000); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+	defer os.Chmod(locked, 0755)
+
+	scanner := NewScanner(config.DefaultConfig())
+	if _, err := scanner.Scan(tmpDir); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	diagnostics := scanner.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticPermissionDenied {
+		t.Fatalf("Expected one permission-denied diagnostic, got %+v", diagnostics)
+	}
+	if diagnostics[0].Suggestion == "" {
+		t.Error("Expected a suggested fix")
+	}
+}
+
+func TestScanner_RecordError(t *testing.T) {
+	scanner := NewScanner(config.DefaultConfig())
+
+	scanner.recordError("/code/secret", &os.PathError{Op: "open", Path: "/code/secret", Err: os.ErrPermission})
+	scanner.recordError("/code/io", &os.PathError{Op: "open", Path: "/code/io", Err: os.ErrInvalid})
+
+	diagnostics := scanner.Diagnostics()
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %d", len(diagnostics))
+	}
+	if diagnostics[0].Kind != DiagnosticPermissionDenied {
+		t.Errorf("Expected permission-denied, got %s", diagnostics[0].Kind)
+	}
+	if diagnostics[1].Kind != DiagnosticUnreadable {
+		t.Errorf("Expected unreadable, got %s", diagnostics[1].Kind)
+	}
+}
+
+func TestScanner_MaxProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		os.Mkdir(filepath.Join(tmpDir, name), 0755)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.MaxProjects = 3
+	scanner := NewScanner(cfg)
+
+	if _, err := scanner.Scan(tmpDir); !errors.Is(err, ErrTooManyProjects) {
+		t.Fatalf("Expected ErrTooManyProjects, got %v", err)
+	}
+
+	cfg.MaxProjects = 10
+	found, err := NewScanner(cfg).Scan(tmpDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(found) != 4 {
+		t.Errorf("Expected 4 projects, got %d", len(found))
+	}
+}
+
+func TestScanner_BroadRoot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// A group with a child project would normally be expanded
+	os.MkdirAll(filepath.Join(home, "work", "app"), 0755)
+	os.WriteFile(filepath.Join(home, "work", "app", "go.mod"), []byte("module app"), 0644)
+
+	if !IsBroadRoot(home) || !IsBroadRoot("/") {
+		t.Fatal("Expected home and / to be broad roots")
+	}
+	if IsBroadRoot(filepath.Join(home, "work")) {
+		t.Error("Expected a subdirectory not to be a broad root")
+	}
+
+	scanner := NewScanner(config.DefaultConfig())
+	found, err := scanner.Scan(home)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(found) != 1 || found[0].Name != "work" {
+		t.Errorf("Expected only the top-level directory, got %d projects", len(found))
+	}
+
+	diagnostics := scanner.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticBroadRoot {
+		t.Errorf("Expected a broad-root diagnostic, got %+v", diagnostics)
+	}
+}
+
+func TestScanner_WindowsMountInWSL(t *testing.T) {
+	scanner := NewScanner(config.DefaultConfig())
+	scanner.skipWindowsMounts = true
+
+	if _, err := scanner.Scan("/mnt/c/Users/me/code"); err == nil || !strings.Contains(err.Error(), "wsl.scanWindowsMounts") {
+		t.Errorf("Expected Windows drive scan to be refused, got %v", err)
+	}
+}
+
+func TestDetectDevEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{"flake", []string{"flake.nix"}, DevEnvNix},
+		{"envrc", []string{".envrc"}, DevEnvDirenv},
+		{"both prefers flake", []string{"flake.nix", ".envrc"}, DevEnvNix},
+		{"none", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := DetectDevEnv(dir); got != tt.expected {
+				t.Errorf("DetectDevEnv() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScanner_MaxDepth(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"work/backend/service-x", "work/backend/service-y", "work/frontend/src", "work/docs"} {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+	}
+	for _, file := range []string{"work/backend/service-x/go.mod", "work/backend/service-y/go.mod"} {
+		os.WriteFile(filepath.Join(root, file), []byte("module x\n"), 0644)
+	}
+
+	scan := func(depth int) []*Project {
+		cfg := config.DefaultConfig()
+		cfg.Display.MaxDepth = depth
+		found, err := NewScanner(cfg).Scan(root)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		return Sort(found, SortByName)
+	}
+	tree := func(projects []*Project) []string {
+		var lines []string
+		for _, p := range projects {
+			line := strings.Repeat("  ", p.Depth) + p.Name
+			if p.IsGroup {
+				line += "/"
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	// The default stops at the group's own children
+	if got, want := tree(scan(0)), []string{"work/", "  backend", "  docs", "  frontend"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default depth found %q, want %q", got, want)
+	}
+
+	// Deeper, folders holding projects become nested groups; frontend only
+	// holds a plain folder, so it stays a directory
+	found := scan(3)
+	want := []string{"work/", "  backend/", "    service-x", "    service-y", "  docs", "  frontend"}
+	if got := tree(found); !reflect.DeepEqual(got, want) {
+		t.Fatalf("depth 3 found %q, want %q", got, want)
+	}
+	backend := found[1]
+	if backend.SubProjectCount != 2 || found[2].ParentPath != backend.Path || found[0].SubProjectCount != 3 {
+		t.Errorf("backend has %d children, work %d", backend.SubProjectCount, found[0].SubProjectCount)
+	}
+	if got := Descendants(found, found[0].Path); len(got) != 5 {
+		t.Errorf("Descendants() = %d projects, want all 5 inside work", len(got))
+	}
+}
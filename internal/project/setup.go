@@ -0,0 +1,43 @@
+package project
+
+import (
+	"path/filepath"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// virtualenvDirs are the in-project virtualenv locations that count as set up
+var virtualenvDirs = []string{".venv", "venv"}
+
+// NeedsSetup reports whether a project's dependencies look uninstalled: a
+// Node project without node_modules, a Python project without a virtualenv,
+// or a PHP project without vendor/. Pipenv and Poetry keep their virtualenvs
+// outside the project, so those projects are never flagged.
+func NeedsSetup(path, language string) bool {
+	has := func(name string) bool {
+		_, err := sys.Stat(filepath.Join(path, name))
+		return err == nil
+	}
+
+	switch language {
+	case "JavaScript", "TypeScript":
+		return has("package.json") && !has("node_modules")
+	case "Python":
+		if has("Pipfile") || has("poetry.lock") {
+			return false
+		}
+		if !has("requirements.txt") && !has("pyproject.toml") {
+			return false
+		}
+		for _, dir := range virtualenvDirs {
+			if has(dir) {
+				return false
+			}
+		}
+		return true
+	case "PHP":
+		return has("composer.json") && !has("vendor")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,47 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNeedsSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		files    []string
+		dirs     []string
+		expected bool
+	}{
+		{"node without node_modules", "JavaScript", []string{"package.json"}, nil, true},
+		{"node with node_modules", "TypeScript", []string{"package.json"}, []string{"node_modules"}, false},
+		{"python without venv", "Python", []string{"requirements.txt"}, nil, true},
+		{"python with .venv", "Python", []string{"pyproject.toml"}, []string{".venv"}, false},
+		{"python with venv", "Python", []string{"requirements.txt"}, []string{"venv"}, false},
+		{"poetry manages its own venv", "Python", []string{"pyproject.toml", "poetry.lock"}, nil, false},
+		{"python script without deps", "Python", []string{"main.py"}, nil, false},
+		{"php without vendor", "PHP", []string{"composer.json"}, nil, true},
+		{"php with vendor", "PHP", []string{"composer.json"}, []string{"vendor"}, false},
+		{"go never needs setup", "Go", []string{"go.mod"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, d := range tt.dirs {
+				if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := NeedsSetup(dir, tt.language); got != tt.expected {
+				t.Errorf("NeedsSetup() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package project
+
+import (
+	"sort"
+	"strings"
+)
+
+// LanguageCount is the number of projects using a language
+type LanguageCount struct {
+	Language string
+	Count    int
+}
+
+// Stats are aggregate numbers about a set of projects
+type Stats struct {
+	Total     int
+	Dirty     int
+	Docker    int
+	Languages []LanguageCount // Most used first
+}
+
+// ComputeStats tallies the projects
+func ComputeStats(projects []*Project) Stats {
+	stats := Stats{Total: len(projects)}
+	counts := make(map[string]int)
+	for _, p := range projects {
+		if p.GitDirty {
+			stats.Dirty++
+		}
+		if p.HasDockerfile || p.HasCompose {
+			stats.Docker++
+		}
+		if p.Language != "" && p.Language != "Unknown" {
+			counts[p.Language]++
+		}
+	}
+
+	for lang, n := range counts {
+		stats.Languages = append(stats.Languages, LanguageCount{Language: lang, Count: n})
+	}
+	sort.Slice(stats.Languages, func(i, j int) bool {
+		a, b := stats.Languages[i], stats.Languages[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Language < b.Language
+	})
+	return stats
+}
+
+// TopLanguages returns up to n of the most used languages
+func (s Stats) TopLanguages(n int) []LanguageCount {
+	if len(s.Languages) < n {
+		return s.Languages
+	}
+	return s.Languages[:n]
+}
+
+// LanguageQuery returns the Match query selecting a language. Spaces are
+// replaced with dashes so the query stays a single term.
+func LanguageQuery(language string) string {
+	return "lang:" + strings.ReplaceAll(strings.ToLower(language), " ", "-")
+}
@@ -0,0 +1,155 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+// ConfigFile holds settings for a single project
+const ConfigFile = ".proj.json"
+
+// Config are settings a project keeps in its own directory
+type Config struct {
+	Workdir string `json:"workdir"` // Subdirectory actions run in, e.g. "backend"
+}
+
+// LoadConfig reads the config of a project directory. It returns nil
+// without an error when the directory has none.
+func LoadConfig(dir string) (*Config, error) {
+	data, err := sys.ReadFile(filepath.Join(dir, ConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// DetectWorkdir returns the subdirectory of the project at path that its
+// actions run in, or "" for the project directory itself. The workdir of
+// its .proj.json comes first; otherwise a workspace listing a single
+// member, in go.work or package.json, points at it. Directories outside
+// the project or that don't exist are ignored.
+func DetectWorkdir(path string) string {
+	if cfg, err := LoadConfig(path); err == nil && cfg != nil && cfg.Workdir != "" {
+		return validWorkdir(path, cfg.Workdir)
+	}
+	if dir := goWorkMember(path); dir != "" {
+		return validWorkdir(path, dir)
+	}
+	return validWorkdir(path, npmWorkspaceMember(path))
+}
+
+// validWorkdir cleans dir and returns it if it's a directory inside the
+// project at path
+func validWorkdir(path, dir string) string {
+	if dir == "" || filepath.IsAbs(dir) {
+		return ""
+	}
+	dir = filepath.Clean(filepath.FromSlash(dir))
+	if dir == "." || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	if info, err := sys.Stat(filepath.Join(path, dir)); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// goWorkMember returns the module a go.work uses when it uses only one
+func goWorkMember(path string) string {
+	members := GoWorkModules(path)
+	if len(members) != 1 {
+		return ""
+	}
+	return members[0]
+}
+
+// GoWorkModules returns the module directories the go.work at path uses,
+// relative to path and in the order listed, or nil if there is no go.work
+func GoWorkModules(path string) []string {
+	data, err := sys.ReadFile(filepath.Join(path, "go.work"))
+	if err != nil {
+		return nil
+	}
+	var members []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.SplitN(line, "//", 2)[0])
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			members = append(members, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			members = append(members, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), `"`))
+		}
+	}
+	return members
+}
+
+// GoModulePath returns the module path the go.mod in dir declares, or ""
+func GoModulePath(dir string) string {
+	data, err := sys.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(strings.SplitN(rest, "//", 2)[0]), `"`)
+		}
+	}
+	return ""
+}
+
+// npmWorkspaceMember returns the package a package.json's workspaces list
+// when it lists only one, and it isn't a glob
+func npmWorkspaceMember(path string) string {
+	data, err := sys.ReadFile(filepath.Join(path, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return ""
+	}
+	// Either a list, or Yarn's {"packages": [...]}
+	var members []string
+	if json.Unmarshal(pkg.Workspaces, &members) != nil {
+		var yarn struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(pkg.Workspaces, &yarn) != nil {
+			return ""
+		}
+		members = yarn.Packages
+	}
+	if len(members) != 1 || strings.ContainsAny(members[0], "*?[") {
+		return ""
+	}
+	return members[0]
+}
+
+// WorkPath returns the directory the project's actions run in: its
+// Workdir, or the project directory
+func (p *Project) WorkPath() string {
+	if p.Workdir == "" {
+		return p.Path
+	}
+	return filepath.Join(p.Path, p.Workdir)
+}
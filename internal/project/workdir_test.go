@@ -0,0 +1,65 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+func TestDetectWorkdir(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"none", map[string]string{"go.mod": "module x"}, ""},
+		{"proj.json", map[string]string{ConfigFile: `{"workdir": "backend/"}`}, "backend"},
+		{"proj.json wins", map[string]string{ConfigFile: `{"workdir": "backend"}`, "go.work": "use ./frontend\n"}, "backend"},
+		{"proj.json outside", map[string]string{ConfigFile: `{"workdir": "../elsewhere"}`}, ""},
+		{"proj.json missing dir", map[string]string{ConfigFile: `{"workdir": "gone"}`}, ""},
+		{"go.work single use", map[string]string{"go.work": "go 1.22\n\nuse (\n\t./backend // the API\n)\n"}, "backend"},
+		{"go.work several", map[string]string{"go.work": "go 1.22\n\nuse (\n\t./backend\n\t./frontend\n)\n"}, ""},
+		{"npm workspaces", map[string]string{"package.json": `{"workspaces": ["frontend"]}`}, "frontend"},
+		{"yarn workspaces", map[string]string{"package.json": `{"workspaces": {"packages": ["frontend"]}}`}, "frontend"},
+		{"npm workspace glob", map[string]string{"package.json": `{"workspaces": ["packages/*"]}`}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.MkdirAll(filepath.Join(dir, "backend"), 0755)
+			os.MkdirAll(filepath.Join(dir, "frontend"), 0755)
+			for name, content := range tt.files {
+				os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+			}
+			if got := DetectWorkdir(dir); got != tt.want {
+				t.Errorf("DetectWorkdir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_Workdir(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	os.MkdirAll(filepath.Join(api, "backend"), 0755)
+	os.WriteFile(filepath.Join(api, ConfigFile), []byte(`{"workdir": "backend"}`), 0644)
+	os.WriteFile(filepath.Join(api, "backend", "go.mod"), []byte("module api\n"), 0644)
+
+	found, err := NewScanner(config.DefaultConfig()).Scan(root)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	p := Find(found, "api")
+	if p == nil {
+		t.Fatal("Expected the directory with a .proj.json to be a project")
+	}
+	if p.Workdir != "backend" || p.WorkPath() != filepath.Join(api, "backend") {
+		t.Errorf("Workdir %q, WorkPath %q", p.Workdir, p.WorkPath())
+	}
+	if p.Language != "Go" {
+		t.Errorf("Language = %q, want Go from the workdir", p.Language)
+	}
+}
@@ -0,0 +1,157 @@
+package protocol
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Scheme is the URL scheme handled by proj
+const Scheme = "proj"
+
+// desktopFileName is the name of the freedesktop entry registered for the scheme
+const desktopFileName = "proj-url-handler.desktop"
+
+// Request represents a parsed proj:// URL
+type Request struct {
+	Project string // Project name to focus
+	Action  string // Optional action ID to run directly
+}
+
+// IsURL checks if an argument looks like a proj:// URL
+func IsURL(arg string) bool {
+	return strings.HasPrefix(strings.ToLower(arg), Scheme+"://")
+}
+
+// Parse parses a proj:// URL such as proj://open?name=myapp&action=git-pull
+func Parse(raw string) (*Request, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return nil, fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+
+	// proj://open?name=X parses with "open" as the host, while proj:open?name=X
+	// leaves it in the opaque part
+	command := u.Host
+	if command == "" {
+		command = u.Opaque
+	}
+	command = strings.Trim(command, "/")
+
+	if command != "open" {
+		return nil, fmt.Errorf("unsupported URL command: %q", command)
+	}
+
+	query := u.Query()
+	req := &Request{
+		Project: strings.TrimSpace(query.Get("name")),
+		Action:  strings.TrimSpace(query.Get("action")),
+	}
+
+	if req.Project == "" {
+		return nil, fmt.Errorf("URL is missing the project name (proj://open?name=<project>)")
+	}
+
+	return req, nil
+}
+
+// URL builds a proj:// URL for a project and optional action
+func URL(projectName, actionID string) string {
+	query := url.Values{}
+	query.Set("name", projectName)
+	if actionID != "" {
+		query.Set("action", actionID)
+	}
+	return fmt.Sprintf("%s://open?%s", Scheme, query.Encode())
+}
+
+// Register registers binary as the handler for proj:// URLs and returns a
+// human-readable description of what was done
+func Register(binary string) (string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return registerLinux(binary)
+	case "darwin":
+		return "", fmt.Errorf("automatic registration is not supported on macOS; " +
+			"wrap proj in an app bundle declaring the proj URL scheme (see docs/INSTALL.md)")
+	default:
+		return "", fmt.Errorf("URL handler registration is not supported on %s", runtime.GOOS)
+	}
+}
+
+// registerLinux writes a freedesktop entry and sets it as the default scheme handler
+func registerLinux(binary string) (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	appsDir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(appsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create applications directory: %w", err)
+	}
+
+	desktopPath := filepath.Join(appsDir, desktopFileName)
+	if err := os.WriteFile(desktopPath, []byte(DesktopEntry(binary)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write desktop entry: %w", err)
+	}
+
+	mimeType := "x-scheme-handler/" + Scheme
+	if _, err := exec.LookPath("xdg-mime"); err != nil {
+		return fmt.Sprintf("Wrote %s\nxdg-mime not found; run 'xdg-mime default %s %s' manually",
+			desktopPath, desktopFileName, mimeType), nil
+	}
+
+	if out, err := exec.Command("xdg-mime", "default", desktopFileName, mimeType).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("xdg-mime failed: %v\n%s", err, strings.TrimSpace(string(out)))
+	}
+
+	return fmt.Sprintf("Wrote %s\nRegistered as default handler for %s:// URLs", desktopPath, Scheme), nil
+}
+
+// DesktopEntry returns the freedesktop entry content for the URL handler.
+// Terminal=true makes the desktop environment open a terminal for the TUI.
+func DesktopEntry(binary string) string {
+	binary = desktopExecArg(binary)
+	return fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=proj
+Comment=Open projects from proj:// links
+Exec=%s %%u
+Terminal=true
+NoDisplay=true
+MimeType=x-scheme-handler/%s;
+`, binary, Scheme)
+}
+
+// desktopExecArg quotes an argument of a desktop entry's Exec key as the
+// spec requires when it has reserved characters: in double quotes, with ",
+// `, $ and \ escaped by a backslash. Backslashes are then escaped again, as
+// in any string value, and % is doubled so it isn't taken for a field code.
+func desktopExecArg(arg string) string {
+	if strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`") {
+		var b strings.Builder
+		b.WriteByte('"')
+		for _, r := range arg {
+			if strings.ContainsRune("\"`$\\", r) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+		arg = strings.ReplaceAll(b.String(), `\`, `\\`)
+	}
+	return strings.ReplaceAll(arg, "%", "%%")
+}
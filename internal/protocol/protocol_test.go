@@ -0,0 +1,87 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsURL(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected bool
+	}{
+		{"proj://open?name=app", true},
+		{"PROJ://open?name=app", true},
+		{"myproject", false},
+		{"--list", false},
+		{"https://example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsURL(tt.arg); got != tt.expected {
+			t.Errorf("IsURL(%q) = %v, want %v", tt.arg, got, tt.expected)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw     string
+		project string
+		action  string
+		wantErr bool
+	}{
+		{"proj://open?name=myapp", "myapp", "", false},
+		{"proj://open?name=myapp&action=git-pull", "myapp", "git-pull", false},
+		{"proj://open/?name=my%20app", "my app", "", false},
+		{"proj:open?name=myapp", "myapp", "", false},
+		{"proj://open", "", "", true},
+		{"proj://delete?name=myapp", "", "", true},
+		{"https://open?name=myapp", "", "", true},
+	}
+
+	for _, tt := range tests {
+		req, err := Parse(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) expected error, got %+v", tt.raw, req)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", tt.raw, err)
+			continue
+		}
+		if req.Project != tt.project || req.Action != tt.action {
+			t.Errorf("Parse(%q) = %+v, want project=%q action=%q", tt.raw, req, tt.project, tt.action)
+		}
+	}
+}
+
+func TestURLRoundTrip(t *testing.T) {
+	raw := URL("my app", "npm-dev")
+	req, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", raw, err)
+	}
+	if req.Project != "my app" || req.Action != "npm-dev" {
+		t.Errorf("Round trip mismatch: %+v", req)
+	}
+}
+
+func TestDesktopEntry(t *testing.T) {
+	entry := DesktopEntry("/usr/local/bin/proj")
+	if !strings.Contains(entry, "Exec=/usr/local/bin/proj %u") {
+		t.Errorf("Desktop entry missing Exec line:\n%s", entry)
+	}
+	if !strings.Contains(entry, "MimeType=x-scheme-handler/proj;") {
+		t.Errorf("Desktop entry missing MimeType line:\n%s", entry)
+	}
+}
+
+func TestDesktopEntryQuoting(t *testing.T) {
+	entry := DesktopEntry(`/home/me/My Apps/$proj 100%`)
+	if want := `Exec="/home/me/My Apps/\\$proj 100%%" %u`; !strings.Contains(entry, want) {
+		t.Errorf("Desktop entry missing %s:\n%s", want, entry)
+	}
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// DefaultStaleDays is the inactivity threshold used when --days is not given
+const DefaultStaleDays = 180
+
+// Activity sources for StaleProject.Source
+const (
+	SourceCommit   = "commit"
+	SourceModified = "modified"
+)
+
+// StaleProject is a project without recent commits or file changes
+type StaleProject struct {
+	Project      *project.Project
+	LastActivity time.Time
+	Source       string // SourceCommit or SourceModified, whichever is more recent
+}
+
+// lastCommitTime is swapped out in tests
+var lastCommitTime = git.LastCommitTime
+
+// Stale returns projects with no commits or modifications in the last days,
+// oldest first. Groups and archived projects are skipped.
+func Stale(projects []*project.Project, days int, now time.Time) []StaleProject {
+	if days <= 0 {
+		days = DefaultStaleDays
+	}
+	cutoff := now.AddDate(0, 0, -days)
+
+	stale := make([]StaleProject, 0)
+	for _, p := range projects {
+		if p.IsGroup || p.Archived {
+			continue
+		}
+
+		last, source := LastActivity(p)
+		if last.After(cutoff) {
+			continue
+		}
+		stale = append(stale, StaleProject{Project: p, LastActivity: last, Source: source})
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].LastActivity.Before(stale[j].LastActivity)
+	})
+
+	return stale
+}
+
+// LastActivity returns the most recent of the project's last commit and
+// modification time, along with which one it was
+func LastActivity(p *project.Project) (time.Time, string) {
+	last, source := lastModified(p), SourceModified
+
+	if p.IsGitRepo {
+		if commit, err := lastCommitTime(p.Path); err == nil && commit.After(last) {
+			last, source = commit, SourceCommit
+		}
+	}
+
+	return last, source
+}
+
+// lastModified returns when the project or one of its top-level entries
+// last changed. Editing a file doesn't touch the directories above it, so
+// the project directory's own time misses most work.
+func lastModified(p *project.Project) time.Time {
+	last := p.LastModified
+	entries, err := sys.ReadDir(p.Path)
+	if err != nil {
+		return last
+	}
+	for _, entry := range entries {
+		// Fetches touch .git too; commits are judged by their dates
+		if entry.Name() == ".git" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last
+}
+
+// DaysSince returns the number of whole days between t and now
+func DaysSince(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestStale(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	commits := map[string]time.Time{
+		"/code/revived": now.AddDate(0, 0, -3),
+		"/code/old-git": now.AddDate(-2, 0, 0),
+	}
+	orig := lastCommitTime
+	defer func() { lastCommitTime = orig }()
+	lastCommitTime = func(path string) (time.Time, error) {
+		if c, ok := commits[path]; ok {
+			return c, nil
+		}
+		return time.Time{}, errors.New("no commits")
+	}
+
+	old := now.AddDate(-1, 0, 0)
+	projects := []*project.Project{
+		{Name: "fresh", Path: "/code/fresh", LastModified: now.AddDate(0, 0, -1)},
+		{Name: "old", Path: "/code/old", LastModified: old},
+		{Name: "revived", Path: "/code/revived", LastModified: old, IsGitRepo: true},
+		{Name: "old-git", Path: "/code/old-git", LastModified: now.AddDate(-3, 0, 0), IsGitRepo: true},
+		{Name: "group", Path: "/code/group", LastModified: old, IsGroup: true},
+		{Name: "archived", Path: "/code/archived", LastModified: old, Archived: true},
+	}
+
+	stale := Stale(projects, 180, now)
+	if len(stale) != 2 {
+		t.Fatalf("Expected 2 stale projects, got %d", len(stale))
+	}
+
+	// Oldest first
+	if stale[0].Project.Name != "old-git" || stale[0].Source != SourceCommit {
+		t.Errorf("Expected old-git by commit first, got %s (%s)", stale[0].Project.Name, stale[0].Source)
+	}
+	if stale[1].Project.Name != "old" || stale[1].Source != SourceModified {
+		t.Errorf("Expected old by mtime second, got %s (%s)", stale[1].Project.Name, stale[1].Source)
+	}
+
+	if days := DaysSince(stale[1].LastActivity, now); days != 365 {
+		t.Errorf("DaysSince = %d, want 365", days)
+	}
+}
+
+func TestStale_TopLevelEdits(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := now.AddDate(-1, 0, 0)
+	if err := os.Chtimes(dir, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// The directory looks untouched, but a file in it was just edited
+	p := &project.Project{Name: "notes", Path: dir, LastModified: old}
+	if stale := Stale([]*project.Project{p}, 180, now); len(stale) != 0 {
+		t.Errorf("Expected a project with a fresh file not to be stale, got %+v", stale[0])
+	}
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/wsl"
+)
+
+// DefaultUsageDays is the period covered by the usage report when --days is not given
+const DefaultUsageDays = 30
+
+// TopUsage is how many projects, actions and editors the usage report lists
+const TopUsage = 10
+
+// BarWidth is the length of the longest bar in a chart
+const BarWidth = 30
+
+// Count is a named tally in the usage report
+type Count struct {
+	Name  string
+	Count int
+}
+
+// UsageReport summarizes the opens and action runs recorded over a period
+type UsageReport struct {
+	Days     int
+	Projects []Count // Opens plus runs, most used first
+	Actions  []Count // Runs, most used first
+	Editors  []Count // Opens, most used first
+	Weeks    []Count // Opens plus runs per week, oldest first
+}
+
+// Empty reports whether nothing was recorded in the period
+func (r UsageReport) Empty() bool {
+	return len(r.Projects) == 0
+}
+
+// Usage tallies the usage recorded in the state over the last days. Projects
+// are named after the scanned project at the same path, or the directory name
+// for projects that are no longer found.
+func Usage(st *state.State, projects []*project.Project, days int, now time.Time) UsageReport {
+	if days <= 0 {
+		days = DefaultUsageDays
+	}
+	start := now.AddDate(0, 0, -days+1)
+	from := start.Format(state.DayFormat)
+	to := now.Format(state.DayFormat)
+	first, _ := time.Parse(state.DayFormat, from)
+
+	names := make(map[string]string, len(projects))
+	for _, p := range projects {
+		names[wsl.NormalizePath(p.Path)] = p.Name
+	}
+
+	byProject := make(map[string]int)
+	byAction := make(map[string]int)
+	byEditor := make(map[string]int)
+	weeks := make([]int, (days+6)/7)
+
+	for path, ps := range st.Projects {
+		name, ok := names[path]
+		if !ok {
+			name = filepath.Base(path)
+		}
+		for day, usage := range ps.Usage {
+			if day < from || day > to {
+				continue
+			}
+			total := usage.Opens
+			for action, n := range usage.Actions {
+				byAction[action] += n
+				total += n
+			}
+			for editor, n := range usage.Editors {
+				byEditor[editor] += n
+			}
+			if total == 0 {
+				continue
+			}
+			byProject[name] += total
+
+			// Days are parsed in UTC so daylight saving can't shift a day into another week
+			if t, err := time.Parse(state.DayFormat, day); err == nil {
+				if week := DaysSince(first, t) / 7; week < len(weeks) {
+					weeks[week] += total
+				}
+			}
+		}
+	}
+
+	r := UsageReport{
+		Days:     days,
+		Projects: top(byProject),
+		Actions:  top(byAction),
+		Editors:  top(byEditor),
+	}
+	if !r.Empty() {
+		for i, n := range weeks {
+			label := start.AddDate(0, 0, i*7).Format("Jan _2")
+			r.Weeks = append(r.Weeks, Count{Name: label, Count: n})
+		}
+	}
+	return r
+}
+
+// top sorts counts by count, then name, keeping the first TopUsage
+func top(counts map[string]int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for name, n := range counts {
+		sorted = append(sorted, Count{Name: name, Count: n})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	if len(sorted) > TopUsage {
+		sorted = sorted[:TopUsage]
+	}
+	return sorted
+}
+
+// Bars renders counts as a horizontal bar chart, one line per count, with
+// the largest count filling width cells
+func Bars(counts []Count, width int) string {
+	if len(counts) == 0 {
+		return "  (none)"
+	}
+
+	nameWidth, max := 0, 0
+	for _, c := range counts {
+		if n := len([]rune(c.Name)); n > nameWidth {
+			nameWidth = n
+		}
+		if c.Count > max {
+			max = c.Count
+		}
+	}
+	if nameWidth > 30 {
+		nameWidth = 30
+	}
+
+	lines := make([]string, len(counts))
+	for i, c := range counts {
+		name := c.Name
+		if runes := []rune(name); len(runes) > nameWidth {
+			name = string(runes[:nameWidth-1]) + "…"
+		}
+		bar := ""
+		if max > 0 {
+			cells := c.Count * width / max
+			if cells == 0 && c.Count > 0 {
+				cells = 1
+			}
+			bar = strings.Repeat("█", cells)
+		}
+		lines[i] = fmt.Sprintf("  %-*s  %s %d", nameWidth, name, bar, c.Count)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Render renders the report as bar charts under section headings
+func (r UsageReport) Render(width int) string {
+	sections := []struct {
+		title  string
+		counts []Count
+	}{
+		{"Most used projects", r.Projects},
+		{"Most run actions", r.Actions},
+		{"Editors", r.Editors},
+		{"Activity per week", r.Weeks},
+	}
+
+	parts := make([]string, len(sections))
+	for i, s := range sections {
+		parts[i] = s.title + "\n" + Bars(s.counts, width)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+)
+
+func TestUsage(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := func(daysAgo int) string { return now.AddDate(0, 0, -daysAgo).Format(state.DayFormat) }
+
+	st := state.New("")
+	st.Project("/code/api").Usage = map[string]*state.Usage{
+		day(0):  {Opens: 2, Editors: map[string]int{"nvim": 2}, Actions: map[string]int{"Run Tests": 3}},
+		day(10): {Opens: 1, Editors: map[string]int{"code": 1}},
+		day(40): {Opens: 9, Editors: map[string]int{"code": 9}}, // Outside the period
+	}
+	st.Project("/code/gone").Usage = map[string]*state.Usage{
+		day(1): {Actions: map[string]int{"Build": 1}},
+	}
+
+	projects := []*project.Project{{Name: "api-server", Path: "/code/api"}}
+	r := Usage(st, projects, 30, now)
+
+	if len(r.Projects) != 2 || r.Projects[0] != (Count{"api-server", 6}) || r.Projects[1] != (Count{"gone", 1}) {
+		t.Errorf("Projects = %v", r.Projects)
+	}
+	if len(r.Actions) != 2 || r.Actions[0] != (Count{"Run Tests", 3}) {
+		t.Errorf("Actions = %v", r.Actions)
+	}
+	if len(r.Editors) != 2 || r.Editors[0] != (Count{"nvim", 2}) || r.Editors[1] != (Count{"code", 1}) {
+		t.Errorf("Editors = %v", r.Editors)
+	}
+
+	// 30 days span 5 weeks; today and yesterday fall in the last
+	if len(r.Weeks) != 5 || r.Weeks[4].Count != 6 || r.Weeks[2].Count != 1 {
+		t.Errorf("Weeks = %v", r.Weeks)
+	}
+
+	if empty := Usage(state.New(""), projects, 30, now); !empty.Empty() || len(empty.Weeks) != 0 {
+		t.Errorf("Usage of an empty state = %+v", empty)
+	}
+}
+
+func TestBars(t *testing.T) {
+	got := Bars([]Count{{"api", 10}, {"web", 5}, {"cli", 0}}, 10)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Bars returned %d lines:\n%s", len(lines), got)
+	}
+	if strings.Count(lines[0], "█") != 10 || strings.Count(lines[1], "█") != 5 || strings.Count(lines[2], "█") != 0 {
+		t.Errorf("unexpected bar lengths:\n%s", got)
+	}
+	if !strings.HasSuffix(lines[0], " 10") {
+		t.Errorf("bar should end with its count: %q", lines[0])
+	}
+
+	if got := Bars(nil, 10); got != "  (none)" {
+		t.Errorf("Bars(nil) = %q", got)
+	}
+}
@@ -0,0 +1,149 @@
+package results
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Location is a file:line[:col] reference found in command output
+type Location struct {
+	File    string // Absolute path
+	Line    int
+	Col     int // 0 when the output has no column
+	Message string
+}
+
+// String renders the location relative to root for display
+func (l Location) String(root string) string {
+	file := l.File
+	if rel, err := filepath.Rel(root, l.File); err == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	s := file + ":" + strconv.Itoa(l.Line)
+	if l.Col > 0 {
+		s += ":" + strconv.Itoa(l.Col)
+	}
+	if l.Message != "" {
+		s += "  " + l.Message
+	}
+	return s
+}
+
+// maxLocations caps how many locations are collected from a single output
+const maxLocations = 200
+
+// maxIndexedFiles caps the file index used to resolve paths printed relative
+// to a subdirectory (such as go test's package-relative paths)
+const maxIndexedFiles = 20000
+
+var locationPattern = regexp.MustCompile(`(?:^|[\s(\[])((?:[A-Za-z]:)?[\w./\\-]*\w\.\w+):(\d+)(?::(\d+))?:?\s*(.*)`)
+
+// indexSkipDirs are not searched when resolving relative paths
+var indexSkipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, "target": true, ".venv": true}
+
+// FindLocations extracts file:line[:col] references from output, keeping only
+// those that point at files inside root. Duplicate locations are dropped.
+func FindLocations(output, root string) []Location {
+	var locations []Location
+	seen := map[string]bool{}
+	var index map[string][]string
+
+	for _, line := range strings.Split(output, "\n") {
+		m := locationPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		lineNum, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		if lineNum == 0 {
+			continue
+		}
+
+		file := resolveFile(root, m[1], &index)
+		if file == "" {
+			continue
+		}
+
+		key := file + ":" + m[2] + ":" + m[3]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		locations = append(locations, Location{File: file, Line: lineNum, Col: col, Message: strings.TrimSpace(m[4])})
+		if len(locations) == maxLocations {
+			break
+		}
+	}
+	return locations
+}
+
+// resolveFile maps a path from the output to an existing file under root. The
+// file index is built lazily the first time a path can't be found directly.
+func resolveFile(root, path string, index *map[string][]string) string {
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		if isFile(path) && within(root, path) {
+			return path
+		}
+		return ""
+	}
+
+	if candidate := filepath.Join(root, path); isFile(candidate) {
+		return candidate
+	}
+
+	// Paths relative to a subdirectory: match by suffix if unambiguous
+	if *index == nil {
+		*index = indexFiles(root)
+	}
+	var match string
+	for _, candidate := range (*index)[filepath.Base(path)] {
+		if strings.HasSuffix(candidate, string(filepath.Separator)+path) {
+			if match != "" {
+				return ""
+			}
+			match = candidate
+		}
+	}
+	return match
+}
+
+// indexFiles maps base names to the files under root
+func indexFiles(root string) map[string][]string {
+	index := map[string][]string{}
+	count := 0
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && indexSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		index[d.Name()] = append(index[d.Name()], path)
+		count++
+		if count >= maxIndexedFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return index
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
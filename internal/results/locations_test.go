@@ -0,0 +1,54 @@
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindLocations(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"main.go", "pkg/util/util_test.go", "src/app.ts"} {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := `# example.com/x
+./main.go:12:5: undefined: foo
+--- FAIL: TestUtil (0.00s)
+    util/util_test.go:30: expected 1, got 2
+src/app.ts(3,1): not matched
+src/app.ts:7:10 - error TS2322: Type 'string' is not assignable
+./main.go:12:5: undefined: foo
+missing.go:1:1: not in the project
+see http://example.com:8080/docs`
+
+	locations := FindLocations(output, root)
+
+	expected := []Location{
+		{File: filepath.Join(root, "main.go"), Line: 12, Col: 5, Message: "undefined: foo"},
+		{File: filepath.Join(root, "pkg/util/util_test.go"), Line: 30, Message: "expected 1, got 2"},
+		{File: filepath.Join(root, "src/app.ts"), Line: 7, Col: 10, Message: "- error TS2322: Type 'string' is not assignable"},
+	}
+	if len(locations) != len(expected) {
+		t.Fatalf("Expected %d locations, got %d: %+v", len(expected), len(locations), locations)
+	}
+	for i, want := range expected {
+		if locations[i] != want {
+			t.Errorf("location %d = %+v, want %+v", i, locations[i], want)
+		}
+	}
+}
+
+func TestLocation_String(t *testing.T) {
+	root := t.TempDir()
+	loc := Location{File: filepath.Join(root, "a", "b.go"), Line: 3, Col: 7, Message: "boom"}
+	if got, want := loc.String(root), filepath.Join("a", "b.go")+":3:7  boom"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
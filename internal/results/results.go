@@ -0,0 +1,279 @@
+// Package results parses structured test output (go test -json, jest --json,
+// JUnit XML, ctest) into a pass/fail summary for the result view.
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Output parser names, as used in actions.parsers
+const (
+	GoTestJSON = "go-test-json"
+	JestJSON   = "jest-json"
+	JUnitXML   = "junit-xml"
+	CTest      = "ctest"
+)
+
+// Parsers lists the supported output parsers
+var Parsers = []string{GoTestJSON, JestJSON, JUnitXML, CTest}
+
+// Summary is the structured result of a test run
+type Summary struct {
+	Passed   int
+	Failed   int
+	Skipped  int
+	Failures []string // Names of failing tests
+}
+
+// String renders the summary for the result view
+func (s *Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✓ %d passed  ✗ %d failed", s.Passed, s.Failed)
+	if s.Skipped > 0 {
+		fmt.Fprintf(&b, "  ○ %d skipped", s.Skipped)
+	}
+
+	if len(s.Failures) > 0 {
+		b.WriteString("\n\nFailing tests:\n")
+		for _, name := range s.Failures {
+			b.WriteString("  ✗ " + name + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Parse parses command output with the named parser. Text around the
+// structured output (such as a "Command failed" prefix) is ignored.
+func Parse(parser, output string) (*Summary, error) {
+	switch parser {
+	case GoTestJSON:
+		return parseGoTest(output)
+	case JestJSON:
+		return parseJest(output)
+	case JUnitXML:
+		return parseJUnit(output)
+	case CTest:
+		return parseCTest(output)
+	default:
+		return nil, fmt.Errorf("unknown output parser %q (supported: %s)", parser, strings.Join(Parsers, ", "))
+	}
+}
+
+// goTestEvent is a line of go test -json output
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+}
+
+// eachGoTestEvent calls fn for each JSON event, skipping other lines
+func eachGoTestEvent(output string, fn func(goTestEvent)) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var ev goTestEvent
+		if json.Unmarshal([]byte(line), &ev) == nil && ev.Action != "" {
+			fn(ev)
+		}
+	}
+}
+
+// parseGoTest parses go test -json output. Packages that fail without a
+// failing test (e.g. build errors) are reported as failures too.
+func parseGoTest(output string) (*Summary, error) {
+	s := &Summary{}
+	events := 0
+	failedTests := map[string]bool{}
+
+	eachGoTestEvent(output, func(ev goTestEvent) {
+		events++
+		switch ev.Action {
+		case "pass":
+			if ev.Test != "" {
+				s.Passed++
+			}
+		case "skip":
+			if ev.Test != "" {
+				s.Skipped++
+			}
+		case "fail":
+			if ev.Test != "" {
+				s.Failed++
+				failedTests[ev.Package] = true
+				s.Failures = append(s.Failures, ev.Package+"."+ev.Test)
+			} else if !failedTests[ev.Package] {
+				s.Failed++
+				s.Failures = append(s.Failures, ev.Package+" (build or setup failed)")
+			}
+		}
+	})
+
+	if events == 0 {
+		return nil, fmt.Errorf("no go test -json events found in output")
+	}
+	return s, nil
+}
+
+// GoTestOutput reassembles the human-readable output from go test -json
+// events, keeping any non-JSON lines (such as build errors) as they are
+func GoTestOutput(output string) string {
+	var b strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var ev goTestEvent
+		if strings.HasPrefix(strings.TrimSpace(line), "{") && json.Unmarshal([]byte(line), &ev) == nil && ev.Action != "" {
+			b.WriteString(ev.Output)
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// parseJest parses jest --json output
+func parseJest(output string) (*Summary, error) {
+	start := strings.Index(output, "{")
+	if start < 0 {
+		return nil, fmt.Errorf("no jest JSON report found in output")
+	}
+
+	var report struct {
+		NumPassedTests  int `json:"numPassedTests"`
+		NumFailedTests  int `json:"numFailedTests"`
+		NumPendingTests int `json:"numPendingTests"`
+		TestResults     []struct {
+			Name             string `json:"name"`
+			AssertionResults []struct {
+				FullName string `json:"fullName"`
+				Status   string `json:"status"`
+			} `json:"assertionResults"`
+		} `json:"testResults"`
+	}
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse jest output: %w", err)
+	}
+
+	s := &Summary{
+		Passed:  report.NumPassedTests,
+		Failed:  report.NumFailedTests,
+		Skipped: report.NumPendingTests,
+	}
+	for _, file := range report.TestResults {
+		for _, a := range file.AssertionResults {
+			if a.Status == "failed" {
+				s.Failures = append(s.Failures, a.FullName)
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseJUnit parses a JUnit XML report, with either <testsuites> or a single
+// <testsuite> at the root
+func parseJUnit(output string) (*Summary, error) {
+	start := strings.Index(output, "<")
+	if start < 0 {
+		return nil, fmt.Errorf("no JUnit XML found in output")
+	}
+
+	type testCase struct {
+		Name      string    `xml:"name,attr"`
+		ClassName string    `xml:"classname,attr"`
+		Failure   *struct{} `xml:"failure"`
+		Error     *struct{} `xml:"error"`
+		Skipped   *struct{} `xml:"skipped"`
+	}
+
+	s := &Summary{}
+	cases := 0
+	dec := xml.NewDecoder(strings.NewReader(output[start:]))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if cases > 0 {
+				// Trailing output after the report
+				break
+			}
+			return nil, fmt.Errorf("failed to parse JUnit XML: %w", err)
+		}
+
+		el, ok := tok.(xml.StartElement)
+		if !ok || el.Name.Local != "testcase" {
+			continue
+		}
+
+		var tc testCase
+		if err := dec.DecodeElement(&tc, &el); err != nil {
+			return nil, fmt.Errorf("failed to parse JUnit XML: %w", err)
+		}
+		cases++
+
+		switch {
+		case tc.Failure != nil || tc.Error != nil:
+			s.Failed++
+			name := tc.Name
+			if tc.ClassName != "" {
+				name = tc.ClassName + "." + tc.Name
+			}
+			s.Failures = append(s.Failures, name)
+		case tc.Skipped != nil:
+			s.Skipped++
+		default:
+			s.Passed++
+		}
+	}
+
+	if cases == 0 {
+		return nil, fmt.Errorf("no test cases found in JUnit XML")
+	}
+	return s, nil
+}
+
+// ctestLine matches a test's result in ctest's output, e.g.
+// " 2/3 Test #2: parse ......................***Failed    0.01 sec"
+var ctestLine = regexp.MustCompile(`^\s*\d+/\d+\s+Test\s+#\d+:\s+(.+?)\s+(?:\.+\s*(?:\*+)?|\*+)(\S.*?)\s+[\d.]+\s+sec\s*$`)
+
+// parseCTest parses ctest's plain output. Tests that are disabled or not
+// run count as skipped; any other status than Passed is a failure.
+func parseCTest(output string) (*Summary, error) {
+	s := &Summary{}
+	tests := 0
+	for _, line := range strings.Split(output, "\n") {
+		m := ctestLine.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		tests++
+		name, status := m[1], m[2]
+		switch {
+		case status == "Passed":
+			s.Passed++
+		case strings.HasPrefix(status, "Skipped"), strings.HasPrefix(status, "Not Run"):
+			s.Skipped++
+		default:
+			s.Failed++
+			s.Failures = append(s.Failures, name)
+		}
+	}
+
+	if tests == 0 {
+		return nil, fmt.Errorf("no ctest results found in output")
+	}
+	return s, nil
+}
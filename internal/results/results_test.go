@@ -0,0 +1,148 @@
+package results
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const goTestOutput = `{"Action":"run","Package":"example.com/x","Test":"TestA"}
+{"Action":"output","Package":"example.com/x","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"pass","Package":"example.com/x","Test":"TestA"}
+{"Action":"run","Package":"example.com/x","Test":"TestB"}
+{"Action":"output","Package":"example.com/x","Test":"TestB","Output":"    x_test.go:9: boom\n"}
+{"Action":"fail","Package":"example.com/x","Test":"TestB"}
+{"Action":"skip","Package":"example.com/x","Test":"TestC"}
+{"Action":"fail","Package":"example.com/x"}
+# example.com/y
+y/y.go:3:1: syntax error
+{"Action":"fail","Package":"example.com/y"}
+`
+
+func TestParse_GoTestJSON(t *testing.T) {
+	s, err := Parse(GoTestJSON, "Command failed: exit status 1\n\n"+goTestOutput)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := &Summary{
+		Passed:   1,
+		Failed:   2,
+		Skipped:  1,
+		Failures: []string{"example.com/x.TestB", "example.com/y (build or setup failed)"},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("Parse() = %+v, want %+v", s, expected)
+	}
+}
+
+func TestGoTestOutput(t *testing.T) {
+	out := GoTestOutput(goTestOutput)
+	for _, want := range []string{"=== RUN   TestA", "x_test.go:9: boom", "y/y.go:3:1: syntax error"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"Action"`) {
+		t.Errorf("Expected JSON events to be unwrapped, got:\n%s", out)
+	}
+}
+
+func TestParse_JestJSON(t *testing.T) {
+	output := `> jest --json
+{"numPassedTests":3,"numFailedTests":1,"numPendingTests":0,"testResults":[{"name":"/app/sum.test.js","assertionResults":[
+{"fullName":"sum adds","status":"passed"},{"fullName":"sum handles negatives","status":"failed"}]}]}`
+
+	s, err := Parse(JestJSON, output)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if s.Passed != 3 || s.Failed != 1 || !reflect.DeepEqual(s.Failures, []string{"sum handles negatives"}) {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+}
+
+func TestParse_JUnitXML(t *testing.T) {
+	output := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="calc" tests="4">
+    <testcase classname="calc.AddTest" name="adds"/>
+    <testcase classname="calc.AddTest" name="overflows"><failure message="expected 0"/></testcase>
+    <testcase classname="calc.DivTest" name="by zero"><error type="panic"/></testcase>
+    <testcase classname="calc.DivTest" name="later"><skipped/></testcase>
+  </testsuite>
+</testsuites>`
+
+	s, err := Parse(JUnitXML, output)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := &Summary{
+		Passed:   1,
+		Failed:   2,
+		Skipped:  1,
+		Failures: []string{"calc.AddTest.overflows", "calc.DivTest.by zero"},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("Parse() = %+v, want %+v", s, expected)
+	}
+}
+
+func TestParse_CTest(t *testing.T) {
+	output := `Test project /src/build
+    Start 1: add
+1/4 Test #1: add ..............................   Passed    0.00 sec
+    Start 2: divide by zero
+2/4 Test #2: divide by zero ...................***Failed    0.01 sec
+    Start 3: slow
+3/4 Test #3: slow .............................***Not Run (Disabled)   0.00 sec
+    Start 4: crash
+4/4 Test #4: crash ............................***Exception: SegFault  0.02 sec
+
+50% tests passed, 2 tests failed out of 4
+`
+	s, err := Parse(CTest, output)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := &Summary{
+		Passed:   1,
+		Failed:   2,
+		Skipped:  1,
+		Failures: []string{"divide by zero", "crash"},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("Parse() = %+v, want %+v", s, expected)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		parser string
+		output string
+	}{
+		{"tap", "ok 1"},
+		{GoTestJSON, "ok  example.com/x 0.1s"},
+		{JestJSON, "no json here"},
+		{JUnitXML, "<testsuite></testsuite>"},
+		{CTest, "No tests were found!!!"},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(tt.parser, tt.output); err == nil {
+			t.Errorf("Expected error parsing %q with %s", tt.output, tt.parser)
+		}
+	}
+}
+
+func TestSummary_String(t *testing.T) {
+	s := &Summary{Passed: 5, Failed: 1, Skipped: 2, Failures: []string{"pkg.TestX"}}
+	out := s.String()
+	for _, want := range []string{"✓ 5 passed", "✗ 1 failed", "○ 2 skipped", "Failing tests:", "✗ pkg.TestX"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package scaffold
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitignorePlan is what generating a .gitignore would do to a project
+type GitignorePlan struct {
+	Sections []string // Templates the entries came from, e.g. "Go", "Docker"
+	Exists   bool     // The project already has a .gitignore, which is merged into
+	Added    []string // Entries the project's .gitignore doesn't have yet
+	Content  []byte   // The whole file after writing
+}
+
+// PlanGitignore composes a .gitignore from the templates of the project's
+// languages (and Docker's, if it uses Docker) and works out what it adds to
+// the project's existing .gitignore, if any
+func PlanGitignore(root string, languages []string, docker bool, data Data) (GitignorePlan, error) {
+	var plan GitignorePlan
+	var sections [][]byte
+	seen := make(map[string]bool)
+
+	for _, lang := range languages {
+		key, ok := templateKeys[lang]
+		if !ok || seen[key] || !exists("gitignore/"+key) {
+			continue
+		}
+		seen[key] = true
+		f, err := render("gitignore/"+key, GitignorePath, data)
+		if err != nil {
+			return plan, err
+		}
+		plan.Sections = append(plan.Sections, lang)
+		sections = append(sections, section(lang, f.Content))
+	}
+	if docker && exists("gitignore/docker") {
+		f, err := render("gitignore/docker", GitignorePath, data)
+		if err != nil {
+			return plan, err
+		}
+		plan.Sections = append(plan.Sections, "Docker")
+		sections = append(sections, section("Docker", f.Content))
+	}
+	if len(sections) == 0 {
+		f, err := render("gitignore/default", GitignorePath, data)
+		if err != nil {
+			return plan, err
+		}
+		sections = append(sections, f.Content)
+	}
+	generated := dedupeEntries(bytes.Join(sections, []byte("\n")))
+
+	existing, err := os.ReadFile(filepath.Join(root, GitignorePath))
+	if err != nil && !os.IsNotExist(err) {
+		return plan, err
+	}
+	if err != nil {
+		plan.Content = generated
+		plan.Added = entries(generated)
+		return plan, nil
+	}
+
+	plan.Exists = true
+	plan.Content, plan.Added = MergeGitignore(existing, generated)
+	return plan, nil
+}
+
+// MergeGitignore appends the entries of generated that existing doesn't have
+// yet, returning the merged file and the entries added
+func MergeGitignore(existing, generated []byte) ([]byte, []string) {
+	have := make(map[string]bool)
+	for _, entry := range entries(existing) {
+		have[entry] = true
+	}
+	var added []string
+	for _, entry := range entries(generated) {
+		if !have[entry] {
+			have[entry] = true
+			added = append(added, entry)
+		}
+	}
+	if len(added) == 0 {
+		return existing, nil
+	}
+
+	merged := append([]byte(nil), existing...)
+	if len(merged) > 0 && !bytes.HasSuffix(merged, []byte("\n")) {
+		merged = append(merged, '\n')
+	}
+	merged = append(merged, "\n# Added by proj\n"+strings.Join(added, "\n")+"\n"...)
+	return merged, added
+}
+
+// WriteGitignore writes the planned .gitignore into the project
+func WriteGitignore(root string, plan GitignorePlan) error {
+	return os.WriteFile(filepath.Join(root, GitignorePath), plan.Content, 0644)
+}
+
+// section prefixes a template's entries with a "# name" header
+func section(name string, content []byte) []byte {
+	return append([]byte("# "+name+"\n"), content...)
+}
+
+// entries returns the patterns in a .gitignore, without comments and blank lines
+func entries(content []byte) []string {
+	var patterns []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// dedupeEntries drops patterns an earlier section already has, and headers
+// left with nothing under them
+func dedupeEntries(content []byte) []byte {
+	seen := make(map[string]bool)
+	var out []string
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			if seen[trimmed] {
+				continue
+			}
+			seen[trimmed] = true
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(collapseBlank(out), "\n") + "\n")
+}
+
+// collapseBlank removes runs of blank lines and comments with no entries
+// before the next comment or the end
+func collapseBlank(lines []string) []string {
+	var out []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" && (len(out) == 0 || strings.TrimSpace(out[len(out)-1]) == "") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") && !hasEntryBefore(lines[i+1:]) {
+			continue
+		}
+		out = append(out, line)
+	}
+	for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// hasEntryBefore reports whether lines has a pattern before the next blank line
+func hasEntryBefore(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			return false
+		}
+		if !strings.HasPrefix(trimmed, "#") {
+			return true
+		}
+	}
+	return false
+}
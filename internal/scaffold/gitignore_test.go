@@ -0,0 +1,76 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanGitignore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+
+	plan, err := PlanGitignore(root, []string{"Go", "TypeScript", "JavaScript"}, true, Data{})
+	if err != nil {
+		t.Fatalf("PlanGitignore failed: %v", err)
+	}
+	if want := []string{"Go", "TypeScript", "Docker"}; !reflect.DeepEqual(plan.Sections, want) {
+		t.Errorf("Sections = %v, want %v", plan.Sections, want)
+	}
+	content := string(plan.Content)
+	for _, entry := range []string{"# Go\n", "go.work.sum", "node_modules/", "docker-compose.override.yml"} {
+		if !strings.Contains(content, entry) {
+			t.Errorf("generated .gitignore is missing %q:\n%s", entry, content)
+		}
+	}
+	if n := strings.Count(content, ".DS_Store"); n != 1 {
+		t.Errorf(".DS_Store appears %d times, want once:\n%s", n, content)
+	}
+	if plan.Exists {
+		t.Error("Exists should be false without a .gitignore")
+	}
+}
+
+func TestPlanGitignore_Unknown(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	plan, err := PlanGitignore(t.TempDir(), []string{"Unknown"}, false, Data{})
+	if err != nil {
+		t.Fatalf("PlanGitignore failed: %v", err)
+	}
+	if len(plan.Sections) != 0 || !strings.Contains(string(plan.Content), "Thumbs.db") {
+		t.Errorf("expected the default .gitignore, got %v:\n%s", plan.Sections, plan.Content)
+	}
+}
+
+func TestPlanGitignore_Merge(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	existing := "# mine\n/target/\nsecrets.txt"
+	os.WriteFile(filepath.Join(root, GitignorePath), []byte(existing), 0644)
+
+	plan, err := PlanGitignore(root, []string{"Rust"}, false, Data{})
+	if err != nil {
+		t.Fatalf("PlanGitignore failed: %v", err)
+	}
+	if !plan.Exists {
+		t.Fatal("Exists should be true")
+	}
+	if want := []string{"**/*.rs.bk", ".DS_Store", ".env"}; !reflect.DeepEqual(plan.Added, want) {
+		t.Errorf("Added = %v, want %v", plan.Added, want)
+	}
+	want := existing + "\n\n# Added by proj\n**/*.rs.bk\n.DS_Store\n.env\n"
+	if string(plan.Content) != want {
+		t.Errorf("merged content = %q, want %q", plan.Content, want)
+	}
+
+	if err := WriteGitignore(root, plan); err != nil {
+		t.Fatalf("WriteGitignore failed: %v", err)
+	}
+	again, _ := PlanGitignore(root, []string{"Rust"}, false, Data{})
+	if len(again.Added) != 0 {
+		t.Errorf("a second merge should add nothing, got %v", again.Added)
+	}
+}
@@ -0,0 +1,43 @@
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// pluginFiles are the templates of a plugin skeleton and the files they
+// render to. Go sources have a .tmpl suffix so they aren't built as part of proj.
+var pluginFiles = []struct {
+	template string
+	path     string
+}{
+	{"plugin/plugin.json", "plugin.json"},
+	{"plugin/main.go.tmpl", "main.go"},
+	{"plugin/main_test.go.tmpl", "main_test.go"},
+	{"plugin/go.mod.tmpl", "go.mod"},
+	{"plugin/Makefile", "Makefile"},
+	{"plugin/gitignore", ".gitignore"},
+}
+
+// pluginName is what a plugin may be called: it names the plugin's
+// directory, executable, Go module and action ID prefix
+var pluginName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// Plugin renders the skeleton of a Go plugin called name: the JSON-RPC loop
+// with an example action, its tests, the plugin.json manifest and a Makefile
+func Plugin(name string) ([]File, error) {
+	if !pluginName.MatchString(name) {
+		return nil, fmt.Errorf("invalid plugin name %q: use lowercase letters, digits, - and _, starting with a letter", name)
+	}
+
+	data := Data{Project: name}
+	files := make([]File, 0, len(pluginFiles))
+	for _, pf := range pluginFiles {
+		f, err := render(pf.template, pf.path, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+)
+
+// TemplateFile holds the settings of a template repository. It is left out
+// of the projects created from the template.
+const TemplateFile = ".proj-template.json"
+
+// RemoteTemplate is what a template repository declares in TemplateFile
+type RemoteTemplate struct {
+	PostInit string `json:"postInit"` // Command to run in new projects, e.g. npm install
+}
+
+// FromTemplate creates dest from the files of a template repository, without
+// its history. source is a repository URL or owner/repo, optionally followed
+// by #branch or #tag, or the path of a local repository.
+func FromTemplate(source, dest string) (RemoteTemplate, error) {
+	var tmpl RemoteTemplate
+	repo, ref, _ := strings.Cut(strings.TrimSpace(source), "#")
+
+	url := config.ExpandPath(repo)
+	if info, err := os.Stat(url); err != nil || !info.IsDir() {
+		remote, err := git.ParseRemote(repo)
+		if err != nil {
+			return tmpl, err
+		}
+		url = remote.URL
+	}
+
+	if out, err := git.Export(url, ref, dest); err != nil {
+		os.RemoveAll(dest)
+		return tmpl, fmt.Errorf("git clone failed: %v\n%s", err, out)
+	}
+
+	path := filepath.Join(dest, TemplateFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return tmpl, nil
+	} else if err != nil {
+		return tmpl, err
+	}
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return tmpl, fmt.Errorf("invalid %s: %w", TemplateFile, err)
+	}
+	return tmpl, os.Remove(path)
+}
+
+// Generators that create projects from their own kind of template, asking
+// their questions in the terminal
+const (
+	GeneratorCookiecutter = "cookiecutter"
+	GeneratorCopier       = "copier"
+)
+
+// ParseTemplate splits a templates entry into the generator that runs it
+// and the template it is given. Entries without a cookiecutter: or copier:
+// prefix are template repositories for FromTemplate, with no generator.
+func ParseTemplate(source string) (generator, template string) {
+	for _, g := range []string{GeneratorCookiecutter, GeneratorCopier} {
+		if rest, ok := strings.CutPrefix(source, g+":"); ok {
+			return g, strings.TrimSpace(rest)
+		}
+	}
+	return "", strings.TrimSpace(source)
+}
+
+// GeneratorInstalled reports whether a generator can be run
+func GeneratorInstalled(generator string) bool {
+	_, err := exec.LookPath(generator)
+	return err == nil
+}
+
+// GeneratorCommand returns the command that creates project name in dir
+// from template with generator, to run attached to the terminal.
+// cookiecutter names the directory itself, so name is offered as the
+// default of its project_name and project_slug questions.
+func GeneratorCommand(generator, template, dir, name string) (*exec.Cmd, error) {
+	if !GeneratorInstalled(generator) {
+		return nil, fmt.Errorf("%s is not installed (try pipx install %s)", generator, generator)
+	}
+
+	var cmd *exec.Cmd
+	switch generator {
+	case GeneratorCookiecutter:
+		cmd = exec.Command(generator, template, "--output-dir", dir, "project_name="+name, "project_slug="+name)
+	case GeneratorCopier:
+		cmd = exec.Command(generator, "copy", template, filepath.Join(dir, name))
+	default:
+		return nil, fmt.Errorf("unknown generator %q", generator)
+	}
+	cmd.Dir = dir
+	return cmd, nil
+}
@@ -0,0 +1,113 @@
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/s33g/proj/internal/git"
+)
+
+func TestFromTemplate(t *testing.T) {
+	if !git.IsInstalled() {
+		t.Skip("git not installed")
+	}
+
+	repo := filepath.Join(t.TempDir(), "template")
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# template\n"), 0644)
+	os.WriteFile(filepath.Join(repo, TemplateFile), []byte(`{"postInit": "npm install"}`), 0644)
+	run("add", ".")
+	run("commit", "-m", "initial")
+	run("checkout", "-b", "minimal")
+	run("rm", "-q", TemplateFile)
+	run("commit", "-m", "no post-init")
+	run("checkout", "-q", "main")
+
+	dest := filepath.Join(t.TempDir(), "code", "app")
+	tmpl, err := FromTemplate(repo, dest)
+	if err != nil {
+		t.Fatalf("FromTemplate failed: %v", err)
+	}
+	if tmpl.PostInit != "npm install" {
+		t.Errorf("PostInit = %q, want npm install", tmpl.PostInit)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); err != nil {
+		t.Errorf("README.md was not copied: %v", err)
+	}
+	for _, name := range []string{".git", TemplateFile} {
+		if _, err := os.Stat(filepath.Join(dest, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be in the new project", name)
+		}
+	}
+
+	// #ref picks a branch
+	dest = filepath.Join(t.TempDir(), "app")
+	if tmpl, err := FromTemplate(repo+"#minimal", dest); err != nil || tmpl.PostInit != "" {
+		t.Errorf("FromTemplate(#minimal) = %+v, %v; want no post-init", tmpl, err)
+	}
+
+	// A failed clone leaves nothing behind
+	dest = filepath.Join(t.TempDir(), "app")
+	if _, err := FromTemplate(repo+"#missing", dest); err == nil {
+		t.Error("FromTemplate with a missing branch should fail")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("a failed clone should not leave the project directory behind")
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		source, generator, template string
+	}{
+		{"github.com/me/go-cli", "", "github.com/me/go-cli"},
+		{"cookiecutter:gh:audreyfeldroy/cookiecutter-pypackage", GeneratorCookiecutter, "gh:audreyfeldroy/cookiecutter-pypackage"},
+		{"copier: https://github.com/me/copier-template.git", GeneratorCopier, "https://github.com/me/copier-template.git"},
+	}
+	for _, tt := range tests {
+		generator, template := ParseTemplate(tt.source)
+		if generator != tt.generator || template != tt.template {
+			t.Errorf("ParseTemplate(%q) = %q, %q; want %q, %q", tt.source, generator, template, tt.generator, tt.template)
+		}
+	}
+}
+
+func TestGeneratorCommand(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+
+	if _, err := GeneratorCommand(GeneratorCopier, "gh:me/tmpl", "/code", "app"); err == nil {
+		t.Error("GeneratorCommand should fail when copier isn't installed")
+	}
+
+	for _, name := range []string{GeneratorCookiecutter, GeneratorCopier} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd, err := GeneratorCommand(GeneratorCookiecutter, "gh:me/tmpl", "/code", "app")
+	if err != nil {
+		t.Fatalf("GeneratorCommand failed: %v", err)
+	}
+	if want := []string{"cookiecutter", "gh:me/tmpl", "--output-dir", "/code", "project_name=app", "project_slug=app"}; !reflect.DeepEqual(cmd.Args, want) || cmd.Dir != "/code" {
+		t.Errorf("cookiecutter command = %v in %s, want %v", cmd.Args, cmd.Dir, want)
+	}
+	cmd, err = GeneratorCommand(GeneratorCopier, "gh:me/tmpl", "/code", "app")
+	if want := []string{"copier", "copy", "gh:me/tmpl", filepath.Join("/code", "app")}; err != nil || !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("copier command = %v, %v; want %v", cmd.Args, err, want)
+	}
+}
@@ -0,0 +1,201 @@
+// Package scaffold generates common project files (.gitignore, LICENSE,
+// .editorconfig, a CI workflow) and plugin skeletons from templates. The templates are bundled
+// with proj and can be overridden by files at the same path under
+// ~/.config/proj/templates.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/s33g/proj/internal/config"
+)
+
+//go:embed templates
+var bundled embed.FS
+
+// Paths of the files scaffolding writes, relative to the project root
+const (
+	GitignorePath    = ".gitignore"
+	LicensePath      = "LICENSE"
+	EditorConfigPath = ".editorconfig"
+	CIPath           = ".github/workflows/ci.yml"
+)
+
+// File is a rendered file ready to be written into a project
+type File struct {
+	Path    string // Relative to the project root, slash-separated
+	Content []byte
+}
+
+// Data is what templates can refer to: {{.Project}}, {{.Author}}, {{.Year}}
+type Data struct {
+	Project string
+	Author  string
+	Year    int
+}
+
+// templateKeys maps project languages to the name of their .gitignore and
+// CI templates. Languages that share a toolchain share a template.
+var templateKeys = map[string]string{
+	"Go":         "go",
+	"Rust":       "rust",
+	"TypeScript": "node",
+	"JavaScript": "node",
+	"Python":     "python",
+	"Java":       "jvm",
+	"Kotlin":     "jvm",
+	"Scala":      "jvm",
+	"Clojure":    "jvm",
+	"C#":         "csharp",
+	"Ruby":       "ruby",
+	"PHP":        "php",
+	"Swift":      "swift",
+	"C/C++":      "c-cpp",
+	"Elixir":     "elixir",
+	"Zig":        "zig",
+	"Haskell":    "haskell",
+}
+
+// TemplateDir returns the directory whose files override the bundled templates
+func TemplateDir() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// License renders one of the licenses listed by Licenses
+func License(name string, data Data) (File, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return File{}, fmt.Errorf("invalid license name %q", name)
+	}
+	if !exists("license/" + name) {
+		return File{}, fmt.Errorf("no license template named %s", name)
+	}
+	return render("license/"+name, LicensePath, data)
+}
+
+// EditorConfig renders the .editorconfig
+func EditorConfig(data Data) (File, error) {
+	return render("editorconfig", EditorConfigPath, data)
+}
+
+// CI renders the GitHub Actions workflow for a language
+func CI(language string, data Data) (File, error) {
+	if !HasCI(language) {
+		return File{}, fmt.Errorf("no CI template for %s", language)
+	}
+	return render("ci/"+templateKeys[language]+".yml", CIPath, data)
+}
+
+// HasCI reports whether there is a CI template for a language
+func HasCI(language string) bool {
+	key, ok := templateKeys[language]
+	return ok && exists("ci/"+key+".yml")
+}
+
+// Licenses lists the available license templates, bundled and user-provided
+func Licenses() []string {
+	seen := make(map[string]bool)
+	if entries, err := fs.ReadDir(bundled, "templates/license"); err == nil {
+		for _, entry := range entries {
+			seen[entry.Name()] = true
+		}
+	}
+	if dir, err := TemplateDir(); err == nil {
+		if entries, err := os.ReadDir(filepath.Join(dir, "license")); err == nil {
+			for _, entry := range entries {
+				if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+					seen[entry.Name()] = true
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Exists reports whether a project already has the file at path
+func Exists(root, path string) bool {
+	_, err := os.Lstat(filepath.Join(root, filepath.FromSlash(path)))
+	return err == nil
+}
+
+// Write writes f into the project at root. It never overwrites an existing file.
+func Write(root string, f File) error {
+	dest := filepath.Join(root, filepath.FromSlash(f.Path))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists", f.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(f.Content); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// render executes the template called name (e.g. "ci/go.yml") into a File
+func render(name, dest string, data Data) (File, error) {
+	text, err := read(name)
+	if err != nil {
+		return File{}, err
+	}
+	tmpl, err := template.New(path.Base(name)).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return File{}, fmt.Errorf("template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return File{}, fmt.Errorf("template %s: %w", name, err)
+	}
+	return File{Path: dest, Content: buf.Bytes()}, nil
+}
+
+// read returns the user's override of a template if there is one, the
+// bundled template otherwise
+func read(name string) ([]byte, error) {
+	if dir, err := TemplateDir(); err == nil {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return fs.ReadFile(bundled, "templates/"+name)
+}
+
+// exists reports whether there is a template called name
+func exists(name string) bool {
+	if dir, err := TemplateDir(); err == nil {
+		if info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	_, err := fs.Stat(bundled, "templates/"+name)
+	return err == nil
+}
@@ -0,0 +1,126 @@
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLicense(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	f, err := License("MIT", Data{Author: "Jane Doe", Year: 2026})
+	if err != nil {
+		t.Fatalf("License(MIT) failed: %v", err)
+	}
+	if !strings.Contains(string(f.Content), "Copyright (c) 2026 Jane Doe") {
+		t.Errorf("License(MIT) did not fill in the copyright line:\n%s", f.Content)
+	}
+
+	for _, name := range []string{"GPL-42", "../editorconfig", ""} {
+		if _, err := License(name, Data{}); err == nil {
+			t.Errorf("License(%q) should fail", name)
+		}
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "proj", "templates")
+	os.MkdirAll(filepath.Join(dir, "license"), 0755)
+	os.MkdirAll(filepath.Join(dir, "ci"), 0755)
+	os.WriteFile(filepath.Join(dir, "editorconfig"), []byte("root = true # {{.Project}}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "license", "Company"), []byte("Proprietary, {{.Year}}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "ci", "ruby.yml"), []byte("name: Ruby\n"), 0644)
+
+	f, err := EditorConfig(Data{Project: "api"})
+	if err != nil || string(f.Content) != "root = true # api\n" {
+		t.Errorf("EditorConfig() = %q, %v; want the override", f.Content, err)
+	}
+
+	licenses := Licenses()
+	if !contains(licenses, "Company") || !contains(licenses, "MIT") {
+		t.Errorf("Licenses() = %v, want bundled and user licenses", licenses)
+	}
+	if f, err := License("Company", Data{Year: 2026}); err != nil || string(f.Content) != "Proprietary, 2026\n" {
+		t.Errorf("License(Company) = %q, %v", f.Content, err)
+	}
+
+	if !HasCI("Ruby") || HasCI("Haskell") {
+		t.Errorf("HasCI: Ruby %v, Haskell %v; want a user template for Ruby only", HasCI("Ruby"), HasCI("Haskell"))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	root := t.TempDir()
+	f := File{Path: CIPath, Content: []byte("name: CI\n")}
+
+	if err := Write(root, f); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !Exists(root, CIPath) {
+		t.Fatalf("Write did not create %s", CIPath)
+	}
+
+	f.Content = []byte("changed\n")
+	if err := Write(root, f); err == nil {
+		t.Error("Write should refuse to overwrite an existing file")
+	}
+	data, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(CIPath)))
+	if string(data) != "name: CI\n" {
+		t.Errorf("existing file was changed to %q", data)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPlugin(t *testing.T) {
+	home := os.Getenv("HOME") // For go's build cache
+	t.Setenv("HOME", t.TempDir())
+
+	for _, name := range []string{"", "My Plugin", "../x", "1st"} {
+		if _, err := Plugin(name); err == nil {
+			t.Errorf("Plugin(%q) should fail", name)
+		}
+	}
+
+	files, err := Plugin("deploy-tools")
+	if err != nil {
+		t.Fatalf("Plugin failed: %v", err)
+	}
+	dir := t.TempDir()
+	for _, f := range files {
+		if err := Write(dir, f); err != nil {
+			t.Fatalf("Write(%s) failed: %v", f.Path, err)
+		}
+	}
+
+	manifest, _ := os.ReadFile(filepath.Join(dir, "plugin.json"))
+	if !strings.Contains(string(manifest), `"executable": "deploy-tools"`) {
+		t.Errorf("plugin.json doesn't name the executable:\n%s", manifest)
+	}
+
+	// The skeleton builds and its tests pass
+	if testing.Short() {
+		t.Skip("skipping building the plugin skeleton in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "HOME="+home, "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go test in the plugin skeleton failed: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,239 @@
+package scripts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/s33g/proj/internal/results"
+)
+
+// CMake preset files, the project's and the user's own
+var cmakePresetFiles = []string{"CMakePresets.json", "CMakeUserPresets.json"}
+
+// cmakeBuildDir is where CMake projects without presets are built
+const cmakeBuildDir = "build"
+
+// cmakePreset is a configure, build or test preset
+type cmakePreset struct {
+	Name            string          `json:"name"`
+	DisplayName     string          `json:"displayName"`
+	Description     string          `json:"description"`
+	Hidden          bool            `json:"hidden"`
+	Inherits        json.RawMessage `json:"inherits"` // A name or a list of names
+	BinaryDir       string          `json:"binaryDir"`
+	ConfigurePreset string          `json:"configurePreset"`
+}
+
+// cmakePresets are the presets of a project's preset files
+type cmakePresets struct {
+	Configure []cmakePreset `json:"configurePresets"`
+	Build     []cmakePreset `json:"buildPresets"`
+	Test      []cmakePreset `json:"testPresets"`
+}
+
+// loadCMakePresets reads the preset files of a project. ok is false if it
+// has none.
+func loadCMakePresets(projectPath string) (presets cmakePresets, ok bool) {
+	for _, name := range cmakePresetFiles {
+		data, err := os.ReadFile(filepath.Join(projectPath, name))
+		if err != nil {
+			continue
+		}
+		var file cmakePresets
+		if json.Unmarshal(data, &file) != nil {
+			continue
+		}
+		ok = true
+		presets.Configure = append(presets.Configure, file.Configure...)
+		presets.Build = append(presets.Build, file.Build...)
+		presets.Test = append(presets.Test, file.Test...)
+	}
+	return presets, ok
+}
+
+// detectCMake offers configure, build and test actions for a CMake project:
+// one for each of its presets, or the usual out-of-source build in build/
+// when it has none
+func detectCMake(projectPath string) []Script {
+	if _, err := os.Stat(filepath.Join(projectPath, "CMakeLists.txt")); err != nil {
+		return nil
+	}
+
+	presets, ok := loadCMakePresets(projectPath)
+	if !ok {
+		return []Script{
+			{
+				ID:      "cmake-configure",
+				Name:    "configure",
+				Command: "cmake -S . -B " + cmakeBuildDir,
+				Desc:    "Configure into " + cmakeBuildDir + "/",
+				Source:  "cmake",
+			},
+			{
+				ID:      "cmake-build",
+				Name:    "build",
+				Command: "cmake --build " + cmakeBuildDir,
+				Desc:    "Build " + cmakeBuildDir + "/",
+				Source:  "cmake",
+			},
+			{
+				ID:      "cmake-test",
+				Name:    "test",
+				Command: "ctest --test-dir " + cmakeBuildDir + " --output-on-failure",
+				Desc:    "Run the tests with ctest",
+				Source:  "cmake",
+				Parser:  results.CTest,
+			},
+		}
+	}
+
+	var scripts []Script
+	built := map[string]bool{} // Configure presets a build preset builds
+	for _, p := range visible(presets.Build) {
+		built[p.ConfigurePreset] = true
+	}
+	for _, p := range visible(presets.Configure) {
+		scripts = append(scripts, Script{
+			ID:      "cmake-configure-" + p.Name,
+			Name:    "configure " + p.Name,
+			Command: "cmake --preset " + p.Name,
+			Desc:    presetDesc(p),
+			Source:  "cmake",
+		})
+		// Presets without a build preset are built in their binary directory
+		if built[p.Name] {
+			continue
+		}
+		if dir := presets.binaryDir(p); dir != "" {
+			scripts = append(scripts,
+				Script{
+					ID:      "cmake-build-" + p.Name,
+					Name:    "build " + p.Name,
+					Command: "cmake --build " + dir,
+					Desc:    "Build " + dir,
+					Source:  "cmake",
+				},
+				Script{
+					ID:      "cmake-test-" + p.Name,
+					Name:    "test " + p.Name,
+					Command: "ctest --test-dir " + dir + " --output-on-failure",
+					Desc:    "Run the tests in " + dir + " with ctest",
+					Source:  "cmake",
+					Parser:  results.CTest,
+				},
+			)
+		}
+	}
+	for _, p := range visible(presets.Build) {
+		scripts = append(scripts, Script{
+			ID:      "cmake-build-" + p.Name,
+			Name:    "build " + p.Name,
+			Command: "cmake --build --preset " + p.Name,
+			Desc:    presetDesc(p),
+			Source:  "cmake",
+		})
+	}
+	for _, p := range visible(presets.Test) {
+		scripts = append(scripts, Script{
+			ID:      "cmake-test-" + p.Name,
+			Name:    "test " + p.Name,
+			Command: "ctest --preset " + p.Name,
+			Desc:    presetDesc(p),
+			Source:  "cmake",
+			Parser:  results.CTest,
+		})
+	}
+	return scripts
+}
+
+// CMakeTestArgs returns the ctest command that runs the tests of the CMake
+// project at projectPath: its first test preset, else the build directory of
+// its first configure preset, else build/. It returns nil if the project
+// doesn't use CMake.
+func CMakeTestArgs(projectPath string) []string {
+	if _, err := os.Stat(filepath.Join(projectPath, "CMakeLists.txt")); err != nil {
+		return nil
+	}
+	presets, _ := loadCMakePresets(projectPath)
+	if tests := visible(presets.Test); len(tests) > 0 {
+		return []string{"ctest", "--preset", tests[0].Name}
+	}
+	dir := cmakeBuildDir
+	if configure := visible(presets.Configure); len(configure) > 0 {
+		if binary := presets.binaryDir(configure[0]); binary != "" {
+			dir = binary
+		}
+	}
+	return []string{"ctest", "--test-dir", dir, "--output-on-failure"}
+}
+
+// visible drops hidden presets, which only exist to be inherited
+func visible(presets []cmakePreset) []cmakePreset {
+	var shown []cmakePreset
+	for _, p := range presets {
+		if !p.Hidden && p.Name != "" {
+			shown = append(shown, p)
+		}
+	}
+	return shown
+}
+
+// presetDesc describes a preset by its description or display name
+func presetDesc(p cmakePreset) string {
+	if p.Description != "" {
+		return p.Description
+	}
+	return p.DisplayName
+}
+
+// binaryDir returns the build directory of a configure preset, relative to
+// the project. It returns "" if the preset has none, or it uses macros other
+// than ${sourceDir} and ${presetName}.
+func (c cmakePresets) binaryDir(p cmakePreset) string {
+	dir := c.declaredBinaryDir(p, 0)
+	if dir == "" {
+		return ""
+	}
+	// ${presetName} is the name of the preset being configured, not the
+	// one that declares binaryDir
+	dir = strings.ReplaceAll(dir, "${presetName}", p.Name)
+	dir = strings.TrimPrefix(strings.ReplaceAll(dir, "${sourceDir}", "."), "./")
+	if strings.Contains(dir, "$") || filepath.IsAbs(dir) {
+		return ""
+	}
+	return dir
+}
+
+// declaredBinaryDir returns the binaryDir a configure preset declares or
+// inherits, unexpanded
+func (c cmakePresets) declaredBinaryDir(p cmakePreset, depth int) string {
+	// Presets can't inherit in a cycle, but guard against broken files
+	if p.BinaryDir != "" || depth > 8 {
+		return p.BinaryDir
+	}
+	for _, parent := range inherited(p) {
+		for _, q := range c.Configure {
+			if q.Name == parent {
+				if dir := c.declaredBinaryDir(q, depth+1); dir != "" {
+					return dir
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// inherited returns the names of the presets a preset inherits from
+func inherited(p cmakePreset) []string {
+	var names []string
+	if json.Unmarshal(p.Inherits, &names) == nil {
+		return names
+	}
+	var name string
+	if json.Unmarshal(p.Inherits, &name) == nil && name != "" {
+		return []string{name}
+	}
+	return nil
+}
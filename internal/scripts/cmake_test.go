@@ -0,0 +1,73 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const cmakePresetsJSON = `{
+  "version": 6,
+  "configurePresets": [
+    {"name": "base", "hidden": true, "binaryDir": "${sourceDir}/out/${presetName}"},
+    {"name": "debug", "inherits": "base", "displayName": "Debug"},
+    {"name": "release", "inherits": ["base"], "description": "Optimized build"}
+  ],
+  "buildPresets": [
+    {"name": "release", "configurePreset": "release"}
+  ],
+  "testPresets": [
+    {"name": "release", "configurePreset": "release"}
+  ]
+}`
+
+func TestDetectCMake(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"CMakeLists.txt": "project(calc)\n",
+		"Makefile":       "build:\n\tcmake --build build\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	// Without presets, the usual build in build/
+	commands := map[string]string{}
+	for _, s := range Detect(dir, "C/C++") {
+		if s.Source == "Makefile" {
+			t.Errorf("Expected the Makefile of a CMake project to be skipped, got %s", s.ID)
+		}
+		commands[s.ID] = s.Command
+	}
+	if commands["cmake-test"] != "ctest --test-dir build --output-on-failure" {
+		t.Errorf("cmake-test = %q", commands["cmake-test"])
+	}
+	if got := CMakeTestArgs(dir); strings.Join(got, " ") != "ctest --test-dir build --output-on-failure" {
+		t.Errorf("CMakeTestArgs() = %v", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "CMakePresets.json"), []byte(cmakePresetsJSON), 0o644); err != nil {
+		t.Fatalf("failed to write presets: %v", err)
+	}
+	var got []string
+	for _, s := range detectCMake(dir) {
+		got = append(got, s.ID+": "+s.Command)
+	}
+	want := []string{
+		"cmake-configure-debug: cmake --preset debug",
+		"cmake-build-debug: cmake --build out/debug",
+		"cmake-test-debug: ctest --test-dir out/debug --output-on-failure",
+		"cmake-configure-release: cmake --preset release",
+		"cmake-build-release: cmake --build --preset release",
+		"cmake-test-release: ctest --preset release",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("detectCMake() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := CMakeTestArgs(dir); strings.Join(got, " ") != "ctest --preset release" {
+		t.Errorf("CMakeTestArgs() = %v, want the test preset", got)
+	}
+}
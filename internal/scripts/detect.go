@@ -16,6 +16,7 @@ type Script struct {
 	Command string // The command to run
 	Desc    string // Description
 	Source  string // Where the script came from (package.json, Makefile, etc)
+	Parser  string // Output parser for a structured summary (see results.Parsers)
 }
 
 // Detect detects available scripts for a project
@@ -34,10 +35,18 @@ func Detect(projectPath, language string) []Script {
 		scripts = append(scripts, detectPythonScripts(projectPath)...)
 	case "Ruby":
 		scripts = append(scripts, detectRubyScripts(projectPath)...)
+	case "C#":
+		scripts = append(scripts, detectDotnet(projectPath)...)
 	}
 
-	// Universal detection (Makefile, shell scripts)
-	scripts = append(scripts, detectMakefile(projectPath)...)
+	// Universal detection (CMake or Makefile, shell scripts). A CMake
+	// project's Makefile is generated by it or wraps it, so its presets
+	// are offered instead.
+	if cmake := detectCMake(projectPath); len(cmake) > 0 {
+		scripts = append(scripts, cmake...)
+	} else {
+		scripts = append(scripts, detectMakefile(projectPath)...)
+	}
 	scripts = append(scripts, detectShellScripts(projectPath)...)
 	scripts = append(scripts, detectJustfile(projectPath)...)
 
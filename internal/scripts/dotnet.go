@@ -0,0 +1,253 @@
+package scripts
+
+import (
+	"bufio"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dotnetProjectExts are the extensions of .NET project files
+var dotnetProjectExts = []string{".csproj", ".fsproj", ".vbproj"}
+
+// dotnetSkipDirs are never searched for project files
+var dotnetSkipDirs = map[string]bool{"bin": true, "obj": true, "node_modules": true, ".git": true}
+
+// slnProject matches a project line of a .sln file:
+// Project("{type}") = "Name", "path\to\Name.csproj", "{guid}"
+var slnProject = regexp.MustCompile(`^Project\("\{[^}]+\}"\)\s*=\s*"([^"]+)",\s*"([^"]+)"`)
+
+var (
+	dotnetTestSdk = regexp.MustCompile(`Microsoft\.NET\.Test\.Sdk|<IsTestProject>\s*true\s*</IsTestProject>`)
+	dotnetExe     = regexp.MustCompile(`(?i)<OutputType>\s*(Exe|WinExe)\s*</OutputType>|Sdk="Microsoft\.NET\.Sdk\.(Web|Worker|BlazorWebAssembly)"`)
+)
+
+// dotnetProject is a project of a .NET solution
+type dotnetProject struct {
+	Name     string
+	Path     string // Relative to the solution, with forward slashes
+	Test     bool   // References the test SDK
+	Runnable bool   // Builds an executable, web app or worker
+}
+
+// detectDotnet offers build and test actions for a .NET solution and
+// build, test and run actions for each of its projects. Without a solution
+// file the project files in the first two levels of the tree are used.
+func detectDotnet(projectPath string) []Script {
+	solution := findSolution(projectPath)
+	var projects []dotnetProject
+	if solution != "" {
+		projects = solutionProjects(projectPath, solution)
+	} else {
+		projects = findDotnetProjects(projectPath)
+	}
+
+	var scripts []Script
+	if solution != "" {
+		scripts = append(scripts,
+			Script{
+				ID:      "dotnet-build",
+				Name:    "build",
+				Command: "dotnet build " + quoteArg(solution),
+				Desc:    "Build every project in " + solution,
+				Source:  "dotnet",
+			},
+			Script{
+				ID:      "dotnet-projects",
+				Name:    "list projects",
+				Command: "dotnet sln " + quoteArg(solution) + " list",
+				Desc:    "List the projects in " + solution,
+				Source:  "dotnet",
+			},
+		)
+		for _, p := range projects {
+			if p.Test {
+				scripts = append(scripts, Script{
+					ID:      "dotnet-test",
+					Name:    "test",
+					Command: "dotnet test " + quoteArg(solution),
+					Desc:    "Run the tests of every project in " + solution,
+					Source:  "dotnet",
+				})
+				break
+			}
+		}
+	}
+
+	for _, p := range projects {
+		path := quoteArg(p.Path)
+		scripts = append(scripts, Script{
+			ID:      "dotnet-build-" + p.Name,
+			Name:    "build " + p.Name,
+			Command: "dotnet build " + path,
+			Desc:    "Build " + p.Path,
+			Source:  "dotnet",
+		})
+		if p.Test {
+			scripts = append(scripts, Script{
+				ID:      "dotnet-test-" + p.Name,
+				Name:    "test " + p.Name,
+				Command: "dotnet test " + path,
+				Desc:    "Run the tests in " + p.Path,
+				Source:  "dotnet",
+			})
+		}
+		if p.Runnable {
+			scripts = append(scripts, Script{
+				ID:      "dotnet-run-" + p.Name,
+				Name:    "run " + p.Name,
+				Command: "dotnet run --project " + path,
+				Desc:    "Run " + p.Path,
+				Source:  "dotnet",
+			})
+		}
+	}
+	return scripts
+}
+
+// findSolution returns the first solution file in a project's root, or ""
+func findSolution(projectPath string) string {
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".sln" || ext == ".slnx") {
+			return e.Name()
+		}
+	}
+	return ""
+}
+
+// solutionProjects returns the projects of a .sln or .slnx solution,
+// skipping solution folders and projects that no longer exist
+func solutionProjects(projectPath, solution string) []dotnetProject {
+	var paths []string
+	if filepath.Ext(solution) == ".slnx" {
+		paths = slnxProjectPaths(filepath.Join(projectPath, solution))
+	} else {
+		file, err := os.Open(filepath.Join(projectPath, solution))
+		if err != nil {
+			return nil
+		}
+		defer func() { _ = file.Close() }()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if m := slnProject.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+				paths = append(paths, m[2])
+			}
+		}
+	}
+
+	var projects []dotnetProject
+	for _, path := range paths {
+		// Solutions written on Windows separate paths with backslashes
+		path = strings.ReplaceAll(path, `\`, "/")
+		if !isDotnetProject(path) {
+			continue
+		}
+		if p, ok := loadDotnetProject(projectPath, path); ok {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// slnxProjectPaths returns the paths of the <Project> elements of an XML
+// solution, in or out of folders
+func slnxProjectPaths(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var paths []string
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return paths
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Project" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "Path" {
+				paths = append(paths, attr.Value)
+			}
+		}
+	}
+}
+
+// findDotnetProjects returns the project files in the first two levels of
+// a tree without a solution, sorted by path
+func findDotnetProjects(projectPath string) []dotnetProject {
+	var paths []string
+	for _, pattern := range []string{"*", "*/*", "*/*/*"} {
+		matches, _ := filepath.Glob(filepath.Join(projectPath, pattern))
+		for _, match := range matches {
+			rel, err := filepath.Rel(projectPath, match)
+			if err != nil || !isDotnetProject(rel) {
+				continue
+			}
+			skip := false
+			for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+				skip = skip || dotnetSkipDirs[dir]
+			}
+			if !skip {
+				paths = append(paths, filepath.ToSlash(rel))
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	var projects []dotnetProject
+	for _, path := range paths {
+		if p, ok := loadDotnetProject(projectPath, path); ok {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// loadDotnetProject reads a project file to tell test and runnable
+// projects from libraries
+func loadDotnetProject(projectPath, path string) (dotnetProject, bool) {
+	data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(path)))
+	if err != nil {
+		return dotnetProject{}, false
+	}
+	test := dotnetTestSdk.Match(data)
+	return dotnetProject{
+		Name:     strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path:     path,
+		Test:     test,
+		Runnable: !test && dotnetExe.Match(data),
+	}, true
+}
+
+// isDotnetProject reports whether a path is a .NET project file
+func isDotnetProject(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range dotnetProjectExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteArg quotes a command argument containing spaces
+func quoteArg(arg string) string {
+	if strings.ContainsAny(arg, " \t") {
+		return "'" + arg + "'"
+	}
+	return arg
+}
@@ -0,0 +1,98 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const dotnetSolution = `
+Microsoft Visual Studio Solution File, Format Version 12.00
+Project("{2150E333-8FDC-42A3-9474-1A3956D46DE8}") = "src", "src", "{11111111-1111-1111-1111-111111111111}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "Shop.Api", "src\Shop.Api\Shop.Api.csproj", "{22222222-2222-2222-2222-222222222222}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "Shop.Core", "src\Shop.Core\Shop.Core.csproj", "{33333333-3333-3333-3333-333333333333}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "Shop.Tests", "tests\Shop.Tests\Shop.Tests.csproj", "{44444444-4444-4444-4444-444444444444}"
+EndProject
+`
+
+func TestDetectDotnet(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"src/Shop.Api/Shop.Api.csproj":       `<Project Sdk="Microsoft.NET.Sdk.Web"></Project>`,
+		"src/Shop.Core/Shop.Core.csproj":     `<Project Sdk="Microsoft.NET.Sdk"></Project>`,
+		"tests/Shop.Tests/Shop.Tests.csproj": `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup><PackageReference Include="Microsoft.NET.Test.Sdk" /></ItemGroup></Project>`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	// Without a solution, the project files found in the tree
+	var got []string
+	for _, s := range Detect(dir, "C#") {
+		got = append(got, s.ID+": "+s.Command)
+	}
+	want := []string{
+		"dotnet-build-Shop.Api: dotnet build src/Shop.Api/Shop.Api.csproj",
+		"dotnet-run-Shop.Api: dotnet run --project src/Shop.Api/Shop.Api.csproj",
+		"dotnet-build-Shop.Core: dotnet build src/Shop.Core/Shop.Core.csproj",
+		"dotnet-build-Shop.Tests: dotnet build tests/Shop.Tests/Shop.Tests.csproj",
+		"dotnet-test-Shop.Tests: dotnet test tests/Shop.Tests/Shop.Tests.csproj",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Detect() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "My Shop.sln"), []byte(dotnetSolution), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scripts := detectDotnet(dir)
+	got = nil
+	for _, s := range scripts[:3] {
+		got = append(got, s.ID+": "+s.Command)
+	}
+	want = []string{
+		"dotnet-build: dotnet build 'My Shop.sln'",
+		"dotnet-projects: dotnet sln 'My Shop.sln' list",
+		"dotnet-test: dotnet test 'My Shop.sln'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("solution actions =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(scripts) != 8 {
+		t.Errorf("Expected 3 solution and 5 project actions, got %d", len(scripts))
+	}
+}
+
+func TestSlnxProjects(t *testing.T) {
+	dir := t.TempDir()
+	slnx := `<Solution>
+  <Folder Name="/src/">
+    <Project Path="src/Cli/Cli.fsproj" />
+  </Folder>
+  <Project Path="Missing/Missing.csproj" />
+</Solution>`
+	if err := os.WriteFile(filepath.Join(dir, "App.slnx"), []byte(slnx), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "src", "Cli"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "Cli", "Cli.fsproj"), []byte("<Project><PropertyGroup><OutputType>Exe</OutputType></PropertyGroup></Project>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []dotnetProject{{Name: "Cli", Path: "src/Cli/Cli.fsproj", Runnable: true}}
+	if got := solutionProjects(dir, findSolution(dir)); !reflect.DeepEqual(got, want) {
+		t.Errorf("solutionProjects() = %+v, want %+v", got, want)
+	}
+}
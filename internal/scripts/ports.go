@@ -0,0 +1,78 @@
+package scripts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// portFlag matches a port set on a command line: --port 4000, -p 4000,
+// PORT=4000 or an address such as 0.0.0.0:4000
+var portFlag = regexp.MustCompile(`(?:--port[= ]|-p[= ]?|\bPORT=|(?:localhost|127\.0\.0\.1|0\.0\.0\.0):)(\d{2,5})\b`)
+
+// defaultPorts are the ports dev servers listen on unless told otherwise,
+// by the command that runs them
+var defaultPorts = []struct {
+	command *regexp.Regexp
+	port    int
+}{
+	{regexp.MustCompile(`\bvite\b`), 5173},
+	{regexp.MustCompile(`\bastro\b`), 4321},
+	{regexp.MustCompile(`\bng serve\b`), 4200},
+	{regexp.MustCompile(`\b(next|nuxt|nuxi|react-scripts start)\b`), 3000},
+	{regexp.MustCompile(`\brails (s|server)\b`), 3000},
+	{regexp.MustCompile(`\bgatsby develop\b`), 8000},
+	{regexp.MustCompile(`\bwebpack(-dev-server| serve)\b`), 8080},
+	{regexp.MustCompile(`\bparcel\b`), 1234},
+	{regexp.MustCompile(`\bstorybook dev\b|\bstart-storybook\b`), 6006},
+	{regexp.MustCompile(`\bwrangler dev\b`), 8787},
+	{regexp.MustCompile(`\bhugo server\b`), 1313},
+	{regexp.MustCompile(`\bjekyll serve\b`), 4000},
+	{regexp.MustCompile(`\bphx\.server\b`), 4000},
+	{regexp.MustCompile(`\bmkdocs serve\b|\bmanage\.py runserver\b|\buvicorn\b`), 8000},
+	{regexp.MustCompile(`\bflask run\b`), 5000},
+}
+
+// Port returns the port a dev server command will likely listen on: the one
+// set on its command line, or failing that its framework's default. For a
+// package.json script run through a package manager, the script itself is
+// looked at. It returns 0 if there's no telling.
+func Port(projectPath, command string) int {
+	if script := packageScript(projectPath, command); script != "" {
+		command = script
+	}
+	if match := portFlag.FindStringSubmatch(command); match != nil {
+		if port, err := strconv.Atoi(match[1]); err == nil && port > 0 && port < 65536 {
+			return port
+		}
+	}
+	for _, d := range defaultPorts {
+		if d.command.MatchString(command) {
+			return d.port
+		}
+	}
+	return 0
+}
+
+// packageScript returns the package.json script a command such as
+// "pnpm run dev" runs, or ""
+func packageScript(projectPath, command string) string {
+	fields := strings.Fields(command)
+	if len(fields) != 3 || fields[1] != "run" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Scripts[fields[2]]
+}
@@ -0,0 +1,35 @@
+package scripts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPort(t *testing.T) {
+	dir := t.TempDir()
+	pkg := `{"scripts": {"dev": "vite --host", "preview": "vite preview --port 4000", "start": "node server.js"}}`
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command string
+		want    int
+	}{
+		{"npm run dev", 5173},
+		{"pnpm run preview", 4000},
+		{"npm run start", 0},
+		{"PORT=8081 node server.js", 8081},
+		{"python manage.py runserver", 8000},
+		{"python manage.py runserver 0.0.0.0:9000", 9000},
+		{"hugo server -D", 1313},
+		{"bundle exec rails s -p 3001", 3001},
+		{"go run ./cmd/server", 0},
+	}
+	for _, tt := range tests {
+		if got := Port(dir, tt.command); got != tt.want {
+			t.Errorf("Port(%q) = %d, want %d", tt.command, got, tt.want)
+		}
+	}
+}
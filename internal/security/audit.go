@@ -0,0 +1,306 @@
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Vulnerability is a known vulnerability in a project dependency
+type Vulnerability struct {
+	ID       string
+	Package  string
+	Severity Severity
+	Title    string
+}
+
+// AuditReport is the result of a dependency audit
+type AuditReport struct {
+	Tool  string
+	Vulns []Vulnerability
+}
+
+// Counts tallies the report's vulnerabilities per severity
+func (r *AuditReport) Counts() Counts {
+	counts := Counts{}
+	for _, v := range r.Vulns {
+		counts[v.Severity]++
+	}
+	return counts
+}
+
+// String renders the report for the result view
+func (r *AuditReport) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Audited with %s\n\n", r.Tool))
+
+	if len(r.Vulns) == 0 {
+		b.WriteString("No known vulnerabilities")
+		return b.String()
+	}
+
+	b.WriteString(r.Counts().Summary())
+	b.WriteString("\n\n")
+	for _, v := range r.Vulns {
+		line := fmt.Sprintf("[%-8s] %s  %s", v.Severity, v.Package, v.ID)
+		if v.Title != "" {
+			line += "  " + v.Title
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// auditor describes how to audit dependencies for one ecosystem
+type auditor struct {
+	tool    string
+	args    []string
+	install string // How to install the tool
+	parse   func([]byte) ([]Vulnerability, error)
+}
+
+var (
+	govulncheck = auditor{"govulncheck", []string{"-json", "./..."}, "go install golang.org/x/vuln/cmd/govulncheck@latest", parseGovulncheck}
+	npmAudit    = auditor{"npm", []string{"audit", "--json"}, "install Node.js", parseNpmAudit}
+	cargoAudit  = auditor{"cargo-audit", []string{"audit", "--json"}, "cargo install cargo-audit", parseCargoAudit}
+	pipAudit    = auditor{"pip-audit", []string{"-f", "json"}, "pip install pip-audit", parsePipAudit}
+)
+
+// auditors maps project languages to their audit tool
+var auditors = map[string]auditor{
+	"Go":         govulncheck,
+	"JavaScript": npmAudit,
+	"TypeScript": npmAudit,
+	"Rust":       cargoAudit,
+	"Python":     pipAudit,
+}
+
+// CanAudit reports whether dependency audits are supported for a language
+func CanAudit(language string) bool {
+	_, ok := auditors[language]
+	return ok
+}
+
+// Audit checks a project's dependencies for known vulnerabilities using the
+// ecosystem's audit tool
+func Audit(root, language string) (*AuditReport, error) {
+	a, ok := auditors[language]
+	if !ok {
+		return nil, fmt.Errorf("dependency audits are not supported for %s projects", language)
+	}
+
+	if _, err := lookPath(a.tool); err != nil {
+		return nil, fmt.Errorf("%s not found in PATH (%s)", a.tool, a.install)
+	}
+
+	// cargo audit is invoked as "cargo audit" once cargo-audit is installed
+	name := a.tool
+	if name == "cargo-audit" {
+		name = "cargo"
+	}
+
+	cmd := exec.Command(name, a.args...)
+	cmd.Dir = root
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	// Most audit tools exit non-zero when they find something, so only treat
+	// the run as failed when there's no report to parse
+	runErr := cmd.Run()
+	if out.Len() == 0 && runErr != nil {
+		return nil, fmt.Errorf("%s failed: %v\n%s", a.tool, runErr, strings.TrimSpace(stderr.String()))
+	}
+
+	vulns, err := a.parse(out.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].Severity != vulns[j].Severity {
+			return vulns[i].Severity > vulns[j].Severity
+		}
+		return vulns[i].Package < vulns[j].Package
+	})
+
+	return &AuditReport{Tool: a.tool, Vulns: vulns}, nil
+}
+
+// parseGovulncheck parses the govulncheck JSON message stream. Govulncheck has
+// no severity: vulnerabilities in code that is actually called are reported
+// as high, ones that are only imported as low.
+func parseGovulncheck(data []byte) ([]Vulnerability, error) {
+	type frame struct {
+		Module   string `json:"module"`
+		Function string `json:"function"`
+	}
+	type message struct {
+		OSV *struct {
+			ID      string `json:"id"`
+			Summary string `json:"summary"`
+		} `json:"osv"`
+		Finding *struct {
+			OSV   string  `json:"osv"`
+			Trace []frame `json:"trace"`
+		} `json:"finding"`
+	}
+
+	titles := map[string]string{}
+	found := map[string]*Vulnerability{}
+	order := []string{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var msg message
+		if err := dec.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse govulncheck output: %w", err)
+		}
+
+		if msg.OSV != nil {
+			titles[msg.OSV.ID] = msg.OSV.Summary
+		}
+		if msg.Finding == nil {
+			continue
+		}
+
+		id := msg.Finding.OSV
+		v, ok := found[id]
+		if !ok {
+			v = &Vulnerability{ID: id, Severity: SeverityLow}
+			found[id] = v
+			order = append(order, id)
+		}
+		for _, f := range msg.Finding.Trace {
+			if v.Package == "" && f.Module != "" {
+				v.Package = f.Module
+			}
+			if f.Function != "" {
+				v.Severity = SeverityHigh
+			}
+		}
+	}
+
+	vulns := make([]Vulnerability, 0, len(order))
+	for _, id := range order {
+		v := found[id]
+		v.Title = titles[id]
+		vulns = append(vulns, *v)
+	}
+	return vulns, nil
+}
+
+// parseNpmAudit parses npm audit --json (npm 7+)
+func parseNpmAudit(data []byte) ([]Vulnerability, error) {
+	var report struct {
+		Vulnerabilities map[string]struct {
+			Name     string            `json:"name"`
+			Severity string            `json:"severity"`
+			Via      []json.RawMessage `json:"via"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse npm audit output: %w", err)
+	}
+
+	vulns := make([]Vulnerability, 0, len(report.Vulnerabilities))
+	for name, v := range report.Vulnerabilities {
+		vuln := Vulnerability{Package: name, Severity: ParseSeverity(v.Severity)}
+
+		// "via" holds advisories, or names of vulnerable dependencies
+		for _, via := range v.Via {
+			var advisory struct {
+				Title string `json:"title"`
+				URL   string `json:"url"`
+			}
+			if json.Unmarshal(via, &advisory) == nil && advisory.Title != "" {
+				vuln.Title = advisory.Title
+				vuln.ID = advisory.URL[strings.LastIndex(advisory.URL, "/")+1:]
+				break
+			}
+			var dependency string
+			if json.Unmarshal(via, &dependency) == nil {
+				vuln.Title = "via " + dependency
+			}
+		}
+		vulns = append(vulns, vuln)
+	}
+	return vulns, nil
+}
+
+// parseCargoAudit parses cargo audit --json. RustSec advisories carry a CVSS
+// vector rather than a severity, so they are reported as unknown.
+func parseCargoAudit(data []byte) ([]Vulnerability, error) {
+	var report struct {
+		Vulnerabilities struct {
+			List []struct {
+				Advisory struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+				} `json:"advisory"`
+				Package struct {
+					Name string `json:"name"`
+				} `json:"package"`
+			} `json:"list"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse cargo audit output: %w", err)
+	}
+
+	vulns := make([]Vulnerability, 0, len(report.Vulnerabilities.List))
+	for _, v := range report.Vulnerabilities.List {
+		vulns = append(vulns, Vulnerability{
+			ID:       v.Advisory.ID,
+			Package:  v.Package.Name,
+			Severity: SeverityUnknown,
+			Title:    v.Advisory.Title,
+		})
+	}
+	return vulns, nil
+}
+
+// parsePipAudit parses pip-audit -f json. pip-audit reports no severity.
+func parsePipAudit(data []byte) ([]Vulnerability, error) {
+	type dependency struct {
+		Name  string `json:"name"`
+		Vulns []struct {
+			ID          string   `json:"id"`
+			FixVersions []string `json:"fix_versions"`
+		} `json:"vulns"`
+	}
+
+	// Newer versions wrap the list in {"dependencies": [...]}
+	var deps []dependency
+	var wrapped struct {
+		Dependencies []dependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err == nil && wrapped.Dependencies != nil {
+		deps = wrapped.Dependencies
+	} else if err := json.Unmarshal(data, &deps); err != nil {
+		return nil, fmt.Errorf("failed to parse pip-audit output: %w", err)
+	}
+
+	vulns := make([]Vulnerability, 0)
+	for _, d := range deps {
+		for _, v := range d.Vulns {
+			title := ""
+			if len(v.FixVersions) > 0 {
+				title = "fixed in " + strings.Join(v.FixVersions, ", ")
+			}
+			vulns = append(vulns, Vulnerability{
+				ID:       v.ID,
+				Package:  d.Name,
+				Severity: SeverityUnknown,
+				Title:    title,
+			})
+		}
+	}
+	return vulns, nil
+}
@@ -0,0 +1,117 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGovulncheck(t *testing.T) {
+	data := []byte(`{"config":{"scanner_name":"govulncheck"}}
+{"osv":{"id":"GO-2024-0001","summary":"Denial of service in x/net"}}
+{"osv":{"id":"GO-2024-0002","summary":"Bug in x/text"}}
+{
+  "finding": {"osv":"GO-2024-0001","trace":[{"module":"golang.org/x/net","version":"v0.1.0","function":"Parse"}]}
+}
+{"finding":{"osv":"GO-2024-0002","trace":[{"module":"golang.org/x/text","version":"v0.3.0"}]}}
+`)
+
+	vulns, err := parseGovulncheck(data)
+	if err != nil {
+		t.Fatalf("parseGovulncheck failed: %v", err)
+	}
+	if len(vulns) != 2 {
+		t.Fatalf("Expected 2 vulnerabilities, got %+v", vulns)
+	}
+	if vulns[0].Severity != SeverityHigh || vulns[0].Package != "golang.org/x/net" || vulns[0].Title != "Denial of service in x/net" {
+		t.Errorf("Unexpected called vulnerability: %+v", vulns[0])
+	}
+	if vulns[1].Severity != SeverityLow {
+		t.Errorf("Expected imported-only vulnerability to be low, got %s", vulns[1].Severity)
+	}
+}
+
+func TestParseNpmAudit(t *testing.T) {
+	data := []byte(`{
+  "vulnerabilities": {
+    "minimist": {"name":"minimist","severity":"critical","via":[{"title":"Prototype Pollution","url":"https://github.com/advisories/GHSA-xvch-5gv4-984h"}]},
+    "mkdirp": {"name":"mkdirp","severity":"moderate","via":["minimist"]}
+  },
+  "metadata": {"vulnerabilities": {"critical": 1, "moderate": 1, "total": 2}}
+}`)
+
+	vulns, err := parseNpmAudit(data)
+	if err != nil {
+		t.Fatalf("parseNpmAudit failed: %v", err)
+	}
+
+	counts := (&AuditReport{Vulns: vulns}).Counts()
+	if counts[SeverityCritical] != 1 || counts[SeverityMedium] != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+	for _, v := range vulns {
+		if v.Package == "minimist" && (v.ID != "GHSA-xvch-5gv4-984h" || v.Title != "Prototype Pollution") {
+			t.Errorf("Unexpected advisory details: %+v", v)
+		}
+		if v.Package == "mkdirp" && v.Title != "via minimist" {
+			t.Errorf("Expected transitive title, got %+v", v)
+		}
+	}
+}
+
+func TestParseCargoAudit(t *testing.T) {
+	data := []byte(`{"vulnerabilities":{"found":true,"count":1,"list":[{"advisory":{"id":"RUSTSEC-2020-0071","title":"Potential segfault in time"},"package":{"name":"time","version":"0.1.43"}}]}}`)
+
+	vulns, err := parseCargoAudit(data)
+	if err != nil {
+		t.Fatalf("parseCargoAudit failed: %v", err)
+	}
+	if len(vulns) != 1 || vulns[0].ID != "RUSTSEC-2020-0071" || vulns[0].Package != "time" {
+		t.Errorf("Unexpected vulnerabilities: %+v", vulns)
+	}
+}
+
+func TestParsePipAudit(t *testing.T) {
+	wrapped := []byte(`{"dependencies":[{"name":"flask","version":"0.5","vulns":[{"id":"PYSEC-2019-179","fix_versions":["1.0"]}]},{"name":"click","version":"8.0","vulns":[]}]}`)
+	legacy := []byte(`[{"name":"flask","version":"0.5","vulns":[{"id":"PYSEC-2019-179","fix_versions":["1.0"]}]}]`)
+
+	for _, data := range [][]byte{wrapped, legacy} {
+		vulns, err := parsePipAudit(data)
+		if err != nil {
+			t.Fatalf("parsePipAudit failed: %v", err)
+		}
+		if len(vulns) != 1 || vulns[0].Package != "flask" || vulns[0].Title != "fixed in 1.0" {
+			t.Errorf("Unexpected vulnerabilities: %+v", vulns)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	if _, err := Audit(t.TempDir(), "COBOL"); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+
+	// Fake npm that reports a vulnerability and exits non-zero like the real one
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho '{\"vulnerabilities\":{\"lodash\":{\"name\":\"lodash\",\"severity\":\"high\",\"via\":[]}}}'\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, "npm"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake npm: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	report, err := Audit(t.TempDir(), "TypeScript")
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+	if len(report.Vulns) != 1 || report.Vulns[0].Severity != SeverityHigh {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if !strings.Contains(report.String(), "High: 1") {
+		t.Errorf("Expected normalized summary, got:\n%s", report.String())
+	}
+
+	if _, err := Audit(t.TempDir(), "Go"); err == nil || !strings.Contains(err.Error(), "govulncheck") {
+		t.Errorf("Expected missing govulncheck error, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Secret scanning tools, in order of preference
+const (
+	ToolGitleaks   = "gitleaks"
+	ToolTrufflehog = "trufflehog"
+	ToolBuiltin    = "built-in rules"
+)
+
+// maxScanFileSize skips large files (likely data or build output) in the built-in scanner
+const maxScanFileSize = 1 << 20
+
+// lookPath is swapped out in tests
+var lookPath = exec.LookPath
+
+// SecretFinding is a potential secret found in the working tree.
+// The secret itself is never stored.
+type SecretFinding struct {
+	Rule     string
+	File     string // Relative to the project root
+	Line     int
+	Severity Severity
+}
+
+// SecretReport is the result of a secret scan
+type SecretReport struct {
+	Tool     string
+	Findings []SecretFinding
+}
+
+// Counts tallies the report's findings per severity
+func (r *SecretReport) Counts() Counts {
+	counts := Counts{}
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
+
+// String renders the report for the result view
+func (r *SecretReport) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Scanned with %s\n\n", r.Tool))
+
+	if len(r.Findings) == 0 {
+		b.WriteString("No secrets found")
+		return b.String()
+	}
+
+	b.WriteString(r.Counts().Summary())
+	b.WriteString("\n\n")
+	for _, f := range r.Findings {
+		b.WriteString(fmt.Sprintf("[%-8s] %s:%d  %s\n", f.Severity, f.File, f.Line, f.Rule))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ScanSecrets scans the working tree at root with gitleaks or trufflehog when
+// installed, falling back to built-in rules. excludes are directory or file
+// names skipped by the built-in scanner.
+func ScanSecrets(root string, excludes []string) (*SecretReport, error) {
+	if _, err := lookPath(ToolGitleaks); err == nil {
+		return runGitleaks(root)
+	}
+	if _, err := lookPath(ToolTrufflehog); err == nil {
+		return runTrufflehog(root)
+	}
+	return scanBuiltin(root, excludes)
+}
+
+// runGitleaks scans with gitleaks, which reports findings as a JSON array
+func runGitleaks(root string) (*SecretReport, error) {
+	reportFile, err := os.CreateTemp("", "proj-gitleaks-*.json")
+	if err != nil {
+		return nil, err
+	}
+	reportFile.Close()
+	defer os.Remove(reportFile.Name())
+
+	cmd := exec.Command(ToolGitleaks, "detect", "--no-git", "--source", root,
+		"--report-format", "json", "--report-path", reportFile.Name(), "--exit-code", "0", "--redact")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gitleaks failed: %v\n%s", err, strings.TrimSpace(out.String()))
+	}
+
+	data, err := os.ReadFile(reportFile.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := parseGitleaks(data, root)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretReport{Tool: ToolGitleaks, Findings: findings}, nil
+}
+
+// parseGitleaks parses a gitleaks JSON report. Gitleaks has no severity, so
+// everything it finds is treated as high.
+func parseGitleaks(data []byte, root string) ([]SecretFinding, error) {
+	var raw []struct {
+		RuleID    string `json:"RuleID"`
+		File      string `json:"File"`
+		StartLine int    `json:"StartLine"`
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse gitleaks report: %w", err)
+	}
+
+	findings := make([]SecretFinding, 0, len(raw))
+	for _, r := range raw {
+		findings = append(findings, SecretFinding{
+			Rule:     r.RuleID,
+			File:     relPath(root, r.File),
+			Line:     r.StartLine,
+			Severity: SeverityHigh,
+		})
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// runTrufflehog scans with trufflehog, which prints one JSON object per finding
+func runTrufflehog(root string) (*SecretReport, error) {
+	cmd := exec.Command(ToolTrufflehog, "filesystem", root, "--json", "--no-update")
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("trufflehog failed: %v\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	findings, err := parseTrufflehog(out.Bytes(), root)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretReport{Tool: ToolTrufflehog, Findings: findings}, nil
+}
+
+// parseTrufflehog parses trufflehog JSON lines. Verified secrets are critical,
+// unverified ones high.
+func parseTrufflehog(data []byte, root string) ([]SecretFinding, error) {
+	findings := make([]SecretFinding, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var r struct {
+			DetectorName   string `json:"DetectorName"`
+			Verified       bool   `json:"Verified"`
+			SourceMetadata struct {
+				Data struct {
+					Filesystem struct {
+						File string `json:"file"`
+						Line int    `json:"line"`
+					} `json:"Filesystem"`
+				} `json:"Data"`
+			} `json:"SourceMetadata"`
+		}
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse trufflehog output: %w", err)
+		}
+		if r.DetectorName == "" {
+			// Log lines are interleaved with findings
+			continue
+		}
+
+		severity := SeverityHigh
+		if r.Verified {
+			severity = SeverityCritical
+		}
+		fsMeta := r.SourceMetadata.Data.Filesystem
+		findings = append(findings, SecretFinding{
+			Rule:     r.DetectorName,
+			File:     relPath(root, fsMeta.File),
+			Line:     fsMeta.Line,
+			Severity: severity,
+		})
+	}
+
+	sortFindings(findings)
+	return findings, scanner.Err()
+}
+
+// secretRule is a built-in pattern for the fallback scanner
+type secretRule struct {
+	name     string
+	pattern  *regexp.Regexp
+	severity Severity
+}
+
+var secretRules = []secretRule{
+	{"private-key", regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY( BLOCK)?-----`), SeverityCritical},
+	{"aws-access-key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), SeverityCritical},
+	{"github-token", regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{22,}\b`), SeverityHigh},
+	{"slack-token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`), SeverityHigh},
+	{"stripe-secret-key", regexp.MustCompile(`\bsk_live_[A-Za-z0-9]{20,}\b`), SeverityHigh},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`), SeverityHigh},
+	{"generic-secret", regexp.MustCompile(`(?i)\b(api[_-]?key|secret|token|passw(or)?d)\b\s*[:=]\s*["'][^"'\s]{8,}["']`), SeverityMedium},
+}
+
+// scanBuiltin scans text files under root with the built-in rules
+func scanBuiltin(root string, excludes []string) (*SecretReport, error) {
+	skip := map[string]bool{".git": true}
+	for _, e := range excludes {
+		skip[e] = true
+	}
+
+	findings := make([]SecretFinding, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable entries are skipped rather than failing the scan
+			return nil
+		}
+		if skip[d.Name()] && path != root {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxScanFileSize {
+			return nil
+		}
+
+		fileFindings, err := scanFile(path)
+		if err != nil {
+			return nil
+		}
+		for i := range fileFindings {
+			fileFindings[i].File = relPath(root, path)
+		}
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortFindings(findings)
+	return &SecretReport{Tool: ToolBuiltin, Findings: findings}, nil
+}
+
+// scanFile checks each line of a text file against the built-in rules
+func scanFile(path string) ([]SecretFinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Skip binary files
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, nil
+	}
+
+	var findings []SecretFinding
+	for i, line := range strings.Split(string(data), "\n") {
+		for _, rule := range secretRules {
+			if rule.pattern.MatchString(line) {
+				findings = append(findings, SecretFinding{Rule: rule.name, Line: i + 1, Severity: rule.severity})
+				break
+			}
+		}
+	}
+	return findings, nil
+}
+
+// sortFindings orders findings by severity (worst first), then file and line
+func sortFindings(findings []SecretFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+// relPath returns path relative to root when possible
+func relPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
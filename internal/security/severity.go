@@ -0,0 +1,72 @@
+package security
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks findings from security scans
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// String returns the lower-case severity name
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSeverity maps tool-specific severity names onto Severity
+func ParseSeverity(s string) Severity {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return SeverityCritical
+	case "high":
+		return SeverityHigh
+	case "medium", "moderate":
+		return SeverityMedium
+	case "low", "info":
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// Counts tallies findings per severity
+type Counts map[Severity]int
+
+// Total returns the total number of findings
+func (c Counts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// Summary renders counts in a fixed order, e.g.
+// "🔴 Critical: 1  🟠 High: 2  🟡 Medium: 0  🔵 Low: 0"
+func (c Counts) Summary() string {
+	summary := fmt.Sprintf("🔴 Critical: %d  🟠 High: %d  🟡 Medium: %d  🔵 Low: %d",
+		c[SeverityCritical], c[SeverityHigh], c[SeverityMedium], c[SeverityLow])
+	if n := c[SeverityUnknown]; n > 0 {
+		summary += fmt.Sprintf("  ⚪ Unknown: %d", n)
+	}
+	return summary
+}
@@ -0,0 +1,36 @@
+package security
+
+import "testing"
+
+func TestParseSeverity(t *testing.T) {
+	tests := map[string]Severity{
+		"CRITICAL": SeverityCritical,
+		"high":     SeverityHigh,
+		"moderate": SeverityMedium,
+		"Low":      SeverityLow,
+		"info":     SeverityLow,
+		"":         SeverityUnknown,
+	}
+	for input, expected := range tests {
+		if got := ParseSeverity(input); got != expected {
+			t.Errorf("ParseSeverity(%q) = %s, want %s", input, got, expected)
+		}
+	}
+}
+
+func TestCountsSummary(t *testing.T) {
+	counts := Counts{SeverityCritical: 1, SeverityHigh: 2}
+	if counts.Total() != 3 {
+		t.Errorf("Total = %d, want 3", counts.Total())
+	}
+
+	expected := "🔴 Critical: 1  🟠 High: 2  🟡 Medium: 0  🔵 Low: 0"
+	if got := counts.Summary(); got != expected {
+		t.Errorf("Summary = %q, want %q", got, expected)
+	}
+
+	counts[SeverityUnknown] = 1
+	if got := counts.Summary(); got != expected+"  ⚪ Unknown: 1" {
+		t.Errorf("Expected unknown count in summary, got %q", got)
+	}
+}
@@ -0,0 +1,399 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/procs"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/wsl"
+)
+
+// OpenedInTerminal marks a project that was last opened via cd rather than an editor
+const OpenedInTerminal = "terminal"
+
+// MaxHistory is how many runs are remembered per project
+const MaxHistory = 20
+
+// MaxUsageDays is how many days of usage counts are kept per project
+const MaxUsageDays = 365
+
+// MaxInputHistory is how many entries are remembered per text input
+const MaxInputHistory = 50
+
+// DayFormat is the layout of the keys of ProjectState.Usage
+const DayFormat = "2006-01-02"
+
+// State holds data proj remembers between runs (as opposed to user configuration)
+type State struct {
+	Projects map[string]*ProjectState `json:"projects"`         // Keyed by project path (lower-cased on Windows drives)
+	Inputs   map[string][]string      `json:"inputs,omitempty"` // Text input name -> entries, most recent first
+
+	path string
+}
+
+// ProjectState holds remembered data for a single project
+type ProjectState struct {
+	LastOpenedWith string            `json:"lastOpenedWith,omitempty"` // Editor alias or OpenedInTerminal
+	LastOpenedAt   time.Time         `json:"lastOpenedAt,omitempty"`
+	LastSelectedAt time.Time         `json:"lastSelectedAt,omitempty"` // When its action menu was last opened from the list
+	Archived       bool              `json:"archived,omitempty"`
+	Pinned         bool              `json:"pinned,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	History        []Run             `json:"history,omitempty"`  // Most recent first
+	BulkRuns       map[string]string `json:"bulkRuns,omitempty"` // Bulk command -> fingerprint of the project when it last succeeded
+	Usage          map[string]*Usage `json:"usage,omitempty"`    // Day (DayFormat) -> what was done that day
+	Contributors   *Contributors     `json:"contributors,omitempty"`
+	Server         *Server           `json:"server,omitempty"` // Dev server last started in the project
+}
+
+// Server is a dev server proj started in the background
+type Server struct {
+	Command   string    `json:"command"`
+	PID       int       `json:"pid"`
+	Port      int       `json:"port,omitempty"` // Once its log shows one
+	Log       string    `json:"log"`            // File its output is written to
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// Contributors caches git shortlog for a project, which is slow in large
+// repositories, until HEAD moves
+type Contributors struct {
+	Head    string            `json:"head"`
+	Authors []git.Contributor `json:"authors"` // Most commits first
+}
+
+// Usage counts how often a project was opened and its actions run on one day
+type Usage struct {
+	Opens   int            `json:"opens,omitempty"`
+	Selects int            `json:"selects,omitempty"` // Times its action menu was opened from the list
+	Editors map[string]int `json:"editors,omitempty"` // Editor alias or OpenedInTerminal -> opens
+	Actions map[string]int `json:"actions,omitempty"` // Action label -> runs
+}
+
+// Run is an action that was run in a project
+type Run struct {
+	ActionID string    `json:"actionId"`
+	Label    string    `json:"label"`
+	Command  string    `json:"command,omitempty"` // For script actions
+	At       time.Time `json:"at"`
+	ExitCode int       `json:"exitCode"`
+}
+
+// Path returns the full path to the state file
+func Path() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// Load loads the state from the default state file
+func Load() (*State, error) {
+	path, err := Path()
+	if err != nil {
+		return New(""), err
+	}
+	return LoadFrom(path)
+}
+
+// LoadFrom loads the state from a file. A missing file yields an empty state.
+func LoadFrom(path string) (*State, error) {
+	s := New(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return New(path), err
+	}
+	if s.Projects == nil {
+		s.Projects = make(map[string]*ProjectState)
+	}
+
+	return s, nil
+}
+
+// New creates an empty state backed by the given file
+func New(path string) *State {
+	return &State{
+		Projects: make(map[string]*ProjectState),
+		path:     path,
+	}
+}
+
+// Save writes the state back to its file
+func (s *State) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// Project returns the state for a project path, creating it if needed
+func (s *State) Project(path string) *ProjectState {
+	key := wsl.NormalizePath(path)
+	ps, ok := s.Projects[key]
+	if !ok {
+		ps = &ProjectState{}
+		s.Projects[key] = ps
+	}
+	return ps
+}
+
+// RecordOpen records that a project was opened with an editor or terminal
+func (s *State) RecordOpen(path, openedWith string) {
+	ps := s.Project(path)
+	ps.LastOpenedWith = openedWith
+	ps.LastOpenedAt = time.Now()
+
+	usage := ps.usageOn(ps.LastOpenedAt)
+	usage.Opens++
+	if usage.Editors == nil {
+		usage.Editors = make(map[string]int)
+	}
+	usage.Editors[openedWith]++
+}
+
+// RecordSelect records that a project was selected in the list to open its
+// action menu
+func (s *State) RecordSelect(path string) {
+	ps := s.Project(path)
+	ps.LastSelectedAt = time.Now()
+	ps.usageOn(ps.LastSelectedAt).Selects++
+}
+
+// Frecency scores how often and how recently a project was opened or
+// selected, like zoxide: each visit counts 4 on the day, 2 within a week, 1
+// within a month and 0.25 after that
+func (ps *ProjectState) Frecency(now time.Time) float64 {
+	today := now.Format(DayFormat)
+	score := 0.0
+	for day, usage := range ps.Usage {
+		visits := usage.Opens + usage.Selects
+		if visits == 0 {
+			continue
+		}
+		t, err := time.ParseInLocation(DayFormat, day, now.Location())
+		if err != nil {
+			continue
+		}
+		weight := 0.25
+		switch age := now.Sub(t); {
+		case day == today:
+			weight = 4
+		case age < 7*24*time.Hour:
+			weight = 2
+		case age < 30*24*time.Hour:
+			weight = 1
+		}
+		score += float64(visits) * weight
+	}
+	return score
+}
+
+// RecordRun adds a run to the front of a project's history, keeping at most
+// MaxHistory runs
+func (s *State) RecordRun(path string, run Run) {
+	ps := s.Project(path)
+	ps.History = append([]Run{run}, ps.History...)
+	if len(ps.History) > MaxHistory {
+		ps.History = ps.History[:MaxHistory]
+	}
+
+	usage := ps.usageOn(run.At)
+	if usage.Actions == nil {
+		usage.Actions = make(map[string]int)
+	}
+	usage.Actions[run.Label]++
+}
+
+// InputHistory returns what was entered in a text input, most recent first
+func (s *State) InputHistory(name string) []string {
+	return s.Inputs[name]
+}
+
+// RecordInput adds an entry to the front of a text input's history, moving
+// it there if it was entered before and keeping at most MaxInputHistory
+func (s *State) RecordInput(name, value string) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	if s.Inputs == nil {
+		s.Inputs = make(map[string][]string)
+	}
+	history := []string{value}
+	for _, entry := range s.Inputs[name] {
+		if entry != value && len(history) < MaxInputHistory {
+			history = append(history, entry)
+		}
+	}
+	s.Inputs[name] = history
+}
+
+// usageOn returns the usage counts for the day of t, creating them if needed
+// and dropping days older than MaxUsageDays
+func (ps *ProjectState) usageOn(t time.Time) *Usage {
+	if ps.Usage == nil {
+		ps.Usage = make(map[string]*Usage)
+	}
+	cutoff := t.AddDate(0, 0, -MaxUsageDays).Format(DayFormat)
+	for day := range ps.Usage {
+		if day < cutoff {
+			delete(ps.Usage, day)
+		}
+	}
+
+	day := t.Format(DayFormat)
+	usage, ok := ps.Usage[day]
+	if !ok {
+		usage = &Usage{}
+		ps.Usage[day] = usage
+	}
+	return usage
+}
+
+// History returns a project's runs, most recent first
+func (s *State) History(path string) []Run {
+	if ps, ok := s.Projects[wsl.NormalizePath(path)]; ok {
+		return ps.History
+	}
+	return nil
+}
+
+// Contributors returns a project's cached contributors if they were counted
+// at head
+func (s *State) Contributors(path, head string) ([]git.Contributor, bool) {
+	if ps, ok := s.Projects[wsl.NormalizePath(path)]; ok && ps.Contributors != nil && ps.Contributors.Head == head {
+		return ps.Contributors.Authors, true
+	}
+	return nil, false
+}
+
+// CachedContributors returns a copy of a project's contributors cache, for
+// commands checking it against HEAD in the background
+func (s *State) CachedContributors(path string) Contributors {
+	if ps, ok := s.Projects[wsl.NormalizePath(path)]; ok && ps.Contributors != nil {
+		return *ps.Contributors
+	}
+	return Contributors{}
+}
+
+// RecordContributors caches a project's contributors as counted at head
+func (s *State) RecordContributors(path, head string, authors []git.Contributor) {
+	s.Project(path).Contributors = &Contributors{Head: head, Authors: authors}
+}
+
+// Server returns the dev server last started in a project, running or not,
+// or nil
+func (s *State) Server(path string) *Server {
+	if ps, ok := s.Projects[wsl.NormalizePath(path)]; ok {
+		return ps.Server
+	}
+	return nil
+}
+
+// RecordServer remembers the dev server started in a project
+func (s *State) RecordServer(path string, server *Server) {
+	s.Project(path).Server = server
+}
+
+// BulkFingerprints returns the fingerprints the projects at paths had when
+// command last succeeded in them, keyed by path
+func (s *State) BulkFingerprints(command string, paths []string) map[string]string {
+	fingerprints := make(map[string]string)
+	for _, path := range paths {
+		if ps, ok := s.Projects[wsl.NormalizePath(path)]; ok && ps.BulkRuns[command] != "" {
+			fingerprints[path] = ps.BulkRuns[command]
+		}
+	}
+	return fingerprints
+}
+
+// RecordBulkRuns remembers the fingerprints of the projects command succeeded in
+func (s *State) RecordBulkRuns(command string, fingerprints map[string]string) {
+	for path, fingerprint := range fingerprints {
+		ps := s.Project(path)
+		if ps.BulkRuns == nil {
+			ps.BulkRuns = make(map[string]string)
+		}
+		ps.BulkRuns[command] = fingerprint
+	}
+}
+
+// RecentlyOpened returns the paths of opened projects, most recently opened first
+func (s *State) RecentlyOpened() []string {
+	paths := make([]string, 0, len(s.Projects))
+	for path, ps := range s.Projects {
+		if !ps.LastOpenedAt.IsZero() {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return s.Projects[paths[i]].LastOpenedAt.After(s.Projects[paths[j]].LastOpenedAt)
+	})
+	return paths
+}
+
+// SetArchived marks a project as archived (or restores it)
+func (s *State) SetArchived(path string, archived bool) {
+	s.Project(path).Archived = archived
+}
+
+// SetPinned pins a project to the top of the list (or unpins it)
+func (s *State) SetPinned(path string, pinned bool) {
+	s.Project(path).Pinned = pinned
+}
+
+// AddTag adds a tag to a project, ignoring duplicates
+func (s *State) AddTag(path, tag string) {
+	ps := s.Project(path)
+	for _, t := range ps.Tags {
+		if t == tag {
+			return
+		}
+	}
+	ps.Tags = append(ps.Tags, tag)
+}
+
+// Annotate copies remembered state onto scanned projects
+func (s *State) Annotate(projects []*project.Project) {
+	for _, p := range projects {
+		ps, ok := s.Projects[wsl.NormalizePath(p.Path)]
+		if !ok {
+			continue
+		}
+		p.LastOpenedWith = ps.LastOpenedWith
+		p.LastOpenedAt = ps.LastOpenedAt
+		p.Archived = ps.Archived
+		p.Pinned = ps.Pinned
+		p.Frecency = ps.Frecency(time.Now())
+		// Tags from a group's .proj-group.json are kept
+		p.Tags = project.MergeTags(p.Tags, ps.Tags)
+		if ps.Server != nil && procs.Running(ps.Server.PID) {
+			p.ServerPID, p.ServerPort = ps.Server.PID, ps.Server.Port
+		}
+	}
+}
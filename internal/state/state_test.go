@@ -0,0 +1,262 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestLoadFrom_MissingFile(t *testing.T) {
+	s, err := LoadFrom(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if s.Projects == nil || len(s.Projects) != 0 {
+		t.Errorf("Expected empty project state, got %v", s.Projects)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	s := New(path)
+	s.RecordOpen("/code/app", "nvim")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	ps := loaded.Projects["/code/app"]
+	if ps == nil {
+		t.Fatal("Expected project state to be persisted")
+	}
+	if ps.LastOpenedWith != "nvim" {
+		t.Errorf("LastOpenedWith = %q, want %q", ps.LastOpenedWith, "nvim")
+	}
+	if ps.LastOpenedAt.IsZero() {
+		t.Error("LastOpenedAt should be set")
+	}
+}
+
+func TestLoadFrom_InvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+
+	s, err := LoadFrom(path)
+	if err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if s == nil || s.Projects == nil {
+		t.Error("Expected a usable empty state on error")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	s := New("")
+	s.RecordOpen("/code/app", OpenedInTerminal)
+
+	projects := []*project.Project{
+		{Name: "app", Path: "/code/app"},
+		{Name: "other", Path: "/code/other"},
+	}
+	s.Annotate(projects)
+
+	if projects[0].LastOpenedWith != OpenedInTerminal {
+		t.Errorf("Expected app to be annotated, got %q", projects[0].LastOpenedWith)
+	}
+	if projects[1].LastOpenedWith != "" {
+		t.Errorf("Expected other to be untouched, got %q", projects[1].LastOpenedWith)
+	}
+}
+
+func TestArchiveAndTag(t *testing.T) {
+	s := New("")
+	s.SetArchived("/code/old", true)
+	s.SetPinned("/code/old", true)
+	s.AddTag("/code/old", "stale")
+	s.AddTag("/code/old", "stale")
+
+	projects := []*project.Project{{Name: "old", Path: "/code/old"}}
+	s.Annotate(projects)
+
+	if !projects[0].Archived || !projects[0].Pinned {
+		t.Error("Expected project to be archived and pinned")
+	}
+	if len(projects[0].Tags) != 1 || projects[0].Tags[0] != "stale" {
+		t.Errorf("Expected a single stale tag, got %v", projects[0].Tags)
+	}
+}
+
+func TestRecordRun(t *testing.T) {
+	s := New("")
+	if len(s.History("/code/app")) != 0 {
+		t.Fatal("Expected no history for an unknown project")
+	}
+
+	for i := 0; i < MaxHistory+5; i++ {
+		s.RecordRun("/code/app", Run{ActionID: "run-tests", ExitCode: i})
+	}
+
+	history := s.History("/code/app")
+	if len(history) != MaxHistory {
+		t.Fatalf("len(History) = %d, want %d", len(history), MaxHistory)
+	}
+	if history[0].ExitCode != MaxHistory+4 {
+		t.Errorf("History()[0].ExitCode = %d, want the latest run first", history[0].ExitCode)
+	}
+}
+
+func TestRecordInput(t *testing.T) {
+	s := New("")
+	s.RecordInput("each", "go vet ./...")
+	s.RecordInput("each", "  ")
+	s.RecordInput("each", "go test ./...")
+	s.RecordInput("each", " go vet ./... ")
+
+	history := s.InputHistory("each")
+	if len(history) != 2 || history[0] != "go vet ./..." || history[1] != "go test ./..." {
+		t.Errorf("InputHistory() = %q, want the repeated entry moved to the front", history)
+	}
+	if len(s.InputHistory("newProject")) != 0 {
+		t.Error("Expected inputs to keep separate histories")
+	}
+
+	for i := 0; i < MaxInputHistory+5; i++ {
+		s.RecordInput("each", fmt.Sprintf("echo %d", i))
+	}
+	if got := len(s.InputHistory("each")); got != MaxInputHistory {
+		t.Errorf("len(InputHistory()) = %d, want %d", got, MaxInputHistory)
+	}
+}
+
+func TestAnnotate_WindowsDriveCaseInsensitive(t *testing.T) {
+	s := New("")
+	s.RecordOpen("/mnt/c/Users/Me/code/app", "code")
+
+	projects := []*project.Project{{Name: "app", Path: "/mnt/c/users/me/Code/app"}}
+	s.Annotate(projects)
+
+	if projects[0].LastOpenedWith != "code" {
+		t.Error("Expected paths on Windows drives to match case-insensitively")
+	}
+}
+
+func TestBulkRuns(t *testing.T) {
+	s := New("")
+	s.RecordBulkRuns("go test ./...", map[string]string{"/code/a": "abc"})
+
+	got := s.BulkFingerprints("go test ./...", []string{"/code/a", "/code/b"})
+	if len(got) != 1 || got["/code/a"] != "abc" {
+		t.Errorf("BulkFingerprints() = %v", got)
+	}
+	if got := s.BulkFingerprints("go vet ./...", []string{"/code/a"}); len(got) != 0 {
+		t.Errorf("fingerprints of another command = %v", got)
+	}
+}
+
+func TestContributors(t *testing.T) {
+	s := New("")
+	if _, ok := s.Contributors("/code/a", "abc"); ok {
+		t.Error("Expected nothing cached yet")
+	}
+
+	s.RecordContributors("/code/a", "abc", []git.Contributor{{Name: "Ann", Commits: 3}})
+	if got, ok := s.Contributors("/code/a", "abc"); !ok || len(got) != 1 || got[0].Name != "Ann" {
+		t.Errorf("Contributors() = %v, %v", got, ok)
+	}
+	// A new commit makes the cache stale
+	if _, ok := s.Contributors("/code/a", "def"); ok {
+		t.Error("Expected contributors counted at another commit to be stale")
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := New("")
+	if s.Server("/code/web") != nil {
+		t.Error("Expected no server yet")
+	}
+
+	// This test's own process stands in for a running server
+	s.RecordServer("/code/web", &Server{Command: "npm run dev", PID: os.Getpid(), Port: 5173})
+	projects := []*project.Project{{Name: "web", Path: "/code/web"}, {Name: "api", Path: "/code/api"}}
+	s.RecordServer("/code/api", &Server{Command: "make run", PID: -1})
+	s.Annotate(projects)
+	if projects[0].ServerPID != os.Getpid() || projects[0].ServerPort != 5173 {
+		t.Errorf("Running server annotated as pid %d, port %d", projects[0].ServerPID, projects[0].ServerPort)
+	}
+	if projects[1].ServerPID != 0 {
+		t.Errorf("Exited server annotated as pid %d", projects[1].ServerPID)
+	}
+	if got := s.Server("/code/api"); got == nil || got.Command != "make run" {
+		t.Errorf("Server() = %+v; the last run is kept for its log", got)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	s := New("")
+	now := time.Now()
+	s.RecordOpen("/code/app", "nvim")
+	s.RecordOpen("/code/app", OpenedInTerminal)
+	s.RecordRun("/code/app", Run{Label: "Run Tests", At: now})
+	s.RecordRun("/code/app", Run{Label: "Run Tests", At: now})
+	s.RecordRun("/code/app", Run{Label: "Build", At: now.AddDate(0, 0, -MaxUsageDays-1)})
+
+	usage := s.Projects["/code/app"].Usage
+	today := usage[now.Format(DayFormat)]
+	if today == nil || today.Opens != 2 || today.Editors["nvim"] != 1 || today.Actions["Run Tests"] != 2 {
+		t.Errorf("today's usage = %+v", today)
+	}
+
+	// Recording drops days past MaxUsageDays
+	s.RecordRun("/code/app", Run{Label: "Build", At: now})
+	if len(usage) != 1 {
+		t.Errorf("usage kept %d days, want 1", len(usage))
+	}
+}
+
+func TestRecentlyOpened(t *testing.T) {
+	s := New("")
+	now := time.Now()
+	s.Project("/code/old").LastOpenedAt = now.Add(-2 * time.Hour)
+	s.Project("/code/new").LastOpenedAt = now
+	s.Project("/code/never").Archived = true
+
+	got := s.RecentlyOpened()
+	if len(got) != 2 || got[0] != "/code/new" || got[1] != "/code/old" {
+		t.Errorf("RecentlyOpened() = %v", got)
+	}
+}
+
+func TestFrecency(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	ps := &ProjectState{Usage: map[string]*Usage{
+		"2026-03-10": {Opens: 1, Selects: 1},               // Today: 2 × 4
+		"2026-03-07": {Opens: 2},                           // This week: 2 × 2
+		"2026-02-20": {Selects: 1},                         // This month: 1 × 1
+		"2025-10-01": {Opens: 4},                           // Older: 4 × 0.25
+		"2026-03-09": {Actions: map[string]int{"test": 3}}, // Runs alone don't count
+	}}
+	if got := ps.Frecency(now); got != 14 {
+		t.Errorf("Frecency() = %v, want 14", got)
+	}
+
+	s := New("")
+	s.RecordSelect("/code/app")
+	s.RecordOpen("/code/app", OpenedInTerminal)
+	projects := []*project.Project{{Name: "app", Path: "/code/app"}}
+	s.Annotate(projects)
+	if projects[0].Frecency != 8 {
+		t.Errorf("Expected an open and a select today to score 8, got %v", projects[0].Frecency)
+	}
+}
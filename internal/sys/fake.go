@@ -0,0 +1,123 @@
+package sys
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Result is what a command run by a Fake prints and exits with
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Fake is a Runner for tests. It runs none of the programs asked for: each
+// command is replaced by a shell printing the Result given for it, so tests
+// needn't have git or docker installed or shim them onto PATH.
+type Fake struct {
+	mu      sync.Mutex
+	results map[string]Result
+	calls   []string
+}
+
+// NewFake returns a Fake without any results
+func NewFake() *Fake {
+	return &Fake{results: make(map[string]Result)}
+}
+
+// On sets the result of the commands starting with prefix, such as
+// "git status" or "docker ps -a". The longest matching prefix wins; a
+// command matching none exits with 127.
+func (f *Fake) On(prefix string, result Result) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[prefix] = result
+	return f
+}
+
+// Calls returns the command lines run so far, oldest first
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// CommandContext returns a command printing the result set for name and args
+func (f *Fake) CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	line := strings.Join(append([]string{name}, args...), " ")
+
+	f.mu.Lock()
+	f.calls = append(f.calls, line)
+	result, ok := f.lookup(line)
+	f.mu.Unlock()
+	if !ok {
+		result = Result{Stderr: "fake: no result for " + line + "\n", ExitCode: 127}
+	}
+
+	return exec.CommandContext(ctx, "/bin/sh", "-c", `printf '%s' "$1"; printf '%s' "$2" >&2; exit "$3"`,
+		"sh", result.Stdout, result.Stderr, strconv.Itoa(result.ExitCode))
+}
+
+// LookPath finds the programs the Fake has results for
+func (f *Fake) LookPath(name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for prefix := range f.results {
+		if prefix == name || strings.HasPrefix(prefix, name+" ") {
+			return "/fake/bin/" + name, nil
+		}
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// lookup returns the result with the longest prefix of line
+func (f *Fake) lookup(line string) (Result, bool) {
+	prefixes := make([]string, 0, len(f.results))
+	for prefix := range f.results {
+		if line == prefix || strings.HasPrefix(line, prefix+" ") {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return Result{}, false
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	return f.results[prefixes[0]], true
+}
+
+// FromFS returns an FS reading absolute paths from fsys, such as an
+// fstest.MapFS, whose root stands for /. It has no symlinks.
+func FromFS(fsys fs.FS) FS {
+	return ioFS{fsys}
+}
+
+type ioFS struct {
+	fsys fs.FS
+}
+
+// rel turns an absolute path into fsys's form
+func (f ioFS) rel(name string) string {
+	name = strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+func (f ioFS) Stat(name string) (fs.FileInfo, error) { return fs.Stat(f.fsys, f.rel(name)) }
+
+func (f ioFS) ReadDir(name string) ([]fs.DirEntry, error) { return fs.ReadDir(f.fsys, f.rel(name)) }
+
+func (f ioFS) ReadFile(name string) ([]byte, error) { return fs.ReadFile(f.fsys, f.rel(name)) }
+
+func (f ioFS) Readlink(name string) (string, error) {
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
@@ -0,0 +1,122 @@
+// Package sys is proj's access to the machine it runs on: the commands it
+// starts and the files the scanner reads. The scanner and the actions, git
+// and docker packages go through it instead of os and os/exec, so tests can
+// put a Fake in place of git or docker rather than shimming PATH, and so
+// commands could be run somewhere else, such as over SSH or in a container,
+// by another Runner.
+package sys
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// Runner starts commands
+type Runner interface {
+	// CommandContext returns the command running name with args, killed when
+	// ctx is done, as exec.CommandContext does
+	CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd
+	// LookPath finds the executable name, as exec.LookPath does
+	LookPath(name string) (string, error)
+}
+
+// FS reads files and directories
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	ReadFile(name string) ([]byte, error)
+	Readlink(name string) (string, error)
+}
+
+// Local runs commands and reads files on this machine; it is the default
+// Runner and FS
+type Local struct{}
+
+// CommandContext returns exec.CommandContext(ctx, name, args...)
+func (Local) CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+// LookPath returns exec.LookPath(name)
+func (Local) LookPath(name string) (string, error) { return exec.LookPath(name) }
+
+// Stat returns os.Stat(name)
+func (Local) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+// ReadDir returns os.ReadDir(name)
+func (Local) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+// ReadFile returns os.ReadFile(name)
+func (Local) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+// Readlink returns os.Readlink(name)
+func (Local) Readlink(name string) (string, error) { return os.Readlink(name) }
+
+var (
+	mu     sync.RWMutex
+	runner Runner = Local{}
+	files  FS     = Local{}
+)
+
+// UseRunner makes r start every command from now on and returns the
+// function putting back the one it replaced
+func UseRunner(r Runner) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := runner
+	runner = r
+	return func() { UseRunner(prev) }
+}
+
+// UseFS makes f read every file from now on and returns the function
+// putting back the one it replaced
+func UseFS(f FS) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := files
+	files = f
+	return func() { UseFS(prev) }
+}
+
+func currentRunner() Runner {
+	mu.RLock()
+	defer mu.RUnlock()
+	return runner
+}
+
+func currentFS() FS {
+	mu.RLock()
+	defer mu.RUnlock()
+	return files
+}
+
+// Command returns the command running name with args, like exec.Command
+func Command(name string, args ...string) *exec.Cmd {
+	return currentRunner().CommandContext(context.Background(), name, args...)
+}
+
+// CommandContext returns the command running name with args, killed when
+// ctx is done, like exec.CommandContext
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return currentRunner().CommandContext(ctx, name, args...)
+}
+
+// LookPath finds the executable name, like exec.LookPath
+func LookPath(name string) (string, error) {
+	return currentRunner().LookPath(name)
+}
+
+// Stat describes the file name, like os.Stat
+func Stat(name string) (fs.FileInfo, error) { return currentFS().Stat(name) }
+
+// ReadDir lists the directory name, like os.ReadDir
+func ReadDir(name string) ([]fs.DirEntry, error) { return currentFS().ReadDir(name) }
+
+// ReadFile reads the file name, like os.ReadFile
+func ReadFile(name string) ([]byte, error) { return currentFS().ReadFile(name) }
+
+// Readlink returns the target of the symlink name, like os.Readlink
+func Readlink(name string) (string, error) { return currentFS().Readlink(name) }
@@ -0,0 +1,71 @@
+package sys
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFake(t *testing.T) {
+	fake := NewFake().
+		On("git status", Result{Stdout: "clean"}).
+		On("git status --porcelain", Result{Stdout: " M go.mod\n"}).
+		On("git push", Result{Stderr: "rejected", ExitCode: 1})
+	t.Cleanup(UseRunner(fake))
+
+	out, err := Command("git", "status", "--porcelain").Output()
+	if err != nil || string(out) != " M go.mod\n" {
+		t.Errorf("git status --porcelain = %q, %v; want the longest prefix's output", out, err)
+	}
+	if out, _ := Command("git", "status", "-sb").Output(); string(out) != "clean" {
+		t.Errorf("git status -sb = %q, want clean", out)
+	}
+
+	var exitErr *exec.ExitError
+	out, err = Command("git", "push").CombinedOutput()
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 || string(out) != "rejected" {
+		t.Errorf("git push = %q, %v; want rejected with exit code 1", out, err)
+	}
+	if err := Command("git", "statusx").Run(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 127 {
+		t.Errorf("Expected a command without a result to exit with 127, got %v", err)
+	}
+
+	if got := strings.Join(fake.Calls(), ", "); got != "git status --porcelain, git status -sb, git push, git statusx" {
+		t.Errorf("Calls() = %q", got)
+	}
+	if _, err := LookPath("git"); err != nil {
+		t.Errorf("LookPath(git) = %v, want it found", err)
+	}
+	if _, err := LookPath("docker"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("LookPath(docker) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUseRunnerRestores(t *testing.T) {
+	restore := UseRunner(NewFake())
+	restore()
+	if _, ok := currentRunner().(Local); !ok {
+		t.Errorf("Expected the local runner back, got %T", currentRunner())
+	}
+}
+
+func TestFromFS(t *testing.T) {
+	t.Cleanup(UseFS(FromFS(fstest.MapFS{
+		"code/app/go.mod": {Data: []byte("module app")},
+	})))
+
+	if data, err := ReadFile("/code/app/go.mod"); err != nil || string(data) != "module app" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if info, err := Stat("/code/app"); err != nil || !info.IsDir() {
+		t.Errorf("Stat(/code/app) = %v, %v; want a directory", info, err)
+	}
+	if entries, err := ReadDir("/"); err != nil || len(entries) != 1 || entries[0].Name() != "code" {
+		t.Errorf("ReadDir(/) = %v, %v", entries, err)
+	}
+	if _, err := Readlink("/code/app"); err == nil {
+		t.Error("Expected Readlink to fail without symlinks")
+	}
+}
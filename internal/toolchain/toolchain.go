@@ -0,0 +1,295 @@
+// Package toolchain detects the toolchain versions a project declares
+// (asdf/mise, .nvmrc, go.mod) and compares them with what is installed.
+package toolchain
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/s33g/proj/internal/sys"
+)
+
+// Requirement is a toolchain version declared by a project
+type Requirement struct {
+	Tool    string // Normalized tool name, e.g. "node" or "go"
+	Version string
+	Source  string // File the requirement came from
+	Minimum bool   // Version is a minimum rather than an exact pin (go.mod)
+}
+
+// Status is a requirement together with the installed version
+type Status struct {
+	Requirement
+	Installed string // Empty if the tool isn't installed
+	OK        bool
+}
+
+// aliases maps asdf plugin names to the names used elsewhere
+var aliases = map[string]string{
+	"nodejs": "node",
+	"golang": "go",
+	"rustc":  "rust",
+}
+
+func normalize(tool string) string {
+	tool = strings.ToLower(strings.TrimSpace(tool))
+	if alias, ok := aliases[tool]; ok {
+		return alias
+	}
+	return tool
+}
+
+// Detect returns the toolchain versions declared in the project at path. When
+// several files name the same tool, mise config wins over .tool-versions,
+// which wins over .nvmrc and go.mod.
+func Detect(path string) []Requirement {
+	var reqs []Requirement
+	seen := map[string]bool{}
+	add := func(found []Requirement) {
+		for _, r := range found {
+			if r.Tool == "" || r.Version == "" || seen[r.Tool] {
+				continue
+			}
+			seen[r.Tool] = true
+			reqs = append(reqs, r)
+		}
+	}
+
+	for _, name := range []string{".mise.toml", "mise.toml"} {
+		add(parseMise(filepath.Join(path, name)))
+	}
+	add(parseToolVersions(filepath.Join(path, ".tool-versions")))
+	add(parseNvmrc(filepath.Join(path, ".nvmrc")))
+	add(parseGoMod(filepath.Join(path, "go.mod")))
+
+	return reqs
+}
+
+// Check detects the project's requirements and compares them with the
+// installed toolchains
+func Check(path string) []Status {
+	reqs := Detect(path)
+	statuses := make([]Status, 0, len(reqs))
+	for _, r := range reqs {
+		installed := InstalledVersion(r.Tool)
+		statuses = append(statuses, Status{
+			Requirement: r,
+			Installed:   installed,
+			OK:          installed != "" && Satisfies(r, installed),
+		})
+	}
+	return statuses
+}
+
+// parseToolVersions parses an asdf .tool-versions file ("tool version [fallback...]")
+func parseToolVersions(path string) []Requirement {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var reqs []Requirement
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		reqs = append(reqs, Requirement{Tool: normalize(fields[0]), Version: fields[1], Source: ".tool-versions"})
+	}
+	return reqs
+}
+
+// parseMise parses the [tools] table of a mise config. Values may be a
+// version string, a list of versions, or a table with a version key.
+func parseMise(path string) []Requirement {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var config struct {
+		Tools map[string]any `toml:"tools"`
+	}
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil
+	}
+
+	source := filepath.Base(path)
+	var reqs []Requirement
+	for tool, value := range config.Tools {
+		if version := miseVersion(value); version != "" {
+			reqs = append(reqs, Requirement{Tool: normalize(tool), Version: version, Source: source})
+		}
+	}
+	sortRequirements(reqs)
+	return reqs
+}
+
+// sortRequirements orders requirements by tool so map iteration is stable
+func sortRequirements(reqs []Requirement) {
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Tool < reqs[j].Tool })
+}
+
+func miseVersion(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) > 0 {
+			return miseVersion(v[0])
+		}
+	case map[string]any:
+		return miseVersion(v["version"])
+	}
+	return ""
+}
+
+// parseNvmrc reads the Node version from .nvmrc
+func parseNvmrc(path string) []Requirement {
+	data, err := sys.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	version := strings.TrimSpace(string(data))
+	if version == "" {
+		return nil
+	}
+	return []Requirement{{Tool: "node", Version: version, Source: ".nvmrc"}}
+}
+
+// parseGoMod reads the go directive, which is a minimum version
+func parseGoMod(path string) []Requirement {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "go" {
+			return []Requirement{{Tool: "go", Version: fields[1], Source: "go.mod", Minimum: true}}
+		}
+	}
+	return nil
+}
+
+// versionCommands are the commands that print each tool's installed version
+var versionCommands = map[string][][]string{
+	"go":        {{"go", "version"}},
+	"node":      {{"node", "--version"}},
+	"python":    {{"python3", "--version"}, {"python", "--version"}},
+	"ruby":      {{"ruby", "--version"}},
+	"rust":      {{"rustc", "--version"}},
+	"java":      {{"java", "-version"}},
+	"deno":      {{"deno", "--version"}},
+	"bun":       {{"bun", "--version"}},
+	"terraform": {{"terraform", "-version"}},
+	"elixir":    {{"elixir", "--short-version"}},
+	"zig":       {{"zig", "version"}},
+}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+var (
+	installedMu    sync.Mutex
+	installedCache = map[string]string{}
+)
+
+// InstalledVersion returns the installed version of a tool, or "" if it isn't
+// installed or its version can't be determined. Results are cached for the
+// life of the process.
+func InstalledVersion(tool string) string {
+	installedMu.Lock()
+	defer installedMu.Unlock()
+
+	if version, ok := installedCache[tool]; ok {
+		return version
+	}
+
+	version := ""
+	for _, args := range versionCommands[tool] {
+		out, err := sys.Command(args[0], args[1:]...).CombinedOutput()
+		if err != nil {
+			continue
+		}
+		if match := versionPattern.FindString(string(out)); match != "" {
+			version = match
+			break
+		}
+	}
+
+	installedCache[tool] = version
+	return version
+}
+
+// Satisfies reports whether the installed version meets the requirement.
+// Pins match by prefix ("20" matches "20.11.0"); minimums compare numerically.
+// Requirements that aren't plain versions (e.g. "lts/iron", "latest") can't be
+// judged and are treated as satisfied.
+func Satisfies(req Requirement, installed string) bool {
+	want, ok := parseVersion(req.Version)
+	if !ok {
+		return true
+	}
+	have, ok := parseVersion(installed)
+	if !ok {
+		return false
+	}
+
+	if req.Minimum {
+		for i := 0; i < len(want); i++ {
+			h := 0
+			if i < len(have) {
+				h = have[i]
+			}
+			if h != want[i] {
+				return h > want[i]
+			}
+		}
+		return true
+	}
+
+	if len(have) < len(want) {
+		return false
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVersion splits a version like "v1.22.3" into numeric components
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(version, ".")
+	nums := make([]int, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		nums = append(nums, n)
+	}
+	return nums, true
+}
@@ -0,0 +1,101 @@
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/s33g/proj/internal/sys"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".mise.toml"), "[tools]\nnode = \"20\"\npython = [\"3.12\", \"3.11\"]\nterraform = { version = \"1.7.0\" }\n")
+	writeFile(t, filepath.Join(dir, ".tool-versions"), "# pinned\nnodejs 18.19.0\nruby 3.2.2 system\n")
+	writeFile(t, filepath.Join(dir, ".nvmrc"), "v16\n")
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/x\n\ngo 1.22\n")
+
+	expected := []Requirement{
+		{Tool: "node", Version: "20", Source: ".mise.toml"},
+		{Tool: "python", Version: "3.12", Source: ".mise.toml"},
+		{Tool: "terraform", Version: "1.7.0", Source: ".mise.toml"},
+		{Tool: "ruby", Version: "3.2.2", Source: ".tool-versions"},
+		{Tool: "go", Version: "1.22", Source: "go.mod", Minimum: true},
+	}
+
+	reqs := Detect(dir)
+	if len(reqs) != len(expected) {
+		t.Fatalf("Expected %d requirements, got %d: %+v", len(expected), len(reqs), reqs)
+	}
+	for i, want := range expected {
+		if reqs[i] != want {
+			t.Errorf("requirement %d = %+v, want %+v", i, reqs[i], want)
+		}
+	}
+}
+
+func TestDetect_None(t *testing.T) {
+	if reqs := Detect(t.TempDir()); len(reqs) != 0 {
+		t.Errorf("Expected no requirements, got %+v", reqs)
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version   string
+		minimum   bool
+		installed string
+		expected  bool
+	}{
+		{"20", false, "20.11.0", true},
+		{"v20.11", false, "20.11.1", true},
+		{"20", false, "18.19.0", false},
+		{"3.12.1", false, "3.12", false},
+		{"1.22", true, "1.22.3", true},
+		{"1.22", true, "1.23.0", true},
+		{"1.22.5", true, "1.22.3", false},
+		{"1.21", true, "1.20.14", false},
+		{"lts/iron", false, "20.11.0", true},
+		{"latest", false, "1.0.0", true},
+		{"20", false, "unknown", false},
+	}
+
+	for _, tt := range tests {
+		req := Requirement{Tool: "x", Version: tt.version, Minimum: tt.minimum}
+		if got := Satisfies(req, tt.installed); got != tt.expected {
+			t.Errorf("Satisfies(%q, minimum=%v, %q) = %v, want %v", tt.version, tt.minimum, tt.installed, got, tt.expected)
+		}
+	}
+}
+
+func TestInstalledVersion_Unknown(t *testing.T) {
+	if v := InstalledVersion("no-such-tool"); v != "" {
+		t.Errorf("Expected empty version for unknown tool, got %q", v)
+	}
+}
+
+func TestCheck_Fakes(t *testing.T) {
+	t.Cleanup(sys.UseFS(sys.FromFS(fstest.MapFS{"code/api/.nvmrc": {Data: []byte("v20\n")}})))
+	t.Cleanup(sys.UseRunner(sys.NewFake().On("node --version", sys.Result{Stdout: "v20.11.0\n"})))
+	installedMu.Lock()
+	delete(installedCache, "node")
+	installedMu.Unlock()
+	t.Cleanup(func() {
+		installedMu.Lock()
+		delete(installedCache, "node")
+		installedMu.Unlock()
+	})
+
+	statuses := Check("/code/api")
+	if len(statuses) != 1 || statuses[0].Installed != "20.11.0" || !statuses[0].OK {
+		t.Errorf("Check() = %+v, want node 20 satisfied by the fake's 20.11.0", statuses)
+	}
+}
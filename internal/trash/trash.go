@@ -0,0 +1,142 @@
+// Package trash moves files to the desktop trash instead of deleting them,
+// so they can be restored: the freedesktop.org trash on Linux and the BSDs,
+// ~/.Trash on macOS.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrUnavailable means there is no trash to move the file to, e.g. on
+// Windows or when the file is on a different filesystem than the trash
+var ErrUnavailable = errors.New("trash not available")
+
+// Dir returns the trash directory files are moved to on this platform
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", ErrUnavailable
+	}
+	switch runtime.GOOS {
+	case "windows":
+		return "", ErrUnavailable
+	case "darwin":
+		return filepath.Join(home, ".Trash"), nil
+	default:
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		return filepath.Join(dataHome, "Trash"), nil
+	}
+}
+
+// Move moves path to the trash and returns where it ended up
+func Move(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return "", err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS == "darwin" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", err
+		}
+		dest := uniquePath(dir, filepath.Base(path))
+		return dest, rename(path, dest)
+	}
+	return moveFreedesktop(dir, path)
+}
+
+// moveFreedesktop follows the freedesktop.org trash specification: the file
+// goes into files/ and a .trashinfo in info/ records where it came from
+func moveFreedesktop(dir, path string) (string, error) {
+	filesDir := filepath.Join(dir, "files")
+	infoDir := filepath.Join(dir, "info")
+	for _, d := range []string{filesDir, infoDir} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return "", err
+		}
+	}
+
+	// The info file is created first and exclusively, reserving the name
+	base := filepath.Base(path)
+	var name string
+	var info *os.File
+	for i := 1; ; i++ {
+		name = base
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d", base, i)
+		}
+		f, err := os.OpenFile(filepath.Join(infoDir, name+".trashinfo"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		info = f
+		break
+	}
+
+	_, err := fmt.Fprintf(info, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		escapePath(path), time.Now().Format("2006-01-02T15:04:05"))
+	if closeErr := info.Close(); err == nil {
+		err = closeErr
+	}
+	dest := filepath.Join(filesDir, name)
+	if err == nil {
+		err = rename(path, dest)
+	}
+	if err != nil {
+		os.Remove(filepath.Join(infoDir, name+".trashinfo"))
+		return "", err
+	}
+	return dest, nil
+}
+
+// rename moves a file, reporting a move across filesystems as ErrUnavailable
+// since the trash can't hold it without copying
+func rename(from, to string) error {
+	err := os.Rename(from, to)
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) && errors.Is(linkErr.Err, syscall.EXDEV) {
+		return fmt.Errorf("%w: %s is on a different filesystem", ErrUnavailable, from)
+	}
+	return err
+}
+
+// uniquePath returns dir/name, or "name 2", "name 3"... if it's taken
+func uniquePath(dir, name string) string {
+	dest := filepath.Join(dir, name)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(dest); os.IsNotExist(err) {
+			return dest
+		}
+		dest = filepath.Join(dir, fmt.Sprintf("%s %d", name, i))
+	}
+}
+
+// escapePath URL-encodes a path for a .trashinfo file, keeping the slashes
+func escapePath(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,60 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("checks the freedesktop trash layout")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "share"))
+	trashDir := filepath.Join(home, "share", "Trash")
+
+	dir := t.TempDir()
+	for i := 1; i <= 2; i++ {
+		path := filepath.Join(dir, "build dir")
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "out"), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		dest, err := Move(path)
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		want := filepath.Join(trashDir, "files", "build dir")
+		if i == 2 {
+			want += ".2"
+		}
+		if dest != want {
+			t.Errorf("Move() = %s, want %s", dest, want)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("the original should be gone")
+		}
+		if _, err := os.Stat(filepath.Join(dest, "out")); err != nil {
+			t.Errorf("the contents should be in the trash: %v", err)
+		}
+
+		info, err := os.ReadFile(filepath.Join(trashDir, "info", filepath.Base(dest)+".trashinfo"))
+		if err != nil {
+			t.Fatalf("missing .trashinfo: %v", err)
+		}
+		if !strings.HasPrefix(string(info), "[Trash Info]\nPath="+filepath.ToSlash(dir)+"/build%20dir\nDeletionDate=") {
+			t.Errorf(".trashinfo = %q", info)
+		}
+	}
+
+	if _, err := Move(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Move(missing) error = %v, want not exist", err)
+	}
+}
@@ -4,16 +4,32 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines keyboard shortcuts
 type KeyMap struct {
-	Up      key.Binding
-	Down    key.Binding
-	Enter   key.Binding
-	Back    key.Binding
-	Quit    key.Binding
-	New     key.Binding
-	Search  key.Binding
-	Sort    key.Binding
-	Help    key.Binding
-	Refresh key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Enter     key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+	New       key.Binding
+	Search    key.Binding
+	Sort      key.Binding
+	Help      key.Binding
+	Refresh   key.Binding
+	Reopen    key.Binding
+	Issues    key.Binding
+	Mark      key.Binding
+	Each      key.Binding
+	Bootstrap key.Binding
+	Density   key.Binding
+	DryRun    key.Binding
+	Edit      key.Binding
+	Rerun     key.Binding
+	History   key.Binding
+	Stats     key.Binding
+	Switch    key.Binding
+	Plugins   key.Binding
+	Workspace key.Binding
+	Pin       key.Binding
+	Archived  key.Binding
 }
 
 // DefaultKeyMap returns the default key mappings
@@ -59,6 +75,70 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("f5", "r"),
 			key.WithHelp("F5/r", "refresh"),
 		),
+		Reopen: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "reopen like last time"),
+		),
+		Issues: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "scan issues"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark for bulk run"),
+		),
+		Each: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "run command in marked projects"),
+		),
+		Bootstrap: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "bootstrap project"),
+		),
+		Density: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "toggle compact/detailed list"),
+		),
+		DryRun: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "dry run: show what an action would run"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit a script action's command and run it once"),
+		),
+		Rerun: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "re-run the last action"),
+		),
+		History: key.NewBinding(
+			key.WithKeys("h"),
+			key.WithHelp("h", "command history"),
+		),
+		Stats: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "usage statistics"),
+		),
+		Switch: key.NewBinding(
+			key.WithKeys("ctrl+o", "-"),
+			key.WithHelp("-/ctrl+o", "switch to the previous project"),
+		),
+		Plugins: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "plugin manager"),
+		),
+		Workspace: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "workspace actions"),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "pin"),
+		),
+		Archived: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "archived projects"),
+		),
 	}
 }
 
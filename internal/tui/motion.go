@@ -0,0 +1,15 @@
+package tui
+
+import "github.com/charmbracelet/bubbles/cursor"
+
+// ReducedMotion turns off animation, for display.reducedMotion: text
+// cursors stay still instead of blinking. Set it before creating views.
+var ReducedMotion bool
+
+// CursorMode returns the mode text cursors start in
+func CursorMode() cursor.Mode {
+	if ReducedMotion {
+		return cursor.CursorStatic
+	}
+	return cursor.CursorBlink
+}
@@ -9,6 +9,7 @@ var (
 	Primary = lipgloss.AdaptiveColor{Light: "#00CED1", Dark: "#00CED1"}
 	Accent  = lipgloss.AdaptiveColor{Light: "#32CD32", Dark: "#32CD32"}
 	Error   = lipgloss.AdaptiveColor{Light: "#FF6347", Dark: "#FF6347"}
+	Warning = lipgloss.AdaptiveColor{Light: "#D7A000", Dark: "#FFD700"}
 	Muted   = lipgloss.AdaptiveColor{Light: "#A0A0A0", Dark: "#707070"}
 	Border  = lipgloss.AdaptiveColor{Light: "#D0D0D0", Dark: "#404040"}
 )
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ToastLevel is the kind of a toast, which sets its colour and how long it
+// is shown
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// How long toasts are shown; errors stay longer to be read
+const (
+	ToastDuration      = 3 * time.Second
+	ErrorToastDuration = 6 * time.Second
+)
+
+// maxToasts is how many toasts are shown at once; older ones make way
+const maxToasts = 3
+
+// toastWidth is the widest a toast gets before its text wraps
+const toastWidth = 48
+
+// toast is a message shown in the corner until it expires
+type toast struct {
+	id        int
+	level     ToastLevel
+	text      string
+	scheduled bool // Its expiry is ticking
+}
+
+// Toasts are transient messages ("Copied!", "Config saved") shown in the
+// top-right corner of any view and dismissed after a while. They are pushed
+// without a command; Schedule starts the timers of the new ones, so callers
+// deep in a handler needn't thread a tea.Cmd back out.
+type Toasts struct {
+	items []toast
+	next  int
+}
+
+// ToastExpiredMsg dismisses a toast when its time is up
+type ToastExpiredMsg struct {
+	id int
+}
+
+// Push adds a toast
+func (t *Toasts) Push(level ToastLevel, text string) {
+	t.next++
+	t.items = append(t.items, toast{id: t.next, level: level, text: text})
+	if len(t.items) > maxToasts {
+		t.items = t.items[len(t.items)-maxToasts:]
+	}
+}
+
+// Info adds an informational toast
+func (t *Toasts) Info(text string) {
+	t.Push(ToastInfo, text)
+}
+
+// Success adds a toast reporting something done
+func (t *Toasts) Success(text string) {
+	t.Push(ToastSuccess, text)
+}
+
+// Warn adds a toast about something that went partly wrong
+func (t *Toasts) Warn(text string) {
+	t.Push(ToastWarning, text)
+}
+
+// Error adds a toast reporting err
+func (t *Toasts) Error(err error) {
+	t.Push(ToastError, "Error: "+err.Error())
+}
+
+// Schedule returns the command dismissing the toasts pushed since it was
+// last called, or nil
+func (t *Toasts) Schedule() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range t.items {
+		if t.items[i].scheduled {
+			continue
+		}
+		t.items[i].scheduled = true
+		id, duration := t.items[i].id, ToastDuration
+		if t.items[i].level == ToastError {
+			duration = ErrorToastDuration
+		}
+		cmds = append(cmds, tea.Tick(duration, func(time.Time) tea.Msg {
+			return ToastExpiredMsg{id: id}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Dismiss removes the toast a ToastExpiredMsg is for
+func (t *Toasts) Dismiss(msg ToastExpiredMsg) {
+	for i, item := range t.items {
+		if item.id == msg.id {
+			t.items = append(t.items[:i:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Texts returns the text of the toasts shown, oldest first
+func (t Toasts) Texts() []string {
+	texts := make([]string, len(t.items))
+	for i, item := range t.items {
+		texts[i] = item.text
+	}
+	return texts
+}
+
+// View renders the toasts stacked, the newest at the bottom
+func (t Toasts) View() string {
+	boxes := make([]string, len(t.items))
+	for i, item := range t.items {
+		color := Primary
+		switch item.level {
+		case ToastSuccess:
+			color = Accent
+		case ToastWarning:
+			color = Warning
+		case ToastError:
+			color = Error
+		}
+		text := item.text
+		if lipgloss.Width(text) > toastWidth {
+			text = ansi.Wrap(text, toastWidth, " ")
+		}
+		boxes[i] = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color).
+			Foreground(color).
+			Padding(0, 1).
+			Render(text)
+	}
+	return lipgloss.JoinVertical(lipgloss.Right, boxes...)
+}
+
+// Overlay draws the toasts over the top-right corner of view, which is
+// width columns wide
+func (t Toasts) Overlay(view string, width int) string {
+	if len(t.items) == 0 {
+		return view
+	}
+	toasts := strings.Split(t.View(), "\n")
+	lines := strings.Split(view, "\n")
+	for len(lines) < len(toasts) {
+		lines = append(lines, "")
+	}
+	for i, toast := range toasts {
+		w := ansi.StringWidth(toast)
+		left := max(width-w-1, 0)
+		line := ansi.Truncate(lines[i], left, "")
+		if pad := left - ansi.StringWidth(line); pad > 0 {
+			line += strings.Repeat(" ", pad)
+		}
+		lines[i] = line + toast + ansi.TruncateLeft(lines[i], left+w, "")
+	}
+	return strings.Join(lines, "\n")
+}
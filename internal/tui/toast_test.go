@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestToasts(t *testing.T) {
+	var toasts Toasts
+	for i := 1; i <= 4; i++ {
+		toasts.Info(fmt.Sprintf("toast %d", i))
+	}
+	if got := strings.Join(toasts.Texts(), ", "); got != "toast 2, toast 3, toast 4" {
+		t.Fatalf("Texts() = %q, want the newest %d", got, maxToasts)
+	}
+
+	if toasts.Schedule() == nil {
+		t.Fatal("Expected the new toasts to be scheduled")
+	}
+	if toasts.Schedule() != nil {
+		t.Error("Expected toasts to be scheduled once")
+	}
+
+	toasts.Dismiss(ToastExpiredMsg{id: toasts.items[0].id})
+	if got := strings.Join(toasts.Texts(), ", "); got != "toast 3, toast 4" {
+		t.Errorf("Texts() after dismissing = %q", got)
+	}
+	toasts.Dismiss(ToastExpiredMsg{id: 99})
+	if len(toasts.Texts()) != 2 {
+		t.Error("Expected an unknown toast's expiry to change nothing")
+	}
+}
+
+func TestToastsOverlay(t *testing.T) {
+	view := strings.Repeat(strings.Repeat(".", 40)+"\n", 5) + "end"
+
+	var toasts Toasts
+	if got := toasts.Overlay(view, 40); got != view {
+		t.Error("Expected no toasts to leave the view as it is")
+	}
+
+	toasts.Error(errors.New("boom"))
+	lines := strings.Split(toasts.Overlay(view, 40), "\n")
+	if len(lines) != 6 || lines[5] != "end" {
+		t.Fatalf("Expected the view's lines to be kept, got %q", lines)
+	}
+	if plain := ansi.Strip(lines[1]); !strings.HasSuffix(plain, "│ Error: boom │.") || ansi.StringWidth(lines[1]) != 40 {
+		t.Errorf("Expected the toast in the top-right corner, got %q", plain)
+	}
+}
@@ -3,14 +3,23 @@ package views
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/envfile"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/jvm"
 	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/scaffold"
 	"github.com/s33g/proj/internal/scripts"
+	"github.com/s33g/proj/internal/security"
+	"github.com/s33g/proj/internal/state"
 	"github.com/s33g/proj/internal/tui"
 )
 
@@ -30,6 +39,16 @@ type Action struct {
 	Source   string   // Source of the script (package.json, Makefile, etc)
 	IsSubmenu bool    // Whether this action opens a submenu
 	Children []Action // Submenu actions
+	Parser   string   // Output parser for a structured summary (see results.Parsers)
+}
+
+// PluginsLoadingID is the ID of the placeholder shown while plugin actions load
+const PluginsLoadingID = "plugins-loading"
+
+// PluginsLoadingAction is the placeholder for the plugin actions, shown in
+// their place until the plugins answer
+func PluginsLoadingAction() Action {
+	return Action{ID: PluginsLoadingID, Label: "Loading plugin actions…"}
 }
 
 // FilterValue implements list.Item
@@ -65,6 +84,12 @@ func (d actionDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 
 	isSelected := index == m.Index()
 
+	if a.ID == PluginsLoadingID {
+		placeholder := lipgloss.NewStyle().Foreground(tui.Muted).Italic(true)
+		_, _ = fmt.Fprint(w, actionItemStyle.Render("  "+placeholder.Render(a.Label))+"\n")
+		return
+	}
+
 	// Build the line
 	var line strings.Builder
 
@@ -106,6 +131,7 @@ func (d actionDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 type ActionMenuModel struct {
 	list    list.Model
 	project *project.Project
+	actions []Action
 	width   int
 	height  int
 }
@@ -130,6 +156,7 @@ func NewActionMenuModel(proj *project.Project, actions []Action) ActionMenuModel
 	return ActionMenuModel{
 		list:    l,
 		project: proj,
+		actions: actions,
 		width:   80,
 		height:  20,
 	}
@@ -166,9 +193,61 @@ func (m ActionMenuModel) SelectedAction() *Action {
 	return &action
 }
 
+// SetActions replaces the actions shown, keeping the selected one
+func (m *ActionMenuModel) SetActions(actions []Action) {
+	selected := m.SelectedAction()
+	items := make([]list.Item, len(actions))
+	for i, a := range actions {
+		items[i] = a
+	}
+	m.actions = actions
+	m.list.SetItems(items)
+	if selected == nil {
+		return
+	}
+	for i, a := range actions {
+		if a.ID == selected.ID {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// Actions returns the actions shown in the menu
+func (m ActionMenuModel) Actions() []Action {
+	return m.actions
+}
+
+// FindAction finds an action by ID, searching submenus recursively
+func FindAction(actions []Action, id string) *Action {
+	for i := range actions {
+		if actions[i].ID == id {
+			return &actions[i]
+		}
+		if actions[i].IsSubmenu {
+			if found := FindAction(actions[i].Children, id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
 // DefaultActions returns the default set of actions
 func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Action {
-	actions := []Action{
+	actions := []Action{}
+
+	// Offer to set up projects whose dependencies aren't installed yet
+	if bootstrap := BootstrapAction(proj); bootstrap != nil {
+		actions = append(actions, *bootstrap)
+	}
+
+	// Offer to reopen the project the way it was opened last time
+	if reopen := ReopenAction(proj); reopen != nil {
+		actions = append(actions, *reopen)
+	}
+
+	actions = append(actions, []Action{
 		{
 			ID:    "open-editor",
 			Label: "Open in Editor",
@@ -181,7 +260,7 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 			Desc:  "Navigate to project directory",
 			Icon:  "📂",
 		},
-	}
+	}...)
 
 	// Git actions (if enabled and is git repo)
 	if gitEnabled && proj.IsGitRepo {
@@ -204,7 +283,45 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 				Desc:  "Checkout a different branch",
 				Icon:  "🌿",
 			},
+			Action{
+				ID:    "git-new-branch",
+				Label: "New Branch from Template",
+				Desc:  "Name a branch from its type, ticket and description, then switch to it",
+				Icon:  "🌱",
+			},
 		)
+		if proj.GitDirty {
+			actions = append(actions, Action{
+				ID:    "git-commit",
+				Label: "Commit",
+				Desc:  "Commit changes with a conventional type, message template and trailers",
+				Icon:  "💬",
+			})
+		}
+		if key := git.IssueKey(proj.GitBranch); key != "" {
+			actions = append(actions, Action{
+				ID:    "open-issue",
+				Label: "Open Issue",
+				Desc:  fmt.Sprintf("Open %s, named by the branch, in the issue tracker", key),
+				Icon:  "🎫",
+			})
+		}
+		if proj.UsesLFS {
+			actions = append(actions,
+				Action{
+					ID:    "git-lfs-pull",
+					Label: "LFS Pull",
+					Desc:  "Download the large files of the current checkout",
+					Icon:  "📦",
+				},
+				Action{
+					ID:    "git-lfs-prune",
+					Label: "LFS Prune",
+					Desc:  "Delete local copies of old large files",
+					Icon:  "🧹",
+				},
+			)
+		}
 	} else if gitEnabled && !proj.IsGitRepo {
 		// Show git init for non-git directories
 		actions = append(actions, Action{
@@ -216,7 +333,7 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 	}
 
 	// Detect and add project scripts
-	detectedScripts := scripts.Detect(proj.Path, proj.Language)
+	detectedScripts := scripts.Detect(proj.WorkPath(), proj.Language)
 	if len(detectedScripts) > 0 {
 		// Group scripts by source
 		sourceGroups := make(map[string][]scripts.Script)
@@ -244,6 +361,7 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 						Icon:    "▸",
 						Command: s.Command,
 						Source:  s.Source,
+						Parser:  s.Parser,
 					}
 				}
 
@@ -270,12 +388,25 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 						Icon:    icon,
 						Command: s.Command,
 						Source:  s.Source,
+						Parser:  s.Parser,
 					})
 				}
 			}
 		}
 	}
 
+	if work := goWorkAction(proj); work != nil {
+		actions = append(actions, *work)
+	}
+
+	if tasks := buildTasksAction(proj); tasks != nil {
+		actions = append(actions, *tasks)
+	}
+
+	if env := envAction(proj); env != nil {
+		actions = append(actions, *env)
+	}
+
 	// Docker actions - group into submenu if present
 	if proj.HasDockerfile || proj.HasCompose {
 		dockerInfo, err := docker.Detect(proj.Path)
@@ -313,6 +444,18 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 					})
 				}
 
+				// Open the ports the services publish, once they're up
+				for _, port := range docker.ComposePorts(proj.Path, dockerInfo.ComposeFiles) {
+					open := OpenPortAction(port, "Open a port the compose services publish in the browser")
+					open.Label = "🌐 " + open.Label
+					open.Icon = ""
+					composeChildren = append(composeChildren, open)
+				}
+
+				for _, db := range docker.Databases(proj.Path, dockerInfo.ComposeFiles) {
+					composeChildren = append(composeChildren, databaseActions(db)...)
+				}
+
 				// Add Compose submenu if there are compose actions
 				if len(composeChildren) > 0 {
 					actions = append(actions, Action{
@@ -328,6 +471,18 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 		}
 	}
 
+	// Dependency audit for ecosystems with an audit tool
+	if security.CanAudit(proj.Language) {
+		actions = append(actions, Action{
+			ID:    "audit-deps",
+			Label: "Audit Dependencies",
+			Desc:  "Check dependencies for known vulnerabilities",
+			Icon:  "🛡️",
+		})
+	}
+
+	actions = append(actions, ScaffoldAction(proj))
+
 	// General actions
 	actions = append(actions,
 		Action{
@@ -339,9 +494,28 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 		Action{
 			ID:    "clean",
 			Label: "Clean Build Artifacts",
-			Desc:  "Remove build directories",
+			Desc:  "Pick build directories to remove, with their sizes",
 			Icon:  "🗑️",
 		},
+		Action{
+			ID:    "backup",
+			Label: "Backup Project",
+			Desc:  "Write a git bundle or tar.gz to the backup directory",
+			Icon:  "💾",
+		},
+		Action{
+			ID:    "duplicate",
+			Label: "Duplicate Project",
+			Desc:  "Copy to a new name without .git and build artifacts, e.g. as a template",
+			Icon:  "📋",
+		},
+		ArchiveAction(proj),
+		Action{
+			ID:    "scan-secrets",
+			Label: "Scan for Secrets",
+			Desc:  "Look for leaked keys and tokens (gitleaks, trufflehog or built-in rules)",
+			Icon:  "🔐",
+		},
 		Action{
 			ID:    "back",
 			Label: "← Back",
@@ -353,6 +527,415 @@ func DefaultActions(proj *project.Project, gitEnabled, testsEnabled bool) []Acti
 	return actions
 }
 
+// ArchiveAction returns the action that archives a project, hiding it from
+// the project list without touching its directory, or restores an archived one
+func ArchiveAction(proj *project.Project) Action {
+	if proj.Archived {
+		return Action{
+			ID:    "unarchive",
+			Label: "Restore from Archive",
+			Desc:  "Show the project in the project list again",
+			Icon:  "📤",
+		}
+	}
+	return Action{
+		ID:    "archive",
+		Label: "Archive Project",
+		Desc:  "Hide from the project list, leaving the directory where it is (a: show archived)",
+		Icon:  "🗄️",
+	}
+}
+
+// ScaffoldAction returns the "Add Scaffolding" submenu. A .gitignore can
+// always be generated, since it's merged into an existing one; the other
+// files are only offered while the project doesn't have them.
+func ScaffoldAction(proj *project.Project) Action {
+	children := []Action{{
+		ID:    "generate-gitignore",
+		Label: "Generate .gitignore",
+		Desc:  "Preview ignore rules for the detected languages, then write or merge them",
+		Icon:  "▸",
+	}}
+	if !scaffold.Exists(proj.Path, scaffold.LicensePath) {
+		var licenses []Action
+		for _, name := range scaffold.Licenses() {
+			licenses = append(licenses, Action{
+				ID:    "scaffold-license-" + name,
+				Label: name,
+				Desc:  "Write LICENSE",
+				Icon:  "▸",
+			})
+		}
+		if len(licenses) > 0 {
+			children = append(children, Action{
+				ID:        "submenu-scaffold-license",
+				Label:     "LICENSE",
+				Desc:      fmt.Sprintf("%d licenses", len(licenses)),
+				Icon:      "⚖️",
+				IsSubmenu: true,
+				Children:  licenses,
+			})
+		}
+	}
+	if !scaffold.Exists(proj.Path, scaffold.EditorConfigPath) {
+		children = append(children, Action{
+			ID:    "scaffold-editorconfig",
+			Label: ".editorconfig",
+			Desc:  "Consistent indentation and line endings across editors",
+			Icon:  "▸",
+		})
+	}
+	if scaffold.HasCI(proj.Language) && !scaffold.Exists(proj.Path, scaffold.CIPath) {
+		children = append(children, Action{
+			ID:    "scaffold-ci",
+			Label: "CI Workflow",
+			Desc:  "GitHub Actions workflow that builds and tests " + proj.Language,
+			Icon:  "▸",
+		})
+	}
+	return Action{
+		ID:        "submenu-scaffold",
+		Label:     "Add Scaffolding",
+		Desc:      "Generate missing project files from templates",
+		Icon:      "🧱",
+		IsSubmenu: true,
+		Children:  children,
+	}
+}
+
+// ReopenAction returns the "reopen like last time" action, or nil if the
+// project has never been opened with proj
+func ReopenAction(proj *project.Project) *Action {
+	if proj.LastOpenedWith == "" {
+		return nil
+	}
+
+	label := fmt.Sprintf("Reopen in %s", proj.LastOpenedWith)
+	if proj.LastOpenedWith == state.OpenedInTerminal {
+		label = "Reopen in Terminal"
+	}
+
+	desc := "Open the same way as last time"
+	if !proj.LastOpenedAt.IsZero() {
+		desc = fmt.Sprintf("Open the same way as last time (%s)", RelativeTime(proj.LastOpenedAt, time.Now()))
+	}
+
+	return &Action{
+		ID:    "reopen",
+		Label: label,
+		Desc:  desc,
+		Icon:  "⏪",
+	}
+}
+
+// BootstrapAction returns the action that sets up a project's environment, or
+// nil if the project doesn't need setup
+func BootstrapAction(proj *project.Project) *Action {
+	if !proj.NeedsSetup {
+		return nil
+	}
+
+	return &Action{
+		ID:    "bootstrap",
+		Label: "Bootstrap",
+		Desc:  "Set up the environment and install dependencies",
+		Icon:  "🧰",
+	}
+}
+
+// WorkspaceActions returns the actions that aren't tied to a project, offered
+// from the project list
+func WorkspaceActions() []Action {
+	return []Action{
+		{
+			ID:    "workspace-pull-all",
+			Label: "Pull All",
+			Desc:  "Fast-forward every git project from its upstream",
+			Icon:  "🔄",
+		},
+		{
+			ID:    "workspace-sync-remotes",
+			Label: "Sync Remotes",
+			Desc:  "Fetch all remotes of every git project, pruning deleted branches",
+			Icon:  "📡",
+		},
+		{
+			ID:    "workspace-rescan",
+			Label: "Rescan Projects",
+			Desc:  "Scan the projects directory again",
+			Icon:  "🔍",
+		},
+		{
+			ID:    "workspace-clean-all",
+			Label: "Clean All",
+			Desc:  "Pick build artifacts to remove across all projects",
+			Icon:  "🗑️",
+		},
+		{
+			ID:    "workspace-open-config",
+			Label: "Open Config",
+			Desc:  "Edit config.json in $EDITOR, then reload it",
+			Icon:  "⚙️",
+		},
+		{
+			ID:    "workspace-open-plugins",
+			Label: "Open Plugins Directory",
+			Desc:  "Open the plugins directory in the configured editor",
+			Icon:  "🧩",
+		},
+		{
+			ID:    "back",
+			Label: "← Back",
+			Desc:  "Return to project list",
+		},
+	}
+}
+
+// goWorkAction returns the Go Workspace submenu, or nil if the project has
+// no go.work using any modules
+func goWorkAction(proj *project.Project) *Action {
+	modules := project.GoWorkModules(proj.Path)
+	if len(modules) == 0 {
+		return nil
+	}
+
+	children := []Action{
+		{
+			ID:    "gowork-list",
+			Label: "List Modules",
+			Desc:  "Modules go.work uses, with their module paths",
+			Icon:  "▸",
+		},
+		{
+			ID:    "gowork-sync",
+			Label: "Sync Workspace",
+			Desc:  "go work sync: push the workspace's dependency versions to each module",
+			Icon:  "▸",
+		},
+	}
+	for _, dir := range modules {
+		children = append(children,
+			Action{
+				ID:    "gowork-build-" + dir,
+				Label: "Build " + dir,
+				Desc:  "go build ./... in " + dir,
+				Icon:  "▸",
+			},
+			Action{
+				ID:    "gowork-test-" + dir,
+				Label: "Test " + dir,
+				Desc:  "go test ./... in " + dir,
+				Icon:  "▸",
+			},
+		)
+	}
+	return &Action{
+		ID:        "submenu-gowork",
+		Label:     "Go Workspace",
+		Desc:      fmt.Sprintf("%d modules in go.work", len(modules)),
+		Icon:      "🐹",
+		IsSubmenu: true,
+		Children:  children,
+	}
+}
+
+// RefreshTasksID is the action that lists a Gradle build's tasks again
+const RefreshTasksID = "jvm-refresh-tasks"
+
+// buildTasksAction returns the submenu of a Gradle or Maven build's tasks,
+// grouped as the build groups them, or nil for other projects. Gradle's
+// tasks come from the cache, so until they're listed the submenu only
+// offers to list them.
+func buildTasksAction(proj *project.Project) *Action {
+	path := proj.WorkPath()
+	tool := jvm.BuildTool(path)
+	if tool == "" {
+		return nil
+	}
+	command := jvm.Command(tool, path)
+
+	var tasks []jvm.Task
+	var refresh *Action
+	label, icon := "Maven", "🪶"
+	if tool == jvm.Gradle {
+		label, icon = "Gradle Tasks", "🐘"
+		refresh = &Action{
+			ID:    RefreshTasksID,
+			Label: "Load Tasks",
+			Desc:  fmt.Sprintf("Run %s tasks --all, which takes a while, and remember them", command),
+			Icon:  "↻",
+		}
+		if cache := jvm.CachedTasks(path); cache != nil {
+			tasks = cache.Tasks
+			refresh.Label = "Refresh Tasks"
+			refresh.Desc = "Listed " + RelativeTime(cache.ListedAt, time.Now())
+			if cache.Stale(path) {
+				refresh.Desc += "; the build files changed since"
+			}
+		}
+	} else {
+		var err error
+		if tasks, err = jvm.MavenTasks(path); err != nil {
+			return nil
+		}
+	}
+
+	// Nest the tasks in a submenu per group, unless there's only one
+	var groups []string
+	byGroup := map[string][]Action{}
+	for _, t := range tasks {
+		desc := t.Desc
+		if desc == "" {
+			desc = command + " " + t.Name
+		}
+		if _, ok := byGroup[t.Group]; !ok {
+			groups = append(groups, t.Group)
+		}
+		byGroup[t.Group] = append(byGroup[t.Group], Action{
+			ID:      tool + "-" + strings.ReplaceAll(t.Name, " ", "-"),
+			Label:   t.Name,
+			Desc:    desc,
+			Icon:    "▸",
+			Command: command + " " + t.Name,
+			Source:  tool,
+		})
+	}
+	var children []Action
+	if len(groups) == 1 {
+		children = byGroup[groups[0]]
+	} else {
+		for _, group := range groups {
+			name := group
+			if name == "" {
+				name = "Other"
+			}
+			children = append(children, Action{
+				ID:        "submenu-" + tool + "-" + strings.ToLower(strings.ReplaceAll(name, " ", "-")),
+				Label:     name,
+				Desc:      fmt.Sprintf("%d tasks", len(byGroup[group])),
+				Icon:      "▸",
+				IsSubmenu: true,
+				Children:  byGroup[group],
+			})
+		}
+	}
+	if refresh != nil {
+		children = append(children, *refresh)
+	}
+
+	desc := fmt.Sprintf("%d tasks", len(tasks))
+	if len(tasks) == 0 {
+		desc = "Tasks haven't been listed yet"
+	}
+	return &Action{
+		ID:        "submenu-" + tool,
+		Label:     label,
+		Desc:      desc,
+		Icon:      icon,
+		IsSubmenu: true,
+		Children:  children,
+	}
+}
+
+// envAction returns the .env Manager submenu, or nil if the project has no
+// .env files
+func envAction(proj *project.Project) *Action {
+	files := envfile.Files(proj.Path)
+	if len(files) == 0 {
+		return nil
+	}
+
+	children := []Action{{
+		ID:    "env-show",
+		Label: "Show Variables",
+		Desc:  "Keys of every .env file, with values masked, and what .env is missing",
+		Icon:  "▸",
+	}}
+	if example := envfile.Example(proj.Path); example != "" {
+		exampleVars, _ := envfile.Parse(filepath.Join(proj.Path, example))
+		vars, err := envfile.Parse(filepath.Join(proj.Path, envfile.Name))
+		if os.IsNotExist(err) {
+			children = append(children, Action{
+				ID:    "env-create",
+				Label: "Create .env from " + example,
+				Desc:  fmt.Sprintf("Copy %s, with its %d keys, to .env", example, len(exampleVars)),
+				Icon:  "▸",
+			})
+		} else if missing := envfile.Missing(exampleVars, vars); len(missing) > 0 {
+			children = append(children, Action{
+				ID:    "env-sync",
+				Label: fmt.Sprintf("Add %d Missing Keys to .env", len(missing)),
+				Desc:  fmt.Sprintf("Add %s from %s", strings.Join(envfile.Keys(missing), ", "), example),
+				Icon:  "▸",
+			})
+		}
+	}
+	for _, name := range files {
+		children = append(children, Action{
+			ID:    "env-open-" + name,
+			Label: "Edit " + name,
+			Desc:  "Open " + name + " in your editor",
+			Icon:  "▸",
+		})
+	}
+
+	return &Action{
+		ID:        "submenu-env",
+		Label:     ".env Manager",
+		Desc:      fmt.Sprintf("%d environment files", len(files)),
+		Icon:      "🔑",
+		IsSubmenu: true,
+		Children:  children,
+	}
+}
+
+// OpenPortAction returns the action that opens http://localhost:port
+func OpenPortAction(port int, desc string) Action {
+	return Action{
+		ID:    fmt.Sprintf("open-port-%d", port),
+		Label: fmt.Sprintf("Open localhost:%d", port),
+		Desc:  desc,
+		Icon:  "🌐",
+	}
+}
+
+// databaseActions returns the quick actions for a database service of a
+// compose project
+func databaseActions(db docker.Database) []Action {
+	client := map[docker.DatabaseKind]string{
+		docker.Postgres: "psql",
+		docker.MySQL:    "mysql",
+		docker.MariaDB:  "mariadb",
+		docker.Redis:    "redis-cli",
+	}[db.Kind]
+
+	actions := []Action{
+		{
+			ID:    "db-shell-" + db.Service,
+			Label: fmt.Sprintf("🗄  %s Shell (%s)", client, db.Service),
+			Desc:  fmt.Sprintf("Open %s in the %s container", client, db.Service),
+		},
+		{
+			ID:    "db-url-" + db.Service,
+			Label: fmt.Sprintf("🔗 Connection String (%s)", db.Service),
+			Desc:  "Show the URL for connecting to " + db.Service,
+		},
+		{
+			ID:    "db-dump-" + db.Service,
+			Label: fmt.Sprintf("💾 Dump %s", db.Service),
+			Desc:  "Write a dump of " + db.Service + " to the backup directory",
+		},
+	}
+	if db.RestoreArgs() != nil {
+		actions = append(actions, Action{
+			ID:    "db-restore-" + db.Service,
+			Label: fmt.Sprintf("♻️  Restore Latest Dump (%s)", db.Service),
+			Desc:  "Load the newest dump of " + db.Service + ", replacing the tables it holds",
+		})
+	}
+	return actions
+}
+
 // getScriptIcon returns an icon based on the script source
 func getScriptIcon(source string) string {
 	switch source {
@@ -366,6 +949,10 @@ func getScriptIcon(source string) string {
 		return "🔵"
 	case "cargo":
 		return "🦀"
+	case "cmake":
+		return "🔺"
+	case "dotnet":
+		return "🔷"
 	case "poetry", "pip", "python", "pytest":
 		return "🐍"
 	case "django":
@@ -393,6 +980,10 @@ func getSourceDisplayName(source string) string {
 		return "Go Commands"
 	case "cargo":
 		return "Cargo"
+	case "cmake":
+		return "CMake"
+	case "dotnet":
+		return ".NET"
 	case "poetry":
 		return "Poetry"
 	case "pip":
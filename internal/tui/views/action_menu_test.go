@@ -1,8 +1,12 @@
 package views
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/s33g/proj/internal/jvm"
 	"github.com/s33g/proj/internal/project"
 )
 
@@ -94,6 +98,35 @@ func TestDefaultActionsStructure(t *testing.T) {
 	}
 }
 
+func TestDefaultActionsWithLFS(t *testing.T) {
+	proj := &project.Project{Name: "assets", Path: "/tmp/assets", IsGitRepo: true}
+	if FindAction(DefaultActions(proj, true, true), "git-lfs-pull") != nil {
+		t.Error("LFS actions should only be offered for LFS repositories")
+	}
+
+	proj.UsesLFS = true
+	actions := DefaultActions(proj, true, true)
+	if FindAction(actions, "git-lfs-pull") == nil || FindAction(actions, "git-lfs-prune") == nil {
+		t.Error("Expected LFS pull and prune actions")
+	}
+}
+
+func TestDefaultActionsWithIssue(t *testing.T) {
+	proj := &project.Project{Name: "api", Path: "/tmp/api", IsGitRepo: true, GitBranch: "main"}
+	if FindAction(DefaultActions(proj, true, true), "open-issue") != nil {
+		t.Error("Open Issue should only be offered for branches naming an issue")
+	}
+
+	proj.GitBranch = "feature/ABC-123-login"
+	action := FindAction(DefaultActions(proj, true, true), "open-issue")
+	if action == nil || !strings.Contains(action.Desc, "ABC-123") {
+		t.Errorf("Expected an Open Issue action for ABC-123, got %+v", action)
+	}
+	if header := ActionHeader(proj.Name, "Go", proj.GitBranch, false); !strings.Contains(header, "ABC-123") {
+		t.Errorf("Expected the issue key in the header, got %q", header)
+	}
+}
+
 func TestDefaultActionsWithDocker(t *testing.T) {
 	// This test would require mocking the docker.Detect function
 	// For now, we test the structure without actual docker files
@@ -257,3 +290,192 @@ func TestNewActionMenuModel(t *testing.T) {
 		t.Errorf("Project name = %q, want %q", model.project.Name, "test")
 	}
 }
+
+func TestFindAction(t *testing.T) {
+	actions := []Action{
+		{ID: "open-editor", Label: "Open in Editor"},
+		{
+			ID:        "submenu-docker",
+			Label:     "Docker",
+			IsSubmenu: true,
+			Children: []Action{
+				{ID: "docker-build", Label: "Build Image"},
+			},
+		},
+	}
+
+	if a := FindAction(actions, "open-editor"); a == nil || a.Label != "Open in Editor" {
+		t.Errorf("Expected to find open-editor, got %v", a)
+	}
+
+	if a := FindAction(actions, "docker-build"); a == nil || a.Label != "Build Image" {
+		t.Errorf("Expected to find nested docker-build, got %v", a)
+	}
+
+	if a := FindAction(actions, "missing"); a != nil {
+		t.Errorf("Expected nil for missing action, got %v", a)
+	}
+}
+
+func TestReopenAction(t *testing.T) {
+	proj := &project.Project{Name: "test", Path: "/tmp/test"}
+	if a := ReopenAction(proj); a != nil {
+		t.Errorf("Expected no reopen action for never-opened project, got %+v", a)
+	}
+
+	proj.LastOpenedWith = "nvim"
+	a := ReopenAction(proj)
+	if a == nil || a.ID != "reopen" || a.Label != "Reopen in nvim" {
+		t.Fatalf("Unexpected reopen action: %+v", a)
+	}
+
+	actions := DefaultActions(proj, false, false)
+	if actions[0].ID != "reopen" {
+		t.Errorf("Expected reopen to be the first action, got %s", actions[0].ID)
+	}
+}
+
+func TestBootstrapAction(t *testing.T) {
+	proj := &project.Project{Name: "web", Path: "/tmp/web", LastOpenedWith: "nvim"}
+	if a := BootstrapAction(proj); a != nil {
+		t.Errorf("Expected no bootstrap action for a set-up project, got %+v", a)
+	}
+
+	proj.NeedsSetup = true
+	a := BootstrapAction(proj)
+	if a == nil || a.ID != "bootstrap" {
+		t.Fatalf("Unexpected bootstrap action: %+v", a)
+	}
+
+	actions := DefaultActions(proj, false, false)
+	if actions[0].ID != "bootstrap" || actions[1].ID != "reopen" {
+		t.Errorf("Expected bootstrap then reopen first, got %s, %s", actions[0].ID, actions[1].ID)
+	}
+}
+
+func TestDefaultActions_ComposePorts(t *testing.T) {
+	dir := t.TempDir()
+	compose := "services:\n  web:\n    ports:\n      - \"8080:80\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "compose.yml"), []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proj := &project.Project{Name: "web", Path: dir, HasCompose: true}
+	action := FindAction(DefaultActions(proj, false, false), "open-port-8080")
+	if action == nil || !strings.Contains(action.Label, "localhost:8080") {
+		t.Errorf("Expected an action opening localhost:8080, got %+v", action)
+	}
+}
+
+func TestDefaultActions_Databases(t *testing.T) {
+	dir := t.TempDir()
+	compose := "services:\n  db:\n    image: postgres:16\n  cache:\n    image: redis:7\n"
+	if err := os.WriteFile(filepath.Join(dir, "compose.yml"), []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proj := &project.Project{Name: "web", Path: dir, HasCompose: true}
+	actions := DefaultActions(proj, false, false)
+	if shell := FindAction(actions, "db-shell-db"); shell == nil || !strings.Contains(shell.Label, "psql") {
+		t.Errorf("Expected a psql shell for db, got %+v", shell)
+	}
+	if FindAction(actions, "db-restore-db") == nil {
+		t.Error("Expected Restore Latest Dump for Postgres")
+	}
+	if FindAction(actions, "db-dump-cache") == nil || FindAction(actions, "db-restore-cache") != nil {
+		t.Error("Expected Redis to be dumped but not restored")
+	}
+}
+
+func TestDefaultActions_Env(t *testing.T) {
+	dir := t.TempDir()
+	proj := &project.Project{Name: "web", Path: dir}
+	if FindAction(DefaultActions(proj, false, false), "submenu-env") != nil {
+		t.Error("Expected no .env Manager without .env files")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".env.example"), []byte("API_KEY=\nPORT=3000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if FindAction(DefaultActions(proj, false, false), "env-create") == nil {
+		t.Error("Expected Create .env while there's only an example")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("API_KEY=x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	actions := DefaultActions(proj, false, false)
+	if sync := FindAction(actions, "env-sync"); sync == nil || sync.Desc != "Add PORT from .env.example" {
+		t.Errorf("env-sync = %+v", sync)
+	}
+	if FindAction(actions, "env-create") != nil || FindAction(actions, "env-open-.env") == nil {
+		t.Error("Expected Edit .env instead of Create .env once it exists")
+	}
+}
+
+func TestDefaultActions_GoWork(t *testing.T) {
+	dir := t.TempDir()
+	proj := &project.Project{Name: "mono", Path: dir, Language: "Go"}
+	if FindAction(DefaultActions(proj, false, false), "submenu-gowork") != nil {
+		t.Error("Expected no Go Workspace without go.work")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.22\n\nuse (\n\t./api\n\t./web\n)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	actions := DefaultActions(proj, false, false)
+	work := FindAction(actions, "submenu-gowork")
+	if work == nil || len(work.Children) != 6 {
+		t.Fatalf("Expected list, sync, and build and test for 2 modules, got %+v", work)
+	}
+	if FindAction(actions, "gowork-test-./web") == nil {
+		t.Error("Expected Test ./web")
+	}
+}
+
+func TestDefaultActions_BuildTasks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	gradle := t.TempDir()
+	if err := os.WriteFile(filepath.Join(gradle, "build.gradle"), []byte("plugins { id 'java' }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	proj := &project.Project{Name: "app", Path: gradle, Language: "Java"}
+	tasks := FindAction(DefaultActions(proj, false, false), "submenu-gradle")
+	if tasks == nil || len(tasks.Children) != 1 || tasks.Children[0].ID != RefreshTasksID {
+		t.Fatalf("Expected only Load Tasks before the tasks are listed, got %+v", tasks)
+	}
+
+	if err := jvm.SaveTasks(gradle, []jvm.Task{{Name: "build", Group: "Build"}, {Name: "test", Group: "Verification"}}); err != nil {
+		t.Fatal(err)
+	}
+	actions := DefaultActions(proj, false, false)
+	if build := FindAction(actions, "gradle-build"); build == nil || build.Command != "gradle build" {
+		t.Errorf("Expected the cached build task, got %+v", build)
+	}
+	if FindAction(actions, "submenu-gradle-verification") == nil {
+		t.Error("Expected the tasks grouped as Gradle groups them")
+	}
+
+	maven := t.TempDir()
+	if err := os.WriteFile(filepath.Join(maven, "pom.xml"), []byte("<project></project>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	actions = DefaultActions(&project.Project{Name: "svc", Path: maven, Language: "Java"}, false, false)
+	if pkg := FindAction(actions, "maven-package"); pkg == nil || pkg.Command != "mvn package" {
+		t.Errorf("Expected the Maven package phase, got %+v", pkg)
+	}
+}
+
+func TestActionMenuSetActions(t *testing.T) {
+	proj := &project.Project{Name: "api", Path: t.TempDir()}
+	menu := NewActionMenuModel(proj, []Action{{ID: "open-editor"}, {ID: "cd"}, PluginsLoadingAction(), {ID: "back"}})
+	menu.list.Select(1)
+
+	menu.SetActions([]Action{{ID: "open-editor"}, {ID: "deploy"}, {ID: "cd"}, {ID: "back"}})
+	if selected := menu.SelectedAction(); selected == nil || selected.ID != "cd" {
+		t.Errorf("selected %+v after the plugin actions arrived, want cd", selected)
+	}
+	if len(menu.Actions()) != 4 {
+		t.Errorf("got %d actions, want 4", len(menu.Actions()))
+	}
+}
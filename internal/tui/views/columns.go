@@ -0,0 +1,167 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/language"
+	"github.com/s33g/proj/internal/project"
+)
+
+// Project list column names, as used in display.columns
+const (
+	ColumnName       = "name"
+	ColumnLanguage   = "language"
+	ColumnBranch     = "branch"
+	ColumnDocker     = "docker"
+	ColumnSize       = "size"
+	ColumnLastCommit = "lastCommit"
+)
+
+// columnGap separates columns in the project list
+const columnGap = "  "
+
+// Column is a project list column. Width 0 sizes the column to its content.
+type Column struct {
+	Name  string
+	Width int
+}
+
+// defaultWidths are used for columns configured without a width
+var defaultWidths = map[string]int{
+	ColumnName:       33,
+	ColumnLanguage:   13,
+	ColumnBranch:     22,
+	ColumnDocker:     2,
+	ColumnSize:       9,
+	ColumnLastCommit: 8,
+}
+
+// DefaultColumns is the layout used when display.columns is empty
+var DefaultColumns = []Column{
+	{Name: ColumnName, Width: 33},
+	{Name: ColumnLanguage, Width: 13},
+	{Name: ColumnBranch, Width: 22},
+	{Name: ColumnDocker, Width: 2},
+}
+
+// ParseColumns parses display.columns entries of the form "name" or
+// "name:width". An empty list returns DefaultColumns. The name column carries
+// the selection marker, so it is added first if missing.
+func ParseColumns(specs []string) ([]Column, error) {
+	if len(specs) == 0 {
+		return DefaultColumns, nil
+	}
+
+	columns := make([]Column, 0, len(specs)+1)
+	hasName := false
+	for _, spec := range specs {
+		name, width, hasWidth := strings.Cut(strings.TrimSpace(spec), ":")
+
+		canonical := ""
+		for known := range defaultWidths {
+			if strings.EqualFold(name, known) {
+				canonical = known
+			}
+		}
+		if canonical == "" {
+			return nil, fmt.Errorf("unknown column %q in display.columns (use name, language, branch, docker, size or lastCommit)", name)
+		}
+
+		hasName = hasName || canonical == ColumnName
+		col := Column{Name: canonical, Width: defaultWidths[canonical]}
+		if hasWidth {
+			w, err := strconv.Atoi(width)
+			if err != nil || w < 0 {
+				return nil, fmt.Errorf("invalid width %q for column %s", width, name)
+			}
+			col.Width = w
+		}
+		columns = append(columns, col)
+	}
+
+	if !hasName {
+		columns = append([]Column{{Name: ColumnName, Width: defaultWidths[ColumnName]}}, columns...)
+	}
+	return columns, nil
+}
+
+// renderCell renders a non-name column for a project, coloring its branch
+// by the theme's branch patterns
+func renderCell(col Column, p *project.Project, theme config.ThemeConfig, now time.Time) string {
+	switch col.Name {
+	case ColumnLanguage:
+		if p.Enriching {
+			return branchStyle.Render("…")
+		}
+		if p.Language == "" || p.Language == "Unknown" {
+			return ""
+		}
+		return langStyle.Render(fit(fmt.Sprintf("%s %s", language.GetIcon(p.Language), p.Language), col.Width))
+	case ColumnBranch:
+		if p.GitBranch == "" {
+			return ""
+		}
+		width := col.Width
+		if p.GitDirty && width > 0 {
+			width-- // Room for the dirty marker
+		}
+		cell := BranchStyle(theme, p.GitBranch).Render(fit(p.GitBranch, width))
+		if p.GitDirty {
+			cell += dirtyStyle.Render("*")
+		}
+		return cell
+	case ColumnDocker:
+		if p.HasCompose {
+			return "🐙"
+		} else if p.HasDockerfile {
+			return "🐳"
+		}
+		return ""
+	case ColumnSize:
+		if p.Size == 0 {
+			return ""
+		}
+		return branchStyle.Render(actions.FormatBytes(p.Size))
+	case ColumnLastCommit:
+		if p.LastCommit.IsZero() {
+			return ""
+		}
+		return branchStyle.Render(RelativeTime(p.LastCommit, now))
+	}
+	return ""
+}
+
+// BranchStyle returns the style of a branch name: the color of the theme
+// pattern it matches, or muted gray
+func BranchStyle(theme config.ThemeConfig, branch string) lipgloss.Style {
+	if color := theme.BranchColor(branch); color != "" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	}
+	return branchStyle
+}
+
+// fit truncates s to width display cells, marking the cut with "..."
+func fit(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+3 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// pad right-pads a rendered cell to width display cells
+func pad(cell string, width int) string {
+	if gap := width - lipgloss.Width(cell); gap > 0 {
+		return cell + strings.Repeat(" ", gap)
+	}
+	return cell
+}
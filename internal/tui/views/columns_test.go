@@ -0,0 +1,83 @@
+package views
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns(nil)
+	if err != nil || !reflect.DeepEqual(columns, DefaultColumns) {
+		t.Errorf("Expected default columns, got %v (%v)", columns, err)
+	}
+
+	columns, err = ParseColumns([]string{"name:20", "lastcommit", "size:6"})
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+	expected := []Column{{ColumnName, 20}, {ColumnLastCommit, 8}, {ColumnSize, 6}}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("ParseColumns() = %v, want %v", columns, expected)
+	}
+
+	// The name column is always present
+	columns, _ = ParseColumns([]string{"branch"})
+	if len(columns) != 2 || columns[0].Name != ColumnName {
+		t.Errorf("Expected name column to be added first, got %v", columns)
+	}
+
+	for _, bad := range []string{"stars", "name:wide", "branch:-1"} {
+		if _, err := ParseColumns([]string{bad}); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestRenderCell(t *testing.T) {
+	now := time.Now()
+	p := &project.Project{
+		Name:          "api",
+		GitBranch:     "feature/very-long-branch-name",
+		GitDirty:      true,
+		HasDockerfile: true,
+		Size:          2048,
+		LastCommit:    now.Add(-3 * time.Hour),
+	}
+
+	tests := []struct {
+		col      Column
+		expected string
+	}{
+		{Column{ColumnBranch, 12}, "feature/..."},
+		{Column{ColumnDocker, 2}, "🐳"},
+		{Column{ColumnSize, 9}, "2.0 KB"},
+		{Column{ColumnLastCommit, 8}, "3h ago"},
+	}
+
+	for _, tt := range tests {
+		if cell := renderCell(tt.col, p, config.ThemeConfig{}, now); !strings.Contains(cell, tt.expected) {
+			t.Errorf("renderCell(%s) = %q, expected to contain %q", tt.col.Name, cell, tt.expected)
+		}
+	}
+
+	if cell := renderCell(Column{ColumnSize, 9}, &project.Project{}, config.ThemeConfig{}, now); cell != "" {
+		t.Errorf("Expected empty size cell when size is unknown, got %q", cell)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("short", 10); got != "short" {
+		t.Errorf("fit() = %q, want unchanged", got)
+	}
+	if got := fit("a-very-long-name", 10); got != "a-very-..." {
+		t.Errorf("fit() = %q, want %q", got, "a-very-...")
+	}
+	if got := fit("unbounded", 0); got != "unbounded" {
+		t.Errorf("fit() with width 0 = %q, want unchanged", got)
+	}
+}
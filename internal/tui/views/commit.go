@@ -0,0 +1,260 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// Trailer is a Key: Value line that can be added to a commit message
+type Trailer struct {
+	Key   string // e.g. Signed-off-by or Co-authored-by
+	Value string // e.g. Name <email>
+	On    bool
+}
+
+// Parts of the commit prompt that take the keys, in tab order
+const (
+	commitSummary = iota
+	commitBody
+	commitTrailers
+)
+
+// CommitModel is the prompt for a commit message: a conventional commit
+// type, the summary line, a body started from the project's template, and
+// trailers to tick
+type CommitModel struct {
+	summary  Input
+	body     textarea.Model
+	types    []string
+	typ      int // Index into types plus one; 0 for no type
+	trailers []Trailer
+	cursor   int // Trailer the cursor is on
+	focus    int
+	all      bool // Nothing is staged, so every change to tracked files is committed
+	err      error
+}
+
+// NewCommitModel creates the commit prompt. template starts the body, and
+// history holds summaries entered before. all says that nothing is staged,
+// so the commit takes every change to tracked files.
+func NewCommitModel(types []string, template string, trailers []Trailer, history []string, all bool) CommitModel {
+	summary := NewInput(history)
+	summary.Placeholder = "Summarize the change"
+	summary.CharLimit = 100
+	summary.Width = 60
+	summary.Focus()
+
+	body := textarea.New()
+	body.Cursor.SetMode(tui.CursorMode())
+	body.Placeholder = "Why the change was made (optional)"
+	body.ShowLineNumbers = false
+	body.CharLimit = 0
+	body.SetWidth(72)
+	body.SetHeight(6)
+	body.SetValue(strings.TrimRight(template, "\n"))
+	body.Blur()
+
+	return CommitModel{summary: summary, body: body, types: types, trailers: trailers, all: all}
+}
+
+func (m CommitModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m CommitModel) Update(msg tea.Msg) (CommitModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		m.err = nil
+		switch key.String() {
+		case "tab":
+			return m.setFocus(m.focus + 1), nil
+		case "shift+tab":
+			return m.setFocus(m.focus - 1), nil
+		case "ctrl+t":
+			if len(m.types) > 0 {
+				m.typ = (m.typ + 1) % (len(m.types) + 1)
+			}
+			return m, nil
+		}
+
+		if m.focus == commitTrailers {
+			switch key.String() {
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case "down", "j":
+				if m.cursor < len(m.trailers)-1 {
+					m.cursor++
+				}
+			case " ", "x":
+				m.trailers[m.cursor].On = !m.trailers[m.cursor].On
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focus {
+	case commitSummary:
+		m.summary, cmd = m.summary.Update(msg)
+	case commitBody:
+		m.body, cmd = m.body.Update(msg)
+	}
+	return m, cmd
+}
+
+// setFocus moves the keys to part n, wrapping around and skipping the
+// trailers when there are none
+func (m CommitModel) setFocus(n int) CommitModel {
+	parts := commitTrailers + 1
+	if len(m.trailers) == 0 {
+		parts = commitTrailers
+	}
+	m.focus = (n + parts) % parts
+
+	m.summary.Blur()
+	m.body.Blur()
+	switch m.focus {
+	case commitSummary:
+		m.summary.Focus()
+	case commitBody:
+		m.body.Focus()
+	}
+	return m
+}
+
+// EditingBody reports whether the body has the keys, where enter starts a
+// new line rather than committing
+func (m CommitModel) EditingBody() bool {
+	return m.focus == commitBody
+}
+
+// SetError shows why the commit can't be made
+func (m *CommitModel) SetError(err error) {
+	m.err = err
+}
+
+// Summary returns the summary line as typed
+func (m CommitModel) Summary() string {
+	return m.summary.Value()
+}
+
+// All reports whether the commit takes every change to tracked files
+func (m CommitModel) All() bool {
+	return m.all
+}
+
+// Type returns the chosen conventional commit type, or ""
+func (m CommitModel) Type() string {
+	if m.typ == 0 {
+		return ""
+	}
+	return m.types[m.typ-1]
+}
+
+// Message returns the commit message, or an error if it has no summary
+func (m CommitModel) Message() (string, error) {
+	if strings.TrimSpace(m.Summary()) == "" {
+		return "", errors.New("enter a summary")
+	}
+	return CommitMessage(m.Type(), m.Summary(), m.body.Value(), m.trailers), nil
+}
+
+func (m CommitModel) View() string {
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+	label := func(part int, text string) string {
+		if m.focus == part {
+			return tui.SelectedStyle.Render(text)
+		}
+		return muted.Render(text)
+	}
+
+	typ := "(none)"
+	if m.Type() != "" {
+		typ = m.Type()
+	}
+	lines := []string{
+		tui.TitleStyle.Render("💬 Commit"),
+		"",
+		muted.Render("Type:    ") + tui.SubtitleStyle.Render(typ) + tui.HelpStyle.Render("  (ctrl+t)"),
+		label(commitSummary, "Summary: ") + m.summary.View(),
+		"",
+		label(commitBody, "Body:"),
+		m.body.View(),
+	}
+
+	if len(m.trailers) > 0 {
+		lines = append(lines, "", label(commitTrailers, "Trailers:"))
+		for i, t := range m.trailers {
+			check := "[ ]"
+			if t.On {
+				check = "[x]"
+			}
+			line := fmt.Sprintf("%s %s: %s", check, t.Key, t.Value)
+			if m.focus == commitTrailers && i == m.cursor {
+				lines = append(lines, tui.SelectedStyle.Render("▸ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+	}
+
+	what := "Commits the staged changes"
+	if m.all {
+		what = "Nothing is staged: commits every change to tracked files"
+	}
+	lines = append(lines, "", muted.Render(what))
+	if m.err != nil {
+		lines = append(lines, tui.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+
+	help := "enter: commit  •  tab: next field  •  ctrl+t: type  •  ↑/↓: history  •  esc: cancel"
+	switch m.focus {
+	case commitBody:
+		help = "ctrl+s: commit  •  enter: new line  •  tab: next field  •  esc: cancel"
+	case commitTrailers:
+		help = "enter: commit  •  ↑/↓: move  •  space: toggle  •  tab: next field  •  esc: cancel"
+	}
+	lines = append(lines, "", tui.HelpStyle.Render(help))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// CommitMessage composes a commit message from a conventional commit type
+// (or ""), the summary, a body whose # comment lines are dropped as git
+// would, and the trailers that are on
+func CommitMessage(typ, summary, body string, trailers []Trailer) string {
+	header := strings.TrimSpace(summary)
+	if typ != "" {
+		header = typ + ": " + header
+	}
+	parts := []string{header}
+
+	var kept []string
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, strings.TrimRight(line, " \t"))
+		}
+	}
+	if body := strings.Trim(strings.Join(kept, "\n"), "\n"); body != "" {
+		parts = append(parts, body)
+	}
+
+	var on []string
+	for _, t := range trailers {
+		if t.On {
+			on = append(on, t.Key+": "+t.Value)
+		}
+	}
+	if len(on) > 0 {
+		parts = append(parts, strings.Join(on, "\n"))
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
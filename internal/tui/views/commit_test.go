@@ -0,0 +1,66 @@
+package views
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCommitMessage(t *testing.T) {
+	trailers := []Trailer{
+		{Key: "Signed-off-by", Value: "Ann <ann@example.com>", On: true},
+		{Key: "Co-authored-by", Value: "Bob <bob@example.com>"},
+	}
+	tests := []struct {
+		typ, summary, body string
+		trailers           []Trailer
+		want               string
+	}{
+		{"", "Fix crash", "", nil, "Fix crash\n"},
+		{"fix", " crash on start ", "", nil, "fix: crash on start\n"},
+		{"feat", "Login", "# Why?\n\nUsers asked for it.  \n# Lines starting with # are dropped\n", nil, "feat: Login\n\nUsers asked for it.\n"},
+		{"", "Tidy", "", trailers, "Tidy\n\nSigned-off-by: Ann <ann@example.com>\n"},
+	}
+	for _, tt := range tests {
+		if got := CommitMessage(tt.typ, tt.summary, tt.body, tt.trailers); got != tt.want {
+			t.Errorf("CommitMessage(%q, %q, %q) = %q, want %q", tt.typ, tt.summary, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestCommitModel(t *testing.T) {
+	trailers := []Trailer{{Key: "Co-authored-by", Value: "Bob <bob@example.com>"}}
+	m := NewCommitModel([]string{"feat", "fix"}, "# Why?\n", trailers, nil, false)
+	if _, err := m.Message(); err == nil {
+		t.Error("Expected an error without a summary")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Add login")})
+	for range 2 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	}
+	if m.Type() != "fix" {
+		t.Errorf("Type() after two ctrl+t = %q, want fix", m.Type())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !m.EditingBody() {
+		t.Fatal("Expected tab to move to the body")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+
+	msg, err := m.Message()
+	if err != nil {
+		t.Fatalf("Message() failed: %v", err)
+	}
+	if want := "fix: Add login\n\nCo-authored-by: Bob <bob@example.com>\n"; msg != want {
+		t.Errorf("Message() = %q, want %q", msg, want)
+	}
+
+	// Tab wraps around to the summary
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.focus != commitSummary {
+		t.Errorf("Expected tab from the trailers to return to the summary, focus %d", m.focus)
+	}
+}
@@ -0,0 +1,325 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/results"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/toolchain"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// ProjectInfo is what the action menu found out about a project on opening
+type ProjectInfo struct {
+	Toolchains   []toolchain.Status
+	Activity     []int             // Commits per week, oldest first
+	Contributors []git.Contributor // Most commits first
+	Me           string            // The git user.name of whoever is running proj
+	Services     []docker.ComposeService
+}
+
+// TopContributors is how many contributors the action menu names
+const TopContributors = 3
+
+// TopServices is how many compose services the action menu lists
+const TopServices = 5
+
+// ProjectDetails renders the detail lines shown below the action header
+func ProjectDetails(p *project.Project, info ProjectInfo) string {
+	lines := []string{}
+
+	if warning := GitWarning(p); warning != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(tui.Warning).Bold(true).Render("⚠ "+capitalize(warning)))
+	}
+
+	if hint := LastOpenedHint(p, time.Now()); hint != "" {
+		lines = append(lines, tui.SubtitleStyle.Render(hint))
+	}
+
+	if line := ActivityLine(info.Activity); line != "" {
+		lines = append(lines, line)
+	}
+
+	if line := ContributorsLine(info.Contributors, info.Me); line != "" {
+		lines = append(lines, line)
+	}
+
+	for _, t := range info.Toolchains {
+		lines = append(lines, ToolchainLine(t))
+	}
+
+	lines = append(lines, ServiceLines(info.Services)...)
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// GitWarning describes a repository state worth knowing before opening the
+// project: an operation left in progress or a detached HEAD
+func GitWarning(p *project.Project) string {
+	switch {
+	case p.GitOperation != "":
+		return p.GitOperation + " in progress"
+	case p.GitDetached:
+		return "detached HEAD"
+	}
+	return ""
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// ToolchainLine renders a required toolchain version, warning when the
+// installed version differs or the tool is missing
+func ToolchainLine(t toolchain.Status) string {
+	required := t.Version
+	if t.Minimum {
+		required = ">= " + required
+	}
+	line := fmt.Sprintf("%s %s (%s)", t.Tool, required, t.Source)
+
+	if t.OK {
+		return tui.SubtitleStyle.Render("🔧 " + line)
+	}
+
+	installed := "not installed"
+	if t.Installed != "" {
+		installed = t.Installed + " installed"
+	}
+	return lipgloss.NewStyle().Foreground(tui.Warning).Render(fmt.Sprintf("⚠ %s — %s", line, installed))
+}
+
+// sparkBlocks are the bar heights of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders counts as one bar each, the largest the full height.
+// Empty counts stay at the baseline, so any activity shows above it.
+func Sparkline(counts []int) string {
+	max := 0
+	for _, n := range counts {
+		if n > max {
+			max = n
+		}
+	}
+
+	bars := make([]rune, len(counts))
+	for i, n := range counts {
+		level := 0
+		if n > 0 {
+			level = 1 + n*(len(sparkBlocks)-2)/max
+		}
+		bars[i] = sparkBlocks[level]
+	}
+	return string(bars)
+}
+
+// ActivityLine renders weekly commit counts as a sparkline with the total,
+// muted for a dormant project
+func ActivityLine(weeks []int) string {
+	if len(weeks) == 0 {
+		return ""
+	}
+	total := 0
+	for _, n := range weeks {
+		total += n
+	}
+
+	if total == 0 {
+		return lipgloss.NewStyle().Foreground(tui.Muted).Render(fmt.Sprintf("%s  No commits in %d weeks", Sparkline(weeks), len(weeks)))
+	}
+	commits := "commits"
+	if total == 1 {
+		commits = "commit"
+	}
+	return lipgloss.NewStyle().Foreground(tui.Accent).Render(Sparkline(weeks)) +
+		tui.SubtitleStyle.Render(fmt.Sprintf("  %d %s in %d weeks", total, commits, len(weeks)))
+}
+
+// ContributorsLine names a project's top contributors with their commits,
+// followed by the share of commits that are me's
+func ContributorsLine(contributors []git.Contributor, me string) string {
+	if len(contributors) == 0 {
+		return ""
+	}
+
+	total, mine := 0, 0
+	for _, c := range contributors {
+		total += c.Commits
+		if me != "" && strings.EqualFold(c.Name, me) {
+			mine += c.Commits
+		}
+	}
+
+	names := make([]string, 0, TopContributors)
+	for i, c := range contributors {
+		if i == TopContributors {
+			names = append(names, fmt.Sprintf("+%d more", len(contributors)-i))
+			break
+		}
+		names = append(names, fmt.Sprintf("%s %d", c.Name, c.Commits))
+	}
+	line := "Contributors: " + strings.Join(names, " · ")
+	if me != "" {
+		line += fmt.Sprintf("  (you: %d%%)", mine*100/total)
+	}
+	return tui.SubtitleStyle.Render(line)
+}
+
+// ServiceLines gives an overview of a project's compose services: what each
+// runs, the ports it publishes, the volumes it mounts and its profiles
+func ServiceLines(services []docker.ComposeService) []string {
+	lines := make([]string, 0, TopServices+1)
+	for i, svc := range services {
+		if i == TopServices {
+			lines = append(lines, tui.SubtitleStyle.Render(fmt.Sprintf("   +%d more services", len(services)-i)))
+			break
+		}
+
+		parts := []string{svc.Name}
+		switch {
+		case svc.Image != "":
+			parts = append(parts, svc.Image)
+		case svc.Build:
+			parts = append(parts, "(build)")
+		}
+		for _, port := range svc.Ports {
+			if port.Published > 0 {
+				parts = append(parts, fmt.Sprintf(":%d", port.Published))
+			}
+		}
+		for _, volume := range svc.Volumes {
+			if volume.Source != "" {
+				parts = append(parts, "⛁ "+volume.Source)
+			}
+		}
+		if len(svc.Profiles) > 0 {
+			parts = append(parts, "["+strings.Join(svc.Profiles, ", ")+"]")
+		}
+		lines = append(lines, tui.SubtitleStyle.Render("🐙 "+strings.Join(parts, "  ")))
+	}
+	return lines
+}
+
+// LastOpenedHint returns a "last opened in nvim 2h ago" hint for a project
+func LastOpenedHint(p *project.Project, now time.Time) string {
+	if p.LastOpenedWith == "" || p.LastOpenedAt.IsZero() {
+		return ""
+	}
+
+	where := p.LastOpenedWith
+	if where == state.OpenedInTerminal {
+		where = "the terminal"
+	}
+
+	return fmt.Sprintf("Last opened in %s %s", where, RelativeTime(p.LastOpenedAt, now))
+}
+
+// RelativeTime formats t relative to now (e.g. "just now", "2h ago", "3d ago")
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dw ago", int(d.Hours()/(24*7)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/(24*365)))
+	}
+}
+
+// ProgressBar renders a text progress bar of the given width
+func ProgressBar(done, total int64, width int) string {
+	if total <= 0 {
+		return "[" + strings.Repeat("░", width) + "]"
+	}
+	if done > total {
+		done = total
+	}
+
+	filled := int(int64(width) * done / total)
+	percent := done * 100 / total
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), percent)
+}
+
+// ScanIssuesHint renders the "Scan Issues (N)" hint shown in the header
+func ScanIssuesHint(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(tui.Warning).Render(fmt.Sprintf("⚠ Scan Issues (%d) — press ! to view", count))
+}
+
+// ScanIssues renders the list of entries skipped during the scan together
+// with a suggested fix for each
+func ScanIssues(diagnostics []project.Diagnostic) string {
+	if len(diagnostics) == 0 {
+		return "No scan issues."
+	}
+
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+
+	blocks := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		lines := []string{"• " + d.Message}
+		if d.Suggestion != "" {
+			lines = append(lines, muted.Render("  Fix: "+d.Suggestion))
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// ProblemListRows is the number of problems shown at once in the result view
+const ProblemListRows = 5
+
+// ProblemList renders the file locations found in an action's output as a
+// selectable list, scrolled to keep the selection visible
+func ProblemList(locations []results.Location, root string, selected, width int) string {
+	if len(locations) == 0 {
+		return ""
+	}
+
+	start := 0
+	if selected >= ProblemListRows {
+		start = selected - ProblemListRows + 1
+	}
+	end := start + ProblemListRows
+	if end > len(locations) {
+		end = len(locations)
+	}
+
+	lines := []string{lipgloss.NewStyle().Foreground(tui.Warning).Render(fmt.Sprintf("Problems (%d)", len(locations)))}
+	for i := start; i < end; i++ {
+		line := locations[i].String(root)
+		if runes := []rune(line); width > 10 && len(runes) > width-4 {
+			line = string(runes[:width-7]) + "..."
+		}
+		if i == selected {
+			lines = append(lines, tui.SelectedStyle.Render("▸ "+line))
+		} else {
+			lines = append(lines, tui.NormalStyle.Render("  "+line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
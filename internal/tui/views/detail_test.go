@@ -0,0 +1,240 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/results"
+	"github.com/s33g/proj/internal/toolchain"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		ago      time.Duration
+		expected string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{2 * time.Hour, "2h ago"},
+		{3 * 24 * time.Hour, "3d ago"},
+		{3 * 7 * 24 * time.Hour, "3w ago"},
+		{2 * 365 * 24 * time.Hour, "2y ago"},
+	}
+
+	for _, tt := range tests {
+		if got := RelativeTime(now.Add(-tt.ago), now); got != tt.expected {
+			t.Errorf("RelativeTime(-%v) = %q, want %q", tt.ago, got, tt.expected)
+		}
+	}
+}
+
+func TestLastOpenedHint(t *testing.T) {
+	now := time.Now()
+
+	p := &project.Project{}
+	if hint := LastOpenedHint(p, now); hint != "" {
+		t.Errorf("Expected no hint for never-opened project, got %q", hint)
+	}
+
+	p.LastOpenedWith = "nvim"
+	p.LastOpenedAt = now.Add(-2 * time.Hour)
+	if hint := LastOpenedHint(p, now); hint != "Last opened in nvim 2h ago" {
+		t.Errorf("Unexpected hint: %q", hint)
+	}
+
+	p.LastOpenedWith = "terminal"
+	if hint := LastOpenedHint(p, now); hint != "Last opened in the terminal 2h ago" {
+		t.Errorf("Unexpected hint: %q", hint)
+	}
+}
+
+func TestGitWarning(t *testing.T) {
+	tests := []struct {
+		p        project.Project
+		expected string
+	}{
+		{project.Project{GitBranch: "main"}, ""},
+		{project.Project{GitBranch: "HEAD", GitDetached: true}, "detached HEAD"},
+		{project.Project{GitBranch: "HEAD", GitDetached: true, GitOperation: "rebase"}, "rebase in progress"},
+		{project.Project{GitBranch: "main", GitOperation: "merge"}, "merge in progress"},
+	}
+
+	for _, tt := range tests {
+		if got := GitWarning(&tt.p); got != tt.expected {
+			t.Errorf("GitWarning(%+v) = %q, want %q", tt.p, got, tt.expected)
+		}
+	}
+
+	p := &project.Project{GitOperation: "rebase"}
+	if details := ProjectDetails(p, ProjectInfo{}); !strings.Contains(details, "Rebase in progress") {
+		t.Errorf("ProjectDetails should warn about the rebase, got %q", details)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		counts   []int
+		expected string
+	}{
+		{[]int{0, 0, 0}, "▁▁▁"},
+		{[]int{0, 1, 7, 14}, "▁▂▅█"},
+		{[]int{3, 3}, "██"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.counts); got != tt.expected {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.counts, got, tt.expected)
+		}
+	}
+
+	if line := ActivityLine(nil); line != "" {
+		t.Errorf("Expected no activity line without git history, got %q", line)
+	}
+	if line := ActivityLine([]int{0, 0}); !strings.Contains(line, "No commits in 2 weeks") {
+		t.Errorf("Expected a dormant project to say so, got %q", line)
+	}
+	if line := ActivityLine([]int{1, 0, 2}); !strings.Contains(line, "3 commits in 3 weeks") {
+		t.Errorf("Expected the total in the activity line, got %q", line)
+	}
+}
+
+func TestContributorsLine(t *testing.T) {
+	if line := ContributorsLine(nil, "Ann"); line != "" {
+		t.Errorf("Expected no line without contributors, got %q", line)
+	}
+
+	contributors := []git.Contributor{{Name: "Bob", Commits: 10}, {Name: "Ann", Commits: 6}, {Name: "Cy", Commits: 2}, {Name: "Di", Commits: 1}, {Name: "Ed", Commits: 1}}
+	line := ContributorsLine(contributors, "ann")
+	if !strings.Contains(line, "Bob 10 · Ann 6 · Cy 2 · +2 more") {
+		t.Errorf("Expected the top contributors, got %q", line)
+	}
+	if !strings.Contains(line, "you: 30%") {
+		t.Errorf("Expected my share, got %q", line)
+	}
+	if line := ContributorsLine(contributors, ""); strings.Contains(line, "you") {
+		t.Errorf("Expected no share without a git user, got %q", line)
+	}
+}
+
+func TestScanIssues(t *testing.T) {
+	if hint := ScanIssuesHint(0); hint != "" {
+		t.Errorf("Expected no hint without issues, got %q", hint)
+	}
+	if hint := ScanIssuesHint(3); !strings.Contains(hint, "Scan Issues (3)") {
+		t.Errorf("Expected count in hint, got %q", hint)
+	}
+
+	diagnostics := []project.Diagnostic{
+		{Message: "broken symlink: a → /missing", Suggestion: "Remove the link"},
+		{Message: "permission denied: /code/secret"},
+	}
+
+	out := ScanIssues(diagnostics)
+	if !strings.Contains(out, "broken symlink: a") || !strings.Contains(out, "permission denied") {
+		t.Errorf("Expected all issues listed, got %q", out)
+	}
+	if !strings.Contains(out, "Fix: Remove the link") {
+		t.Errorf("Expected suggested fix, got %q", out)
+	}
+}
+
+func TestToolchainLine(t *testing.T) {
+	tests := []struct {
+		status   toolchain.Status
+		contains []string
+	}{
+		{
+			toolchain.Status{Requirement: toolchain.Requirement{Tool: "go", Version: "1.22", Source: "go.mod", Minimum: true}, Installed: "1.23.1", OK: true},
+			[]string{"go >= 1.22 (go.mod)"},
+		},
+		{
+			toolchain.Status{Requirement: toolchain.Requirement{Tool: "node", Version: "20", Source: ".nvmrc"}, Installed: "18.19.0"},
+			[]string{"⚠", "node 20 (.nvmrc)", "18.19.0 installed"},
+		},
+		{
+			toolchain.Status{Requirement: toolchain.Requirement{Tool: "ruby", Version: "3.2.2", Source: ".tool-versions"}},
+			[]string{"⚠", "not installed"},
+		},
+	}
+
+	for _, tt := range tests {
+		line := ToolchainLine(tt.status)
+		for _, want := range tt.contains {
+			if !strings.Contains(line, want) {
+				t.Errorf("ToolchainLine(%s) = %q, expected to contain %q", tt.status.Tool, line, want)
+			}
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		done, total int64
+		expected    string
+	}{
+		{0, 100, "[░░░░░░░░░░]   0%"},
+		{50, 100, "[█████░░░░░]  50%"},
+		{100, 100, "[██████████] 100%"},
+		{150, 100, "[██████████] 100%"},
+		{0, 0, "[░░░░░░░░░░]"},
+	}
+
+	for _, tt := range tests {
+		if got := ProgressBar(tt.done, tt.total, 10); got != tt.expected {
+			t.Errorf("ProgressBar(%d, %d) = %q, want %q", tt.done, tt.total, got, tt.expected)
+		}
+	}
+}
+
+func TestProblemList(t *testing.T) {
+	if out := ProblemList(nil, "/p", 0, 80); out != "" {
+		t.Errorf("Expected nothing without problems, got %q", out)
+	}
+
+	var locations []results.Location
+	for i := 1; i <= 8; i++ {
+		locations = append(locations, results.Location{File: "/p/main.go", Line: i})
+	}
+
+	out := ProblemList(locations, "/p", 6, 80)
+	if !strings.Contains(out, "Problems (8)") {
+		t.Errorf("Expected problem count, got %q", out)
+	}
+	if !strings.Contains(out, "▸ main.go:7") {
+		t.Errorf("Expected selection to be visible, got %q", out)
+	}
+	if strings.Contains(out, "main.go:1\n") {
+		t.Errorf("Expected list to scroll past the first problem, got %q", out)
+	}
+}
+
+func TestServiceLines(t *testing.T) {
+	services := []docker.ComposeService{
+		{Name: "web", Build: true, Ports: []docker.PortMapping{{Published: 8080, Target: 80}, {Target: 9229}}},
+		{Name: "db", Image: "postgres:16", Volumes: []docker.VolumeMount{{Source: "pgdata", Target: "/data"}, {Target: "/tmp"}}, Profiles: []string{"db"}},
+	}
+	lines := ServiceLines(services)
+	if len(lines) != 2 {
+		t.Fatalf("ServiceLines() = %q", lines)
+	}
+	if !strings.Contains(lines[0], "web  (build)  :8080") || strings.Contains(lines[0], "9229") {
+		t.Errorf("web line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "db  postgres:16  ⛁ pgdata  [db]") {
+		t.Errorf("db line = %q", lines[1])
+	}
+
+	for i := 0; i < TopServices; i++ {
+		services = append(services, docker.ComposeService{Name: "worker"})
+	}
+	lines = ServiceLines(services)
+	if len(lines) != TopServices+1 || !strings.Contains(lines[TopServices], "+2 more services") {
+		t.Errorf("ServiceLines() with %d services = %q", len(services), lines)
+	}
+}
@@ -0,0 +1,181 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/docker"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// DockerBuildModel is the prompt before building an image: the stage of a
+// multi-stage Dockerfile to build (←/→) and a value for each ARG, starting
+// from its default
+type DockerBuildModel struct {
+	dockerfile string
+	stages     []string // "" (the last stage) followed by the named stages
+	stage      int
+	args       []docker.BuildArg
+	inputs     []Input
+	focus      int // 0 for the target with several stages, else an input
+}
+
+// NewDockerBuildModel creates the prompt for building from dockerfile
+func NewDockerBuildModel(dockerfile string, df docker.Dockerfile) DockerBuildModel {
+	m := DockerBuildModel{dockerfile: dockerfile, args: df.Args}
+	if len(df.Stages) > 1 {
+		m.stages = append([]string{""}, df.Stages...)
+	}
+	for _, arg := range df.Args {
+		input := NewInput(nil)
+		input.Prompt = ""
+		input.Width = 40
+		input.SetValue(arg.Default)
+		m.inputs = append(m.inputs, input)
+	}
+	m.focusField(0)
+	return m
+}
+
+// NeedsPrompt reports whether a Dockerfile has anything to ask about
+func NeedsPrompt(df docker.Dockerfile) bool {
+	return len(df.Args) > 0 || len(df.Stages) > 1
+}
+
+func (m DockerBuildModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m DockerBuildModel) Update(msg tea.Msg) (DockerBuildModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "tab", "down":
+			m.focusField(m.focus + 1)
+			return m, nil
+		case "shift+tab", "up":
+			m.focusField(m.focus - 1)
+			return m, nil
+		}
+		if m.onTarget() {
+			switch key.String() {
+			case "right", "l", " ":
+				m.stage = (m.stage + 1) % len(m.stages)
+			case "left", "h":
+				m.stage = (m.stage - 1 + len(m.stages)) % len(m.stages)
+			}
+			return m, nil
+		}
+	}
+
+	if len(m.inputs) == 0 {
+		return m, nil
+	}
+	i := m.inputIndex()
+	var cmd tea.Cmd
+	m.inputs[i], cmd = m.inputs[i].Update(msg)
+	return m, cmd
+}
+
+// fields returns how many fields the prompt has
+func (m DockerBuildModel) fields() int {
+	n := len(m.inputs)
+	if len(m.stages) > 0 {
+		n++
+	}
+	return n
+}
+
+// onTarget reports whether the target picker has the focus
+func (m DockerBuildModel) onTarget() bool {
+	return len(m.stages) > 0 && m.focus == 0
+}
+
+// inputIndex returns the index of the focused input
+func (m DockerBuildModel) inputIndex() int {
+	if len(m.stages) > 0 {
+		return m.focus - 1
+	}
+	return m.focus
+}
+
+// focusField moves the focus to field n, wrapping around
+func (m *DockerBuildModel) focusField(n int) {
+	if m.fields() == 0 {
+		return
+	}
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	m.focus = (n + m.fields()) % m.fields()
+	if !m.onTarget() {
+		m.inputs[m.inputIndex()].Focus()
+	}
+}
+
+// Options returns the chosen target and the build args whose values differ
+// from the Dockerfile's defaults
+func (m DockerBuildModel) Options() docker.BuildOptions {
+	var opts docker.BuildOptions
+	if len(m.stages) > 0 {
+		opts.Target = m.stages[m.stage]
+	}
+	for i, arg := range m.args {
+		if value := m.inputs[i].Value(); value != arg.Default {
+			if opts.Args == nil {
+				opts.Args = make(map[string]string)
+			}
+			opts.Args[arg.Name] = value
+		}
+	}
+	return opts
+}
+
+func (m DockerBuildModel) View() string {
+	title := tui.TitleStyle.Render("🏗️  Build Image")
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+
+	width := len("Target")
+	for _, arg := range m.args {
+		width = max(width, len(arg.Name))
+	}
+	row := func(focused bool, label, value string) string {
+		label = fmt.Sprintf("%-*s", width, label)
+		if focused {
+			return tui.SelectedStyle.Render("> "+label) + "  " + value
+		}
+		return "  " + label + "  " + value
+	}
+
+	var lines []string
+	if len(m.stages) > 0 {
+		target := m.stages[m.stage]
+		if target == "" {
+			target = "(last stage)"
+		}
+		lines = append(lines, row(m.onTarget(), "Target", "◂ "+tui.SubtitleStyle.Render(target)+" ▸"))
+	}
+	for i, arg := range m.args {
+		lines = append(lines, row(!m.onTarget() && i == m.inputIndex(), arg.Name, m.inputs[i].View()))
+	}
+
+	content := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+
+	help := tui.HelpStyle.Render("tab/↑/↓: next field  •  ←/→: change target  •  enter: build  •  esc: cancel")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		"",
+		muted.Render("Build args and target for "+m.dockerfile+":"),
+		content,
+		"",
+		help,
+	)
+}
@@ -0,0 +1,37 @@
+package views
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/docker"
+)
+
+func TestDockerBuildModel(t *testing.T) {
+	df := docker.Dockerfile{
+		Args:   []docker.BuildArg{{Name: "GO_VERSION", Default: "1.24"}, {Name: "VERSION"}},
+		Stages: []string{"build", "runtime"},
+	}
+	if !NeedsPrompt(df) || NeedsPrompt(docker.Dockerfile{Stages: []string{"build"}}) {
+		t.Error("Expected a prompt only for args or several stages")
+	}
+
+	m := NewDockerBuildModel("Dockerfile", df)
+	if opts := m.Options(); opts.Target != "" || opts.Args != nil {
+		t.Errorf("Options() before any changes = %+v", opts)
+	}
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRight},                   // Target: build
+		{Type: tea.KeyTab}, {Type: tea.KeyTab}, // VERSION
+		{Type: tea.KeyRunes, Runes: []rune("1.2")}, // Typed into VERSION
+	} {
+		m, _ = m.Update(msg)
+	}
+
+	want := docker.BuildOptions{Target: "build", Args: map[string]string{"VERSION": "1.2"}}
+	if opts := m.Options(); !reflect.DeepEqual(opts, want) {
+		t.Errorf("Options() = %+v, want %+v", opts, want)
+	}
+}
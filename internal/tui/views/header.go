@@ -2,15 +2,81 @@ package views
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/project"
 	"github.com/s33g/proj/internal/tui"
 )
 
-// Header renders the application header
-func Header(reposPath string, projectCount int) string {
+// HeaderStatsLine is the line of the header showing the statistics
+// (title, its margin, the path, then the statistics)
+const HeaderStatsLine = 3
+
+// statSeparator separates the statistics in the header
+const statSeparator = "  •  "
+
+// HeaderStat is a statistic shown in the header. Clicking it applies Query
+// as a filter; the project count has an empty Query and clears the filter.
+type HeaderStat struct {
+	Label string
+	Query string
+}
+
+// HeaderStats lists the statistics shown in the header
+func HeaderStats(stats project.Stats) []HeaderStat {
+	items := []HeaderStat{{Label: fmt.Sprintf("Projects: %d", stats.Total)}}
+	if stats.Dirty > 0 {
+		items = append(items, HeaderStat{Label: fmt.Sprintf("%d dirty", stats.Dirty), Query: "is:dirty"})
+	}
+	if stats.Docker > 0 {
+		items = append(items, HeaderStat{Label: fmt.Sprintf("%d docker", stats.Docker), Query: "is:docker"})
+	}
+	for _, lang := range stats.TopLanguages(3) {
+		items = append(items, HeaderStat{
+			Label: fmt.Sprintf("%s %d", lang.Language, lang.Count),
+			Query: project.LanguageQuery(lang.Language),
+		})
+	}
+	return items
+}
+
+// HeaderStatAt returns the statistic rendered at column x of the statistics line
+func HeaderStatAt(stats project.Stats, x int) (HeaderStat, bool) {
+	start := 0
+	for _, item := range HeaderStats(stats) {
+		end := start + lipgloss.Width(item.Label)
+		if x >= start && x < end {
+			return item, true
+		}
+		start = end + lipgloss.Width(statSeparator)
+	}
+	return HeaderStat{}, false
+}
+
+// Header renders the application header. The statistic matching the active
+// filter query is highlighted.
+func Header(reposPath string, stats project.Stats, active string) string {
 	title := tui.TitleStyle.Render("📂 proj - Project Navigator")
-	subtitle := tui.SubtitleStyle.Render(fmt.Sprintf("Path: %s  •  Projects: %d", reposPath, projectCount))
+
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+	highlight := lipgloss.NewStyle().Foreground(tui.Primary).Bold(true).Underline(true)
+	items := HeaderStats(stats)
+	labels := make([]string, len(items))
+	for i, item := range items {
+		if active != "" && item.Query == active {
+			labels[i] = highlight.Render(item.Label)
+		} else {
+			labels[i] = muted.Render(item.Label)
+		}
+	}
+
+	subtitle := lipgloss.NewStyle().MarginBottom(1).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		muted.Render(fmt.Sprintf("Path: %s", reposPath)),
+		strings.Join(labels, muted.Render(statSeparator)),
+	))
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle)
 }
@@ -29,6 +95,9 @@ func ActionHeader(projectName, language string, gitBranch string, gitDirty bool)
 		if gitDirty {
 			badges += tui.DirtyBadgeStyle.Render(" * ")
 		}
+		if key := git.IssueKey(gitBranch); key != "" {
+			badges += tui.LanguageBadgeStyle.Render("🎫 " + key)
+		}
 	}
 
 	if badges != "" {
@@ -0,0 +1,37 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestHeaderStatAt(t *testing.T) {
+	stats := project.Stats{
+		Total:     12,
+		Dirty:     3,
+		Languages: []project.LanguageCount{{Language: "Go", Count: 7}},
+	}
+
+	// Projects: 12  •  3 dirty  •  Go 7
+	tests := []struct {
+		x     int
+		query string
+		ok    bool
+	}{
+		{0, "", true},
+		{11, "", true},
+		{13, "", false},
+		{17, "is:dirty", true},
+		{23, "is:dirty", true},
+		{30, "lang:go", true},
+		{40, "", false},
+	}
+
+	for _, tt := range tests {
+		stat, ok := HeaderStatAt(stats, tt.x)
+		if ok != tt.ok || stat.Query != tt.query {
+			t.Errorf("HeaderStatAt(%d) = %q, %v; want %q, %v", tt.x, stat.Query, ok, tt.query, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// Input is a single-line text input with readline-style editing: the word
+// and line keys of textinput (alt+b/alt+f, ctrl+w, alt+d, ctrl+a/ctrl+e,
+// ctrl+u/ctrl+k), up/down (or ctrl+p/ctrl+n) to recall earlier entries, and
+// pasted text cleaned of line breaks.
+type Input struct {
+	textinput.Model
+	history []string // Earlier entries, most recent first
+	recall  int      // Index into history being shown, -1 while editing a new entry
+	draft   string   // What was typed before recalling history
+}
+
+// NewInput creates an input recalling history, most recent first
+func NewInput(history []string) Input {
+	ti := textinput.New()
+	ti.Cursor.SetMode(tui.CursorMode())
+	return Input{Model: ti, history: history, recall: -1}
+}
+
+// Update handles history recall and paste, passing everything else on to
+// the text input
+func (i Input) Update(msg tea.Msg) (Input, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case keyMsg.Paste:
+			// Terminals paste a copied line with its newline; don't turn it into a space
+			keyMsg.Runes = []rune(cleanPaste(string(keyMsg.Runes)))
+			msg = keyMsg
+		case keyMsg.String() == "up" || keyMsg.String() == "ctrl+p":
+			i.recallEntry(i.recall + 1)
+			return i, nil
+		case keyMsg.String() == "down" || keyMsg.String() == "ctrl+n":
+			i.recallEntry(i.recall - 1)
+			return i, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	i.Model, cmd = i.Model.Update(msg)
+	return i, cmd
+}
+
+// recallEntry shows history entry n, or the draft for -1
+func (i *Input) recallEntry(n int) {
+	if n < -1 || n >= len(i.history) || n == i.recall {
+		return
+	}
+	if i.recall == -1 {
+		i.draft = i.Value()
+	}
+	i.recall = n
+	if n == -1 {
+		i.SetValue(i.draft)
+	} else {
+		i.SetValue(i.history[n])
+	}
+	i.CursorEnd()
+}
+
+// cleanPaste trims pasted text and joins its lines with spaces
+func cleanPaste(s string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
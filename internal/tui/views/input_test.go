@@ -0,0 +1,61 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/s33g/proj/internal/tui"
+)
+
+func TestInputHistory(t *testing.T) {
+	in := NewInput([]string{"go test ./...", "go vet ./..."})
+	in.Focus()
+	in.SetValue("make")
+
+	steps := []struct {
+		key  tea.KeyType
+		want string
+	}{
+		{tea.KeyUp, "go test ./..."},
+		{tea.KeyUp, "go vet ./..."},
+		{tea.KeyUp, "go vet ./..."}, // Stays on the oldest entry
+		{tea.KeyDown, "go test ./..."},
+		{tea.KeyDown, "make"}, // Back to what was typed
+		{tea.KeyDown, "make"},
+	}
+	for i, step := range steps {
+		in, _ = in.Update(tea.KeyMsg{Type: step.key})
+		if in.Value() != step.want {
+			t.Errorf("step %d: Value() = %q, want %q", i, in.Value(), step.want)
+		}
+	}
+}
+
+func TestInputPaste(t *testing.T) {
+	in := NewInput(nil)
+	in.Focus()
+
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("  git@github.com:s33g/proj.git\r\n"), Paste: true})
+	if in.Value() != "git@github.com:s33g/proj.git" {
+		t.Errorf("Value() = %q, want the URL without whitespace", in.Value())
+	}
+
+	in.SetValue("")
+	in, _ = in.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("go vet ./...\n  go test  ./...\n"), Paste: true})
+	if in.Value() != "go vet ./... go test  ./..." {
+		t.Errorf("Value() = %q, want the lines joined", in.Value())
+	}
+}
+
+func TestInputReducedMotion(t *testing.T) {
+	if mode := NewInput(nil).Cursor.Mode(); mode != cursor.CursorBlink {
+		t.Errorf("cursor mode = %v, want blinking", mode)
+	}
+
+	tui.ReducedMotion = true
+	defer func() { tui.ReducedMotion = false }()
+	if mode := NewInput(nil).Cursor.Mode(); mode != cursor.CursorStatic {
+		t.Errorf("cursor mode with reduced motion = %v, want static", mode)
+	}
+}
@@ -0,0 +1,150 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/git"
+	"github.com/s33g/proj/internal/tui"
+)
+
+// NewBranchModel is the prompt for a branch named from a pattern such as
+// {type}/{ticket}-{slug}: tab picks the type and the description gives the
+// slug, starting with the ticket if it names one
+type NewBranchModel struct {
+	textInput Input
+	pattern   string
+	types     []string
+	typ       int
+	err       error
+}
+
+// NewNewBranchModel creates the prompt for a branch named by pattern, with
+// the types to choose from; history holds descriptions entered before
+func NewNewBranchModel(pattern string, types []string, history []string) NewBranchModel {
+	ti := NewInput(history)
+	ti.Placeholder = "ABC-123 add login page"
+	ti.Focus()
+	ti.CharLimit = 100
+	ti.Width = 50
+
+	return NewBranchModel{textInput: ti, pattern: pattern, types: types}
+}
+
+func (m NewBranchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m NewBranchModel) Update(msg tea.Msg) (NewBranchModel, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && len(m.types) > 1 {
+		switch key.String() {
+		case "tab":
+			m.typ = (m.typ + 1) % len(m.types)
+			return m, nil
+		case "shift+tab":
+			m.typ = (m.typ - 1 + len(m.types)) % len(m.types)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.err = nil
+	return m, cmd
+}
+
+// SetError shows why the branch can't be created
+func (m *NewBranchModel) SetError(err error) {
+	m.err = err
+}
+
+// Value returns the description as typed
+func (m NewBranchModel) Value() string {
+	return m.textInput.Value()
+}
+
+// Type returns the chosen type of change
+func (m NewBranchModel) Type() string {
+	if len(m.types) == 0 {
+		return ""
+	}
+	return m.types[m.typ]
+}
+
+// Branch returns the name of the branch to create, or "" until there's a
+// description
+func (m NewBranchModel) Branch() string {
+	ticket, description := splitTicket(m.Value())
+	if description == "" && ticket == "" {
+		return ""
+	}
+	return BranchName(m.pattern, m.Type(), ticket, description)
+}
+
+func (m NewBranchModel) View() string {
+	title := tui.TitleStyle.Render("🌱 New Branch")
+	muted := lipgloss.NewStyle().Foreground(tui.Muted)
+	prompt := muted.Render("Describe the change (start with an issue key like ABC-123 or #456 to include it):")
+
+	details := ""
+	if len(m.types) > 0 {
+		details = "\n" + muted.Render("Type:   ") + tui.SubtitleStyle.Render(m.Type())
+		if len(m.types) > 1 {
+			details += tui.HelpStyle.Render("  (tab)")
+		}
+	}
+	if branch := m.Branch(); branch != "" {
+		details += "\n" + muted.Render("Branch: ") + tui.SubtitleStyle.Render(branch)
+	}
+
+	errMsg := ""
+	if m.err != nil {
+		errMsg = "\n" + tui.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	}
+
+	help := tui.HelpStyle.Render("enter: create and switch  •  tab: change type  •  ↑/↓: history  •  esc: cancel")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		"",
+		prompt,
+		m.textInput.View(),
+		details,
+		errMsg,
+		"",
+		help,
+	)
+}
+
+// BranchName fills in a branch name pattern. Parts of the name left empty,
+// such as {ticket} without a ticket, are dropped with their separators.
+func BranchName(pattern, typ, ticket, description string) string {
+	name := strings.NewReplacer("{type}", typ, "{ticket}", ticket, "{slug}", slugify(description)).Replace(pattern)
+
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		for strings.Contains(part, "--") {
+			part = strings.ReplaceAll(part, "--", "-")
+		}
+		if part = strings.Trim(part, "-_."); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// splitTicket takes an issue key off the front of a description, without
+// the # of a #123 key. Keys must be in upper case, as trackers show them,
+// so that words like utf-8 aren't taken for one.
+func splitTicket(description string) (ticket, rest string) {
+	description = strings.TrimSpace(description)
+	first, rest, _ := strings.Cut(description, " ")
+	if key := git.IssueKey(first); key == "" || key != first {
+		return "", description
+	}
+	return strings.TrimPrefix(first, "#"), strings.TrimSpace(rest)
+}
@@ -0,0 +1,49 @@
+package views
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		pattern, typ, ticket, description string
+		want                              string
+	}{
+		{"{type}/{ticket}-{slug}", "feature", "ABC-123", "Add login page", "feature/ABC-123-add-login-page"},
+		{"{type}/{ticket}-{slug}", "fix", "", "Crash on start", "fix/crash-on-start"},
+		{"{type}/{slug}-{ticket}", "chore", "", "bump deps", "chore/bump-deps"},
+		{"{ticket}/{slug}", "feature", "", "tidy", "tidy"},
+		{"{type}/{ticket}-{slug}", "", "456", "", "456"},
+	}
+	for _, tt := range tests {
+		if got := BranchName(tt.pattern, tt.typ, tt.ticket, tt.description); got != tt.want {
+			t.Errorf("BranchName(%q, %q, %q, %q) = %q, want %q", tt.pattern, tt.typ, tt.ticket, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestNewBranchModel(t *testing.T) {
+	m := NewNewBranchModel("{type}/{ticket}-{slug}", []string{"feature", "fix", "chore"}, nil)
+	if m.Branch() != "" {
+		t.Errorf("Expected no branch without a description, got %q", m.Branch())
+	}
+
+	m.textInput.SetValue("ABC-123 Add login page")
+	if got := m.Branch(); got != "feature/ABC-123-add-login-page" {
+		t.Errorf("Branch() = %q", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.textInput.SetValue("#456 fix crash")
+	if got := m.Branch(); got != "fix/456-fix-crash" {
+		t.Errorf("Branch() after tab = %q", got)
+	}
+
+	// A first word that only starts like an issue key is part of the description
+	m.textInput.SetValue("utf-8 handling")
+	if got := m.Branch(); got != "fix/utf-8-handling" {
+		t.Errorf("Branch() = %q", got)
+	}
+}
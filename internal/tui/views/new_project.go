@@ -2,6 +2,10 @@ package views
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,25 +13,81 @@ import (
 	"github.com/s33g/proj/internal/tui"
 )
 
+// ProjectLocation is a directory new projects can be created in
+type ProjectLocation struct {
+	Name string // Group name; empty for the repos root
+	Path string
+}
+
+// TemplateOption is a template new projects can start from
+type TemplateOption struct {
+	Name string
+	Note string // Shown after the name, e.g. what runs the template
+}
+
 // NewProjectModel is the model for creating a new project
 type NewProjectModel struct {
-	textInput textinput.Model
+	textInput Input
+	locations []ProjectLocation // The repos root first, then groups
+	location  int
+	source    string           // Project being duplicated, if any
+	templates []TemplateOption // Templates new projects can start from
+	template  int              // Index into templates plus one; 0 starts empty
+	initGit   bool             // Start a new git repository in the duplicate
 	err       error
 	width     int
 	height    int
 }
 
-// NewNewProjectModel creates a new project creation model
-func NewNewProjectModel() NewProjectModel {
-	ti := textinput.New()
+// NewNewProjectModel creates a new project creation model. locations are
+// where the project can be created, starting at selected; history holds
+// names entered before, most recent first.
+func NewNewProjectModel(locations []ProjectLocation, selected int, history []string) NewProjectModel {
+	ti := NewInput(history)
 	ti.Placeholder = "my-new-project"
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.Width = 50
 
+	if selected < 0 || selected >= len(locations) {
+		selected = 0
+	}
 	return NewProjectModel{
 		textInput: ti,
+		locations: locations,
+		location:  selected,
+	}
+}
+
+// ForDuplicate turns the model into the prompt for a copy of the project
+// source, starting with the name source-copy. initGit is whether the copy
+// gets a new git repository, which ctrl+g toggles.
+func (m NewProjectModel) ForDuplicate(source string, initGit bool) NewProjectModel {
+	m.source = source
+	m.initGit = initGit
+	m.textInput.SetValue(source + "-copy")
+	m.textInput.CursorEnd()
+	m.validate()
+	return m
+}
+
+// WithTemplates offers templates, which ctrl+t cycles through
+func (m NewProjectModel) WithTemplates(templates []TemplateOption) NewProjectModel {
+	m.templates = templates
+	return m
+}
+
+// Template returns the name of the chosen template, or "" to start empty
+func (m NewProjectModel) Template() string {
+	if m.template == 0 || m.source != "" {
+		return ""
 	}
+	return m.templates[m.template-1].Name
+}
+
+// InitGit reports whether a duplicate should get a new git repository
+func (m NewProjectModel) InitGit() bool {
+	return m.initGit
 }
 
 func (m NewProjectModel) Init() tea.Cmd {
@@ -35,26 +95,108 @@ func (m NewProjectModel) Init() tea.Cmd {
 }
 
 func (m NewProjectModel) Update(msg tea.Msg) (NewProjectModel, tea.Cmd) {
+	// tab cycles through the locations
+	if key, ok := msg.(tea.KeyMsg); ok && len(m.locations) > 1 {
+		switch key.String() {
+		case "tab":
+			m.location = (m.location + 1) % len(m.locations)
+			return m, nil
+		case "shift+tab":
+			m.location = (m.location - 1 + len(m.locations)) % len(m.locations)
+			return m, nil
+		}
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+t" && len(m.templates) > 0 && m.source == "" {
+		m.template = (m.template + 1) % (len(m.templates) + 1)
+		return m, nil
+	}
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+g" && m.source != "" {
+		m.initGit = !m.initGit
+		return m, nil
+	}
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "ctrl+s" {
+		if suggestion := m.Suggestion(); suggestion != "" {
+			m.textInput.SetValue(suggestion)
+			m.textInput.CursorEnd()
+			m.validate()
+		}
+		return m, nil
+	}
+
+	value := m.Value()
 	var cmd tea.Cmd
 	m.textInput, cmd = m.textInput.Update(msg)
+	if m.Value() != value {
+		m.validate()
+	}
 	return m, cmd
 }
 
+// validate shows what's wrong with the name as it is typed
+func (m *NewProjectModel) validate() {
+	m.err = nil
+	// Wait for the name after a group/
+	value := strings.TrimSpace(m.Value())
+	if value == "" || strings.HasSuffix(value, "/") {
+		return
+	}
+	_, _, m.err = m.Target()
+}
+
 func (m NewProjectModel) View() string {
 	title := tui.TitleStyle.Render("📝 Create New Project")
+	promptText := "Enter project name:"
+	if m.source != "" {
+		title = tui.TitleStyle.Render("📋 Duplicate " + m.source)
+		promptText = "Enter a name for the copy:"
+	}
 
 	prompt := lipgloss.NewStyle().
 		Foreground(tui.Muted).
-		Render("Enter project name:")
+		Render(promptText)
 
 	input := m.textInput.View()
 
-	help := tui.HelpStyle.Render("enter: create  •  esc: cancel")
+	location := ""
+	helpText := "enter: create  •  ↑/↓: history  •  esc: cancel"
+	if len(m.locations) > 0 {
+		label := lipgloss.NewStyle().Foreground(tui.Muted).Render("Create in: ")
+		location = "\n" + label + tui.SubtitleStyle.Render(m.locationLabel())
+		if len(m.locations) > 1 {
+			helpText = "enter: create  •  tab: change group (or type group/name)  •  ↑/↓: history  •  esc: cancel"
+		}
+	}
+	if len(m.templates) > 0 && m.source == "" {
+		name := tui.SubtitleStyle.Render("(none)")
+		if m.Template() != "" {
+			t := m.templates[m.template-1]
+			name = tui.SubtitleStyle.Render(t.Name)
+			if t.Note != "" {
+				name += " " + lipgloss.NewStyle().Foreground(tui.Muted).Render(t.Note)
+			}
+		}
+		label := lipgloss.NewStyle().Foreground(tui.Muted).Render("Template:  ")
+		location += "\n" + label + name + tui.HelpStyle.Render("  (ctrl+t)")
+	}
+	if m.source != "" {
+		check := "[ ]"
+		if m.initGit {
+			check = "[x]"
+		}
+		label := lipgloss.NewStyle().Foreground(tui.Muted).Render("Git:       ")
+		location += "\n" + label + check + " start a new repository" + tui.HelpStyle.Render("  (ctrl+g)")
+	}
+	help := tui.HelpStyle.Render(helpText)
 
 	errMsg := ""
 	if m.err != nil {
 		errMsg = "\n" + tui.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
 	}
+	if suggestion := m.Suggestion(); suggestion != "" {
+		label := lipgloss.NewStyle().Foreground(tui.Muted).Render("Suggestion: ")
+		errMsg += "\n" + label + tui.SubtitleStyle.Render(suggestion) + tui.HelpStyle.Render("  (ctrl+s to use)")
+	}
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -62,6 +204,7 @@ func (m NewProjectModel) View() string {
 		"",
 		prompt,
 		input,
+		location,
 		errMsg,
 		"",
 		help,
@@ -73,6 +216,125 @@ func (m NewProjectModel) Value() string {
 	return m.textInput.Value()
 }
 
+// locationLabel describes where the project will be created
+func (m NewProjectModel) locationLabel() string {
+	loc, name := m.locations[m.location], m.Value()
+	if group, _, ok := m.splitGroup(name); ok {
+		loc = group
+	}
+	if loc.Name == "" {
+		return loc.Path + " (top level)"
+	}
+	return loc.Name + "  " + loc.Path
+}
+
+// Target returns the name of the new project and where to create it. A
+// "group/name" value creates it in that group, whichever one is selected.
+func (m NewProjectModel) Target() (string, ProjectLocation, error) {
+	name := strings.TrimSpace(m.Value())
+	var loc ProjectLocation
+	if len(m.locations) > 0 {
+		loc = m.locations[m.location]
+	}
+
+	if group, rest, ok := m.splitGroup(name); ok {
+		loc, name = group, rest
+	} else if i := strings.LastIndex(name, "/"); i >= 0 {
+		return "", loc, fmt.Errorf("no group named %q", name[:i])
+	}
+
+	if err := validateName(name); err != nil {
+		return "", loc, err
+	}
+	if _, err := os.Lstat(filepath.Join(loc.Path, name)); err == nil {
+		return "", loc, fmt.Errorf("%s already exists in %s", name, loc.Path)
+	}
+	return name, loc, nil
+}
+
+// reservedNames can't be used as directory names on Windows, with or
+// without an extension
+var reservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// validateName checks that name can be used as a project directory name on
+// any platform
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid project name %q", name)
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("project name %q can't contain / or \\", name)
+	}
+	for _, r := range name {
+		if strings.ContainsRune(`<>:"|?*`, r) || unicode.IsControl(r) {
+			return fmt.Errorf("project name %q can't contain %q", name, r)
+		}
+	}
+	if strings.HasSuffix(name, ".") {
+		return fmt.Errorf("project name %q can't end with a dot", name)
+	}
+	base, _, _ := strings.Cut(name, ".")
+	for _, reserved := range reservedNames {
+		if strings.EqualFold(base, reserved) {
+			return fmt.Errorf("%q is a reserved name", name)
+		}
+	}
+	return nil
+}
+
+// Suggestion returns a slug of the typed name, such as my-app for "My App",
+// when it differs from the name. A group/ prefix is kept as typed.
+func (m NewProjectModel) Suggestion() string {
+	value := strings.TrimSpace(m.Value())
+	prefix, name := "", value
+	if _, rest, ok := m.splitGroup(value); ok {
+		prefix, name = value[:len(value)-len(rest)], rest
+	}
+	slug := slugify(name)
+	if slug == "" || slug == name || validateName(slug) != nil {
+		return ""
+	}
+	return prefix + slug
+}
+
+// slugify lower-cases name and replaces runs of anything but letters,
+// digits, dots and underscores with a dash
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), ".")
+}
+
+// splitGroup splits "group/name" when group is one of the locations
+func (m NewProjectModel) splitGroup(value string) (ProjectLocation, string, bool) {
+	i := strings.LastIndex(value, "/")
+	if i < 0 {
+		return ProjectLocation{}, "", false
+	}
+	prefix := strings.TrimSuffix(filepath.ToSlash(value[:i]), "/")
+	for _, loc := range m.locations {
+		if loc.Name != "" && strings.EqualFold(loc.Name, prefix) {
+			return loc, value[i+1:], true
+		}
+	}
+	return ProjectLocation{}, "", false
+}
+
 // SetError sets an error message
 func (m *NewProjectModel) SetError(err error) {
 	m.err = err
@@ -0,0 +1,172 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewProjectTarget(t *testing.T) {
+	locations := []ProjectLocation{
+		{Path: "/code"},
+		{Name: "work", Path: "/code/work"},
+		{Name: "github.com/s33g", Path: "/code/github.com/s33g"},
+	}
+
+	tests := []struct {
+		value    string
+		selected int
+		name     string
+		path     string
+		ok       bool
+	}{
+		{"api", 0, "api", "/code", true},
+		{"api", 1, "api", "/code/work", true},
+		{"Work/api", 0, "api", "/code/work", true},
+		{"github.com/s33g/tool", 1, "tool", "/code/github.com/s33g", true},
+		{"play/api", 0, "", "", false},
+		{"work/", 0, "", "", false},
+		{"..", 0, "", "", false},
+	}
+
+	for _, tt := range tests {
+		m := NewNewProjectModel(locations, tt.selected, nil)
+		m.textInput.SetValue(tt.value)
+
+		name, loc, err := m.Target()
+		if (err == nil) != tt.ok {
+			t.Errorf("Target(%q) error = %v, want ok %v", tt.value, err, tt.ok)
+			continue
+		}
+		if tt.ok && (name != tt.name || loc.Path != tt.path) {
+			t.Errorf("Target(%q) = %q in %s, want %q in %s", tt.value, name, loc.Path, tt.name, tt.path)
+		}
+	}
+}
+
+func TestNewProjectCycleLocation(t *testing.T) {
+	locations := []ProjectLocation{{Path: "/code"}, {Name: "work", Path: "/code/work"}}
+	m := NewNewProjectModel(locations, 0, nil)
+	m.textInput.SetValue("api")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if _, loc, _ := m.Target(); loc.Path != "/code/work" {
+		t.Errorf("after tab: location %s, want /code/work", loc.Path)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if _, loc, _ := m.Target(); loc.Path != "/code" {
+		t.Errorf("after shift+tab: location %s, want /code", loc.Path)
+	}
+	if m.Value() != "api" {
+		t.Errorf("tab should not change the name, got %q", m.Value())
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"api", true},
+		{"My App", true},
+		{"v1.2", true},
+		{"con", false},
+		{"Aux.txt", false},
+		{"console", true},
+		{`back\slash`, false},
+		{"what?", false},
+		{"tab\there", false},
+		{"trailing.", false},
+	}
+	for _, tt := range tests {
+		if err := validateName(tt.name); (err == nil) != tt.ok {
+			t.Errorf("validateName(%q) = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestNewProjectSuggestion(t *testing.T) {
+	locations := []ProjectLocation{{Path: "/code"}, {Name: "work", Path: "/code/work"}}
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"My App", "my-app"},
+		{"work/Hello, World!", "work/hello-world"},
+		{"  Data_Pipeline  v2 ", "data_pipeline-v2"},
+		{"my-app", ""},
+		{"???", ""},
+	}
+	for _, tt := range tests {
+		m := NewNewProjectModel(locations, 0, nil)
+		m.textInput.SetValue(tt.value)
+		if got := m.Suggestion(); got != tt.want {
+			t.Errorf("Suggestion(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	// ctrl+s takes the suggestion
+	m := NewNewProjectModel(locations, 0, nil)
+	m.textInput.SetValue("My App")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.Value() != "my-app" {
+		t.Errorf("after ctrl+s: Value() = %q, want my-app", m.Value())
+	}
+}
+
+func TestNewProjectExisting(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	m := NewNewProjectModel([]ProjectLocation{{Path: root}}, 0, nil)
+
+	// Errors show as the name is typed
+	for _, r := range "api" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "already exists") {
+		t.Errorf("err = %v, want already exists", m.err)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-v2")})
+	if m.err != nil {
+		t.Errorf("err = %v, want none for a new name", m.err)
+	}
+}
+
+func TestNewProjectForDuplicate(t *testing.T) {
+	m := NewNewProjectModel([]ProjectLocation{{Path: t.TempDir()}}, 0, nil).ForDuplicate("api", true)
+	if m.Value() != "api-copy" || !m.InitGit() {
+		t.Fatalf("ForDuplicate: Value() = %q, InitGit() = %v", m.Value(), m.InitGit())
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if m.InitGit() {
+		t.Error("ctrl+g should turn off starting a new repository")
+	}
+	if !strings.Contains(m.View(), "Duplicate api") {
+		t.Errorf("View() should name the project being duplicated:\n%s", m.View())
+	}
+}
+
+func TestNewProjectTemplates(t *testing.T) {
+	m := NewNewProjectModel([]ProjectLocation{{Path: "/code"}}, 0, nil).WithTemplates([]TemplateOption{{Name: "go-cli"}, {Name: "pypkg", Note: "cookiecutter"}})
+	want := []string{"go-cli", "pypkg", ""}
+	for _, name := range want {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		if m.Template() != name {
+			t.Errorf("Template() = %q, want %q", m.Template(), name)
+		}
+	}
+
+	// Duplicating doesn't use templates
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m = m.ForDuplicate("api", false); m.Template() != "" {
+		t.Errorf("Template() = %q while duplicating", m.Template())
+	}
+}
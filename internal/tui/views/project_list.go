@@ -3,11 +3,15 @@ package views
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/s33g/proj/internal/config"
 	"github.com/s33g/proj/internal/language"
 	"github.com/s33g/proj/internal/project"
 	"github.com/s33g/proj/internal/tui"
@@ -20,6 +24,7 @@ var (
 	langStyle         = lipgloss.NewStyle().Foreground(tui.Accent)
 	branchStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
 	dirtyStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6347")).Bold(true)
+	detailStyle       = lipgloss.NewStyle().PaddingLeft(4).Foreground(tui.Muted)
 )
 
 // ProjectListItem implements list.Item for projects
@@ -27,8 +32,36 @@ type ProjectListItem struct {
 	Project *project.Project
 }
 
+// aliasSep separates the name from the aliases in FilterValue
+const aliasSep = "\x00"
+
 func (i ProjectListItem) FilterValue() string {
-	return i.Project.Name
+	return strings.Join(append([]string{i.Project.Name}, i.Project.Aliases...), aliasSep)
+}
+
+// aliasFirstFilter ranks projects with an alias equal to the filter term
+// first, followed by fuzzy matches of the names as list.DefaultFilter does
+func aliasFirstFilter(term string, targets []string) []list.Rank {
+	var ranks []list.Rank
+	aliased := make(map[int]bool)
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		fields := strings.Split(target, aliasSep)
+		names[i] = fields[0]
+		for _, alias := range fields[1:] {
+			if strings.EqualFold(alias, term) {
+				ranks = append(ranks, list.Rank{Index: i})
+				aliased[i] = true
+				break
+			}
+		}
+	}
+	for _, rank := range list.DefaultFilter(term, names) {
+		if !aliased[rank.Index] {
+			ranks = append(ranks, rank)
+		}
+	}
+	return ranks
 }
 
 func (i ProjectListItem) Title() string {
@@ -57,9 +90,21 @@ func (i ProjectListItem) Description() string {
 }
 
 // itemDelegate is a custom delegate for rendering project items
-type itemDelegate struct{}
+type itemDelegate struct {
+	marked   map[string]bool // Paths of projects marked for bulk actions
+	columns  []Column        // Columns to show; DefaultColumns when empty
+	detailed bool            // Show a second line with the path and activity
+	theme    config.ThemeConfig
+	indent   int // Depth of the list's outermost items, which aren't indented
+}
+
+func (d itemDelegate) Height() int {
+	if d.detailed {
+		return 2
+	}
+	return 1
+}
 
-func (d itemDelegate) Height() int                             { return 1 }
 func (d itemDelegate) Spacing() int                            { return 0 }
 func (d itemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 
@@ -70,92 +115,163 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	}
 
 	p := i.Project
-	isSelected := index == m.Index()
-	isGroup := p.IsGroup
-	hasChildren := p.SubProjectCount > 0
+	columns := d.columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
 
 	// Build the line
 	var line strings.Builder
+	now := time.Now()
+	for n, col := range columns {
+		cell := ""
+		if col.Name == ColumnName {
+			cell = d.renderName(p, index == m.Index(), col.Width)
+		} else if !p.IsGroup {
+			// Only show details for actual projects (not pure groups)
+			cell = renderCell(col, p, d.theme, now)
+		}
+		if n < len(columns)-1 {
+			cell = pad(cell, col.Width) + columnGap
+		}
+		line.WriteString(cell)
+	}
+
+	if !p.IsGroup {
+		// Dev server started from the menu, and the port it listens on
+		if p.ServerPID > 0 {
+			marker := "  ▶"
+			if p.ServerPort > 0 {
+				marker += fmt.Sprintf(" :%d", p.ServerPort)
+			}
+			line.WriteString(lipgloss.NewStyle().Foreground(tui.Accent).Render(marker))
+		}
+
+		// Declared Nix/direnv environment
+		if p.DevEnv != "" {
+			line.WriteString("  ❄")
+		}
+
+		if p.UsesLFS {
+			line.WriteString(branchStyle.Render("  LFS"))
+		}
+
+		// Contributed by a plugin rather than found on disk
+		if p.Source != "" {
+			line.WriteString(branchStyle.Render("  ☁ " + p.Source))
+		}
+
+		if p.NeedsSetup {
+			line.WriteString(lipgloss.NewStyle().Foreground(tui.Warning).Render("  needs setup"))
+		}
+
+		if warning := GitWarning(p); warning != "" {
+			line.WriteString(lipgloss.NewStyle().Foreground(tui.Warning).Render("  ⚠ " + warning))
+		}
+	}
+
+	// Archive marker and tags
+	if p.Archived {
+		line.WriteString(branchStyle.Render("  archived"))
+	}
+	if len(p.Tags) > 0 {
+		line.WriteString(branchStyle.Render("  #" + strings.Join(p.Tags, " #")))
+	}
+
+	// Symlink origin
+	if p.SymlinkTarget != "" {
+		line.WriteString(branchStyle.Render("  → " + p.SymlinkTarget))
+	}
+
+	if d.detailed {
+		line.WriteString("\n")
+		line.WriteString(detailStyle.Render(DetailLine(p, now)))
+	}
+
+	_, _ = fmt.Fprint(w, line.String())
+}
+
+// DetailLine returns the second line shown for a project in the detailed
+// list: its path, when it was last modified and last opened
+func DetailLine(p *project.Project, now time.Time) string {
+	parts := []string{shortenHome(p.Path)}
+	if !p.LastModified.IsZero() {
+		parts = append(parts, "modified "+RelativeTime(p.LastModified, now))
+	}
+	if hint := LastOpenedHint(p, now); hint != "" {
+		parts = append(parts, strings.ToLower(hint[:1])+hint[1:])
+	}
+	return strings.Join(parts, "  •  ")
+}
 
-	// Prefix for selection
+// shortenHome replaces the home directory prefix of path with ~
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
+
+// renderName renders the name column: selection prefix, group or monorepo
+// icon, and the name truncated to fit width
+func (d itemDelegate) renderName(p *project.Project, isSelected bool, width int) string {
+	isGroup := p.IsGroup
+	hasChildren := p.SubProjectCount > 0
+
+	// Prefix for selection, then nested groups' items indented
 	prefix := "  "
 	if isSelected {
 		prefix = "▸ "
 	}
+	indent := ""
+	if p.Depth > d.indent {
+		indent = strings.Repeat("  ", p.Depth-d.indent)
+	}
 
 	// Project/group name
 	name := p.Name
 	if hasChildren {
 		name = fmt.Sprintf("%s (%d)", name, p.SubProjectCount)
 	}
+	if p.Pinned {
+		name = "★ " + name
+	}
+	if d.marked[p.Path] {
+		name = "✓ " + name
+	}
 
-	// Style based on selection and type
-	if isGroup {
-		// Pure group (folder containing projects)
-		groupStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true)
-		if isSelected {
-			line.WriteString(selectedItemStyle.Render(prefix + "📁 " + name))
-		} else {
-			line.WriteString(itemStyle.Render(prefix + groupStyle.Render("📁 " + name)))
-		}
+	icon := ""
+	if isGroup && !p.Expanded {
+		icon = "+ 📁 "
+	} else if isGroup {
+		icon = "📁 "
 	} else if hasChildren {
-		// Monorepo (project with sub-projects)
-		if isSelected {
-			line.WriteString(selectedItemStyle.Render(prefix + "📦 " + name))
-		} else {
-			line.WriteString(itemStyle.Render(prefix + "📦 " + name))
-		}
-	} else if isSelected {
-		line.WriteString(selectedItemStyle.Render(prefix + name))
-	} else {
-		line.WriteString(itemStyle.Render(prefix + name))
+		icon = "📦 "
 	}
+	icon = indent + icon
 
-	// Calculate padding for alignment
-	visibleLen := len(prefix) + len(p.Name)
-	if hasChildren {
-		visibleLen += len(fmt.Sprintf(" (%d)", p.SubProjectCount))
-	}
-	if isGroup || hasChildren {
-		visibleLen += 3 // icon
-	}
-	padding := 35 - visibleLen
-	if padding < 2 {
-		padding = 2
+	// Leave room for the left padding, selection prefix and icon
+	if width > 0 {
+		name = fit(name, width-4-lipgloss.Width(icon))
 	}
-	line.WriteString(strings.Repeat(" ", padding))
-
-	// Only show details for actual projects (not pure groups)
-	if !p.IsGroup {
-		// Language
-		if p.Language != "" && p.Language != "Unknown" {
-			icon := language.GetIcon(p.Language)
-			line.WriteString(langStyle.Render(fmt.Sprintf("%s %-10s", icon, p.Language)))
-		} else {
-			line.WriteString(strings.Repeat(" ", 12))
-		}
 
-		// Git branch
-		if p.GitBranch != "" {
-			branch := p.GitBranch
-			if len(branch) > 20 {
-				branch = branch[:17] + "..."
-			}
-			line.WriteString(branchStyle.Render(branch))
-			if p.GitDirty {
-				line.WriteString(dirtyStyle.Render("*"))
-			}
-		}
-
-		// Docker indicators
-		if p.HasCompose {
-			line.WriteString("  🐙")
-		} else if p.HasDockerfile {
-			line.WriteString("  🐳")
-		}
+	// Style based on selection and type
+	if isGroup && !isSelected {
+		// Pure group (folder containing projects)
+		groupStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true)
+		return itemStyle.Render(prefix + groupStyle.Render(icon+name))
 	}
-
-	_, _ = fmt.Fprint(w, line.String())
+	if isSelected {
+		return selectedItemStyle.Render(prefix + icon + name)
+	}
+	return itemStyle.Render(prefix + icon + name)
 }
 
 // ProjectListModel is the model for the project list view
@@ -164,19 +280,28 @@ type ProjectListModel struct {
 	projects   []*project.Project
 	width      int
 	height     int
-	showAll    bool // When true, show all projects regardless of depth (for group views)
+	showAll    bool // When true, show all projects regardless of depth (for group views), except inside collapsed groups
+	indent     int             // Depth of the outermost projects, set by RebuildList
+	marked     map[string]bool // Paths of projects marked for bulk actions
+	columns    []Column        // Columns shown for each project
+	detailed   bool            // Two-line items with path and activity
+	query      string          // project.Match query limiting the items
+	archived   bool            // List the archived projects instead of the others
+	theme      config.ThemeConfig
 }
 
 // NewProjectListModel creates a new project list model
 func NewProjectListModel(projects []*project.Project) ProjectListModel {
 	// Use our custom compact delegate
-	delegate := itemDelegate{}
+	marked := make(map[string]bool)
+	delegate := itemDelegate{marked: marked}
 
 	// Initialize with reasonable default size (will be updated on WindowSizeMsg)
 	l := list.New([]list.Item{}, delegate, 80, 24)
 	l.Title = ""
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
+	l.Filter = aliasFirstFilter
 	l.SetShowHelp(false)
 	l.SetShowPagination(true)
 	l.Styles.Title = lipgloss.NewStyle()
@@ -189,6 +314,7 @@ func NewProjectListModel(projects []*project.Project) ProjectListModel {
 		width:    80,
 		height:   24,
 		showAll:  false, // Main list only shows top-level
+		marked:   marked,
 	}
 	
 	// Build the visible items list
@@ -200,13 +326,15 @@ func NewProjectListModel(projects []*project.Project) ProjectListModel {
 // NewGroupListModel creates a project list model for showing group contents (shows all items)
 func NewGroupListModel(projects []*project.Project) ProjectListModel {
 	// Use our custom compact delegate
-	delegate := itemDelegate{}
+	marked := make(map[string]bool)
+	delegate := itemDelegate{marked: marked}
 
 	// Initialize with reasonable default size
 	l := list.New([]list.Item{}, delegate, 80, 24)
 	l.Title = ""
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
+	l.Filter = aliasFirstFilter
 	l.SetShowHelp(false)
 	l.SetShowPagination(true)
 	l.Styles.Title = lipgloss.NewStyle()
@@ -219,6 +347,7 @@ func NewGroupListModel(projects []*project.Project) ProjectListModel {
 		width:    80,
 		height:   24,
 		showAll:  true, // Group list shows all items
+		marked:   marked,
 	}
 	
 	// Build the visible items list
@@ -248,6 +377,46 @@ func (m *ProjectListModel) SetSize(width, height int) {
 	m.list.SetSize(width, height)
 }
 
+// SetColumns sets the columns shown for each project
+func (m *ProjectListModel) SetColumns(columns []Column) {
+	m.columns = columns
+	m.updateDelegate()
+}
+
+// SetDetailed switches between compact (one line) and detailed (two line) items
+func (m *ProjectListModel) SetDetailed(detailed bool) {
+	m.detailed = detailed
+	m.updateDelegate()
+}
+
+// SetTheme sets the theme used to color branches
+func (m *ProjectListModel) SetTheme(theme config.ThemeConfig) {
+	m.theme = theme
+	m.updateDelegate()
+}
+
+func (m *ProjectListModel) updateDelegate() {
+	m.list.SetDelegate(itemDelegate{marked: m.marked, columns: m.columns, detailed: m.detailed, theme: m.theme, indent: m.indent})
+}
+
+// ToggleExpanded collapses the selected group, hiding the projects inside
+// it, or expands it again. It reports false when a project is selected.
+func (m *ProjectListModel) ToggleExpanded() bool {
+	p := m.SelectedProject()
+	if p == nil || !p.IsGroup {
+		return false
+	}
+	p.Expanded = !p.Expanded
+	m.RebuildList()
+	m.SelectProject(p)
+	return true
+}
+
+// IsFiltering reports whether the user is currently typing a filter
+func (m ProjectListModel) IsFiltering() bool {
+	return m.list.FilterState() == list.Filtering
+}
+
 // SelectedProject returns the currently selected project
 func (m ProjectListModel) SelectedProject() *project.Project {
 	item := m.list.SelectedItem()
@@ -257,21 +426,152 @@ func (m ProjectListModel) SelectedProject() *project.Project {
 	return item.(ProjectListItem).Project
 }
 
-// RebuildList rebuilds the list items
-func (m *ProjectListModel) RebuildList() {
+// ToggleMarked marks or unmarks the selected project for bulk actions
+func (m *ProjectListModel) ToggleMarked() {
+	p := m.SelectedProject()
+	if p == nil || p.IsGroup {
+		return
+	}
+	if m.marked[p.Path] {
+		delete(m.marked, p.Path)
+	} else {
+		m.marked[p.Path] = true
+	}
+}
+
+// MarkedProjects returns the projects marked for bulk actions in list order
+func (m ProjectListModel) MarkedProjects() []*project.Project {
+	marked := make([]*project.Project, 0, len(m.marked))
+	for _, p := range m.projects {
+		if m.marked[p.Path] {
+			marked = append(marked, p)
+		}
+	}
+	return marked
+}
+
+// ClearMarked unmarks all projects
+func (m *ProjectListModel) ClearMarked() {
+	for path := range m.marked {
+		delete(m.marked, path)
+	}
+}
+
+// SetQuery limits the list to projects matching a project.Match query. An
+// empty query shows everything again.
+func (m *ProjectListModel) SetQuery(query string) error {
+	if _, err := project.Match(nil, query); err != nil {
+		return err
+	}
+	m.query = query
+	m.RebuildList()
+	m.list.Select(0)
+	return nil
+}
+
+// SetArchived switches the list between the archived projects and the
+// others, which is what it shows by default
+func (m *ProjectListModel) SetArchived(archived bool) tea.Cmd {
+	m.archived = archived
+	cmd := m.RebuildList()
+	m.list.Select(0)
+	return cmd
+}
+
+// Archived reports whether the list shows the archived projects
+func (m ProjectListModel) Archived() bool {
+	return m.archived
+}
+
+// Query returns the query set with SetQuery
+func (m ProjectListModel) Query() string {
+	return m.query
+}
+
+// CursorUp moves the selection up one item
+func (m *ProjectListModel) CursorUp() {
+	m.list.CursorUp()
+}
+
+// CursorDown moves the selection down one item
+func (m *ProjectListModel) CursorDown() {
+	m.list.CursorDown()
+}
+
+// SetProjects replaces the projects (e.g. after re-sorting or a rescan),
+// keeping the marks, the filter and the selected project. The returned
+// command filters the new items when a filter is applied.
+func (m *ProjectListModel) SetProjects(projects []*project.Project) tea.Cmd {
+	selected := m.SelectedProject()
+	m.projects = projects
+	cmd := m.RebuildList()
+
+	if selected == nil || m.list.FilterState() != list.Unfiltered {
+		return cmd
+	}
+	m.SelectProject(selected)
+	return cmd
+}
+
+// SelectProject moves the selection to p, or the project at its path after
+// a rescan, reporting whether it is shown in the list
+func (m *ProjectListModel) SelectProject(p *project.Project) bool {
+	for i, item := range m.list.Items() {
+		if item.(ProjectListItem).Project.Path == p.Path {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// RebuildList rebuilds the list items, returning the command that filters
+// them when a filter is applied
+func (m *ProjectListModel) RebuildList() tea.Cmd {
 	visibleProjects := make([]*project.Project, 0)
+	hidden := make(map[string]bool) // Paths of collapsed or archived groups and what's inside them
+	indent := -1
 	for _, p := range m.projects {
+		if m.archived {
+			// Archived projects are listed wherever they are nested
+			if p.Archived {
+				visibleProjects = append(visibleProjects, p)
+			}
+			continue
+		}
+		if p.Archived {
+			hidden[p.Path] = true // Along with what's inside it
+			continue
+		}
+		if indent == -1 || p.Depth < indent {
+			indent = p.Depth
+		}
 		// Show all items if showAll is true, otherwise only top-level
+		if m.showAll && hidden[p.ParentPath] {
+			hidden[p.Path] = true
+			continue
+		}
+		if m.showAll && p.IsGroup && !p.Expanded {
+			hidden[p.Path] = true
+		}
 		if m.showAll || p.Depth == 0 {
 			visibleProjects = append(visibleProjects, p)
 		}
 	}
+	if m.showAll && max(indent, 0) != m.indent {
+		m.indent = max(indent, 0)
+		m.updateDelegate()
+	}
+	if m.query != "" {
+		// The query is validated by SetQuery
+		visibleProjects, _ = project.Match(visibleProjects, m.query)
+	}
 
 	items := make([]list.Item, len(visibleProjects))
 	for i, p := range visibleProjects {
 		items[i] = ProjectListItem{Project: p}
 	}
-	m.list.SetItems(items)
+	return m.list.SetItems(items)
 }
 
 // ProjectList renders a simple project list
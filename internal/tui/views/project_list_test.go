@@ -0,0 +1,116 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s33g/proj/internal/project"
+)
+
+func TestDetailLine(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	now := time.Now()
+	p := &project.Project{
+		Path:           filepath.Join(home, "code", "api"),
+		LastModified:   now.Add(-2 * time.Hour),
+		LastOpenedWith: "nvim",
+		LastOpenedAt:   now.Add(-24 * time.Hour),
+	}
+
+	line := DetailLine(p, now)
+	for _, want := range []string{filepath.Join("~", "code", "api"), "modified 2h ago", "last opened in nvim 1d ago"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("DetailLine() = %q, expected to contain %q", line, want)
+		}
+	}
+}
+
+func TestAliasFirstFilter(t *testing.T) {
+	items := []ProjectListItem{
+		{Project: &project.Project{Name: "wiki"}},
+		{Project: &project.Project{Name: "company-website", Aliases: []string{"w"}}},
+		{Project: &project.Project{Name: "web"}},
+	}
+	targets := make([]string, len(items))
+	for i, item := range items {
+		targets[i] = item.FilterValue()
+	}
+
+	ranks := aliasFirstFilter("W", targets)
+	if len(ranks) == 0 || ranks[0].Index != 1 {
+		t.Fatalf("Expected the aliased project first, got %+v", ranks)
+	}
+	for _, r := range ranks[1:] {
+		if r.Index == 1 {
+			t.Errorf("Aliased project ranked twice: %+v", ranks)
+		}
+	}
+}
+
+func TestGroupListCollapse(t *testing.T) {
+	projects := []*project.Project{
+		{Name: "backend", Path: "/code/work/backend", ParentPath: "/code/work", Depth: 1, IsGroup: true, Expanded: true},
+		{Name: "service-x", Path: "/code/work/backend/service-x", ParentPath: "/code/work/backend", Depth: 2},
+		{Name: "web", Path: "/code/work/web", ParentPath: "/code/work", Depth: 1},
+	}
+	m := NewGroupListModel(projects)
+	names := func() string {
+		var shown []string
+		for _, item := range m.list.Items() {
+			shown = append(shown, item.(ProjectListItem).Project.Name)
+		}
+		return strings.Join(shown, ", ")
+	}
+	if got := names(); got != "backend, service-x, web" {
+		t.Fatalf("items = %q, want the nested group expanded", got)
+	}
+
+	if !m.ToggleExpanded() {
+		t.Fatal("Expected the selected group to collapse")
+	}
+	if got := names(); got != "backend, web" {
+		t.Errorf("items = %q, want the collapsed group's projects hidden", got)
+	}
+
+	m.CursorDown()
+	if m.ToggleExpanded() {
+		t.Error("Expected a project not to collapse")
+	}
+}
+
+func TestProjectListArchived(t *testing.T) {
+	projects := []*project.Project{
+		{Name: "api", Path: "/code/api"},
+		{Name: "old", Path: "/code/old", Archived: true},
+		{Name: "work", Path: "/code/work", IsGroup: true, Expanded: true},
+		{Name: "legacy", Path: "/code/work/legacy", ParentPath: "/code/work", Depth: 1, Archived: true},
+	}
+	m := NewProjectListModel(projects)
+	names := func() string {
+		var shown []string
+		for _, item := range m.list.Items() {
+			shown = append(shown, item.(ProjectListItem).Project.Name)
+		}
+		return strings.Join(shown, ", ")
+	}
+	if got := names(); got != "api, work" {
+		t.Errorf("items = %q, want the archived projects hidden", got)
+	}
+
+	m.SetArchived(true)
+	if got := names(); got != "old, legacy" {
+		t.Errorf("items = %q, want only the archived projects, nested ones too", got)
+	}
+
+	group := NewGroupListModel(projects[3:])
+	if len(group.list.Items()) != 0 {
+		t.Error("Expected archived projects hidden from group lists")
+	}
+}
@@ -0,0 +1,81 @@
+package wsl
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// windowsMount matches drvfs mounts such as /mnt/c or /mnt/d/code
+var windowsMount = regexp.MustCompile(`^/mnt/([a-zA-Z])(/|$)`)
+
+var (
+	detectOnce sync.Once
+	isWSL      bool
+)
+
+// IsWSL reports whether proj is running inside the Windows Subsystem for Linux
+func IsWSL() bool {
+	detectOnce.Do(func() {
+		release, _ := os.ReadFile("/proc/sys/kernel/osrelease")
+		isWSL = detect(string(release), os.Getenv("WSL_DISTRO_NAME"))
+	})
+	return isWSL
+}
+
+// detect checks the kernel release string and WSL environment
+func detect(osRelease, distroName string) bool {
+	if distroName != "" {
+		return true
+	}
+	release := strings.ToLower(osRelease)
+	return strings.Contains(release, "microsoft") || strings.Contains(release, "wsl")
+}
+
+// IsWindowsMount reports whether path is on a Windows drive mounted in WSL
+func IsWindowsMount(path string) bool {
+	return windowsMount.MatchString(filepath.ToSlash(path))
+}
+
+// NormalizePath returns a key for comparing paths. Windows drives are case
+// insensitive, so paths on them are lower-cased.
+func NormalizePath(path string) string {
+	if IsWindowsMount(path) {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// IsWindowsExecutable reports whether a command is a Windows program, which
+// needs Windows-style paths as arguments
+func IsWindowsExecutable(command string) bool {
+	return strings.HasSuffix(strings.ToLower(command), ".exe")
+}
+
+// ToWindowsPath converts a WSL path to a Windows path using wslpath, falling
+// back to translating /mnt/<drive> paths directly
+func ToWindowsPath(path string) string {
+	if out, err := exec.Command("wslpath", "-w", path).Output(); err == nil {
+		if converted := string(bytes.TrimSpace(out)); converted != "" {
+			return converted
+		}
+	}
+	return translate(path)
+}
+
+// translate converts /mnt/c/x/y to C:\x\y. Other paths are returned unchanged
+// since they can't be expressed without knowing the distro name.
+func translate(path string) string {
+	path = filepath.ToSlash(path)
+	m := windowsMount.FindStringSubmatch(path)
+	if m == nil {
+		return path
+	}
+
+	rest := strings.TrimPrefix(path[len("/mnt/")+1:], "/")
+	return strings.ToUpper(m[1]) + `:\` + strings.ReplaceAll(rest, "/", `\`)
+}
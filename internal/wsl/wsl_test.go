@@ -0,0 +1,69 @@
+package wsl
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		release, distro string
+		expected        bool
+	}{
+		{"5.15.90.1-microsoft-standard-WSL2", "", true},
+		{"4.4.0-19041-Microsoft", "", true},
+		{"6.5.0-generic", "Ubuntu", true},
+		{"6.5.0-generic", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := detect(tt.release, tt.distro); got != tt.expected {
+			t.Errorf("detect(%q, %q) = %v, want %v", tt.release, tt.distro, got, tt.expected)
+		}
+	}
+}
+
+func TestIsWindowsMount(t *testing.T) {
+	tests := map[string]bool{
+		"/mnt/c":              true,
+		"/mnt/c/Users/me":     true,
+		"/mnt/D/code":         true,
+		"/mnt/data":           false,
+		"/home/me/code":       false,
+		"/mnt/wsl/something":  false,
+		"/mnt/cdrive/nothing": false,
+	}
+	for path, expected := range tests {
+		if got := IsWindowsMount(path); got != expected {
+			t.Errorf("IsWindowsMount(%q) = %v, want %v", path, got, expected)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	if got := NormalizePath("/mnt/c/Users/Me/Code"); got != "/mnt/c/users/me/code" {
+		t.Errorf("Expected Windows paths to be lower-cased, got %q", got)
+	}
+	if got := NormalizePath("/home/Me/Code"); got != "/home/Me/Code" {
+		t.Errorf("Expected Linux paths to be unchanged, got %q", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := map[string]string{
+		"/mnt/c/Users/me/code": `C:\Users\me\code`,
+		"/mnt/d":               `D:\`,
+		"/home/me/code":        "/home/me/code",
+	}
+	for path, expected := range tests {
+		if got := translate(path); got != expected {
+			t.Errorf("translate(%q) = %q, want %q", path, got, expected)
+		}
+	}
+}
+
+func TestIsWindowsExecutable(t *testing.T) {
+	if !IsWindowsExecutable("notepad++.EXE") {
+		t.Error("Expected .exe to be a Windows executable")
+	}
+	if IsWindowsExecutable("code") {
+		t.Error("Expected code (the WSL shim) not to be a Windows executable")
+	}
+}
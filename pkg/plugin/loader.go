@@ -3,14 +3,30 @@ package plugin
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 )
 
-// ExternalPlugin represents a plugin loaded from an external executable
+// ExternalPlugin represents a plugin loaded from an external executable.
+// Its process is supervised: if it crashes it's restarted with backoff.
 type ExternalPlugin struct {
-	client   *RPCClient
+	client   *RPCClient // Nil unless running
 	manifest *Manifest
 	config   map[string]interface{}
 	execPath string
+	prefixes []string // Action ID prefixes the plugin declared in its init response
+	id       string   // Directory the plugin was loaded from, which its user settings are keyed by
+
+	configProblems []string // User settings ignored because they didn't match the schema
+
+	mu          sync.Mutex
+	state       string
+	restarts    int
+	lastError   string
+	startedAt   time.Time
+	nextRestart time.Time
+	stop        chan struct{} // Closed on shutdown to cancel a pending restart
 }
 
 // NewExternalPlugin creates a new external plugin
@@ -19,24 +35,49 @@ func NewExternalPlugin(execPath string, manifest *Manifest, config map[string]in
 		manifest: manifest,
 		config:   config,
 		execPath: execPath,
+		state:    StateStopped,
+		stop:     make(chan struct{}),
 	}
 }
 
-// Init initializes the plugin
+// Init starts the plugin and initializes it
 func (p *ExternalPlugin) Init() error {
-	client, err := NewRPCClient(p.execPath)
-	if err != nil {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.start(); err != nil {
+		p.state = StateFailed
+		p.lastError = err.Error()
 		return err
 	}
-	p.client = client
+	return nil
+}
 
-	// Call init method
-	params := map[string]interface{}{
-		"config": p.config,
+// handshake is the part of the init response proj reads
+type handshake struct {
+	ActionPrefixes []string `json:"actionPrefixes"`
+}
+
+// ownedPrefix returns the longest prefix the plugin declared that actionID
+// starts with, or "" if none
+func (p *ExternalPlugin) ownedPrefix(actionID string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	owned := ""
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(actionID, prefix) && len(prefix) > len(owned) {
+			owned = prefix
+		}
 	}
+	return owned
+}
 
-	_, err = p.client.Call("init", params)
-	return err
+// declaresPrefixes reports whether the plugin declared which actions it owns
+func (p *ExternalPlugin) declaresPrefixes() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.prefixes) > 0
 }
 
 // GetActions gets actions from the plugin
@@ -45,7 +86,7 @@ func (p *ExternalPlugin) GetActions(proj Project) ([]Action, error) {
 		return nil, nil
 	}
 
-	result, err := p.client.Call("actions", proj)
+	result, err := p.call("actions", proj)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +96,17 @@ func (p *ExternalPlugin) GetActions(proj Project) ([]Action, error) {
 		return nil, fmt.Errorf("failed to unmarshal actions: %w", err)
 	}
 
+	// Actions outside the declared prefixes couldn't be routed back here
+	if p.declaresPrefixes() {
+		owned := actions[:0]
+		for _, action := range actions {
+			if p.ownedPrefix(action.ID) != "" {
+				owned = append(owned, action)
+			}
+		}
+		actions = owned
+	}
+
 	return actions, nil
 }
 
@@ -65,7 +117,7 @@ func (p *ExternalPlugin) ExecuteAction(actionID string, proj Project) (*ActionRe
 		"project": proj,
 	}
 
-	result, err := p.client.Call("executeAction", params)
+	result, err := p.call("executeAction", params)
 	if err != nil {
 		return nil, err
 	}
@@ -78,13 +130,33 @@ func (p *ExternalPlugin) ExecuteAction(actionID string, proj Project) (*ActionRe
 	return &actionResult, nil
 }
 
+// GetProjects gets the projects the plugin contributes, e.g. ones on remote
+// machines. Their paths only need to be unique, not to exist locally.
+func (p *ExternalPlugin) GetProjects() ([]Project, error) {
+	if !p.hasCapability("projects") {
+		return nil, nil
+	}
+
+	result, err := p.call("projects", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	if err := json.Unmarshal(result, &projects); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal projects: %w", err)
+	}
+
+	return projects, nil
+}
+
 // GetLanguages gets language detectors from the plugin
 func (p *ExternalPlugin) GetLanguages() ([]LanguageDetector, error) {
 	if !p.hasCapability("languages") {
 		return nil, nil
 	}
 
-	result, err := p.client.Call("languages", nil)
+	result, err := p.call("languages", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -97,17 +169,38 @@ func (p *ExternalPlugin) GetLanguages() ([]LanguageDetector, error) {
 	return languages, nil
 }
 
-// Shutdown shuts down the plugin
+// Shutdown shuts down the plugin and stops supervising it
 func (p *ExternalPlugin) Shutdown() error {
-	if p.client == nil {
+	p.mu.Lock()
+	select {
+	case <-p.stop:
+		p.mu.Unlock()
+		return nil // Already shut down
+	default:
+	}
+	client := p.client
+	p.client = nil
+	p.state = StateStopped
+	close(p.stop)
+	p.mu.Unlock()
+
+	if client == nil {
 		return nil
 	}
 
-	// Call shutdown method (ignore errors)
-	_, _ = p.client.Call("shutdown", nil)
+	// Call shutdown method (ignore errors), without waiting on a hung plugin
+	called := make(chan struct{})
+	go func() {
+		_, _ = client.Call("shutdown", nil)
+		close(called)
+	}()
+	select {
+	case <-called:
+	case <-time.After(shutdownTimeout):
+	}
 
-	// Close client
-	return p.client.Close()
+	// Close client, killing the process if it doesn't exit
+	return client.Close()
 }
 
 // hasCapability checks if the plugin has a capability
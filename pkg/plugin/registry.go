@@ -2,9 +2,11 @@ package plugin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 // Manifest represents a plugin manifest file
@@ -15,6 +17,7 @@ type Manifest struct {
 	Executable   string                 `json:"executable"`
 	Capabilities []string               `json:"capabilities"`
 	Config       map[string]interface{} `json:"config"`
+	ConfigSchema []ConfigField          `json:"configSchema"` // Settings users can change, checked on load
 }
 
 // Registry manages loaded plugins
@@ -70,14 +73,22 @@ func (r *Registry) LoadAll() error {
 			continue
 		}
 
+		// Check the user's settings against the schema
+		config, problems := ResolveConfig(manifest.ConfigSchema, manifest.Config, r.getPluginConfig(pluginName))
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "Warning: plugin %s config: %s\n", pluginName, problem)
+		}
+
 		// Create external plugin
 		execPath := filepath.Join(r.pluginsDir, pluginName, manifest.Executable)
-		plugin := NewExternalPlugin(execPath, manifest, r.getPluginConfig(pluginName))
+		plugin := NewExternalPlugin(execPath, manifest, config)
+		plugin.id = pluginName
+		plugin.configProblems = problems
 
-		// Initialize plugin
+		// Initialize plugin; one that fails stays registered so the plugin
+		// manager can show why and start it again
 		if err := plugin.Init(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to initialize plugin %s: %v\n", pluginName, err)
-			continue
 		}
 
 		r.plugins[pluginName] = plugin
@@ -122,6 +133,11 @@ func (r *Registry) getPluginConfig(name string) map[string]interface{} {
 	return make(map[string]interface{})
 }
 
+// Dir returns the directory plugins are loaded from
+func (r *Registry) Dir() string {
+	return r.pluginsDir
+}
+
 // GetPlugin gets a plugin by name
 func (r *Registry) GetPlugin(name string) *ExternalPlugin {
 	return r.plugins[name]
@@ -136,11 +152,50 @@ func (r *Registry) GetAllPlugins() []*ExternalPlugin {
 	return plugins
 }
 
-// GetActions gets all actions from all plugins for a project
+// GetProjects gets the projects contributed by all plugins, with Source set
+// to the plugin each belongs to
+func (r *Registry) GetProjects() []Project {
+	projects := []Project{}
+
+	for name, plugin := range r.plugins {
+		if !plugin.Running() {
+			continue
+		}
+		pluginProjects, err := plugin.GetProjects()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: plugin %s failed to get projects: %v\n", plugin.manifest.Name, err)
+			continue
+		}
+		for _, proj := range pluginProjects {
+			proj.Source = name
+			projects = append(projects, proj)
+		}
+	}
+
+	return projects
+}
+
+// pluginsFor returns the plugins that handle a project: only the one that
+// contributed it, or all of them for projects on disk
+func (r *Registry) pluginsFor(proj Project) map[string]*ExternalPlugin {
+	if proj.Source == "" {
+		return r.plugins
+	}
+	if plugin, ok := r.plugins[proj.Source]; ok {
+		return map[string]*ExternalPlugin{proj.Source: plugin}
+	}
+	return nil
+}
+
+// GetActions gets all actions from all plugins for a project, ordered by
+// priority
 func (r *Registry) GetActions(proj Project) []Action {
 	actions := []Action{}
 
-	for _, plugin := range r.plugins {
+	for _, plugin := range r.pluginsFor(proj) {
+		if !plugin.Running() {
+			continue
+		}
 		pluginActions, err := plugin.GetActions(proj)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: plugin %s failed to get actions: %v\n", plugin.manifest.Name, err)
@@ -149,13 +204,54 @@ func (r *Registry) GetActions(proj Project) []Action {
 		actions = append(actions, pluginActions...)
 	}
 
+	// Plugins are kept in a map, so order by priority (lowest first) and
+	// then label for a menu that doesn't change between openings
+	sort.SliceStable(actions, func(i, j int) bool {
+		a, b := actions[i], actions[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.ID < b.ID
+	})
 	return actions
 }
 
-// ExecuteAction executes a plugin action
+// ErrUnknownAction is returned by ExecuteAction when no plugin handles an action
+var ErrUnknownAction = errors.New("no plugin handles the action")
+
+// owner returns the plugin whose declared prefix for actionID is longest
+func owner(plugins map[string]*ExternalPlugin, actionID string) *ExternalPlugin {
+	var found *ExternalPlugin
+	longest := ""
+	for _, plugin := range plugins {
+		if prefix := plugin.ownedPrefix(actionID); len(prefix) > len(longest) {
+			found, longest = plugin, prefix
+		}
+	}
+	return found
+}
+
+// ExecuteAction executes a plugin action. An action whose ID starts with a
+// prefix a plugin declared at init goes only to that plugin. Otherwise the
+// plugins that declared no prefixes are tried in turn.
 func (r *Registry) ExecuteAction(actionID string, proj Project) (*ActionResult, error) {
-	// Try each plugin
-	for _, plugin := range r.plugins {
+	plugins := r.pluginsFor(proj)
+	if plugin := owner(plugins, actionID); plugin != nil {
+		result, err := plugin.ExecuteAction(actionID, proj)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s failed to run %s: %w", plugin.manifest.Name, actionID, err)
+		}
+		return result, nil
+	}
+
+	// Try each plugin that didn't say which actions it owns
+	for _, plugin := range plugins {
+		if plugin.declaresPrefixes() || !plugin.Running() {
+			continue
+		}
 		result, err := plugin.ExecuteAction(actionID, proj)
 		if err != nil {
 			continue
@@ -165,7 +261,46 @@ func (r *Registry) ExecuteAction(actionID string, proj Project) (*ActionResult,
 		}
 	}
 
-	return nil, fmt.Errorf("no plugin handled action: %s", actionID)
+	return nil, fmt.Errorf("%w: %s", ErrUnknownAction, actionID)
+}
+
+// Statuses returns the process state of every plugin, sorted by name
+func (r *Registry) Statuses() []Status {
+	statuses := make([]Status, 0, len(r.plugins))
+	for _, plugin := range r.plugins {
+		statuses = append(statuses, plugin.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Restart starts a failed plugin again
+func (r *Registry) Restart(id string) error {
+	plugin, ok := r.plugins[id]
+	if !ok {
+		return fmt.Errorf("plugin not loaded: %s", id)
+	}
+	return plugin.Restart()
+}
+
+// Settings returns the config schema of a plugin and the settings it runs with
+func (r *Registry) Settings(id string) ([]ConfigField, map[string]interface{}, error) {
+	plugin, ok := r.plugins[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("plugin not loaded: %s", id)
+	}
+	return plugin.manifest.ConfigSchema, plugin.Config(), nil
+}
+
+// Reconfigure checks a plugin's new user settings against its schema and
+// restarts it with them, returning the problems ResolveConfig found
+func (r *Registry) Reconfigure(id string, user map[string]interface{}) ([]string, error) {
+	plugin, ok := r.plugins[id]
+	if !ok {
+		return nil, fmt.Errorf("plugin not loaded: %s", id)
+	}
+	config, problems := ResolveConfig(plugin.manifest.ConfigSchema, plugin.manifest.Config, user)
+	return problems, plugin.Reconfigure(config, problems)
 }
 
 // Shutdown shuts down all plugins
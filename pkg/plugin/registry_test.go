@@ -1,8 +1,10 @@
 package plugin
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -124,3 +126,119 @@ func TestRegistryGetPluginConfig(t *testing.T) {
 		t.Error("Expected empty config for non-existent plugin")
 	}
 }
+
+// remotePlugin is a plugin script contributing a project on a remote machine
+const remotePlugin = `#!/bin/sh
+while read -r line; do
+  case "$line" in
+    *'"method":"projects"'*) echo '{"jsonrpc":"2.0","result":[{"name":"api","path":"devbox:/srv/api","language":"Go"}],"id":0}' ;;
+    *'"method":"init"'*) echo '{"jsonrpc":"2.0","result":{"success":true,"actionPrefixes":["remote-"]},"id":0}' ;;
+    *'"method":"actions"'*) echo '{"jsonrpc":"2.0","result":[{"id":"remote-shell","label":"Shell on devbox"},{"id":"stray","label":"Not ours"}],"id":0}' ;;
+    *'"method":"executeAction"'*) echo '{"jsonrpc":"2.0","result":{"success":true,"message":"ran on devbox"},"id":0}' ;;
+    *) echo '{"jsonrpc":"2.0","result":{"success":true},"id":0}' ;;
+  esac
+done
+`
+
+func TestRegistryProjects(t *testing.T) {
+	registry := NewRegistry(writePlugin(t, t.TempDir(), "remote", remotePlugin), t.TempDir(), []string{"remote"}, nil)
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+
+	projects := registry.GetProjects()
+	if len(projects) != 1 || projects[0].Path != "devbox:/srv/api" || projects[0].Source != "remote" {
+		t.Fatalf("GetProjects() = %+v, want the devbox project from remote", projects)
+	}
+
+	if actions := registry.GetActions(projects[0]); len(actions) != 1 || actions[0].ID != "remote-shell" {
+		t.Errorf("GetActions(remote project) = %+v", actions)
+	}
+	result, err := registry.ExecuteAction("remote-shell", projects[0])
+	if err != nil || result.Message != "ran on devbox" {
+		t.Errorf("ExecuteAction(remote project) = %+v, %v", result, err)
+	}
+
+	// Projects of plugins that aren't loaded go nowhere
+	orphan := Project{Name: "api", Path: "cloud:/api", Source: "cloud"}
+	if actions := registry.GetActions(orphan); len(actions) != 0 {
+		t.Errorf("Expected no actions for an unknown source, got %+v", actions)
+	}
+	if _, err := registry.ExecuteAction("remote-shell", orphan); err == nil {
+		t.Error("Expected an error for an unknown source")
+	}
+}
+
+// legacyPlugin declares no action prefixes and handles whatever it's sent
+const legacyPlugin = `#!/bin/sh
+while read -r line; do
+  case "$line" in
+    *'"method":"executeAction"'*) echo '{"jsonrpc":"2.0","result":{"success":true,"message":"legacy"},"id":0}' ;;
+    *) echo '{"jsonrpc":"2.0","result":{"success":true},"id":0}' ;;
+  esac
+done
+`
+
+func TestRegistryRoutesByPrefix(t *testing.T) {
+	pluginsDir := writePlugin(t, t.TempDir(), "remote", remotePlugin)
+	registry := NewRegistry(pluginsDir, t.TempDir(), []string{"remote"}, nil)
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+	onDisk := Project{Name: "web", Path: "/src/web"}
+
+	if result, err := registry.ExecuteAction("remote-shell", onDisk); err != nil || result.Message != "ran on devbox" {
+		t.Errorf("ExecuteAction(remote-shell) = %+v, %v", result, err)
+	}
+	// Built-in actions never reach a plugin that declared its prefixes
+	if _, err := registry.ExecuteAction("git-pull", onDisk); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("ExecuteAction(git-pull) error = %v, want ErrUnknownAction", err)
+	}
+	// Actions outside the declared prefixes are dropped
+	if actions := registry.GetActions(onDisk); len(actions) != 1 || actions[0].ID != "remote-shell" {
+		t.Errorf("GetActions() = %+v, want only remote-shell", actions)
+	}
+
+	// Plugins without prefixes still get the actions nobody owns
+	writePlugin(t, pluginsDir, "legacy", legacyPlugin)
+	registry = NewRegistry(pluginsDir, t.TempDir(), []string{"remote", "legacy"}, nil)
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+	if result, err := registry.ExecuteAction("remote-shell", onDisk); err != nil || result.Message != "ran on devbox" {
+		t.Errorf("ExecuteAction(remote-shell) with a legacy plugin = %+v, %v", result, err)
+	}
+	if result, err := registry.ExecuteAction("git-pull", onDisk); err != nil || result.Message != "legacy" {
+		t.Errorf("ExecuteAction(git-pull) with a legacy plugin = %+v, %v", result, err)
+	}
+}
+
+// rankedPlugin lists its actions out of priority order
+const rankedPlugin = `#!/bin/sh
+while read -r line; do
+  case "$line" in
+    *'"method":"actions"'*) echo '{"jsonrpc":"2.0","result":[{"id":"c","label":"Cleanup","priority":20},{"id":"b","label":"Build","priority":20},{"id":"a","label":"Deploy","priority":5}],"id":0}' ;;
+    *) echo '{"jsonrpc":"2.0","result":{"success":true},"id":0}' ;;
+  esac
+done
+`
+
+func TestRegistryOrdersActionsByPriority(t *testing.T) {
+	registry := NewRegistry(writePlugin(t, t.TempDir(), "ranked", rankedPlugin), t.TempDir(), []string{"ranked"}, nil)
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+
+	var ids []string
+	for _, a := range registry.GetActions(Project{Name: "web", Path: "/src/web"}) {
+		ids = append(ids, a.ID)
+	}
+	// Lowest priority first, ties by label
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Errorf("GetActions() order = %s, want a,b,c", got)
+	}
+}
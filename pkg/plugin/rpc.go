@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"sync"
+	"time"
 )
 
+// closeTimeout is how long Close waits for a plugin to exit before killing it
+const closeTimeout = 2 * time.Second
+
 // RPCRequest represents a JSON-RPC 2.0 request
 type RPCRequest struct {
 	JSONRPC string      `json:"jsonrpc"`
@@ -41,6 +46,12 @@ type RPCClient struct {
 	mu     sync.Mutex
 	nextID int
 	reader *bufio.Reader
+	lines  chan []byte   // Lines read from stdout; closed at its end
+	eof    error         // Why stdout ended, set before lines is closed
+	quit   chan struct{} // Closed by Close, so unread lines are dropped
+	once   sync.Once     // Closes quit
+	done   chan struct{} // Closed once the process has exited and been reaped
+	exit   error         // Result of waiting for the process, set before done is closed
 }
 
 // NewRPCClient creates a new RPC client for a plugin
@@ -72,20 +83,73 @@ func NewRPCClient(execPath string) (*RPCClient, error) {
 		stdout: stdout,
 		stderr: stderr,
 		reader: bufio.NewReader(stdout),
+		lines:  make(chan []byte),
+		quit:   make(chan struct{}),
 		nextID: 1,
+		done:   make(chan struct{}),
 	}
 
-	// Start stderr reader
-	go client.readStderr()
+	// Wait closes the pipes, so it's only called once both have been read
+	// to the end; that also keeps the last stderr lines of a crashing plugin
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		client.readStdout()
+	}()
+	go func() {
+		defer readers.Done()
+		client.readStderr()
+	}()
+
+	// Reap the process as soon as it exits so it can't linger as a zombie
+	go func() {
+		readers.Wait()
+		client.exit = cmd.Wait()
+		close(client.done)
+	}()
 
 	return client, nil
 }
 
-// readStderr reads and logs stderr from the plugin
+// Done is closed once the plugin process has exited
+func (c *RPCClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// ExitErr returns how the plugin process exited; only valid after Done
+func (c *RPCClient) ExitErr() error {
+	return c.exit
+}
+
+// PID returns the plugin's process ID
+func (c *RPCClient) PID() int {
+	return c.cmd.Process.Pid
+}
+
+// readStdout passes the plugin's responses to Call, one per line, until
+// stdout ends
+func (c *RPCClient) readStdout() {
+	defer close(c.lines)
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			c.eof = err
+			return
+		}
+		select {
+		case c.lines <- line:
+		case <-c.quit:
+		}
+	}
+}
+
+// readStderr copies the plugin's stderr to proj's, where it doesn't get in
+// the way of the TUI on stdout
 func (c *RPCClient) readStderr() {
 	scanner := bufio.NewScanner(c.stderr)
 	for scanner.Scan() {
-		fmt.Printf("[plugin stderr] %s\n", scanner.Text())
+		fmt.Fprintf(os.Stderr, "[plugin stderr] %s\n", scanner.Text())
 	}
 }
 
@@ -109,21 +173,19 @@ func (c *RPCClient) Call(method string, params interface{}) (json.RawMessage, er
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	// Hold the lock until the response arrives so concurrent calls can't
+	// read each other's responses
 	c.mu.Lock()
-	_, err = c.stdin.Write(append(requestData, '\n'))
-	c.mu.Unlock()
+	defer c.mu.Unlock()
 
-	if err != nil {
+	if _, err := c.stdin.Write(append(requestData, '\n')); err != nil {
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
 
 	// Read response
-	c.mu.Lock()
-	line, err := c.reader.ReadBytes('\n')
-	c.mu.Unlock()
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	line, ok := <-c.lines
+	if !ok {
+		return nil, fmt.Errorf("failed to read response: %w", c.eof)
 	}
 
 	var response RPCResponse
@@ -138,10 +200,38 @@ func (c *RPCClient) Call(method string, params interface{}) (json.RawMessage, er
 	return response.Result, nil
 }
 
-// Close closes the RPC client and terminates the plugin
+// CallTimeout is Call giving up after timeout, when it closes the client so
+// the hung plugin is killed
+func (c *RPCClient) CallTimeout(method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+	type reply struct {
+		result json.RawMessage
+		err    error
+	}
+	replied := make(chan reply, 1)
+	go func() {
+		result, err := c.Call(method, params)
+		replied <- reply{result, err}
+	}()
+
+	select {
+	case r := <-replied:
+		return r.result, r.err
+	case <-time.After(timeout):
+		_ = c.Close()
+		return nil, fmt.Errorf("no response to %s within %s", method, timeout)
+	}
+}
+
+// Close closes the plugin's stdin and waits for it to exit, killing it if
+// it hasn't within closeTimeout. It can be called more than once.
 func (c *RPCClient) Close() error {
+	c.once.Do(func() { close(c.quit) })
 	_ = c.stdin.Close()
-	_ = c.stdout.Close()
-	_ = c.stderr.Close()
-	return c.cmd.Wait()
+	select {
+	case <-c.done:
+	case <-time.After(closeTimeout):
+		_ = c.cmd.Process.Kill()
+		<-c.done
+	}
+	return c.exit
 }
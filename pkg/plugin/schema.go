@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Value types a manifest's configSchema can declare
+const (
+	ConfigString = "string"
+	ConfigNumber = "number"
+	ConfigBool   = "bool"
+)
+
+// ConfigField is a setting declared in a manifest's configSchema
+type ConfigField struct {
+	Key         string      `json:"key"`
+	Type        string      `json:"type"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+}
+
+// Check reports whether value has the field's type
+func (f ConfigField) Check(value interface{}) error {
+	ok := false
+	switch f.Type {
+	case ConfigString:
+		_, ok = value.(string)
+	case ConfigNumber:
+		switch value.(type) {
+		case float64, float32, int, int64:
+			ok = true
+		}
+	case ConfigBool:
+		_, ok = value.(bool)
+	default:
+		return fmt.Errorf("%s has unknown type %q", f.Key, f.Type)
+	}
+	if !ok {
+		return fmt.Errorf("%s should be a %s, got %v", f.Key, f.Type, value)
+	}
+	return nil
+}
+
+// Parse converts text typed into the settings form to the field's type
+func (f ConfigField) Parse(text string) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	switch f.Type {
+	case ConfigNumber:
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s should be a number", f.Key)
+		}
+		return n, nil
+	case ConfigBool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s should be true or false", f.Key)
+		}
+		return b, nil
+	}
+	return text, nil
+}
+
+// Format renders a value for the settings form
+func (f ConfigField) Format(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if n, ok := value.(float64); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// lookup finds key in settings ignoring case, since the config loader
+// lowercases keys
+func lookup(settings map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := settings[key]; ok {
+		return v, true
+	}
+	for k, v := range settings {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveConfig works out the settings to initialize a plugin with. Without
+// a schema the user's settings are passed through. With one, each declared
+// key gets the user's value if it has the right type, otherwise the
+// manifest's default; problems lists the values that were ignored.
+func ResolveConfig(schema []ConfigField, defaults, user map[string]interface{}) (resolved map[string]interface{}, problems []string) {
+	if len(schema) == 0 {
+		if user == nil {
+			user = make(map[string]interface{})
+		}
+		return user, nil
+	}
+
+	resolved = make(map[string]interface{})
+	declared := make(map[string]bool)
+	for _, field := range schema {
+		declared[strings.ToLower(field.Key)] = true
+
+		value := field.Default
+		if v, ok := lookup(defaults, field.Key); ok {
+			value = v
+		}
+		if v, ok := lookup(user, field.Key); ok {
+			if err := field.Check(v); err != nil {
+				problems = append(problems, err.Error()+"; using the default")
+			} else {
+				value = v
+			}
+		}
+		resolved[field.Key] = value
+	}
+
+	for key := range user {
+		if !declared[strings.ToLower(key)] {
+			problems = append(problems, fmt.Sprintf("%s is not a setting of this plugin", key))
+		}
+	}
+	sort.Strings(problems)
+	return resolved, problems
+}
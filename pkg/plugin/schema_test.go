@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var testSchema = []ConfigField{
+	{Key: "apiToken", Type: ConfigString, Description: "Token for the API"},
+	{Key: "timeout", Type: ConfigNumber, Default: 30.0},
+	{Key: "verbose", Type: ConfigBool, Default: false},
+}
+
+func TestResolveConfig(t *testing.T) {
+	// Keys come back lowercased from the config loader
+	user := map[string]interface{}{"apitoken": "abc", "timeout": "soon", "colour": "red"}
+	defaults := map[string]interface{}{"verbose": true}
+
+	got, problems := ResolveConfig(testSchema, defaults, user)
+	want := map[string]interface{}{"apiToken": "abc", "timeout": 30.0, "verbose": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveConfig() = %v, want %v", got, want)
+	}
+	wantProblems := []string{
+		"colour is not a setting of this plugin",
+		"timeout should be a number, got soon; using the default",
+	}
+	if !reflect.DeepEqual(problems, wantProblems) {
+		t.Errorf("problems = %q, want %q", problems, wantProblems)
+	}
+
+	// Without a schema the user's settings pass through unchecked
+	if got, problems := ResolveConfig(nil, defaults, user); !reflect.DeepEqual(got, user) || problems != nil {
+		t.Errorf("ResolveConfig(no schema) = %v, %q", got, problems)
+	}
+}
+
+func TestConfigFieldParse(t *testing.T) {
+	tests := []struct {
+		field ConfigField
+		text  string
+		want  interface{}
+		ok    bool
+	}{
+		{testSchema[0], " abc ", "abc", true},
+		{testSchema[1], "12.5", 12.5, true},
+		{testSchema[1], "soon", nil, false},
+		{testSchema[2], "true", true, true},
+		{testSchema[2], "yes", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.Parse(tt.text)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%s.Parse(%q) = %v, %v", tt.field.Key, tt.text, got, err)
+		}
+	}
+
+	if got := testSchema[1].Format(30.0); got != "30" {
+		t.Errorf("Format(30.0) = %q, want 30", got)
+	}
+}
+
+func TestRegistryReconfigure(t *testing.T) {
+	pluginsDir := writePlugin(t, t.TempDir(), "remote", remotePlugin)
+	os.WriteFile(filepath.Join(pluginsDir, "remote", "plugin.json"), []byte(`{
+		"name": "remote", "executable": "plugin.sh", "capabilities": ["actions"],
+		"configSchema": [{"key": "host", "type": "string", "default": "devbox"}]
+	}`), 0644)
+
+	registry := NewRegistry(pluginsDir, t.TempDir(), []string{"remote"}, map[string]interface{}{
+		"remote": map[string]interface{}{"host": 42},
+	})
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+
+	status := registry.Statuses()[0]
+	if status.ID != "remote" || len(status.ConfigProblems) != 1 {
+		t.Errorf("Expected a config problem for the mistyped host, got %+v", status)
+	}
+	schema, settings, err := registry.Settings("remote")
+	if err != nil || len(schema) != 1 || settings["host"] != "devbox" {
+		t.Errorf("Settings() = %v, %v, %v", schema, settings, err)
+	}
+
+	problems, err := registry.Reconfigure("remote", map[string]interface{}{"host": "cloudbox"})
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("Reconfigure failed: %v %q", err, problems)
+	}
+	after := registry.Statuses()[0]
+	if after.State != StateRunning || after.PID == status.PID || len(after.ConfigProblems) != 0 {
+		t.Errorf("Expected a restarted plugin without problems, got %+v", after)
+	}
+	if _, settings, _ := registry.Settings("remote"); settings["host"] != "cloudbox" {
+		t.Errorf("Settings() after Reconfigure = %v", settings)
+	}
+}
@@ -0,0 +1,236 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Plugin process states, as shown in the plugin manager
+const (
+	StateRunning    = "running"
+	StateRestarting = "restarting" // Crashed; waiting out the backoff before starting again
+	StateFailed     = "failed"     // Couldn't be started, or crashed MaxRestarts times in a row
+	StateStopped    = "stopped"
+)
+
+// MaxRestarts is how many times in a row a crashed plugin is restarted
+// before it's left failed
+const MaxRestarts = 5
+
+// Restart backoff, doubling from initialBackoff up to maxBackoff. A plugin
+// that stays up for stableAfter has its restart count reset. Variables so
+// tests can shorten them.
+var (
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+	stableAfter    = time.Minute
+)
+
+// shutdownTimeout bounds how long a plugin gets to answer the shutdown call
+const shutdownTimeout = time.Second
+
+// initTimeout bounds how long a plugin gets to answer init. start holds the
+// plugin's lock meanwhile, so a hung plugin mustn't keep it. A variable so
+// tests can shorten it.
+var initTimeout = 10 * time.Second
+
+// Status describes a plugin's process for the plugin manager
+type Status struct {
+	ID             string // Directory the plugin was loaded from
+	Name           string
+	Version        string
+	State          string
+	PID            int       // Zero unless running
+	Restarts       int       // Restarts since the plugin last ran stably
+	LastError      string    // Why the plugin last failed to start or exited
+	StartedAt      time.Time // When the running process started
+	NextRestart    time.Time // When a restarting plugin will be started again
+	ConfigProblems []string  // User settings ignored because they didn't match the schema
+}
+
+// backoff returns the delay before the given restart (0 for the first)
+func backoff(restarts int) time.Duration {
+	delay := initialBackoff
+	for i := 0; i < restarts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// start launches the plugin process and sends it init. The caller holds p.mu.
+func (p *ExternalPlugin) start() error {
+	client, err := NewRPCClient(p.execPath)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"config": p.config,
+	}
+	result, err := client.CallTimeout("init", params, initTimeout)
+	if err != nil {
+		_ = client.Close()
+		return fmt.Errorf("init failed: %w", err)
+	}
+
+	// Older plugins reply with anything; they just don't declare prefixes
+	var hs handshake
+	_ = json.Unmarshal(result, &hs)
+
+	p.client = client
+	p.prefixes = hs.ActionPrefixes
+	p.state = StateRunning
+	p.startedAt = time.Now()
+	go p.supervise(client)
+	return nil
+}
+
+// supervise waits for client's process to exit and, unless the plugin is
+// being shut down, restarts it with backoff
+func (p *ExternalPlugin) supervise(client *RPCClient) {
+	<-client.Done()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client != client || p.state == StateStopped {
+		return
+	}
+	p.client = nil
+	p.lastError = "exited"
+	if err := client.ExitErr(); err != nil {
+		p.lastError = "exited: " + err.Error()
+	}
+	if time.Since(p.startedAt) >= stableAfter {
+		p.restarts = 0
+	}
+
+	for p.restarts < MaxRestarts {
+		delay := backoff(p.restarts)
+		p.restarts++
+		p.state = StateRestarting
+		p.nextRestart = time.Now().Add(delay)
+
+		p.mu.Unlock()
+		select {
+		case <-time.After(delay):
+		case <-p.stop:
+		}
+		p.mu.Lock()
+		if p.state != StateRestarting {
+			// Shut down, or started again by Reconfigure meanwhile
+			return
+		}
+
+		err := p.start()
+		if err == nil {
+			return
+		}
+		p.lastError = err.Error()
+	}
+	p.state = StateFailed
+}
+
+// Restart starts a failed plugin again, resetting its restart count
+func (p *ExternalPlugin) Restart() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state != StateFailed {
+		return fmt.Errorf("plugin %s is %s", p.manifest.Name, p.state)
+	}
+
+	p.restarts = 0
+	if err := p.start(); err != nil {
+		p.lastError = err.Error()
+		return err
+	}
+	return nil
+}
+
+// Reconfigure restarts the plugin with new settings. A stopped plugin just
+// keeps them for the next start.
+func (p *ExternalPlugin) Reconfigure(config map[string]interface{}, problems []string) error {
+	p.mu.Lock()
+	p.config = config
+	p.configProblems = problems
+	if p.state == StateStopped {
+		p.mu.Unlock()
+		return nil
+	}
+
+	// Detach the running process first so supervise doesn't restart it, and
+	// cancel a pending restart
+	client := p.client
+	p.client = nil
+	p.state = StateStopped
+	p.mu.Unlock()
+	if client != nil {
+		_ = client.Close()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.stop:
+		return nil // Shut down meanwhile
+	default:
+	}
+	p.restarts = 0
+	if err := p.start(); err != nil {
+		p.state = StateFailed
+		p.lastError = err.Error()
+		return err
+	}
+	return nil
+}
+
+// Config returns the settings the plugin was initialized with
+func (p *ExternalPlugin) Config() map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.config
+}
+
+// Running reports whether the plugin's process is up
+func (p *ExternalPlugin) Running() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state == StateRunning
+}
+
+// Status returns the plugin's process state
+func (p *ExternalPlugin) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := Status{
+		ID:             p.id,
+		Name:           p.manifest.Name,
+		Version:        p.manifest.Version,
+		State:          p.state,
+		Restarts:       p.restarts,
+		LastError:      p.lastError,
+		ConfigProblems: p.configProblems,
+	}
+	switch {
+	case p.state == StateRunning && p.client != nil:
+		status.PID = p.client.PID()
+		status.StartedAt = p.startedAt
+	case p.state == StateRestarting:
+		status.NextRestart = p.nextRestart
+	}
+	return status
+}
+
+// call makes an RPC call if the plugin is running
+func (p *ExternalPlugin) call(method string, params interface{}) ([]byte, error) {
+	p.mu.Lock()
+	client := p.client
+	state := p.state
+	p.mu.Unlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("plugin %s is %s", p.manifest.Name, state)
+	}
+	return client.Call(method, params)
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// crashingPlugin answers init and then exits with an error
+const crashingPlugin = `#!/bin/sh
+read -r line
+echo '{"jsonrpc":"2.0","result":{"success":true},"id":0}'
+sleep 0.05
+exit 3
+`
+
+// writePlugin writes a plugin script and its manifest into pluginsDir,
+// returning pluginsDir
+func writePlugin(t *testing.T, pluginsDir, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin script needs sh")
+	}
+	dir := filepath.Join(pluginsDir, name)
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"name":"`+name+`","version":"1.0.0","executable":"plugin.sh","capabilities":["projects","actions"]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "plugin.sh"), []byte(script), 0755)
+	return pluginsDir
+}
+
+// waitForState polls the plugin's status until it reaches state
+func waitForState(t *testing.T, p *ExternalPlugin, state string) Status {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if status := p.Status(); status.State == state {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("plugin never became %s, last status %+v", state, p.Status())
+	return Status{}
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for restarts, w := range want {
+		if got := backoff(restarts); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", restarts, got, w)
+		}
+	}
+}
+
+func TestSuperviseRestartsCrashedPlugin(t *testing.T) {
+	defer func(initial, max time.Duration) { initialBackoff, maxBackoff = initial, max }(initialBackoff, maxBackoff)
+	initialBackoff, maxBackoff = time.Millisecond, 5*time.Millisecond
+
+	registry := NewRegistry(writePlugin(t, t.TempDir(), "crashy", crashingPlugin), t.TempDir(), []string{"crashy"}, nil)
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+
+	p := registry.GetPlugin("crashy")
+	status := waitForState(t, p, StateFailed)
+	if status.Restarts != MaxRestarts || status.LastError == "" {
+		t.Errorf("Expected %d restarts and an error, got %+v", MaxRestarts, status)
+	}
+	if actions := registry.GetActions(Project{Name: "api"}); len(actions) != 0 {
+		t.Errorf("Expected no actions from a failed plugin, got %+v", actions)
+	}
+
+	// Restarting by hand resets the count
+	if err := registry.Restart("crashy"); err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	if status := p.Status(); status.Restarts != 0 {
+		t.Errorf("Expected the restart count reset, got %+v", status)
+	}
+	if err := registry.Restart("missing"); err == nil {
+		t.Error("Expected an error restarting an unknown plugin")
+	}
+}
+
+func TestShutdownStopsPlugin(t *testing.T) {
+	registry := NewRegistry(writePlugin(t, t.TempDir(), "remote", remotePlugin), t.TempDir(), []string{"remote"}, nil)
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+
+	p := registry.GetPlugin("remote")
+	status := p.Status()
+	if status.State != StateRunning || status.PID == 0 {
+		t.Fatalf("Expected a running plugin, got %+v", status)
+	}
+	client := p.client
+
+	registry.Shutdown()
+	select {
+	case <-client.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("plugin process was not reaped")
+	}
+	if statuses := registry.Statuses(); len(statuses) != 1 || statuses[0].State != StateStopped {
+		t.Errorf("Statuses() after shutdown = %+v", statuses)
+	}
+
+	// A second shutdown is harmless
+	registry.Shutdown()
+}
+
+func TestInitTimesOut(t *testing.T) {
+	defer func(timeout time.Duration) { initTimeout = timeout }(initTimeout)
+	initTimeout = 50 * time.Millisecond
+
+	// Reads init and never answers
+	registry := NewRegistry(writePlugin(t, t.TempDir(), "hung", "#!/bin/sh\nread -r line\nexec sleep 10\n"), t.TempDir(), []string{"hung"}, nil)
+	begin := time.Now()
+	if err := registry.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	defer registry.Shutdown()
+
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Errorf("LoadAll took %v waiting on a hung plugin", elapsed)
+	}
+	if status := registry.GetPlugin("hung").Status(); status.State != StateFailed || status.LastError == "" {
+		t.Errorf("Expected the plugin failed with an error, got %+v", status)
+	}
+}
@@ -15,6 +15,7 @@ type Plugin interface {
 	Actions(project Project) ([]Action, error)
 	Languages() ([]LanguageDetector, error)
 	OnAction(action string, project Project) (*ActionResult, error)
+	Projects() ([]Project, error)
 }
 
 // PluginConfig holds configuration for a plugin
@@ -31,6 +32,23 @@ type Project struct {
 	GitBranch string
 	GitDirty  bool
 	IsGitRepo bool
+	Source    string // Plugin that contributed the project; empty for projects on disk
+
+	// Context proj already knows about the project, so plugins don't have to
+	// scan it again
+	Tags            []string
+	IsFavorite      bool     // Tagged "favorite"
+	DetectedScripts []Script // Runnable scripts found in package.json, Makefile, ...
+	HasDockerfile   bool
+	Framework       string            // Reserved; proj doesn't detect frameworks yet
+	Metadata        map[string]string // Further details, e.g. "devEnv" or "usesLFS"
+}
+
+// Script is a runnable script proj detected in a project
+type Script struct {
+	Name    string
+	Command string
+	Source  string // File the script was found in, e.g. "package.json"
 }
 
 // Action represents a custom action
@@ -0,0 +1,271 @@
+// Package proj embeds proj's project discovery and actions in other Go
+// programs, such as editor plugins and chat bots, without its TUI:
+//
+//	client, err := proj.New(proj.Options{})
+//	projects, err := client.Scan()
+//	actions, err := client.Actions(projects[0])
+//	result, err := client.Execute(projects[0], "git-log")
+//
+// The types here are its stable API. They copy what they need from proj's
+// internal packages, which change freely between releases.
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/s33g/proj/internal/actions"
+	"github.com/s33g/proj/internal/config"
+	"github.com/s33g/proj/internal/hooks"
+	"github.com/s33g/proj/internal/project"
+	"github.com/s33g/proj/internal/state"
+	"github.com/s33g/proj/internal/tui/views"
+)
+
+// Options configure a Client
+type Options struct {
+	// ReposPath is scanned instead of the configured reposPath
+	ReposPath string
+	// IgnoreConfig uses proj's default settings rather than the user's
+	// config file, for tools that shouldn't depend on how proj is set up
+	IgnoreConfig bool
+	// AllowProtectedBranches runs actions that would change a protected
+	// branch, which the CLI and TUI ask about first, instead of failing
+	// with ErrProtectedBranch
+	AllowProtectedBranches bool
+}
+
+// Project is a directory proj found
+type Project struct {
+	Name         string
+	Path         string
+	Group        string // Path of the group holding it, or "" at the top level
+	IsGroup      bool   // A folder of projects rather than a project
+	Language     string
+	IsGitRepo    bool
+	GitBranch    string
+	GitDirty     bool
+	HasDocker    bool // Has a Dockerfile or a Compose file
+	Tags         []string
+	Aliases      []string
+	LastModified time.Time
+}
+
+// Action is something proj can do to a project
+type Action struct {
+	ID          string
+	Label       string
+	Description string
+	Command     string   // Shell command a script action runs, if any
+	Source      string   // Where a script action was found, such as package.json
+	Children    []Action // The actions of a submenu, which can't be executed itself
+}
+
+// Result is the outcome of an action
+type Result struct {
+	Success  bool
+	Output   string
+	ExitCode int      // Exit code of the command the action ran, if it ran one
+	Dir      string   // Directory the action asks its caller to change to
+	Exec     []string // Command the action asks its caller to hand the terminal to
+}
+
+// Errors Execute fails with, wrapped with the details
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrActionNotFound  = errors.New("action not available")
+	ErrActionDisabled  = errors.New("action disabled by actions.disabled in the config")
+	ErrProtectedBranch = errors.New("action would change a protected branch")
+)
+
+// Client finds projects and runs their actions. It's safe for concurrent
+// use.
+type Client struct {
+	cfg       *config.Config
+	reposPath string
+	opts      Options
+
+	mu       sync.Mutex
+	projects []*project.Project
+}
+
+// New returns a Client with the user's config, or the defaults when
+// opts.IgnoreConfig is set. Nothing is scanned until Scan is called.
+func New(opts Options) (*Client, error) {
+	cfg := config.DefaultConfig()
+	if !opts.IgnoreConfig {
+		loaded, err := config.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		cfg = loaded
+	}
+	reposPath := cfg.ReposPath
+	if opts.ReposPath != "" {
+		reposPath = opts.ReposPath
+	}
+	return &Client{cfg: cfg, reposPath: config.ExpandPath(reposPath), opts: opts}, nil
+}
+
+// Scan finds the projects, replacing those found by the last scan
+func (c *Client) Scan() ([]Project, error) {
+	projects, err := project.NewScanner(c.cfg).Scan(c.reposPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan projects: %w", err)
+	}
+	if !c.opts.IgnoreConfig {
+		if st, err := state.Load(); err == nil {
+			st.Annotate(projects)
+		}
+	}
+
+	c.mu.Lock()
+	c.projects = projects
+	c.mu.Unlock()
+	return c.Projects(), nil
+}
+
+// Projects returns the projects found by the last scan, groups followed by
+// their children
+func (c *Client) Projects() []Project {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Project, len(c.projects))
+	for i, p := range c.projects {
+		out[i] = newProject(p)
+	}
+	return out
+}
+
+// Find returns the project of the last scan called name, or one of its
+// aliases
+func (c *Client) Find(name string) (Project, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p := project.Find(c.projects, name); p != nil {
+		return newProject(p), true
+	}
+	return Project{}, false
+}
+
+// Actions returns the actions available for p, as listed in its menu
+func (c *Client) Actions(p Project) ([]Action, error) {
+	proj, err := c.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.newActions(c.defaultActions(proj), proj), nil
+}
+
+// Execute runs the action id on p and waits for it to finish. The action's
+// own failure is reported in the Result; the error is for an action that
+// couldn't be run at all.
+func (c *Client) Execute(p Project, id string) (Result, error) {
+	proj, err := c.lookup(p)
+	if err != nil {
+		return Result{}, err
+	}
+	action := views.FindAction(c.defaultActions(proj), id)
+	if action == nil || action.IsSubmenu {
+		return Result{}, fmt.Errorf("%w for %s: %s", ErrActionNotFound, proj.Name, id)
+	}
+	if c.cfg.Actions.IsDisabled(proj.Name, action.ID) {
+		return Result{}, fmt.Errorf("%w: %s for %s", ErrActionDisabled, action.ID, proj.Name)
+	}
+
+	executor := actions.NewExecutor(c.cfg)
+	if warning := executor.ProtectedBranchWarning(action.Command, proj); warning != "" && !c.opts.AllowProtectedBranches {
+		return Result{}, fmt.Errorf("%w: %s in %s %s", ErrProtectedBranch, action.Label, proj.Name, warning)
+	}
+	if err := hooks.Run(c.cfg, hooks.PreAction, proj, "PROJ_ACTION="+action.ID); err != nil {
+		return Result{}, err
+	}
+
+	var result actions.Result
+	if action.Command != "" {
+		result = executor.ExecuteCommand(action.Command, proj)
+	} else {
+		result = executor.Execute(action.ID, proj)
+	}
+	return Result{
+		Success:  result.Success,
+		Output:   result.Message,
+		ExitCode: result.ExitCode,
+		Dir:      result.CdPath,
+		Exec:     result.ExecCmd,
+	}, nil
+}
+
+// lookup returns the scanned project at p's path, or scans it when the
+// last scan didn't find it, such as one outside reposPath
+func (c *Client) lookup(p Project) (*project.Project, error) {
+	path := filepath.Clean(p.Path)
+	c.mu.Lock()
+	for _, proj := range c.projects {
+		if proj.Path == path {
+			c.mu.Unlock()
+			if proj.IsGroup {
+				return nil, fmt.Errorf("%w: %s is a group", ErrProjectNotFound, proj.Name)
+			}
+			return proj, nil
+		}
+	}
+	c.mu.Unlock()
+
+	proj, err := project.NewScanner(c.cfg).ScanProject(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrProjectNotFound, p.Path, err)
+	}
+	return proj, nil
+}
+
+// defaultActions returns the actions of proj's menu
+func (c *Client) defaultActions(proj *project.Project) []views.Action {
+	return views.DefaultActions(proj, c.cfg.Actions.EnableGitOperations, c.cfg.Actions.EnableTestRunner)
+}
+
+// newProject copies what the API shows of p
+func newProject(p *project.Project) Project {
+	return Project{
+		Name:         p.Name,
+		Path:         p.Path,
+		Group:        p.ParentPath,
+		IsGroup:      p.IsGroup,
+		Language:     p.Language,
+		IsGitRepo:    p.IsGitRepo,
+		GitBranch:    p.GitBranch,
+		GitDirty:     p.GitDirty,
+		HasDocker:    p.HasDockerfile || p.HasCompose,
+		Tags:         append([]string(nil), p.Tags...),
+		Aliases:      append([]string(nil), p.Aliases...),
+		LastModified: p.LastModified,
+	}
+}
+
+// newActions copies the menu's actions, leaving out its back entry, the
+// actions disabled for proj and submenus left empty by them
+func (c *Client) newActions(menu []views.Action, proj *project.Project) []Action {
+	var out []Action
+	for _, a := range menu {
+		if a.ID == "back" || c.cfg.Actions.IsDisabled(proj.Name, a.ID) {
+			continue
+		}
+		action := Action{
+			ID:          a.ID,
+			Label:       a.Label,
+			Description: a.Desc,
+			Command:     a.Command,
+			Source:      a.Source,
+		}
+		if a.IsSubmenu {
+			if action.Children = c.newActions(a.Children, proj); len(action.Children) == 0 {
+				continue
+			}
+		}
+		out = append(out, action)
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package proj
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "app")
+	if err := os.MkdirAll(app, 0755); err != nil {
+		t.Fatal(err)
+	}
+	makefile := "hello:\n\t@echo hello from make\n"
+	if err := os.WriteFile(filepath.Join(app, "Makefile"), []byte(makefile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := New(Options{ReposPath: root, IgnoreConfig: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if len(client.Projects()) != 0 {
+		t.Error("Expected no projects before scanning")
+	}
+	projects, err := client.Scan()
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Name != "app" {
+		t.Fatalf("Scan() = %+v, want app", projects)
+	}
+	p, ok := client.Find("app")
+	if !ok || p.Path != app {
+		t.Fatalf("Find(app) = %+v, %v", p, ok)
+	}
+
+	list, err := client.Actions(p)
+	if err != nil {
+		t.Fatalf("Actions failed: %v", err)
+	}
+	hello := findAction(list, func(a Action) bool { return a.Source == "Makefile" && strings.Contains(a.Command, "hello") })
+	if hello == nil {
+		t.Fatalf("Expected the Makefile's hello target among %+v", list)
+	}
+	if findAction(list, func(a Action) bool { return a.ID == "back" }) != nil {
+		t.Error("Expected the menu's back entry to be left out")
+	}
+
+	result, err := client.Execute(p, hello.ID)
+	if err != nil || !result.Success || !strings.Contains(result.Output, "hello from make") {
+		t.Errorf("Execute(%s) = %+v, %v", hello.ID, result, err)
+	}
+	if _, err := client.Execute(p, "no-such-action"); !errors.Is(err, ErrActionNotFound) {
+		t.Errorf("Execute(no-such-action) error = %v, want ErrActionNotFound", err)
+	}
+	if _, err := client.Actions(Project{Path: filepath.Join(root, "missing")}); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Actions of a missing project error = %v, want ErrProjectNotFound", err)
+	}
+}
+
+func findAction(actions []Action, match func(Action) bool) *Action {
+	for i := range actions {
+		if match(actions[i]) {
+			return &actions[i]
+		}
+		if found := findAction(actions[i].Children, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -98,7 +98,11 @@ func (p *ExamplePlugin) handleRequest(req *RPCRequest) *RPCResponse {
 			return resp
 		}
 		p.config = params.Config
-		resp.Result = map[string]bool{"success": true}
+		// Declare which action IDs are ours so proj only routes those here
+		resp.Result = map[string]interface{}{
+			"success":        true,
+			"actionPrefixes": []string{"example-"},
+		}
 
 	case "actions":
 		var proj Project